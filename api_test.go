@@ -0,0 +1,258 @@
+package idc
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// The exported API of every package of the module, one symbol per line
+const apiGolden = "testdata/api.golden"
+
+var update = flag.Bool("update", false, "update "+apiGolden+" with the current exported API")
+
+// Fails when the exported API of the module changes, so that changes which would break downstream test suites are
+// made deliberately.  After an intentional change, regenerate the golden file and commit it alongside the change:
+//
+//	go test -run Test_ApiSurface -update .
+func Test_ApiSurface(t *testing.T) {
+	actual, err := apiSurface(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := ioutil.WriteFile(apiGolden, []byte(strings.Join(actual, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	b, err := ioutil.ReadFile(apiGolden)
+	if err != nil {
+		t.Fatalf("unable to read %s (run 'go test -run Test_ApiSurface -update .' to create it): %s", apiGolden, err)
+	}
+	expected := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+
+	removed, added := difference(expected, actual), difference(actual, expected)
+	if len(removed) > 0 || len(added) > 0 {
+		t.Errorf("the exported API has changed; if the change is intended, run 'go test -run Test_ApiSurface -update .'\n"+
+			"removed:\n\t%s\nadded:\n\t%s", strings.Join(removed, "\n\t"), strings.Join(added, "\n\t"))
+	}
+}
+
+// Fails when a package of the module imports testify when built with the notestify tag, so that consumers outside of
+// `go test` (e.g. the commands) may build without it:
+//
+//	go build -tags notestify ./...
+func Test_NoTestifyBuild(t *testing.T) {
+	ctx := build.Default
+	ctx.BuildTags = append(append([]string{}, ctx.BuildTags...), "notestify")
+	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != "." && (info.Name() == "testdata" || strings.HasPrefix(info.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		pkg, err := ctx.ImportDir(path, 0)
+		if _, ok := err.(*build.NoGoError); ok {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, imp := range pkg.Imports {
+			if strings.HasPrefix(imp, "github.com/stretchr/testify") {
+				t.Errorf("%s imports %s when built with the notestify tag; move the assertions using it to a file "+
+					"excluded by the tag (e.g. assert.go)", path, imp)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Answers the exported symbols of every importable package beneath the root, sorted.  Commands, test files, and
+// testdata are ignored.
+func apiSurface(root string) ([]string, error) {
+	var api []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != root && (name == "testdata" || name == "cmd" || name == "internal" || strings.HasPrefix(name, ".")) {
+			return filepath.SkipDir
+		}
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, path, func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go")
+		}, 0)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			if pkg.Name == "main" {
+				continue
+			}
+			prefix := fmt.Sprintf("pkg %s, ", filepath.ToSlash(filepath.Clean(path)))
+			for _, file := range pkg.Files {
+				for _, decl := range file.Decls {
+					for _, symbol := range exported(fset, decl) {
+						api = append(api, prefix+symbol)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	sort.Strings(api)
+	return api, err
+}
+
+// Answers the exported symbols declared by the declaration
+func exported(fset *token.FileSet, decl ast.Decl) []string {
+	var symbols []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		if d.Recv == nil {
+			return []string{"func " + d.Name.Name + strings.TrimPrefix(node(fset, d.Type), "func")}
+		}
+		recv := node(fset, d.Recv.List[0].Type)
+		if !ast.IsExported(strings.TrimPrefix(recv, "*")) {
+			return nil
+		}
+		return []string{fmt.Sprintf("method (%s) %s%s", recv, d.Name.Name, strings.TrimPrefix(node(fset, d.Type), "func"))}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					symbols = append(symbols, typeSymbols(fset, "type "+s.Name.Name, s)...)
+				}
+			case *ast.ValueSpec:
+				for i, name := range s.Names {
+					if !name.IsExported() {
+						continue
+					}
+					symbol := fmt.Sprintf("%s %s", d.Tok, name.Name)
+					if s.Type != nil {
+						symbol += " " + node(fset, s.Type)
+					}
+					if d.Tok == token.CONST && i < len(s.Values) {
+						symbol += " = " + node(fset, s.Values[i])
+					}
+					symbols = append(symbols, symbol)
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// Answers the symbols of a type: the type itself, and the exported fields of structs (including the fields of
+// anonymous structs, e.g. `JsonApiData []struct, Id string`) or methods of interfaces
+func typeSymbols(fset *token.FileSet, prefix string, s *ast.TypeSpec) []string {
+	if s.Assign.IsValid() {
+		return []string{prefix + " = " + node(fset, s.Type)}
+	}
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		return append([]string{prefix + " struct"}, fieldSymbols(fset, prefix+" struct", t)...)
+	case *ast.InterfaceType:
+		symbols := []string{prefix + " interface"}
+		for _, m := range t.Methods.List {
+			if len(m.Names) == 0 {
+				symbols = append(symbols, prefix+" interface, embeds "+node(fset, m.Type))
+				continue
+			}
+			if m.Names[0].IsExported() {
+				symbols = append(symbols, prefix+" interface, "+m.Names[0].Name+strings.TrimPrefix(node(fset, m.Type), "func"))
+			}
+		}
+		return symbols
+	}
+	return []string{prefix + " " + node(fset, s.Type)}
+}
+
+func fieldSymbols(fset *token.FileSet, prefix string, st *ast.StructType) []string {
+	var symbols []string
+	for _, f := range st.Fields.List {
+		// the element type of anonymous structs, e.g. []struct{...} or *struct{...}
+		elem, modifiers := f.Type, ""
+		for {
+			if a, ok := elem.(*ast.ArrayType); ok {
+				elem, modifiers = a.Elt, modifiers+"[]"
+			} else if p, ok := elem.(*ast.StarExpr); ok {
+				elem, modifiers = p.X, modifiers+"*"
+			} else {
+				break
+			}
+		}
+
+		names := f.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("embedded " + node(fset, f.Type))}
+		}
+		for _, name := range names {
+			if len(f.Names) > 0 && !name.IsExported() {
+				continue
+			}
+			if nested, ok := elem.(*ast.StructType); ok {
+				symbols = append(symbols, fmt.Sprintf("%s, %s %sstruct", prefix, name.Name, modifiers))
+				symbols = append(symbols, fieldSymbols(fset, fmt.Sprintf("%s, %s %sstruct", prefix, name.Name, modifiers), nested)...)
+			} else if len(f.Names) == 0 {
+				symbols = append(symbols, prefix+", "+name.Name)
+			} else {
+				symbols = append(symbols, fmt.Sprintf("%s, %s %s", prefix, name.Name, node(fset, f.Type)))
+			}
+		}
+	}
+	return symbols
+}
+
+// Answers the source of the node on a single line
+func node(fset *token.FileSet, n ast.Node) string {
+	var b bytes.Buffer
+	_ = printer.Fprint(&b, fset, n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Answers the lines of a that are not in b
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, line := range b {
+		inB[line] = true
+	}
+	var diff []string
+	for _, line := range a {
+		if !inB[line] {
+			diff = append(diff, line)
+		}
+	}
+	return diff
+}
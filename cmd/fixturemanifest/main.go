@@ -0,0 +1,60 @@
+// Checks a fixture tree against its manifest (see fs.Manifest), or writes the manifest of the tree.
+//
+// Usage:
+//
+//	go run ./cmd/fixturemanifest testdata/fixtures.yaml
+//	go run ./cmd/fixturemanifest -write testdata/fixtures.yaml 'expected/*.json' '*.tif'
+//
+// The fixture tree is the directory of the manifest.  Without -write, each file listed by the manifest that is
+// missing or whose checksum differs is reported, and the command exits with status 1.  With -write, the manifest is
+// regenerated from the files of the tree matching the patterns (every file if none is supplied), e.g. from a
+// go:generate directive beside the tests:
+//
+//	//go:generate go run github.com/jhu-idc/idc-golang/cmd/fixturemanifest -write testdata/fixtures.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/jhu-idc/idc-golang/drupal/fs"
+)
+
+func main() {
+	write := flag.Bool("write", false, "write the manifest of the fixture tree rather than checking the tree against it")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: %s [-write] <manifest> [pattern]...\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	manifest := flag.Arg(0)
+
+	if *write {
+		m, err := fs.GenerateManifest(os.DirFS(filepath.Dir(manifest)), flag.Args()[1:]...)
+		if err != nil {
+			log.Fatalf("Unable to generate manifest: %s", err)
+		}
+		if err := m.Write(manifest); err != nil {
+			log.Fatalf("Unable to write manifest: %s", err)
+		}
+		fmt.Printf("Wrote %d fixtures to %s\n", len(m.Files), manifest)
+		return
+	}
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := fs.VerifyManifest(manifest); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,73 @@
+// Prints fixtures merged with the defaults of their directories (see verify.LoadFixture), i.e. the fixtures as they
+// are verified, for debugging fixture inheritance.
+//
+// Usage:
+//
+//	go run ./cmd/flattenfixtures testdata/expected/ansel-adams/moonrise.json
+//	go run ./cmd/flattenfixtures testdata/expected
+//
+// Each argument is a fixture, or a directory whose fixtures (and those of its subdirectories) are printed in lexical
+// order.  When more than one fixture is printed, each is preceded by a `==> path <==` header.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: %s <fixture or directory>...\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var paths []string
+	for _, arg := range flag.Args() {
+		info, err := os.Stat(arg)
+		if err != nil {
+			log.Fatalf("Unable to read %s: %s", arg, err)
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		dirPaths, err := verify.FixturePaths(arg)
+		if err != nil {
+			log.Fatalf("Unable to list the fixtures of %s: %s", arg, err)
+		}
+		paths = append(paths, dirPaths...)
+	}
+
+	for i, path := range paths {
+		b, err := verify.LoadFixture(path)
+		if err != nil {
+			log.Fatalf("Unable to load fixture: %s", err)
+		}
+		out := &bytes.Buffer{}
+		if err := json.Indent(out, b, "", "  "); err != nil {
+			log.Fatalf("Unable to format fixture %s: %s", path, err)
+		}
+		out.WriteByte('\n')
+		if len(paths) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("==> %s <==\n", path)
+		}
+		if _, err := os.Stdout.Write(out.Bytes()); err != nil {
+			log.Fatalf("Unable to write fixture: %s", err)
+		}
+	}
+}
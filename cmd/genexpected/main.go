@@ -0,0 +1,112 @@
+// Generates an Expected JSON fixture from a live Drupal entity, retrieved using the JSON API.
+//
+// Usage:
+//
+//	go run ./cmd/genexpected -baseurl https://islandora-idc.traefik.me -entity taxonomy_term -bundle subject \
+//	  -value "Analog Photography" -o taxonomy-subject.json
+//
+// The entity is matched on its `title` (nodes) or `name` (everything else) unless -filter is supplied.  The fixture is
+// written in canonical form (see package canonical) to standard output unless -o is supplied, so that a regenerated
+// fixture differs from its committed version only where the entity differs.  The lists of the keys of -unordered are
+// sorted.
+//
+// With -schema, the JSON schema that fixtures of the entity type and bundle are validated against (see
+// model.ValidateFixture) is written instead, e.g. for editors that complete and check the keys of fixtures:
+//
+//	go run ./cmd/genexpected -entity taxonomy_term -bundle subject -schema -o taxonomy-subject.schema.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/canonical"
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+func main() {
+	baseUrl := flag.String("baseurl", env.BaseUrlOr("https://islandora-idc.traefik.me"), "base url of Drupal")
+	entity := flag.String("entity", model.Node, "Drupal entity type, e.g. node or taxonomy_term")
+	bundle := flag.String("bundle", "", "Drupal bundle, e.g. islandora_object or subject")
+	filter := flag.String("filter", "", "field used to match the entity (default 'title' for nodes, otherwise 'name')")
+	value := flag.String("value", "", "value of the filter field, e.g. the title of the entity")
+	username := flag.String("username", "", "username used to authenticate to Drupal (optional)")
+	password := flag.String("password", "", "password used to authenticate to Drupal (optional)")
+	out := flag.String("o", "", "file the fixture is written to (default standard output)")
+	unordered := flag.String("unordered", "", "comma-separated keys whose lists are sorted, e.g. subject,genre")
+	schema := flag.Bool("schema", false, "write the JSON schema of the fixtures of the bundle instead of a fixture")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nSupported entity types and bundles:\n  %s\n",
+			strings.Join(model.Generatable(), "\n  "))
+	}
+	flag.Parse()
+
+	if *bundle == "" || (*value == "" && !*schema) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *schema {
+		s, err := model.Schema(*entity, *bundle, false)
+		if err != nil {
+			log.Fatalf("Unable to generate schema: %s", err)
+		}
+		b, err := canonical.Marshal(s)
+		if err != nil {
+			log.Fatalf("Unable to marshal schema: %s", err)
+		}
+		write(*out, b)
+		return
+	}
+
+	if *filter == "" {
+		*filter = "name"
+		if *entity == model.Node {
+			*filter = "title"
+		}
+	}
+
+	expected, err := model.Generate(&jsonapi.JsonApiUrl{
+		BaseUrl:      *baseUrl,
+		DrupalEntity: *entity,
+		DrupalBundle: *bundle,
+		Filter:       *filter,
+		Value:        *value,
+		Username:     *username,
+		Password:     env.Secret(*password),
+	})
+	if err != nil {
+		log.Fatalf("Unable to generate fixture: %s", err)
+	}
+
+	var keys []string
+	if *unordered != "" {
+		keys = strings.Split(*unordered, ",")
+	}
+	b, err := canonical.Marshal(expected, keys...)
+	if err != nil {
+		log.Fatalf("Unable to marshal fixture: %s", err)
+	}
+
+	write(*out, b)
+}
+
+// Writes the document to the file, or to standard output if file is empty
+func write(file string, b []byte) {
+	var err error
+	if file == "" {
+		_, err = os.Stdout.Write(b)
+	} else {
+		err = os.WriteFile(file, b, 0644)
+	}
+	if err != nil {
+		log.Fatalf("Unable to write output: %s", err)
+	}
+}
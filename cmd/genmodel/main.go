@@ -0,0 +1,97 @@
+// Generates an Expected struct from the field definitions of a Drupal bundle, or reports the drift between the fields
+// of the bundle and the keys package model generates for it.
+//
+// Usage:
+//
+//	go run ./cmd/genmodel -baseurl https://islandora-idc.traefik.me -username admin -password password \
+//	  -bundle islandora_object -type ExpectedRepoObj -o expected_repo_obj.go
+//	go run ./cmd/genmodel -config config/sync -bundle islandora_object -check
+//	go run ./cmd/genmodel -list -entity media
+//
+// Field definitions are read from the JSON API unless -config names the directory of a configuration export.  With
+// -check, the drift is written to standard output, and the exit status is 1 if there is any.  With -list, the bundles of
+// the entity type listed by the JSON API entry point are written to standard output, one per line, rather than
+// generating a struct.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/fieldconfig"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+func main() {
+	baseUrl := flag.String("baseurl", env.BaseUrlOr("https://islandora-idc.traefik.me"), "base url of Drupal")
+	config := flag.String("config", "", "directory of a configuration export, read instead of the JSON API")
+	entity := flag.String("entity", model.Node, "Drupal entity type, e.g. node or taxonomy_term")
+	bundle := flag.String("bundle", "", "Drupal bundle, e.g. islandora_object or subject")
+	username := flag.String("username", "", "username used to authenticate to Drupal")
+	password := flag.String("password", "", "password used to authenticate to Drupal")
+	pkg := flag.String("package", "model", "package of the generated file")
+	typeName := flag.String("type", "", "name of the generated struct (default Expected followed by the bundle)")
+	out := flag.String("o", "", "file the struct is written to (default standard output)")
+	check := flag.Bool("check", false, "report the drift between the fields of the bundle and package model")
+	list := flag.Bool("list", false, "list the bundles of the entity type exposed by the JSON API")
+	flag.Parse()
+
+	if *list {
+		c, err := jsonapi.Discover(*baseUrl, *username, *password)
+		if err != nil {
+			log.Fatalf("Unable to discover bundles: %s", err)
+		}
+		for _, b := range c.Bundles(*entity) {
+			fmt.Println(b)
+		}
+		return
+	}
+
+	if *bundle == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var fields []fieldconfig.Field
+	var err error
+	if *config != "" {
+		fields, err = fieldconfig.Load(*config, *entity, *bundle)
+	} else {
+		fields, err = fieldconfig.Fetch(*baseUrl, *username, *password, *entity, *bundle)
+	}
+	if err != nil {
+		log.Fatalf("Unable to read fields: %s", err)
+	}
+
+	if *check {
+		d, err := fieldconfig.CheckDrift(*entity, *bundle, fields)
+		if err != nil {
+			log.Fatalf("Unable to check drift: %s", err)
+		}
+		if !d.Empty() {
+			fmt.Println(d)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *typeName == "" {
+		*typeName = "Expected" + fieldconfig.GoName(*bundle)
+	}
+	src, err := fieldconfig.Generate(*pkg, *typeName, *entity, *bundle, fields)
+	if err != nil {
+		log.Fatalf("Unable to generate %s: %s", *typeName, err)
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		log.Fatalf("Unable to write %s: %s", *typeName, err)
+	}
+}
@@ -0,0 +1,165 @@
+// Verifies Expected fixtures against the live entities of a Drupal site, writing a report of the outcomes.
+//
+// Usage:
+//
+//	go run ./cmd/idc-verify -baseurl https://islandora-idc.traefik.me -format json -o report.json testdata/expected
+//
+// Each argument is a fixture, or a directory whose fixtures (and those of its subdirectories) are verified in lexical
+// order.  Fixtures are verified concurrently (see -concurrency), but reported in order.  With -junit, a JUnit XML
+// report is written too, e.g. for a CI server to chart.  The base url and credentials default to DRUPAL_BASE_URL, DRUPAL_USERNAME and DRUPAL_PASSWORD, which may be
+// set by a .env file in the working directory (see env.LoadDotEnv).
+//
+// The exit status distinguishes the class of the outcome, so that CI pipelines may branch on it (see report.ExitCode):
+//
+//	0  every fixture passed (with -fail-on warnings, without drift or unverified keys)
+//	1  a fixture failed or errored (with -fail-on warnings, or carried a warning)
+//	2  the configuration was invalid, e.g. an unknown flag or a missing base url
+//	3  Drupal was unreachable or rejected the credentials
+//	4  internal error, e.g. the report could not be written
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/preflight"
+	"github.com/jhu-idc/idc-golang/drupal/report"
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+func main() {
+	os.Exit(int(run()))
+}
+
+// Verifies the fixtures named by the command line, answering the exit status
+func run() report.ExitCode {
+	baseUrl := flag.String("baseurl", "", "base url of Drupal (default DRUPAL_BASE_URL)")
+	username := flag.String("username", "", "username used to authenticate to Drupal (default DRUPAL_USERNAME)")
+	password := flag.String("password", "", "password used to authenticate to Drupal (default DRUPAL_PASSWORD)")
+	format := flag.String("format", "text", "format of the report: text, json or groups")
+	out := flag.String("o", "", "file the report is written to (default standard output)")
+	failOnFlag := flag.String("fail-on", string(report.FailOnErrors), "least severe outcome failing the run: errors or warnings")
+	skipPreflight := flag.Bool("skip-preflight", false, "verify without first checking that Drupal is reachable")
+	concurrency := flag.Int("concurrency", report.DefaultConcurrency, "number of fixtures verified at once")
+	junit := flag.String("junit", "", "file a JUnit XML report is also written to, e.g. for CI servers")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: %s [flags] <fixture or directory>...\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		return report.ExitConfig
+	}
+	failOn, err := report.ParseFailOn(*failOnFlag)
+	if err != nil {
+		return fail(report.ExitConfig, "%s", err)
+	}
+	if *format != "text" && *format != "json" && *format != "groups" {
+		return fail(report.ExitConfig, "Unknown report format '%s'", *format)
+	}
+
+	if err := env.LoadDotEnv(); err != nil {
+		return fail(report.ExitConfig, "%s", err)
+	}
+	var opts []env.Option
+	if *baseUrl != "" {
+		opts = append(opts, env.WithBaseUrl(*baseUrl))
+	}
+	if *username != "" {
+		opts = append(opts, env.WithCredentials(*username, *password))
+	}
+	c, err := env.LoadConfig(opts...)
+	if err != nil {
+		return fail(report.ExitConfig, "%s", err)
+	}
+	if err := jsonapi.UseConfig(c); err != nil {
+		return fail(report.ExitConfig, "%s", err)
+	}
+
+	for _, arg := range flag.Args() {
+		if _, err := os.Stat(arg); err != nil {
+			return fail(report.ExitConfig, "Unable to read %s: %s", arg, err)
+		}
+	}
+
+	if !*skipPreflight {
+		checks := preflight.NewChecker(c.BaseUrl, c.Username, c.Password.Reveal()).Run()
+		if !checks.Reachable() {
+			return fail(report.ExitUnreachable, "%s", checks.Err())
+		}
+		if err := checks.Err(); err != nil {
+			return fail(report.ExitConfig, "%s", err)
+		}
+	}
+
+	engine := verify.NewEngine(c.BaseUrl, c.Username, c.Password.Reveal())
+	r := report.New(time.Now())
+	defer r.Close()
+	if err := r.Environment.ProbeDrupal(c.BaseUrl); err != nil {
+		log.Printf("%s", err)
+	}
+	runner := report.NewRunner(engine)
+	runner.Concurrency = *concurrency
+	if err := runner.Run(r, flag.Args()...); err != nil {
+		return fail(report.ExitInternal, "%s", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fail(report.ExitInternal, "Unable to create report: %s", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	switch *format {
+	case "json":
+		err = r.WriteJson(w)
+	case "groups":
+		err = r.WriteGroups(w)
+	default:
+		err = r.WriteText(w)
+	}
+	if err != nil {
+		return fail(report.ExitInternal, "Unable to write report: %s", err)
+	}
+	if *junit != "" {
+		if err := writeJUnit(r, *junit); err != nil {
+			return fail(report.ExitInternal, "Unable to write JUnit report: %s", err)
+		}
+	}
+
+	code, err := r.ExitCode(failOn)
+	if err != nil {
+		return fail(code, "Unable to classify the results: %s", err)
+	}
+	return code
+}
+
+// Writes the report as JUnit XML to the file
+func writeJUnit(r *report.Report, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteJUnit(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Logs the message, answering the code
+func fail(code report.ExitCode, format string, args ...interface{}) report.ExitCode {
+	log.Printf(format, args...)
+	return code
+}
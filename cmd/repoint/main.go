@@ -0,0 +1,57 @@
+// Repoints a relationship of every entity of a bundle from one entity to another, e.g. to move every member of one
+// collection to another after a test run.
+//
+// Usage:
+//
+//	go run ./cmd/repoint -baseurl https://islandora-idc.traefik.me -username admin -password password \
+//	  -bundle islandora_object -relationship field_member_of -from {uuid} -to-type node--collection_object -to {uuid} \
+//	  -dry-run
+//
+// The planned changes are written to standard output.  Unless -dry-run is supplied, they are then applied using JSON
+// API PATCH requests.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/jhu-idc/idc-golang/drupal/rewrite"
+)
+
+func main() {
+	baseUrl := flag.String("baseurl", env.BaseUrlOr("https://islandora-idc.traefik.me"), "base url of Drupal")
+	entity := flag.String("entity", model.Node, "Drupal entity type of the entities changed, e.g. node or media")
+	bundle := flag.String("bundle", model.RepositoryObject, "Drupal bundle of the entities changed, e.g. islandora_object")
+	relationship := flag.String("relationship", "field_member_of", "relationship repointed, e.g. field_member_of")
+	from := flag.String("from", "", "UUID of the entity currently referenced")
+	toType := flag.String("to-type", "", "Drupal type of the entity referenced instead, e.g. node--collection_object")
+	to := flag.String("to", "", "UUID of the entity referenced instead")
+	username := flag.String("username", "", "username used to authenticate to Drupal")
+	password := flag.String("password", "", "password used to authenticate to Drupal")
+	dryRun := flag.Bool("dry-run", false, "write the planned changes without applying them")
+	flag.Parse()
+
+	if *from == "" || *to == "" || *toType == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	w := rewrite.NewRewriter(*baseUrl, *username, *password)
+	changes, err := w.Plan(*entity, *bundle, *relationship, *from, *toType, *to)
+	if err != nil {
+		log.Fatalf("Unable to plan changes: %s", err)
+	}
+	if err := rewrite.WritePlan(os.Stdout, changes); err != nil {
+		log.Fatalf("Unable to write planned changes: %s", err)
+	}
+	if *dryRun {
+		return
+	}
+	if err := w.Apply(changes); err != nil {
+		log.Fatalf("Unable to apply changes: %s", err)
+	}
+	log.Printf("Applied %d changes", len(changes))
+}
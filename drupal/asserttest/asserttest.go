@@ -0,0 +1,46 @@
+// Provides a Recorder, an assert.TestingT that records the failures reported to it rather than failing a test, so
+// that tests of assertion helpers (e.g. verify.AssertLinks) may assert that a helper fails, and on why it fails.
+//
+//	rec := &asserttest.Recorder{}
+//	assert.False(t, verify.AssertUri(rec, "http://example.org/a", "http://example.org/b"))
+//	assert.Contains(t, rec.String(), "URIs are not equal")
+package asserttest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Records the failure messages reported by assertions.  The zero value is ready to use, and is safe for use by
+// multiple goroutines.
+type Recorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// Records the formatted failure message
+func (r *Recorder) Errorf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+// Answers true if any failure was recorded
+func (r *Recorder) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages) > 0
+}
+
+// Answers the recorded failure messages, in the order they were reported
+func (r *Recorder) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages...)
+}
+
+// Answers the recorded failure messages, separated by newlines
+func (r *Recorder) String() string {
+	return strings.Join(r.Messages(), "\n")
+}
@@ -0,0 +1,23 @@
+package asserttest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Recorder(t *testing.T) {
+	rec := &Recorder{}
+	assert.True(t, assert.Equal(rec, 1, 1))
+	assert.False(t, rec.Failed())
+	assert.Equal(t, "", rec.String())
+
+	assert.False(t, assert.Equal(rec, "Moonrise", "Moonset", "title differs"))
+	assert.False(t, assert.Empty(rec, []string{"Photography"}))
+	assert.True(t, rec.Failed())
+	messages := rec.Messages()
+	assert.Equal(t, 2, len(messages))
+	assert.Contains(t, messages[0], "title differs")
+	assert.Contains(t, messages[1], "Should be empty")
+	assert.Contains(t, rec.String(), "Moonset")
+}
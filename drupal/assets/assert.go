@@ -0,0 +1,21 @@
+//go:build !notestify
+// +build !notestify
+
+package assets
+
+import (
+	"github.com/stretchr/testify/require"
+)
+
+// Requires that every asset referenced by the manifests is available before the timeout elapses, failing the test
+// immediately with a list of the missing assets otherwise
+func (c *Checker) RequireManifests(t require.TestingT, patterns ...string) {
+	require.Nil(t, c.CheckManifests(patterns...))
+}
+
+// Behaves as Fetch, failing the test immediately if the asset cannot be fetched or does not match its checksum
+func (f *Fetcher) Require(t require.TestingT, relPath, checksum string) string {
+	local, err := f.Fetch(relPath, checksum)
+	require.Nil(t, err, "error fetching asset %s: %s", relPath, err)
+	return local
+}
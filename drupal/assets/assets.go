@@ -0,0 +1,178 @@
+// Checks that the assets referenced by fixtures are served by the assets server, so that a test suite may fail early
+// with a list of missing assets rather than with errors in the middle of an ingest.
+//
+// Asset URLs are found by scanning manifests (e.g. migration source CSVs or fixture JSON) for absolute URLs beneath
+// the base URL of the assets server:
+//
+//	func TestMain(m *testing.M) {
+//		if err := assets.CheckManifests(env.AssetsBaseUrl(), time.Minute, "testdata/migrate/*.csv"); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//		os.Exit(m.Run())
+//	}
+package assets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The interval between polls of Checker.Check
+var PollInterval = 2 * time.Second
+
+// Matches absolute http and https URLs, ending at whitespace, quotes, or the delimiters of CSV and JSON
+var urlPattern = regexp.MustCompile(`https?://[^\s"',;|<>\[\]{}]+`)
+
+// An asset that was not available before the timeout elapsed
+type Missing struct {
+	Url string
+	// The status of the last response; zero if no response was received
+	Status int
+	// The error of the last request, if no response was received
+	Err error
+}
+
+// Answers the missing asset as e.g. `http://assets/image.jpg: 404 Not Found`
+func (m Missing) String() string {
+	if m.Err != nil {
+		return fmt.Sprintf("%s: %s", m.Url, m.Err)
+	}
+	return fmt.Sprintf("%s: %d %s", m.Url, m.Status, http.StatusText(m.Status))
+}
+
+// Polls the assets server until assets are available.  The server may still be starting, or may still be populating
+// its content, so assets are requested repeatedly until they answer 200 or the timeout elapses.
+type Checker struct {
+	// The base URL of the assets server; only URLs beneath it are found in manifests.  Every URL is found if empty.
+	BaseUrl string
+	Timeout time.Duration
+}
+
+// Creates a Checker for the assets server at the base url
+func NewChecker(baseUrl string, timeout time.Duration) *Checker {
+	return &Checker{BaseUrl: baseUrl, Timeout: timeout}
+}
+
+// Answers the distinct asset URLs referenced by the content, in order of their first reference
+func (c *Checker) Urls(content []byte) []string {
+	base := strings.TrimSuffix(c.BaseUrl, "/")
+	seen := map[string]bool{}
+	var urls []string
+	// JSON may escape the slashes of a URL
+	unescaped := strings.ReplaceAll(string(content), `\/`, "/")
+	for _, u := range urlPattern.FindAllString(unescaped, -1) {
+		if seen[u] || (base != "" && u != base && !strings.HasPrefix(u, base+"/")) {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// Answers the distinct asset URLs referenced by the manifests matching the glob patterns, in order of their first
+// reference.  An error is answered if a pattern matches no file, or a manifest cannot be read.
+func (c *Checker) ManifestUrls(patterns ...string) ([]string, error) {
+	seen := map[string]bool{}
+	var urls []string
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("assets: %w", err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("assets: no manifest matches '%s'", pattern)
+		}
+		for _, path := range paths {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("assets: %w", err)
+			}
+			for _, u := range c.Urls(b) {
+				if !seen[u] {
+					seen[u] = true
+					urls = append(urls, u)
+				}
+			}
+		}
+	}
+	return urls, nil
+}
+
+// Requests each URL every PollInterval until it answers 200, and answers the URLs that did not answer 200 before the
+// timeout elapsed, ordered by URL
+func (c *Checker) Check(urls ...string) []Missing {
+	deadline := time.Now().Add(c.Timeout)
+	pending := append([]string(nil), urls...)
+	var missing []Missing
+	for {
+		missing = missing[:0]
+		for _, u := range pending {
+			if m := request(u); m != nil {
+				missing = append(missing, *m)
+			}
+		}
+		if len(missing) == 0 || time.Now().Add(PollInterval).After(deadline) {
+			break
+		}
+		pending = pending[:0]
+		for _, m := range missing {
+			pending = append(pending, m.Url)
+		}
+		time.Sleep(PollInterval)
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Url < missing[j].Url })
+	return missing
+}
+
+// Answers an error listing the assets referenced by the manifests that are not available before the timeout elapses
+func (c *Checker) CheckManifests(patterns ...string) error {
+	urls, err := c.ManifestUrls(patterns...)
+	if err != nil {
+		return err
+	}
+	if missing := c.Check(urls...); len(missing) > 0 {
+		return fmt.Errorf("assets: %d of %d assets are missing:\n%s", len(missing), len(urls), describe(missing))
+	}
+	return nil
+}
+
+// Answers an error listing the assets referenced by the manifests that are not available from the assets server at
+// the base url before the timeout elapses
+func CheckManifests(baseUrl string, timeout time.Duration, patterns ...string) error {
+	return NewChecker(baseUrl, timeout).CheckManifests(patterns...)
+}
+
+// Requests the URL, answering nil if it answers 200
+func request(u string) *Missing {
+	res, err := jsonapi.HTTPClient().Head(u)
+	if err == nil && res.StatusCode == http.StatusMethodNotAllowed {
+		res.Body.Close()
+		res, err = jsonapi.HTTPClient().Get(u)
+	}
+	if err != nil {
+		return &Missing{Url: u, Err: err}
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return &Missing{Url: u, Status: res.StatusCode}
+	}
+	return nil
+}
+
+func describe(missing []Missing) string {
+	lines := make([]string, 0, len(missing))
+	for _, m := range missing {
+		lines = append(lines, "  "+m.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,68 @@
+package assets
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Urls(t *testing.T) {
+	c := NewChecker("http://assets/assets/", time.Second)
+	csv := `local_id,title,file
+io_1,Moonrise,http://assets/assets/moonrise.jpg
+io_2,"Hernandez, New Mexico",http://assets/assets/hernandez.tif|http://assets/assets/moonrise.jpg
+io_3,Elsewhere,https://example.org/assets/other.jpg`
+	assert.Equal(t, []string{"http://assets/assets/moonrise.jpg", "http://assets/assets/hernandez.tif"}, c.Urls([]byte(csv)))
+	assert.Empty(t, c.Urls([]byte(`no urls here`)))
+	assert.Equal(t, []string{"http://assets/assets/video.mp4"}, c.Urls([]byte(`{"file": "http:\/\/assets\/assets\/video.mp4"}`)))
+	assert.Equal(t, 3, len(NewChecker("", time.Second).Urls([]byte(csv))))
+}
+
+func Test_CheckManifests(t *testing.T) {
+	interval := PollInterval
+	PollInterval = 10 * time.Millisecond
+	defer func() { PollInterval = interval }()
+
+	// the late asset is only available from the third request
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/assets/moonrise.jpg":
+		case "/assets/late.jpg":
+			if atomic.AddInt32(&requests, 1) < 3 {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "/assets/head.jpg":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "objects.csv"), []byte("local_id,file\n"+
+		"io_1,"+server.URL+"/assets/moonrise.jpg\n"+
+		"io_2,"+server.URL+"/assets/late.jpg\n"+
+		"io_3,"+server.URL+"/assets/head.jpg\n"), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "media.csv"), []byte("local_id,file\n"+
+		"m_1,"+server.URL+"/assets/absent.jpg\n"), 0644))
+
+	assert.Nil(t, CheckManifests(server.URL+"/assets", time.Second, filepath.Join(dir, "objects.csv")))
+
+	err := CheckManifests(server.URL+"/assets", 100*time.Millisecond, filepath.Join(dir, "*.csv"))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 of 4 assets are missing")
+	assert.Contains(t, err.Error(), server.URL+"/assets/absent.jpg: 404 Not Found")
+
+	assert.NotNil(t, CheckManifests(server.URL, time.Second, filepath.Join(dir, "*.json")))
+}
@@ -0,0 +1,195 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The content of a downloaded asset does not match its expected checksum
+var ErrChecksumMismatch = errors.New("assets: checksum mismatch")
+
+// Matches a hex-encoded SHA-256 checksum
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Downloads assets (e.g. large TIFFs and PDFs) from the assets server, caching them on local disk keyed by their
+// SHA-256 checksum, so that tests may use large binaries without committing them, and without downloading them on
+// every run:
+//
+//	f, err := assets.NewFetcherFromEnv()
+//	...
+//	path := f.Require(t, "tiff/moonrise.tif", "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08")
+type Fetcher struct {
+	// The base URL of the assets server, which asset paths are relative to
+	BaseUrl string
+	// The directory of the cache; an `idc-assets` directory beneath the temporary directory of the system if empty
+	CacheDir string
+}
+
+// Creates a Fetcher of the assets beneath the base url
+func NewFetcher(baseUrl string) *Fetcher {
+	return &Fetcher{BaseUrl: baseUrl}
+}
+
+// Creates a Fetcher of the assets beneath the base url of the environment variable 'BASE_ASSETS_URL', answering an
+// error if it is unset
+func NewFetcherFromEnv() (*Fetcher, error) {
+	baseUrl, err := env.AssetsBaseUrlE()
+	if err != nil {
+		return nil, err
+	}
+	return NewFetcher(baseUrl), nil
+}
+
+// Answers the local path of the asset at the path relative to the base url (e.g. `tiff/moonrise.tif`), downloading
+// it unless it is already cached.  The checksum is the hex-encoded SHA-256 of the asset's content: a cached asset is
+// answered only if its content still matches it, and a downloaded asset whose content does not match it is discarded,
+// answering an error wrapping ErrChecksumMismatch.  If the checksum is empty the asset is trusted as downloaded, and
+// cached by the checksum of its content; its url is remembered, so that it is downloaded only once.
+func (f *Fetcher) Fetch(relPath, checksum string) (string, error) {
+	u, err := f.url(relPath)
+	if err != nil {
+		return "", err
+	}
+	expected := strings.ToLower(checksum)
+	if expected != "" && !sha256Pattern.MatchString(expected) {
+		return "", fmt.Errorf("assets: invalid checksum '%s' of %s: expected a hex-encoded SHA-256", checksum, relPath)
+	}
+	key := expected
+	if key == "" {
+		key = f.indexed(u)
+	}
+	if key != "" {
+		local := f.cached(key, relPath)
+		if sum, err := Checksum(local); err == nil && sum == key {
+			return local, nil
+		}
+	}
+
+	tmp, sum, err := f.download(u)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+	if expected != "" && sum != expected {
+		return "", fmt.Errorf("%w: %s has SHA-256 %s, expected %s", ErrChecksumMismatch, u, sum, expected)
+	}
+	local := f.cached(sum, relPath)
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		return "", fmt.Errorf("assets: error creating the cache: %w", err)
+	}
+	if err := os.Rename(tmp, local); err != nil {
+		return "", fmt.Errorf("assets: error caching %s: %w", u, err)
+	}
+	f.index(u, sum)
+	return local, nil
+}
+
+// Answers the hex-encoded SHA-256 checksum of the content of the file, e.g. to record the checksum of an asset
+func Checksum(file string) (string, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Answers the directory of the cache
+func (f *Fetcher) cacheDir() string {
+	if f.CacheDir != "" {
+		return f.CacheDir
+	}
+	return filepath.Join(os.TempDir(), "idc-assets")
+}
+
+// Answers the path of the cached asset with the checksum, retaining the file name of the asset so that its
+// extension is preserved
+func (f *Fetcher) cached(checksum, relPath string) string {
+	return filepath.Join(f.cacheDir(), checksum, path.Base(relPath))
+}
+
+// Answers the url of the asset at the relative path, escaping each of its segments.  An error is answered if the path
+// is absolute or escapes the base url.
+func (f *Fetcher) url(relPath string) (string, error) {
+	clean := path.Clean(relPath)
+	if relPath == "" || path.IsAbs(relPath) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("assets: invalid asset path '%s': must be relative to the base url", relPath)
+	}
+	segments := strings.Split(clean, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.TrimSuffix(f.BaseUrl, "/") + "/" + strings.Join(segments, "/"), nil
+}
+
+// Downloads the url to a temporary file in the cache directory, answering its path and the checksum of its content
+func (f *Fetcher) download(u string) (string, string, error) {
+	if err := os.MkdirAll(f.cacheDir(), 0755); err != nil {
+		return "", "", fmt.Errorf("assets: error creating the cache: %w", err)
+	}
+	res, err := jsonapi.HTTPClient().Get(u)
+	if err != nil {
+		return "", "", fmt.Errorf("assets: error downloading %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("assets: error downloading %s: %d %s", u, res.StatusCode, http.StatusText(res.StatusCode))
+	}
+
+	out, err := ioutil.TempFile(f.cacheDir(), "download-")
+	if err != nil {
+		return "", "", fmt.Errorf("assets: error creating the cache: %w", err)
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, h), res.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", "", fmt.Errorf("assets: error downloading %s: %w", u, err)
+	}
+	return out.Name(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Answers the path of the index entry of the url, recording the checksum of its content
+func (f *Fetcher) indexEntry(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(f.cacheDir(), "urls", hex.EncodeToString(sum[:]))
+}
+
+// Answers the checksum of the content last downloaded from the url, or the empty string if it was never downloaded
+func (f *Fetcher) indexed(u string) string {
+	b, err := ioutil.ReadFile(f.indexEntry(u))
+	if checksum := strings.TrimSpace(string(b)); err == nil && sha256Pattern.MatchString(checksum) {
+		return checksum
+	}
+	return ""
+}
+
+// Records the checksum of the content downloaded from the url.  The index is only an optimization, so errors are
+// ignored.
+func (f *Fetcher) index(u, checksum string) {
+	entry := f.indexEntry(u)
+	if err := os.MkdirAll(filepath.Dir(entry), 0755); err == nil {
+		_ = ioutil.WriteFile(entry, []byte(checksum), 0644)
+	}
+}
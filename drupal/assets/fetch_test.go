@@ -0,0 +1,77 @@
+//go:build !notestify
+// +build !notestify
+
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Fetch(t *testing.T) {
+	content := "II*\x00moonrise"
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.EscapedPath() {
+		case "/assets/tiff/moonrise%20over%20hernandez.tif":
+			w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	f := &Fetcher{BaseUrl: server.URL + "/assets/", CacheDir: t.TempDir()}
+	local, err := f.Fetch("tiff/moonrise over hernandez.tif", strings.ToUpper(checksum))
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(f.CacheDir, checksum, "moonrise over hernandez.tif"), local)
+	b, err := ioutil.ReadFile(local)
+	require.Nil(t, err)
+	assert.Equal(t, content, string(b))
+
+	// cached assets are not downloaded again
+	local, err = f.Fetch("tiff/moonrise over hernandez.tif", checksum)
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, local, f.Require(t, "tiff/moonrise over hernandez.tif", ""))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// corrupt cached assets are downloaded again
+	require.Nil(t, ioutil.WriteFile(local, []byte("corrupt"), 0644))
+	local, err = f.Fetch("tiff/moonrise over hernandez.tif", checksum)
+	require.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	actual, err := Checksum(local)
+	require.Nil(t, err)
+	assert.Equal(t, checksum, actual)
+
+	_, err = f.Fetch("tiff/moonrise over hernandez.tif", strings.Repeat("0", 64))
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	assert.Contains(t, err.Error(), "expected 0000")
+	_, err = f.Fetch("tiff/missing.tif", "")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found")
+
+	requests = 0
+	for _, invalid := range []string{"", "/tiff/moonrise.tif", "../moonrise.tif", "tiff/../../moonrise.tif"} {
+		_, err = f.Fetch(invalid, "")
+		assert.NotNil(t, err, invalid)
+	}
+	_, err = f.Fetch("tiff/moonrise.tif", "../../etc")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,138 @@
+// Serializes JSON documents canonically, so that fixtures and snapshots regenerated from a live site differ from
+// their committed versions only where their content differs, and review well in version control:
+//
+//	b, err := canonical.Marshal(expected, "subject", "genre")
+//
+// A canonical document has the keys of its objects sorted, is indented by two spaces, is not HTML-escaped, and ends
+// with a newline.  Numbers are written as they were read.  The lists of the unordered keys supplied (those whose
+// order is not significant, e.g. the keys of verify.Engine.UnorderedKeys) are sorted by the canonical encoding of
+// their elements.
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Answers the canonical JSON encoding of the value, sorting the lists of the unordered keys.  An unordered key is a
+// key (e.g. `subject`), matched at any depth, or a dotted path from the root (e.g. `data.attributes.field_subject`),
+// either of which may carry the wildcards of path.Match.  Array indexes are not part of a path.
+func Marshal(v interface{}, unordered ...string) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("canonical: %w", err)
+	}
+	return Format(b, unordered...)
+}
+
+// Answers the canonical form of the JSON document, sorting the lists of the unordered keys (see Marshal)
+func Format(doc []byte, unordered ...string) ([]byte, error) {
+	v, err := Decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(v, unordered...)
+}
+
+// Decodes the JSON document, preserving its numbers as json.Number, so that the document may be altered (e.g. by
+// removing members) before it is encoded by Encode
+func Decode(doc []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("canonical: invalid JSON document: %w", err)
+	}
+	return v, nil
+}
+
+// Answers the canonical JSON encoding of the decoded document (see Decode), sorting the lists of the unordered keys
+// (see Marshal)
+func Encode(v interface{}, unordered ...string) ([]byte, error) {
+	for _, pattern := range unordered {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("canonical: invalid unordered key '%s': %w", pattern, err)
+		}
+	}
+	v, err := sortLists(v, "", false, unordered)
+	if err != nil {
+		return nil, err
+	}
+	out := &bytes.Buffer{}
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("canonical: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// Answers true if the member with the key, at the dotted path, matches any of the patterns: a pattern containing `.`
+// is matched against the path, and any other pattern against the key
+func Matches(key, p string, patterns []string) bool {
+	for _, pattern := range patterns {
+		subject := key
+		if strings.Contains(pattern, ".") {
+			subject = p
+		}
+		if ok, _ := path.Match(pattern, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Answers the value with the lists of the unordered keys sorted, at any depth.  The list itself is sorted if sorted is
+// true.
+func sortLists(v interface{}, at string, sorted bool, unordered []string) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, member := range v {
+			p := k
+			if at != "" {
+				p = at + "." + k
+			}
+			var err error
+			if v[k], err = sortLists(member, p, Matches(k, p, unordered), unordered); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		keys := make([]string, len(v))
+		for i, item := range v {
+			var err error
+			if v[i], err = sortLists(item, at, false, unordered); err != nil {
+				return nil, err
+			}
+			if sorted {
+				b, err := json.Marshal(v[i])
+				if err != nil {
+					return nil, fmt.Errorf("canonical: %w", err)
+				}
+				keys[i] = string(b)
+			}
+		}
+		if sorted {
+			sort.Sort(byKey{v, keys})
+		}
+	}
+	return v, nil
+}
+
+// Sorts the elements of a list by their compact encodings
+type byKey struct {
+	list []interface{}
+	keys []string
+}
+
+func (b byKey) Len() int           { return len(b.list) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.list[i], b.list[j] = b.list[j], b.list[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
@@ -0,0 +1,70 @@
+package canonical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Format(t *testing.T) {
+	b, err := Format([]byte(`{"type": "node", "title": "Moonrise & Hernandez", "field_weight": 3.0,
+		"subject": ["Photography", "Landscapes", {"name": "Moon", "id": "m1"}], "genre": ["Prints", "Maps"]}`), "subject")
+	require.Nil(t, err)
+	assert.Equal(t, `{
+  "field_weight": 3.0,
+  "genre": [
+    "Prints",
+    "Maps"
+  ],
+  "subject": [
+    "Landscapes",
+    "Photography",
+    {
+      "id": "m1",
+      "name": "Moon"
+    }
+  ],
+  "title": "Moonrise & Hernandez",
+  "type": "node"
+}
+`, string(b))
+
+	// formatting is idempotent
+	again, err := Format(b, "subject")
+	require.Nil(t, err)
+	assert.Equal(t, string(b), string(again))
+
+	_, err = Format([]byte(`{"title": `))
+	assert.NotNil(t, err)
+	_, err = Format([]byte(`{}`), "[")
+	assert.NotNil(t, err)
+}
+
+func Test_FormatPaths(t *testing.T) {
+	doc := []byte(`{"data": {"attributes": {"field_subject": ["b", "a"], "field_extent": ["b", "a"]},
+		"relationships": {"field_subject": {"data": [{"id": "2"}, {"id": "1"}]}}}}`)
+
+	b, err := Format(doc, "data.attributes.field_subject")
+	require.Nil(t, err)
+	assert.Regexp(t, `"field_extent": \[\s+"b",\s+"a"\s+\]`, string(b))
+	assert.Regexp(t, `"field_subject": \[\s+"a",\s+"b"\s+\]`, string(b))
+	assert.Regexp(t, `"id": "2"\s+},\s+{\s+"id": "1"`, string(b))
+
+	b, err = Format(doc, "field_sub*", "data.relationships.*.data")
+	require.Nil(t, err)
+	assert.Regexp(t, `"field_subject": \[\s+"a",\s+"b"\s+\]`, string(b))
+	assert.Regexp(t, `"id": "1"\s+},\s+{\s+"id": "2"`, string(b))
+}
+
+func Test_Marshal(t *testing.T) {
+	b, err := Marshal(struct {
+		Type  string   `json:"type"`
+		Genre []string `json:"genre"`
+	}{Type: "node", Genre: []string{"Prints", "Maps"}}, "genre")
+	require.Nil(t, err)
+	assert.Equal(t, "{\n  \"genre\": [\n    \"Maps\",\n    \"Prints\"\n  ],\n  \"type\": \"node\"\n}\n", string(b))
+
+	_, err = Marshal(make(chan int))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,193 @@
+// Traverses the membership hierarchy of collections and repository objects formed by their `field_member_of`
+// relationships, so that collection hierarchies may be verified without chaining requests by hand.
+package collection
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+var (
+	ErrNotFound  = errors.New("no node matches")
+	ErrAmbiguous = errors.New("more than one node matches")
+)
+
+// The node bundles that may be members of a collection, in the order they are searched
+var MemberBundles = []string{model.Collection, model.RepositoryObject}
+
+// The relationship that carries the membership of a node
+const memberOf = "field_member_of"
+
+// A node within a membership hierarchy
+type Member struct {
+	Id string
+	// The bundle of the node, e.g. `collection_object` or `islandora_object`
+	Bundle string
+	Title  string
+	// The ids of the nodes the node is a member of
+	MemberOf []string
+	// The distance of the node from the node a traversal began at, e.g. 1 for a direct child or parent
+	Depth int
+}
+
+// Traverses the membership hierarchy of a Drupal site
+type Client struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the Drupal site at the base url.  If the username is not empty, requests are authenticated
+// using HTTP Basic Auth.
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the node identified by the title, UUID, or legacy PID (see jsonapi.NodeIdentifierFilter).  Nodes are
+// searched for in the order of MemberBundles; an error wrapping ErrNotFound or ErrAmbiguous is answered if zero or
+// more than one node of a bundle matches.
+func (c *Client) Find(titleOrUuid string) (*Member, error) {
+	filter, value := jsonapi.NodeIdentifierFilter(titleOrUuid)
+	for _, bundle := range MemberBundles {
+		var matched []map[string]interface{}
+		err := c.url(bundle, filter, value).FetchPages(func(page *jsonapi.JsonApiPage) error {
+			matched = append(matched, page.Data...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch len(matched) {
+		case 0:
+			continue
+		case 1:
+			return newMember(matched[0], 0), nil
+		}
+		return nil, fmt.Errorf("%w: '%s' matches %d %s nodes", ErrAmbiguous, titleOrUuid, len(matched), bundle)
+	}
+	return nil, fmt.Errorf("%w: '%s'", ErrNotFound, titleOrUuid)
+}
+
+// Answers the direct members of the node identified by the title or UUID
+func (c *Client) Children(titleOrUuid string) ([]*Member, error) {
+	return c.Descendants(titleOrUuid, 1)
+}
+
+// Answers the members of the node identified by the title or UUID, their members, and so on, breadth first.  If
+// maxDepth is positive, members further than maxDepth from the node are not answered; e.g. a maxDepth of 1 answers
+// the direct members only.  A member of more than one node within the hierarchy is answered once, at its least depth.
+func (c *Client) Descendants(titleOrUuid string, maxDepth int) ([]*Member, error) {
+	root, err := c.Find(titleOrUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var descendants []*Member
+	seen := map[string]bool{root.Id: true}
+	level := []*Member{root}
+	for depth := 1; len(level) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		var next []*Member
+		for _, parent := range level {
+			children, err := c.members(parent.Id, depth)
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				if !seen[child.Id] {
+					seen[child.Id] = true
+					next = append(next, child)
+				}
+			}
+		}
+		descendants = append(descendants, next...)
+		level = next
+	}
+	return descendants, nil
+}
+
+// Answers the chain of nodes that the node identified by the title or UUID is a member of, beginning with its parent
+// and ending with the root of the hierarchy.  Where a node is a member of more than one node, the first is followed.
+func (c *Client) Ancestors(titleOrUuid string) ([]*Member, error) {
+	node, err := c.Find(titleOrUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []*Member
+	seen := map[string]bool{node.Id: true}
+	for depth := 1; len(node.MemberOf) > 0; depth++ {
+		parentId := node.MemberOf[0]
+		if seen[parentId] {
+			return ancestors, fmt.Errorf("membership of '%s' forms a cycle at node %s", titleOrUuid, parentId)
+		}
+		seen[parentId] = true
+		if node, err = c.Find(parentId); err != nil {
+			return ancestors, err
+		}
+		node.Depth = depth
+		ancestors = append(ancestors, node)
+	}
+	return ancestors, nil
+}
+
+// Answers the nodes of every member bundle that are members of the node with the id, retrieving every page
+func (c *Client) members(id string, depth int) ([]*Member, error) {
+	var members []*Member
+	for _, bundle := range MemberBundles {
+		err := c.url(bundle, memberOf+".id", id).FetchPages(func(page *jsonapi.JsonApiPage) error {
+			for _, d := range page.Data {
+				members = append(members, newMember(d, depth))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+func (c *Client) url(bundle, filter, value string) *jsonapi.JsonApiUrl {
+	return &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: model.Node,
+		DrupalBundle: bundle,
+		Filter:       filter,
+		Value:        value,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+}
+
+// Creates a Member from a JSON API resource object
+func newMember(data map[string]interface{}, depth int) *Member {
+	m := &Member{Depth: depth}
+	m.Id, _ = data["id"].(string)
+	if t, ok := data["type"].(string); ok {
+		m.Bundle = jsonapi.DrupalType(t).Bundle()
+	}
+	attributes, _ := data["attributes"].(map[string]interface{})
+	m.Title, _ = attributes["title"].(string)
+
+	relationships, _ := data["relationships"].(map[string]interface{})
+	rel, _ := relationships[memberOf].(map[string]interface{})
+	switch refs := rel["data"].(type) {
+	case map[string]interface{}:
+		if id, ok := refs["id"].(string); ok {
+			m.MemberOf = append(m.MemberOf, id)
+		}
+	case []interface{}:
+		for _, ref := range refs {
+			if ref, ok := ref.(map[string]interface{}); ok {
+				if id, ok := ref["id"].(string); ok {
+					m.MemberOf = append(m.MemberOf, id)
+				}
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,118 @@
+package collection
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rootId  = "00000000-0000-4000-8000-000000000000"
+	childId = "00000000-0000-4000-8000-000000000001"
+)
+
+func node(bundle, id, title string, memberOf ...string) jsonapitest.Resource {
+	var refs []interface{}
+	for _, parent := range memberOf {
+		refs = append(refs, map[string]interface{}{"type": "node--collection_object", "id": parent})
+	}
+	return jsonapitest.Resource{"type": "node--" + bundle, "id": id, "attributes": map[string]interface{}{"title": title},
+		"relationships": map[string]interface{}{"field_member_of": map[string]interface{}{"data": refs}}}
+}
+
+// Ansel Adams Images
+//
+//	Moonrise
+//	Hernandez
+//	  Church
+//	  Cemetery (also a member of Ansel Adams Images)
+func newServer() *jsonapitest.MockServer {
+	m := jsonapitest.NewMockServer()
+	m.PageSize = 1
+	m.Add(
+		node("collection_object", rootId, "Ansel Adams Images"),
+		node("collection_object", childId, "Hernandez", rootId),
+		node("islandora_object", "o1", "Moonrise", rootId),
+		node("islandora_object", "o2", "Church", childId),
+		node("islandora_object", "o3", "Cemetery", childId, rootId),
+	)
+	return m
+}
+
+func titles(members []*Member) []string {
+	var titles []string
+	for _, m := range members {
+		titles = append(titles, m.Title)
+	}
+	return titles
+}
+
+func Test_Find(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	found, err := c.Find(childId)
+	require.Nil(t, err)
+	assert.Equal(t, &Member{Id: childId, Bundle: "collection_object", Title: "Hernandez", MemberOf: []string{rootId}}, found)
+
+	found, err = c.Find("Church")
+	require.Nil(t, err)
+	assert.Equal(t, "islandora_object", found.Bundle)
+
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "o5",
+		"attributes": map[string]interface{}{"title": "Moonset", "field_pid": "islandora:1234"}})
+	found, err = c.Find("info:fedora/islandora:1234")
+	require.Nil(t, err)
+	assert.Equal(t, "Moonset", found.Title)
+
+	_, err = c.Find("Moondance")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	m.Add(node("islandora_object", "o4", "Church"))
+	_, err = c.Find("Church")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+}
+
+func Test_Descendants(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	children, err := c.Children("Ansel Adams Images")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Hernandez", "Moonrise", "Cemetery"}, titles(children))
+
+	descendants, err := c.Descendants(rootId, 0)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Hernandez", "Moonrise", "Cemetery", "Church"}, titles(descendants))
+	assert.Equal(t, 2, descendants[3].Depth)
+
+	descendants, err = c.Descendants("Moonrise", 0)
+	require.Nil(t, err)
+	assert.Empty(t, descendants)
+}
+
+func Test_Ancestors(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	ancestors, err := c.Ancestors("Church")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Hernandez", "Ansel Adams Images"}, titles(ancestors))
+	assert.Equal(t, []int{1, 2}, []int{ancestors[0].Depth, ancestors[1].Depth})
+
+	ancestors, err = c.Ancestors(rootId)
+	require.Nil(t, err)
+	assert.Empty(t, ancestors)
+
+	// a cycle
+	m.Reset()
+	m.Add(node("collection_object", rootId, "A", childId), node("collection_object", childId, "B", rootId))
+	ancestors, err = c.Ancestors("A")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"B"}, titles(ancestors))
+}
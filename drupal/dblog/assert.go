@@ -0,0 +1,29 @@
+//go:build !notestify
+// +build !notestify
+
+package dblog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that no entry at least as severe as the threshold was logged since the window was started, answering the
+// offending entries so that they may be attached to a report
+func (w *Window) AssertNoneAtLeast(t assert.TestingT, threshold Severity) []Entry {
+	severe, err := w.AtLeast(threshold)
+	if !assert.Nil(t, err, "unable to retrieve dblog entries: %s", err) {
+		return nil
+	}
+	if len(severe) > 0 {
+		messages := make([]string, len(severe))
+		for i, e := range severe {
+			messages[i] = e.String()
+		}
+		assert.Fail(t, fmt.Sprintf("%d dblog entries of severity %s or greater were logged", len(severe), threshold),
+			strings.Join(messages, "\n"))
+	}
+	return severe
+}
@@ -0,0 +1,250 @@
+// Provides access to Drupal's database log (a.k.a. the watchdog), so that tests may assert that a migration or
+// verification run did not log errors or warnings.
+//
+// Drupal does not expose log entries through the JSON API, so entries are retrieved from a REST export view of the
+// `watchdog` table, which is expected to answer the most recent entries first, e.g.:
+//
+//	[{"wid": "1021", "type": "php", "message": "Warning: Undefined array key ...", "severity": "4", "timestamp": "1620750000"}]
+//
+// Numeric values may be rendered as JSON numbers or strings, and severity may be rendered as a number (0-7) or as its
+// label (e.g. "Warning").
+package dblog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// Path of the REST export view used when Client.Path is empty
+const DefaultPath = "/api/dblog"
+
+// The maximum number of pages retrieved when Client.MaxPages is not positive
+const defaultMaxPages = 20
+
+// The severity of a log entry, as defined by RFC 5424 and used by Drupal.  Lower values are more severe.
+type Severity int
+
+const (
+	Emergency Severity = iota
+	Alert
+	Critical
+	Error
+	Warning
+	Notice
+	Info
+	Debug
+)
+
+var severityLabels = []string{"emergency", "alert", "critical", "error", "warning", "notice", "info", "debug"}
+
+func (s Severity) String() string {
+	if s < Emergency || s > Debug {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityLabels[s]
+}
+
+// Accepts a severity rendered as a number, a numeric string, or a label
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	v, err := unquote(b)
+	if err != nil {
+		return err
+	}
+	for i, label := range severityLabels {
+		if strings.EqualFold(v, label) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("unknown dblog severity %s", b)
+	}
+	*s = Severity(n)
+	return nil
+}
+
+// A single entry of the database log
+type Entry struct {
+	// Identifies the entry; entries logged later have greater ids
+	Wid int64 `json:"wid"`
+	// The type (or channel) of the entry, e.g. `php` or `migrate`
+	Type     string   `json:"type"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+	// The time the entry was logged
+	Timestamp time.Time `json:"timestamp"`
+	// The URL of the request that logged the entry
+	Location string `json:"location"`
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("[%s] %s %s: %s", e.Severity, e.Timestamp.Format(time.RFC3339), e.Type, e.Message)
+}
+
+// Accepts ids and timestamps rendered as numbers or numeric strings.  Timestamps may also be rendered as RFC 3339.
+func (e *Entry) UnmarshalJSON(b []byte) error {
+	raw := struct {
+		Wid       json.RawMessage `json:"wid"`
+		Type      string          `json:"type"`
+		Message   string          `json:"message"`
+		Severity  Severity        `json:"severity"`
+		Timestamp json.RawMessage `json:"timestamp"`
+		Location  string          `json:"location"`
+	}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*e = Entry{Type: raw.Type, Message: raw.Message, Severity: raw.Severity, Location: raw.Location}
+	wid, err := unquote(raw.Wid)
+	if err != nil {
+		return err
+	}
+	if e.Wid, err = strconv.ParseInt(wid, 10, 64); err != nil {
+		return fmt.Errorf("invalid dblog wid %s: %w", raw.Wid, err)
+	}
+
+	if len(raw.Timestamp) > 0 {
+		ts, err := unquote(raw.Timestamp)
+		if err != nil {
+			return err
+		}
+		if secs, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			e.Timestamp = time.Unix(secs, 0)
+		} else if e.Timestamp, err = time.Parse(time.RFC3339, ts); err != nil {
+			return fmt.Errorf("invalid dblog timestamp %s: %w", raw.Timestamp, err)
+		}
+	}
+	return nil
+}
+
+// Retrieves entries of the database log from a REST export view
+type Client struct {
+	BaseUrl string
+	// The path of the REST export view; DefaultPath is used if empty
+	Path     string
+	Username string
+	Password env.Secret
+	// The maximum number of pages of the view retrieved by a single request for entries; 20 if not positive
+	MaxPages int
+}
+
+// Creates a Client for the view at DefaultPath.  If the username is not empty, requests are authenticated using HTTP
+// Basic Auth; Drupal usually requires the 'access site reports' permission to read the log.
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the entries logged after the entry identified by wid, most recent first.  Pages of the view are retrieved
+// until an entry with an id not greater than wid is found, the view is exhausted, or MaxPages pages are retrieved.
+func (c *Client) EntriesAfter(wid int64) ([]Entry, error) {
+	maxPages := c.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	var entries []Entry
+	for page := 0; page < maxPages; page++ {
+		pageEntries, err := c.page(page)
+		if err != nil {
+			return nil, err
+		}
+		if len(pageEntries) == 0 {
+			return entries, nil
+		}
+		for _, e := range pageEntries {
+			if e.Wid <= wid {
+				return entries, nil
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// Answers the id of the most recent entry, or 0 if the log is empty
+func (c *Client) LatestWid() (int64, error) {
+	entries, err := c.page(0)
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+	return entries[0].Wid, nil
+}
+
+// Retrieves a single page of the view
+func (c *Client) page(page int) ([]Entry, error) {
+	path := c.Path
+	if path == "" {
+		path = DefaultPath
+	}
+	u := fmt.Sprintf("%s/%s?_format=json&page=%d", strings.TrimSuffix(c.BaseUrl, "/"), strings.TrimPrefix(path, "/"), page)
+	_, body, err := jsonapi.FetchResource(u, c.Username, c.Password.Reveal())
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("error unmarshaling dblog entries from %s: %w", u, err)
+	}
+	return entries, nil
+}
+
+// Captures the entries logged between the start of a window (e.g. the start of a test) and the time the entries are
+// retrieved.  The window is delimited by entry id rather than time, so it is not affected by clock skew between the
+// test and Drupal.
+type Window struct {
+	client *Client
+	start  int64
+}
+
+// Starts a window, which captures the entries logged from now on
+func (c *Client) Start() (*Window, error) {
+	wid, err := c.LatestWid()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start dblog window: %w", err)
+	}
+	return &Window{client: c, start: wid}, nil
+}
+
+// Answers the entries logged since the window was started, most recent first
+func (w *Window) Entries() ([]Entry, error) {
+	return w.client.EntriesAfter(w.start)
+}
+
+// Answers the entries logged since the window was started that are at least as severe as the threshold, e.g.
+// Warning answers warnings, errors, and more severe entries
+func (w *Window) AtLeast(threshold Severity) ([]Entry, error) {
+	entries, err := w.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var severe []Entry
+	for _, e := range entries {
+		if e.Severity <= threshold {
+			severe = append(severe, e)
+		}
+	}
+	return severe, nil
+}
+
+// Answers the value of a JSON string or number
+func unquote(b []byte) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("expected a string or number, but found %s", b)
+}
@@ -0,0 +1,85 @@
+//go:build !notestify
+// +build !notestify
+
+package dblog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnmarshalEntry(t *testing.T) {
+	e := Entry{}
+	require.Nil(t, e.UnmarshalJSON([]byte(`{"wid": "12", "type": "php", "message": "Warning", "severity": "4", "timestamp": "1620750000"}`)))
+	assert.Equal(t, int64(12), e.Wid)
+	assert.Equal(t, Warning, e.Severity)
+	assert.Equal(t, time.Unix(1620750000, 0), e.Timestamp)
+
+	require.Nil(t, e.UnmarshalJSON([]byte(`{"wid": 13, "type": "migrate", "severity": "Error", "timestamp": "2021-05-11T16:20:00Z"}`)))
+	assert.Equal(t, int64(13), e.Wid)
+	assert.Equal(t, Error, e.Severity)
+	assert.Equal(t, 2021, e.Timestamp.Year())
+
+	assert.NotNil(t, e.UnmarshalJSON([]byte(`{"wid": "x"}`)))
+	assert.NotNil(t, e.UnmarshalJSON([]byte(`{"wid": 1, "severity": "loud"}`)))
+	assert.Equal(t, "warning", Warning.String())
+}
+
+func Test_Window(t *testing.T) {
+	entries := `[{"wid": 3, "type": "cron", "message": "Cron run completed.", "severity": 5, "timestamp": 1620750000}]`
+	// requests are recorded by the handler, and asserted on by the test, as the handler cannot stop the test
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path+" "+r.URL.Query().Get("_format"))
+		mu.Unlock()
+		switch r.URL.Query().Get("page") {
+		case "0":
+			w.Write([]byte(entries))
+		case "1":
+			w.Write([]byte(`[{"wid": 4, "severity": 3}, {"wid": 3, "severity": 5}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "")
+	window, err := c.Start()
+	require.Nil(t, err)
+
+	captured, err := window.Entries()
+	require.Nil(t, err)
+	assert.Empty(t, captured)
+	assert.Empty(t, window.AssertNoneAtLeast(t, Warning))
+
+	// a migration logs a warning and an error; the view answers them across two pages, most recent first
+	entries = `[{"wid": 6, "type": "php", "message": "Undefined index: title", "severity": 4}, {"wid": 5, "type": "migrate", "message": "Processed 4 items", "severity": 6}]`
+	captured, err = window.Entries()
+	require.Nil(t, err)
+	require.Equal(t, 3, len(captured))
+	assert.Equal(t, []int64{6, 5, 4}, []int64{captured[0].Wid, captured[1].Wid, captured[2].Wid})
+
+	rec := &asserttest.Recorder{}
+	severe := window.AssertNoneAtLeast(rec, Warning)
+	assert.Contains(t, rec.String(), "2 dblog entries of severity warning or greater were logged")
+	assert.Equal(t, 2, len(severe))
+	errors, err := window.AtLeast(Error)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(errors))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, requested)
+	for _, r := range requested {
+		assert.Equal(t, "/api/dblog json", r)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !notestify
+// +build !notestify
+
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the expected and actual values do not differ (see Compare), reporting each difference on a line of
+// its own, colored if Colorize answers true
+func Assert(t assert.TestingT, expected, actual interface{}, msgAndArgs ...interface{}) bool {
+	diffs, err := Compare(expected, actual)
+	if err != nil {
+		return assert.Fail(t, err.Error(), msgAndArgs...)
+	}
+	if len(diffs) == 0 {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("%d differences:\n%s", len(diffs), Format(diffs, Colorize())), msgAndArgs...)
+}
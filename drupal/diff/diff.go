@@ -0,0 +1,216 @@
+// Compares expected and actual values field by field, answering a Difference per differing field at its path, e.g.
+// `field_genre[1]: expected "Maps", got "Map"`, rather than the wall of text of assert.Equal on two large structs:
+//
+//	diff.Assert(t, expected, actual) // e.g. two *model.ExpectedRepoObj
+//
+// Values are compared as their JSON representations, so that paths are named by JSON keys, and structs, maps and
+// decoded JSON compare alike.  Empty values (null, "", [] and {}) are equal to absent values.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ANSI escapes coloring the values of a colorized Difference
+const (
+	red   = "\x1b[31m"
+	green = "\x1b[32m"
+	bold  = "\x1b[1m"
+	reset = "\x1b[0m"
+)
+
+// A value that differs between the expected and actual values
+type Difference struct {
+	// The location of the value, e.g. `field_genre[1]` or `model.name`; empty for the values themselves
+	Path string
+	// The expected value, as decoded from JSON; nil if absent
+	Expected interface{}
+	// The actual value, as decoded from JSON; nil if absent
+	Actual interface{}
+}
+
+// Answers the difference as e.g. `field_genre[1]: expected "Maps", got "Map"`
+func (d Difference) String() string {
+	return d.Format(false)
+}
+
+// Answers the difference as String does, coloring the expected value red and the actual value green if color is true
+func (d Difference) Format(color bool) string {
+	path, expected, actual := d.Path, Describe(d.Expected), Describe(d.Actual)
+	if path == "" {
+		path = "(value)"
+	}
+	if color {
+		path, expected, actual = bold+path+reset, red+expected+reset, green+actual+reset
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", path, expected, actual)
+}
+
+// Answers the differences between the expected and actual values, ordered by path.  Objects are compared key by key,
+// and arrays element by element, an array differing in length contributing a Difference per differing element.  An
+// error is answered if either value cannot be represented as JSON.
+func Compare(expected, actual interface{}) ([]Difference, error) {
+	e, err := decode(expected)
+	if err != nil {
+		return nil, fmt.Errorf("diff: expected value: %w", err)
+	}
+	a, err := decode(actual)
+	if err != nil {
+		return nil, fmt.Errorf("diff: actual value: %w", err)
+	}
+	var diffs []Difference
+	compare("", e, a, &diffs)
+	return diffs, nil
+}
+
+// Answers the differences, one per line, colored if color is true (see Colorize)
+func Format(diffs []Difference, color bool) string {
+	lines := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		lines = append(lines, d.Format(color))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Writes the differences, one per line, colored if color is true (see Colorize)
+func Write(w io.Writer, diffs []Difference, color bool) error {
+	for _, d := range diffs {
+		if _, err := fmt.Fprintln(w, d.Format(color)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Answers whether differences ought to be colored: false if the NO_COLOR environment variable is set, true if
+// FORCE_COLOR is set, and otherwise true only if standard output is a terminal
+func Colorize() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if _, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		return true
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Answers the value as JSON, or "nothing" if the value is absent
+func Describe(v interface{}) string {
+	if v == nil {
+		return "nothing"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// Answers the value as it would be decoded from its JSON representation
+func decode(v interface{}) (interface{}, error) {
+	if raw, ok := v.(json.RawMessage); ok {
+		v = []byte(raw)
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var decoded interface{}
+	return decoded, json.Unmarshal(b, &decoded)
+}
+
+func compare(path string, expected, actual interface{}, diffs *[]Difference) {
+	if isEmpty(expected) && isEmpty(actual) {
+		return
+	}
+	// an absent list or object is compared as an empty one, so that its elements are reported at their paths
+	if isEmpty(expected) {
+		expected = emptyLike(actual, expected)
+	} else if isEmpty(actual) {
+		actual = emptyLike(expected, actual)
+	}
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e)+len(a))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := e[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			compare(join(path, k), e[k], a[k], diffs)
+		}
+		return
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(e) || i < len(a); i++ {
+			compare(path+"["+strconv.Itoa(i)+"]", at(e, i), at(a, i), diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		*diffs = append(*diffs, Difference{Path: path, Expected: expected, Actual: actual})
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Answers the element at the index of the list, or nil if the list is shorter
+func at(list []interface{}, i int) interface{} {
+	if i < len(list) {
+		return list[i]
+	}
+	return nil
+}
+
+// Answers an empty value of the type of the list or object, or the empty value itself
+func emptyLike(v, empty interface{}) interface{} {
+	switch v.(type) {
+	case []interface{}:
+		return []interface{}{}
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	}
+	return empty
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+//go:build !notestify
+// +build !notestify
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Compare(t *testing.T) {
+	expected := &model.ExpectedRepoObj{Genre: []string{"Maps", "Photographs"}, Extent: []string{"1 photograph"}}
+	expected.Title = "Moonrise"
+	expected.Model.Name = "Image"
+	actual := &model.ExpectedRepoObj{Genre: []string{"Map"}, Subject: []string{"Photography"}, Extent: []string{"1 photograph"}}
+	actual.Title = "Moonrise"
+	actual.Model.Name = "Digital Document"
+
+	diffs, err := Compare(expected, actual)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		`genre[0]: expected "Maps", got "Map"`,
+		`genre[1]: expected "Photographs", got nothing`,
+		`model.name: expected "Image", got "Digital Document"`,
+		`subject[0]: expected nothing, got "Photography"`,
+	}, lines(diffs))
+
+	// structs, maps and JSON compare alike, and empty values equal absent values
+	diffs, err = Compare(json.RawMessage(`{"title": "Moonrise", "genre": [], "weight": 1}`),
+		map[string]interface{}{"title": "Moonrise", "weight": 1, "subject": nil})
+	require.Nil(t, err)
+	assert.Empty(t, diffs)
+	diffs, err = Compare([]byte(`{"weight": 1}`), struct {
+		Weight string `json:"weight"`
+	}{"1"})
+	require.Nil(t, err)
+	assert.Equal(t, []string{`weight: expected 1, got "1"`}, lines(diffs))
+
+	diffs, err = Compare("Moonrise", "Moonset")
+	require.Nil(t, err)
+	assert.Equal(t, []string{`(value): expected "Moonrise", got "Moonset"`}, lines(diffs))
+
+	_, err = Compare(func() {}, "Moonset")
+	assert.NotNil(t, err)
+	_, err = Compare([]byte(`{`), "Moonset")
+	assert.NotNil(t, err)
+}
+
+func Test_Format(t *testing.T) {
+	diffs := []Difference{{Path: "field_genre[1]", Expected: "maps", Actual: "map"}, {Path: "weight", Expected: 2.0}}
+	assert.Equal(t, "field_genre[1]: expected \"maps\", got \"map\"\nweight: expected 2, got nothing", Format(diffs, false))
+	assert.Equal(t, "\x1b[1mfield_genre[1]\x1b[0m: expected \x1b[31m\"maps\"\x1b[0m, got \x1b[32m\"map\"\x1b[0m", diffs[0].Format(true))
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, Write(buf, diffs, false))
+	assert.Equal(t, Format(diffs, false)+"\n", buf.String())
+}
+
+func Test_Assert(t *testing.T) {
+	assert.True(t, Assert(t, map[string]interface{}{"title": "Moonrise"}, []byte(`{"title": "Moonrise"}`)))
+	rec := &asserttest.Recorder{}
+	assert.False(t, Assert(rec, map[string]interface{}{"title": "Moonrise"}, map[string]interface{}{"title": "Moonset"}))
+	assert.Contains(t, rec.String(), `title: expected "Moonrise", got "Moonset"`)
+	rec = &asserttest.Recorder{}
+	assert.False(t, Assert(rec, func() {}, nil))
+	assert.Contains(t, rec.String(), "json: unsupported type: func()")
+}
+
+func Test_Colorize(t *testing.T) {
+	for _, k := range []string{"NO_COLOR", "FORCE_COLOR"} {
+		if prev, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, prev)
+		} else {
+			defer os.Unsetenv(k)
+		}
+		os.Unsetenv(k)
+	}
+	os.Setenv("FORCE_COLOR", "1")
+	assert.True(t, Colorize())
+	os.Setenv("NO_COLOR", "")
+	assert.False(t, Colorize())
+}
+
+func lines(diffs []Difference) []string {
+	var s []string
+	for _, d := range diffs {
+		s = append(s, d.String())
+	}
+	return s
+}
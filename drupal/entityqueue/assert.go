@@ -0,0 +1,34 @@
+//go:build !notestify
+// +build !notestify
+
+package entityqueue
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the item identified by its title, name, or UUID appears in the subqueue (see Client.Subqueue) at the
+// position, beginning at 1.  If position is 0, asserts only that the item appears.
+func AssertPosition(t assert.TestingT, c *Client, subqueue, titleOrUuid string, position int) bool {
+	q, err := c.Subqueue(subqueue)
+	if !assert.Nil(t, err, "unable to retrieve subqueue %s", subqueue) {
+		return false
+	}
+	actual := q.Position(titleOrUuid)
+	if !assert.NotZero(t, actual, "%s does not appear in subqueue %s: %v", titleOrUuid, subqueue, q.Titles()) {
+		return false
+	}
+	if position == 0 {
+		return true
+	}
+	return assert.Equal(t, position, actual, "position of %s in subqueue %s: %v", titleOrUuid, subqueue, q.Titles())
+}
+
+// Asserts that the item identified by its title, name, or UUID does not appear in the subqueue
+func AssertAbsent(t assert.TestingT, c *Client, subqueue, titleOrUuid string) bool {
+	q, err := c.Subqueue(subqueue)
+	if !assert.Nil(t, err, "unable to retrieve subqueue %s", subqueue) {
+		return false
+	}
+	return assert.Zero(t, q.Position(titleOrUuid), "%s appears in subqueue %s: %v", titleOrUuid, subqueue, q.Titles())
+}
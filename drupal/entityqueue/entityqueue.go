@@ -0,0 +1,149 @@
+// Retrieves the entity subqueues of the Entityqueue module, e.g. the ordered list of featured objects, so that tests
+// may verify that a migrated object appears in a queue, and at which position:
+//
+//	c := entityqueue.NewClient(env.BaseUrl(), username, password)
+//	q, err := c.Subqueue("featured_items")
+//	position := q.Position("Moonrise Over Hernandez") // 1 for the first item, 0 if absent
+//	entityqueue.AssertPosition(t, c, "featured_items", "Moonrise Over Hernandez", 1)
+//
+// A simple queue has a single subqueue, named after the queue.  The items of a subqueue are carried by its `items`
+// relationship, in queue order.
+package entityqueue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// No subqueue of the name exists
+var ErrNotFound = errors.New("entityqueue: no subqueue matches")
+
+// The relationship carrying the items of a subqueue
+const items = "items"
+
+// An item of a subqueue
+type Item struct {
+	// The type of the entity, e.g. `node--islandora_object`
+	Type string
+	Id   string
+	// The title or name of the entity, if it was retrieved
+	Title string
+}
+
+// An entity subqueue and its items, in queue order
+type Subqueue struct {
+	Id string
+	// The machine name of the subqueue, e.g. `featured_items`
+	Name string
+	// The machine name of the queue the subqueue belongs to
+	Queue string
+	Title string
+	Items []Item
+}
+
+// Answers the position of the item identified by its title, name, or UUID, beginning at 1, or 0 if the subqueue does
+// not carry the item
+func (q *Subqueue) Position(titleOrUuid string) int {
+	for i, item := range q.Items {
+		if item.Id == titleOrUuid || item.Title == titleOrUuid {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Answers the titles (or ids, if their titles were not retrieved) of the items, in queue order
+func (q *Subqueue) Titles() []string {
+	var titles []string
+	for _, item := range q.Items {
+		if item.Title != "" {
+			titles = append(titles, item.Title)
+		} else {
+			titles = append(titles, item.Id)
+		}
+	}
+	return titles
+}
+
+// Retrieves the entity subqueues of a Drupal site
+type Client struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the Drupal site at the base url.  If the username is not empty, requests are authenticated
+// using HTTP Basic Auth.
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the subqueue with the machine name (e.g. `featured_items`) of the queue of the same name, with the titles
+// of its items.  An error wrapping ErrNotFound is answered if the queue has no such subqueue.
+func (c *Client) Subqueue(name string) (*Subqueue, error) {
+	return c.SubqueueOf(name, name)
+}
+
+// Answers the subqueue with the machine name belonging to the queue, with the titles of its items.  The subqueues of
+// a queue are the bundle of the `entity_subqueue` entity type named after the queue.  An error wrapping ErrNotFound
+// is answered if the queue has no such subqueue.
+func (c *Client) SubqueueOf(queue, name string) (*Subqueue, error) {
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: "entity_subqueue",
+		DrupalBundle: queue,
+		Filter:       "name",
+		Value:        name,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+	var q *Subqueue
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			if q == nil {
+				q = newSubqueue(d, page, queue)
+			}
+		}
+		return nil
+	}, items)
+	if err != nil {
+		return nil, fmt.Errorf("entityqueue: error retrieving subqueue '%s' of '%s': %w", name, queue, err)
+	}
+	if q == nil {
+		return nil, fmt.Errorf("%w: '%s' of '%s'", ErrNotFound, name, queue)
+	}
+	return q, nil
+}
+
+// Answers the subqueue of the JSON API data element, resolving the titles of its items from the included resources
+func newSubqueue(d map[string]interface{}, page *jsonapi.JsonApiPage, queue string) *Subqueue {
+	q := &Subqueue{Queue: queue}
+	q.Id, _ = d["id"].(string)
+	attrs, _ := d["attributes"].(map[string]interface{})
+	q.Name, _ = attrs["name"].(string)
+	q.Title, _ = attrs["title"].(string)
+
+	rels, _ := d["relationships"].(map[string]interface{})
+	rel, _ := rels[items].(map[string]interface{})
+	refs, _ := rel["data"].([]interface{})
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		item := Item{}
+		item.Type, _ = ref["type"].(string)
+		item.Id, _ = ref["id"].(string)
+		if related := page.Related(ref); related != nil {
+			relatedAttrs, _ := related["attributes"].(map[string]interface{})
+			if item.Title, _ = relatedAttrs["title"].(string); item.Title == "" {
+				item.Title, _ = relatedAttrs["name"].(string)
+			}
+		}
+		q.Items = append(q.Items, item)
+	}
+	return q
+}
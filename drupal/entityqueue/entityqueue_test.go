@@ -0,0 +1,72 @@
+//go:build !notestify
+// +build !notestify
+
+package entityqueue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer() *jsonapitest.MockServer {
+	m := jsonapitest.NewMockServer()
+	m.Add(
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "o1", "attributes": map[string]interface{}{"title": "Moonrise"}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "o2", "attributes": map[string]interface{}{"title": "Church"}},
+		jsonapitest.Resource{"type": "node--collection_object", "id": "c1", "attributes": map[string]interface{}{"title": "Ansel Adams Images"}},
+		jsonapitest.Resource{"type": "entity_subqueue--featured_items", "id": "q1",
+			"attributes": map[string]interface{}{"name": "featured_items", "title": "Featured Items"},
+			"relationships": map[string]interface{}{"items": map[string]interface{}{"data": []interface{}{
+				map[string]interface{}{"type": "node--islandora_object", "id": "o2"},
+				map[string]interface{}{"type": "node--collection_object", "id": "c1"},
+				map[string]interface{}{"type": "node--islandora_object", "id": "o1"},
+			}}}},
+	)
+	return m
+}
+
+func Test_Subqueue(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	q, err := c.Subqueue("featured_items")
+	require.Nil(t, err)
+	assert.Equal(t, "Featured Items", q.Title)
+	assert.Equal(t, []string{"Church", "Ansel Adams Images", "Moonrise"}, q.Titles())
+	assert.Equal(t, Item{Type: "node--islandora_object", Id: "o1", Title: "Moonrise"}, q.Items[2])
+	assert.Equal(t, 3, q.Position("Moonrise"))
+	assert.Equal(t, 2, q.Position("c1"))
+	assert.Equal(t, 0, q.Position("Hernandez"))
+
+	_, err = c.Subqueue("carousel")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func Test_AssertPosition(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	assert.True(t, AssertPosition(t, c, "featured_items", "Church", 1))
+	assert.True(t, AssertPosition(t, c, "featured_items", "Moonrise", 0))
+	assert.True(t, AssertAbsent(t, c, "featured_items", "Hernandez"))
+
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertPosition(rec, c, "featured_items", "Church", 2))
+	assert.Contains(t, rec.String(), "position of Church in subqueue featured_items")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertPosition(rec, c, "featured_items", "Hernandez", 0))
+	assert.Contains(t, rec.String(), "Hernandez does not appear in subqueue featured_items")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertAbsent(rec, c, "featured_items", "Moonrise"))
+	assert.Contains(t, rec.String(), "Moonrise appears in subqueue featured_items")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertAbsent(rec, c, "carousel", "Moonrise"))
+	assert.Contains(t, rec.String(), "unable to retrieve subqueue carousel")
+}
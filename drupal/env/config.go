@@ -0,0 +1,167 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidConfig = errors.New("env: invalid configuration")
+
+// The configuration of a test suite, loaded once from the environment (see LoadConfig) and handed to the packages that
+// need it (e.g. jsonapi.UseConfig), rather than each package reading environment variables itself
+type Config struct {
+	// The base url of Drupal, from 'DRUPAL_BASE_URL'; required
+	BaseUrl string
+	// The base url of the test assets server, from 'BASE_ASSETS_URL'
+	AssetsUrl string
+	// The name (not path) of the base directory of the test suite, from 'DRUPAL_TEST_BASEDIR'
+	TestBasedir string
+	// The Drupal user to authenticate as, from 'DRUPAL_USERNAME' and 'DRUPAL_PASSWORD', or from the files named by
+	// 'DRUPAL_USERNAME_FILE' and 'DRUPAL_PASSWORD_FILE' (see GetSecret)
+	Username string
+	Password Secret
+	// The time limit of each request to Drupal, from 'DRUPAL_TIMEOUT' (e.g. `30s`); zero means no limit
+	Timeout time.Duration
+	// Skips verification of Drupal's certificate, from 'DRUPAL_INSECURE'
+	Insecure bool
+	// Resolves remote video embed URLs using their oEmbed provider, from 'VERIFY_OEMBED'
+	VerifyOembed bool
+	// The maximum size, in bytes, of a response body read from Drupal, from 'DRUPAL_MAX_RESPONSE_BYTES'; zero uses
+	// the default of the reader, and a negative value means no limit
+	MaxResponseBytes int
+}
+
+// Overrides a value of the Config loaded from the environment
+type Option func(c *Config)
+
+// Overrides the base url of Drupal
+func WithBaseUrl(baseUrl string) Option {
+	return func(c *Config) { c.BaseUrl = baseUrl }
+}
+
+// Overrides the base url of the test assets server
+func WithAssetsUrl(assetsUrl string) Option {
+	return func(c *Config) { c.AssetsUrl = assetsUrl }
+}
+
+// Overrides the name of the base directory of the test suite
+func WithTestBasedir(dir string) Option {
+	return func(c *Config) { c.TestBasedir = dir }
+}
+
+// Overrides the Drupal user to authenticate as
+func WithCredentials(username, password string) Option {
+	return func(c *Config) { c.Username, c.Password = username, Secret(password) }
+}
+
+// Overrides the time limit of each request to Drupal
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) { c.Timeout = timeout }
+}
+
+// Overrides whether Drupal's certificate is verified
+func WithInsecure(insecure bool) Option {
+	return func(c *Config) { c.Insecure = insecure }
+}
+
+// Loads the Config from the environment variables documented by its fields (or the active Profile, for those unset),
+// applies the options in order, and validates the result.  Unlike the accessors of this package, LoadConfig answers an error rather than panicking if a
+// variable cannot be parsed or a required value is missing:
+//
+//	c, err := env.LoadConfig(env.WithTimeout(time.Minute))
+func LoadConfig(opts ...Option) (*Config, error) {
+	c := &Config{
+		BaseUrl:     GetEnvOr(drupalBaseUrl, ""),
+		AssetsUrl:   GetEnvOr(assetsBaseUrl, ""),
+		TestBasedir: GetEnvOr(testBasedir, ""),
+	}
+	var problems []string
+	if _, _, err := ActiveProfile(); err != nil {
+		problems = append(problems, strings.TrimPrefix(err.Error(), "env: "))
+	}
+	var err error
+	if c.Username, err = GetSecret(username); err != nil {
+		problems = append(problems, strings.TrimPrefix(err.Error(), "env: "))
+	}
+	p, err := GetSecret(password)
+	if err != nil {
+		problems = append(problems, strings.TrimPrefix(err.Error(), "env: "))
+	}
+	c.Password = Secret(p)
+	d, err := GetEnvOrDuration(timeout, 0)
+	if err != nil {
+		problems = append(problems, strings.TrimPrefix(err.Error(), "env: "))
+	}
+	c.Timeout = d
+	for _, b := range []struct {
+		envVar string
+		dest   *bool
+	}{{insecure, &c.Insecure}, {verifyOembed, &c.VerifyOembed}} {
+		if v, ok := getEnv(b.envVar, false); ok {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", b.envVar, err))
+			}
+			*b.dest = parsed
+		}
+	}
+	if v, ok := getEnv(maxResponse, false); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", maxResponse, err))
+		}
+		c.MaxResponseBytes = n
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Answers an error wrapping ErrInvalidConfig and describing each problem of the Config: a missing base url, urls that
+// are not absolute http or https urls, a password without a username, or a negative timeout
+func (c *Config) Validate() error {
+	var problems []string
+	if c.BaseUrl == "" {
+		problems = append(problems, fmt.Sprintf("the base url (%s) is required", drupalBaseUrl))
+	} else if err := checkUrl(c.BaseUrl); err != nil {
+		problems = append(problems, fmt.Sprintf("the base url (%s) %s", drupalBaseUrl, err))
+	}
+	if c.AssetsUrl != "" {
+		if err := checkUrl(c.AssetsUrl); err != nil {
+			problems = append(problems, fmt.Sprintf("the assets url (%s) %s", assetsBaseUrl, err))
+		}
+	}
+	if c.Username == "" && c.Password != "" {
+		problems = append(problems, fmt.Sprintf("a password (%s) is supplied without a username (%s)", password, username))
+	}
+	if c.Timeout < 0 {
+		problems = append(problems, fmt.Sprintf("the timeout (%s) must not be negative", timeout))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func checkUrl(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("cannot be parsed: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("'%s' is not an absolute http or https url", s)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sets the environment variables for the duration of the test
+func setenv(t *testing.T, vars map[string]string) {
+	for k, v := range vars {
+		k := k
+		prev, ok := os.LookupEnv(k)
+		require.Nil(t, os.Setenv(k, v))
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func Test_LoadConfig(t *testing.T) {
+	setenv(t, map[string]string{
+		drupalBaseUrl: "https://islandora-idc.traefik.me",
+		assetsBaseUrl: "http://assets",
+		username:      "admin",
+		password:      "secret",
+		timeout:       "30s",
+		insecure:      "true",
+		maxResponse:   "1024",
+	})
+
+	c, err := LoadConfig()
+	require.Nil(t, err)
+	assert.Equal(t, &Config{
+		BaseUrl:          "https://islandora-idc.traefik.me",
+		AssetsUrl:        "http://assets",
+		Username:         "admin",
+		Password:         "secret",
+		Timeout:          30 * time.Second,
+		Insecure:         true,
+		MaxResponseBytes: 1024,
+	}, c)
+
+	// options override the environment, in order
+	c, err = LoadConfig(WithBaseUrl("http://localhost"), WithCredentials("", ""), WithTimeout(time.Minute),
+		WithInsecure(false), WithTestBasedir("tests"), WithBaseUrl("http://drupal"))
+	require.Nil(t, err)
+	assert.Equal(t, "http://drupal", c.BaseUrl)
+	assert.Equal(t, "", c.Username)
+	assert.Equal(t, time.Minute, c.Timeout)
+	assert.False(t, c.Insecure)
+	assert.Equal(t, "tests", c.TestBasedir)
+}
+
+func Test_LoadConfigInvalid(t *testing.T) {
+	setenv(t, map[string]string{timeout: "soon", insecure: "maybe"})
+	_, err := LoadConfig()
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+	assert.Contains(t, err.Error(), timeout)
+	assert.Contains(t, err.Error(), insecure)
+
+	os.Unsetenv(timeout)
+	os.Unsetenv(insecure)
+	os.Unsetenv(drupalBaseUrl)
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "the base url (DRUPAL_BASE_URL) is required")
+
+	_, err = LoadConfig(WithBaseUrl("http://drupal"))
+	assert.Nil(t, err)
+}
+
+func Test_ConfigValidate(t *testing.T) {
+	assert.Nil(t, (&Config{BaseUrl: "https://drupal", AssetsUrl: "http://assets:8080"}).Validate())
+
+	err := (&Config{BaseUrl: "drupal", AssetsUrl: "ftp://assets", Password: "secret", Timeout: -1}).Validate()
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+	assert.Equal(t, "env: invalid configuration: the base url (DRUPAL_BASE_URL) 'drupal' is not an absolute http or "+
+		"https url; the assets url (BASE_ASSETS_URL) 'ftp://assets' is not an absolute http or https url; a password "+
+		"(DRUPAL_PASSWORD) is supplied without a username (DRUPAL_USERNAME); the timeout (DRUPAL_TIMEOUT) must not be "+
+		"negative", err.Error())
+}
@@ -0,0 +1,190 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// The file read by LoadDotEnv if no path is supplied
+const DotEnvFile = ".env"
+
+var dotEnvKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// Reads the variables of each .env file into the process environment, so that local development needs no exports
+// before running the tests.  LoadDotEnv ought to be invoked before the Config is loaded, e.g. from TestMain:
+//
+//	if err := env.LoadDotEnv(); err != nil {
+//		log.Fatal(err)
+//	}
+//	c, err := env.LoadConfig()
+//
+// If no path is supplied, DotEnvFile is read if it exists.  Variables already set in the environment are not replaced,
+// and neither are variables set by an earlier file, so the environment of CI overrides a developer's .env file.  Each
+// line of a file is blank, a comment beginning with `#`, or a `KEY=value` assignment, optionally preceded by `export`:
+//
+//	# comments and blank lines are ignored
+//	DRUPAL_BASE_URL=https://islandora-idc.traefik.me   # so is a comment following an unquoted value
+//	DRUPAL_PASSWORD='pa$$word'                          # single quotes are literal
+//	GREETING="Hello,\n${DRUPAL_USERNAME:-world}"        # double quotes are escaped and expanded, and may span lines
+//	export DRUPAL_USERNAME=admin
+//
+// Unquoted and double-quoted values expand `$VAR` and `${VAR}` using the environment, including the variables set by
+// earlier lines, and `${VAR:-default}` if VAR is unset or empty.
+func LoadDotEnv(paths ...string) error {
+	if len(paths) == 0 {
+		if _, err := os.Stat(DotEnvFile); os.IsNotExist(err) {
+			return nil
+		}
+		paths = []string{DotEnvFile}
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("env: unable to read %s: %w", path, err)
+		}
+		err = parseDotEnv(f, func(key, value string) error {
+			if _, ok := os.LookupEnv(key); ok {
+				return nil
+			}
+			return os.Setenv(key, value)
+		})
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("env: %s:%w", path, err)
+		}
+	}
+	return nil
+}
+
+// Parses the assignments of the .env file, invoking set with each in turn.  Errors are prefixed by their line number.
+func parseDotEnv(r io.Reader, set func(key, value string) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		start := lineNo
+		if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "export\t") {
+			line = strings.TrimSpace(line[len("export"):])
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return fmt.Errorf("%d: expected KEY=value, found '%s'", start, line)
+		}
+		key, raw := strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
+		if !dotEnvKey.MatchString(key) {
+			return fmt.Errorf("%d: invalid variable name '%s'", start, key)
+		}
+
+		var value string
+		switch {
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				return fmt.Errorf("%d: unterminated single-quoted value of %s", start, key)
+			}
+			value = raw[1 : end+1]
+			if err := checkTrailing(raw[end+2:]); err != nil {
+				return fmt.Errorf("%d: %s", start, err)
+			}
+		case strings.HasPrefix(raw, `"`):
+			// a double-quoted value continues on the following lines until its closing quote
+			quoted := raw[1:]
+			end := closingQuote(quoted)
+			for end < 0 && scanner.Scan() {
+				lineNo++
+				quoted += "\n" + scanner.Text()
+				end = closingQuote(quoted)
+			}
+			if end < 0 {
+				return fmt.Errorf("%d: unterminated double-quoted value of %s", start, key)
+			}
+			if err := checkTrailing(quoted[end+1:]); err != nil {
+				return fmt.Errorf("%d: %s", lineNo, err)
+			}
+			value = expand(unescape(quoted[:end]))
+		default:
+			if i := strings.Index(raw, " #"); i >= 0 {
+				raw = raw[:i]
+			} else if i := strings.Index(raw, "\t#"); i >= 0 {
+				raw = raw[:i]
+			}
+			value = expand(strings.TrimSpace(raw))
+		}
+		if err := set(key, value); err != nil {
+			return fmt.Errorf("%d: %w", start, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Answers the index of the first unescaped double quote of s, or -1
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// Answers an error unless the remainder of a line following a quoted value is empty or a comment
+func checkTrailing(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("unexpected '%s' following quoted value", rest)
+	}
+	return nil
+}
+
+// Replaces the escape sequences of a double-quoted value.  An escaped `$` is protected from expansion.
+func unescape(s string) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '$':
+			b.WriteString(escapedDollar)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// Stands in for an escaped `$` until expansion is done
+const escapedDollar = "\x00"
+
+// Expands `$VAR`, `${VAR}` and `${VAR:-default}` using the environment
+func expand(s string) string {
+	expanded := os.Expand(s, func(name string) string {
+		if i := strings.Index(name, ":-"); i >= 0 {
+			if v := os.Getenv(name[:i]); v != "" {
+				return v
+			}
+			return name[i+2:]
+		}
+		return os.Getenv(name)
+	})
+	return strings.ReplaceAll(expanded, escapedDollar, "$")
+}
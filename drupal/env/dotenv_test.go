@@ -0,0 +1,88 @@
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseDotEnv(t *testing.T) {
+	os.Setenv("IDC_DOTENV_HOST", "drupal")
+	defer os.Unsetenv("IDC_DOTENV_HOST")
+	vars := map[string]string{}
+	set := func(key, value string) error {
+		vars[key] = value
+		return os.Setenv(key, value)
+	}
+	defer func() {
+		for k := range vars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	err := parseDotEnv(strings.NewReader(`
+# a comment
+IDC_DOTENV_URL=https://${IDC_DOTENV_HOST}/jsonapi   # trailing comment
+export IDC_DOTENV_USER = admin
+IDC_DOTENV_LITERAL='pa$$word # not a comment'
+IDC_DOTENV_QUOTED="Hello,\n\"$IDC_DOTENV_USER\" \$HOME"
+IDC_DOTENV_DEFAULT=${IDC_DOTENV_UNSET:-fallback}
+IDC_DOTENV_MULTI="first
+second"
+IDC_DOTENV_EMPTY=
+`), set)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"IDC_DOTENV_URL":     "https://drupal/jsonapi",
+		"IDC_DOTENV_USER":    "admin",
+		"IDC_DOTENV_LITERAL": "pa$$word # not a comment",
+		"IDC_DOTENV_QUOTED":  "Hello,\n\"admin\" $HOME",
+		"IDC_DOTENV_DEFAULT": "fallback",
+		"IDC_DOTENV_MULTI":   "first\nsecond",
+		"IDC_DOTENV_EMPTY":   "",
+	}, vars)
+
+	for input, msg := range map[string]string{
+		"\nNO_EQUALS":           "2: expected KEY=value",
+		"1BAD=x":                "1: invalid variable name '1BAD'",
+		"A='unterminated":       "1: unterminated single-quoted value of A",
+		"A=\"unterminated\nB=c": "1: unterminated double-quoted value of A",
+		"A=\"x\" y":             "1: unexpected 'y' following quoted value",
+	} {
+		err := parseDotEnv(strings.NewReader(input), set)
+		if assert.NotNil(t, err, input) {
+			assert.Contains(t, err.Error(), msg)
+		}
+	}
+}
+
+func Test_LoadDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.env"), filepath.Join(dir, ".env")
+	require.Nil(t, ioutil.WriteFile(first, []byte("IDC_DOTENV_A=first\nIDC_DOTENV_B=first\n"), 0644))
+	require.Nil(t, ioutil.WriteFile(second, []byte("IDC_DOTENV_B=second\nIDC_DOTENV_C=second\n"), 0644))
+	os.Setenv("IDC_DOTENV_A", "process")
+	for _, k := range []string{"IDC_DOTENV_A", "IDC_DOTENV_B", "IDC_DOTENV_C"} {
+		defer os.Unsetenv(k)
+	}
+
+	require.Nil(t, LoadDotEnv(first, second))
+	assert.Equal(t, "process", os.Getenv("IDC_DOTENV_A"))
+	assert.Equal(t, "first", os.Getenv("IDC_DOTENV_B"))
+	assert.Equal(t, "second", os.Getenv("IDC_DOTENV_C"))
+
+	err := LoadDotEnv(filepath.Join(dir, "missing.env"))
+	assert.NotNil(t, err)
+
+	// the default file is optional
+	wd, err := os.Getwd()
+	require.Nil(t, err)
+	defer os.Chdir(wd)
+	require.Nil(t, os.Chdir(t.TempDir()))
+	assert.Nil(t, LoadDotEnv())
+}
@@ -1,19 +1,32 @@
 // Provides access to environment variables used by IDC
+//
+// Accessors that panic when a variable is unset or malformed (e.g. BaseUrl or GetEnvOrInt) each have a variant
+// suffixed with E that answers an error instead (e.g. BaseUrlE or GetEnvOrIntE).  Accessors with no panicking variant,
+// like GetEnvOrDuration, GetEnvOrURL and GetSecret, answer an error and carry no suffix.
 package env
 
 import (
 	"fmt"
+	"net/url"
 	"os"
-	"strconv"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/logging"
 )
 
 const (
 	drupalBaseUrl = "DRUPAL_BASE_URL"
 	testBasedir   = "DRUPAL_TEST_BASEDIR"
 	assetsBaseUrl = "BASE_ASSETS_URL"
+	verifyOembed  = "VERIFY_OEMBED"
+	username      = "DRUPAL_USERNAME"
+	password      = "DRUPAL_PASSWORD"
+	maxResponse   = "DRUPAL_MAX_RESPONSE_BYTES"
+	timeout       = "DRUPAL_TIMEOUT"
+	insecure      = "DRUPAL_INSECURE"
 )
 
-// Answers the base url of Drupal from the environment variable 'DRUPAL_BASE_URL', or panics
+// Answers the base url of Drupal from the environment variable 'DRUPAL_BASE_URL', or panics (see BaseUrlE)
 func BaseUrl() string {
 	return requireEnv(drupalBaseUrl)
 }
@@ -23,6 +36,18 @@ func BaseUrlOr(defaultValue string) string {
 	return GetEnvOr(drupalBaseUrl, defaultValue)
 }
 
+// Answers the name of the Drupal user to authenticate as from the environment variable 'DRUPAL_USERNAME' (or the file
+// named by 'DRUPAL_USERNAME_FILE'), or returns the default value if unset.  Panics if the file cannot be read.
+func UsernameOr(defaultValue string) string {
+	return GetSecretOr(username, defaultValue)
+}
+
+// Answers the password of the Drupal user to authenticate as from the environment variable 'DRUPAL_PASSWORD' (or the file
+// named by 'DRUPAL_PASSWORD_FILE'), or returns the default value if unset.  Panics if the file cannot be read.
+func PasswordOr(defaultValue string) string {
+	return GetSecretOr(password, defaultValue)
+}
+
 // Answers the name (not path) of the base directory for the test suite from the environment variable
 // 'DRUPAL_TEST_BASEDIR', or panics
 func TestBasedir() string {
@@ -47,6 +72,18 @@ func AssetsBaseUrlOr(defaultValue string) string {
 	return GetEnvOr(assetsBaseUrl, defaultValue)
 }
 
+// Answers whether remote video embed URLs ought to be resolved using their oEmbed provider from the environment variable
+// 'VERIFY_OEMBED', or returns the default value if unset.  Panics if the value cannot be parsed as a bool.
+func VerifyOembedOr(defaultValue bool) bool {
+	return GetEnvOrBool(verifyOembed, defaultValue)
+}
+
+// Answers the maximum size, in bytes, of a response body read from Drupal from the environment variable
+// 'DRUPAL_MAX_RESPONSE_BYTES', or returns the default value if unset.  Panics if the value cannot be parsed as an integer.
+func MaxResponseBytesOr(defaultValue int) int {
+	return GetEnvOrInt(maxResponse, defaultValue)
+}
+
 // Answers the value of the supplied environment variable, or the default value if unset
 func GetEnvOr(envVar, defValue string) string {
 	if val, ok := getEnv(envVar, false); ok {
@@ -57,31 +94,55 @@ func GetEnvOr(envVar, defValue string) string {
 }
 
 // Answers the value of the supplied environment variable as an integer, or the default value if unset.  This function
-// will panic if the value of the environment variable cannot be parsed as an integer.
+// will panic if the value of the environment variable cannot be parsed as an integer (see GetEnvOrIntE).
 func GetEnvOrInt(envVar string, defValue int) int {
-	if val, ok := getEnv(envVar, false); ok {
-		if intval, err := strconv.Atoi(val); err != nil {
-			panic(fmt.Errorf("env: error formatting the value of environment variable '%s' as an integer: %w", envVar, err))
-		} else {
-			return intval
-		}
-	} else {
-		return defValue
+	intval, err := GetEnvOrIntE(envVar, defValue)
+	if err != nil {
+		panic(err)
 	}
+	return intval
 }
 
 // Answers the value of the supplied environment variable, or the default value if unset.  This function
-// will panic if the value of the environment variable cannot be parsed as a bool.
+// will panic if the value of the environment variable cannot be parsed as a bool (see GetEnvOrBoolE).
 func GetEnvOrBool(envVar string, defValue bool) bool {
-	if val, ok := getEnv(envVar, false); ok {
-		if boolval, err := strconv.ParseBool(val); err != nil {
-			panic(fmt.Errorf("env: error formatting the value of environment variable '%s' as a bool: %w", envVar, err))
-		} else {
-			return boolval
+	boolval, err := GetEnvOrBoolE(envVar, defValue)
+	if err != nil {
+		panic(err)
+	}
+	return boolval
+}
+
+// Answers the value of the supplied environment variable as a duration, e.g. `30s` or `5m` (see time.ParseDuration),
+// or the default value if unset.  Unlike GetEnvOrInt, an error is answered rather than a panic if the value cannot be
+// parsed.
+func GetEnvOrDuration(envVar string, defValue time.Duration) (time.Duration, error) {
+	val, ok := getEnv(envVar, false)
+	if !ok {
+		return defValue, nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return defValue, fmt.Errorf("env: error parsing the value of environment variable '%s' as a duration: %w", envVar, err)
+	}
+	return d, nil
+}
+
+// Answers the value of the supplied environment variable as an absolute http or https url, or the default value if
+// unset, e.g. `https://islandora-idc.traefik.me`.  Nil is answered if the variable is unset and the default is empty.
+// Unlike GetEnvOrInt, an error is answered rather than a panic if the url cannot be parsed or lacks a scheme or host.
+func GetEnvOrURL(envVar, defValue string) (*url.URL, error) {
+	val, ok := getEnv(envVar, false)
+	if !ok {
+		if defValue == "" {
+			return nil, nil
 		}
-	} else {
-		return defValue
+		val = defValue
+	}
+	if err := checkUrl(val); err != nil {
+		return nil, fmt.Errorf("env: the value of environment variable '%s' %w", envVar, err)
 	}
+	return url.Parse(val)
 }
 
 // Answers the value for the supplied environment variable, or panics
@@ -90,12 +151,18 @@ func requireEnv(envVar string) string {
 	return val
 }
 
-// Answers the value for the supplied environment variable, or panics if `require` is true
+// Answers the value for the supplied environment variable, or the value of the active Profile if it is unset, or panics
+// if `require` is true
 func getEnv(envVar string, require bool) (val string, ok bool) {
 	if val, ok = os.LookupEnv(envVar); !ok {
+		if val, ok = profileValue(envVar); ok {
+			return
+		}
 		if require {
+			logging.Errorf("env: missing required environment variable: %s", envVar)
 			panic(fmt.Sprintf("env: missing required environment variable: %s", envVar))
 		}
+		logging.Debugf("env: %s is unset; using the default value", envVar)
 	}
 
 	return
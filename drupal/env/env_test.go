@@ -0,0 +1,50 @@
+package env
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetEnvOrDuration(t *testing.T) {
+	d, err := GetEnvOrDuration("IDC_TEST_DURATION", time.Minute)
+	require.Nil(t, err)
+	assert.Equal(t, time.Minute, d)
+
+	setenv(t, map[string]string{"IDC_TEST_DURATION": "90s"})
+	d, err = GetEnvOrDuration("IDC_TEST_DURATION", time.Minute)
+	require.Nil(t, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	os.Setenv("IDC_TEST_DURATION", "30")
+	d, err = GetEnvOrDuration("IDC_TEST_DURATION", time.Minute)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "env: error parsing the value of environment variable 'IDC_TEST_DURATION' as a duration")
+	assert.Equal(t, time.Minute, d)
+}
+
+func Test_GetEnvOrURL(t *testing.T) {
+	u, err := GetEnvOrURL("IDC_TEST_URL", "")
+	require.Nil(t, err)
+	assert.Nil(t, u)
+
+	u, err = GetEnvOrURL("IDC_TEST_URL", "https://islandora-idc.traefik.me")
+	require.Nil(t, err)
+	assert.Equal(t, "islandora-idc.traefik.me", u.Host)
+
+	setenv(t, map[string]string{"IDC_TEST_URL": "http://assets:8080/files"})
+	u, err = GetEnvOrURL("IDC_TEST_URL", "https://islandora-idc.traefik.me")
+	require.Nil(t, err)
+	assert.Equal(t, "assets:8080", u.Host)
+	assert.Equal(t, "/files", u.Path)
+
+	for _, bad := range []string{"islandora-idc.traefik.me", "ftp://assets", "https://", "http://%zz"} {
+		os.Setenv("IDC_TEST_URL", bad)
+		_, err = GetEnvOrURL("IDC_TEST_URL", "")
+		assert.NotNil(t, err, bad)
+	}
+	assert.Contains(t, err.Error(), "env: the value of environment variable 'IDC_TEST_URL' cannot be parsed")
+}
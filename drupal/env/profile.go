@@ -0,0 +1,94 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+)
+
+// The environment variable selecting the Profile supplying the defaults of the other variables, e.g. `ci`
+const ProfileEnv = "IDC_PROFILE"
+
+// Default values of environment variables, keyed by variable name (e.g. 'DRUPAL_BASE_URL'), selected as a bundle by
+// ProfileEnv.  A variable set in the environment overrides the value of the profile, so a single variable may be
+// changed without defining a new profile.
+type Profile map[string]string
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[string]Profile{
+		// a development stack on the workstation, i.e. isle-dc with its self-signed certificate; credentials are
+		// supplied by the environment or a secret file (see GetSecret)
+		"local": {
+			drupalBaseUrl: "https://islandora-idc.traefik.me",
+			insecure:      "true",
+			timeout:       "30s",
+		},
+		// the stack started by a CI job; credentials are supplied by the job
+		"ci": {
+			drupalBaseUrl: "https://islandora-idc.traefik.me",
+			insecure:      "true",
+			timeout:       "2m",
+		},
+		// a shared environment with a trusted certificate; its urls and credentials are supplied by the environment
+		"staging": {
+			insecure: "false",
+			timeout:  "1m",
+		},
+	}
+)
+
+// Registers the profile under the name, replacing any profile of that name, e.g. so that a test suite may define the
+// urls of its staging environment
+func RegisterProfile(name string, p Profile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	profiles[name] = p
+}
+
+// Answers the names of the registered profiles, sorted
+func Profiles() []string {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Answers the profile named by ProfileEnv and its name, or an empty name and a nil profile if ProfileEnv is unset.  An
+// error is answered if ProfileEnv names no registered profile.
+func ActiveProfile() (string, Profile, error) {
+	name, ok := os.LookupEnv(ProfileEnv)
+	if !ok || name == "" {
+		return "", nil, nil
+	}
+	profilesMu.RLock()
+	p, ok := profiles[name]
+	profilesMu.RUnlock()
+	if !ok {
+		return name, nil, fmt.Errorf("env: unknown profile '%s' named by %s; expected one of %s", name, ProfileEnv,
+			strings.Join(Profiles(), ", "))
+	}
+	return name, p, nil
+}
+
+// Answers the value of the variable supplied by the active profile, if any
+func profileValue(envVar string) (string, bool) {
+	name, p, err := ActiveProfile()
+	if err != nil {
+		logging.Warnf("%s", err)
+		return "", false
+	}
+	if val, ok := p[envVar]; ok {
+		logging.Debugf("env: %s is unset; using the value of profile %s", envVar, name)
+		return val, true
+	}
+	return "", false
+}
@@ -0,0 +1,48 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Profile(t *testing.T) {
+	for _, k := range []string{drupalBaseUrl, username, password, timeout, insecure} {
+		if v, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, v)
+			os.Unsetenv(k)
+		}
+	}
+	setenv(t, map[string]string{ProfileEnv: "ci", timeout: "5m"})
+
+	name, p, err := ActiveProfile()
+	require.Nil(t, err)
+	assert.Equal(t, "ci", name)
+	assert.Equal(t, "2m", p[timeout])
+
+	// the environment overrides the profile
+	c, err := LoadConfig()
+	require.Nil(t, err)
+	assert.Equal(t, "https://islandora-idc.traefik.me", c.BaseUrl)
+	assert.True(t, c.Insecure)
+	assert.Equal(t, 5*time.Minute, c.Timeout)
+	assert.Equal(t, "https://islandora-idc.traefik.me", BaseUrl())
+
+	RegisterProfile("test-staging", Profile{drupalBaseUrl: "https://staging.example.org"})
+	assert.Contains(t, Profiles(), "test-staging")
+	os.Setenv(ProfileEnv, "test-staging")
+	assert.Equal(t, "https://staging.example.org", BaseUrlOr("https://unused"))
+	assert.Equal(t, "unused", UsernameOr("unused"))
+
+	os.Setenv(ProfileEnv, "production")
+	_, _, err = ActiveProfile()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown profile 'production' named by IDC_PROFILE; expected one of ci, local, staging")
+	assert.Equal(t, "https://unused", BaseUrlOr("https://unused"))
+	_, err = LoadConfig(WithBaseUrl("https://drupal"))
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+}
@@ -0,0 +1,93 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A required environment variable is unset
+var ErrMissingEnv = errors.New("env: missing required environment variable")
+
+// Answers the base url of Drupal from the environment variable 'DRUPAL_BASE_URL', or an error wrapping ErrMissingEnv
+// if it is unset
+func BaseUrlE() (string, error) {
+	return RequireEnvE(drupalBaseUrl)
+}
+
+// Answers the name (not path) of the base directory for the test suite from the environment variable
+// 'DRUPAL_TEST_BASEDIR', or an error wrapping ErrMissingEnv if it is unset
+func TestBasedirE() (string, error) {
+	return RequireEnvE(testBasedir)
+}
+
+// Answers the base URL to the test assets docker container from the environment variable 'BASE_ASSETS_URL', or an
+// error wrapping ErrMissingEnv if it is unset
+func AssetsBaseUrlE() (string, error) {
+	return RequireEnvE(assetsBaseUrl)
+}
+
+// Answers the value of the supplied environment variable (or of the active Profile), or an error wrapping
+// ErrMissingEnv if it is unset.  Unlike the accessors requiring a variable (e.g. BaseUrl), RequireEnvE does not panic,
+// so that TestMain may report the problem with context.
+func RequireEnvE(envVar string) (string, error) {
+	if val, ok := getEnv(envVar, false); ok {
+		return val, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrMissingEnv, envVar)
+}
+
+// Answers the value of the supplied environment variable as an integer, or the default value if unset.  Unlike
+// GetEnvOrInt, an error is answered rather than a panic if the value cannot be parsed as an integer.
+func GetEnvOrIntE(envVar string, defValue int) (int, error) {
+	val, ok := getEnv(envVar, false)
+	if !ok {
+		return defValue, nil
+	}
+	intval, err := strconv.Atoi(val)
+	if err != nil {
+		return defValue, fmt.Errorf("env: error formatting the value of environment variable '%s' as an integer: %w", envVar, err)
+	}
+	return intval, nil
+}
+
+// Answers the value of the supplied environment variable as a bool, or the default value if unset.  Unlike
+// GetEnvOrBool, an error is answered rather than a panic if the value cannot be parsed as a bool.
+func GetEnvOrBoolE(envVar string, defValue bool) (bool, error) {
+	val, ok := getEnv(envVar, false)
+	if !ok {
+		return defValue, nil
+	}
+	boolval, err := strconv.ParseBool(val)
+	if err != nil {
+		return defValue, fmt.Errorf("env: error formatting the value of environment variable '%s' as a bool: %w", envVar, err)
+	}
+	return boolval, nil
+}
+
+// Checks up front that each of the supplied environment variables is set (in the environment or the active Profile),
+// answering an error wrapping ErrMissingEnv that names every missing variable at once, rather than the first panic
+// of an accessor.  With no arguments, the variables required by BaseUrl, TestBasedir and AssetsBaseUrl are checked:
+//
+//	func TestMain(m *testing.M) {
+//		if err := env.Validate(); err != nil {
+//			log.Fatal(err)
+//		}
+//		os.Exit(m.Run())
+//	}
+func Validate(envVars ...string) error {
+	if len(envVars) == 0 {
+		envVars = []string{drupalBaseUrl, testBasedir, assetsBaseUrl}
+	}
+	var missing []string
+	for _, envVar := range envVars {
+		if _, ok := getEnv(envVar, false); !ok {
+			missing = append(missing, envVar)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingEnv, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequireEnvE(t *testing.T) {
+	_, err := RequireEnvE("IDC_TEST_REQUIRED")
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrMissingEnv))
+	assert.Equal(t, "env: missing required environment variable: IDC_TEST_REQUIRED", err.Error())
+
+	setenv(t, map[string]string{"IDC_TEST_REQUIRED": "value"})
+	v, err := RequireEnvE("IDC_TEST_REQUIRED")
+	require.Nil(t, err)
+	assert.Equal(t, "value", v)
+}
+
+func Test_GetEnvOrIntEAndBoolE(t *testing.T) {
+	i, err := GetEnvOrIntE("IDC_TEST_INT", 3)
+	require.Nil(t, err)
+	assert.Equal(t, 3, i)
+	b, err := GetEnvOrBoolE("IDC_TEST_BOOL", true)
+	require.Nil(t, err)
+	assert.True(t, b)
+
+	setenv(t, map[string]string{"IDC_TEST_INT": "42", "IDC_TEST_BOOL": "false"})
+	i, err = GetEnvOrIntE("IDC_TEST_INT", 3)
+	require.Nil(t, err)
+	assert.Equal(t, 42, i)
+	b, err = GetEnvOrBoolE("IDC_TEST_BOOL", true)
+	require.Nil(t, err)
+	assert.False(t, b)
+
+	os.Setenv("IDC_TEST_INT", "many")
+	os.Setenv("IDC_TEST_BOOL", "maybe")
+	_, err = GetEnvOrIntE("IDC_TEST_INT", 3)
+	assert.NotNil(t, err)
+	_, err = GetEnvOrBoolE("IDC_TEST_BOOL", true)
+	assert.NotNil(t, err)
+	assert.Panics(t, func() { GetEnvOrInt("IDC_TEST_INT", 3) })
+	assert.Panics(t, func() { GetEnvOrBool("IDC_TEST_BOOL", true) })
+}
+
+func Test_Validate(t *testing.T) {
+	for _, k := range []string{drupalBaseUrl, testBasedir, assetsBaseUrl, ProfileEnv} {
+		if v, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, v)
+			os.Unsetenv(k)
+		}
+	}
+
+	err := Validate()
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrMissingEnv))
+	assert.Equal(t, "env: missing required environment variable: DRUPAL_BASE_URL, DRUPAL_TEST_BASEDIR, BASE_ASSETS_URL", err.Error())
+	_, err = BaseUrlE()
+	assert.True(t, errors.Is(err, ErrMissingEnv))
+
+	setenv(t, map[string]string{drupalBaseUrl: "https://islandora-idc.traefik.me"})
+	assert.Nil(t, Validate(drupalBaseUrl))
+	err = Validate(drupalBaseUrl, "IDC_TEST_REQUIRED")
+	require.NotNil(t, err)
+	assert.Equal(t, "env: missing required environment variable: IDC_TEST_REQUIRED", err.Error())
+
+	// the active profile supplies required variables
+	setenv(t, map[string]string{ProfileEnv: "local"})
+	os.Unsetenv(drupalBaseUrl)
+	assert.Nil(t, Validate(drupalBaseUrl))
+
+	// but never credentials
+	for _, k := range []string{username, password} {
+		if v, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, v)
+			os.Unsetenv(k)
+		}
+	}
+	err = Validate(username, password)
+	require.NotNil(t, err)
+	assert.Equal(t, "env: missing required environment variable: DRUPAL_USERNAME, DRUPAL_PASSWORD", err.Error())
+}
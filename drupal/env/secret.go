@@ -0,0 +1,113 @@
+package env
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// The text replacing the value of a non-empty Secret wherever it is formatted
+const Redacted = "[redacted]"
+
+// A credential, e.g. a password, that is masked wherever it is formatted or serialized: by String, by every fmt verb
+// (so that `%v` of a struct carrying a Secret, as in an assertion message or a panic, does not reveal it), and by JSON
+// and YAML marshaling.  An empty Secret is formatted as the empty string, so that its absence remains apparent.  The
+// value is answered only by Reveal.
+type Secret string
+
+// Answers the value of the secret, e.g. to set the Authorization header of a request
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// Answers true if the value of the secret is the string, taking the same time whatever the position of the first
+// differing byte, so that a comparison does not reveal how much of a guess is correct
+func (s Secret) Equal(value string) bool {
+	return subtle.ConstantTimeCompare([]byte(s), []byte(value)) == 1
+}
+
+// Answers Redacted, or the empty string if the secret is empty
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// Formats the secret as String does, whatever the verb
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'q':
+		fmt.Fprintf(f, "%q", s.String())
+	case 'v':
+		if f.Flag('#') {
+			_, _ = f.Write([]byte(s.GoString()))
+			return
+		}
+		fallthrough
+	default:
+		_, _ = f.Write([]byte(s.String()))
+	}
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", s.String())), nil
+}
+
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// The suffix of the environment variable naming the file that carries the value of a secret, e.g.
+// 'DRUPAL_PASSWORD_FILE' naming a Docker or Kubernetes secret mounted at `/run/secrets/drupal_password`
+const SecretFileSuffix = "_FILE"
+
+// Answers the value of the secret named by the environment variable, so that credentials need not appear in the
+// environment (and thereby in CI logs).  If the variable suffixed by SecretFileSuffix is set (e.g.
+// 'DRUPAL_PASSWORD_FILE'), the secret is read from the file it names, less any trailing newline.  Otherwise the value
+// of the variable itself is answered, or of the active Profile, or the empty string if neither is set:
+//
+//	password, err := env.GetSecret("DRUPAL_PASSWORD")
+//
+// An error is answered if the file cannot be read, or if both the variable and its file variable are set.
+func GetSecret(envVar string) (string, error) {
+	val, _, err := lookupSecret(envVar)
+	return val, err
+}
+
+// Answers the value of the secret named by the environment variable (see GetSecret), or the default value if unset.
+// This function will panic if the secret cannot be read.
+func GetSecretOr(envVar, defValue string) string {
+	val, ok, err := lookupSecret(envVar)
+	if err != nil {
+		panic(err)
+	}
+	if !ok {
+		return defValue
+	}
+	return val
+}
+
+// Answers the value of the secret named by the environment variable, and whether it is set
+func lookupSecret(envVar string) (string, bool, error) {
+	fileVar := envVar + SecretFileSuffix
+	path, ok := os.LookupEnv(fileVar)
+	if !ok {
+		val, ok := getEnv(envVar, false)
+		return val, ok, nil
+	}
+	if _, set := os.LookupEnv(envVar); set {
+		return "", false, fmt.Errorf("env: both %s and %s are set; set only one", envVar, fileVar)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("env: error reading the secret file named by %s: %w", fileVar, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), true, nil
+}
@@ -0,0 +1,106 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_GetSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	secret := filepath.Join(dir, "drupal_password")
+	require.Nil(t, ioutil.WriteFile(secret, []byte("moonrise\n"), 0600))
+
+	setenv(t, map[string]string{"IDC_TEST_SECRET": "plain"})
+	v, err := GetSecret("IDC_TEST_SECRET")
+	require.Nil(t, err)
+	assert.Equal(t, "plain", v)
+
+	v, err = GetSecret("IDC_TEST_SECRET_UNSET")
+	require.Nil(t, err)
+	assert.Equal(t, "", v)
+	assert.Equal(t, "default", GetSecretOr("IDC_TEST_SECRET_UNSET", "default"))
+
+	os.Unsetenv("IDC_TEST_SECRET")
+	setenv(t, map[string]string{"IDC_TEST_SECRET_FILE": secret})
+	v, err = GetSecret("IDC_TEST_SECRET")
+	require.Nil(t, err)
+	assert.Equal(t, "moonrise", v)
+	assert.Equal(t, "moonrise", GetSecretOr("IDC_TEST_SECRET", "default"))
+
+	os.Setenv("IDC_TEST_SECRET", "plain")
+	_, err = GetSecret("IDC_TEST_SECRET")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "both IDC_TEST_SECRET and IDC_TEST_SECRET_FILE are set")
+	os.Unsetenv("IDC_TEST_SECRET")
+
+	os.Setenv("IDC_TEST_SECRET_FILE", filepath.Join(dir, "missing"))
+	_, err = GetSecret("IDC_TEST_SECRET")
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Panics(t, func() { GetSecretOr("IDC_TEST_SECRET", "default") })
+}
+
+func Test_LoadConfigSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	secret := filepath.Join(dir, "drupal_password")
+	require.Nil(t, ioutil.WriteFile(secret, []byte("moonrise\n"), 0600))
+	if v, ok := os.LookupEnv(password); ok {
+		defer os.Setenv(password, v)
+		os.Unsetenv(password)
+	}
+
+	setenv(t, map[string]string{drupalBaseUrl: "https://islandora-idc.traefik.me", username: "admin", password + SecretFileSuffix: secret})
+	c, err := LoadConfig()
+	require.Nil(t, err)
+	assert.Equal(t, "moonrise", c.Password.Reveal())
+	assert.Equal(t, "moonrise", PasswordOr(""))
+
+	os.Setenv(password+SecretFileSuffix, filepath.Join(dir, "missing"))
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+	assert.Contains(t, err.Error(), "error reading the secret file named by DRUPAL_PASSWORD_FILE")
+}
+
+func Test_SecretRedacted(t *testing.T) {
+	s := Secret("moonrise")
+	credentials := struct {
+		Username string
+		Password Secret
+	}{"admin", s}
+	for _, formatted := range []string{
+		s.String(), fmt.Sprint(s), fmt.Sprintf("%s %v %q %x %#v %d", s, s, s, s, s, s),
+		fmt.Sprintf("%v %+v %#v", credentials, credentials, credentials), fmt.Sprint(&credentials),
+	} {
+		assert.NotContains(t, formatted, "moonrise")
+		assert.Contains(t, formatted, Redacted)
+	}
+	assert.Equal(t, `{admin [redacted]}`, fmt.Sprintf("%v", credentials))
+	assert.Equal(t, `"[redacted]"`, fmt.Sprintf("%q", s))
+
+	b, err := json.Marshal(credentials)
+	require.Nil(t, err)
+	assert.Equal(t, `{"Username":"admin","Password":"[redacted]"}`, string(b))
+	y, err := yaml.Marshal(credentials)
+	require.Nil(t, err)
+	assert.NotContains(t, string(y), "moonrise")
+
+	assert.Equal(t, "", Secret("").String())
+	assert.Equal(t, "moonrise", s.Reveal())
+	assert.True(t, s.Equal("moonrise"))
+	assert.False(t, s.Equal("moonris"))
+	assert.False(t, s.Equal("moonrisf"))
+}
@@ -0,0 +1,33 @@
+//go:build !notestify
+// +build !notestify
+
+package fedora
+
+import (
+	"sort"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the Drupal entity with the UUID is synchronized to a Fedora resource carrying the expected values of
+// each predicate.  Predicates, and values that are IRIs, may be prefixed names (see Prefixes).  The resource may carry
+// values besides those expected.
+func (v *Verifier) AssertSynced(t assert.TestingT, uuid string, expected map[string][]string) bool {
+	r, err := v.Resource(uuid)
+	if !assert.Nil(t, err, "error retrieving the Fedora resource of '%s': %s", uuid, err) {
+		return false
+	}
+	predicates := make([]string, 0, len(expected))
+	for p := range expected {
+		predicates = append(predicates, p)
+	}
+	sort.Strings(predicates)
+	ok := true
+	for _, p := range predicates {
+		actual := r.Values(p)
+		for _, e := range expected[p] {
+			ok = assert.Contains(t, actual, Expand(e), "%s of %s lacks %q", p, r.Uri, e) && ok
+		}
+	}
+	return ok
+}
@@ -0,0 +1,223 @@
+// Verifies that Islandora synchronized Drupal entities to Fedora.  Islandora records the Fedora URI of each synchronized
+// node and media in Gemini; a Verifier looks up that URI by the entity's UUID, retrieves the Fedora resource, and
+// asserts that it carries the expected RDF properties:
+//
+//	auth := fedora.Credentials{Token: jwt}
+//	v := fedora.NewVerifier(&fedora.Gemini{BaseUrl: "http://gemini:8000", Credentials: auth}, &fedora.Client{Credentials: auth})
+//	v.AssertSynced(t, uuid, map[string][]string{"dcterms:title": {"Moonrise"}})
+package fedora
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var (
+	ErrNotMapped = errors.New("fedora: no Fedora resource is mapped")
+	ErrNotFound  = errors.New("fedora: resource not found")
+)
+
+// Prefixes expanded in the predicates supplied to Verifier.AssertSynced and Resource.Values, e.g. `dcterms:title`
+var Prefixes = map[string]string{
+	"rdf":     "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs":    "http://www.w3.org/2000/01/rdf-schema#",
+	"dc":      "http://purl.org/dc/elements/1.1/",
+	"dcterms": "http://purl.org/dc/terms/",
+	"schema":  "http://schema.org/",
+	"pcdm":    "http://pcdm.org/models#",
+	"ldp":     "http://www.w3.org/ns/ldp#",
+	"fedora":  "http://fedora.info/definitions/v4/repository#",
+	"ebucore": "http://www.ebu.ch/metadata/ontologies/ebucore/ebucore#",
+	"premis":  "http://www.loc.gov/premis/rdf/v1#",
+}
+
+// The JSON-LD type of a resource, answered as the values of `rdf:type`
+const rdfType = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+
+// The Drupal and Fedora URIs of a synchronized entity, as recorded by Gemini
+type Mapping struct {
+	Drupal string `json:"drupal"`
+	Fedora string `json:"fedora"`
+}
+
+// Credentials used to authenticate requests: a bearer token (e.g. the JWT accepted by Islandora's microservices) if
+// Token is not empty, otherwise HTTP Basic Auth if Username is not empty
+type Credentials struct {
+	Token    string
+	Username string
+	Password env.Secret
+}
+
+func (c Credentials) authenticate(req *http.Request) {
+	switch {
+	case strings.TrimSpace(c.Token) != "":
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	case strings.TrimSpace(c.Username) != "":
+		req.SetBasicAuth(c.Username, c.Password.Reveal())
+	}
+}
+
+// Looks up the Fedora URIs of Drupal entities using Gemini's REST API
+type Gemini struct {
+	// The base url of Gemini, e.g. `http://gemini:8000`
+	BaseUrl string
+	Credentials
+}
+
+// Answers the URIs recorded by Gemini for the entity with the UUID.  An error wrapping ErrNotMapped is answered if
+// Gemini records none.
+func (g *Gemini) Lookup(uuid string) (*Mapping, error) {
+	u := strings.TrimSuffix(g.BaseUrl, "/") + "/" + uuid
+	res, body, err := get(u, "application/json", g.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w to '%s'", ErrNotMapped, uuid)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fedora: %d status encountered when requesting %s", res.StatusCode, u)
+	}
+	m := &Mapping{}
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, fmt.Errorf("fedora: unable to unmarshal the mapping of '%s': %w", uuid, err)
+	}
+	if m.Fedora == "" {
+		return nil, fmt.Errorf("%w to '%s'", ErrNotMapped, uuid)
+	}
+	return m, nil
+}
+
+// A Fedora resource, with the values of its RDF properties keyed by predicate IRI.  IRIs and literals are both
+// answered as strings.
+type Resource struct {
+	Uri        string
+	Properties map[string][]string
+}
+
+// Answers the values of the predicate, which may be an IRI or a prefixed name (see Prefixes)
+func (r *Resource) Values(predicate string) []string {
+	return r.Properties[Expand(predicate)]
+}
+
+// Retrieves Fedora resources
+type Client struct {
+	Credentials
+}
+
+// Retrieves the Fedora resource at the uri as expanded JSON-LD.  An error wrapping ErrNotFound is answered if it does
+// not exist.
+func (c *Client) Fetch(uri string) (*Resource, error) {
+	res, body, err := get(uri, `application/ld+json; profile="http://www.w3.org/ns/json-ld#expanded"`, c.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%w: %s (%d)", ErrNotFound, uri, res.StatusCode)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fedora: %d status encountered when requesting %s", res.StatusCode, uri)
+	}
+	var nodes []map[string]interface{}
+	if err := json.Unmarshal(body, &nodes); err != nil {
+		return nil, fmt.Errorf("fedora: unable to unmarshal %s as expanded JSON-LD: %w", uri, err)
+	}
+
+	r := &Resource{Uri: uri, Properties: map[string][]string{}}
+	for _, node := range nodes {
+		if id, _ := node["@id"].(string); strings.TrimSuffix(id, "/") != strings.TrimSuffix(uri, "/") {
+			continue
+		}
+		for predicate, values := range node {
+			switch predicate {
+			case "@id":
+				continue
+			case "@type":
+				predicate = rdfType
+			}
+			r.Properties[predicate] = append(r.Properties[predicate], literals(values)...)
+		}
+	}
+	return r, nil
+}
+
+// Verifies that Drupal entities are synchronized to Fedora
+type Verifier struct {
+	Gemini *Gemini
+	Fedora *Client
+}
+
+// Creates a Verifier looking up Fedora URIs using the Gemini, and retrieving resources using the Client
+func NewVerifier(gemini *Gemini, fedora *Client) *Verifier {
+	return &Verifier{Gemini: gemini, Fedora: fedora}
+}
+
+// Answers the Fedora resource synchronized from the Drupal entity with the UUID
+func (v *Verifier) Resource(uuid string) (*Resource, error) {
+	m, err := v.Gemini.Lookup(uuid)
+	if err != nil {
+		return nil, err
+	}
+	return v.Fedora.Fetch(m.Fedora)
+}
+
+// Answers the IRI of a prefixed name, e.g. `http://purl.org/dc/terms/title` for `dcterms:title`.  Values that are not
+// prefixed names of Prefixes are answered unchanged.
+func Expand(name string) string {
+	i := strings.Index(name, ":")
+	if i < 0 {
+		return name
+	}
+	if ns, ok := Prefixes[name[:i]]; ok && !strings.HasPrefix(name[i+1:], "//") {
+		return ns + name[i+1:]
+	}
+	return name
+}
+
+// Answers the values of an expanded JSON-LD property: the `@id` of node references, and the `@value` of literals
+func literals(v interface{}) []string {
+	var values []string
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			values = append(values, item)
+		case map[string]interface{}:
+			if id, ok := item["@id"].(string); ok {
+				values = append(values, id)
+			} else if value, ok := item["@value"]; ok {
+				values = append(values, fmt.Sprintf("%v", value))
+			}
+		}
+	}
+	return values
+}
+
+func get(u, accept string, c Credentials) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fedora: %w", err)
+	}
+	c.authenticate(req)
+	req.Header.Set("Accept", accept)
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fedora: error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fedora: error reading response body from %s: %w", u, err)
+	}
+	return res, body, nil
+}
@@ -0,0 +1,92 @@
+//go:build !notestify
+// +build !notestify
+
+package fedora
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	syncedUuid   = "0b4d8c6a-4e61-4fc1-8d2b-7d0d1c6a0b11"
+	deletedUuid  = "1c5e9d7b-5f72-4ad2-9e3c-8e1e2d7b1c22"
+	unmappedUuid = "2d6fae8c-6083-4be3-af4d-9f2f3e8c2d33"
+)
+
+func newServer() *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer islandora" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/gemini/" + syncedUuid:
+			_, _ = w.Write([]byte(`{"drupal": "http://drupal/node/1", "fedora": "` + server.URL + `/fcrepo/rest/0b/4d/8c/6a/` + syncedUuid + `"}`))
+		case "/gemini/" + deletedUuid:
+			_, _ = w.Write([]byte(`{"drupal": "http://drupal/node/2", "fedora": "` + server.URL + `/fcrepo/rest/` + deletedUuid + `"}`))
+		case "/fcrepo/rest/0b/4d/8c/6a/" + syncedUuid:
+			if !strings.HasPrefix(r.Header.Get("Accept"), "application/ld+json") {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"@id": "` + server.URL + `/fcrepo/rest/0b/4d/8c/6a/` + syncedUuid + `",
+				"@type": ["http://pcdm.org/models#Object", "http://fedora.info/definitions/v4/repository#Resource"],
+				"http://purl.org/dc/terms/title": [{"@value": "Moonrise"}],
+				"http://schema.org/sameAs": [{"@id": "http://drupal/node/1"}],
+				"http://purl.org/dc/terms/extent": [{"@value": 2, "@type": "http://www.w3.org/2001/XMLSchema#integer"}]},
+				{"@id": "` + server.URL + `/fcrepo/rest/other", "http://purl.org/dc/terms/title": [{"@value": "Other"}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func Test_Verifier(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+	credentials := Credentials{Token: "islandora"}
+	v := NewVerifier(&Gemini{BaseUrl: server.URL + "/gemini", Credentials: credentials}, &Client{Credentials: credentials})
+
+	r, err := v.Resource(syncedUuid)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Moonrise"}, r.Values("dcterms:title"))
+	assert.Equal(t, []string{"2"}, r.Values("http://purl.org/dc/terms/extent"))
+	assert.ElementsMatch(t, []string{"http://pcdm.org/models#Object", "http://fedora.info/definitions/v4/repository#Resource"}, r.Values("rdf:type"))
+
+	assert.True(t, v.AssertSynced(t, syncedUuid, map[string][]string{
+		"dcterms:title": {"Moonrise"},
+		"rdf:type":      {"pcdm:Object"},
+		"schema:sameAs": {"http://drupal/node/1"},
+	}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, v.AssertSynced(rec, syncedUuid, map[string][]string{"dcterms:title": {"Other"}}))
+	assert.Contains(t, rec.String(), `dcterms:title of `)
+	assert.Contains(t, rec.String(), `lacks "Other"`)
+
+	_, err = v.Resource(deletedUuid)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = v.Resource(unmappedUuid)
+	assert.ErrorIs(t, err, ErrNotMapped)
+	rec = &asserttest.Recorder{}
+	assert.False(t, v.AssertSynced(rec, unmappedUuid, nil))
+	assert.Contains(t, rec.String(), "no Fedora resource is mapped to '2d6fae8c-6083-4be3-af4d-9f2f3e8c2d33'")
+
+	_, err = (&Gemini{BaseUrl: server.URL + "/gemini"}).Lookup(syncedUuid)
+	assert.NotNil(t, err)
+}
+
+func Test_Expand(t *testing.T) {
+	assert.Equal(t, "http://purl.org/dc/terms/title", Expand("dcterms:title"))
+	assert.Equal(t, "http://example.org/title", Expand("http://example.org/title"))
+	assert.Equal(t, "unknown:title", Expand("unknown:title"))
+	assert.Equal(t, "Moonrise", Expand("Moonrise"))
+}
@@ -0,0 +1,195 @@
+// Reads the field definitions of a Drupal bundle, and generates Expected structs from them, so that the Expected
+// structs of package model may be kept in sync with the fields of the site rather than by hand.
+//
+// Field definitions are read from the JSON API `field_config` and `field_storage_config` resources of a live site, or
+// from a configuration export (the `field.field.*.yml` and `field.storage.*.yml` files written by `drush config:export`):
+//
+//	fields, err := fieldconfig.Fetch(env.BaseUrl(), username, password, model.Node, model.RepositoryObject)
+//	fields, err := fieldconfig.Load("config/sync", model.Node, model.RepositoryObject)
+//	src, err := fieldconfig.Generate("model", "ExpectedRepoObj", model.Node, model.RepositoryObject, fields)
+//	drift, err := fieldconfig.CheckDrift(model.Node, model.RepositoryObject, fields)
+//
+// Reading field config using the JSON API requires a user permitted to administer the fields of the bundle.
+package fieldconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"gopkg.in/yaml.v3"
+)
+
+// The cardinality of a field whose number of values is unlimited
+const Unlimited = -1
+
+// No fields are defined for the entity type and bundle
+var ErrNoFields = errors.New("fieldconfig: no fields are defined")
+
+// The definition of a field of a bundle, merged from its field config and its field storage config
+type Field struct {
+	EntityType string
+	Bundle     string
+	// The machine name of the field, e.g. `field_subject`
+	Name        string
+	Label       string
+	Description string
+	// The field type, e.g. `string`, `entity_reference`, or `typed_relation`
+	Type     string
+	Required bool
+	// The maximum number of values of the field, or Unlimited
+	Cardinality int
+	// The entity type referenced by a reference field, e.g. `taxonomy_term`
+	TargetType string
+}
+
+// Answers true if the field may carry more than one value
+func (f Field) Multiple() bool {
+	return f.Cardinality != 1
+}
+
+// The members of a field config, as exported to YAML or answered by the JSON API
+type fieldConfig struct {
+	FieldName   string `yaml:"field_name" json:"field_name"`
+	EntityType  string `yaml:"entity_type" json:"entity_type"`
+	Bundle      string `yaml:"bundle" json:"bundle"`
+	Label       string `yaml:"label" json:"label"`
+	Description string `yaml:"description" json:"description"`
+	Required    bool   `yaml:"required" json:"required"`
+	FieldType   string `yaml:"field_type" json:"field_type"`
+}
+
+// The members of a field storage config, as exported to YAML or answered by the JSON API
+type storageConfig struct {
+	FieldName   string `yaml:"field_name" json:"field_name"`
+	EntityType  string `yaml:"entity_type" json:"entity_type"`
+	Type        string `yaml:"type" json:"type"`
+	Cardinality int    `yaml:"cardinality" json:"cardinality"`
+	Settings    struct {
+		TargetType string `yaml:"target_type" json:"target_type"`
+	} `yaml:"settings" json:"settings"`
+}
+
+// Retrieves the fields of the entity type and bundle (e.g. `node` and `islandora_object`) from the JSON API of the
+// Drupal site at the base url, answering them ordered by name.  Drupal does not support filtering config entities, so
+// every field config is retrieved.
+func Fetch(baseUrl, username, password, entityType, bundle string) ([]Field, error) {
+	var configs []fieldConfig
+	if err := fetchAll(baseUrl, username, password, "field_config", &configs); err != nil {
+		return nil, err
+	}
+	var storage []storageConfig
+	if err := fetchAll(baseUrl, username, password, "field_storage_config", &storage); err != nil {
+		return nil, err
+	}
+	return merge(entityType, bundle, configs, storage)
+}
+
+// Reads the fields of the entity type and bundle from the YAML files of a configuration export in the directory,
+// answering them ordered by name
+func Load(dir, entityType, bundle string) ([]Field, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("field.field.%s.%s.*.yml", entityType, bundle)))
+	if err != nil {
+		return nil, fmt.Errorf("fieldconfig: %w", err)
+	}
+	var configs []fieldConfig
+	var storage []storageConfig
+	for _, p := range paths {
+		c := fieldConfig{}
+		if err := readYaml(p, &c); err != nil {
+			return nil, err
+		}
+		configs = append(configs, c)
+
+		s := storageConfig{}
+		if err := readYaml(filepath.Join(dir, fmt.Sprintf("field.storage.%s.%s.yml", entityType, c.FieldName)), &s); err != nil {
+			return nil, err
+		}
+		storage = append(storage, s)
+	}
+	return merge(entityType, bundle, configs, storage)
+}
+
+// Retrieves every resource of the config entity type, decoding their attributes into v, a pointer to a slice
+func fetchAll(baseUrl, username, password, entityType string, v interface{}) error {
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      baseUrl,
+		DrupalEntity: entityType,
+		DrupalBundle: entityType,
+		Username:     username,
+		Password:     env.Secret(password),
+	}
+	var attributes []interface{}
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			attributes = append(attributes, d["attributes"])
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("fieldconfig: error retrieving %s: %w", entityType, err)
+	}
+	if b, err := json.Marshal(attributes); err != nil {
+		return fmt.Errorf("fieldconfig: error decoding %s: %w", entityType, err)
+	} else if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("fieldconfig: error decoding %s: %w", entityType, err)
+	}
+	return nil
+}
+
+// Decodes the YAML file into v
+func readYaml(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("fieldconfig: %w", err)
+	}
+	if err := yaml.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("fieldconfig: unable to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Answers the fields of the entity type and bundle, merging each field config with its storage config
+func merge(entityType, bundle string, configs []fieldConfig, storage []storageConfig) ([]Field, error) {
+	var fields []Field
+	for _, c := range configs {
+		if c.EntityType != entityType || c.Bundle != bundle {
+			continue
+		}
+		f := Field{EntityType: entityType, Bundle: bundle, Name: c.FieldName, Label: c.Label,
+			Description: c.Description, Type: c.FieldType, Required: c.Required, Cardinality: 1}
+		found := false
+		for _, s := range storage {
+			if s.EntityType == entityType && s.FieldName == c.FieldName {
+				f.Cardinality, f.TargetType, found = s.Cardinality, s.Settings.TargetType, true
+				if f.Type == "" {
+					f.Type = s.Type
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("fieldconfig: no storage config is defined for %s.%s", entityType, c.FieldName)
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w for %s--%s", ErrNoFields, entityType, bundle)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields, nil
+}
+
+// Answers the fixture key of the field: the key generated from it by package model, if any, otherwise its name
+// without the `field_` prefix
+func key(f Field, sources map[string]string) string {
+	if k, ok := sources[f.Name]; ok {
+		return k
+	}
+	return strings.TrimPrefix(f.Name, "field_")
+}
@@ -0,0 +1,110 @@
+package fieldconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Load(t *testing.T) {
+	fields, err := Load("testdata/config", model.Node, model.RepositoryObject)
+	require.Nil(t, err)
+	require.Equal(t, 6, len(fields))
+	assert.Equal(t, "field_abstract", fields[0].Name)
+	assert.Equal(t, Field{EntityType: model.Node, Bundle: model.RepositoryObject, Name: "field_member_of",
+		Label: "Member Of", Type: "entity_reference", Cardinality: 1, TargetType: model.Node}, fields[2])
+	assert.True(t, fields[3].Required)
+	assert.Equal(t, Unlimited, fields[4].Cardinality)
+
+	fields, err = Load("testdata/config", model.Node, model.Collection)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(fields))
+
+	_, err = Load("testdata/config", model.Node, "page")
+	assert.True(t, errors.Is(err, ErrNoFields))
+}
+
+func Test_Fetch(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	m.Add(
+		jsonapitest.Resource{"type": "field_config--field_config", "attributes": map[string]interface{}{
+			"field_name": "field_subject", "entity_type": "node", "bundle": "islandora_object", "label": "Subject",
+			"field_type": "entity_reference"}},
+		jsonapitest.Resource{"type": "field_config--field_config", "attributes": map[string]interface{}{
+			"field_name": "field_subject", "entity_type": "node", "bundle": "page", "field_type": "entity_reference"}},
+		jsonapitest.Resource{"type": "field_storage_config--field_storage_config", "attributes": map[string]interface{}{
+			"field_name": "field_subject", "entity_type": "node", "type": "entity_reference", "cardinality": -1,
+			"settings": map[string]interface{}{"target_type": "taxonomy_term"}}},
+	)
+
+	fields, err := Fetch(m.URL, "", "", model.Node, model.RepositoryObject)
+	require.Nil(t, err)
+	assert.Equal(t, []Field{{EntityType: model.Node, Bundle: model.RepositoryObject, Name: "field_subject",
+		Label: "Subject", Type: "entity_reference", Cardinality: Unlimited, TargetType: model.TaxonomyTerm}}, fields)
+
+	m.Reset()
+	m.Add(jsonapitest.Resource{"type": "field_config--field_config", "attributes": map[string]interface{}{
+		"field_name": "field_subject", "entity_type": "node", "bundle": "islandora_object"}})
+	_, err = Fetch(m.URL, "", "", model.Node, model.RepositoryObject)
+	assert.NotNil(t, err)
+}
+
+func Test_Generate(t *testing.T) {
+	fields, err := Load("testdata/config", model.Node, model.RepositoryObject)
+	require.Nil(t, err)
+
+	src, err := Generate("fixtures", "ExpectedObject", model.Node, model.RepositoryObject, fields)
+	require.Nil(t, err)
+	assert.Contains(t, string(src), "// Code generated by genmodel from the fields of node--islandora_object; DO NOT EDIT.")
+	assert.Contains(t, string(src), "import \"github.com/jhu-idc/idc-golang/drupal/model\"")
+	assert.Contains(t, string(src), "model.ExpectedWithTitle")
+	assert.Regexp(t, "Abstract +\\[\\]model.LanguageString +`json:\"abstract\"`", string(src))
+	assert.Regexp(t, "RelType string `json:\"rel_type\"`", string(src))
+	assert.Regexp(t, "MemberOf +string +`json:\"member_of\"`", string(src))
+	assert.Regexp(t, "Rights +string +`json:\"rights\"`", string(src))
+	assert.Regexp(t, "Subject +\\[\\]string +`json:\"subject\"`", string(src))
+	assert.Regexp(t, "Weight +int +`json:\"weight\"`", string(src))
+	assert.Contains(t, string(src), "// Rights (field_rights)")
+
+	src, err = Generate("model", "ExpectedPage", model.Node, "page", fields)
+	require.Nil(t, err)
+	assert.NotContains(t, string(src), "import")
+	assert.Regexp(t, "Abstract +\\[\\]LanguageString", string(src))
+}
+
+func Test_CheckDrift(t *testing.T) {
+	fields, err := Load("testdata/config", model.Node, model.RepositoryObject)
+	require.Nil(t, err)
+
+	d, err := CheckDrift(model.Node, model.RepositoryObject, fields)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(d.Missing))
+	assert.Equal(t, "field_rights", d.Missing[0].Name)
+	assert.Contains(t, d.Stale, "field_genre")
+	assert.NotContains(t, d.Stale, "field_subject")
+	assert.NotContains(t, d.Stale, "title")
+	assert.Contains(t, d.String(), "node--islandora_object: field_rights (string) is not generated by the model")
+	assert.False(t, d.Empty())
+
+	// registered fields are generated by the model
+	defer model.UnregisterFields(model.Node, model.RepositoryObject)
+	model.MustRegisterField(model.Node, model.RepositoryObject, model.ExtraField{Key: "rights",
+		Generate: model.FromAttribute("field_rights")})
+	d, err = CheckDrift(model.Node, model.RepositoryObject, fields)
+	require.Nil(t, err)
+	assert.Empty(t, d.Missing)
+
+	_, err = CheckDrift(model.Node, "page", fields)
+	assert.True(t, errors.Is(err, model.ErrUnsupported))
+}
+
+func Test_GoName(t *testing.T) {
+	assert.Equal(t, "UniqueId", GoName("unique_id"))
+	assert.Equal(t, "Toc", GoName("toc"))
+}
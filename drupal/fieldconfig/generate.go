@@ -0,0 +1,157 @@
+package fieldconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The Go types of the values of field types, as they appear in an Expected fixture
+var goTypes = map[string]string{
+	"boolean":             "bool",
+	"integer":             "int",
+	"list_integer":        "int",
+	"decimal":             "float64",
+	"float":               "float64",
+	"list_float":          "float64",
+	"language_value_pair": "LanguageString",
+	"typed_relation":      "struct {\nRelType string `json:\"rel_type\"`\nName string `json:\"name\"`\n}",
+}
+
+// Answers the Go type of the value of the field in an Expected fixture, e.g. `[]string` for a multi-valued reference
+// to taxonomy terms, which a fixture carries as the names of the terms.  Text, link, date, and reference fields are
+// carried as strings; field types without a known representation as interface{}.
+func GoType(f Field) string {
+	t, ok := goTypes[f.Type]
+	if !ok {
+		t = "string"
+		if strings.HasPrefix(f.Type, "geo") || f.Type == "" {
+			t = "interface{}"
+		}
+	}
+	if f.Multiple() {
+		return "[]" + t
+	}
+	return t
+}
+
+// Answers the Go source of a file of the package declaring the Expected struct named typeName, with a member for each
+// field of the entity type and bundle.  Each member is tagged with the fixture key package model generates from the
+// field, or the name of the field without its `field_` prefix.  The struct embeds model.ExpectedWithTitle (nodes) or
+// model.ExpectedWithName (other entities).
+func Generate(pkg, typeName, entityType, bundle string, fields []Field) ([]byte, error) {
+	sources, err := model.SourceFields(entityType, bundle)
+	if err != nil && !errors.Is(err, model.ErrUnsupported) {
+		return nil, err
+	}
+	qualifier := "model."
+	if pkg == "model" {
+		qualifier = ""
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by genmodel from the fields of %s--%s; DO NOT EDIT.\n\n", entityType, bundle)
+	fmt.Fprintf(out, "package %s\n\n", pkg)
+	if qualifier != "" {
+		fmt.Fprintf(out, "import \"github.com/jhu-idc/idc-golang/drupal/model\"\n\n")
+	}
+	fmt.Fprintf(out, "// Represents the expected %s--%s\n", entityType, bundle)
+	fmt.Fprintf(out, "type %s struct {\n", typeName)
+	if entityType == model.Node {
+		fmt.Fprintf(out, "%sExpectedWithTitle\n", qualifier)
+	} else {
+		fmt.Fprintf(out, "%sExpectedWithName\n", qualifier)
+	}
+	for _, f := range fields {
+		k := key(f, sources)
+		if k == "title" || k == "name" {
+			continue
+		}
+		t := GoType(f)
+		if strings.HasSuffix(t, "LanguageString") {
+			t = strings.TrimSuffix(t, "LanguageString") + qualifier + "LanguageString"
+		}
+		if f.Label != "" {
+			fmt.Fprintf(out, "// %s (%s)\n", f.Label, f.Name)
+		}
+		fmt.Fprintf(out, "%s %s `json:\"%s\"`\n", GoName(k), t, k)
+	}
+	fmt.Fprintf(out, "}\n\n")
+	fmt.Fprintf(out, "func (e %s) EntityType() string { return %q }\n\n", typeName, entityType)
+	fmt.Fprintf(out, "func (e %s) EntityBundle() string { return %q }\n", typeName, bundle)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("fieldconfig: error formatting %s: %w", typeName, err)
+	}
+	return src, nil
+}
+
+// Answers the Go name of the fixture key, e.g. `UniqueId` for `unique_id`
+func GoName(key string) string {
+	var name strings.Builder
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' }) {
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return name.String()
+}
+
+// The difference between the fields of a bundle and the keys package model generates for it
+type Drift struct {
+	EntityType string
+	Bundle     string
+	// The fields of the bundle from which model generates no key
+	Missing []Field
+	// The fields from which model generates a key, which the bundle no longer has
+	Stale []string
+}
+
+// Answers true if the bundle and the model agree
+func (d *Drift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Stale) == 0
+}
+
+// Answers a line for each field that is missing from, or stale in, the model
+func (d *Drift) String() string {
+	var lines []string
+	for _, f := range d.Missing {
+		lines = append(lines, fmt.Sprintf("%s--%s: %s (%s) is not generated by the model",
+			d.EntityType, d.Bundle, f.Name, f.Type))
+	}
+	for _, name := range d.Stale {
+		lines = append(lines, fmt.Sprintf("%s--%s: %s is generated by the model, but is not a field of the bundle",
+			d.EntityType, d.Bundle, name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Compares the fields of the entity type and bundle with the fields from which package model generates fixture keys
+// (see model.SourceFields), including the fields registered for the bundle.  Base fields (e.g. `title` or `promote`),
+// whose names lack the `field_` prefix, are not compared.  An error wrapping model.ErrUnsupported is answered if model
+// does not generate the bundle.
+func CheckDrift(entityType, bundle string, fields []Field) (*Drift, error) {
+	sources, err := model.SourceFields(entityType, bundle)
+	if err != nil {
+		return nil, err
+	}
+	d := &Drift{EntityType: entityType, Bundle: bundle}
+	defined := map[string]bool{}
+	for _, f := range fields {
+		defined[f.Name] = true
+		if _, ok := sources[f.Name]; !ok {
+			d.Missing = append(d.Missing, f)
+		}
+	}
+	for name := range sources {
+		if strings.HasPrefix(name, "field_") && !defined[name] {
+			d.Stale = append(d.Stale, name)
+		}
+	}
+	sort.Strings(d.Stale)
+	return d, nil
+}
@@ -0,0 +1,48 @@
+//go:build !notestify
+// +build !notestify
+
+package files
+
+import (
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the file at each url becomes available from the CDN
+func (c *CdnChecker) AssertAvailable(t assert.TestingT, urls ...string) bool {
+	ok := true
+	for _, u := range urls {
+		_, err := c.WaitAvailable(u)
+		ok = assert.Nil(t, err, "%s", err) && ok
+	}
+	return ok
+}
+
+// Asserts that the actual size and checksums match those of the expected.  Only the non-empty values of expected are
+// compared, so a fixture may carry e.g. only a SHA-256.  Checksums are compared without regard to case.
+func AssertChecksums(t assert.TestingT, expected, actual Checksums) bool {
+	ok := true
+	if expected.Size > 0 {
+		ok = assert.Equal(t, expected.Size, actual.Size, "file size differs") && ok
+	}
+	for _, c := range []struct{ name, expected, actual string }{
+		{"md5", expected.Md5, actual.Md5},
+		{"sha1", expected.Sha1, actual.Sha1},
+		{"sha256", expected.Sha256, actual.Sha256},
+	} {
+		if c.expected != "" {
+			ok = assert.Equal(t, strings.ToLower(c.expected), c.actual, "file %s differs", c.name) && ok
+		}
+	}
+	return ok
+}
+
+// Downloads the file at the url and asserts that its size and checksums match the expected (see AssertChecksums)
+func AssertDownload(t assert.TestingT, d *Downloader, url string, expected Checksums) bool {
+	actual, err := d.DownloadAndChecksum(url)
+	if !assert.Nil(t, err, "error downloading %s: %s", url, err) {
+		return false
+	}
+	return AssertChecksums(t, expected, actual)
+}
@@ -0,0 +1,155 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var ErrUnavailable = errors.New("files: not available from the CDN")
+
+// The defaults of a CdnChecker: files served through a CDN may lag behind Drupal by minutes after ingest, so the
+// CDN is polled far more patiently than Drupal itself
+const (
+	DefaultCdnTimeout        = 15 * time.Minute
+	DefaultCdnInitialBackoff = 10 * time.Second
+	DefaultCdnMaxBackoff     = 2 * time.Minute
+)
+
+// The query parameter carrying the cache-busting value of a CdnChecker with CacheBust set, if it names none
+const DefaultCacheBustParam = "_cb"
+
+// Waits for files to become available from a CDN fronting Drupal.  The URL of a file served by Drupal is rewritten
+// beneath the base url of the CDN, and requested until it answers 200, backing off exponentially between requests.
+// Unlike a request to Drupal, a request to the CDN may be answered from a stale cache entry (e.g. a cached 404), so a
+// cache-busting query parameter may be added to each request.
+type CdnChecker struct {
+	// The base url of Drupal, e.g. https://islandora-idc.traefik.me; URLs beneath it are rewritten beneath CdnBaseUrl
+	OriginBaseUrl string
+	// The base url of the CDN, e.g. https://cdn.example.org
+	CdnBaseUrl string
+	// Adds a unique value of CacheBustParam to each request
+	CacheBust      bool
+	CacheBustParam string
+	// The time waited for each file; DefaultCdnTimeout if zero
+	Timeout time.Duration
+	// The wait after the first unavailable response, doubling after each until MaxBackoff; DefaultCdnInitialBackoff
+	// and DefaultCdnMaxBackoff if zero
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// The outcome of waiting for a file
+type Availability struct {
+	// The CDN url of the file, without any cache-busting parameter
+	Url string
+	// The status of the last response; zero if no response was received
+	Status int
+	// The error of the last request, if no response was received
+	Err      error
+	Attempts int
+	Elapsed  time.Duration
+}
+
+// Answers true if the file was available
+func (a Availability) Available() bool {
+	return a.Status == http.StatusOK
+}
+
+// Answers the availability as e.g. `https://cdn.example.org/moonrise.jpg: 404 Not Found after 5 attempts in 3m0s`
+func (a Availability) String() string {
+	outcome := fmt.Sprintf("%d %s", a.Status, http.StatusText(a.Status))
+	if a.Err != nil {
+		outcome = a.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s after %d attempts in %s", a.Url, outcome, a.Attempts, a.Elapsed.Round(time.Millisecond))
+}
+
+// Creates a CdnChecker serving the files of Drupal at the origin base url from the CDN at the cdn base url
+func NewCdnChecker(originBaseUrl, cdnBaseUrl string) *CdnChecker {
+	return &CdnChecker{OriginBaseUrl: originBaseUrl, CdnBaseUrl: cdnBaseUrl}
+}
+
+// Answers the CDN url of the file at the url, which is answered unchanged unless it is beneath OriginBaseUrl
+func (c *CdnChecker) Url(u string) string {
+	origin := strings.TrimSuffix(c.OriginBaseUrl, "/")
+	if origin == "" || !strings.HasPrefix(u, origin+"/") {
+		return u
+	}
+	return strings.TrimSuffix(c.CdnBaseUrl, "/") + strings.TrimPrefix(u, origin)
+}
+
+// Requests the CDN url of the file at the url until it answers 200 or the timeout elapses, and answers its
+// availability.  An error wrapping ErrUnavailable is answered if the file is not available.
+func (c *CdnChecker) WaitAvailable(u string) (Availability, error) {
+	timeout, backoff, max := c.Timeout, c.InitialBackoff, c.MaxBackoff
+	if timeout == 0 {
+		timeout = DefaultCdnTimeout
+	}
+	if backoff == 0 {
+		backoff = DefaultCdnInitialBackoff
+	}
+	if max == 0 {
+		max = DefaultCdnMaxBackoff
+	}
+
+	a := Availability{Url: c.Url(u)}
+	start := time.Now()
+	deadline := start.Add(timeout)
+	for {
+		a.Attempts++
+		a.Status, a.Err = c.head(a.Url)
+		a.Elapsed = time.Since(start)
+		if a.Available() {
+			return a, nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return a, fmt.Errorf("%w: %s", ErrUnavailable, a)
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+	}
+}
+
+// Requests the url, adding a cache-busting parameter if configured, and answers the status of the response
+func (c *CdnChecker) head(u string) (int, error) {
+	if c.CacheBust {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return 0, fmt.Errorf("files: %w", err)
+		}
+		param := c.CacheBustParam
+		if param == "" {
+			param = DefaultCacheBustParam
+		}
+		q := parsed.Query()
+		q.Set(param, strconv.FormatInt(time.Now().UnixNano(), 36))
+		parsed.RawQuery = q.Encode()
+		u = parsed.String()
+	}
+
+	req, err := http.NewRequest(http.MethodHead, u, nil)
+	if err != nil {
+		return 0, fmt.Errorf("files: %w", err)
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err == nil && res.StatusCode == http.StatusMethodNotAllowed {
+		res.Body.Close()
+		req.Method = http.MethodGet
+		res, err = jsonapi.HTTPClient().Do(req)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("files: error requesting %s: %w", u, err)
+	}
+	res.Body.Close()
+	return res.StatusCode, nil
+}
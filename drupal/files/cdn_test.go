@@ -0,0 +1,58 @@
+//go:build !notestify
+// +build !notestify
+
+package files
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CdnChecker(t *testing.T) {
+	var requests []*http.Request
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		// the CDN lags behind Drupal, answering the file on the third request
+		if r.URL.Path != "/system/files/moonrise.jpg" || len(requests) < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, http.MethodHead, r.Method)
+	}))
+	defer cdn.Close()
+
+	c := NewCdnChecker("https://islandora-idc.traefik.me/", cdn.URL)
+	c.CacheBust = true
+	c.InitialBackoff, c.MaxBackoff, c.Timeout = time.Millisecond, 2*time.Millisecond, time.Second
+	assert.Equal(t, cdn.URL+"/system/files/moonrise.jpg", c.Url("https://islandora-idc.traefik.me/system/files/moonrise.jpg"))
+	assert.Equal(t, "https://elsewhere.org/a.jpg", c.Url("https://elsewhere.org/a.jpg"))
+
+	a, err := c.WaitAvailable("https://islandora-idc.traefik.me/system/files/moonrise.jpg")
+	require.Nil(t, err)
+	assert.True(t, a.Available())
+	assert.Equal(t, 3, a.Attempts)
+	require.Equal(t, 3, len(requests))
+	assert.NotEqual(t, requests[0].URL.Query().Get(DefaultCacheBustParam), requests[1].URL.Query().Get(DefaultCacheBustParam))
+	assert.Equal(t, "no-cache", requests[0].Header.Get("Cache-Control"))
+
+	c.CacheBust = false
+	c.Timeout = 10 * time.Millisecond
+	a, err = c.WaitAvailable("https://islandora-idc.traefik.me/system/files/missing.jpg")
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.Equal(t, http.StatusNotFound, a.Status)
+	assert.Empty(t, requests[len(requests)-1].URL.RawQuery)
+	assert.Contains(t, a.String(), "/system/files/missing.jpg: 404 Not Found after")
+
+	assert.True(t, c.AssertAvailable(t, "https://islandora-idc.traefik.me/system/files/moonrise.jpg"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, c.AssertAvailable(rec, "https://islandora-idc.traefik.me/system/files/missing.jpg"))
+	assert.Contains(t, rec.String(), "files: not available from the CDN")
+	assert.Contains(t, rec.String(), "missing.jpg: 404 Not Found")
+}
@@ -0,0 +1,149 @@
+// Downloads the binaries of migrated media and computes their checksums, so that tests may verify that the files were
+// migrated intact.
+package files
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The size of the ranges requested by a Downloader if its ChunkSize is not positive: 64 MiB
+const DefaultChunkSize int64 = 64 << 20
+
+// The size and checksums of a file.  Checksums are lower-case hex.
+type Checksums struct {
+	Size   int64  `json:"size,omitempty"`
+	Md5    string `json:"md5,omitempty"`
+	Sha1   string `json:"sha1,omitempty"`
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// Downloads files, requesting large files in ranges so that an interrupted connection does not restart the download
+// from the beginning
+type Downloader struct {
+	// The credentials used to authenticate using HTTP Basic Auth, if Username is not empty
+	Username string
+	Password env.Secret
+	// The size of each requested range; DefaultChunkSize if not positive
+	ChunkSize int64
+	// The number of times a failed range is retried
+	Retries int
+}
+
+// Downloads the file at the url without authentication, and answers its size and checksums
+func DownloadAndChecksum(url string) (Checksums, error) {
+	return (&Downloader{}).DownloadAndChecksum(url)
+}
+
+// Downloads the file at the url, and answers its size and checksums.  Ranges are requested if the server supports
+// them; otherwise the file is downloaded in a single request.
+func (d *Downloader) DownloadAndChecksum(url string) (Checksums, error) {
+	chunk := d.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultChunkSize
+	}
+
+	md5sum, sha1sum, sha256sum := md5.New(), sha1.New(), sha256.New()
+	w := io.MultiWriter(md5sum, sha1sum, sha256sum)
+
+	var offset int64
+	total := int64(-1)
+	for total < 0 || offset < total {
+		var n int64
+		var err error
+		for attempt := 0; attempt <= d.Retries; attempt++ {
+			var length int64
+			n, length, err = d.get(url, offset, offset+chunk-1, w)
+			if err == nil || n > 0 {
+				// a partially read range cannot be retried, as the hashes have consumed it
+				if length >= 0 {
+					total = length
+				}
+				break
+			}
+		}
+		if err != nil {
+			return Checksums{}, err
+		}
+		offset += n
+		if total < 0 {
+			// the server ignored the range and answered the entire file
+			total = offset
+		}
+		if n == 0 && offset < total {
+			return Checksums{}, fmt.Errorf("files: no content answered for %s at offset %d of %d", url, offset, total)
+		}
+	}
+
+	return Checksums{Size: offset, Md5: sum(md5sum), Sha1: sum(sha1sum), Sha256: sum(sha256sum)}, nil
+}
+
+// Requests the range of the file, writing its content to w.  The number of bytes written is answered, along with the
+// total length of the file if the server answered a partial response, or -1 if it answered the entire file.
+func (d *Downloader) get(url string, first, last int64, w io.Writer) (written int64, total int64, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("files: %w", err)
+	}
+	if strings.TrimSpace(d.Username) != "" {
+		req.SetBasicAuth(d.Username, d.Password.Reveal())
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", first, last))
+
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("files: error requesting %s: %w", url, err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		if first > 0 {
+			return 0, 0, fmt.Errorf("files: %s answered the entire file for a range beginning at %d", url, first)
+		}
+		total = -1
+	case http.StatusPartialContent:
+		if total, err = contentRangeTotal(res.Header.Get("Content-Range")); err != nil {
+			return 0, 0, fmt.Errorf("files: %s: %w", url, err)
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// an empty file
+		return 0, 0, nil
+	default:
+		return 0, 0, fmt.Errorf("files: %d status encountered when requesting %s", res.StatusCode, url)
+	}
+
+	written, err = io.Copy(w, res.Body)
+	if err != nil {
+		return written, total, fmt.Errorf("files: error reading %s: %w", url, err)
+	}
+	return written, total, nil
+}
+
+// Answers the total length carried by a Content-Range header, e.g. 1234 from `bytes 0-99/1234`
+func contentRangeTotal(header string) (int64, error) {
+	i := strings.LastIndexByte(header, '/')
+	if !strings.HasPrefix(header, "bytes ") || i < 0 {
+		return 0, fmt.Errorf("malformed Content-Range '%s'", header)
+	}
+	total, err := strconv.ParseInt(header[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed Content-Range '%s': %w", header, err)
+	}
+	return total, nil
+}
+
+func sum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,89 @@
+//go:build !notestify
+// +build !notestify
+
+package files
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var content = []byte(strings.Repeat("Moonrise Over Hernandez, New Mexico. ", 100))
+
+func expected(b []byte) Checksums {
+	m, s1, s256 := md5.Sum(b), sha1.Sum(b), sha256.Sum256(b)
+	return Checksums{Size: int64(len(b)), Md5: hex.EncodeToString(m[:]), Sha1: hex.EncodeToString(s1[:]), Sha256: hex.EncodeToString(s256[:])}
+}
+
+func Test_DownloadAndChecksum(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "password" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		switch r.URL.Path {
+		case "/ranged.jpg":
+			http.ServeContent(w, r, "ranged.jpg", time.Time{}, bytes.NewReader(content))
+		case "/empty.jpg":
+			http.ServeContent(w, r, "empty.jpg", time.Time{}, bytes.NewReader(nil))
+		default:
+			w.Write(content)
+		}
+	}))
+	defer server.Close()
+
+	d := &Downloader{Username: "admin", Password: "password", ChunkSize: 1000}
+	actual, err := d.DownloadAndChecksum(server.URL + "/ranged.jpg")
+	require.Nil(t, err)
+	assert.Equal(t, expected(content), actual)
+	assert.Equal(t, []string{"bytes=0-999", "bytes=1000-1999", "bytes=2000-2999", "bytes=3000-3999"}, ranges)
+
+	// a server that ignores ranges
+	ranges = nil
+	actual, err = d.DownloadAndChecksum(server.URL + "/unranged.jpg")
+	require.Nil(t, err)
+	assert.Equal(t, expected(content), actual)
+	assert.Equal(t, 1, len(ranges))
+
+	actual, err = d.DownloadAndChecksum(server.URL + "/empty.jpg")
+	require.Nil(t, err)
+	assert.Equal(t, expected(nil), actual)
+
+	_, err = DownloadAndChecksum(server.URL + "/ranged.jpg")
+	assert.NotNil(t, err)
+}
+
+func Test_AssertChecksums(t *testing.T) {
+	actual := expected(content)
+	assert.True(t, AssertChecksums(t, Checksums{Sha256: strings.ToUpper(actual.Sha256)}, actual))
+	assert.True(t, AssertChecksums(t, Checksums{}, actual))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertChecksums(rec, Checksums{Size: 1, Md5: actual.Md5}, actual))
+	assert.Contains(t, rec.String(), "file size differs")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertChecksums(rec, Checksums{Sha1: actual.Md5}, actual))
+	assert.Contains(t, rec.String(), "file sha1 differs")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "moonrise.jpg", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	assert.True(t, AssertDownload(t, &Downloader{}, server.URL, Checksums{Md5: actual.Md5}))
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertDownload(rec, &Downloader{}, server.URL+"/%zz", Checksums{}))
+	assert.Contains(t, rec.String(), "invalid URL escape")
+}
@@ -0,0 +1,28 @@
+//go:build !notestify
+// +build !notestify
+
+package fs
+
+import (
+	"strings"
+	"testing"
+)
+
+// Behaves as the package function FindExpectedJson against the fixtures of the set rather than the working
+// directory: answers the path within the set of the named fixture, panicking if it cannot be found.  The `name` and
+// optional `searchdirs` should not contain any path separators.
+func (s *FixtureSet) FindExpectedJson(t *testing.T, name string, searchdirs ...string) string {
+	if strings.Contains(name, "/") {
+		panicf("Supplied file name '%s' must not contain path separator '/'", name)
+	}
+	for _, dir := range searchdirs {
+		if strings.Contains(dir, "/") {
+			panicf("Supplied search directory '%s' must not contain path separator '/'", dir)
+		}
+	}
+	p, err := s.Lookup(name, searchdirs...)
+	if err != nil {
+		panicf("Could not locate file '%v': %s", name, err)
+	}
+	return p
+}
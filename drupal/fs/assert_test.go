@@ -0,0 +1,20 @@
+//go:build !notestify
+// +build !notestify
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FixtureSetFindExpectedJson(t *testing.T) {
+	s := NewFixtureSet(testdata)
+
+	assert.Equal(t, "testdata/expected/collections/collection-02.json", s.FindExpectedJson(t, "collection-02.json"))
+	assert.Equal(t, "testdata/other/collection-01.json", s.FindExpectedJson(t, "collection-01.json", "other"))
+	assert.Panics(t, func() { s.FindExpectedJson(t, "missing.json") })
+	assert.Panics(t, func() { s.FindExpectedJson(t, "other/collection-01.json") })
+	assert.Panics(t, func() { s.FindExpectedJson(t, "collection-01.json", "testdata/other") })
+}
@@ -0,0 +1,146 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// A fixture is not present in the FixtureSet
+var ErrFixtureNotFound = errors.New("fs: fixture not found")
+
+// A set of test fixtures, typically embedded in the test binary with go:embed, so that they are discovered
+// independently of the working directory of the test:
+//
+//	//go:embed testdata
+//	var testdata embed.FS
+//
+//	var fixtures = fs.NewFixtureSet(testdata)
+//
+//	func Test_VerifyCollection(t *testing.T) {
+//		expectedJson, err := fixtures.ReadFile("collection-01.json")
+//		...
+//	}
+//
+// Fixtures are looked up by file name (e.g. `collection-01.json`) at any depth, or by their path within the set (e.g.
+// `testdata/expected/collection-01.json`).  If more than one fixture shares a name, the first in lexical order of
+// their paths is answered, as FindExpectedJson answers the first found walking the file system.
+type FixtureSet struct {
+	fsys fs.FS
+}
+
+// Creates a FixtureSet of the files of the file system, typically an embed.FS
+func NewFixtureSet(fsys fs.FS) *FixtureSet {
+	return &FixtureSet{fsys: fsys}
+}
+
+// Answers the underlying file system of the set, e.g. to open a fixture by the path answered by Lookup
+func (s *FixtureSet) FS() fs.FS {
+	return s.fsys
+}
+
+// Answers the paths of every fixture in the set, in lexical order
+func (s *FixtureSet) List() ([]string, error) {
+	return s.Glob("*")
+}
+
+// Answers the paths of the fixtures whose file names match the pattern (see path.Match), e.g. `collection-*.json`,
+// in lexical order.  A pattern containing a `/` is instead matched against the path of each fixture within the set,
+// e.g. `testdata/expected/*.json`.
+func (s *FixtureSet) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("fs: invalid fixture pattern '%s': %w", pattern, err)
+	}
+	matches := []string{}
+	err := s.walk(func(p string) bool {
+		subject := path.Base(p)
+		if strings.Contains(pattern, "/") {
+			subject = p
+		}
+		if ok, _ := path.Match(pattern, subject); ok {
+			matches = append(matches, p)
+		}
+		return true
+	})
+	return matches, err
+}
+
+// Answers the path within the set of the named fixture.  The name is either a file name, found at any depth, or the
+// path of the fixture within the set.  If `searchdirs` are supplied, the fixture must have at least one of them as an
+// ancestor directory.  An error wrapping ErrFixtureNotFound is answered if no fixture is found.
+func (s *FixtureSet) Lookup(name string, searchdirs ...string) (string, error) {
+	if strings.Contains(name, "/") {
+		if !fs.ValidPath(name) {
+			return "", fmt.Errorf("fs: invalid fixture path '%s'", name)
+		}
+		if info, err := fs.Stat(s.fsys, name); err == nil && !info.IsDir() && ancestorOf(name, searchdirs) {
+			return name, nil
+		}
+		return "", fmt.Errorf("%w: %s", ErrFixtureNotFound, name)
+	}
+
+	var found string
+	err := s.walk(func(p string) bool {
+		if path.Base(p) == name && ancestorOf(p, searchdirs) {
+			found = p
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("%w: %s", ErrFixtureNotFound, name)
+	}
+	return found, nil
+}
+
+// Answers the content of the named fixture (see Lookup)
+func (s *FixtureSet) ReadFile(name string, searchdirs ...string) ([]byte, error) {
+	p, err := s.Lookup(name, searchdirs...)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(s.fsys, p)
+}
+
+// Stops a walk of the fixtures early
+var errStopWalk = errors.New("stop walking")
+
+// Walks the regular files of the set in lexical order, until the function answers false
+func (s *FixtureSet) walk(fn func(p string) bool) error {
+	err := fs.WalkDir(s.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !fn(p) {
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil && err != errStopWalk {
+		return fmt.Errorf("fs: error walking the fixtures: %w", err)
+	}
+	return nil
+}
+
+// Answers true if one of the directories of the path is among the candidates, or there are no candidates
+func ancestorOf(p string, candidates []string) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		for _, candidate := range candidates {
+			if dir == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package fs
+
+import (
+	"embed"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata
+var testdata embed.FS
+
+func Test_FixtureSetLookup(t *testing.T) {
+	s := NewFixtureSet(testdata)
+
+	p, err := s.Lookup("object-01.json")
+	require.Nil(t, err)
+	assert.Equal(t, "testdata/expected/object-01.json", p)
+
+	// the first in lexical order, unless a search directory is supplied
+	p, err = s.Lookup("collection-01.json")
+	require.Nil(t, err)
+	assert.Equal(t, "testdata/expected/collections/collection-01.json", p)
+	p, err = s.Lookup("collection-01.json", "other")
+	require.Nil(t, err)
+	assert.Equal(t, "testdata/other/collection-01.json", p)
+
+	p, err = s.Lookup("testdata/other/collection-01.json")
+	require.Nil(t, err)
+	assert.Equal(t, "testdata/other/collection-01.json", p)
+
+	_, err = s.Lookup("object-01.json", "other")
+	assert.True(t, errors.Is(err, ErrFixtureNotFound))
+	_, err = s.Lookup("testdata/expected")
+	assert.True(t, errors.Is(err, ErrFixtureNotFound))
+	_, err = s.Lookup("missing.json")
+	assert.True(t, errors.Is(err, ErrFixtureNotFound))
+	_, err = s.Lookup("../fs.go")
+	assert.NotNil(t, err)
+
+	b, err := s.ReadFile("collection-02.json")
+	require.Nil(t, err)
+	assert.Contains(t, string(b), "Dorothea Lange")
+}
+
+func Test_FixtureSetGlob(t *testing.T) {
+	s := NewFixtureSet(testdata)
+
+	all, err := s.List()
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"testdata/expected/collections/collection-01.json",
+		"testdata/expected/collections/collection-02.json",
+		"testdata/expected/object-01.json",
+		"testdata/other/collection-01.json",
+	}, all)
+
+	matches, err := s.Glob("collection-*.json")
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(matches))
+	matches, err = s.Glob("testdata/expected/*.json")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"testdata/expected/object-01.json"}, matches)
+	matches, err = s.Glob("*.csv")
+	require.Nil(t, err)
+	assert.Empty(t, matches)
+
+	_, err = s.Glob("[")
+	assert.NotNil(t, err)
+}
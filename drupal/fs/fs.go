@@ -1,13 +1,17 @@
+//go:build !notestify
+// +build !notestify
+
 // Deprecated package of functions used to discover test resources
 //
-// Instead of using this package, consider using go:embed instead
+// Instead of walking the file system, consider embedding test resources with go:embed, and discovering them with a
+// FixtureSet
 package fs
 
 import (
 	"errors"
 	"fmt"
-	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/logging"
 	"github.com/stretchr/testify/require"
 	"io/fs"
 	"os"
@@ -16,8 +20,6 @@ import (
 	"testing"
 )
 
-var logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
-
 // Searches the file system for the named file, and answers the first path matching the file name.
 //
 // The `name` or optional `searchdirs` should not contain any path separators.  If `searchdirs` is supplied, the
@@ -30,13 +32,13 @@ func FindExpectedJson(t *testing.T, name string, searchdirs ...string) string {
 	var expectedJsonFile string
 
 	if strings.Contains(name, string(os.PathSeparator)) {
-		logger.Panic().Msgf("Supplied file name '%s' must not contain path separator '%s'", name, string(os.PathSeparator))
+		panicf("Supplied file name '%s' must not contain path separator '%s'", name, string(os.PathSeparator))
 	}
 
 	if searchdirs != nil && len(searchdirs) > 0 {
 		for _, dir := range searchdirs {
 			if strings.Contains(dir, string(os.PathSeparator)) {
-				logger.Panic().Msgf("Supplied search directory '%s' must not contain path separator '%s'", dir, string(os.PathSeparator))
+				panicf("Supplied search directory '%s' must not contain path separator '%s'", dir, string(os.PathSeparator))
 			}
 		}
 	}
@@ -50,7 +52,7 @@ func FindExpectedJson(t *testing.T, name string, searchdirs ...string) string {
 					basedirs = append(basedirs, path)
 					return filepath.SkipDir
 				} else {
-					logger.Printf("skipping dir: %s", info.Name())
+					logging.Debugf("skipping dir: %s", info.Name())
 				}
 			}
 			return nil
@@ -64,7 +66,7 @@ func FindExpectedJson(t *testing.T, name string, searchdirs ...string) string {
 			require.Nil(t, err, "Unexpected error when searching for '%s': %s", name, err)
 
 			if info.IsDir() {
-				logger.Printf("searching dir: %s", info.Name())
+				logging.Debugf("searching dir: %s", info.Name())
 			}
 
 			// Resolve the file
@@ -77,11 +79,18 @@ func FindExpectedJson(t *testing.T, name string, searchdirs ...string) string {
 	}
 
 	if expectedJsonFile == "" {
-		logger.Panic().Msgf("Could not locate file '%v'", name)
+		panicf("Could not locate file '%v'", name)
 	}
 	return expectedJsonFile
 }
 
+// Logs the message at logging.Error, and panics with it
+func panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	logging.Errorf("%s", msg)
+	panic(msg)
+}
+
 func pathContains(path string, candidates []string) bool {
 	for _, pathelement := range strings.Split(path, string(os.PathSeparator)) {
 		for _, candidate := range candidates {
@@ -93,3 +102,12 @@ func pathContains(path string, candidates []string) bool {
 
 	return false
 }
+
+// Behaves as FindExpectedJson, searching beneath the base directory of the test suite named by the Config (see
+// env.Config.TestBasedir) if it names one
+func FindExpectedJsonWith(t *testing.T, c *env.Config, name string) string {
+	if c != nil && c.TestBasedir != "" {
+		return FindExpectedJson(t, name, c.TestBasedir)
+	}
+	return FindExpectedJson(t, name)
+}
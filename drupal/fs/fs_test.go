@@ -1,3 +1,6 @@
+//go:build !notestify
+// +build !notestify
+
 package fs
 
 import (
@@ -0,0 +1,217 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The kinds of the files of a Manifest
+const (
+	// An expected JSON fixture
+	KindExpected = "expected"
+	// A binary asset, e.g. an image ingested by a test
+	KindAsset = "asset"
+)
+
+// A file listed by a Manifest
+type ManifestEntry struct {
+	// The path of the file relative to the directory of the manifest, separated by `/`
+	Path string `json:"path" yaml:"path"`
+	// KindExpected or KindAsset
+	Kind string `json:"kind" yaml:"kind"`
+	// The hex-encoded SHA-256 checksum of the content of the file
+	Sha256 string `json:"sha256" yaml:"sha256"`
+}
+
+// Lists the fixtures of a test suite (expected JSON and binary assets) with their checksums, so that a suite may
+// check before its tests run that its fixture tree is complete and current, rather than failing in the middle of a
+// run on a missing or stale file.  A manifest is read from, and written to, YAML (`fixtures.yaml`) or JSON
+// (`fixtures.json`):
+//
+//	files:
+//	  - path: expected/collection-01.json
+//	    kind: expected
+//	    sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+type Manifest struct {
+	Files []ManifestEntry `json:"files" yaml:"files"`
+}
+
+// A file of the fixture tree that does not match its Manifest
+type ManifestProblem struct {
+	Path string
+	// True if the file is missing; otherwise its content does not match its checksum
+	Missing bool
+	// The checksum of the content of the file, if it is stale
+	Sha256 string
+}
+
+// Answers the problem as e.g. `expected/collection-01.json: missing` or `expected/collection-01.json: stale
+// (sha256 0a1b...)`
+func (p ManifestProblem) String() string {
+	if p.Missing {
+		return fmt.Sprintf("%s: missing", p.Path)
+	}
+	return fmt.Sprintf("%s: stale (sha256 %s)", p.Path, p.Sha256)
+}
+
+// Reads the manifest from the file, as JSON if its name ends with `.json` and otherwise as YAML
+func LoadManifest(file string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("fs: error reading manifest: %w", err)
+	}
+	m := &Manifest{}
+	if isJson(file) {
+		err = json.Unmarshal(b, m)
+	} else {
+		err = yaml.Unmarshal(b, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fs: error parsing manifest %s: %w", file, err)
+	}
+	return m, nil
+}
+
+// Writes the manifest to the file, as JSON if its name ends with `.json` and otherwise as YAML
+func (m *Manifest) Write(file string) error {
+	var b []byte
+	var err error
+	if isJson(file) {
+		b, err = json.MarshalIndent(m, "", "  ")
+		b = append(b, '\n')
+	} else {
+		b, err = yaml.Marshal(m)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(file, b, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("fs: error writing manifest %s: %w", file, err)
+	}
+	return nil
+}
+
+// Creates a manifest of the files of the file system (e.g. os.DirFS of a testdata directory) whose paths match any
+// of the patterns (see FixtureSet.Glob), or of every file if no pattern is supplied, ordered by path.  Files whose
+// names end with `.json` are listed as KindExpected, others as KindAsset.  Manifests themselves (`fixtures.yaml`,
+// `fixtures.yml` and `fixtures.json`) are not listed.
+func GenerateManifest(fsys fs.FS, patterns ...string) (*Manifest, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	s := NewFixtureSet(fsys)
+	seen := map[string]bool{}
+	m := &Manifest{Files: []ManifestEntry{}}
+	for _, pattern := range patterns {
+		paths, err := s.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			if seen[p] || isManifest(p) {
+				continue
+			}
+			seen[p] = true
+			sum, err := checksum(fsys, p)
+			if err != nil {
+				return nil, err
+			}
+			kind := KindAsset
+			if isJson(p) {
+				kind = KindExpected
+			}
+			m.Files = append(m.Files, ManifestEntry{Path: p, Kind: kind, Sha256: sum})
+		}
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	return m, nil
+}
+
+// Answers the files listed by the manifest that are missing from the file system, or whose content does not match
+// their checksum, in the order they are listed.  An error is answered if a file cannot be read.
+func (m *Manifest) Check(fsys fs.FS) ([]ManifestProblem, error) {
+	var problems []ManifestProblem
+	for _, f := range m.Files {
+		if !fs.ValidPath(f.Path) {
+			return nil, fmt.Errorf("fs: invalid manifest path '%s'", f.Path)
+		}
+		sum, err := checksum(fsys, f.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			problems = append(problems, ManifestProblem{Path: f.Path, Missing: true})
+		case err != nil:
+			return nil, err
+		case !strings.EqualFold(sum, f.Sha256):
+			problems = append(problems, ManifestProblem{Path: f.Path, Sha256: sum})
+		}
+	}
+	return problems, nil
+}
+
+// Checks the fixture tree beside the manifest file (i.e. the files of its directory) against it, answering an error
+// listing every missing and stale file, so that TestMain may fail before tests run:
+//
+//	func TestMain(m *testing.M) {
+//		if err := fs.VerifyManifest("testdata/fixtures.yaml"); err != nil {
+//			log.Fatal(err)
+//		}
+//		os.Exit(m.Run())
+//	}
+func VerifyManifest(file string) error {
+	m, err := LoadManifest(file)
+	if err != nil {
+		return err
+	}
+	problems, err := m.Check(os.DirFS(filepath.Dir(file)))
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		lines := make([]string, 0, len(problems))
+		for _, p := range problems {
+			lines = append(lines, "  "+p.String())
+		}
+		return fmt.Errorf("fs: %d of %d fixtures do not match manifest %s:\n%s", len(problems), len(m.Files), file,
+			strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// Answers the hex-encoded SHA-256 checksum of the content of the file
+func checksum(fsys fs.FS, p string) (string, error) {
+	f, err := fsys.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("fs: error reading %s: %w", p, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isJson(p string) bool {
+	return strings.EqualFold(path.Ext(p), ".json")
+}
+
+func isManifest(p string) bool {
+	switch path.Base(p) {
+	case "fixtures.yaml", "fixtures.yml", "fixtures.json":
+		return true
+	}
+	return false
+}
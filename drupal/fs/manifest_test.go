@@ -0,0 +1,78 @@
+package fs
+
+import (
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateManifest(t *testing.T) {
+	sub, err := fs.Sub(testdata, "testdata")
+	require.Nil(t, err)
+
+	m, err := GenerateManifest(sub)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(m.Files))
+	assert.Equal(t, "expected/collections/collection-01.json", m.Files[0].Path)
+	assert.Equal(t, KindExpected, m.Files[0].Kind)
+	assert.Equal(t, 64, len(m.Files[0].Sha256))
+
+	m, err = GenerateManifest(sub, "object-*.json", "expected/*/*.json", "object-01.json")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(m.Files))
+	assert.Equal(t, "expected/object-01.json", m.Files[2].Path)
+
+	problems, err := m.Check(sub)
+	require.Nil(t, err)
+	assert.Empty(t, problems)
+}
+
+func Test_VerifyManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "expected"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "expected", "moonrise.json"), []byte(`{"title": "Moonrise"}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "moonrise.tif"), []byte("II*\x00"), 0644))
+
+	m, err := GenerateManifest(os.DirFS(dir))
+	require.Nil(t, err)
+	assert.Equal(t, []string{KindExpected, KindAsset}, []string{m.Files[0].Kind, m.Files[1].Kind})
+
+	for _, name := range []string{"fixtures.yaml", "fixtures.json"} {
+		manifest := filepath.Join(dir, name)
+		require.Nil(t, m.Write(manifest))
+		loaded, err := LoadManifest(manifest)
+		require.Nil(t, err)
+		assert.Equal(t, m, loaded)
+		assert.Nil(t, VerifyManifest(manifest))
+	}
+
+	// the manifests themselves are not listed
+	regenerated, err := GenerateManifest(os.DirFS(dir))
+	require.Nil(t, err)
+	assert.Equal(t, m, regenerated)
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "expected", "moonrise.json"), []byte(`{"title": "Moonset"}`), 0644))
+	require.Nil(t, os.Remove(filepath.Join(dir, "moonrise.tif")))
+	problems, err := m.Check(os.DirFS(dir))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(problems))
+	assert.False(t, problems[0].Missing)
+	assert.Contains(t, problems[0].String(), "expected/moonrise.json: stale (sha256 ")
+	assert.Equal(t, "moonrise.tif: missing", problems[1].String())
+
+	err = VerifyManifest(filepath.Join(dir, "fixtures.yaml"))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "2 of 2 fixtures do not match manifest")
+
+	_, err = LoadManifest(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(t, err)
+	_, err = (&Manifest{Files: []ManifestEntry{{Path: "../moonrise.tif"}}}).Check(os.DirFS(dir))
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrFixtureNotFound))
+}
@@ -0,0 +1,43 @@
+//go:build !notestify
+// +build !notestify
+
+package iiif
+
+import (
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the manifest has the number of canvases
+func AssertCanvasCount(t assert.TestingT, m *Manifest, expected int) bool {
+	return assert.Equal(t, expected, len(m.Canvases), "manifest %s has an unexpected number of canvases", m.Id)
+}
+
+// Asserts that each canvas of the manifest has an image service beneath the base url, e.g.
+// `https://islandora-idc.traefik.me/cantaloupe/iiif/2/`
+func AssertImageServices(t assert.TestingT, m *Manifest, baseUrl string) bool {
+	ok := true
+	for i, c := range m.Canvases {
+		found := false
+		for _, s := range c.ImageServices {
+			found = found || strings.HasPrefix(s, baseUrl)
+		}
+		ok = assert.True(t, found, "canvas %d (%s) of manifest %s has no image service beneath %s: %v", i, c.Id, m.Id, baseUrl, c.ImageServices) && ok
+	}
+	return ok
+}
+
+// Asserts that the label of the manifest is the expected value, e.g. the title of its node
+func AssertLabel(t assert.TestingT, m *Manifest, expected string) bool {
+	return assert.Equal(t, []string{expected}, m.Label, "manifest %s has an unexpected label", m.Id)
+}
+
+// Asserts that the manifest has a metadata entry with the label and the expected values, in any order
+func AssertMetadata(t assert.TestingT, m *Manifest, label string, expected ...string) bool {
+	actual := m.MetadataValues(label)
+	if !assert.NotNil(t, actual, "manifest %s has no metadata labeled '%s'", m.Id, label) {
+		return false
+	}
+	return assert.ElementsMatch(t, expected, actual, "metadata '%s' of manifest %s differs", label, m.Id)
+}
@@ -0,0 +1,224 @@
+// Retrieves and verifies the IIIF Presentation manifests of repository objects.  Manifests of Presentation 2 and 3
+// are unmarshaled into ManifestV2 and ManifestV3, and summarized by a version-agnostic Manifest for assertions:
+//
+//	c := iiif.NewClient(baseUrl, username, password)
+//	m, err := c.FetchForTitle("Moonrise")
+//	iiif.AssertCanvasCount(t, m, 2)
+//	iiif.AssertImageServices(t, m, "https://islandora-idc.traefik.me/cantaloupe/iiif/2/")
+package iiif
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+var ErrUnsupportedVersion = errors.New("iiif: unsupported Presentation API version")
+
+// The path of the manifest of a node, if a Client names none; `%d` is replaced by the node id
+const DefaultManifestPath = "/node/%d/manifest"
+
+// The contexts identifying the version of a manifest
+const (
+	ContextV2 = "http://iiif.io/api/presentation/2/context.json"
+	ContextV3 = "http://iiif.io/api/presentation/3/context.json"
+)
+
+// A manifest, summarized without regard to its Presentation API version
+type Manifest struct {
+	// The Presentation API version, 2 or 3
+	Version  int
+	Id       string
+	Label    []string
+	Metadata []Metadata
+	Canvases []Canvas
+	// The manifest as unmarshaled, either a *ManifestV2 or a *ManifestV3
+	Raw interface{}
+}
+
+// A metadata entry of a manifest
+type Metadata struct {
+	Label  string
+	Values []string
+}
+
+// A canvas of a manifest
+type Canvas struct {
+	Id     string
+	Label  []string
+	Width  int
+	Height int
+	// The ids of the image services of the canvas's images
+	ImageServices []string
+}
+
+// Answers the values of the metadata entry with the label, or nil if the manifest has none
+func (m *Manifest) MetadataValues(label string) []string {
+	for _, md := range m.Metadata {
+		if md.Label == label {
+			return md.Values
+		}
+	}
+	return nil
+}
+
+// Parses a manifest of Presentation API 2 or 3, identified by its `@context`.  An error wrapping
+// ErrUnsupportedVersion is answered for other versions.
+func Parse(b []byte) (*Manifest, error) {
+	var probe struct {
+		Context interface{} `json:"@context"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, fmt.Errorf("iiif: unable to unmarshal manifest: %w", err)
+	}
+	contexts, ok := probe.Context.([]interface{})
+	if !ok {
+		contexts = []interface{}{probe.Context}
+	}
+	for _, c := range contexts {
+		switch c {
+		case ContextV2:
+			return parseV2(b)
+		case ContextV3:
+			return parseV3(b)
+		}
+	}
+	return nil, fmt.Errorf("%w: @context %v", ErrUnsupportedVersion, probe.Context)
+}
+
+func parseV2(b []byte) (*Manifest, error) {
+	raw := &ManifestV2{}
+	if err := json.Unmarshal(b, raw); err != nil {
+		return nil, fmt.Errorf("iiif: unable to unmarshal Presentation 2 manifest: %w", err)
+	}
+	m := &Manifest{Version: 2, Id: raw.Id, Label: raw.Label, Raw: raw}
+	for _, md := range raw.Metadata {
+		m.Metadata = append(m.Metadata, Metadata{Label: strings.Join(md.Label, " "), Values: md.Value})
+	}
+	for _, seq := range raw.Sequences {
+		for _, c := range seq.Canvases {
+			canvas := Canvas{Id: c.Id, Label: c.Label, Width: c.Width, Height: c.Height}
+			for _, image := range c.Images {
+				if id := image.Resource.Service.Id; id != "" {
+					canvas.ImageServices = append(canvas.ImageServices, id)
+				}
+			}
+			m.Canvases = append(m.Canvases, canvas)
+		}
+	}
+	return m, nil
+}
+
+func parseV3(b []byte) (*Manifest, error) {
+	raw := &ManifestV3{}
+	if err := json.Unmarshal(b, raw); err != nil {
+		return nil, fmt.Errorf("iiif: unable to unmarshal Presentation 3 manifest: %w", err)
+	}
+	m := &Manifest{Version: 3, Id: raw.Id, Label: raw.Label.Values(), Raw: raw}
+	for _, md := range raw.Metadata {
+		m.Metadata = append(m.Metadata, Metadata{Label: strings.Join(md.Label.Values(), " "), Values: md.Value.Values()})
+	}
+	for _, c := range raw.Items {
+		canvas := Canvas{Id: c.Id, Label: c.Label.Values(), Width: c.Width, Height: c.Height}
+		for _, page := range c.Items {
+			for _, annotation := range page.Items {
+				for _, s := range annotation.Body.Service {
+					if id := s.ServiceId(); id != "" {
+						canvas.ImageServices = append(canvas.ImageServices, id)
+					}
+				}
+			}
+		}
+		m.Canvases = append(m.Canvases, canvas)
+	}
+	return m, nil
+}
+
+// Retrieves the manifests of nodes
+type Client struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The path of the manifest of a node; DefaultManifestPath if empty
+	ManifestPath string
+}
+
+// Creates a Client for the Drupal site at the base url
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Retrieves and parses the manifest of the node with the id (i.e. its `drupal_internal__nid`)
+func (c *Client) Fetch(nid int) (*Manifest, error) {
+	path := c.ManifestPath
+	if path == "" {
+		path = DefaultManifestPath
+	}
+	u := strings.TrimSuffix(c.BaseUrl, "/") + fmt.Sprintf(path, nid)
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("iiif: %w", err)
+	}
+	if strings.TrimSpace(c.Username) != "" {
+		req.SetBasicAuth(c.Username, c.Password.Reveal())
+	}
+	req.Header.Set("Accept", "application/ld+json, application/json")
+
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("iiif: error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("iiif: error reading response body from %s: %w", u, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("iiif: %d status encountered when requesting %s", res.StatusCode, u)
+	}
+	return Parse(body)
+}
+
+// Retrieves and parses the manifest of the single repository object with the title
+func (c *Client) FetchForTitle(title string) (*Manifest, error) {
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: model.Node,
+		DrupalBundle: model.RepositoryObject,
+		Filter:       "title",
+		Value:        title,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+	node := struct {
+		Data []struct {
+			Attributes struct {
+				Nid int `json:"drupal_internal__nid"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}{}
+	if err := u.Fetch(&node); err != nil {
+		return nil, err
+	}
+	if len(node.Data) != 1 {
+		return nil, fmt.Errorf("iiif: exactly one %s is expected to have the title '%s', but found %d", model.RepositoryObject, title, len(node.Data))
+	}
+	return c.Fetch(node.Data[0].Attributes.Nid)
+}
+
+func sortedLanguages(l LanguageMap) []string {
+	langs := make([]string, 0, len(l))
+	for lang := range l {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
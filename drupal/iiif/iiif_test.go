@@ -0,0 +1,143 @@
+//go:build !notestify
+// +build !notestify
+
+package iiif
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cantaloupe = "https://islandora-idc.traefik.me/cantaloupe/iiif/"
+
+func parseFile(t *testing.T, path string) *Manifest {
+	b, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	m, err := Parse(b)
+	require.Nil(t, err)
+	return m
+}
+
+func Test_ParseV2(t *testing.T) {
+	m := parseFile(t, "testdata/manifest-v2.json")
+
+	assert.Equal(t, 2, m.Version)
+	assert.IsType(t, &ManifestV2{}, m.Raw)
+	assert.Equal(t, "https://islandora-idc.traefik.me/node/1/manifest", m.Id)
+	assert.Equal(t, []string{"Moonrise"}, m.Label)
+	assert.Equal(t, []string{"Adams, Ansel"}, m.MetadataValues("Creator"))
+	assert.Equal(t, []string{"Photographs", "Landscapes"}, m.MetadataValues("Genre"))
+	assert.Nil(t, m.MetadataValues("Subject"))
+	require.Len(t, m.Canvases, 2)
+	assert.Equal(t, Canvas{
+		Id:            "https://islandora-idc.traefik.me/node/1/canvas/2",
+		Label:         []string{"Front"},
+		Width:         640,
+		Height:        480,
+		ImageServices: []string{cantaloupe + "2/front.jpg"},
+	}, m.Canvases[0])
+	assert.Empty(t, m.Canvases[1].ImageServices)
+}
+
+func Test_ParseV3(t *testing.T) {
+	m := parseFile(t, "testdata/manifest-v3.json")
+
+	assert.Equal(t, 3, m.Version)
+	assert.IsType(t, &ManifestV3{}, m.Raw)
+	assert.Equal(t, []string{"Moonrise"}, m.Label)
+	assert.Equal(t, []string{"Adams, Ansel"}, m.MetadataValues("Creator"))
+	require.Len(t, m.Canvases, 2)
+	assert.Equal(t, []string{"Front"}, m.Canvases[0].Label)
+	// Image API 2 services carry `@id`, Image API 3 services carry `id`
+	assert.Equal(t, []string{cantaloupe + "2/front.jpg"}, m.Canvases[0].ImageServices)
+	assert.Equal(t, []string{cantaloupe + "3/back.jpg"}, m.Canvases[1].ImageServices)
+}
+
+func Test_ParseUnsupported(t *testing.T) {
+	_, err := Parse([]byte(`{"@context": "http://iiif.io/api/presentation/1/context.json"}`))
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+
+	_, err = Parse([]byte(`<html></html>`))
+	assert.NotNil(t, err)
+}
+
+func Test_Value(t *testing.T) {
+	m, err := Parse([]byte(`{"@context": "` + ContextV2 + `", "label": [{"@value": "Moonrise", "@language": "en"}, "Lune"]}`))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Moonrise", "Lune"}, m.Label)
+}
+
+func Test_Assertions(t *testing.T) {
+	v2 := parseFile(t, "testdata/manifest-v2.json")
+	v3 := parseFile(t, "testdata/manifest-v3.json")
+
+	for _, m := range []*Manifest{v2, v3} {
+		assert.True(t, AssertCanvasCount(t, m, 2))
+		assert.True(t, AssertLabel(t, m, "Moonrise"))
+		assert.True(t, AssertMetadata(t, m, "Genre", "Landscapes", "Photographs"))
+
+		rec := &asserttest.Recorder{}
+		assert.False(t, AssertCanvasCount(rec, m, 3))
+		assert.Contains(t, rec.String(), "has an unexpected number of canvases")
+		rec = &asserttest.Recorder{}
+		assert.False(t, AssertLabel(rec, m, "Sunset"))
+		assert.Contains(t, rec.String(), "has an unexpected label")
+		rec = &asserttest.Recorder{}
+		assert.False(t, AssertMetadata(rec, m, "Genre", "Photographs"))
+		assert.Contains(t, rec.String(), "metadata 'Genre' of manifest")
+		rec = &asserttest.Recorder{}
+		assert.False(t, AssertMetadata(rec, m, "Subject", "Moons"))
+		assert.Contains(t, rec.String(), "has no metadata labeled 'Subject'")
+	}
+
+	// the second canvas of the v2 manifest has no image service
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertImageServices(rec, v2, cantaloupe))
+	assert.Contains(t, rec.String(), "canvas 1 (https://islandora-idc.traefik.me/node/1/canvas/3)")
+	assert.Contains(t, rec.String(), "has no image service beneath "+cantaloupe+":")
+	assert.True(t, AssertImageServices(t, v3, cantaloupe))
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertImageServices(rec, v3, cantaloupe+"2/"))
+	assert.Contains(t, rec.String(), "has no image service beneath "+cantaloupe+"2/:")
+}
+
+func Test_FetchForTitle(t *testing.T) {
+	manifest, err := ioutil.ReadFile("testdata/manifest-v3.json")
+	require.Nil(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object" && r.URL.Query().Get("filter[title]") == "Moonrise":
+			_, _ = w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "n1", "attributes": {"title": "Moonrise", "drupal_internal__nid": 7}}]}`))
+		case r.URL.Path == "/jsonapi/node/islandora_object":
+			_, _ = w.Write([]byte(`{"data": []}`))
+		case r.URL.Path == "/node/7/manifest":
+			_, _ = w.Write(manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, "", "")
+
+	m, err := c.FetchForTitle("Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, 3, m.Version)
+	AssertCanvasCount(t, m, 2)
+
+	_, err = c.FetchForTitle("Sunset")
+	assert.NotNil(t, err)
+
+	_, err = c.Fetch(8)
+	assert.Contains(t, err.Error(), "404")
+
+	c.ManifestPath = "/node/%d/book-manifest"
+	_, err = c.Fetch(7)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,128 @@
+package iiif
+
+import "encoding/json"
+
+// A value of a IIIF Presentation 2 property which may be a string, a language-tagged value
+// (`{"@value": "Moonrise", "@language": "en"}`), or a list of either.  The values are answered without their language.
+type Value []string
+
+func (v *Value) UnmarshalJSON(b []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*v = Value{}
+	items, ok := raw.([]interface{})
+	if !ok {
+		items = []interface{}{raw}
+	}
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			*v = append(*v, item)
+		case map[string]interface{}:
+			if s, ok := item["@value"].(string); ok {
+				*v = append(*v, s)
+			}
+		}
+	}
+	return nil
+}
+
+// A IIIF Presentation 3 language map, e.g. `{"en": ["Moonrise"]}`
+type LanguageMap map[string][]string
+
+// Answers the values of every language, ordered by language
+func (l LanguageMap) Values() []string {
+	var values []string
+	for _, lang := range sortedLanguages(l) {
+		values = append(values, l[lang]...)
+	}
+	return values
+}
+
+// A IIIF Presentation 2 manifest
+type ManifestV2 struct {
+	Context   interface{}  `json:"@context"`
+	Id        string       `json:"@id"`
+	Type      string       `json:"@type"`
+	Label     Value        `json:"label"`
+	Metadata  []MetadataV2 `json:"metadata"`
+	Sequences []struct {
+		Canvases []CanvasV2 `json:"canvases"`
+	} `json:"sequences"`
+}
+
+type MetadataV2 struct {
+	Label Value `json:"label"`
+	Value Value `json:"value"`
+}
+
+type CanvasV2 struct {
+	Id     string `json:"@id"`
+	Label  Value  `json:"label"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Images []struct {
+		Resource struct {
+			Id      string    `json:"@id"`
+			Format  string    `json:"format"`
+			Service ServiceV2 `json:"service"`
+		} `json:"resource"`
+	} `json:"images"`
+}
+
+type ServiceV2 struct {
+	Context string      `json:"@context"`
+	Id      string      `json:"@id"`
+	Profile interface{} `json:"profile"`
+}
+
+// A IIIF Presentation 3 manifest
+type ManifestV3 struct {
+	Context  interface{}  `json:"@context"`
+	Id       string       `json:"id"`
+	Type     string       `json:"type"`
+	Label    LanguageMap  `json:"label"`
+	Metadata []MetadataV3 `json:"metadata"`
+	Items    []CanvasV3   `json:"items"`
+}
+
+type MetadataV3 struct {
+	Label LanguageMap `json:"label"`
+	Value LanguageMap `json:"value"`
+}
+
+type CanvasV3 struct {
+	Id     string      `json:"id"`
+	Type   string      `json:"type"`
+	Label  LanguageMap `json:"label"`
+	Width  int         `json:"width"`
+	Height int         `json:"height"`
+	Items  []struct {
+		Items []struct {
+			Body struct {
+				Id      string      `json:"id"`
+				Type    string      `json:"type"`
+				Format  string      `json:"format"`
+				Service []ServiceV3 `json:"service"`
+			} `json:"body"`
+		} `json:"items"`
+	} `json:"items"`
+}
+
+// A IIIF Presentation 3 service.  Image API 2 services retain the `@id` and `@type` of Presentation 2.
+type ServiceV3 struct {
+	Id       string `json:"id"`
+	LegacyId string `json:"@id"`
+	Type     string `json:"type"`
+	Profile  string `json:"profile"`
+}
+
+// Answers the id of the service
+func (s ServiceV3) ServiceId() string {
+	if s.Id != "" {
+		return s.Id
+	}
+	return s.LegacyId
+}
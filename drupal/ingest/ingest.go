@@ -0,0 +1,241 @@
+// Ingests migration source CSVs and tracks the entities they create, so that expected models may be matched to live
+// entities by the id of their source row rather than by title.
+//
+// An Ingester places the CSV where the migration reads its source (a shared directory, or an upload endpoint),
+// triggers the migration, and resolves the UUID of the entity created for each row:
+//
+//	i := ingest.NewIngester(baseUrl, username, password, &ingest.DirPlacer{Dir: "/ingest"}, runner)
+//	uuids, err := i.Ingest("testdata/objects.csv", "idc_ingest_new_items", "node", "islandora_object")
+//	uuids["io_1"] // the UUID of the node created for row io_1
+package ingest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/migrate"
+)
+
+var ErrUnresolved = errors.New("no entity was found for source rows")
+
+// The column of a source CSV identifying its rows, if an Ingester names none
+const DefaultIdColumn = "local_id"
+
+// The attribute of a migrated entity carrying the id of its source row, if an Ingester names none
+const DefaultIdAttribute = "field_unique_id"
+
+// Places a source CSV where a migration reads its source
+type Placer interface {
+	// Places the content of the CSV, which was read from the named file
+	Place(name string, content []byte) error
+}
+
+// Places source CSVs in a directory, e.g. a volume shared with the Drupal container
+type DirPlacer struct {
+	Dir string
+	// The name of the placed file; the name of the source CSV if empty
+	Name string
+}
+
+// Writes the content to the directory
+func (p *DirPlacer) Place(name string, content []byte) error {
+	if p.Name != "" {
+		name = p.Name
+	}
+	dest := filepath.Join(p.Dir, filepath.Base(name))
+	if err := ioutil.WriteFile(dest, content, 0644); err != nil {
+		return fmt.Errorf("ingest: unable to place %s: %w", dest, err)
+	}
+	return nil
+}
+
+// Uploads source CSVs to an HTTP endpoint, which must answer a 2xx status once the CSV is stored
+type UploadPlacer struct {
+	// The url of the endpoint; `%s` is replaced by the name of the source CSV, if present
+	Url      string
+	Username string
+	Password env.Secret
+	// The method of the upload; PUT if empty
+	Method string
+}
+
+// Uploads the content to the endpoint
+func (p *UploadPlacer) Place(name string, content []byte) error {
+	u := p.Url
+	if strings.Contains(u, "%s") {
+		u = fmt.Sprintf(u, filepath.Base(name))
+	}
+	method := p.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("ingest: %w", err)
+	}
+	if strings.TrimSpace(p.Username) != "" {
+		req.SetBasicAuth(p.Username, p.Password.Reveal())
+	}
+	req.Header.Set("Content-Type", "text/csv")
+
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("ingest: error uploading %s to %s: %w", name, u, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("ingest: %d status encountered when uploading %s to %s", res.StatusCode, name, u)
+	}
+	return nil
+}
+
+// Ingests source CSVs using a Placer and a migrate.Runner, and resolves the entities created for their rows
+type Ingester struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	Placer   Placer
+	Runner   migrate.Runner
+	// The column identifying source rows; DefaultIdColumn if empty
+	IdColumn string
+	// The attribute of migrated entities carrying the id of their source row; DefaultIdAttribute if empty
+	IdAttribute string
+	// The time allowed for the migration to complete
+	Timeout time.Duration
+}
+
+// Creates an Ingester for the Drupal site at the base url, allowing migrations ten minutes to complete
+func NewIngester(baseUrl, username, password string, placer Placer, runner migrate.Runner) *Ingester {
+	return &Ingester{BaseUrl: baseUrl, Username: username, Password: env.Secret(password), Placer: placer, Runner: runner, Timeout: 10 * time.Minute}
+}
+
+// Places the source CSV, runs the migration, and answers the UUID of the entity of the type and bundle created for
+// each row, keyed by row id.  An error is answered if the migration fails any row; an error wrapping ErrUnresolved
+// is answered, along with the resolved rows, if no entity is found for some rows.
+func (i *Ingester) Ingest(path, migrationId, entityType, bundle string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ingest: %w", err)
+	}
+	ids, err := RowIds(bytes.NewReader(content), i.idColumn())
+	if err != nil {
+		return nil, fmt.Errorf("ingest: %s: %w", path, err)
+	}
+	if err := i.Placer.Place(path, content); err != nil {
+		return nil, err
+	}
+	status, err := migrate.Run(i.Runner, migrationId, i.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if status.Failed > 0 {
+		return nil, fmt.Errorf("ingest: migration '%s' failed %d of %d rows", migrationId, status.Failed, status.Total)
+	}
+	return i.Resolve(entityType, bundle, ids...)
+}
+
+// Answers the UUID of the entity of the type and bundle migrated from each row id, keyed by row id.  An error
+// wrapping ErrUnresolved is answered, along with the resolved rows, if no entity is found for some rows.
+func (i *Ingester) Resolve(entityType, bundle string, ids ...string) (map[string]string, error) {
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	attribute := i.IdAttribute
+	if attribute == "" {
+		attribute = DefaultIdAttribute
+	}
+
+	uuids := map[string]string{}
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      i.BaseUrl,
+		DrupalEntity: entityType,
+		DrupalBundle: bundle,
+		Username:     i.Username,
+		Password:     i.Password,
+	}
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			attributes, _ := d["attributes"].(map[string]interface{})
+			id, _ := attributes[attribute].(string)
+			if wanted[id] {
+				uuids[id], _ = d["id"].(string)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unresolved []string
+	for id := range wanted {
+		if _, ok := uuids[id]; !ok {
+			unresolved = append(unresolved, id)
+		}
+	}
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return uuids, fmt.Errorf("ingest: %w of %s--%s: %s", ErrUnresolved, entityType, bundle, strings.Join(unresolved, ", "))
+	}
+	return uuids, nil
+}
+
+func (i *Ingester) idColumn() string {
+	if i.IdColumn == "" {
+		return DefaultIdColumn
+	}
+	return i.IdColumn
+}
+
+// Answers the values of the id column of each row of the CSV, in order.  An error is answered if the CSV lacks the
+// column, or if a row has an empty or duplicate id.
+func RowIds(r io.Reader, column string) ([]string, error) {
+	rows := csv.NewReader(r)
+	header, err := rows.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the CSV header: %w", err)
+	}
+	index := -1
+	for i, name := range header {
+		// a CSV written by a spreadsheet may begin with a byte order mark
+		if strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) == column {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("the CSV has no '%s' column", column)
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	for line := 2; ; line++ {
+		row, err := rows.Read()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		id := strings.TrimSpace(row[index])
+		switch {
+		case id == "":
+			return nil, fmt.Errorf("row %d has an empty '%s'", line, column)
+		case seen[id]:
+			return nil, fmt.Errorf("row %d has a duplicate '%s' %s", line, column, id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+}
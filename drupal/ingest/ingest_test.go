@@ -0,0 +1,114 @@
+package ingest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const objectsCsv = `local_id,title,member_of
+io_1,Moonrise,c_1
+io_2,"Hernandez, New Mexico",c_1
+`
+
+// A migrate.Runner whose migrations complete immediately, creating the resources of the mock server
+type fakeRunner struct {
+	server   *jsonapitest.MockServer
+	created  []jsonapitest.Resource
+	status   migrate.Status
+	imported []string
+}
+
+func (r *fakeRunner) Import(id string) error {
+	r.imported = append(r.imported, id)
+	r.server.Add(r.created...)
+	return nil
+}
+
+func (r *fakeRunner) Status(id string) (*migrate.Status, error) {
+	status := r.status
+	status.Id = id
+	return &status, nil
+}
+
+func Test_Ingest(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	runner := &fakeRunner{server: m, status: migrate.Status{Status: migrate.Idle, Total: 2, Imported: 2}, created: []jsonapitest.Resource{
+		{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{"title": "Moonrise", "field_unique_id": "io_1"}},
+		{"type": "node--islandora_object", "id": "n2", "attributes": map[string]interface{}{"title": "Hernandez, New Mexico", "field_unique_id": "io_2"}},
+		{"type": "node--islandora_object", "id": "n3", "attributes": map[string]interface{}{"title": "Elsewhere", "field_unique_id": "io_3"}},
+	}}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "objects.csv")
+	require.Nil(t, ioutil.WriteFile(source, []byte(objectsCsv), 0644))
+	ingestDir := t.TempDir()
+
+	i := NewIngester(m.URL, "", "", &DirPlacer{Dir: ingestDir, Name: "idc_ingest.csv"}, runner)
+	uuids, err := i.Ingest(source, "idc_ingest_new_items", "node", "islandora_object")
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"io_1": "n1", "io_2": "n2"}, uuids)
+	assert.Equal(t, []string{"idc_ingest_new_items"}, runner.imported)
+	placed, err := ioutil.ReadFile(filepath.Join(ingestDir, "idc_ingest.csv"))
+	require.Nil(t, err)
+	assert.Equal(t, objectsCsv, string(placed))
+
+	uuids, err = i.Resolve("node", "islandora_object", "io_1", "io_4")
+	assert.ErrorIs(t, err, ErrUnresolved)
+	assert.Contains(t, err.Error(), "io_4")
+	assert.Equal(t, map[string]string{"io_1": "n1"}, uuids)
+
+	runner.status.Failed = 1
+	_, err = i.Ingest(source, "idc_ingest_new_items", "node", "islandora_object")
+	assert.NotNil(t, err)
+
+	_, err = (&Ingester{Placer: &DirPlacer{Dir: ingestDir}, Runner: runner, IdColumn: "unique_id", Timeout: time.Second}).
+		Ingest(source, "idc_ingest_new_items", "node", "islandora_object")
+	assert.NotNil(t, err)
+}
+
+func Test_UploadPlacer(t *testing.T) {
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method != http.MethodPut || r.URL.Path != "/ingest/objects.csv" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		uploaded = string(b)
+	}))
+	defer server.Close()
+
+	p := &UploadPlacer{Url: server.URL + "/ingest/%s"}
+	require.Nil(t, p.Place("testdata/objects.csv", []byte(objectsCsv)))
+	assert.Equal(t, objectsCsv, uploaded)
+
+	p.Method = http.MethodPost
+	assert.NotNil(t, p.Place("testdata/objects.csv", []byte(objectsCsv)))
+}
+
+func Test_RowIds(t *testing.T) {
+	ids, err := RowIds(strings.NewReader("\ufefflocal_id,title\nio_1,Moonrise\nio_2,\"Hernandez\"\n"), "local_id")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"io_1", "io_2"}, ids)
+
+	_, err = RowIds(strings.NewReader("local_id,title\nio_1,Moonrise\nio_1,Hernandez\n"), "local_id")
+	assert.Contains(t, err.Error(), "row 3 has a duplicate 'local_id' io_1")
+	_, err = RowIds(strings.NewReader("local_id,title\n,Moonrise\n"), "local_id")
+	assert.NotNil(t, err)
+	_, err = RowIds(strings.NewReader("id,title\nio_1,Moonrise\n"), "local_id")
+	assert.NotNil(t, err)
+	_, err = RowIds(strings.NewReader(""), "local_id")
+	assert.NotNil(t, err)
+}
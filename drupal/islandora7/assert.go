@@ -0,0 +1,21 @@
+//go:build !notestify
+// +build !notestify
+
+package islandora7
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the descriptive fields of the object with the PID match those of the node migrated from it
+func (a *Auditor) AssertParity(t assert.TestingT, pid string) bool {
+	discrepancies, err := a.Check(pid)
+	if !assert.Nil(t, err, "error comparing '%s': %s", pid, err) {
+		return false
+	}
+	ok := true
+	for _, d := range discrepancies {
+		ok = assert.Fail(t, "migrated value differs from source", "%s", d) && ok
+	}
+	return ok
+}
@@ -0,0 +1,336 @@
+// Compares migrated Drupal nodes with their source objects in an Islandora 7 (Fedora 3) repository, for spot-check
+// parity audits during a migration.  The FOXML and MODS of a source object are retrieved using the Fedora 3 REST API,
+// and the descriptive fields of the MODS are compared with the node whose `field_pid` carries the object's PID:
+//
+//	a := islandora7.NewAuditor(islandora7.NewClient("http://legacy:8080/fedora", "fedoraAdmin", pass), DrupalBaseurl, username, password)
+//	a.AssertParity(t, "islandora:1234")
+package islandora7
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+var ErrNotFound = errors.New("islandora7: not found")
+
+// The datastream carrying the descriptive metadata of an object
+const ModsDatastream = "MODS"
+
+// The FOXML object properties answered by Object
+const (
+	stateProperty    = "info:fedora/fedora-system:def/model#state"
+	labelProperty    = "info:fedora/fedora-system:def/model#label"
+	ownerProperty    = "info:fedora/fedora-system:def/model#ownerId"
+	createdProperty  = "info:fedora/fedora-system:def/model#createdDate"
+	modifiedProperty = "info:fedora/fedora-system:def/view#lastModifiedDate"
+)
+
+// Retrieves objects from the Fedora 3 REST API of an Islandora 7 repository
+type Client struct {
+	// The base url of Fedora, e.g. `http://legacy:8080/fedora`
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the Fedora 3 repository at the base url
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// The properties of a Fedora 3 object, as carried by its FOXML
+type Object struct {
+	Pid string
+	// E.g. `Active`, `Inactive` or `Deleted`
+	State    string
+	Label    string
+	OwnerId  string
+	Created  string
+	Modified string
+	// The ids of the object's datastreams, e.g. `MODS` and `OBJ`
+	Datastreams []string
+}
+
+type foxml struct {
+	Pid        string `xml:"PID,attr"`
+	Properties []struct {
+		Name  string `xml:"NAME,attr"`
+		Value string `xml:"VALUE,attr"`
+	} `xml:"objectProperties>property"`
+	Datastreams []struct {
+		Id string `xml:"ID,attr"`
+	} `xml:"datastream"`
+}
+
+// Retrieves the object with the PID using `/objects/{pid}/objectXML`.  An error wrapping ErrNotFound is answered if
+// it does not exist.
+func (c *Client) Object(pid string) (*Object, error) {
+	body, err := c.get("/objects/" + url.PathEscape(pid) + "/objectXML")
+	if err != nil {
+		return nil, err
+	}
+	doc := foxml{}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("islandora7: unable to unmarshal the FOXML of '%s': %w", pid, err)
+	}
+	o := &Object{Pid: doc.Pid}
+	for _, p := range doc.Properties {
+		switch p.Name {
+		case stateProperty:
+			o.State = p.Value
+		case labelProperty:
+			o.Label = p.Value
+		case ownerProperty:
+			o.OwnerId = p.Value
+		case createdProperty:
+			o.Created = p.Value
+		case modifiedProperty:
+			o.Modified = p.Value
+		}
+	}
+	for _, ds := range doc.Datastreams {
+		o.Datastreams = append(o.Datastreams, ds.Id)
+	}
+	return o, nil
+}
+
+// Retrieves the content of the MODS datastream of the object with the PID.  An error wrapping ErrNotFound is answered
+// if the object or its MODS datastream does not exist.
+func (c *Client) Mods(pid string) (*Mods, error) {
+	body, err := c.get("/objects/" + url.PathEscape(pid) + "/datastreams/" + ModsDatastream + "/content")
+	if err != nil {
+		return nil, err
+	}
+	m := &Mods{}
+	if err := xml.Unmarshal(body, m); err != nil {
+		return nil, fmt.Errorf("islandora7: unable to unmarshal the MODS of '%s': %w", pid, err)
+	}
+	return m, nil
+}
+
+// An object of the legacy repository and its descriptive metadata
+type Source struct {
+	Object *Object
+	Mods   *Mods
+}
+
+// Retrieves the object with the PID and its MODS
+func (c *Client) Source(pid string) (*Source, error) {
+	o, err := c.Object(pid)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.Mods(pid)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{Object: o, Mods: m}, nil
+}
+
+func (c *Client) get(path string) ([]byte, error) {
+	u := strings.TrimSuffix(c.BaseUrl, "/") + path
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("islandora7: %w", err)
+	}
+	if strings.TrimSpace(c.Username) != "" {
+		req.SetBasicAuth(c.Username, c.Password.Reveal())
+	}
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("islandora7: error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("islandora7: error reading response body from %s: %w", u, err)
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, u)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("islandora7: %d status encountered when requesting %s", res.StatusCode, u)
+	}
+	return body, nil
+}
+
+// A descriptive field compared by an Auditor
+type Field struct {
+	// The name of the field, e.g. `title`
+	Name string
+	// Answers the values of the field carried by the source object
+	Source func(s *Source) []string
+	// The keys of the Expected fixture generated from the migrated node that carry the field, e.g. `creator` and
+	// `contributor`
+	Keys []string
+}
+
+// The fields compared by an Auditor if it names none
+var DefaultFields = []Field{
+	{Name: "title", Source: func(s *Source) []string { return s.Titles() }, Keys: []string{"title"}},
+	{Name: "abstract", Source: func(s *Source) []string { return s.Mods.Abstract }, Keys: []string{"abstract"}},
+	{Name: "genre", Source: func(s *Source) []string { return s.Mods.Genre }, Keys: []string{"genre"}},
+	{Name: "subject", Source: func(s *Source) []string { return s.Mods.Topics() }, Keys: []string{"subject"}},
+	{Name: "extent", Source: func(s *Source) []string { return s.Mods.Extents() }, Keys: []string{"extent"}},
+	{Name: "date_created", Source: func(s *Source) []string { return s.Mods.DatesCreated() }, Keys: []string{"date_created"}},
+	{Name: "name", Source: func(s *Source) []string { return s.Mods.Names() }, Keys: []string{"creator", "contributor"}},
+}
+
+// Answers the primary titles of the MODS, or the label of the object if the MODS carries none
+func (s *Source) Titles() []string {
+	if titles := s.Mods.Titles(); len(titles) > 0 {
+		return titles
+	}
+	if s.Object != nil && s.Object.Label != "" {
+		return []string{s.Object.Label}
+	}
+	return nil
+}
+
+// A field whose values differ between the source object and the migrated node.  Values are normalized (see
+// verify.NormalizeText) and sorted.
+type Discrepancy struct {
+	Pid      string
+	Field    string
+	Source   []string
+	Migrated []string
+}
+
+// Answers the discrepancy as e.g. `islandora:1234 genre: source ["Maps"], migrated ["Photographs"]`
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("%s %s: source %q, migrated %q", d.Pid, d.Field, d.Source, d.Migrated)
+}
+
+// Compares the descriptive fields of legacy source objects with the Drupal nodes migrated from them
+type Auditor struct {
+	Legacy *Client
+	// The Drupal site the objects were migrated to
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The bundle of the migrated nodes; model.RepositoryObject if empty
+	Bundle string
+	// The fields compared; DefaultFields if empty
+	Fields []Field
+}
+
+// Creates an Auditor comparing the objects of the legacy repository with the nodes of the Drupal site at the base url
+func NewAuditor(legacy *Client, baseUrl, username, password string) *Auditor {
+	return &Auditor{Legacy: legacy, BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the keys and values of an Expected fixture generated from the node migrated from the object with the PID
+// (see model.GenerateFixture)
+func (a *Auditor) Migrated(pid string) (map[string]interface{}, error) {
+	bundle := a.Bundle
+	if bundle == "" {
+		bundle = model.RepositoryObject
+	}
+	return model.GenerateFixture(&jsonapi.JsonApiUrl{
+		BaseUrl:      a.BaseUrl,
+		DrupalEntity: model.Node,
+		DrupalBundle: bundle,
+		Filter:       jsonapi.PidField,
+		Value:        pid,
+		Username:     a.Username,
+		Password:     a.Password,
+	})
+}
+
+// Answers the fields whose values differ between the object with the PID and the node migrated from it.  Values are
+// compared without regard to order or whitespace.
+func (a *Auditor) Check(pid string) ([]Discrepancy, error) {
+	source, err := a.Legacy.Source(pid)
+	if err != nil {
+		return nil, err
+	}
+	migrated, err := a.Migrated(pid)
+	if err != nil {
+		return nil, fmt.Errorf("islandora7: error retrieving the node migrated from '%s': %w", pid, err)
+	}
+	return Compare(pid, source, migrated, a.Fields...), nil
+}
+
+// Answers the fields whose values differ between the source object and the fixture generated from its migrated node,
+// comparing DefaultFields if no fields are supplied
+func Compare(pid string, source *Source, migrated map[string]interface{}, fields ...Field) []Discrepancy {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	var discrepancies []Discrepancy
+	for _, f := range fields {
+		s := normalized(f.Source(source))
+		var m []string
+		for _, key := range f.Keys {
+			m = append(m, flatten(migrated[key])...)
+		}
+		m = normalized(m)
+		if !equal(s, m) {
+			discrepancies = append(discrepancies, Discrepancy{Pid: pid, Field: f.Name, Source: s, Migrated: m})
+		}
+	}
+	return discrepancies
+}
+
+// Answers the normalized, non-empty values, sorted
+func normalized(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v = verify.NormalizeText(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Answers the string values of a fixture value: a string, or a list of strings, LanguageStrings (by their value) or
+// typed names (by their name)
+func flatten(v interface{}) []string {
+	var generic interface{}
+	if b, err := json.Marshal(v); err != nil || json.Unmarshal(b, &generic) != nil {
+		return nil
+	}
+	items, ok := generic.([]interface{})
+	if !ok {
+		items = []interface{}{generic}
+	}
+	var values []string
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			values = append(values, item)
+		case map[string]interface{}:
+			if s, ok := item["value"].(string); ok {
+				values = append(values, s)
+			} else if s, ok := item["name"].(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,126 @@
+//go:build !notestify
+// +build !notestify
+
+package islandora7
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const objectXml = `<?xml version="1.0" encoding="UTF-8"?>
+<foxml:digitalObject VERSION="1.1" PID="islandora:1234" xmlns:foxml="info:fedora/fedora-system:def/foxml#">
+  <foxml:objectProperties>
+    <foxml:property NAME="info:fedora/fedora-system:def/model#state" VALUE="Active"/>
+    <foxml:property NAME="info:fedora/fedora-system:def/model#label" VALUE="Moonrise"/>
+    <foxml:property NAME="info:fedora/fedora-system:def/model#ownerId" VALUE="admin"/>
+    <foxml:property NAME="info:fedora/fedora-system:def/model#createdDate" VALUE="2015-03-02T15:11:04.123Z"/>
+    <foxml:property NAME="info:fedora/fedora-system:def/view#lastModifiedDate" VALUE="2019-07-12T09:00:00.000Z"/>
+  </foxml:objectProperties>
+  <foxml:datastream ID="MODS" STATE="A" CONTROL_GROUP="M"/>
+  <foxml:datastream ID="OBJ" STATE="A" CONTROL_GROUP="M"/>
+</foxml:digitalObject>`
+
+const modsXml = `<?xml version="1.0" encoding="UTF-8"?>
+<mods:mods xmlns:mods="http://www.loc.gov/mods/v3">
+  <mods:titleInfo><mods:title>Moonrise</mods:title><mods:subTitle>Hernandez, New Mexico</mods:subTitle></mods:titleInfo>
+  <mods:titleInfo type="alternative"><mods:title>Moonrise over Hernandez</mods:title></mods:titleInfo>
+  <mods:abstract>A moon
+    rising</mods:abstract>
+  <mods:genre>Photographs</mods:genre>
+  <mods:subject><mods:topic>Moon</mods:topic><mods:geographic>New Mexico</mods:geographic></mods:subject>
+  <mods:subject><mods:topic>Landscapes</mods:topic></mods:subject>
+  <mods:name type="personal">
+    <mods:namePart type="family">Adams</mods:namePart><mods:namePart type="given">Ansel</mods:namePart>
+    <mods:role><mods:roleTerm>Creator</mods:roleTerm></mods:role>
+  </mods:name>
+  <mods:originInfo><mods:dateCreated>1941</mods:dateCreated></mods:originInfo>
+  <mods:physicalDescription><mods:extent>1 photograph</mods:extent></mods:physicalDescription>
+  <mods:identifier type="local">AA-1941-01</mods:identifier>
+</mods:mods>`
+
+func newLegacyServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, _ := r.BasicAuth(); u != "fedoraAdmin" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/fedora/objects/islandora:1234/objectXML":
+			_, _ = w.Write([]byte(objectXml))
+		case "/fedora/objects/islandora:1234/datastreams/MODS/content":
+			_, _ = w.Write([]byte(modsXml))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func Test_Source(t *testing.T) {
+	server := newLegacyServer()
+	defer server.Close()
+	c := NewClient(server.URL+"/fedora/", "fedoraAdmin", "secret")
+
+	s, err := c.Source("islandora:1234")
+	require.Nil(t, err)
+	assert.Equal(t, &Object{Pid: "islandora:1234", State: "Active", Label: "Moonrise", OwnerId: "admin",
+		Created: "2015-03-02T15:11:04.123Z", Modified: "2019-07-12T09:00:00.000Z", Datastreams: []string{"MODS", "OBJ"}}, s.Object)
+	assert.Equal(t, []string{"Moonrise: Hernandez, New Mexico"}, s.Titles())
+	assert.Equal(t, []string{"Moon", "Landscapes"}, s.Mods.Topics())
+	assert.Equal(t, []string{"Adams, Ansel"}, s.Mods.Names())
+	assert.Equal(t, []string{"1941"}, s.Mods.DatesCreated())
+	assert.Equal(t, []string{"1 photograph"}, s.Mods.Extents())
+	assert.Equal(t, []string{"AA-1941-01"}, s.Mods.Identifiers("local"))
+
+	_, err = c.Source("islandora:9999")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = NewClient(server.URL+"/fedora", "", "").Object("islandora:1234")
+	assert.NotNil(t, err)
+}
+
+func Test_AssertParity(t *testing.T) {
+	legacy := newLegacyServer()
+	defer legacy.Close()
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(
+		jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "g1", "attributes": map[string]interface{}{"name": "Photograph"}},
+		jsonapitest.Resource{"type": "taxonomy_term--subject", "id": "s1", "attributes": map[string]interface{}{"name": "Moon"}},
+		jsonapitest.Resource{"type": "taxonomy_term--subject", "id": "s2", "attributes": map[string]interface{}{"name": "Landscapes"}},
+		jsonapitest.Resource{"type": "taxonomy_term--person", "id": "p1", "attributes": map[string]interface{}{"name": "Adams, Ansel"}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1",
+			"attributes": map[string]interface{}{"title": "Moonrise: Hernandez, New Mexico", "field_pid": "islandora:1234",
+				"field_date_created": []interface{}{"1941"}, "field_extent": []interface{}{"1 photograph"}},
+			"relationships": map[string]interface{}{
+				"field_genre": map[string]interface{}{"data": []interface{}{
+					map[string]interface{}{"type": "taxonomy_term--genre", "id": "g1"}}},
+				"field_subject": map[string]interface{}{"data": []interface{}{
+					map[string]interface{}{"type": "taxonomy_term--subject", "id": "s2"},
+					map[string]interface{}{"type": "taxonomy_term--subject", "id": "s1"}}},
+				"field_creator": map[string]interface{}{"data": []interface{}{
+					map[string]interface{}{"type": "taxonomy_term--person", "id": "p1", "meta": map[string]interface{}{"rel_type": "relators:cre"}}}},
+			}},
+	)
+	a := NewAuditor(NewClient(legacy.URL+"/fedora", "fedoraAdmin", "secret"), m.URL, "", "")
+
+	discrepancies, err := a.Check("islandora:1234")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(discrepancies))
+	assert.Equal(t, `islandora:1234 abstract: source ["A moon rising"], migrated []`, discrepancies[0].String())
+	assert.Equal(t, `islandora:1234 genre: source ["Photographs"], migrated ["Photograph"]`, discrepancies[1].String())
+
+	rec := &asserttest.Recorder{}
+	assert.False(t, a.AssertParity(rec, "islandora:1234"))
+	assert.Contains(t, rec.String(), `islandora:1234 abstract: source ["A moon rising"], migrated []`)
+	a.Fields = []Field{DefaultFields[0], DefaultFields[3], DefaultFields[6]}
+	assert.True(t, a.AssertParity(t, "islandora:1234"))
+
+	_, err = a.Check("islandora:9999")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,157 @@
+package islandora7
+
+import "strings"
+
+// The descriptive fields of a MODS document compared with migrated nodes.  Elements are matched regardless of their
+// namespace prefix.
+type Mods struct {
+	TitleInfo           []TitleInfo           `xml:"titleInfo"`
+	Abstract            []string              `xml:"abstract"`
+	Genre               []string              `xml:"genre"`
+	Subject             []Subject             `xml:"subject"`
+	Name                []Name                `xml:"name"`
+	OriginInfo          []OriginInfo          `xml:"originInfo"`
+	PhysicalDescription []PhysicalDescription `xml:"physicalDescription"`
+	Identifier          []Identifier          `xml:"identifier"`
+}
+
+// A title of the resource; the primary title has no Type
+type TitleInfo struct {
+	// E.g. `alternative`, `translated` or `uniform`
+	Type     string `xml:"type,attr"`
+	NonSort  string `xml:"nonSort"`
+	Title    string `xml:"title"`
+	SubTitle string `xml:"subTitle"`
+}
+
+// Answers the title as e.g. `The Moonrise: a photograph`
+func (t TitleInfo) String() string {
+	title := strings.TrimSpace(t.Title)
+	if nonSort := strings.TrimSpace(t.NonSort); nonSort != "" {
+		title = nonSort + " " + title
+	}
+	if subTitle := strings.TrimSpace(t.SubTitle); subTitle != "" {
+		title += ": " + subTitle
+	}
+	return title
+}
+
+type Subject struct {
+	Topic      []string `xml:"topic"`
+	Geographic []string `xml:"geographic"`
+	Temporal   []string `xml:"temporal"`
+}
+
+type NamePart struct {
+	// E.g. `family`, `given` or `date`; empty for the full name
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Name struct {
+	// E.g. `personal` or `corporate`
+	Type     string     `xml:"type,attr"`
+	NamePart []NamePart `xml:"namePart"`
+	// E.g. `Creator` or `cre`
+	Role []string `xml:"role>roleTerm"`
+}
+
+// Answers the name as e.g. `Adams, Ansel` from its family and given parts, or as its untyped parts otherwise
+func (n Name) String() string {
+	var family, given, full []string
+	for _, p := range n.NamePart {
+		v := strings.TrimSpace(p.Value)
+		switch p.Type {
+		case "family":
+			family = append(family, v)
+		case "given":
+			given = append(given, v)
+		case "":
+			full = append(full, v)
+		}
+	}
+	if len(family) > 0 {
+		name := strings.Join(family, " ")
+		if len(given) > 0 {
+			name += ", " + strings.Join(given, " ")
+		}
+		return name
+	}
+	return strings.Join(full, " ")
+}
+
+type OriginInfo struct {
+	DateCreated []string `xml:"dateCreated"`
+	DateIssued  []string `xml:"dateIssued"`
+	Publisher   []string `xml:"publisher"`
+}
+
+type PhysicalDescription struct {
+	Extent []string `xml:"extent"`
+}
+
+type Identifier struct {
+	// E.g. `local` or `isbn`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Answers the primary titles, disregarding alternative, translated and uniform titles
+func (m *Mods) Titles() []string {
+	var titles []string
+	for _, t := range m.TitleInfo {
+		if t.Type == "" && strings.TrimSpace(t.Title) != "" {
+			titles = append(titles, t.String())
+		}
+	}
+	return titles
+}
+
+// Answers the topical subjects
+func (m *Mods) Topics() []string {
+	var topics []string
+	for _, s := range m.Subject {
+		topics = append(topics, s.Topic...)
+	}
+	return topics
+}
+
+// Answers the names of the agents associated with the resource, regardless of their role
+func (m *Mods) Names() []string {
+	var names []string
+	for _, n := range m.Name {
+		if s := n.String(); s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
+
+// Answers the dates of creation
+func (m *Mods) DatesCreated() []string {
+	var dates []string
+	for _, o := range m.OriginInfo {
+		dates = append(dates, o.DateCreated...)
+	}
+	return dates
+}
+
+// Answers the extents
+func (m *Mods) Extents() []string {
+	var extents []string
+	for _, p := range m.PhysicalDescription {
+		extents = append(extents, p.Extent...)
+	}
+	return extents
+}
+
+// Answers the values of the identifiers of the type, e.g. `local`
+func (m *Mods) Identifiers(identifierType string) []string {
+	var ids []string
+	for _, id := range m.Identifier {
+		if id.Type == identifierType {
+			ids = append(ids, strings.TrimSpace(id.Value))
+		}
+	}
+	return ids
+}
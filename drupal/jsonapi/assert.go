@@ -0,0 +1,119 @@
+//go:build !notestify
+// +build !notestify
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the JSON API content from the URL and unmarshal the response into the supplied interface (which must be a
+// pointer).  This method asserts that there is a single object in the `data` element of the JSON response.
+func (jar *JsonApiUrl) GetSingle(v interface{}) {
+	var res *http.Response
+	var body []byte
+
+	// retrieve json of the migrated entity from the jsonapi and unmarshal the single response
+	if len(strings.TrimSpace(jar.Username)) == 0 {
+		res, body = GetResource(jar.T.(*testing.T), jar.String())
+	} else {
+		res, body = GetResourceWithBasicAuth(jar.T.(*testing.T), jar.String(), jar.Username, jar.Password.Reveal())
+	}
+	defer func() { _ = res.Close }()
+	jar.to(UnmarshalSingleResponse(jar.T.(*testing.T), body, res, &JsonApiResponse{}), v)
+}
+
+// Get the JSON API content from the URL and unmarshal the response into the supplied interface (which must be a
+// pointer).
+func (jar *JsonApiUrl) Get(v interface{}) {
+	var res *http.Response
+	var body []byte
+
+	// retrieve json of the migrated entity from the jsonapi and unmarshal the single response
+	if len(strings.TrimSpace(jar.Username)) == 0 {
+		res, body = GetResource(jar.T.(*testing.T), jar.String())
+	} else {
+		res, body = GetResourceWithBasicAuth(jar.T.(*testing.T), jar.String(), jar.Username, jar.Password.Reveal())
+	}
+	defer func() { _ = res.Close }()
+	jar.to(UnmarshalResponse(jar.T.(*testing.T), body, res, &JsonApiResponse{}, nil), v)
+}
+
+// Adapts the response to the value, asserting that it decodes strictly if the URL is Strict
+func (jar *JsonApiUrl) to(res *JsonApiResponse, v interface{}) {
+	if !jar.Strict {
+		res.To(v)
+		return
+	}
+	err := res.DecodeStrict(v)
+	assert.Nil(jar.T, err, "error decoding the JSONAPI response from %s: %s", jar, err)
+}
+
+// Unmarshal a JSONAPI response body and assert that exactly one data element is present
+func UnmarshalSingleResponse(t *testing.T, body []byte, res *http.Response, value *JsonApiResponse) *JsonApiResponse {
+	UnmarshalResponse(t, body, res, value, func(value *JsonApiResponse) {
+		assert.Equal(t, 1, len(value.Data), "Exactly one JSONAPI data element is expected in the response, but found %d element(s)", len(value.Data))
+	})
+	return value
+}
+
+// Unmarshal a JSONAPI response body and perform supplied assertions on the response
+func UnmarshalResponse(t *testing.T, body []byte, res *http.Response, value *JsonApiResponse, responseAssertions func(res *JsonApiResponse)) *JsonApiResponse {
+	if err := CheckJson(res, body); err != nil {
+		assert.Nil(t, err, "Error unmarshaling JSONAPI response body: %s", err)
+		return value
+	}
+	err := json.Unmarshal(body, value)
+	assert.Nil(t, err, "Error unmarshaling JSONAPI response body: %s", err)
+	if responseAssertions != nil {
+		responseAssertions(value)
+	}
+	return value
+}
+
+// GetResource returns the HTTP response and body from the supplied url.  It asserts that the HTTP status code is 200,
+// and that no errors are encountered reading the response body.  The requeest will be unauthenticated
+func GetResource(t *testing.T, u string) (*http.Response, []byte) {
+	return GetResourceWithBasicAuth(t, u, "", "")
+}
+
+// GetResourceWithAuthn returns the HTTP response and body from the supplied url.  It asserts that the HTTP status code
+// is 200, and that no errors are encountered reading the response body.  The supplied username and password are used to
+// send a Basic Authorization header.  If the supplied username is empty, then the request will be sent without an
+// Authorization header.
+func GetResourceWithBasicAuth(t *testing.T, url, username, password string) (*http.Response, []byte) {
+	req, err := newRequest(url, username, password)
+	assert.Nil(t, err, "encountered error creating request for %s: %s", url, err)
+	res, err := httpClient.Do(req)
+	assert.Nil(t, err, "encountered error requesting %s: %s", url, err)
+	assert.Equal(t, 200, res.StatusCode, "%d status encountered when requesting %s", res.StatusCode, url)
+	body, err := readBody(res)
+	assert.Nil(t, err, "error encountered reading response body from %s: %s", url, err)
+	return res, body
+}
+
+// Retrieves the audio media of the node with the supplied title, and unmarshals the response into the supplied
+// interface (e.g. a pointer to a model.JsonApiAudioMedia).  Any number of audio media may be present in the response.
+func GetAudioMediaOf(t *testing.T, baseUrl, title string, v interface{}) {
+	MediaOfUrl(t, baseUrl, audioBundle, title).Get(v)
+}
+
+// Retrieves the media of every bundle in MediaBundles which are media of the node with the supplied title, UUID, or
+// legacy PID (see NodeIdentifierFilter), grouped by media use.  It asserts that no errors are encountered.
+func GetMediaFor(t *testing.T, baseUrl, titleOrUuid string) MediaByUse {
+	media, err := FetchMediaFor(baseUrl, "", "", titleOrUuid)
+	assert.Nil(t, err, "error retrieving the media of %s: %s", titleOrUuid, err)
+	return media
+}
+
+// Asserts that exactly one term with the supplied name exists in the vocabulary, and answers its UUID
+func (r *TermResolver) MustResolve(t *testing.T, vocabulary, name string) string {
+	id, err := r.Resolve(vocabulary, name)
+	assert.Nil(t, err, "unable to resolve term '%s' in vocabulary '%s': %s", name, vocabulary, err)
+	return id
+}
@@ -0,0 +1,297 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// The path of the token endpoint of Drupal's simple_oauth module
+const DefaultTokenPath = "/oauth/token"
+
+// Authenticates requests, e.g. by setting their Authorization header.  An AuthProvider is installed with
+// ClientConfig.Auth, and authenticates every request of the HTTP client of this package that lacks an Authorization
+// header.
+type AuthProvider interface {
+	Authenticate(req *http.Request) error
+}
+
+// An AuthProvider answering credentials that may be discarded, e.g. an expired token, when a request is rejected
+type Invalidator interface {
+	// Discards the credentials, so that the next request obtains new ones
+	Invalidate()
+}
+
+// Authenticates requests using HTTP Basic Auth
+type BasicAuth struct {
+	Username string
+	Password env.Secret
+}
+
+func (b BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(b.Username, b.Password.Reveal())
+	return nil
+}
+
+// Answers a BasicAuth of the Drupal user named by 'DRUPAL_USERNAME' and 'DRUPAL_PASSWORD', either of which may instead
+// be read from the secret file named by 'DRUPAL_USERNAME_FILE' or 'DRUPAL_PASSWORD_FILE' (see env.GetSecret).  An error
+// is answered if a secret cannot be read, or if no username is set.
+func BasicAuthFromEnv() (BasicAuth, error) {
+	username, err := env.GetSecret("DRUPAL_USERNAME")
+	if err != nil {
+		return BasicAuth{}, err
+	}
+	if username == "" {
+		return BasicAuth{}, fmt.Errorf("unable to authenticate: DRUPAL_USERNAME is unset")
+	}
+	password, err := env.GetSecret("DRUPAL_PASSWORD")
+	if err != nil {
+		return BasicAuth{}, err
+	}
+	return BasicAuth{Username: username, Password: env.Secret(password)}, nil
+}
+
+// Authenticates requests using a fixed bearer token, e.g. a JWT
+type BearerToken string
+
+func (b BearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(b))
+	return nil
+}
+
+// Authenticates requests using OAuth2 access tokens obtained from the token endpoint of Drupal's simple_oauth module,
+// using either the password grant (if Username is not empty) or the client credentials grant.  A token is obtained
+// on the first request, refreshed using its refresh token shortly before it expires, and obtained anew if it cannot be
+// refreshed.  An OAuth is safe for concurrent use.
+type OAuth struct {
+	// The url of the token endpoint, e.g. https://islandora-idc.traefik.me/oauth/token
+	TokenUrl     string
+	ClientId     string
+	ClientSecret env.Secret
+	// The credentials of the password grant; empty for the client credentials grant
+	Username string
+	Password env.Secret
+	// The space-separated scopes requested, e.g. the roles of a simple_oauth consumer; optional
+	Scope string
+	// The client used to request tokens; a default client, if nil.  ClientConfig gives an OAuth lacking a client one
+	// using its transport.
+	Client *http.Client
+	// How long before its expiry a token is refreshed; 30 seconds if zero
+	RefreshMargin time.Duration
+
+	mu      sync.Mutex
+	token   *oauthToken
+	expires time.Time
+}
+
+type oauthToken struct {
+	TokenType    string `json:"token_type"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Creates an OAuth using the password grant of the simple_oauth consumer at the Drupal site at the base url
+func NewPasswordGrant(baseUrl, clientId, clientSecret, username, password string) *OAuth {
+	return &OAuth{TokenUrl: strings.TrimSuffix(baseUrl, "/") + DefaultTokenPath, ClientId: clientId,
+		ClientSecret: env.Secret(clientSecret), Username: username, Password: env.Secret(password)}
+}
+
+// Creates an OAuth using the client credentials grant of the simple_oauth consumer at the Drupal site at the base url
+func NewClientCredentialsGrant(baseUrl, clientId, clientSecret string) *OAuth {
+	return &OAuth{TokenUrl: strings.TrimSuffix(baseUrl, "/") + DefaultTokenPath, ClientId: clientId,
+		ClientSecret: env.Secret(clientSecret)}
+}
+
+// Sets a bearer access token on the request, obtaining or refreshing the token if necessary
+func (o *OAuth) Authenticate(req *http.Request) error {
+	token, err := o.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Answers a current access token, obtaining or refreshing the token if necessary
+func (o *OAuth) Token() (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	margin := o.RefreshMargin
+	if margin == 0 {
+		margin = 30 * time.Second
+	}
+	if o.token != nil && time.Now().Add(margin).Before(o.expires) {
+		return o.token.AccessToken, nil
+	}
+
+	if o.token != nil && o.token.RefreshToken != "" {
+		form := o.form("refresh_token")
+		form.Set("refresh_token", o.token.RefreshToken)
+		if err := o.request(form); err == nil {
+			return o.token.AccessToken, nil
+		}
+	}
+
+	var form url.Values
+	if o.Username != "" {
+		form = o.form("password")
+		form.Set("username", o.Username)
+		form.Set("password", o.Password.Reveal())
+	} else {
+		form = o.form("client_credentials")
+	}
+	if err := o.request(form); err != nil {
+		o.token = nil
+		return "", err
+	}
+	return o.token.AccessToken, nil
+}
+
+// Discards the current token, e.g. after it was revoked
+func (o *OAuth) Invalidate() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.token = nil
+}
+
+func (o *OAuth) form(grant string) url.Values {
+	form := url.Values{"grant_type": {grant}, "client_id": {o.ClientId}}
+	if o.ClientSecret != "" {
+		form.Set("client_secret", o.ClientSecret.Reveal())
+	}
+	if o.Scope != "" {
+		form.Set("scope", o.Scope)
+	}
+	return form
+}
+
+// Requests a token from the token endpoint, retaining it if granted
+func (o *OAuth) request(form url.Values) error {
+	client := o.Client
+	if client == nil {
+		client = withRun(&http.Client{})
+	}
+	res, err := client.PostForm(o.TokenUrl, form)
+	if err != nil {
+		return fmt.Errorf("encountered error requesting an OAuth token from %s: %w", o.TokenUrl, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error encountered reading response body from %s: %w", o.TokenUrl, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%d status encountered requesting an OAuth %s token from %s: %s", res.StatusCode, form.Get("grant_type"), o.TokenUrl, body)
+	}
+	token := &oauthToken{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return fmt.Errorf("unable to unmarshal OAuth token from %s: %w", o.TokenUrl, err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("no access token answered by %s", o.TokenUrl)
+	}
+	if token.RefreshToken == "" && o.token != nil {
+		token.RefreshToken = o.token.RefreshToken
+	}
+	o.token = token
+	o.expires = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return nil
+}
+
+// A transport that authenticates each request to the Drupal site lacking an Authorization header using its Provider.
+// Requests to other hosts, e.g. oEmbed providers, Solr or Fedora, are sent as they are, so that Drupal credentials are
+// not disclosed to them.  If a request is answered 401 and the Provider is an Invalidator, its credentials are
+// invalidated and the request is retried once.
+type AuthTransport struct {
+	Provider AuthProvider
+	// The transport used to send requests; http.DefaultTransport if nil
+	Transport http.RoundTripper
+	// The base url of the Drupal site whose requests are authenticated, e.g. https://islandora-idc.traefik.me.  If
+	// empty, the base url of the Config supplied to UseConfig, or of 'DRUPAL_BASE_URL', is used; if neither is set, no
+	// request is authenticated.
+	BaseUrl string
+}
+
+// Sends a copy of the request authenticated by the Provider, unless the request carries credentials already, is not a
+// request to the Drupal site, or has a context answered by WithoutAuth
+func (at *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := at.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if req.Header.Get("Authorization") != "" || !at.authenticates(req.URL) || req.Context().Value(noAuthKey{}) != nil {
+		return transport.RoundTrip(req)
+	}
+
+	send := func() (*http.Response, error) {
+		r := req.Clone(req.Context())
+		if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		if err := at.Provider.Authenticate(r); err != nil {
+			return nil, err
+		}
+		return transport.RoundTrip(r)
+	}
+
+	res, err := send()
+	invalidator, ok := at.Provider.(Invalidator)
+	retryable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if err != nil || res.StatusCode != http.StatusUnauthorized || !ok || !retryable {
+		return res, err
+	}
+	res.Body.Close()
+	invalidator.Invalidate()
+	return send()
+}
+
+type noAuthKey struct{}
+
+// Answers a context whose requests are not authenticated by an AuthTransport, e.g. for requests made as the anonymous
+// user when ClientConfig.Auth is configured:
+//
+//	req = req.WithContext(jsonapi.WithoutAuth(req.Context()))
+func WithoutAuth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noAuthKey{}, true)
+}
+
+// Answers whether the url is of the host of the Drupal site, and so is authenticated
+func (at *AuthTransport) authenticates(u *url.URL) bool {
+	baseUrl := at.BaseUrl
+	if baseUrl == "" {
+		baseUrl = baseUrlOr("")
+	}
+	base, err := url.Parse(baseUrl)
+	if err != nil || base.Host == "" {
+		return false
+	}
+	return sameHost(base, u)
+}
+
+// Answers whether the urls are of the same host and port, the port of each defaulting to that of its scheme
+func sameHost(a, b *url.URL) bool {
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		if strings.EqualFold(u.Scheme, "http") {
+			return "80"
+		}
+		return "443"
+	}
+	return strings.EqualFold(a.Hostname(), b.Hostname()) && port(a) == port(b)
+}
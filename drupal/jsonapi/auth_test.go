@@ -0,0 +1,199 @@
+package jsonapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A simple_oauth token endpoint and a JSON:API resource requiring its tokens
+type oauthServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	grants    []string
+	issued    int
+	valid     map[string]bool
+	expiresIn int
+}
+
+func newOAuthServer() *oauthServer {
+	s := &oauthServer{valid: map[string]bool{}, expiresIn: 300}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.URL.Path == DefaultTokenPath {
+			_ = r.ParseForm()
+			grant := r.PostForm.Get("grant_type")
+			s.grants = append(s.grants, grant)
+			if r.PostForm.Get("client_id") != "idc" || r.PostForm.Get("client_secret") != "secret" ||
+				grant == "password" && r.PostForm.Get("password") != "moonrise" ||
+				grant == "refresh_token" && r.PostForm.Get("refresh_token") != fmt.Sprintf("refresh-%d", s.issued) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": "invalid_grant"}`))
+				return
+			}
+			s.issued++
+			token := fmt.Sprintf("access-%d", s.issued)
+			s.valid[token] = true
+			fmt.Fprintf(w, `{"token_type": "Bearer", "expires_in": %d, "access_token": "%s", "refresh_token": "refresh-%d"}`, s.expiresIn, token, s.issued)
+			return
+		}
+		var token string
+		fmt.Sscanf(r.Header.Get("Authorization"), "Bearer %s", &token)
+		if !s.valid[token] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	return s
+}
+
+func Test_OAuthPasswordGrant(t *testing.T) {
+	server := newOAuthServer()
+	defer server.Close()
+	defer SetHTTPClient(nil)
+
+	o := NewPasswordGrant(server.URL+"/", "idc", "secret", "admin", "moonrise")
+	require.Nil(t, Configure(ClientConfig{Auth: o, AuthBaseUrl: server.URL}))
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, []string{"password"}, server.grants)
+
+	// a token about to expire is refreshed
+	server.expiresIn = 10
+	o.Invalidate()
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, []string{"password", "password", "refresh_token"}, server.grants)
+
+	server.expiresIn = 300
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, []string{"password", "password", "refresh_token", "refresh_token"}, server.grants)
+
+	// a revoked token is invalidated, and the request retried
+	server.valid = map[string]bool{}
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, []string{"password", "password", "refresh_token", "refresh_token", "password"}, server.grants)
+
+	// explicit credentials are not replaced
+	u.Username, u.Password = "admin", "moonrise"
+	assert.NotNil(t, u.Fetch(&JsonApiResponse{}))
+}
+
+func Test_OAuthClientCredentialsGrant(t *testing.T) {
+	server := newOAuthServer()
+	defer server.Close()
+
+	o := NewClientCredentialsGrant(server.URL, "idc", "secret")
+	token, err := o.Token()
+	require.Nil(t, err)
+	assert.Equal(t, "access-1", token)
+	assert.Equal(t, []string{"client_credentials"}, server.grants)
+
+	o.ClientSecret = "wrong"
+	o.Invalidate()
+	_, err = o.Token()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "401 status")
+}
+
+func Test_AuthTransportHost(t *testing.T) {
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+	defer func(v string, ok bool) {
+		if ok {
+			os.Setenv("DRUPAL_BASE_URL", v)
+		} else {
+			os.Unsetenv("DRUPAL_BASE_URL")
+		}
+	}(os.LookupEnv("DRUPAL_BASE_URL"))
+
+	get := func(client *http.Client, u string) {
+		res, err := client.Get(u)
+		require.Nil(t, err)
+		res.Body.Close()
+	}
+
+	// only requests to the Drupal site are authenticated
+	client := &http.Client{Transport: &AuthTransport{Provider: BearerToken("eyJ0eXAi"), BaseUrl: server.URL + "/"}}
+	get(client, server.URL+"/jsonapi")
+	get(client, other.URL+"/oembed")
+	assert.Equal(t, []string{"Bearer eyJ0eXAi", ""}, authorizations)
+
+	// the site defaults to that of DRUPAL_BASE_URL
+	authorizations = nil
+	client = &http.Client{Transport: &AuthTransport{Provider: BearerToken("eyJ0eXAi")}}
+	os.Setenv("DRUPAL_BASE_URL", other.URL)
+	get(client, server.URL+"/jsonapi")
+	get(client, other.URL+"/jsonapi")
+	os.Unsetenv("DRUPAL_BASE_URL")
+	get(client, other.URL+"/jsonapi")
+	assert.Equal(t, []string{"", "Bearer eyJ0eXAi", ""}, authorizations)
+
+	u := func(s string) *url.URL {
+		parsed, _ := url.Parse(s)
+		return parsed
+	}
+	assert.True(t, sameHost(u("https://Islandora-IDC.traefik.me"), u("https://islandora-idc.traefik.me:443/jsonapi")))
+	assert.False(t, sameHost(u("https://islandora-idc.traefik.me"), u("http://islandora-idc.traefik.me/jsonapi")))
+	assert.False(t, sameHost(u("https://islandora-idc.traefik.me"), u("https://islandora-idc.traefik.me.evil.org/")))
+}
+
+func Test_AuthProviders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.org/", nil)
+	require.Nil(t, BasicAuth{Username: "admin", Password: "moonrise"}.Authenticate(req))
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "moonrise", password)
+
+	require.Nil(t, BearerToken("eyJ0eXAi").Authenticate(req))
+	assert.Equal(t, "Bearer eyJ0eXAi", req.Header.Get("Authorization"))
+}
+
+func Test_BasicAuthFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	secret := filepath.Join(dir, "drupal_password")
+	require.Nil(t, ioutil.WriteFile(secret, []byte("moonrise\n"), 0600))
+	for _, k := range []string{"DRUPAL_USERNAME", "DRUPAL_PASSWORD", "DRUPAL_PASSWORD_FILE", "IDC_PROFILE"} {
+		if v, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, v)
+		} else {
+			defer os.Unsetenv(k)
+		}
+		os.Unsetenv(k)
+	}
+
+	_, err = BasicAuthFromEnv()
+	assert.NotNil(t, err)
+
+	os.Setenv("DRUPAL_USERNAME", "admin")
+	os.Setenv("DRUPAL_PASSWORD_FILE", secret)
+	auth, err := BasicAuthFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, BasicAuth{Username: "admin", Password: "moonrise"}, auth)
+
+	os.Setenv("DRUPAL_PASSWORD_FILE", filepath.Join(dir, "missing"))
+	_, err = BasicAuthFromEnv()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,62 @@
+package jsonapi
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum size")
+
+// The maximum size of a response body read into memory, unless overridden by DRUPAL_MAX_RESPONSE_BYTES: 64 MiB
+const DefaultMaxResponseSize = 64 << 20
+
+// Answers the maximum size, in bytes, of a response body read into memory by GetResource and FetchResource: the
+// MaxResponseBytes of the Config supplied to UseConfig, or the value of DRUPAL_MAX_RESPONSE_BYTES, or
+// DefaultMaxResponseSize if neither is set.  A value that is not positive means no limit.  The limit applies to the
+// decompressed body, so a compressed response cannot exceed it either.
+func MaxResponseSize() int64 {
+	if c := currentConfig(); c != nil {
+		if c.MaxResponseBytes != 0 {
+			return int64(c.MaxResponseBytes)
+		}
+		return DefaultMaxResponseSize
+	}
+	return int64(env.MaxResponseBytesOr(DefaultMaxResponseSize))
+}
+
+// Answers the body of the response, decompressing it if it is gzip-encoded.  Requests created by newRequest accept
+// gzip explicitly, so net/http leaves their responses compressed.
+func decodedBody(res *http.Response) (io.ReadCloser, error) {
+	if res.Uncompressed || res.Header.Get("Content-Encoding") != "gzip" {
+		return res.Body, nil
+	}
+	return gzip.NewReader(res.Body)
+}
+
+// Reads the decompressed body of the response, answering an error wrapping ErrResponseTooLarge if it exceeds
+// MaxResponseSize
+func readBody(res *http.Response) ([]byte, error) {
+	body, err := decodedBody(res)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	limit := MaxResponseSize()
+	if limit <= 0 {
+		return ioutil.ReadAll(body)
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("%w of %d bytes (see DRUPAL_MAX_RESPONSE_BYTES)", ErrResponseTooLarge, limit)
+	}
+	return b, nil
+}
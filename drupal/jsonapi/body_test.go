@@ -0,0 +1,69 @@
+//go:build !notestify
+// +build !notestify
+
+package jsonapi
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGzipServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(body))
+		_ = gz.Close()
+	}))
+}
+
+func Test_FetchResourceGzip(t *testing.T) {
+	doc := `{"data": [{"type": "node--islandora_object", "id": "n1"}]}`
+	server := newGzipServer(doc)
+	defer server.Close()
+
+	_, body, err := FetchResource(server.URL, "", "")
+	require.Nil(t, err)
+	assert.Equal(t, doc, string(body))
+
+	res, body := GetResource(t, server.URL)
+	assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+	assert.Equal(t, doc, string(body))
+
+	var ids []string
+	_, err = StreamResource(server.URL, "", "", func(data JsonApiData) error {
+		ids = append(ids, data.Id())
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"n1"}, ids)
+}
+
+func Test_MaxResponseSize(t *testing.T) {
+	assert.Equal(t, int64(DefaultMaxResponseSize), MaxResponseSize())
+
+	// the limit applies to the decompressed body
+	server := newGzipServer(`{"data": [` + strings.Repeat(" ", 4096) + `]}`)
+	defer server.Close()
+	defer os.Unsetenv("DRUPAL_MAX_RESPONSE_BYTES")
+	os.Setenv("DRUPAL_MAX_RESPONSE_BYTES", "1024")
+	_, _, err := FetchResource(server.URL, "", "")
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Contains(t, err.Error(), "1024 bytes")
+
+	os.Setenv("DRUPAL_MAX_RESPONSE_BYTES", "0")
+	_, body, err := FetchResource(server.URL, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 4108, len(body))
+}
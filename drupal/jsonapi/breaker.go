@@ -0,0 +1,214 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Answered, wrapped, for requests to a host whose circuit is open
+var ErrCircuitOpen = errors.New("target unhealthy: circuit open")
+
+// The states of the circuit of a host
+const (
+	// Requests are sent
+	CircuitClosed = "closed"
+	// Requests fail immediately with ErrCircuitOpen
+	CircuitOpen = "open"
+	// A single probe request is sent; its outcome closes or re-opens the circuit
+	CircuitHalfOpen = "half-open"
+)
+
+// A transport that fails fast when a host is unhealthy.  After Threshold consecutive failures (transport errors or 5xx
+// responses) to a host, its circuit opens and requests to the host fail immediately with ErrCircuitOpen.  Once the
+// Cooldown elapses, a single probe request is sent: if it succeeds the circuit closes, otherwise it opens again.
+//
+// Each request is also given a deadline of Timeout, lengthened by a random fraction of up to Jitter, so that requests
+// to a struggling host do not time out in lockstep.
+//
+// A CircuitBreaker is safe for concurrent use, and is typically installed with ClientConfig:
+//
+//	jsonapi.Configure(jsonapi.ClientConfig{Breaker: jsonapi.NewCircuitBreaker(5, 30*time.Second)})
+type CircuitBreaker struct {
+	// The number of consecutive failures that opens the circuit of a host
+	Threshold int
+	// The time a circuit remains open before a probe request is sent
+	Cooldown time.Duration
+	// The deadline of each request, including reading the response body; zero means no deadline
+	Timeout time.Duration
+	// The fraction of Timeout by which each deadline is randomly lengthened, e.g. 0.2 for up to 20%
+	Jitter float64
+	// The transport used to send requests; http.DefaultTransport if nil
+	Transport http.RoundTripper
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+// The health of a host, as observed by a CircuitBreaker
+type HostHealth struct {
+	Host  string
+	State string
+	// The number of consecutive failed requests
+	Failures int
+	// The most recent failure, if any
+	LastErr  error
+	OpenedAt time.Time
+}
+
+type circuit struct {
+	state    string
+	failures int
+	lastErr  error
+	openedAt time.Time
+	probing  bool
+}
+
+// Creates a CircuitBreaker that opens the circuit of a host after the threshold of consecutive failures, and probes
+// the host once the cooldown elapses
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Sends the request, unless the circuit of its host is open
+func (cb *CircuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if err := cb.allow(host); err != nil {
+		return nil, err
+	}
+
+	var cancel context.CancelFunc
+	if deadline := cb.deadline(); deadline > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), deadline)
+		req = req.WithContext(ctx)
+	}
+
+	transport := cb.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(req)
+	if err == nil && res.StatusCode >= 500 {
+		cb.record(host, fmt.Errorf("%d status encountered when requesting %s", res.StatusCode, req.URL))
+	} else {
+		cb.record(host, err)
+	}
+
+	if cancel != nil {
+		if err != nil {
+			cancel()
+		} else {
+			// the deadline applies until the body is read
+			res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+		}
+	}
+	return res, err
+}
+
+// Answers the health of each host a request has been sent to, ordered by host
+func (cb *CircuitBreaker) Health() []HostHealth {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	var health []HostHealth
+	for host, c := range cb.hosts {
+		health = append(health, HostHealth{Host: host, State: c.state, Failures: c.failures, LastErr: c.lastErr, OpenedAt: c.openedAt})
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Host < health[j].Host })
+	return health
+}
+
+// Answers a summary of the hosts whose circuit is not closed, e.g. for the end of a test run, or the empty string if
+// every host is healthy
+func (cb *CircuitBreaker) Summary() string {
+	var lines []string
+	for _, h := range cb.Health() {
+		if h.State == CircuitClosed {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("target unhealthy: %s is %s after %d consecutive failures since %s (last: %v)",
+			h.Host, h.State, h.Failures, h.OpenedAt.Format(time.RFC3339), h.LastErr))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Answers an error if a request to the host ought not be sent
+func (cb *CircuitBreaker) allow(host string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.circuit(host)
+	switch c.state {
+	case CircuitOpen:
+		if time.Since(c.openedAt) < cb.Cooldown {
+			return fmt.Errorf("%w: %s after %d consecutive failures (last: %v)", ErrCircuitOpen, host, c.failures, c.lastErr)
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+	case CircuitHalfOpen:
+		if c.probing {
+			return fmt.Errorf("%w: %s is being probed", ErrCircuitOpen, host)
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// Records the outcome of a request to the host
+func (cb *CircuitBreaker) record(host string, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.circuit(host)
+	c.probing = false
+	if err == nil {
+		c.state, c.failures, c.lastErr = CircuitClosed, 0, nil
+		return
+	}
+	c.failures++
+	c.lastErr = err
+	threshold := cb.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+	if c.state == CircuitHalfOpen || c.failures >= threshold {
+		c.state = CircuitOpen
+		c.openedAt = time.Now()
+	}
+}
+
+func (cb *CircuitBreaker) circuit(host string) *circuit {
+	if cb.hosts == nil {
+		cb.hosts = map[string]*circuit{}
+	}
+	c, ok := cb.hosts[host]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		cb.hosts[host] = c
+	}
+	return c
+}
+
+// Answers the deadline of a request: the Timeout, lengthened by a random fraction of up to Jitter
+func (cb *CircuitBreaker) deadline() time.Duration {
+	if cb.Timeout <= 0 || cb.Jitter <= 0 {
+		return cb.Timeout
+	}
+	return cb.Timeout + time.Duration(rand.Float64()*cb.Jitter*float64(cb.Timeout))
+}
+
+// Cancels the context of a request once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
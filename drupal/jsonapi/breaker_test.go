@@ -0,0 +1,80 @@
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	var requests, healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	defer SetHTTPClient(nil)
+
+	breaker := NewCircuitBreaker(2, 50*time.Millisecond)
+	require.Nil(t, Configure(ClientConfig{Breaker: breaker}))
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+
+	// two failures open the circuit, after which requests fail without reaching the server
+	assert.NotNil(t, u.Fetch(&JsonApiResponse{}))
+	assert.NotNil(t, u.Fetch(&JsonApiResponse{}))
+	assert.ErrorIs(t, u.Fetch(&JsonApiResponse{}), ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	health := breaker.Health()
+	require.Equal(t, 1, len(health))
+	assert.Equal(t, CircuitOpen, health[0].State)
+	assert.Equal(t, 2, health[0].Failures)
+	assert.Contains(t, breaker.Summary(), "target unhealthy")
+
+	// a failed probe re-opens the circuit
+	time.Sleep(60 * time.Millisecond)
+	assert.NotNil(t, u.Fetch(&JsonApiResponse{}))
+	assert.ErrorIs(t, u.Fetch(&JsonApiResponse{}), ErrCircuitOpen)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// a successful probe closes it
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, CircuitClosed, breaker.Health()[0].State)
+	assert.Equal(t, "", breaker.Summary())
+}
+
+func Test_CircuitBreakerDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") != "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	breaker := &CircuitBreaker{Threshold: 5, Cooldown: time.Minute, Timeout: 50 * time.Millisecond, Jitter: 0.5}
+	for i := 0; i < 20; i++ {
+		d := breaker.deadline()
+		assert.True(t, d >= 50*time.Millisecond && d <= 75*time.Millisecond, "%s", d)
+	}
+
+	client := &http.Client{Transport: breaker}
+	_, err := client.Get(server.URL + "?slow=1")
+	assert.NotNil(t, err)
+	res, err := client.Get(server.URL)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	require.Nil(t, res.Body.Close())
+	assert.Equal(t, 0, breaker.Health()[0].Failures)
+}
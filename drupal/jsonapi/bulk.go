@@ -0,0 +1,130 @@
+package jsonapi
+
+import (
+	"sync"
+	"time"
+)
+
+// Default number of concurrent requests made by a BulkFetcher
+const defaultBulkWorkers = 4
+
+// The outcome of retrieving a single JsonApiUrl with a BulkFetcher
+type BulkResult struct {
+	Url *JsonApiUrl
+	// The response, or nil if Err is not nil
+	Response *JsonApiResponse
+	Err      error
+}
+
+// Retrieves many JsonApiUrls concurrently using a pool of workers, e.g. to verify the thousands of nodes of a large
+// migration.  Requests are optionally rate limited across all workers, so that Drupal is not overwhelmed.  A
+// BulkFetcher may be reused, but not concurrently.
+type BulkFetcher struct {
+	// The number of concurrent requests; if not positive, 4 workers are used
+	Workers int
+	// The minimum interval between the start of successive requests, across all workers; zero disables rate limiting
+	Interval time.Duration
+	// If true, each JsonApiUrl must match exactly one resource (see FetchSingle), otherwise the result carries an error
+	Single bool
+}
+
+// Creates a BulkFetcher using the supplied number of workers, which starts at most requestsPerSecond requests each
+// second.  If requestsPerSecond is not positive, requests are not rate limited.
+func NewBulkFetcher(workers int, requestsPerSecond float64) *BulkFetcher {
+	b := &BulkFetcher{Workers: workers}
+	if requestsPerSecond > 0 {
+		b.Interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return b
+}
+
+// Retrieves each of the JsonApiUrls, answering a result for every url, keyed by the url.  Errors do not stop the
+// retrieval of the remaining urls; they are answered in the result of the failed url.
+func (b *BulkFetcher) FetchAll(urls []*JsonApiUrl) map[*JsonApiUrl]*BulkResult {
+	results := make(map[*JsonApiUrl]*BulkResult, len(urls))
+	for _, r := range b.fetch(urls) {
+		results[r.Url] = r
+	}
+	return results
+}
+
+// Retrieves one resource per value by filtering on the supplied field, e.g. every node whose `title` is one of the
+// values, answering a result for every value, keyed by the value.  Each url is a copy of the supplied template with
+// its Filter and Value replaced.
+func (b *BulkFetcher) FetchValues(template JsonApiUrl, filter string, values []string) map[string]*BulkResult {
+	urls := make([]*JsonApiUrl, len(values))
+	for i, v := range values {
+		u := template
+		u.Filter, u.Value, u.RawFilter = filter, v, ""
+		urls[i] = &u
+	}
+
+	results := make(map[string]*BulkResult, len(values))
+	for i, r := range b.fetch(urls) {
+		results[values[i]] = r
+	}
+	return results
+}
+
+// Retrieves the urls using the pool of workers, answering the results in the order of the urls
+func (b *BulkFetcher) fetch(urls []*JsonApiUrl) []*BulkResult {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = defaultBulkWorkers
+	}
+	limit := &limiter{interval: b.Interval}
+
+	results := make([]*BulkResult, len(urls))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				limit.wait()
+				res := &JsonApiResponse{}
+				var err error
+				if b.Single {
+					err = urls[i].FetchSingle(res)
+				} else {
+					err = urls[i].Fetch(res)
+				}
+				if err != nil {
+					res = nil
+				}
+				results[i] = &BulkResult{Url: urls[i], Response: res, Err: err}
+			}
+		}()
+	}
+
+	for i := range urls {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// Spaces events at least interval apart
+type limiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// Blocks until the next event is permitted
+func (l *limiter) wait() {
+	if l.interval <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
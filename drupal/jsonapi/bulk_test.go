@@ -0,0 +1,83 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BulkFetcher(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			peak := atomic.LoadInt32(&maxInFlight)
+			if n <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		switch title := r.URL.Query().Get("filter[title]"); title {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "none":
+			w.Write([]byte(`{"data": []}`))
+		default:
+			fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, title)
+		}
+	}))
+	defer server.Close()
+
+	var values []string
+	for i := 0; i < 20; i++ {
+		values = append(values, fmt.Sprintf("object %d", i))
+	}
+	values = append(values, "missing", "none")
+
+	b := &BulkFetcher{Workers: 3, Single: true}
+	template := JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+	results := b.FetchValues(template, "title", values)
+
+	require.Equal(t, len(values), len(results))
+	for _, v := range values[:20] {
+		require.Nil(t, results[v].Err)
+		assert.Equal(t, v, results[v].Response.Data[0]["id"])
+		assert.Equal(t, v, results[v].Url.Value)
+	}
+	assert.NotNil(t, results["missing"].Err)
+	assert.Nil(t, results["missing"].Response)
+	assert.NotNil(t, results["none"].Err)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+
+	// without Single, an empty result is not an error
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "none"}
+	byUrl := (&BulkFetcher{}).FetchAll([]*JsonApiUrl{u})
+	require.Nil(t, byUrl[u].Err)
+	assert.Empty(t, byUrl[u].Response.Data)
+}
+
+func Test_BulkFetcherRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	var urls []*JsonApiUrl
+	for i := 0; i < 5; i++ {
+		urls = append(urls, &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"})
+	}
+
+	// 5 requests at 100 requests per second take at least 40ms, regardless of the number of workers
+	start := time.Now()
+	results := NewBulkFetcher(5, 100).FetchAll(urls)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(40*time.Millisecond))
+	assert.Equal(t, 5, len(results))
+}
@@ -0,0 +1,187 @@
+package jsonapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// Configures the HTTP client used by this package to make every request to Drupal.  The zero value configures a
+// client equivalent to the default: certificates are verified, proxies are taken from the environment (i.e.
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY), and connection pooling uses the defaults of net/http.
+type ClientConfig struct {
+	// Skips verification of the server's certificate, e.g. for the self-signed certificates of development
+	// environments like https://islandora-idc.traefik.me
+	InsecureSkipVerify bool
+	// A PEM file of certificate authorities trusted in addition to the system's, e.g. a development CA
+	RootCAFile string
+	// The URL of the proxy used for every request; if empty, proxies are taken from the environment
+	ProxyUrl string
+	// The maximum number of idle connections kept across all hosts; zero uses the net/http default
+	MaxIdleConns int
+	// The maximum number of idle connections kept for each host; zero uses the net/http default
+	MaxIdleConnsPerHost int
+	// The maximum number of connections, including those in use, for each host; zero means no limit
+	MaxConnsPerHost int
+	// The time limit of each request, including reading the response body; zero means no limit
+	Timeout time.Duration
+	// A custom transport, e.g. one that records requests.  If supplied, the TLS, proxy and connection options above
+	// are ignored.
+	Transport http.RoundTripper
+	// A circuit breaker through which every request is sent, so that runs against an unhealthy Drupal fail fast.  If
+	// the breaker lacks a transport, it is given the transport configured above.
+	Breaker *CircuitBreaker
+	// Authenticates every request to the Drupal site lacking an Authorization header, e.g. an OAuth obtaining tokens
+	// from simple_oauth.  An OAuth lacking a Client requests its tokens using the transport configured above.
+	Auth AuthProvider
+	// The base url of the Drupal site whose requests are authenticated by Auth; requests to other hosts are not
+	// authenticated.  If empty, the base url of the Config supplied to UseConfig, or of 'DRUPAL_BASE_URL', is used
+	// (see AuthTransport).
+	AuthBaseUrl string
+}
+
+// Creates an HTTP client according to the configuration
+func (c ClientConfig) NewClient() (*http.Client, error) {
+	if c.Transport != nil {
+		return &http.Client{Transport: c.withAuth(c.withBreaker(c.Transport)), Timeout: c.Timeout}, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = c.MaxConnsPerHost
+	if c.MaxIdleConns > 0 {
+		transport.MaxIdleConns = c.MaxIdleConns
+	}
+	if c.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+	}
+
+	if c.ProxyUrl != "" {
+		proxy, err := url.Parse(c.ProxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url '%s': %w", c.ProxyUrl, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if c.InsecureSkipVerify || c.RootCAFile != "" {
+		tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+		if c.RootCAFile != "" {
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			pem, err := ioutil.ReadFile(c.RootCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read root CA file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in root CA file '%s'", c.RootCAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{Transport: c.withAuth(c.withBreaker(transport)), Timeout: c.Timeout}, nil
+}
+
+// Answers the configured breaker wrapping the transport, or the transport if no breaker is configured
+func (c ClientConfig) withBreaker(transport http.RoundTripper) http.RoundTripper {
+	if c.Breaker == nil {
+		return transport
+	}
+	if c.Breaker.Transport == nil {
+		c.Breaker.Transport = transport
+	}
+	return c.Breaker
+}
+
+// Answers the transport wrapped by an AuthTransport of the configured provider, or the transport if no provider is
+// configured
+func (c ClientConfig) withAuth(transport http.RoundTripper) http.RoundTripper {
+	if c.Auth == nil {
+		return transport
+	}
+	if o, ok := c.Auth.(*OAuth); ok && o.Client == nil {
+		o.Client = withRun(&http.Client{Transport: transport, Timeout: c.Timeout})
+	}
+	return &AuthTransport{Provider: c.Auth, Transport: transport, BaseUrl: c.AuthBaseUrl}
+}
+
+// Replaces the HTTP client used by this package with one created according to the configuration.  Configure ought
+// to be invoked before any requests are made, e.g. from TestMain.
+func Configure(c ClientConfig) error {
+	client, err := c.NewClient()
+	if err != nil {
+		return err
+	}
+	SetHTTPClient(client)
+	return nil
+}
+
+// Replaces the HTTP client used by this package.  A nil client restores the default client.  The client's transport
+// is wrapped so that each request carries the RunHeader; the supplied client is not modified.  SetHTTPClient ought to
+// be invoked before any requests are made, e.g. from TestMain.
+func SetHTTPClient(c *http.Client) {
+	if c == nil {
+		c = &http.Client{}
+	}
+	httpClient = withRun(c)
+}
+
+// Answers the HTTP client used by this package
+func HTTPClient() *http.Client {
+	return httpClient
+}
+
+var (
+	configMu sync.RWMutex
+	config   *env.Config
+)
+
+// Configures this package from the Config rather than from environment variables: its base url replaces the base url
+// of every request (as DRUPAL_BASE_URL does otherwise), its MaxResponseBytes replaces DRUPAL_MAX_RESPONSE_BYTES, and
+// the HTTP client is replaced by one with its timeout and certificate verification.  A nil Config restores the
+// environment variables and the default client.  UseConfig ought to be invoked before any requests are made, e.g.
+// from TestMain:
+//
+//	c, err := env.LoadConfig()
+//	...
+//	err = jsonapi.UseConfig(c)
+func UseConfig(c *env.Config) error {
+	if c == nil {
+		SetHTTPClient(nil)
+	} else if err := Configure(ClientConfig{InsecureSkipVerify: c.Insecure, Timeout: c.Timeout}); err != nil {
+		return err
+	}
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = c
+	return nil
+}
+
+// Answers the Config supplied to UseConfig, or nil
+func currentConfig() *env.Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return config
+}
+
+// Answers the base url of the Config supplied to UseConfig, or of the environment variable 'DRUPAL_BASE_URL', or the
+// supplied base url if neither is set
+func baseUrlOr(baseUrl string) string {
+	if c := currentConfig(); c != nil {
+		if c.BaseUrl != "" {
+			return c.BaseUrl
+		}
+		return baseUrl
+	}
+	return env.BaseUrlOr(baseUrl)
+}
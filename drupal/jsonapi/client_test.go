@@ -0,0 +1,103 @@
+package jsonapi
+
+import (
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTransport struct {
+	requests []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req.URL.String())
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func Test_ClientConfigTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	defer SetHTTPClient(nil)
+
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+
+	// the server's certificate is self-signed, so it is not trusted by default
+	SetHTTPClient(nil)
+	assert.NotNil(t, u.Fetch(&JsonApiResponse{}))
+
+	require.Nil(t, Configure(ClientConfig{InsecureSkipVerify: true}))
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.Nil(t, ioutil.WriteFile(caFile, cert, 0644))
+	require.Nil(t, Configure(ClientConfig{RootCAFile: caFile, MaxIdleConnsPerHost: 2, MaxConnsPerHost: 4}))
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+}
+
+func Test_ClientConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	defer SetHTTPClient(nil)
+
+	rt := &recordingTransport{}
+	require.Nil(t, Configure(ClientConfig{Transport: rt}))
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, []string{server.URL + "/jsonapi/node/islandora_object"}, rt.requests)
+
+	client, err := ClientConfig{ProxyUrl: "http://proxy.example.org:3128"}.NewClient()
+	require.Nil(t, err)
+	proxy, err := client.Transport.(*http.Transport).Proxy(httptest.NewRequest("GET", "http://example.org/", nil))
+	require.Nil(t, err)
+	assert.Equal(t, "proxy.example.org:3128", proxy.Host)
+
+	_, err = ClientConfig{ProxyUrl: "://"}.NewClient()
+	assert.NotNil(t, err)
+	_, err = ClientConfig{RootCAFile: "does-not-exist.pem"}.NewClient()
+	assert.NotNil(t, err)
+}
+
+func Test_UseConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "n1"}]}`))
+	}))
+	defer server.Close()
+	defer UseConfig(nil)
+
+	require.Nil(t, UseConfig(&env.Config{BaseUrl: server.URL, Timeout: time.Minute, MaxResponseBytes: 16}))
+	assert.Equal(t, time.Minute, HTTPClient().Timeout)
+	assert.Equal(t, int64(16), MaxResponseSize())
+
+	// the base url of the config replaces the base url of the request, and the response exceeds its maximum size
+	u := &JsonApiUrl{BaseUrl: "http://unused", DrupalEntity: "node", DrupalBundle: "islandora_object"}
+	s, err := u.Url()
+	require.Nil(t, err)
+	assert.Equal(t, server.URL+"/jsonapi/node/islandora_object", s)
+	err = u.Fetch(&JsonApiResponse{})
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+
+	require.Nil(t, UseConfig(&env.Config{BaseUrl: server.URL}))
+	assert.Equal(t, int64(DefaultMaxResponseSize), MaxResponseSize())
+	assert.Nil(t, u.Fetch(&JsonApiResponse{}))
+
+	require.Nil(t, UseConfig(nil))
+	assert.Equal(t, time.Duration(0), HTTPClient().Timeout)
+	s, err = u.Url()
+	require.Nil(t, err)
+	assert.Equal(t, env.BaseUrlOr("http://unused")+"/jsonapi/node/islandora_object", s)
+}
@@ -0,0 +1,166 @@
+package jsonapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A resource object of a JSON API response, e.g. an element of JsonApiResponse.Data, with accessors for nested
+// values.  Values are addressed by dotted paths, whose segments may be indexed, e.g. `field_description[0].value` or
+// `field_description.0.value`.  The typed getters (String, StringSlice, Int, and Bool) address the attributes of the
+// resource object, coerce the value to their type where possible, and answer the zero value otherwise.
+type JsonApiData map[string]interface{}
+
+// Answers the resource objects of the response
+func (jar *JsonApiResponse) Items() []JsonApiData {
+	items := make([]JsonApiData, 0, len(jar.Data))
+	for _, d := range jar.Data {
+		items = append(items, JsonApiData(d))
+	}
+	return items
+}
+
+// Answers the type of the resource object, e.g. `node--islandora_object`
+func (d JsonApiData) Type() string {
+	s, _ := d["type"].(string)
+	return s
+}
+
+// Answers the UUID of the resource object
+func (d JsonApiData) Id() string {
+	s, _ := d["id"].(string)
+	return s
+}
+
+// Answers the value at the path from the root of the resource object, e.g. `relationships.field_genre.data[0].id`,
+// and whether it is present
+func (d JsonApiData) Lookup(path string) (interface{}, bool) {
+	var v interface{} = map[string]interface{}(d)
+	for _, segment := range splitPath(path) {
+		switch c := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = c[segment]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			v = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Answers the value at the path within the attributes of the resource object, e.g. `field_description[0].value`, or
+// nil if it is absent
+func (d JsonApiData) Attribute(path string) interface{} {
+	v, _ := d.Lookup("attributes." + path)
+	return v
+}
+
+// Answers the UUIDs of the resources referenced by the named relationship, in order.  Single-valued and multi-valued
+// relationships are both answered as a slice; an empty slice is answered if the relationship is absent or empty.
+func (d JsonApiData) RelationshipIDs(name string) []string {
+	data, _ := d.Lookup("relationships." + name + ".data")
+	var refs []interface{}
+	switch data := data.(type) {
+	case map[string]interface{}:
+		refs = []interface{}{data}
+	case []interface{}:
+		refs = data
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ref, _ := ref.(map[string]interface{})
+		if id, ok := ref["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Answers the attribute at the path as a string.  Numbers and booleans are formatted, and formatted text (e.g. a
+// `description` carrying `value` and `format`) is answered as its value.
+func (d JsonApiData) String(path string) string {
+	return toString(d.Attribute(path))
+}
+
+// Answers the attribute at the path as a slice of strings (see String).  A single value is answered as a slice of one.
+func (d JsonApiData) StringSlice(path string) []string {
+	switch v := d.Attribute(path).(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, toString(item))
+		}
+		return values
+	default:
+		return []string{toString(v)}
+	}
+}
+
+// Answers the attribute at the path as an int.  Numeric strings are parsed, and booleans are answered as 1 and 0.
+func (d JsonApiData) Int(path string) int {
+	switch v := d.Attribute(path).(type) {
+	case float64:
+		return int(v)
+	case string:
+		i, _ := strconv.Atoi(strings.TrimSpace(v))
+		return i
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Answers the attribute at the path as a bool.  The numbers 1 and 0, and the strings `1`, `0`, `true`, and `false`
+// are coerced, as some serializers answer booleans in those forms.
+func (d JsonApiData) Bool(path string) bool {
+	switch v := d.Attribute(path).(type) {
+	case bool:
+		return v
+	case float64:
+		return v == 1
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return b
+	}
+	return false
+}
+
+// Splits a path into its segments, e.g. `a[0].b` into `a`, `0`, and `b`
+func splitPath(path string) []string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	var segments []string
+	for _, s := range strings.Split(path, ".") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		if value, ok := v["value"].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
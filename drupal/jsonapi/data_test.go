@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_JsonApiData(t *testing.T) {
+	res := &JsonApiResponse{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": {"type": "node--islandora_object", "id": "n1",
+		"attributes": {"title": "Moonrise", "drupal_internal__nid": 7, "status": true, "featured_item": "1", "weight": "3",
+			"field_description": [{"value": "A moon rising", "language": "en"}], "field_extent": ["2 boxes", 3],
+			"description": {"value": "<p>Moonrise</p>", "format": "basic_html"}},
+		"relationships": {
+			"field_member_of": {"data": {"type": "node--collection_object", "id": "c1"}},
+			"field_genre": {"data": [{"type": "taxonomy_term--genre", "id": "g1"}, {"type": "taxonomy_term--genre", "id": "g2"}]},
+			"field_subject": {"data": []}}}}`), res))
+	items := res.Items()
+	require.Equal(t, 1, len(items))
+	d := items[0]
+
+	assert.Equal(t, "node--islandora_object", d.Type())
+	assert.Equal(t, "n1", d.Id())
+	assert.Equal(t, "Moonrise", d.Attribute("title"))
+	assert.Equal(t, "A moon rising", d.Attribute("field_description[0].value"))
+	assert.Equal(t, "en", d.Attribute("field_description.0.language"))
+	assert.Nil(t, d.Attribute("field_description[1].value"))
+	assert.Nil(t, d.Attribute("title.value"))
+	v, ok := d.Lookup("relationships.field_genre.data[1].id")
+	assert.True(t, ok)
+	assert.Equal(t, "g2", v)
+
+	assert.Equal(t, []string{"c1"}, d.RelationshipIDs("field_member_of"))
+	assert.Equal(t, []string{"g1", "g2"}, d.RelationshipIDs("field_genre"))
+	assert.Equal(t, []string{}, d.RelationshipIDs("field_subject"))
+	assert.Equal(t, []string{}, d.RelationshipIDs("field_creator"))
+
+	assert.Equal(t, "Moonrise", d.String("title"))
+	assert.Equal(t, "7", d.String("drupal_internal__nid"))
+	assert.Equal(t, "<p>Moonrise</p>", d.String("description"))
+	assert.Equal(t, "", d.String("missing"))
+	assert.Equal(t, []string{"2 boxes", "3"}, d.StringSlice("field_extent"))
+	assert.Equal(t, []string{"Moonrise"}, d.StringSlice("title"))
+	assert.Nil(t, d.StringSlice("missing"))
+	assert.Equal(t, 7, d.Int("drupal_internal__nid"))
+	assert.Equal(t, 3, d.Int("weight"))
+	assert.Equal(t, 1, d.Int("status"))
+	assert.Equal(t, 0, d.Int("title"))
+	assert.True(t, d.Bool("status"))
+	assert.True(t, d.Bool("featured_item"))
+	assert.False(t, d.Bool("title"))
+}
@@ -0,0 +1,104 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A catalog of the resource types exposed by a JSON API, as listed by its entry point (`/jsonapi`).  Types the user
+// may not view, and types whose JSON API resources are disabled, are not listed.
+type Catalog struct {
+	// The url of the collection of each resource type, keyed by type, e.g. `node--islandora_object` maps to
+	// `https://islandora-idc.traefik.me/jsonapi/node/islandora_object`
+	Urls map[DrupalType]string
+}
+
+// Retrieves the JSON API entry point (`/jsonapi`) of the Drupal site at the base url, answering the catalog of the
+// resource types it links.  If the username is not empty, the request is authenticated using HTTP Basic Auth, which
+// may expose types that are not listed for anonymous users.
+func Discover(baseUrl, username, password string) (*Catalog, error) {
+	u := strings.TrimSuffix(baseUrlOr(baseUrl), "/") + "/jsonapi"
+	res, body, err := FetchResource(u, username, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckJson(res, body); err != nil {
+		return nil, err
+	}
+	entry := struct {
+		Links map[string]json.RawMessage `json:"links"`
+	}{}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("error unmarshaling JSONAPI entry point from %s: %w", u, err)
+	}
+	if entry.Links == nil {
+		return nil, fmt.Errorf("%s did not answer a JSON:API entry point", u)
+	}
+
+	c := &Catalog{Urls: map[DrupalType]string{}}
+	for name, raw := range entry.Links {
+		// links other than those of resource types, e.g. `self`, are not of the form entity--bundle
+		if !strings.Contains(name, typeSeparator) {
+			continue
+		}
+		t, err := ParseDrupalType(name)
+		if err != nil {
+			continue
+		}
+		link := struct {
+			Href string `json:"href"`
+		}{}
+		if err := json.Unmarshal(raw, &link); err != nil || link.Href == "" {
+			continue
+		}
+		c.Urls[t] = link.Href
+	}
+	return c, nil
+}
+
+// Answers the resource types of the catalog, ordered
+func (c *Catalog) Types() []DrupalType {
+	types := make([]DrupalType, 0, len(c.Urls))
+	for t := range c.Urls {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Answers whether the catalog lists the resource type, e.g. `taxonomy_term--genre`
+func (c *Catalog) Has(t DrupalType) bool {
+	_, ok := c.Urls[t]
+	return ok
+}
+
+// Answers the url of the collection of the resource type, and whether the catalog lists the type
+func (c *Catalog) Url(t DrupalType) (string, bool) {
+	u, ok := c.Urls[t]
+	return u, ok
+}
+
+// Answers the entity types of the catalog, e.g. `media`, `node`, and `taxonomy_term`, ordered
+func (c *Catalog) Entities() []string {
+	var entities []string
+	for _, t := range c.Types() {
+		if len(entities) == 0 || entities[len(entities)-1] != t.Entity() {
+			entities = append(entities, t.Entity())
+		}
+	}
+	return entities
+}
+
+// Answers the bundles of the entity type (e.g. `audio`, `document`, ... for `media`), ordered.  Answers nil if the
+// catalog lists no type of the entity.
+func (c *Catalog) Bundles(entity string) []string {
+	var bundles []string
+	for _, t := range c.Types() {
+		if t.Entity() == entity {
+			bundles = append(bundles, t.Bundle())
+		}
+	}
+	return bundles
+}
@@ -0,0 +1,65 @@
+package jsonapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Discover(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "attributes": map[string]interface{}{"title": "Moonrise"}},
+		jsonapitest.Resource{"type": "media--image", "attributes": map[string]interface{}{"name": "Moonrise"}},
+		jsonapitest.Resource{"type": "media--document"},
+		jsonapitest.Resource{"type": "taxonomy_term--genre"},
+		jsonapitest.Resource{"type": "user--user"})
+
+	c, err := Discover(m.URL+"/", "", "")
+	require.Nil(t, err)
+	assert.Equal(t, []DrupalType{"media--document", "media--image", "node--islandora_object", "taxonomy_term--genre", "user--user"}, c.Types())
+	assert.Equal(t, []string{"media", "node", "taxonomy_term", "user"}, c.Entities())
+	assert.Equal(t, []string{"document", "image"}, c.Bundles("media"))
+	assert.Nil(t, c.Bundles("file"))
+	assert.True(t, c.Has("taxonomy_term--genre"))
+	assert.False(t, c.Has("taxonomy_term--subject"))
+	u, ok := c.Url("node--islandora_object")
+	assert.True(t, ok)
+	assert.Equal(t, m.URL+"/jsonapi/node/islandora_object", u)
+
+	// a Searcher of the catalog searches only the bundles the site exposes
+	s, err := DiscoverSearcher(m.URL, "", "")
+	require.Nil(t, err)
+	matches, err := s.FindAll("media", "Moonrise")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(matches))
+	assert.Equal(t, DrupalType("media--image"), matches[0].Type)
+	match, err := s.Find("node", "Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, DrupalType("node--islandora_object"), match.Type)
+	var paths []string
+	for _, r := range m.Requests() {
+		paths = append(paths, r.Path)
+	}
+	assert.Equal(t, []string{"/jsonapi", "/jsonapi", "/jsonapi/media/document", "/jsonapi/media/image", "/jsonapi/node/islandora_object"}, paths)
+}
+
+func Test_DiscoverNotAnEntryPoint(t *testing.T) {
+	body := `<html>Drupal already installed</html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	_, err := Discover(server.URL, "", "")
+	assert.True(t, errors.Is(err, ErrNotJson))
+
+	body = `{"data": []}`
+	_, err = Discover(server.URL, "", "")
+	assert.EqualError(t, err, server.URL+"/jsonapi did not answer a JSON:API entry point")
+}
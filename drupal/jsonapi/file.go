@@ -0,0 +1,68 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+const (
+	// The Drupal file entity type, whose only bundle is also `file`
+	fileEntity = "file"
+	fileBundle = "file"
+)
+
+// Retrieves the file entity with the UUID, addressing it directly (`/jsonapi/file/file/{uuid}`) rather than by a
+// filter.  If the username is not empty, the request is authenticated using HTTP Basic Auth.
+func FetchFile(baseUrl, username, password, uuid string) (JsonApiData, error) {
+	if !uuidPattern.MatchString(uuid) {
+		return nil, fmt.Errorf("error retrieving file: '%s' is not a UUID", uuid)
+	}
+	u := strings.TrimSuffix(baseUrlOr(baseUrl), "/") + "/jsonapi/" + fileEntity + "/" + fileBundle + "/" + uuid
+	httpRes, body, err := FetchResource(u, username, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckJson(httpRes, body); err != nil {
+		return nil, err
+	}
+	res := &JsonApiResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, fmt.Errorf("error unmarshaling JSONAPI response body from %s: %w", u, err)
+	}
+	if len(res.Data) != 1 {
+		return nil, fmt.Errorf("exactly one JSONAPI data element is expected in the response from %s, but found %d element(s)", u, len(res.Data))
+	}
+	return res.Data[0], nil
+}
+
+// Answers a JsonApiUrl that retrieves the file entities with the supplied filename, e.g. `moonrise.jpg`
+func FilesNamedUrl(baseUrl, filename string) *JsonApiUrl {
+	return &JsonApiUrl{
+		BaseUrl:      baseUrl,
+		DrupalEntity: fileEntity,
+		DrupalBundle: fileBundle,
+		Filter:       "filename",
+		Value:        filename,
+	}
+}
+
+// Retrieves every file entity with the supplied filename.  More than one file may share a filename, e.g. when a
+// migration was run twice.  If the username is not empty, requests are authenticated using HTTP Basic Auth.
+func FetchFilesNamed(baseUrl, username, password, filename string) ([]JsonApiData, error) {
+	u := FilesNamedUrl(baseUrl, filename)
+	u.Username, u.Password = username, env.Secret(password)
+	var files []JsonApiData
+	err := u.FetchPages(func(page *JsonApiPage) error {
+		for _, d := range page.Data {
+			files = append(files, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving files named %s: %w", filename, err)
+	}
+	return files, nil
+}
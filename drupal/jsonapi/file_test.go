@@ -0,0 +1,51 @@
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FetchFile(t *testing.T) {
+	id := "5e2ffa9f-0b0a-4b6b-9d7b-0e2d5c2c0f7e"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/jsonapi/file/file/"+id:
+			w.Write([]byte(`{"data": {"type": "file--file", "id": "` + id + `", "attributes": {"filename": "moonrise.jpg", "filemime": "image/jpeg", "status": false}}}`))
+		case r.URL.Path == "/jsonapi/file/file" && r.URL.Query().Get("filter[filename]") == "moonrise.jpg":
+			w.Write([]byte(`{"data": [
+				{"type": "file--file", "id": "` + id + `", "attributes": {"filename": "moonrise.jpg", "status": false}},
+				{"type": "file--file", "id": "f2", "attributes": {"filename": "moonrise.jpg", "status": true}}
+			]}`))
+		case r.URL.Path == "/jsonapi/file/file":
+			w.Write([]byte(`{"data": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	file, err := FetchFile(server.URL, "", "", id)
+	require.Nil(t, err)
+	assert.Equal(t, "file--file", file.Type())
+	assert.Equal(t, "image/jpeg", file.String("filemime"))
+	assert.False(t, file.Bool("status"))
+
+	_, err = FetchFile(server.URL, "", "", "moonrise.jpg")
+	assert.NotNil(t, err)
+	_, err = FetchFile(server.URL, "", "", "00000000-0000-4000-8000-000000000000")
+	assert.NotNil(t, err)
+
+	files, err := FetchFilesNamed(server.URL, "", "", "moonrise.jpg")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(files))
+	assert.Equal(t, id, files[0].Id())
+	assert.True(t, files[1].Bool("status"))
+
+	files, err = FetchFilesNamed(server.URL, "", "", "moonset.jpg")
+	require.Nil(t, err)
+	assert.Empty(t, files)
+}
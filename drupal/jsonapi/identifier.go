@@ -0,0 +1,40 @@
+package jsonapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The field carrying the legacy Islandora 7 (Fedora 3) PID of a migrated node, e.g. `islandora:1234`
+var PidField = "field_pid"
+
+// Matches a Fedora 3 PID: a namespace and an id separated by a colon, e.g. `islandora:1234` or `jhu-coll:10.5`
+var pidPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9.-]*[A-Za-z0-9])?:(?:[A-Za-z0-9~_.-]|%[0-9A-Fa-f]{2})+$`)
+
+// The prefix of a PID expressed as a Fedora 3 URI, e.g. `info:fedora/islandora:1234`
+const fedoraUriPrefix = "info:fedora/"
+
+// Answers true if the identifier is a Fedora 3 PID, e.g. `islandora:1234` or `info:fedora/islandora:1234`
+func IsPid(identifier string) bool {
+	return pidPattern.MatchString(strings.TrimPrefix(identifier, fedoraUriPrefix))
+}
+
+// Answers the PID of an identifier for which IsPid is true, without any `info:fedora/` prefix
+func NormalizePid(identifier string) string {
+	return strings.TrimPrefix(identifier, fedoraUriPrefix)
+}
+
+// Answers the field on which a node is filtered to resolve the identifier, and the value filtered on.  An identifier
+// is resolved by the first that applies of:
+//   - its UUID, filtering on `id`
+//   - its legacy PID (see IsPid), filtering on PidField
+//   - its title, filtering on `title`
+func NodeIdentifierFilter(identifier string) (field, value string) {
+	switch {
+	case uuidPattern.MatchString(identifier):
+		return "id", identifier
+	case IsPid(identifier):
+		return PidField, NormalizePid(identifier)
+	}
+	return "title", identifier
+}
@@ -0,0 +1,30 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsPid(t *testing.T) {
+	for _, id := range []string{"islandora:1234", "jhu-coll:10.5", "info:fedora/islandora:root", "demo:a%2Fb"} {
+		assert.True(t, IsPid(id), id)
+	}
+	for _, id := range []string{"Moonrise", "Moonrise: a Photograph", "islandora:", ":1234", "3f2b1c4d-0000-4000-8000-000000000001"} {
+		assert.False(t, IsPid(id), id)
+	}
+	assert.Equal(t, "islandora:1234", NormalizePid("info:fedora/islandora:1234"))
+}
+
+func Test_NodeIdentifierFilter(t *testing.T) {
+	for _, c := range []struct{ identifier, field, value string }{
+		{"3f2b1c4d-0000-4000-8000-000000000001", "id", "3f2b1c4d-0000-4000-8000-000000000001"},
+		{"info:fedora/islandora:1234", "field_pid", "islandora:1234"},
+		{"islandora:1234", "field_pid", "islandora:1234"},
+		{"Moonrise", "title", "Moonrise"},
+	} {
+		field, value := NodeIdentifierFilter(c.identifier)
+		assert.Equal(t, c.field, field, c.identifier)
+		assert.Equal(t, c.value, value, c.identifier)
+	}
+}
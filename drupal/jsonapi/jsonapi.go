@@ -3,15 +3,14 @@ package jsonapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jhu-idc/idc-golang/drupal/env"
-	"github.com/stretchr/testify/assert"
-	"io/ioutil"
-	"log"
+	"github.com/jhu-idc/idc-golang/drupal/logging"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
-	"testing"
 )
 
 // Encapsulates the Entity type and bundle of a Drupal resource.
@@ -20,19 +19,69 @@ import (
 //   "type": "taxonomy_term--person"
 type DrupalType string
 
+// The separator between the entity and bundle of a DrupalType
+const typeSeparator = "--"
+
+// The string is not a valid DrupalType
+var ErrInvalidDrupalType = errors.New("invalid drupal type")
+
+// Answers the DrupalType represented by the supplied string, e.g. `taxonomy_term--person` or `user--user`.  Entities
+// without bundles may be supplied without one (e.g. `user`), and are answered as `user--user`, as they are represented
+// by the JSON API.  An error wrapping ErrInvalidDrupalType is answered if the entity or bundle is empty, or if the
+// string contains more than one separator.
+func ParseDrupalType(s string) (DrupalType, error) {
+	parts := strings.Split(s, typeSeparator)
+	switch {
+	case len(parts) > 2:
+		return "", fmt.Errorf("%w: '%s' contains more than one '%s'", ErrInvalidDrupalType, s, typeSeparator)
+	case parts[0] == "":
+		return "", fmt.Errorf("%w: '%s' has an empty entity", ErrInvalidDrupalType, s)
+	case len(parts) == 1:
+		return DrupalType(s + typeSeparator + s), nil
+	case parts[1] == "":
+		return "", fmt.Errorf("%w: '%s' has an empty bundle", ErrInvalidDrupalType, s)
+	}
+	return DrupalType(s), nil
+}
+
 // The entity (e.g. taxonomy_term, node, etc) encapsulated by this type
 func (t DrupalType) Entity() string {
-	return strings.Split(string(t), "--")[0]
+	entity, _ := t.split()
+	return entity
 }
 
-// The bundle (e.g. 'person', 'islandora_object', etc) encapsulated by this type
+// The bundle (e.g. 'person', 'islandora_object', etc) encapsulated by this type.  Entities without bundles (like User)
+// answer their entity type, following the JSON API convention of representing them as e.g. 'user--user'.
 func (t DrupalType) Bundle() string {
-	// TODO: some entities (like User) do not have a bundle type
-	return strings.Split(string(t), "--")[1]
+	_, bundle := t.split()
+	return bundle
 }
 
-// Default HTTP client
-var httpClient = &http.Client{}
+// Answers true if the entity has no bundles (e.g. 'user' or 'user--user'), in which case the JSON API uses the entity
+// type as the bundle
+func (t DrupalType) IsBundleless() bool {
+	entity, bundle := t.split()
+	return entity == bundle
+}
+
+// Answers the entity and bundle of this type.  Neither the entity nor bundle may contain the separator, so anything
+// following a second separator is ignored.
+func (t DrupalType) split() (entity, bundle string) {
+	parts := strings.SplitN(string(t), typeSeparator, 3)
+	if len(parts) == 1 {
+		return parts[0], parts[0]
+	}
+	return parts[0], parts[1]
+}
+
+// HTTP client used for every request; see Configure and SetHTTPClient
+var httpClient = withRun(&http.Client{})
+
+// Reports the failures of assertions; satisfied by *testing.T and by testify's assert.TestingT, without this package
+// depending on either
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
 
 // Encapsulates the relevant components of a URL which executes a JSON API request against Drupal; the typical
 // entrypoint into the JSON API for making queries and retrieving results.
@@ -42,10 +91,15 @@ var httpClient = &http.Client{}
 // If the RawFilter is present, Filter and Value are ignored, and the RawFilter is appended to the JSON API url as-is.
 // An example RawFilter value might be: `filter[name-group][condition][operator]=ENDS_WITH&filter[name-group][condition][path]=name&filter[name-group][condition][value]=Thumbnail Image.jpg&filter[of-group][condition][path]=field_media_of.title&filter[of-group][condition][value]=Derivative Image 04`
 type JsonApiUrl struct {
-	T            assert.TestingT
+	// Reports failures of the assertions of Get and GetSingle, and of String; unused by Fetch and FetchSingle
+	T            TestingT
 	BaseUrl      string
 	DrupalEntity string
 	DrupalBundle string
+	// The UUID of a single resource, e.g. one referenced by a relationship, retrieved from its individual resource
+	// endpoint (`/jsonapi/{entity}/{bundle}/{uuid}`) rather than by filtering the collection.  Any filter is still
+	// appended to the url, so RawFilter may carry e.g. `include=field_member_of`.
+	Uuid string
 	// Filter is the name of the field to match on, e.g. `title`, `name`, or `id`.
 	// If RawFilter is supplied, this field is ignored.
 	Filter string
@@ -56,47 +110,62 @@ type JsonApiUrl struct {
 	// RawFilter is supplied by the caller and is used as-is.  In that case, Filter and Value are not used.
 	RawFilter string
 	// The username to use when authenticating to Drupal's JSONAPI endpoint.  If this value is empty, no `Authorization` header will be sent, otherwise Basic authentication is used.
-	Username  string
-	// The password to use when authenticating to Drupal's JSONAPI endpoint.
-	Password  string
+	Username string
+	// The password to use when authenticating to Drupal's JSONAPI endpoint, redacted wherever it is formatted.
+	Password env.Secret
+	// The language code (e.g. `es`) of the translation to retrieve.  If empty, resources are retrieved in the site
+	// default language.  Note that Drupal answers the default language for resources that lack the translation.
+	Langcode string
+	// Decodes responses strictly (see DecodeStrict), so that a field of the value that no attribute populates (e.g.
+	// one whose json tag is misspelled) fails the request, as do attributes the value does not model
+	Strict bool
 }
 
-// Get the JSON API content from the URL and unmarshal the response into the supplied interface (which must be a
-// pointer).  This method asserts that there is a single object in the `data` element of the JSON response.
-func (jar *JsonApiUrl) GetSingle(v interface{}) {
-	var res *http.Response
-	var body[]byte
+// Fetch behaves as Get, but answers an error instead of making assertions, so it may be used outside of `go test`
+// (e.g. by command line tools).  The JsonApiUrl.T field is not used.
+func (jar *JsonApiUrl) Fetch(v interface{}) error {
+	return jar.fetch(v, false)
+}
 
-	// retrieve json of the migrated entity from the jsonapi and unmarshal the single response
-	if len(strings.TrimSpace(jar.Username)) == 0 {
-		res, body = GetResource(jar.T.(*testing.T), jar.String())
-	} else {
-		res, body = GetResourceWithBasicAuth(jar.T.(*testing.T), jar.String(), jar.Username, jar.Password)
-	}
-	defer func() { _ = res.Close }()
-	UnmarshalSingleResponse(jar.T.(*testing.T), body, res, &JsonApiResponse{}).To(v)
+// FetchSingle behaves as GetSingle, but answers an error instead of making assertions.  An error is returned if the
+// `data` element of the JSON response does not contain exactly one object.
+func (jar *JsonApiUrl) FetchSingle(v interface{}) error {
+	return jar.fetch(v, true)
 }
 
-// Get the JSON API content from the URL and unmarshal the response into the supplied interface (which must be a
-// pointer).
-func (jar *JsonApiUrl) Get(v interface{}) {
-	var res *http.Response
-	var body[]byte
+func (jar *JsonApiUrl) fetch(v interface{}, single bool) error {
+	u, err := jar.Url()
+	if err != nil {
+		return err
+	}
 
-	// retrieve json of the migrated entity from the jsonapi and unmarshal the single response
-	if len(strings.TrimSpace(jar.Username)) == 0 {
-		res, body = GetResource(jar.T.(*testing.T), jar.String())
-	} else {
-		res, body = GetResourceWithBasicAuth(jar.T.(*testing.T), jar.String(), jar.Username, jar.Password)
+	httpRes, body, err := FetchResource(u, jar.Username, jar.Password.Reveal())
+	if err != nil {
+		return err
+	}
+	if err := CheckJson(httpRes, body); err != nil {
+		return err
+	}
+
+	res := &JsonApiResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return fmt.Errorf("error unmarshaling JSONAPI response body from %s: %w", u, err)
 	}
-	defer func() { _ = res.Close }()
-	UnmarshalResponse(jar.T.(*testing.T), body, res, &JsonApiResponse{}, nil).To(v)
+
+	if single && len(res.Data) != 1 {
+		return fmt.Errorf("exactly one JSONAPI data element is expected in the response from %s, but found %d element(s)", u, len(res.Data))
+	}
+
+	if jar.Strict {
+		return res.DecodeStrict(v)
+	}
+	return res.Decode(v)
 }
 
 // Encapsulates a generic JSON API response
 type JsonApiResponse struct {
 	// The 'data' element(s) of the response
-	Data []map[string]interface{}
+	Data []map[string]interface{} `json:"data"`
 }
 
 // Handles the case where the 'data' key contains an array of objects, or a single object.
@@ -126,82 +195,152 @@ func (jar *JsonApiResponse) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Adapts the generic JsonApiResponse to a higher-fidelity type, answering any error encountered
+func (jar *JsonApiResponse) Decode(v interface{}) error {
+	b, err := json.Marshal(jar)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %v as json: %w", jar, err)
+	}
+	return json.Unmarshal(b, v)
+}
+
 // Adapts the generic JsonApiResponse to a higher-fidelity type
 func (jar *JsonApiResponse) To(v interface{}) {
 	if b, e := json.Marshal(jar); e != nil {
-		log.Fatalf("Unable to marshal %v as json: %s", jar, e)
+		logging.Errorf("Unable to marshal %v as json: %s", jar, e)
+		os.Exit(1)
 	} else {
 		json.Unmarshal(b, v)
 	}
 }
 
+// Answers a JsonApiUrl that retrieves the single resource of the type with the UUID, e.g. one referenced by a
+// relationship, from its individual resource endpoint: `{baseUrl}/jsonapi/{entity}/{bundle}/{uuid}`
+func ResourceUrl(baseUrl string, t DrupalType, uuid string) *JsonApiUrl {
+	return &JsonApiUrl{
+		BaseUrl:      baseUrl,
+		DrupalEntity: t.Entity(),
+		DrupalBundle: t.Bundle(),
+		Uuid:         uuid,
+	}
+}
+
+// Assigns the password to use when authenticating, so that a caller holding the password as a plain string (as
+// Password was before it became an env.Secret) need not convert it
+func (moo *JsonApiUrl) SetPassword(password string) {
+	moo.Password = env.Secret(password)
+}
+
 // Compose and return a string representation of the JSONAPI URL
 func (moo *JsonApiUrl) String() string {
+	u, err := moo.Url()
+	if err != nil && moo.T != nil {
+		moo.T.Errorf("error generating a JsonAPI URL: %s", err)
+	}
+	return u
+}
+
+// Answers the path segments of the JSONAPI URL, beginning with the base url.  Translations are addressed by prefixing
+// the path with the language code, e.g. `/es/jsonapi/taxonomy_term/subject`, and a single resource by suffixing it with
+// its UUID.
+func (moo *JsonApiUrl) pathSegments(baseUrl string) []string {
+	segments := []string{baseUrl, "jsonapi", moo.DrupalEntity, moo.DrupalBundle}
+	if moo.Langcode != "" {
+		segments = append([]string{baseUrl, moo.Langcode}, segments[1:]...)
+	}
+	if moo.Uuid != "" {
+		segments = append(segments, moo.Uuid)
+	}
+	return segments
+}
+
+// Compose the JSONAPI URL, answering an error if a required component is missing or the URL cannot be parsed
+func (moo *JsonApiUrl) Url() (string, error) {
 	var u *url.URL
 	var err error
 
-	assert.NotEmpty(moo.T, moo.BaseUrl, "error generating a JsonAPI URL from %v: %s", moo, "base url must not be empty")
-	assert.NotEmpty(moo.T, moo.DrupalEntity, "error generating a JsonAPI URL from %v: %s", moo, "drupal entity must not be empty")
-	assert.NotEmpty(moo.T, moo.DrupalBundle, "error generating a JsonAPI URL from %v: %s", moo, "drupal bundle must not be empty")
+	if moo.BaseUrl == "" {
+		return "", fmt.Errorf("error generating a JsonAPI URL: %s", "base url must not be empty")
+	}
+	if moo.DrupalEntity == "" {
+		return "", fmt.Errorf("error generating a JsonAPI URL: %s", "drupal entity must not be empty")
+	}
+	if moo.DrupalBundle == "" {
+		return "", fmt.Errorf("error generating a JsonAPI URL: %s", "drupal bundle must not be empty")
+	}
+	if moo.Uuid != "" && !uuidPattern.MatchString(moo.Uuid) {
+		return "", fmt.Errorf("error generating a JsonAPI URL: '%s' is not a UUID", moo.Uuid)
+	}
 
-	baseUrl := env.BaseUrlOr(moo.BaseUrl)
+	baseUrl := baseUrlOr(moo.BaseUrl)
 	if strings.HasSuffix(baseUrl, "/") {
 		baseUrl = baseUrl[:len(baseUrl) - 1]
 	}
-	u, err = url.Parse(fmt.Sprintf("%s", strings.Join([]string{baseUrl, "jsonapi", moo.DrupalEntity, moo.DrupalBundle}, "/")))
-	assert.Nil(moo.T, err, "error generating a JsonAPI URL from %v: %s", moo, err)
+	if u, err = url.Parse(fmt.Sprintf("%s", strings.Join(moo.pathSegments(baseUrl), "/"))); err != nil {
+		return "", fmt.Errorf("error generating a JsonAPI URL: %w", err)
+	}
 
 	// If a raw filter is supplied, use it as-is, otherwise use the .Filter and .Value
 	if moo.RawFilter != "" {
 		u, err = url.Parse(fmt.Sprintf("%s?%s", u.String(), moo.RawFilter))
 	} else if moo.Filter != "" {
-		u, err = url.Parse(fmt.Sprintf("%s?filter[%s]=%s", u.String(), moo.Filter, moo.Value))
+		u, err = url.Parse(fmt.Sprintf("%s?filter[%s]=%s", u.String(), moo.Filter, escapeFilterValue(moo.Value)))
 	}
 
-	assert.Nil(moo.T, err, "error generating a JsonAPI URL from %v: %s", moo, err)
-	return u.String()
-}
+	if err != nil {
+		return "", fmt.Errorf("error generating a JsonAPI URL: %w", err)
+	}
 
-// Unmarshal a JSONAPI response body and assert that exactly one data element is present
-func UnmarshalSingleResponse(t *testing.T, body []byte, res *http.Response, value *JsonApiResponse) *JsonApiResponse {
-	UnmarshalResponse(t, body, res, value, func(value *JsonApiResponse) {
-		assert.Equal(t, 1, len(value.Data), "Exactly one JSONAPI data element is expected in the response, but found %d element(s)", len(value.Data))
-	})
-	return value
+	// Values like `Analog Photography` are commonly used in raw filters, but a space is not permitted in the request
+	// line
+	u.RawQuery = strings.ReplaceAll(u.RawQuery, " ", "%20")
+	return u.String(), nil
 }
 
-// Unmarshal a JSONAPI response body and perform supplied assertions on the response
-func UnmarshalResponse(t *testing.T, body []byte, res *http.Response, value *JsonApiResponse, responseAssertions func(res *JsonApiResponse)) *JsonApiResponse {
-	err := json.Unmarshal(body, value)
-	assert.Nil(t, err, "Error unmarshaling JSONAPI response body: %s", err)
-	if responseAssertions != nil {
-		responseAssertions(value)
-	}
-	return value
+// Answers the filter value escaped for a query, so that values like `Arts & Crafts` or `100%` are not split into, or
+// mistaken for, other parameters.  A space is escaped as `%20` rather than `+`, which Drupal decodes alike.
+func escapeFilterValue(v string) string {
+	return strings.ReplaceAll(url.QueryEscape(v), "+", "%20")
 }
 
-// GetResource returns the HTTP response and body from the supplied url.  It asserts that the HTTP status code is 200,
-// and that no errors are encountered reading the response body.  The requeest will be unauthenticated
-func GetResource(t *testing.T, u string) (*http.Response, []byte) {
-	return GetResourceWithBasicAuth(t, u, "", "")
+// FetchResource returns the HTTP response and body from the supplied url.  Unlike GetResourceWithBasicAuth, no
+// assertions are made: an error is answered if the request cannot be executed, the HTTP status code is not 200, or the
+// response body cannot be read.  If the supplied username is empty, then the request will be sent without an
+// Authorization header.
+func FetchResource(url, username, password string) (*http.Response, []byte, error) {
+	req, err := newRequest(url, username, password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encountered error creating request for %s: %w", url, err)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encountered error requesting %s: %w", url, err)
+	}
+	defer res.Body.Close()
+	body, err := readBody(res)
+	if err != nil {
+		return res, nil, fmt.Errorf("error encountered reading response body from %s: %w", url, err)
+	}
+	if res.StatusCode != 200 {
+		return res, body, fmt.Errorf("%d status encountered when requesting %s", res.StatusCode, url)
+	}
+	return res, body, nil
 }
 
-// GetResourceWithAuthn returns the HTTP response and body from the supplied url.  It asserts that the HTTP status code
-// is 200, and that no errors are encountered reading the response body.  The supplied username and password are used to
-// send a Basic Authorization header.  If the supplied username is empty, then the request will be sent without an
-// Authorization header.
-func GetResourceWithBasicAuth(t *testing.T, url, username, password string) (*http.Response, []byte) {
+// newRequest creates a GET request for the supplied url accepting a gzip-encoded JSON API document, using HTTP Basic
+// Auth if the username is not empty
+func newRequest(url, username, password string) (*http.Request, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaType)
+	req.Header.Set("Accept-Encoding", "gzip")
 	if len(strings.TrimSpace(username)) > 0 {
 		req.SetBasicAuth(username, password)
-		log.Printf("Retrieving (with Authorization: basic) %s", url)
+		logging.Infof("Retrieving (with Authorization: basic) %s [run %s]", url, RunId())
 	} else {
-		log.Printf("Retrieving %s", url)
+		logging.Infof("Retrieving %s [run %s]", url, RunId())
 	}
-	res, err := httpClient.Do(req)
-	assert.Nil(t, err, "encountered error requesting %s: %s", url, err)
-	assert.Equal(t, 200, res.StatusCode, "%d status encountered when requesting %s", res.StatusCode, url)
-	body, err := ioutil.ReadAll(res.Body)
-	assert.Nil(t, err, "error encountered reading response body from %s: %s", url, err)
-	return res, body
-}
\ No newline at end of file
+	return req, nil
+}
@@ -1,7 +1,11 @@
+//go:build !notestify
+// +build !notestify
+
 package jsonapi
 
 import (
 	"fmt"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"html"
@@ -204,3 +208,105 @@ func Test_GetResourceWithBasicAuthn(t *testing.T) {
 	u.Get(result)
 	assert.True(t, handlers[noAuthHandlerPath].wasCalled())
 }
+
+func Test_SetPassword(t *testing.T) {
+	password := "s3cret"
+	u := &JsonApiUrl{Username: "admin"}
+	u.SetPassword(password)
+	assert.True(t, u.Password.Equal(password))
+	assert.NotContains(t, fmt.Sprintf("%+v", u), password)
+}
+
+func Test_DrupalTypeBundleless(t *testing.T) {
+	assert.Equal(t, "taxonomy_term", DrupalType("taxonomy_term--person").Entity())
+	assert.Equal(t, "person", DrupalType("taxonomy_term--person").Bundle())
+	assert.False(t, DrupalType("taxonomy_term--person").IsBundleless())
+	assert.Equal(t, "user", DrupalType("user--user").Bundle())
+	assert.True(t, DrupalType("user--user").IsBundleless())
+	assert.Equal(t, "user", DrupalType("user").Entity())
+	assert.Equal(t, "user", DrupalType("user").Bundle())
+	assert.True(t, DrupalType("user").IsBundleless())
+	assert.Equal(t, "", DrupalType("").Entity())
+	assert.Equal(t, "", DrupalType("").Bundle())
+}
+
+func Test_ParseDrupalType(t *testing.T) {
+	valid := map[string]DrupalType{
+		"taxonomy_term--person": "taxonomy_term--person",
+		"user--user":            "user--user",
+		"user":                  "user--user",
+	}
+	for s, expected := range valid {
+		actual, err := ParseDrupalType(s)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	for _, s := range []string{"", "--person", "node--", "media--image--extra"} {
+		_, err := ParseDrupalType(s)
+		assert.ErrorIs(t, err, ErrInvalidDrupalType, "parsing '%s'", s)
+	}
+}
+
+func Test_JsonApiUrlLangcode(t *testing.T) {
+	u := &JsonApiUrl{BaseUrl: "https://islandora-idc.traefik.me/", DrupalEntity: "taxonomy_term", DrupalBundle: "subject", Langcode: "es"}
+	actual, err := u.Url()
+	assert.Nil(t, err)
+	assert.Equal(t, "https://islandora-idc.traefik.me/es/jsonapi/taxonomy_term/subject", actual)
+}
+
+func Test_JsonApiUrlUuid(t *testing.T) {
+	u := &JsonApiUrl{BaseUrl: "https://islandora-idc.traefik.me/", DrupalEntity: "node", DrupalBundle: "islandora_object",
+		Uuid: "329c57a2-97f2-4350-8b54-439237c68311", RawFilter: "include=field_member_of", Langcode: "es"}
+	actual, err := u.Url()
+	assert.Nil(t, err)
+	assert.Equal(t, "https://islandora-idc.traefik.me/es/jsonapi/node/islandora_object/329c57a2-97f2-4350-8b54-439237c68311?include=field_member_of", actual)
+
+	u.Uuid = "../../user/user"
+	_, err = u.Url()
+	assert.EqualError(t, err, "error generating a JsonAPI URL: '../../user/user' is not a UUID")
+
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "0e1ef0c2-2a39-4c39-9ed0-8f2f49a86f7e", "attributes": map[string]interface{}{"name": "Maps"}},
+		jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "4f0d4a4c-21c5-43c3-b4ad-3a2ba1fbd1a2", "attributes": map[string]interface{}{"name": "Maps"}})
+
+	res := &JsonApiResponse{}
+	require.Nil(t, ResourceUrl(m.URL, "taxonomy_term--genre", "4f0d4a4c-21c5-43c3-b4ad-3a2ba1fbd1a2").FetchSingle(res))
+	assert.Equal(t, "4f0d4a4c-21c5-43c3-b4ad-3a2ba1fbd1a2", res.Items()[0].Id())
+
+	err = ResourceUrl(m.URL, "taxonomy_term--genre", "9a9d3c5e-1b7e-4a8f-8d3e-2f4a5b6c7d8e").FetchSingle(res)
+	assert.Contains(t, err.Error(), "404 status encountered")
+
+	// both resources are named Maps, so that only the UUID distinguishes them
+	jar := ResourceUrl(m.URL, "taxonomy_term--genre", "0e1ef0c2-2a39-4c39-9ed0-8f2f49a86f7e")
+	jar.T = t
+	res = &JsonApiResponse{}
+	jar.GetSingle(res)
+	assert.Equal(t, "0e1ef0c2-2a39-4c39-9ed0-8f2f49a86f7e", res.Items()[0].Id())
+	assert.Equal(t, "/jsonapi/taxonomy_term/genre/0e1ef0c2-2a39-4c39-9ed0-8f2f49a86f7e", m.Requests()[2].Path)
+}
+
+// Filter values carrying characters reserved in a query are escaped, rather than split into other parameters
+func Test_JsonApiUrlFilterEscaping(t *testing.T) {
+	u := &JsonApiUrl{BaseUrl: "https://islandora-idc.traefik.me", DrupalEntity: "taxonomy_term", DrupalBundle: "subject",
+		Filter: "name", Value: "Arts & Crafts"}
+	actual, err := u.Url()
+	require.Nil(t, err)
+	assert.Equal(t, "https://islandora-idc.traefik.me/jsonapi/taxonomy_term/subject?filter[name]=Arts%20%26%20Crafts", actual)
+
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	names := []string{"Arts & Crafts", "C++", "#1 Hits", "100% Cotton", "a=b;c", "Analog Photography"}
+	for _, name := range names {
+		m.Add(jsonapitest.Resource{"type": "taxonomy_term--subject", "attributes": map[string]interface{}{"name": name}})
+	}
+	m.Add(jsonapitest.Resource{"type": "taxonomy_term--subject", "attributes": map[string]interface{}{"name": "Arts "}})
+	for _, name := range names {
+		u := &JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "taxonomy_term", DrupalBundle: "subject", Filter: "name", Value: name}
+		res := &JsonApiResponse{}
+		if assert.Nil(t, u.FetchSingle(res), name) {
+			assert.Equal(t, name, res.Items()[0].Attribute("name"))
+		}
+	}
+}
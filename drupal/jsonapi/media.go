@@ -0,0 +1,129 @@
+package jsonapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+const (
+	// The Drupal media entity type
+	mediaEntity = "media"
+	// The Drupal audio media bundle
+	audioBundle = "audio"
+)
+
+// The names of the Islandora media use terms
+const (
+	MediaUseOriginalFile     = "Original File"
+	MediaUseIntermediateFile = "Intermediate File"
+	MediaUsePreservation     = "Preservation Master File"
+	MediaUseServiceFile      = "Service File"
+	MediaUseThumbnail        = "Thumbnail Image"
+	MediaUseExtractedText    = "Extracted Text"
+	MediaUseTranscript       = "Transcript"
+	MediaUseFits             = "FITS File"
+)
+
+// The media bundles searched by GetMediaFor and FetchMediaFor
+var MediaBundles = []string{"audio", "document", "extracted_text", "file", "fits_technical_metadata", "image", "remote_video", "video"}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// The media of a repository object, keyed by the name of their media use term (e.g. `Thumbnail Image`).  Each media
+// is a JSON API resource object.  A media with more than one use appears under each of them, and a media lacking a use
+// appears under the empty string.
+type MediaByUse map[string][]map[string]interface{}
+
+// Answers the names of the media uses present, sorted
+func (m MediaByUse) Uses() []string {
+	var uses []string
+	for use := range m {
+		uses = append(uses, use)
+	}
+	sort.Strings(uses)
+	return uses
+}
+
+// Answers the single media with the use, or nil if there is not exactly one
+func (m MediaByUse) Single(use string) map[string]interface{} {
+	if len(m[use]) != 1 {
+		return nil
+	}
+	return m[use][0]
+}
+
+// Answers a JsonApiUrl that retrieves media of the supplied bundle (e.g. `audio` or `image`) which are media of the
+// node with the supplied title, i.e. the `field_media_of` relationship references a node entitled `title`.
+func MediaOfUrl(t TestingT, baseUrl, bundle, title string) *JsonApiUrl {
+	return &JsonApiUrl{
+		T:            t,
+		BaseUrl:      baseUrl,
+		DrupalEntity: mediaEntity,
+		DrupalBundle: bundle,
+		Filter:       "field_media_of.title",
+		Value:        title,
+	}
+}
+
+// FetchMediaFor behaves as GetMediaFor, but answers an error instead of making assertions.  If the username is not
+// empty, requests are authenticated using HTTP Basic Auth.
+func FetchMediaFor(baseUrl, username, password, titleOrUuid string) (MediaByUse, error) {
+	field, value := NodeIdentifierFilter(titleOrUuid)
+	filter := "field_media_of." + field
+
+	media := MediaByUse{}
+	for _, bundle := range MediaBundles {
+		u := &JsonApiUrl{
+			BaseUrl:      baseUrl,
+			DrupalEntity: mediaEntity,
+			DrupalBundle: bundle,
+			Filter:       filter,
+			Value:        value,
+			Username:     username,
+			Password:     env.Secret(password),
+		}
+		err := u.FetchPages(func(page *JsonApiPage) error {
+			for _, d := range page.Data {
+				uses := mediaUses(page, d)
+				if len(uses) == 0 {
+					uses = []string{""}
+				}
+				for _, use := range uses {
+					media[use] = append(media[use], d)
+				}
+			}
+			return nil
+		}, "field_media_use")
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving %s media of %s: %w", bundle, titleOrUuid, err)
+		}
+	}
+	return media, nil
+}
+
+// Answers the names of the media use terms of the media, resolved from the included resources of the page
+func mediaUses(page *JsonApiPage, media map[string]interface{}) []string {
+	relationships, _ := media["relationships"].(map[string]interface{})
+	rel, _ := relationships["field_media_use"].(map[string]interface{})
+	var refs []interface{}
+	switch data := rel["data"].(type) {
+	case []interface{}:
+		refs = data
+	case map[string]interface{}:
+		refs = []interface{}{data}
+	}
+
+	var uses []string
+	for _, ref := range refs {
+		ref, _ := ref.(map[string]interface{})
+		term := page.Related(ref)
+		attributes, _ := term["attributes"].(map[string]interface{})
+		if name, ok := attributes["name"].(string); ok {
+			uses = append(uses, name)
+		}
+	}
+	return uses
+}
@@ -0,0 +1,62 @@
+//go:build !notestify
+// +build !notestify
+
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MediaOfUrl(t *testing.T) {
+	u := MediaOfUrl(t, "http://localhost/", audioBundle, "Moonrise")
+	assert.Equal(t, "http://localhost/jsonapi/media/audio?filter[field_media_of.title]=Moonrise", u.String())
+
+	u = MediaOfUrl(t, "http://localhost/", audioBundle, "Derivative Image 04")
+	assert.Equal(t, "http://localhost/jsonapi/media/audio?filter[field_media_of.title]=Derivative%20Image%2004", u.String())
+}
+
+func Test_FetchMediaFor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "field_media_use", r.URL.Query().Get("include"))
+		value := r.URL.Query().Get("filter[field_media_of.title]") + r.URL.Query().Get("filter[field_media_of.id]")
+		if value != "Moonrise" && value != "815a4c04-0be5-44f1-a876-e8ddc11dcf21" {
+			w.Write([]byte(`{"data": []}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/jsonapi/media/image":
+			w.Write([]byte(`{"data": [
+				{"type": "media--image", "id": "m1", "relationships": {"field_media_use": {"data": [{"type": "taxonomy_term--islandora_media_use", "id": "u1"}]}}},
+				{"type": "media--image", "id": "m2", "relationships": {"field_media_use": {"data": [{"type": "taxonomy_term--islandora_media_use", "id": "u2"}, {"type": "taxonomy_term--islandora_media_use", "id": "u3"}]}}}
+			], "included": [
+				{"type": "taxonomy_term--islandora_media_use", "id": "u1", "attributes": {"name": "Thumbnail Image"}},
+				{"type": "taxonomy_term--islandora_media_use", "id": "u2", "attributes": {"name": "Service File"}},
+				{"type": "taxonomy_term--islandora_media_use", "id": "u3", "attributes": {"name": "Original File"}}
+			]}`))
+		case "/jsonapi/media/extracted_text":
+			w.Write([]byte(`{"data": [{"type": "media--extracted_text", "id": "m3", "relationships": {"field_media_use": {"data": []}}}]}`))
+		default:
+			w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+
+	media, err := FetchMediaFor(server.URL, "", "", "Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"", MediaUseOriginalFile, MediaUseServiceFile, MediaUseThumbnail}, media.Uses())
+	assert.Equal(t, "m1", media.Single(MediaUseThumbnail)["id"])
+	assert.Equal(t, "m2", media.Single(MediaUseOriginalFile)["id"])
+	assert.Equal(t, "m3", media.Single("")["id"])
+	assert.Nil(t, media.Single(MediaUseFits))
+
+	media = GetMediaFor(t, server.URL, "815a4c04-0be5-44f1-a876-e8ddc11dcf21")
+	assert.Equal(t, 4, len(media.Uses()))
+
+	_, err = FetchMediaFor("", "", "", "Moonrise")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,294 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A request sent by the HTTP client of this package, as observed by the RequestObserver
+type RequestEvent struct {
+	Method string
+	Url    string
+	// The HTTP status code of the response, or 0 if the request failed without a response
+	Status int
+	// The time from sending the request to receiving the headers of its response
+	Duration time.Duration
+	// The error answered by the transport, if any
+	Err error
+}
+
+// Observes each request sent by the HTTP client of this package, e.g. to collect Metrics.  Invoked concurrently if
+// requests are sent concurrently.
+type RequestObserver interface {
+	Observe(e RequestEvent)
+}
+
+var (
+	observerMu sync.RWMutex
+	observer   RequestObserver
+)
+
+// Installs the observer of the requests sent by the HTTP client of this package; nil stops observing requests
+func SetObserver(o RequestObserver) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+	observer = o
+}
+
+// Answers the observer installed by SetObserver, or nil
+func Observer() RequestObserver {
+	observerMu.RLock()
+	defer observerMu.RUnlock()
+	return observer
+}
+
+// A transport that reports each request to the RequestObserver installed by SetObserver.  The HTTP client of this
+// package is always given a MetricsTransport; other clients may install one to be observed too.
+type MetricsTransport struct {
+	// The transport used to send requests; http.DefaultTransport if nil
+	Transport http.RoundTripper
+}
+
+func (mt *MetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := mt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	o := Observer()
+	if o == nil {
+		return transport.RoundTrip(req)
+	}
+	start := time.Now()
+	res, err := transport.RoundTrip(req)
+	e := RequestEvent{Method: req.Method, Url: req.URL.String(), Duration: time.Since(start), Err: err}
+	if res != nil {
+		e.Status = res.StatusCode
+	}
+	o.Observe(e)
+	return res, err
+}
+
+// The upper bounds, in seconds, of the latency histogram buckets of Metrics whose Buckets are empty
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Collects the number of requests, their status codes, and a histogram of their latencies by method and resource
+// (e.g. `node--islandora_object`), so that a run may be compared with earlier runs to spot Drupal performance
+// regressions:
+//
+//	m := jsonapi.NewMetrics()
+//	jsonapi.SetObserver(m)
+//	defer m.WriteFile("metrics.prom")
+type Metrics struct {
+	// The upper bounds, in seconds and ascending, of the latency histogram buckets
+	Buckets []float64
+	mu      sync.Mutex
+	series  map[seriesKey]*series
+}
+
+type seriesKey struct {
+	Method   string
+	Resource string
+}
+
+type series struct {
+	// request counts by status code, "error" counting requests that failed without a response
+	Codes  map[string]int
+	Counts []int
+	Sum    float64
+	Count  int
+}
+
+// Answers Metrics with the DefaultBuckets
+func NewMetrics() *Metrics {
+	return &Metrics{Buckets: DefaultBuckets}
+}
+
+// Records the request
+func (m *Metrics) Observe(e RequestEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Buckets == nil {
+		m.Buckets = DefaultBuckets
+	}
+	if m.series == nil {
+		m.series = map[seriesKey]*series{}
+	}
+	key := seriesKey{Method: e.Method, Resource: Resource(e.Url)}
+	s := m.series[key]
+	if s == nil {
+		s = &series{Codes: map[string]int{}, Counts: make([]int, len(m.Buckets))}
+		m.series[key] = s
+	}
+	code := "error"
+	if e.Status != 0 {
+		code = strconv.Itoa(e.Status)
+	}
+	s.Codes[code]++
+	seconds := e.Duration.Seconds()
+	for i, le := range m.Buckets {
+		if seconds <= le {
+			s.Counts[i]++
+		}
+	}
+	s.Sum += seconds
+	s.Count++
+}
+
+// Answers the resource addressed by the url: `entity--bundle` for JSON API urls like `/jsonapi/node/islandora_object`,
+// otherwise the path of the url
+func Resource(rawUrl string) string {
+	path := rawUrl
+	if u, err := url.Parse(rawUrl); err == nil {
+		path = u.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 3 && segments[0] == "jsonapi" {
+		return segments[1] + "--" + segments[2]
+	}
+	return path
+}
+
+// The requests of a method and resource, as written by Metrics.WriteJson
+type MetricsSummary struct {
+	Method   string         `json:"method"`
+	Resource string         `json:"resource"`
+	Requests int            `json:"requests"`
+	Codes    map[string]int `json:"status_codes"`
+	// Latencies, in seconds
+	Mean float64 `json:"mean_seconds"`
+	// The upper bound of the bucket carrying the 50th and 95th percentile latencies, or +Inf as the string "+Inf"
+	P50 string `json:"p50_le_seconds"`
+	P95 string `json:"p95_le_seconds"`
+}
+
+// Answers a summary of each method and resource, ordered by resource then method
+func (m *Metrics) Summary() []MetricsSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var summaries []MetricsSummary
+	for _, key := range m.keys() {
+		s := m.series[key]
+		codes := map[string]int{}
+		for code, n := range s.Codes {
+			codes[code] = n
+		}
+		summaries = append(summaries, MetricsSummary{
+			Method:   key.Method,
+			Resource: key.Resource,
+			Requests: s.Count,
+			Codes:    codes,
+			Mean:     s.Sum / float64(s.Count),
+			P50:      m.quantile(s, 0.5),
+			P95:      m.quantile(s, 0.95),
+		})
+	}
+	return summaries
+}
+
+// Answers the upper bound of the bucket carrying the quantile
+func (m *Metrics) quantile(s *series, q float64) string {
+	rank := q * float64(s.Count)
+	for i, le := range m.Buckets {
+		if float64(s.Counts[i]) >= rank {
+			return formatFloat(le)
+		}
+	}
+	return "+Inf"
+}
+
+func (m *Metrics) keys() []seriesKey {
+	keys := make([]seriesKey, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Resource != keys[j].Resource {
+			return keys[i].Resource < keys[j].Resource
+		}
+		return keys[i].Method < keys[j].Method
+	})
+	return keys
+}
+
+// Writes the metrics in the Prometheus text exposition format, e.g. for a Pushgateway or the node exporter's textfile
+// collector: the counter `idc_jsonapi_requests_total` labelled by method, resource and code, and the histogram
+// `idc_jsonapi_request_duration_seconds` labelled by method and resource
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := &strings.Builder{}
+	b.WriteString("# HELP idc_jsonapi_requests_total Requests sent to Drupal, by status code.\n")
+	b.WriteString("# TYPE idc_jsonapi_requests_total counter\n")
+	keys := m.keys()
+	for _, key := range keys {
+		s := m.series[key]
+		codes := make([]string, 0, len(s.Codes))
+		for code := range s.Codes {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(b, "idc_jsonapi_requests_total{%s,code=%q} %d\n", labels(key), code, s.Codes[code])
+		}
+	}
+	b.WriteString("# HELP idc_jsonapi_request_duration_seconds Latency of requests sent to Drupal.\n")
+	b.WriteString("# TYPE idc_jsonapi_request_duration_seconds histogram\n")
+	for _, key := range keys {
+		s := m.series[key]
+		for i, le := range m.Buckets {
+			fmt.Fprintf(b, "idc_jsonapi_request_duration_seconds_bucket{%s,le=%q} %d\n", labels(key), formatFloat(le),
+				s.Counts[i])
+		}
+		fmt.Fprintf(b, "idc_jsonapi_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels(key), s.Count)
+		fmt.Fprintf(b, "idc_jsonapi_request_duration_seconds_sum{%s} %s\n", labels(key), formatFloat(s.Sum))
+		fmt.Fprintf(b, "idc_jsonapi_request_duration_seconds_count{%s} %d\n", labels(key), s.Count)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func labels(key seriesKey) string {
+	return fmt.Sprintf("method=%q,resource=%q", key.Method, key.Resource)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Writes the Summary as an indented JSON array
+func (m *Metrics) WriteJson(w io.Writer) error {
+	summaries := m.Summary()
+	if summaries == nil {
+		summaries = []MetricsSummary{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summaries)
+}
+
+// Writes the metrics to the file, as a JSON summary if its name ends in `.json` and in the Prometheus text format
+// otherwise
+func (m *Metrics) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(path, ".json") {
+		err = m.WriteJson(f)
+	} else {
+		err = m.WritePrometheus(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Resource(t *testing.T) {
+	assert.Equal(t, "node--islandora_object", Resource("https://example.org/jsonapi/node/islandora_object?filter[title]=Moonrise"))
+	assert.Equal(t, "media--image", Resource("https://example.org/jsonapi/media/image/5e2ffa9f-0b0a-4b6b-9d7b-0e2d5c2c0f7e"))
+	assert.Equal(t, "/jsonapi", Resource("https://example.org/jsonapi"))
+	assert.Equal(t, "/user/login", Resource("https://example.org/user/login?_format=json"))
+}
+
+func Test_MetricsTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(404)
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	defer SetObserver(nil)
+
+	m := NewMetrics()
+	SetObserver(m)
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+	require.Nil(t, u.Fetch(&JsonApiResponse{}))
+	require.Nil(t, u.Fetch(&JsonApiResponse{}))
+	missing := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "missing"}
+	assert.NotNil(t, missing.Fetch(&JsonApiResponse{}))
+
+	summary := m.Summary()
+	require.Equal(t, 2, len(summary))
+	assert.Equal(t, "node--islandora_object", summary[0].Resource)
+	assert.Equal(t, http.MethodGet, summary[0].Method)
+	assert.Equal(t, 2, summary[0].Requests)
+	assert.Equal(t, map[string]int{"200": 2}, summary[0].Codes)
+	assert.Equal(t, "0.05", summary[0].P50)
+	assert.Equal(t, "node--missing", summary[1].Resource)
+	assert.Equal(t, map[string]int{"404": 1}, summary[1].Codes)
+
+	// requests are not observed once the observer is removed
+	SetObserver(nil)
+	require.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, 2, m.Summary()[0].Requests)
+}
+
+func Test_MetricsWrite(t *testing.T) {
+	m := &Metrics{Buckets: []float64{0.1, 1}}
+	url := "https://example.org/jsonapi/node/islandora_object"
+	m.Observe(RequestEvent{Method: "GET", Url: url, Status: 200, Duration: 50 * time.Millisecond})
+	m.Observe(RequestEvent{Method: "GET", Url: url, Status: 200, Duration: 500 * time.Millisecond})
+	m.Observe(RequestEvent{Method: "GET", Url: url, Duration: 2 * time.Second, Err: errors.New("timeout")})
+
+	b := &strings.Builder{}
+	require.Nil(t, m.WritePrometheus(b))
+	assert.Equal(t, `# HELP idc_jsonapi_requests_total Requests sent to Drupal, by status code.
+# TYPE idc_jsonapi_requests_total counter
+idc_jsonapi_requests_total{method="GET",resource="node--islandora_object",code="200"} 2
+idc_jsonapi_requests_total{method="GET",resource="node--islandora_object",code="error"} 1
+# HELP idc_jsonapi_request_duration_seconds Latency of requests sent to Drupal.
+# TYPE idc_jsonapi_request_duration_seconds histogram
+idc_jsonapi_request_duration_seconds_bucket{method="GET",resource="node--islandora_object",le="0.1"} 1
+idc_jsonapi_request_duration_seconds_bucket{method="GET",resource="node--islandora_object",le="1"} 2
+idc_jsonapi_request_duration_seconds_bucket{method="GET",resource="node--islandora_object",le="+Inf"} 3
+idc_jsonapi_request_duration_seconds_sum{method="GET",resource="node--islandora_object"} 2.55
+idc_jsonapi_request_duration_seconds_count{method="GET",resource="node--islandora_object"} 3
+`, b.String())
+
+	summary := m.Summary()
+	require.Equal(t, 1, len(summary))
+	assert.Equal(t, "1", summary[0].P50)
+	assert.Equal(t, "+Inf", summary[0].P95)
+	assert.InDelta(t, 0.85, summary[0].Mean, 0.001)
+
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	require.Nil(t, m.WriteFile(path))
+	content, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	var written []MetricsSummary
+	require.Nil(t, json.Unmarshal(content, &written))
+	assert.Equal(t, summary, written)
+
+	// an empty summary is written as an empty array
+	b.Reset()
+	require.Nil(t, NewMetrics().WriteJson(b))
+	assert.Equal(t, "[]\n", b.String())
+}
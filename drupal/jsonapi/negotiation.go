@@ -0,0 +1,66 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// The response is not JSON, e.g. an HTML error or maintenance page answered in place of a JSON API document
+var ErrNotJson = errors.New("response is not JSON")
+
+// The number of bytes of a response body quoted by the error answered by CheckJson
+const snippetSize = 200
+
+// Answers an error wrapping ErrNotJson, quoting the Content-Type of the response and the beginning of its body, unless
+// the response is JSON.  A response is JSON if its Content-Type is a JSON media type (e.g. `application/vnd.api+json`
+// or `application/json`), or if its body begins as a JSON object or array does, as a response lacking a JSON
+// Content-Type (e.g. `text/plain`) may nevertheless carry JSON.
+func CheckJson(res *http.Response, body []byte) error {
+	contentType := ""
+	if res != nil {
+		contentType = res.Header.Get("Content-Type")
+	}
+	if isJsonType(contentType) || looksLikeJson(body) {
+		return nil
+	}
+	u := ""
+	if res != nil && res.Request != nil {
+		u = " from " + res.Request.URL.String()
+	}
+	if contentType == "" {
+		contentType = "none"
+	}
+	return fmt.Errorf("%w: the response%s has Content-Type %s: %s", ErrNotJson, u, contentType, snippet(body))
+}
+
+// Answers whether the media type is JSON, e.g. `application/vnd.api+json` or `application/json; charset=utf-8`
+func isJsonType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Answers whether the body begins as a JSON object or array does
+func looksLikeJson(body []byte) bool {
+	trimmed := strings.TrimLeft(string(body), " \t\r\n\ufeff")
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+// Answers the beginning of the body with its whitespace collapsed, quoted, e.g. `"<!DOCTYPE html> <html> ..."`
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) <= snippetSize {
+		return fmt.Sprintf("%q", s)
+	}
+	s = s[:snippetSize]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return fmt.Sprintf("%q...", s)
+}
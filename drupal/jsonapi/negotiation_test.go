@@ -0,0 +1,89 @@
+//go:build !notestify
+// +build !notestify
+
+package jsonapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const maintenancePage = `<!DOCTYPE html>
+<html>
+  <head><title>Site under maintenance | IDC</title></head>
+  <body>IDC is currently under maintenance. We should be back shortly.</body>
+</html>`
+
+func Test_CheckJson(t *testing.T) {
+	for contentType, body := range map[string]string{
+		"application/vnd.api+json":        `{"data": []}`,
+		"application/json; charset=utf-8": `[]`,
+		"application/ld+json":             `{"@context": {}}`,
+		"text/plain; charset=utf-8":       "\n  {\"data\": []}",
+		"":                                `[{"nid": "1"}]`,
+	} {
+		res := &http.Response{Header: http.Header{"Content-Type": []string{contentType}}}
+		assert.Nil(t, CheckJson(res, []byte(body)), contentType)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://drupal/jsonapi/node/islandora_object", nil)
+	res := &http.Response{Request: req, Header: http.Header{"Content-Type": []string{"text/html; charset=UTF-8"}}}
+	err := CheckJson(res, []byte(maintenancePage))
+	require.True(t, errors.Is(err, ErrNotJson))
+	assert.Equal(t, `response is not JSON: the response from http://drupal/jsonapi/node/islandora_object has Content-Type `+
+		`text/html; charset=UTF-8: "<!DOCTYPE html> <html> <head><title>Site under maintenance | IDC</title></head> <body>IDC `+
+		`is currently under maintenance. We should be back shortly.</body> </html>"`, err.Error())
+
+	err = CheckJson(nil, []byte(strings.Repeat("é", snippetSize)))
+	require.True(t, errors.Is(err, ErrNotJson))
+	assert.Equal(t, "response is not JSON: the response has Content-Type none: \""+strings.Repeat("é", snippetSize/2)+"\"...", err.Error())
+}
+
+func Test_AcceptsJsonApi(t *testing.T) {
+	var accepted []string
+	html := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted = append(accepted, r.Header.Get("Accept"))
+		if html {
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+			w.Write([]byte(maintenancePage))
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "n1"}]}`))
+	}))
+	defer server.Close()
+	jar := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+
+	res := &JsonApiResponse{}
+	require.Nil(t, jar.Fetch(res))
+	assert.Equal(t, 1, len(res.Data))
+	require.Nil(t, jar.Stream(func(data JsonApiData) error { return nil }))
+	assert.Equal(t, []string{mediaType, mediaType}, accepted)
+
+	html = true
+	for name, fetch := range map[string]func() error{
+		"Fetch":      func() error { return jar.Fetch(res) },
+		"FetchPages": func() error { return jar.FetchPages(func(page *JsonApiPage) error { return nil }) },
+		"Stream":     func() error { return jar.Stream(func(data JsonApiData) error { return nil }) },
+		"FetchFile": func() error {
+			_, err := FetchFile(server.URL, "", "", "329c57a2-97f2-4350-8b54-439237c68311")
+			return err
+		},
+	} {
+		err := fetch()
+		require.True(t, errors.Is(err, ErrNotJson), "%s: %s", name, err)
+		assert.Contains(t, err.Error(), "Content-Type text/html; charset=UTF-8: \"<!DOCTYPE html>", name)
+	}
+
+	mock := &testing.T{}
+	jar.T = mock
+	jar.Get(res)
+	assert.True(t, mock.Failed())
+}
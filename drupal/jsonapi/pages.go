@@ -0,0 +1,76 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Encapsulates a single page of a JSON API collection, including any related resources requested using `include`
+type JsonApiPage struct {
+	// The 'data' elements of the page
+	Data []map[string]interface{} `json:"data"`
+	// The related resources of the 'data' elements, if any were requested
+	Included []map[string]interface{} `json:"included"`
+	Links    struct {
+		Next struct {
+			Href string
+		}
+	} `json:"links"`
+}
+
+// Retrieves each page of the collection matched by the JsonApiUrl, following the `next` link of each page until the
+// last page is retrieved, and invokes fn with each page in turn.  Relationships named by include (e.g.
+// `field_media_of`) are requested, and their resources are answered in JsonApiPage.Included.  Retrieval stops at the
+// first error, including any error answered by fn.
+func (jar *JsonApiUrl) FetchPages(fn func(page *JsonApiPage) error, include ...string) error {
+	next, err := jar.Url()
+	if err != nil {
+		return err
+	}
+	if len(include) > 0 {
+		next = withQuery(next, "include="+strings.Join(include, ","))
+	}
+
+	for next != "" {
+		res, body, err := FetchResource(next, jar.Username, jar.Password.Reveal())
+		if err != nil {
+			return err
+		}
+		if err := CheckJson(res, body); err != nil {
+			return err
+		}
+		page := &JsonApiPage{}
+		if err := json.Unmarshal(body, page); err != nil {
+			return fmt.Errorf("error unmarshaling JSONAPI response body from %s: %w", next, err)
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		next = page.Links.Next.Href
+	}
+	return nil
+}
+
+// Answers the included resource referenced by the supplied relationship data (an object carrying the `type` and `id`
+// of the related resource), or nil if the resource was not included
+func (p *JsonApiPage) Related(ref map[string]interface{}) map[string]interface{} {
+	if ref == nil {
+		return nil
+	}
+	for _, included := range p.Included {
+		if included["type"] == ref["type"] && included["id"] == ref["id"] {
+			return included
+		}
+	}
+	return nil
+}
+
+// Appends the query to the url, which may already carry a query
+func withQuery(u, query string) string {
+	if parsed, err := url.Parse(u); err == nil && parsed.RawQuery != "" {
+		return u + "&" + query
+	}
+	return u + "?" + query
+}
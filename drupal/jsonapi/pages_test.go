@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FetchPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "field_media_of", r.URL.Query().Get("include"))
+		switch r.URL.Query().Get("page[offset]") {
+		case "":
+			fmt.Fprintf(w, `{"data": [{"type": "media--image", "id": "m1", "relationships": {"field_media_of": {"data": {"type": "node--islandora_object", "id": "n1"}}}}],
+				"included": [{"type": "node--islandora_object", "id": "n1", "attributes": {"title": "Moonrise"}}],
+				"links": {"next": {"href": "%s/jsonapi/media/image?include=field_media_of&page[offset]=1"}}}`, server.URL)
+		default:
+			w.Write([]byte(`{"data": [{"type": "media--image", "id": "m2"}], "links": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "image"}
+	var ids []interface{}
+	var related []map[string]interface{}
+	err := u.FetchPages(func(page *JsonApiPage) error {
+		for _, d := range page.Data {
+			ids = append(ids, d["id"])
+			rel, _ := d["relationships"].(map[string]interface{})
+			mediaOf, _ := rel["field_media_of"].(map[string]interface{})
+			ref, _ := mediaOf["data"].(map[string]interface{})
+			related = append(related, page.Related(ref))
+		}
+		return nil
+	}, "field_media_of")
+	require.Nil(t, err)
+	assert.Equal(t, []interface{}{"m1", "m2"}, ids)
+	require.NotNil(t, related[0])
+	assert.Equal(t, "n1", related[0]["id"])
+	assert.Nil(t, related[1])
+
+	// errors answered by the callback stop retrieval
+	pages := 0
+	err = u.FetchPages(func(page *JsonApiPage) error {
+		pages++
+		return fmt.Errorf("stop")
+	}, "field_media_of")
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 1, pages)
+}
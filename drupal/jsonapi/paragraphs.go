@@ -0,0 +1,105 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// An included resource is not the revision referenced by its relationship
+var ErrStaleRevision = errors.New("included resource is not the referenced revision")
+
+// A reference carried by an entity reference revisions field, e.g. the reference of a node to one of its paragraphs.
+// Unlike other references, it identifies the revision of the referenced entity, in the meta of the relationship.
+type RevisionRef struct {
+	// The type of the referenced entity, e.g. `paragraph--text_block`
+	Type DrupalType
+	// The UUID of the referenced entity
+	Id string
+	// The revision referenced (`target_revision_id`), or 0 if the reference does not identify one
+	RevisionId int
+}
+
+// Answers the references of the named relationship along with the revisions they reference, in order.  Single-valued
+// and multi-valued relationships are both answered as a slice.
+func (d JsonApiData) RevisionRefs(name string) []RevisionRef {
+	data, _ := d.Lookup("relationships." + name + ".data")
+	var items []interface{}
+	switch data := data.(type) {
+	case map[string]interface{}:
+		items = []interface{}{data}
+	case []interface{}:
+		items = data
+	}
+	refs := make([]RevisionRef, 0, len(items))
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		item := JsonApiData(m)
+		ref := RevisionRef{Type: DrupalType(item.Type()), Id: item.Id()}
+		if v, ok := item.Lookup("meta.target_revision_id"); ok {
+			ref.RevisionId = toInt(v)
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Answers the revision id of the resource object (`drupal_internal__revision_id`), or 0 if it is not revisionable
+func (d JsonApiData) RevisionId() int {
+	return d.Int("drupal_internal__revision_id")
+}
+
+// Retrieves the single resource matched by the JsonApiUrl, along with the related resources named by include (e.g.
+// `field_sections` or `field_sections.field_items`), which are answered by the page.  Paragraphs are typically
+// retrieved this way, as Drupal may not expose them at their own JSON API endpoints.  An error is answered if the url
+// does not match exactly one resource.
+func (jar *JsonApiUrl) FetchIncluding(include ...string) (JsonApiData, *JsonApiPage, error) {
+	var result *JsonApiPage
+	err := jar.FetchPages(func(page *JsonApiPage) error {
+		if result != nil || len(page.Data) != 1 {
+			return fmt.Errorf("exactly one JSONAPI data element is expected in the response from %s, but found %d element(s)", jar, len(page.Data))
+		}
+		result = page
+		return nil
+	}, include...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result == nil {
+		return nil, nil, fmt.Errorf("no JSONAPI response was retrieved from %s", jar)
+	}
+	return JsonApiData(result.Data[0]), result, nil
+}
+
+// Answers the included resources referenced by the named entity reference revisions relationship of the resource
+// object, e.g. the paragraphs of a node, in the order referenced.  An error wrapping ErrStaleRevision is answered if an
+// included resource is not the revision its reference identifies; Drupal includes the default revision of a
+// referenced entity, which is not necessarily the revision its host references.  An error is also answered if a
+// referenced resource was not included.
+func (p *JsonApiPage) Revisions(d JsonApiData, name string) ([]JsonApiData, error) {
+	var resolved []JsonApiData
+	for _, ref := range d.RevisionRefs(name) {
+		included := JsonApiData(p.Related(map[string]interface{}{"type": string(ref.Type), "id": ref.Id}))
+		if included == nil {
+			return nil, fmt.Errorf("%s %s referenced by %s of %s %s was not included", ref.Type, ref.Id, name, d.Type(), d.Id())
+		}
+		if ref.RevisionId != 0 && included.RevisionId() != ref.RevisionId {
+			return nil, fmt.Errorf("%w: %s of %s %s references revision %d of %s %s, but revision %d was included",
+				ErrStaleRevision, name, d.Type(), d.Id(), ref.RevisionId, ref.Type, ref.Id, included.RevisionId())
+		}
+		resolved = append(resolved, included)
+	}
+	return resolved, nil
+}
+
+// Answers the number, or numeric string, as an int; 0 otherwise
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case float64:
+		return int(v)
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package jsonapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Adds a node whose `field_sections` references revision 7 of paragraph p1 and revision 3 of paragraph p2, which
+// nests paragraph p3 in its `field_items`
+func addParagraphs(m *jsonapitest.MockServer, p2Revision int) {
+	ref := func(bundle, id string, revision int) map[string]interface{} {
+		return map[string]interface{}{"type": "paragraph--" + bundle, "id": id,
+			"meta": map[string]interface{}{"target_revision_id": revision}}
+	}
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "n1",
+		"attributes": map[string]interface{}{"title": "Moonrise"},
+		"relationships": map[string]interface{}{"field_sections": map[string]interface{}{"data": []interface{}{
+			ref("text_block", "p1", 7), ref("gallery", "p2", 3)}}}},
+		jsonapitest.Resource{"type": "paragraph--text_block", "id": "p1",
+			"attributes": map[string]interface{}{"drupal_internal__revision_id": 7, "field_heading": "Moonrise"}},
+		jsonapitest.Resource{"type": "paragraph--gallery", "id": "p2",
+			"attributes": map[string]interface{}{"drupal_internal__revision_id": p2Revision},
+			"relationships": map[string]interface{}{"field_items": map[string]interface{}{"data": []interface{}{
+				ref("text_block", "p3", 9)}}}},
+		jsonapitest.Resource{"type": "paragraph--text_block", "id": "p3",
+			"attributes": map[string]interface{}{"drupal_internal__revision_id": 9, "field_heading": "Hernandez"}})
+}
+
+func Test_RevisionRefs(t *testing.T) {
+	d := JsonApiData{"relationships": map[string]interface{}{
+		"field_sections": map[string]interface{}{"data": []interface{}{
+			map[string]interface{}{"type": "paragraph--text_block", "id": "p1", "meta": map[string]interface{}{"target_revision_id": float64(7)}},
+			map[string]interface{}{"type": "paragraph--gallery", "id": "p2", "meta": map[string]interface{}{"target_revision_id": "3"}}}},
+		"field_lead": map[string]interface{}{"data": map[string]interface{}{"type": "paragraph--text_block", "id": "p3"}}}}
+
+	assert.Equal(t, []RevisionRef{{"paragraph--text_block", "p1", 7}, {"paragraph--gallery", "p2", 3}}, d.RevisionRefs("field_sections"))
+	assert.Equal(t, []RevisionRef{{"paragraph--text_block", "p3", 0}}, d.RevisionRefs("field_lead"))
+	assert.Empty(t, d.RevisionRefs("field_missing"))
+}
+
+func Test_FetchIncludingRevisions(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	addParagraphs(m, 3)
+	u := &JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise"}
+
+	node, page, err := u.FetchIncluding("field_sections", "field_sections.field_items")
+	require.Nil(t, err)
+	assert.Equal(t, "n1", node.Id())
+
+	sections, err := page.Revisions(node, "field_sections")
+	require.Nil(t, err)
+	require.Len(t, sections, 2)
+	assert.Equal(t, "p1", sections[0].Id())
+	assert.Equal(t, 7, sections[0].RevisionId())
+	assert.Equal(t, "p2", sections[1].Id())
+
+	items, err := page.Revisions(sections[1], "field_items")
+	require.Nil(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Hernandez", items[0].Attribute("field_heading"))
+
+	// the nested paragraphs were not included
+	_, page, err = u.FetchIncluding("field_sections")
+	require.Nil(t, err)
+	_, err = page.Revisions(sections[1], "field_items")
+	assert.EqualError(t, err, "paragraph--text_block p3 referenced by field_items of paragraph--gallery p2 was not included")
+
+	u.Value = "Moonset"
+	_, _, err = u.FetchIncluding("field_sections")
+	assert.Error(t, err)
+}
+
+func Test_RevisionsStale(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	// the default revision of p2 is newer than the revision the node references
+	addParagraphs(m, 4)
+	u := &JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise"}
+
+	node, page, err := u.FetchIncluding("field_sections")
+	require.Nil(t, err)
+	_, err = page.Revisions(node, "field_sections")
+	assert.True(t, errors.Is(err, ErrStaleRevision))
+	assert.Contains(t, err.Error(), "references revision 3 of paragraph--gallery p2, but revision 4 was included")
+}
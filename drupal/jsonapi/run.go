@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// The header carrying the id of the verification run on every request sent by the HTTP client of this package, so
+// that Drupal's logs may be correlated with the run that caused them
+const RunHeader = "X-IDC-Verify-Run"
+
+var (
+	runMu sync.RWMutex
+	runId = NewRunId()
+)
+
+// Answers a random (version 4) UUID suitable as a run id
+func NewRunId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Answers the id of the current verification run, generated once per process unless replaced by SetRunId
+func RunId() string {
+	runMu.RLock()
+	defer runMu.RUnlock()
+	return runId
+}
+
+// Replaces the id of the current verification run, e.g. with the id of a CI job.  An empty id generates a new one.
+func SetRunId(id string) {
+	if id == "" {
+		id = NewRunId()
+	}
+	runMu.Lock()
+	defer runMu.Unlock()
+	runId = id
+}
+
+// A transport that sets the RunHeader of each request that lacks one.  The HTTP client of this package is always
+// given a RunTransport; other clients may install one to identify their requests too.
+type RunTransport struct {
+	// The transport used to send requests; http.DefaultTransport if nil
+	Transport http.RoundTripper
+}
+
+// Sends a copy of the request carrying the RunHeader
+func (rt *RunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := rt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if req.Header.Get(RunHeader) != "" {
+		return transport.RoundTrip(req)
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set(RunHeader, RunId())
+	return transport.RoundTrip(r)
+}
+
+// Answers a shallow copy of the client whose transport is wrapped by a RunTransport (and a TraceTransport, so that the
+// RunHeader is traced, and a MetricsTransport), or the client if it has a RunTransport already
+func withRun(c *http.Client) *http.Client {
+	if _, ok := c.Transport.(*RunTransport); ok {
+		return c
+	}
+	copy := *c
+	copy.Transport = &RunTransport{Transport: &TraceTransport{Transport: &MetricsTransport{Transport: c.Transport}}}
+	return &copy
+}
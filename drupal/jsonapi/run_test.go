@@ -0,0 +1,49 @@
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunHeader(t *testing.T) {
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(RunHeader))
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	defer SetHTTPClient(nil)
+	defer SetRunId("")
+
+	assert.Regexp(t, uuidPattern, RunId())
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object"}
+	require.Nil(t, u.Fetch(&JsonApiResponse{}))
+
+	// a configured client, and a run id replaced by e.g. the id of a CI job
+	SetRunId("ci-1234")
+	rt := &recordingTransport{}
+	require.Nil(t, Configure(ClientConfig{Transport: rt}))
+	require.Nil(t, u.Fetch(&JsonApiResponse{}))
+	assert.Equal(t, 1, len(rt.requests))
+
+	// a header set by the caller is retained
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.Nil(t, err)
+	req.Header.Set(RunHeader, "explicit")
+	res, err := HTTPClient().Do(req)
+	require.Nil(t, err)
+	res.Body.Close()
+
+	require.Equal(t, 3, len(headers))
+	assert.Regexp(t, uuidPattern, headers[0])
+	assert.Equal(t, "ci-1234", headers[1])
+	assert.Equal(t, "explicit", headers[2])
+
+	SetRunId("")
+	assert.Regexp(t, uuidPattern, RunId())
+	assert.NotEqual(t, headers[0], RunId())
+}
@@ -0,0 +1,153 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// No entity of the searched entity type carries the requested title or name
+var ErrNoMatch = errors.New("no matching entity")
+
+// The bundles of each entity type searched by a Searcher, unless configured otherwise
+var SearchBundles = map[string][]string{
+	"node":  {"collection_object", "islandora_object"},
+	"media": MediaBundles,
+	"taxonomy_term": {"access_rights", "copyright_and_use", "corporate_body", "family", "genre", "geo_location",
+		"islandora_access", "language", "person", "resource_types", "subject"},
+}
+
+// The field carrying the label of the entities of each entity type, if not `name`
+var labelFields = map[string]string{
+	"node": "title",
+	"file": "filename",
+}
+
+// An entity found by a Searcher
+type Match struct {
+	// The type of the entity, e.g. `node--islandora_object`
+	Type DrupalType
+	// The resource object of the entity
+	Data JsonApiData
+}
+
+// Searches every bundle of an entity type for entities by title or name, for when an entity is known to be "some
+// node" or "some media" but its bundle is not known
+type Searcher struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The bundles of each entity type searched, in the order they are searched; those of Catalog if nil
+	Bundles map[string][]string
+	// The catalog of the resource types of the site (see Discover), whose bundles are searched unless Bundles is set;
+	// SearchBundles are searched if both are nil
+	Catalog *Catalog
+}
+
+// Creates a Searcher of the bundles in SearchBundles which queries the JSON API at the supplied base url.  If the
+// username is not empty, requests are authenticated using HTTP Basic Auth.
+func NewSearcher(baseUrl, username, password string) *Searcher {
+	return &Searcher{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the first entity of the entity type (e.g. `node`) whose label (the `title` of a node, the `filename` of a
+// file, or the `name` of any other entity) is the supplied label.  Bundles are searched in order, and searching stops
+// at the first bundle carrying a match.  An error wrapping ErrNoMatch is answered if no bundle carries a match.
+func (s *Searcher) Find(entity, label string) (Match, error) {
+	var match Match
+	err := s.search(entity, label, func(m Match) bool {
+		match = m
+		return false
+	})
+	if err == nil && match.Type == "" {
+		err = fmt.Errorf("%w: no %s is labeled '%s'", ErrNoMatch, entity, label)
+	}
+	return match, err
+}
+
+// Answers every entity of the entity type whose label is the supplied label (see Find), ordered by bundle.  No error
+// is answered if there are no matches.
+func (s *Searcher) FindAll(entity, label string) ([]Match, error) {
+	var matches []Match
+	err := s.search(entity, label, func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+	return matches, err
+}
+
+// Creates a Searcher of the bundles listed by the JSON API entry point of the site at the supplied base url (see
+// Discover), so that no list of bundles need be maintained
+func DiscoverSearcher(baseUrl, username, password string) (*Searcher, error) {
+	c, err := Discover(baseUrl, username, password)
+	if err != nil {
+		return nil, err
+	}
+	s := NewSearcher(baseUrl, username, password)
+	s.Catalog = c
+	return s, nil
+}
+
+// Answers the bundles of the entity type searched, or an error if there are none
+func (s *Searcher) bundles(entity string) ([]string, error) {
+	var bundles []string
+	switch {
+	case s.Bundles != nil:
+		bundles = s.Bundles[entity]
+	case s.Catalog != nil:
+		bundles = s.Catalog.Bundles(entity)
+	default:
+		bundles = SearchBundles[entity]
+	}
+	if len(bundles) == 0 {
+		return nil, fmt.Errorf("unable to search entity type '%s': its bundles are not known", entity)
+	}
+	return bundles, nil
+}
+
+// Invokes fn with each match in each bundle of the entity type, until fn answers false.  A bundle that does not exist
+// (i.e. whose collection answers a 404) is skipped.
+func (s *Searcher) search(entity, label string, fn func(m Match) bool) error {
+	bundles, err := s.bundles(entity)
+	if err != nil {
+		return err
+	}
+	field := labelFields[entity]
+	if field == "" {
+		field = "name"
+	}
+	for _, bundle := range bundles {
+		next, err := (&JsonApiUrl{BaseUrl: s.BaseUrl, DrupalEntity: entity, DrupalBundle: bundle, Filter: field,
+			Value: label}).Url()
+		if err != nil {
+			return err
+		}
+		for next != "" {
+			res, body, err := FetchResource(next, s.Username, s.Password.Reveal())
+			if res != nil && res.StatusCode == http.StatusNotFound {
+				break
+			}
+			if err == nil {
+				err = CheckJson(res, body)
+			}
+			if err != nil {
+				return fmt.Errorf("error searching %s--%s for '%s': %w", entity, bundle, label, err)
+			}
+			page := &JsonApiPage{}
+			if err := json.Unmarshal(body, page); err != nil {
+				return fmt.Errorf("error unmarshaling JSONAPI response body from %s: %w", next, err)
+			}
+			for _, d := range page.Data {
+				data := JsonApiData(d)
+				if !fn(Match{Type: DrupalType(data.Type()), Data: data}) {
+					return nil
+				}
+			}
+			next = page.Links.Next.Href
+		}
+	}
+	return nil
+}
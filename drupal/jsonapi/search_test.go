@@ -0,0 +1,79 @@
+package jsonapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Searcher(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{"title": "Moonrise"}},
+		jsonapitest.Resource{"type": "node--collection_object", "id": "c1", "attributes": map[string]interface{}{"title": "Moonrise"}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n2", "attributes": map[string]interface{}{"title": "Moonrise"}},
+		jsonapitest.Resource{"type": "media--image", "id": "m1", "attributes": map[string]interface{}{"name": "Moonrise"}},
+		jsonapitest.Resource{"type": "file--file", "id": "f1", "attributes": map[string]interface{}{"filename": "moonrise.jpg"}})
+	s := NewSearcher(m.URL, "", "")
+
+	match, err := s.Find("node", "Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, DrupalType("node--collection_object"), match.Type)
+	assert.Equal(t, "c1", match.Data.Id())
+
+	matches, err := s.FindAll("node", "Moonrise")
+	require.Nil(t, err)
+	var found []string
+	for _, m := range matches {
+		found = append(found, string(m.Type)+" "+m.Data.Id())
+	}
+	assert.Equal(t, []string{"node--collection_object c1", "node--islandora_object n1", "node--islandora_object n2"}, found)
+
+	match, err = s.Find("media", "Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, DrupalType("media--image"), match.Type)
+
+	_, err = s.Find("media", "Moonset")
+	assert.True(t, errors.Is(err, ErrNoMatch))
+	matches, err = s.FindAll("media", "Moonset")
+	assert.Nil(t, err)
+	assert.Empty(t, matches)
+
+	_, err = s.Find("file", "moonrise.jpg")
+	assert.EqualError(t, err, "unable to search entity type 'file': its bundles are not known")
+	s.Bundles = map[string][]string{"file": {"file"}}
+	match, err = s.Find("file", "moonrise.jpg")
+	require.Nil(t, err)
+	assert.Equal(t, "f1", match.Data.Id())
+}
+
+func Test_SearcherSkipsMissingBundles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/collection_object"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": [{"status": "404"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/islandora_object"):
+			w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "n1"}]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	s := NewSearcher(server.URL, "", "")
+
+	match, err := s.Find("node", "Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, DrupalType("node--islandora_object"), match.Type)
+
+	s.Bundles = map[string][]string{"media": {"image"}}
+	_, err = s.Find("media", "Moonrise")
+	assert.Contains(t, err.Error(), "error searching media--image for 'Moonrise': 500 status encountered")
+}
@@ -0,0 +1,162 @@
+package jsonapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Decodes a JSON API document from r without reading it into memory in its entirety: each element of its `data` array
+// is decoded in turn and passed to fn, so only one element is held in memory at a time.  A `data` object is passed to
+// fn as a single element, and a null `data` is not passed to fn at all.  The `next` link of the document is answered
+// (the empty string on the last page).  Decoding stops at the first error, including any error answered by fn.
+//
+// The `included` member and other members of the document are skipped without being retained.
+func DecodeData(r io.Reader, fn func(data JsonApiData) error) (next string, err error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+	found := false
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("error decoding JSONAPI response: %w", err)
+		}
+		switch t {
+		case "data":
+			found = true
+			if err := decodeData(dec, fn); err != nil {
+				return "", err
+			}
+		case "links":
+			links := struct {
+				Next struct {
+					Href string `json:"href"`
+				} `json:"next"`
+			}{}
+			if err := dec.Decode(&links); err != nil {
+				return "", fmt.Errorf("error decoding JSONAPI links: %w", err)
+			}
+			next = links.Next.Href
+		default:
+			if err := dec.Decode(&json.RawMessage{}); err != nil {
+				return "", fmt.Errorf("error decoding JSONAPI response: %w", err)
+			}
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("missing 'data' key when decoding JSONAPI response")
+	}
+	return next, nil
+}
+
+// Decodes the value of the `data` member, which may be an array, a single object, or null
+func decodeData(dec *json.Decoder, fn func(data JsonApiData) error) error {
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding JSONAPI key 'data': %w", err)
+	}
+	switch t {
+	case json.Delim('['):
+		for dec.More() {
+			data := JsonApiData{}
+			if err := dec.Decode(&data); err != nil {
+				return fmt.Errorf("error decoding JSONAPI data element: %w", err)
+			}
+			if err := fn(data); err != nil {
+				return err
+			}
+		}
+		return expectDelim(dec, ']')
+	case json.Delim('{'):
+		// the opening brace is consumed, so the members of the object are decoded one at a time
+		data := JsonApiData{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("error decoding JSONAPI data element: %w", err)
+			}
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return fmt.Errorf("error decoding JSONAPI data element: %w", err)
+			}
+			data[key.(string)] = v
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+		return fn(data)
+	case nil:
+		return nil
+	}
+	return fmt.Errorf("unable to determine type of JSONAPI key 'data': %v", t)
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding JSONAPI response: %w", err)
+	}
+	if t != delim {
+		return fmt.Errorf("error decoding JSONAPI response: expected '%s', found %v", delim, t)
+	}
+	return nil
+}
+
+// Requests the url and decodes the response body as it is read (see DecodeData), answering the `next` link of the
+// response.  An error is answered if the request cannot be executed, the HTTP status code is not 200, or the response
+// cannot be decoded.  If the supplied username is empty, then the request will be sent without an Authorization
+// header.
+func StreamResource(url, username, password string, fn func(data JsonApiData) error) (next string, err error) {
+	req, err := newRequest(url, username, password)
+	if err != nil {
+		return "", fmt.Errorf("encountered error creating request for %s: %w", url, err)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("encountered error requesting %s: %w", url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return "", fmt.Errorf("%d status encountered when requesting %s", res.StatusCode, url)
+	}
+	body, err := decodedBody(res)
+	if err != nil {
+		return "", fmt.Errorf("error decoding JSONAPI response body from %s: %w", url, err)
+	}
+	defer body.Close()
+	// peek at the beginning of the body, so that an HTML page is reported as such rather than as a decoding error
+	r := bufio.NewReader(body)
+	if !isJsonType(res.Header.Get("Content-Type")) {
+		peeked, _ := r.Peek(snippetSize)
+		if err := CheckJson(res, peeked); err != nil {
+			return "", err
+		}
+	}
+	if next, err = DecodeData(r, fn); err != nil {
+		return "", fmt.Errorf("error decoding JSONAPI response body from %s: %w", url, err)
+	}
+	return next, nil
+}
+
+// Streams each resource of the collection matched by the JsonApiUrl to fn, following the `next` link of each page
+// until the last page is retrieved.  Unlike FetchPages, no page is held in memory: resources are decoded as the
+// response is read, so that collections of thousands of entities may be verified in bounded memory.  Retrieval stops
+// at the first error, including any error answered by fn.
+func (jar *JsonApiUrl) Stream(fn func(data JsonApiData) error) error {
+	next, err := jar.Url()
+	if err != nil {
+		return err
+	}
+	for next != "" {
+		if next, err = StreamResource(next, jar.Username, jar.Password.Reveal(), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DecodeData(t *testing.T) {
+	var ids []string
+	collect := func(data JsonApiData) error {
+		ids = append(ids, data.Id())
+		return nil
+	}
+
+	next, err := DecodeData(strings.NewReader(`{"jsonapi": {"version": "1.0"}, "data": [
+		{"type": "node--islandora_object", "id": "n1", "attributes": {"title": "Moonrise", "field_weight": 2}},
+		{"type": "node--islandora_object", "id": "n2"}],
+		"included": [{"type": "taxonomy_term--genre", "id": "g1"}],
+		"links": {"next": {"href": "http://drupal/jsonapi/node/islandora_object?page[offset]=2"}}}`), collect)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, ids)
+	assert.Equal(t, "http://drupal/jsonapi/node/islandora_object?page[offset]=2", next)
+
+	ids = nil
+	var title string
+	next, err = DecodeData(strings.NewReader(`{"data": {"type": "node--islandora_object", "id": "n1", "attributes": {"title": "Moonrise"}}}`),
+		func(data JsonApiData) error {
+			title = data.String("title")
+			return collect(data)
+		})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"n1"}, ids)
+	assert.Equal(t, "Moonrise", title)
+	assert.Equal(t, "", next)
+
+	ids = nil
+	_, err = DecodeData(strings.NewReader(`{"data": null}`), collect)
+	require.Nil(t, err)
+	assert.Nil(t, ids)
+
+	// decoding stops at the first error answered by the callback
+	stop := errors.New("stop")
+	_, err = DecodeData(strings.NewReader(`{"data": [{"id": "n1"}, {"id": "n2"}]}`), func(data JsonApiData) error { return stop })
+	assert.ErrorIs(t, err, stop)
+
+	for _, doc := range []string{`{"links": {}}`, `[]`, `{"data": "n1"}`, `{"data": [{"id": "n1"}`} {
+		_, err = DecodeData(strings.NewReader(doc), collect)
+		assert.NotNil(t, err, doc)
+	}
+}
+
+func Test_Stream(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page[offset]") {
+		case "":
+			fmt.Fprintf(w, `{"data": [{"type": "media--image", "id": "m1"}, {"type": "media--image", "id": "m2"}],
+				"links": {"next": {"href": "%s/jsonapi/media/image?page[offset]=2"}}}`, server.URL)
+		case "2":
+			w.Write([]byte(`{"data": [{"type": "media--image", "id": "m3"}], "links": {}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "image"}
+	var ids []string
+	err := u.Stream(func(data JsonApiData) error {
+		ids = append(ids, data.Id())
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, ids)
+
+	_, err = StreamResource(server.URL+"/jsonapi/media/image?page[offset]=4", "", "", func(data JsonApiData) error { return nil })
+	assert.NotNil(t, err)
+}
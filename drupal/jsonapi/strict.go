@@ -0,0 +1,170 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSON decoded strictly carried keys unknown to the value decoded, or did not populate all of its fields
+var ErrStrict = errors.New("jsonapi: strict decoding failed")
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Decodes the JSON into the value (which must be a pointer) as json.Unmarshal does, but strictly, so that a typo
+// (e.g. `acess_rights`) is not silently ignored.  Keys of a JSON object matching no field of its struct (see
+// json.Decoder.DisallowUnknownFields), and fields of a struct populated by no key (other than fields tagged
+// `omitempty`), are answered as an error wrapping ErrStrict listing each, e.g.:
+//
+//	jsonapi: strict decoding failed:
+//	  $.data[0].attributes.field_acess_rights: unknown field
+//	  $.data[0].attributes.field_access_rights: never populated
+//
+// Keys match fields as they do for encoding/json, preferring an exact match to a match ignoring case.  The keys named
+// by allowed are permitted in the top-level object in addition to the fields of the value.  Values of types that
+// unmarshal themselves (e.g. model.Link) are not examined, unless the value decoded is itself such a type.
+func DecodeStrict(b []byte, v interface{}, allowed ...string) error {
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	// the decoder would reject the allowed keys, which are checked below along with every other key
+	if len(allowed) == 0 {
+		d.DisallowUnknownFields()
+	}
+	err := d.Decode(v)
+
+	// the decoder answers the first unknown key only, so each key is checked to report every violation
+	var violations []string
+	checkStrict("$", reflect.TypeOf(v), generic, true, allowed, &violations)
+	if len(violations) > 0 {
+		return fmt.Errorf("%w:\n  %s", ErrStrict, strings.Join(violations, "\n  "))
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrStrict, err)
+	}
+	return nil
+}
+
+// Adapts the generic JsonApiResponse to a higher-fidelity type as Decode does, but strictly (see DecodeStrict)
+func (jar *JsonApiResponse) DecodeStrict(v interface{}) error {
+	b, err := json.Marshal(jar)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %v as json: %w", jar, err)
+	}
+	return DecodeStrict(b, v)
+}
+
+// A field of the JSON object of a struct
+type strictField struct {
+	name      string
+	omitempty bool
+	t         reflect.Type
+}
+
+// Appends the violations of strict decoding of the generic JSON value at the path into the type.  The keys named by
+// allowed are permitted in the object of the value.
+func checkStrict(path string, t reflect.Type, v interface{}, root bool, allowed []string, violations *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !root && (t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)) {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		switch t.Kind() {
+		case reflect.Map:
+			for _, k := range keys {
+				checkStrict(path+"."+k, t.Elem(), v[k], false, nil, violations)
+			}
+		case reflect.Struct:
+			fields := strictFields(t, nil)
+			populated := make([]bool, len(fields))
+			for _, k := range keys {
+				i := matchField(fields, k)
+				if i < 0 {
+					if !contains(allowed, k) {
+						*violations = append(*violations, fmt.Sprintf("%s.%s: unknown field", path, k))
+					}
+					continue
+				}
+				populated[i] = true
+				checkStrict(path+"."+k, fields[i].t, v[k], false, nil, violations)
+			}
+			for i, f := range fields {
+				if !populated[i] && !f.omitempty {
+					*violations = append(*violations, fmt.Sprintf("%s.%s: never populated", path, f.name))
+				}
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				checkStrict(fmt.Sprintf("%s[%d]", path, i), t.Elem(), item, false, nil, violations)
+			}
+		}
+	}
+}
+
+// Appends the fields of the JSON object of the struct type, including those of embedded structs
+func strictFields(t reflect.Type, fields []strictField) []strictField {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && options[0] == "" && ft.Kind() == reflect.Struct {
+			fields = strictFields(ft, fields)
+			continue
+		}
+		field := strictField{name: options[0], t: f.Type}
+		if field.name == "" {
+			field.name = f.Name
+		}
+		for _, o := range options[1:] {
+			field.omitempty = field.omitempty || o == "omitempty"
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Answers the index of the field the key populates, preferring an exact match to a match ignoring case, or -1
+func matchField(fields []strictField, key string) int {
+	match := -1
+	for i, f := range fields {
+		if f.name == key {
+			return i
+		}
+		if match < 0 && strings.EqualFold(f.name, key) {
+			match = i
+		}
+	}
+	return match
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+//go:build !notestify
+// +build !notestify
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictTerm struct {
+	Data []struct {
+		Type       DrupalType
+		Id         string
+		Attributes struct {
+			Name         string   `json:"name"`
+			AccessRights []string `json:"field_access_rights"`
+			Weight       int      `json:"weight,omitempty"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+func Test_DecodeStrict(t *testing.T) {
+	term := &strictTerm{}
+	require.Nil(t, DecodeStrict([]byte(`{"data": [{"type": "taxonomy_term--subject", "ID": "t1",
+		"attributes": {"name": "Photography", "field_access_rights": ["Public"]}}]}`), term))
+	assert.Equal(t, "t1", term.Data[0].Id)
+	assert.Equal(t, []string{"Public"}, term.Data[0].Attributes.AccessRights)
+
+	err := DecodeStrict([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "t1",
+		"attributes": {"name": "Photography", "field_acess_rights": ["Public"]}}], "links": {}}`), &strictTerm{})
+	require.True(t, errors.Is(err, ErrStrict))
+	assert.Equal(t, `jsonapi: strict decoding failed:
+  $.data[0].attributes.field_acess_rights: unknown field
+  $.data[0].attributes.field_access_rights: never populated
+  $.links: unknown field`, err.Error())
+
+	assert.Nil(t, DecodeStrict([]byte(`{"data": [], "links": {}}`), &strictTerm{}, "links"))
+	assert.True(t, errors.Is(DecodeStrict([]byte(`{"data": "t1"}`), &strictTerm{}), ErrStrict))
+	assert.NotNil(t, DecodeStrict([]byte(`{"data": [`), &strictTerm{}))
+
+	// the response is lenient unless decoded strictly
+	res := &JsonApiResponse{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": {"type": "taxonomy_term--subject", "id": "t1",
+		"attributes": {"name": "Photography", "drupal_internal__tid": 7, "field_access_rights": []}}}`), res))
+	assert.Nil(t, res.Decode(&strictTerm{}))
+	assert.Contains(t, res.DecodeStrict(&strictTerm{}).Error(), "$.data[0].attributes.drupal_internal__tid: unknown field")
+}
+
+func Test_JsonApiUrlStrict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "t1", "attributes": {"name": "Photography"}}]}`))
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "taxonomy_term", DrupalBundle: "subject"}
+	assert.Nil(t, u.FetchSingle(&strictTerm{}))
+	u.Strict = true
+	err := u.FetchSingle(&strictTerm{})
+	assert.True(t, errors.Is(err, ErrStrict))
+	assert.Contains(t, err.Error(), "$.data[0].attributes.field_access_rights: never populated")
+
+	u.T = &testing.T{}
+	u.GetSingle(&strictTerm{})
+	assert.True(t, u.T.(*testing.T).Failed())
+}
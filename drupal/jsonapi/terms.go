@@ -0,0 +1,148 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+var (
+	// No taxonomy term with the requested name exists in the vocabulary
+	ErrTermNotFound = errors.New("taxonomy term not found")
+	// More than one taxonomy term with the requested name exists in the vocabulary
+	ErrAmbiguousTerm = errors.New("ambiguous taxonomy term")
+)
+
+// Answers the UUIDs of taxonomy terms by vocabulary and name.  A TermResolver is safe for concurrent use, and is
+// intended to be shared by every worker or test in a run:
+//   - successful lookups are cached, as are lookups that found no term or more than one term
+//   - concurrent lookups of the same term result in a single request to Drupal
+//   - lookups that fail for other reasons (e.g. a 5xx from Drupal) are not cached, and will be retried
+type TermResolver struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The maximum number of lookups cached; once exceeded, the earliest lookups are forgotten.  Unbounded if not
+	// positive.
+	MaxEntries int
+
+	mu    sync.Mutex
+	terms map[termKey]*termLookup
+	// the keys of terms, in the order they were looked up
+	order []termKey
+}
+
+type termKey struct {
+	langcode   string
+	vocabulary string
+	name       string
+}
+
+// A lookup of a single term, which may still be in progress
+type termLookup struct {
+	// closed once the lookup has completed
+	done chan struct{}
+	id   string
+	err  error
+}
+
+// Creates a TermResolver which queries the JSON API at the supplied base url.  If the username is not empty, requests
+// are authenticated using HTTP Basic Auth.
+func NewTermResolver(baseUrl, username, password string) *TermResolver {
+	return &TermResolver{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the UUID of the term with the supplied name in the vocabulary (e.g. `subject` or `genre`).  An error
+// wrapping ErrTermNotFound or ErrAmbiguousTerm is answered if zero or more than one term carries the name.
+func (r *TermResolver) Resolve(vocabulary, name string) (string, error) {
+	return r.ResolveTranslation("", vocabulary, name)
+}
+
+// Answers the UUID of the term whose name, translated into the language identified by langcode (e.g. `es`), is the
+// supplied name.  An empty langcode resolves names in the site default language, exactly like Resolve.
+func (r *TermResolver) ResolveTranslation(langcode, vocabulary, name string) (string, error) {
+	key := termKey{langcode, vocabulary, name}
+
+	r.mu.Lock()
+	if r.terms == nil {
+		r.terms = map[termKey]*termLookup{}
+	}
+	if lookup, ok := r.terms[key]; ok {
+		r.mu.Unlock()
+		<-lookup.done
+		return lookup.id, lookup.err
+	}
+	lookup := &termLookup{done: make(chan struct{})}
+	r.terms[key] = lookup
+	r.order = append(r.order, key)
+	r.evict()
+	r.mu.Unlock()
+
+	lookup.id, lookup.err = r.lookup(langcode, vocabulary, name)
+	if lookup.err != nil && !errors.Is(lookup.err, ErrTermNotFound) && !errors.Is(lookup.err, ErrAmbiguousTerm) {
+		// transient failure: forget the lookup so that it may be retried
+		r.mu.Lock()
+		if r.terms[key] == lookup {
+			delete(r.terms, key)
+		}
+		r.mu.Unlock()
+	}
+	close(lookup.done)
+	return lookup.id, lookup.err
+}
+
+// Removes every cached lookup, e.g. after terms have been created or deleted
+func (r *TermResolver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.terms, r.order = nil, nil
+}
+
+// Answers the number of cached lookups
+func (r *TermResolver) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.terms)
+}
+
+// Forgets the earliest lookups until no more than MaxEntries are cached.  Callers of a forgotten lookup that is still
+// in progress receive its outcome regardless.  The caller must hold r.mu.
+func (r *TermResolver) evict() {
+	for r.MaxEntries > 0 && len(r.terms) > r.MaxEntries && len(r.order) > 0 {
+		delete(r.terms, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+func (r *TermResolver) lookup(langcode, vocabulary, name string) (string, error) {
+	u := &JsonApiUrl{
+		BaseUrl:      r.BaseUrl,
+		DrupalEntity: "taxonomy_term",
+		DrupalBundle: vocabulary,
+		Filter:       "name",
+		Value:        name,
+		Username:     r.Username,
+		Password:     r.Password,
+		Langcode:     langcode,
+	}
+	res := &JsonApiResponse{}
+	if err := u.Fetch(res); err != nil {
+		return "", err
+	}
+
+	switch len(res.Data) {
+	case 0:
+		return "", fmt.Errorf("%w: '%s' in vocabulary '%s'", ErrTermNotFound, name, vocabulary)
+	case 1:
+		id, _ := res.Data[0]["id"].(string)
+		return id, nil
+	default:
+		var ids []interface{}
+		for _, d := range res.Data {
+			ids = append(ids, d["id"])
+		}
+		return "", fmt.Errorf("%w: '%s' in vocabulary '%s' matches %d terms: %v", ErrAmbiguousTerm, name, vocabulary, len(ids), ids)
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !notestify
+// +build !notestify
+
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TermResolver(t *testing.T) {
+	var requests int32
+	var failing int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Query().Get("filter[name]") {
+		case "Analog Photography":
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "s1"}]}`))
+		case "Maps":
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "s2"}, {"type": "taxonomy_term--subject", "id": "s3"}]}`))
+		case "Flaky":
+			if atomic.CompareAndSwapInt32(&failing, 1, 0) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "s4"}]}`))
+		default:
+			w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+
+	r := NewTermResolver(server.URL, "", "")
+
+	// concurrent lookups of the same term result in a single request
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := r.Resolve("subject", "Analog Photography")
+			assert.Nil(t, err)
+			assert.Equal(t, "s1", id)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// negative results are cached
+	_, err := r.Resolve("subject", "Missing")
+	assert.ErrorIs(t, err, ErrTermNotFound)
+	_, err = r.Resolve("subject", "Missing")
+	assert.ErrorIs(t, err, ErrTermNotFound)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// ambiguous results are detected and cached
+	_, err = r.Resolve("subject", "Maps")
+	assert.ErrorIs(t, err, ErrAmbiguousTerm)
+	_, err = r.Resolve("subject", "Maps")
+	assert.ErrorIs(t, err, ErrAmbiguousTerm)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// transient failures are retried
+	_, err = r.Resolve("subject", "Flaky")
+	require.NotNil(t, err)
+	id, err := r.Resolve("subject", "Flaky")
+	assert.Nil(t, err)
+	assert.Equal(t, "s4", id)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+
+	// the cache may be reset
+	r.Reset()
+	assert.Equal(t, "s1", r.MustResolve(t, "subject", "Analog Photography"))
+	assert.Equal(t, int32(6), atomic.LoadInt32(&requests))
+
+	// the earliest lookups are forgotten once MaxEntries are cached
+	r.MaxEntries = 2
+	_, _ = r.Resolve("subject", "Maps")
+	_, _ = r.Resolve("subject", "Missing")
+	assert.Equal(t, 2, r.Len())
+	assert.Equal(t, int32(8), atomic.LoadInt32(&requests))
+	_, _ = r.Resolve("subject", "Missing")
+	assert.Equal(t, int32(8), atomic.LoadInt32(&requests))
+	assert.Equal(t, "s1", r.MustResolve(t, "subject", "Analog Photography"))
+	assert.Equal(t, int32(9), atomic.LoadInt32(&requests))
+}
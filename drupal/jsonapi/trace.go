@@ -0,0 +1,159 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+)
+
+// The headers whose values are replaced by TraceTransport, so that credentials do not appear in logs
+var RedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Csrf-Token"}
+
+// The fields whose values are replaced by TraceTransport in form-encoded bodies (e.g. the Drupal login form and OAuth
+// token requests) and in JSON bodies (e.g. OAuth token responses), so that credentials do not appear in logs
+var RedactedFields = []string{"pass", "password", "client_secret", "access_token", "refresh_token"}
+
+// The number of bytes of each request and response body logged by TraceTransport
+var TraceBodyLimit = 4096
+
+// A transport that logs each request and response at logging.Debug: the method and url, the headers (with the values
+// of RedactedHeaders replaced), and the beginning of the body (with the values of RedactedFields replaced).  Nothing
+// is logged, and bodies are not buffered, unless the current logger enables logging.Debug.  The HTTP client of this
+// package is always given a TraceTransport.
+type TraceTransport struct {
+	// The transport used to send requests; http.DefaultTransport if nil
+	Transport http.RoundTripper
+}
+
+func (tt *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := tt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if !logging.Enabled(logging.Debug) {
+		return transport.RoundTrip(req)
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "> %s %s\n", req.Method, req.URL)
+	writeHeaders(b, "> ", req.Header)
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			prefix, _ := ioutil.ReadAll(io.LimitReader(body, int64(TraceBodyLimit)))
+			_ = body.Close()
+			writeBody(b, prefix, req.Header)
+		}
+	}
+	logging.Debugf("HTTP request [run %s]\n%s", req.Header.Get(RunHeader), b)
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		logging.Debugf("HTTP request to %s failed: %s", req.URL, err)
+		return res, err
+	}
+	b.Reset()
+	fmt.Fprintf(b, "< %s %s\n", res.Proto, res.Status)
+	writeHeaders(b, "< ", res.Header)
+	if res.Body != nil {
+		prefix, _ := ioutil.ReadAll(io.LimitReader(res.Body, int64(TraceBodyLimit)))
+		res.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), res.Body), Closer: res.Body}
+		writeBody(b, prefix, res.Header)
+	}
+	logging.Debugf("HTTP response from %s\n%s", req.URL, b)
+	return res, nil
+}
+
+// A response body whose beginning was read for logging
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+func writeHeaders(b *strings.Builder, prefix string, h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		for _, redacted := range RedactedHeaders {
+			if strings.EqualFold(name, redacted) {
+				value = "[redacted]"
+			}
+		}
+		fmt.Fprintf(b, "%s%s: %s\n", prefix, name, value)
+	}
+}
+
+func writeBody(b *strings.Builder, prefix []byte, h http.Header) {
+	switch {
+	case len(prefix) == 0:
+	case h.Get("Content-Encoding") != "":
+		fmt.Fprintf(b, "(%s-encoded body not shown)\n", h.Get("Content-Encoding"))
+	default:
+		b.Write(redactBody(prefix, h.Get("Content-Type")))
+		if len(prefix) == TraceBodyLimit {
+			b.WriteString("…")
+		}
+		b.WriteString("\n")
+	}
+}
+
+// Answers the body with the values of RedactedFields replaced, if it is form-encoded or JSON (whatever its declared
+// content type).  A body truncated by TraceBodyLimit is redacted as far as it can be parsed; a JSON body which cannot
+// be parsed is not shown.
+func redactBody(body []byte, contentType string) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		pairs := strings.Split(string(body), "&")
+		for i, pair := range pairs {
+			name := strings.SplitN(pair, "=", 2)[0]
+			if unescaped, err := url.QueryUnescape(name); err == nil && name != pair && redactedField(unescaped) {
+				pairs[i] = name + "=[redacted]"
+			}
+		}
+		return []byte(strings.Join(pairs, "&"))
+	case isJsonType(contentType) || looksLikeJson(body):
+		redacted := false
+		for _, field := range RedactedFields {
+			redacted = redacted || bytes.Contains(body, []byte(`"`+field+`"`))
+		}
+		if !redacted {
+			return body
+		}
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return []byte("(body carrying credentials not shown)")
+		}
+		for name := range doc {
+			if redactedField(name) {
+				doc[name] = "[redacted]"
+			}
+		}
+		if b, err := json.Marshal(doc); err == nil {
+			return b
+		}
+		return []byte("(body carrying credentials not shown)")
+	}
+	return body
+}
+
+func redactedField(name string) bool {
+	for _, field := range RedactedFields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package jsonapi
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TraceTransport(t *testing.T) {
+	doc := `{"data": [{"type": "node--islandora_object", "id": "n1"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "SESS1", Value: "secret"})
+		w.Write([]byte(doc))
+	}))
+	defer server.Close()
+
+	defer logging.SetLogger(nil)
+	buf := &bytes.Buffer{}
+	logging.SetLogger(&logging.StdLogger{Out: log.New(buf, "", 0), Level: logging.Debug})
+
+	_, body, err := FetchResource(server.URL+"/jsonapi/node/islandora_object", "admin", "password")
+	require.Nil(t, err)
+	assert.Equal(t, doc, string(body))
+	logged := buf.String()
+	assert.Contains(t, logged, "> GET "+server.URL+"/jsonapi/node/islandora_object\n")
+	assert.Contains(t, logged, "> Authorization: [redacted]\n")
+	assert.Contains(t, logged, "> "+http.CanonicalHeaderKey(RunHeader)+": "+RunId()+"\n")
+	assert.Contains(t, logged, "< HTTP/1.1 200 OK\n")
+	assert.Contains(t, logged, "< Set-Cookie: [redacted]\n")
+	assert.Contains(t, logged, doc+"\n")
+	assert.NotContains(t, logged, "secret")
+	assert.NotContains(t, logged, "password")
+
+	// nothing is traced unless debug is enabled
+	buf.Reset()
+	logging.SetLogger(&logging.StdLogger{Out: log.New(buf, "", 0), Level: logging.Info})
+	_, _, err = FetchResource(server.URL, "", "")
+	require.Nil(t, err)
+	assert.False(t, strings.Contains(buf.String(), "HTTP"), buf.String())
+	assert.Contains(t, buf.String(), "INFO Retrieving "+server.URL)
+}
+
+func Test_TraceTransportRedactsCredentials(t *testing.T) {
+	server := newOAuthServer()
+	defer server.Close()
+	defer logging.SetLogger(nil)
+	buf := &bytes.Buffer{}
+	logging.SetLogger(&logging.StdLogger{Out: log.New(buf, "", 0), Level: logging.Debug})
+
+	// an OAuth token request and response
+	o := NewPasswordGrant(server.URL, "idc", "secret", "admin", "moonrise")
+	o.Client = withRun(&http.Client{})
+	_, err := o.Token()
+	require.Nil(t, err)
+	// a Drupal login form
+	res, err := HTTPClient().PostForm(server.URL+"/user/login", url.Values{"name": {"admin"}, "pass": {"moonrise"}, "form_id": {"user_login_form"}})
+	require.Nil(t, err)
+	res.Body.Close()
+
+	logged := buf.String()
+	assert.Contains(t, logged, "client_id=idc&client_secret=[redacted]&grant_type=password&password=[redacted]&username=admin\n")
+	assert.Contains(t, logged, `"access_token":"[redacted]"`)
+	assert.Contains(t, logged, "form_id=user_login_form&name=admin&pass=[redacted]\n")
+	assert.NotContains(t, logged, "moonrise")
+	assert.NotContains(t, logged, "=secret")
+	assert.NotContains(t, logged, "access-1")
+	assert.NotContains(t, logged, "refresh-1")
+}
+
+func Test_RedactBody(t *testing.T) {
+	assert.Equal(t, "name=admin&pa%73s=[redacted]&pass", string(redactBody([]byte("name=admin&pa%73s=moonrise&pass"), "application/x-www-form-urlencoded; charset=UTF-8")))
+	assert.Equal(t, `{"data": []}`, string(redactBody([]byte(`{"data": []}`), "application/vnd.api+json")))
+	assert.Equal(t, "(body carrying credentials not shown)", string(redactBody([]byte(`{"access_token": "eyJ0e`), "application/json")))
+	assert.Equal(t, "pass=moonrise", string(redactBody([]byte("pass=moonrise"), "text/plain")))
+}
@@ -0,0 +1,71 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+)
+
+// The media type of JSON API documents
+const mediaType = "application/vnd.api+json"
+
+// CreateResource creates a resource by POSTing the JSON API document (e.g. `{"data": {"type": "taxonomy_term--genre",
+// "attributes": {"name": "Maps"}}}`) to the url of its collection, e.g.
+// `https://islandora-idc.traefik.me/jsonapi/taxonomy_term/genre`.  The body of the response, which carries the created
+// resource, is answered.  An error is answered if the HTTP status code is not 201.  Drupal must permit JSON API
+// write operations, and the user must be permitted to create the resource.
+func CreateResource(url, username, password string, doc interface{}) ([]byte, error) {
+	logging.Infof("Creating resource at %s [run %s]", url, RunId())
+	return write(http.MethodPost, url, username, password, doc, http.StatusCreated)
+}
+
+// UpdateResource updates a resource by PATCHing the JSON API document (e.g. `{"data": {"type": "node--islandora_object",
+// "id": "...", "relationships": {"field_member_of": {"data": [...]}}}}`) to the url of the resource, e.g.
+// `https://islandora-idc.traefik.me/jsonapi/node/islandora_object/{id}`.  Only the attributes and relationships
+// carried by the document are changed.  The body of the response, which carries the updated resource, is answered.
+// An error is answered if the HTTP status code is not 200.
+func UpdateResource(url, username, password string, doc interface{}) ([]byte, error) {
+	logging.Infof("Updating resource at %s [run %s]", url, RunId())
+	return write(http.MethodPatch, url, username, password, doc, http.StatusOK)
+}
+
+// Sends the JSON API document to the url using the method, answering the body of the response, or an error if its
+// status is not the expected status
+func write(method, url, username, password string, doc interface{}, status int) ([]byte, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling JSONAPI document for %s: %w", url, err)
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("encountered error creating request for %s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("Accept", mediaType)
+	if len(strings.TrimSpace(username)) > 0 {
+		req.SetBasicAuth(username, password)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error requesting %s: %w", url, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error encountered reading response body from %s: %w", url, err)
+	}
+	if res.StatusCode != status {
+		verb := "creating a resource at"
+		if method == http.MethodPatch {
+			verb = "updating the resource"
+		}
+		return body, fmt.Errorf("%d status encountered when %s %s: %s", res.StatusCode, verb, url, body)
+	}
+	return body, nil
+}
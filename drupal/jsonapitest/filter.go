@@ -0,0 +1,382 @@
+package jsonapitest
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The implicit group that conditions and groups lacking a memberOf belong to
+const rootGroup = "@root"
+
+// A filter condition, e.g. from `filter[title]=Moonrise` or `filter[label][condition][path]=title&...`
+type condition struct {
+	path     string
+	operator string
+	values   []string
+	memberOf string
+}
+
+// A filter group, e.g. from `filter[or-group][group][conjunction]=OR`
+type group struct {
+	conjunction string
+	memberOf    string
+}
+
+// A parsed JSON API filter
+type filter struct {
+	conditions map[string]*condition
+	groups     map[string]*group
+}
+
+// Parses the `filter[...]` parameters of the query
+func parseFilter(q url.Values) (*filter, error) {
+	f := &filter{conditions: map[string]*condition{}, groups: map[string]*group{rootGroup: {conjunction: "AND"}}}
+
+	// sorted, so that array values (e.g. `[value][0]`, `[value][1]`) are appended in order
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "filter[") {
+			continue
+		}
+		segs, err := brackets(strings.TrimPrefix(k, "filter"))
+		if err != nil {
+			return nil, err
+		}
+		v := q.Get(k)
+		label := segs[0]
+
+		switch {
+		case len(segs) == 1:
+			// filter[field]=value
+			f.condition(label).values = append(f.condition(label).values, v)
+		case segs[1] == "group":
+			g, ok := f.groups[label]
+			if !ok {
+				g = &group{conjunction: "AND"}
+				f.groups[label] = g
+			}
+			if len(segs) == 3 && segs[2] == "conjunction" {
+				g.conjunction = strings.ToUpper(v)
+			} else if len(segs) == 3 && segs[2] == "memberOf" {
+				g.memberOf = v
+			}
+		case segs[1] == "condition" && len(segs) >= 3:
+			if err := f.condition(label).set(segs[2:], q[k]); err != nil {
+				return nil, fmt.Errorf("invalid filter parameter %s: %w", k, err)
+			}
+		default:
+			// filter[field][value]=value, filter[field][operator]=...
+			if err := f.condition(label).set(segs[1:], q[k]); err != nil {
+				return nil, fmt.Errorf("invalid filter parameter %s: %w", k, err)
+			}
+		}
+	}
+
+	for label, c := range f.conditions {
+		if c.path == "" {
+			c.path = label
+		}
+		if _, ok := f.groups[c.memberOf]; !ok {
+			return nil, fmt.Errorf("filter condition %s is a member of an unknown group %s", label, c.memberOf)
+		}
+	}
+	return f, nil
+}
+
+// Answers the condition with the label, creating it if necessary.  Conditions lacking an explicit path (e.g. the
+// shorthand `filter[title]=...`) are given their label as their path once the filter is parsed.
+func (f *filter) condition(label string) *condition {
+	c, ok := f.conditions[label]
+	if !ok {
+		c = &condition{operator: "=", memberOf: rootGroup}
+		f.conditions[label] = c
+	}
+	return c
+}
+
+// Sets a member of the condition, e.g. [operator], [path], [memberOf], [value], or [value][]
+func (c *condition) set(segs []string, values []string) error {
+	switch segs[0] {
+	case "path":
+		c.path = values[0]
+	case "operator":
+		c.operator = strings.ToUpper(values[0])
+	case "memberOf":
+		c.memberOf = values[0]
+	case "value":
+		c.values = append(c.values, values...)
+	default:
+		return fmt.Errorf("unsupported member %s", segs[0])
+	}
+	return nil
+}
+
+// Answers true if the resource satisfies the filter
+func (f *filter) matches(idx *index, res Resource) bool {
+	return f.matchesGroup(idx, res, rootGroup)
+}
+
+func (f *filter) matchesGroup(idx *index, res Resource, label string) bool {
+	or := f.groups[label].conjunction == "OR"
+	members := 0
+	for _, c := range f.conditions {
+		if c.memberOf != label {
+			continue
+		}
+		members++
+		if m := c.matches(idx, res); m == or {
+			return or
+		}
+	}
+	for name, g := range f.groups {
+		if name == rootGroup || (g.memberOf != label && !(g.memberOf == "" && label == rootGroup)) {
+			continue
+		}
+		members++
+		if m := f.matchesGroup(idx, res, name); m == or {
+			return or
+		}
+	}
+	// an empty OR group matches, like an empty AND group
+	return !or || members == 0
+}
+
+// Answers true if a value of the condition's path satisfies the condition
+func (c *condition) matches(idx *index, res Resource) bool {
+	actual := idx.values(res, strings.Split(c.path, "."))
+	switch c.operator {
+	case "IS NULL":
+		return len(actual) == 0
+	case "IS NOT NULL":
+		return len(actual) > 0
+	case "<>", "NOT IN":
+		for _, a := range actual {
+			for _, v := range c.values {
+				if compare(a, v) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, a := range actual {
+		if c.matchesValue(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *condition) matchesValue(actual interface{}) bool {
+	s := fmt.Sprintf("%v", actual)
+	if len(c.values) == 0 {
+		return false
+	}
+	v := c.values[0]
+	switch c.operator {
+	case "=":
+		return compare(actual, v) == 0
+	case "IN":
+		for _, v := range c.values {
+			if compare(actual, v) == 0 {
+				return true
+			}
+		}
+		return false
+	case "<":
+		return compare(actual, v) < 0
+	case "<=":
+		return compare(actual, v) <= 0
+	case ">":
+		return compare(actual, v) > 0
+	case ">=":
+		return compare(actual, v) >= 0
+	case "BETWEEN":
+		return len(c.values) == 2 && compare(actual, c.values[0]) >= 0 && compare(actual, c.values[1]) <= 0
+	case "CONTAINS":
+		return strings.Contains(strings.ToLower(s), strings.ToLower(v))
+	case "STARTS_WITH":
+		return strings.HasPrefix(strings.ToLower(s), strings.ToLower(v))
+	case "ENDS_WITH":
+		return strings.HasSuffix(strings.ToLower(s), strings.ToLower(v))
+	}
+	return false
+}
+
+// Compares an attribute value to a filter value: numerically if both are numbers, and otherwise as strings,
+// disregarding case as Drupal's default collation does
+func compare(actual interface{}, v string) int {
+	switch a := actual.(type) {
+	case bool:
+		if a {
+			actual = "1"
+		} else {
+			actual = "0"
+		}
+	case float64:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			switch {
+			case a < f:
+				return -1
+			case a > f:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprintf("%v", actual)), strings.ToLower(v))
+}
+
+// Parses `[a][b][]` into a, b, and the empty string
+func brackets(s string) ([]string, error) {
+	var segs []string
+	for s != "" {
+		end := strings.IndexByte(s, ']')
+		if s[0] != '[' || end < 0 {
+			return nil, fmt.Errorf("malformed filter parameter %s", s)
+		}
+		segs = append(segs, s[1:end])
+		s = s[end+1:]
+	}
+	if len(segs) == 0 || segs[0] == "" {
+		return nil, fmt.Errorf("malformed filter parameter")
+	}
+	return segs, nil
+}
+
+// Locates resources by type and id
+type index struct {
+	resources []Resource
+}
+
+// Answers the resource with the type and id, or nil
+func (idx *index) find(drupalType, id interface{}) Resource {
+	for _, r := range idx.resources {
+		if r["type"] == drupalType && r["id"] == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// Answers the values found by following the path from the resource.  The path may traverse relationships, e.g.
+// `field_media_of.title` answers the titles of the resources referenced by `field_media_of`, and attributes that are
+// objects, e.g. `field_edtf_date_created.value`.  Values of multi-valued fields are answered individually.
+func (idx *index) values(res Resource, path []string) []interface{} {
+	if path[0] == "id" && len(path) == 1 {
+		return []interface{}{res["id"]}
+	}
+
+	if attrs, ok := res["attributes"].(map[string]interface{}); ok {
+		if v, ok := attrs[path[0]]; ok {
+			return descend(v, path[1:])
+		}
+	}
+
+	refs := relationship(res, path[0])
+	if refs == nil {
+		return nil
+	}
+	var values []interface{}
+	for _, ref := range refs {
+		switch {
+		case len(path) == 1 || (len(path) == 2 && path[1] == "id"):
+			values = append(values, ref["id"])
+		case path[1] == "meta":
+			values = append(values, descend(ref["meta"], path[2:])...)
+		default:
+			if related := idx.find(ref["type"], ref["id"]); related != nil {
+				values = append(values, idx.values(related, path[1:])...)
+			}
+		}
+	}
+	return values
+}
+
+// Answers the values found by descending into the attribute value along the path
+func descend(v interface{}, path []string) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		var values []interface{}
+		for _, item := range list {
+			values = append(values, descend(item, path)...)
+		}
+		return values
+	}
+	if len(path) == 0 {
+		if v == nil {
+			return nil
+		}
+		return []interface{}{v}
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		return descend(m[path[0]], path[1:])
+	}
+	return nil
+}
+
+// Answers the resource identifiers of the named relationship, or nil if the resource lacks the relationship
+func relationship(res Resource, name string) []map[string]interface{} {
+	rels, _ := res["relationships"].(map[string]interface{})
+	rel, ok := rels[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch data := rel["data"].(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{data}
+	case []interface{}:
+		refs := []map[string]interface{}{}
+		for _, d := range data {
+			if ref, ok := d.(map[string]interface{}); ok {
+				refs = append(refs, ref)
+			}
+		}
+		return refs
+	}
+	return []map[string]interface{}{}
+}
+
+// Answers the resources referenced by the comma-separated relationship paths of an `include` parameter, e.g.
+// `field_media_of,field_member_of.field_model`
+func (idx *index) included(data []Resource, include string) []Resource {
+	included := []Resource{}
+	seen := map[string]bool{}
+	for _, r := range data {
+		seen[fmt.Sprintf("%v/%v", r["type"], r["id"])] = true
+	}
+	if include == "" {
+		return included
+	}
+
+	for _, path := range strings.Split(include, ",") {
+		current := data
+		for _, name := range strings.Split(path, ".") {
+			var next []Resource
+			for _, r := range current {
+				for _, ref := range relationship(r, name) {
+					related := idx.find(ref["type"], ref["id"])
+					if related == nil {
+						continue
+					}
+					next = append(next, related)
+					key := fmt.Sprintf("%v/%v", related["type"], related["id"])
+					if !seen[key] {
+						seen[key] = true
+						included = append(included, related)
+					}
+				}
+			}
+			current = next
+		}
+	}
+	return included
+}
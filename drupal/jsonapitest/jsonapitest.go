@@ -0,0 +1,402 @@
+// Provides a mock Drupal JSON API server, so that tests of code which retrieves resources from Drupal may run without
+// a live Drupal.
+//
+// A MockServer is seeded with JSON API resource objects, either directly, from JSON files (e.g. responses saved from
+// a live Drupal), or from Expected structs.  It answers requests for collections and individual resources, supporting
+// the subset of the JSON API used by this module: shorthand and condition filters (including filters that traverse
+// relationships, e.g. `field_media_of.title`), `include`, and pagination using `page[offset]` and `page[limit]`.
+// Resources may also be created by POSTing them to their collection, and updated by PATCHing them.  Each request is
+// recorded, so that tests may assert on the requests that were made.
+//
+//	server := jsonapitest.NewMockServer()
+//	defer server.Close()
+//	server.AddExpected(model.ExpectedSubject{...})
+//	u := &jsonapi.JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "taxonomy_term", DrupalBundle: "subject", ...}
+package jsonapitest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// The number of resources answered per page, if not limited by the request
+const DefaultPageSize = 50
+
+// A JSON API resource object, carrying `type`, `id`, and optionally `attributes` and `relationships`
+type Resource = map[string]interface{}
+
+// Expected entities (e.g. model.ExpectedSubject) that may seed a MockServer
+type Expected interface {
+	EntityType() string
+	EntityBundle() string
+	NameOrTitle() string
+	Field() string
+}
+
+// A request received by a MockServer
+type RecordedRequest struct {
+	Method string
+	// The path of the request, e.g. `/jsonapi/node/islandora_object`
+	Path  string
+	Query url.Values
+	// The user of the request, if it used HTTP Basic Auth
+	Username string
+}
+
+// A mock Drupal JSON API server.  A MockServer is safe for concurrent use; resources may be added while the server
+// answers requests.
+type MockServer struct {
+	*httptest.Server
+	// The number of resources answered per page, if not limited by the request; DefaultPageSize if not positive
+	PageSize int
+
+	mu        sync.Mutex
+	resources []Resource
+	requests  []RecordedRequest
+	username  string
+	password  string
+}
+
+// Creates and starts a MockServer, which must be closed by the caller
+func NewMockServer() *MockServer {
+	m := &MockServer{}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+	return m
+}
+
+// Adds resource objects to the server.  Each resource must carry a `type` (e.g. `node--islandora_object`); resources
+// lacking an `id` are assigned a random UUID.
+func (m *MockServer) Add(resources ...Resource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range resources {
+		if _, ok := r["id"].(string); !ok {
+			r["id"] = newUuid()
+		}
+		m.resources = append(m.resources, r)
+	}
+}
+
+// Adds the resources of a JSON document, which may be a single resource object, an array of resource objects, or a
+// JSON API response document, in which case the resources of both its `data` and `included` elements are added
+func (m *MockServer) AddJson(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("jsonapitest: unable to unmarshal resources: %w", err)
+	}
+
+	var objects []interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		objects = v
+	case map[string]interface{}:
+		if data, ok := v["data"]; ok {
+			if list, ok := data.([]interface{}); ok {
+				objects = append(objects, list...)
+			} else {
+				objects = append(objects, data)
+			}
+			if included, ok := v["included"].([]interface{}); ok {
+				objects = append(objects, included...)
+			}
+		} else {
+			objects = append(objects, v)
+		}
+	}
+
+	var resources []Resource
+	for _, o := range objects {
+		r, ok := o.(map[string]interface{})
+		if _, typed := r["type"].(string); !ok || !typed {
+			return fmt.Errorf("jsonapitest: resource objects must carry a type: %v", o)
+		}
+		resources = append(resources, r)
+	}
+	m.Add(resources...)
+	return nil
+}
+
+// Adds the resources of a JSON file (see AddJson)
+func (m *MockServer) AddFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("jsonapitest: %w", err)
+	}
+	return m.AddJson(b)
+}
+
+// Adds a resource representing the expected entity, carrying its type and its name or title, and answers the
+// resource so that the caller may add further attributes or relationships
+func (m *MockServer) AddExpected(e Expected) Resource {
+	r := Resource{
+		"type":       e.EntityType() + "--" + e.EntityBundle(),
+		"attributes": map[string]interface{}{e.Field(): e.NameOrTitle()},
+	}
+	m.Add(r)
+	return r
+}
+
+// Requires every request to authenticate using HTTP Basic Auth with the supplied credentials.  Requests lacking
+// credentials are answered with 401, and requests with the wrong credentials with 403.
+func (m *MockServer) RequireBasicAuth(username, password string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.username, m.password = username, password
+}
+
+// Answers the requests received by the server, in the order they were received
+func (m *MockServer) Requests() []RecordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RecordedRequest(nil), m.requests...)
+}
+
+// Removes every resource and recorded request
+func (m *MockServer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resources, m.requests = nil, nil
+}
+
+func (m *MockServer) serve(w http.ResponseWriter, r *http.Request) {
+	username, password, hasAuth := r.BasicAuth()
+
+	m.mu.Lock()
+	m.requests = append(m.requests, RecordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Username: username})
+	resources := append([]Resource(nil), m.resources...)
+	requiredUser, requiredPassword := m.username, m.password
+	pageSize := m.PageSize
+	m.mu.Unlock()
+
+	if requiredUser != "" {
+		if !hasAuth {
+			writeError(w, http.StatusUnauthorized, "authentication is required")
+			return
+		}
+		if username != requiredUser || !env.Secret(requiredPassword).Equal(password) {
+			writeError(w, http.StatusForbidden, "invalid credentials")
+			return
+		}
+	}
+
+	// /jsonapi/{entity}/{bundle}[/{id}], optionally prefixed by a language code, which is ignored
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) > 0 && segments[0] != "jsonapi" {
+		segments = segments[1:]
+	}
+	if len(segments) == 1 && segments[0] == "jsonapi" && r.Method == http.MethodGet {
+		writeJson(w, http.StatusOK, m.entryPoint(resources, username))
+		return
+	}
+	if len(segments) < 3 || len(segments) > 4 || segments[0] != "jsonapi" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no route matches %s", r.URL.Path))
+		return
+	}
+	drupalType := segments[1] + "--" + segments[2]
+
+	if r.Method == http.MethodPost && len(segments) == 3 {
+		m.create(w, r, drupalType)
+		return
+	}
+	if r.Method == http.MethodPatch && len(segments) == 4 {
+		m.update(w, r, drupalType, segments[3])
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported for %s", r.Method, r.URL.Path))
+		return
+	}
+
+	index := &index{resources: resources}
+	doc := map[string]interface{}{"jsonapi": map[string]interface{}{"version": "1.0"}}
+
+	if len(segments) == 4 {
+		res := index.find(drupalType, segments[3])
+		if res == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("the requested %s resource %s does not exist", drupalType, segments[3]))
+			return
+		}
+		doc["data"] = res
+		doc["included"] = index.included([]Resource{res}, r.URL.Query().Get("include"))
+		writeJson(w, http.StatusOK, doc)
+		return
+	}
+
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var matched []Resource
+	for _, res := range resources {
+		if res["type"] == drupalType && filter.matches(index, res) {
+			matched = append(matched, res)
+		}
+	}
+
+	offset, limit, err := page(r.URL.Query(), pageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data := []Resource{}
+	if offset < len(matched) {
+		end := offset + limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		data = matched[offset:end]
+	}
+	doc["data"] = data
+	if include := r.URL.Query().Get("include"); include != "" {
+		doc["included"] = index.included(data, include)
+	}
+
+	links := map[string]interface{}{"self": map[string]string{"href": m.URL + r.URL.RequestURI()}}
+	if offset+limit < len(matched) {
+		q := r.URL.Query()
+		q.Set("page[offset]", strconv.Itoa(offset+limit))
+		q.Set("page[limit]", strconv.Itoa(limit))
+		links["next"] = map[string]string{"href": m.URL + r.URL.Path + "?" + q.Encode()}
+	}
+	doc["links"] = links
+	writeJson(w, http.StatusOK, doc)
+}
+
+// Creates the resource carried by the request, answering it with a 201 as Drupal does
+// Answers the JSON:API entry point, linking the resource types of the resources.  The entry point of an authenticated
+// request links the current user.
+func (m *MockServer) entryPoint(resources []Resource, username string) map[string]interface{} {
+	links := map[string]interface{}{"self": map[string]string{"href": m.URL + "/jsonapi"}}
+	for _, res := range resources {
+		if drupalType, ok := res["type"].(string); ok {
+			links[drupalType] = map[string]string{"href": m.URL + "/jsonapi/" + strings.Replace(drupalType, "--", "/", 1)}
+		}
+	}
+	meta := map[string]interface{}{}
+	if username != "" {
+		meta["links"] = map[string]interface{}{"me": map[string]interface{}{"href": m.URL + "/jsonapi/user/user/" + username, "meta": map[string]string{"id": username}}}
+	}
+	return map[string]interface{}{"jsonapi": map[string]interface{}{"version": "1.0"}, "links": links, "meta": meta}
+}
+
+func (m *MockServer) create(w http.ResponseWriter, r *http.Request, drupalType string) {
+	doc := struct {
+		Data Resource `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || doc.Data == nil {
+		writeError(w, http.StatusBadRequest, "the request body must be a JSON API document carrying a resource")
+		return
+	}
+	if doc.Data["type"] != drupalType {
+		writeError(w, http.StatusConflict, fmt.Sprintf("the resource type %v does not match %s", doc.Data["type"], drupalType))
+		return
+	}
+	m.Add(doc.Data)
+	writeJson(w, http.StatusCreated, map[string]interface{}{"data": doc.Data})
+}
+
+// Updates the resource with the attributes and relationships carried by the request, answering the updated resource
+// as Drupal does
+func (m *MockServer) update(w http.ResponseWriter, r *http.Request, drupalType, id string) {
+	doc := struct {
+		Data Resource `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || doc.Data == nil {
+		writeError(w, http.StatusBadRequest, "the request body must be a JSON API document carrying a resource")
+		return
+	}
+	if doc.Data["type"] != drupalType || doc.Data["id"] != id {
+		writeError(w, http.StatusConflict, fmt.Sprintf("the resource %v %v does not match %s %s", doc.Data["type"],
+			doc.Data["id"], drupalType, id))
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, res := range m.resources {
+		if res["type"] != drupalType || res["id"] != id {
+			continue
+		}
+		// resources are replaced rather than modified, as requests in flight may be reading them
+		updated := Resource{}
+		for k, v := range res {
+			updated[k] = v
+		}
+		for _, member := range []string{"attributes", "relationships"} {
+			changes, _ := doc.Data[member].(map[string]interface{})
+			if len(changes) == 0 {
+				continue
+			}
+			merged := map[string]interface{}{}
+			if existing, ok := res[member].(map[string]interface{}); ok {
+				for k, v := range existing {
+					merged[k] = v
+				}
+			}
+			for k, v := range changes {
+				merged[k] = v
+			}
+			updated[member] = merged
+		}
+		m.resources[i] = updated
+		writeJson(w, http.StatusOK, map[string]interface{}{"data": updated})
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("the requested %s resource %s does not exist", drupalType, id))
+}
+
+// Answers the offset and limit of the requested page
+func page(q url.Values, pageSize int) (offset, limit int, err error) {
+	limit = pageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if v := q.Get("page[offset]"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid page[offset] %s", v)
+		}
+	}
+	if v := q.Get("page[limit]"); v != "" {
+		requested, err := strconv.Atoi(v)
+		if err != nil || requested <= 0 {
+			return 0, 0, fmt.Errorf("invalid page[limit] %s", v)
+		}
+		if requested < limit {
+			limit = requested
+		}
+	}
+	return offset, limit, nil
+}
+
+func writeJson(w http.ResponseWriter, status int, doc interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+// Answers an error document, as Drupal does
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJson(w, status, map[string]interface{}{
+		"errors": []map[string]interface{}{{"status": strconv.Itoa(status), "title": http.StatusText(status), "detail": detail}},
+	})
+}
+
+// Answers a random (version 4) UUID
+func newUuid() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
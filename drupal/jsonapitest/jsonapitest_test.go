@@ -0,0 +1,162 @@
+package jsonapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var moonrise = `{
+  "data": {
+    "type": "node--islandora_object",
+    "id": "n1",
+    "attributes": {"title": "Moonrise Over Hernandez", "field_featured_item": true, "field_weight": 3},
+    "relationships": {"field_member_of": {"data": [{"type": "node--collection_object", "id": "c1"}]}}
+  },
+  "included": [
+    {"type": "node--collection_object", "id": "c1", "attributes": {"title": "Ansel Adams Images"}}
+  ]
+}`
+
+func seed(t *testing.T) *MockServer {
+	m := NewMockServer()
+	require.Nil(t, m.AddJson([]byte(moonrise)))
+	m.Add(
+		Resource{"type": "media--image", "id": "m1", "attributes": map[string]interface{}{"name": "Thumbnail Image.jpg"},
+			"relationships": map[string]interface{}{"field_media_of": map[string]interface{}{"data": map[string]interface{}{"type": "node--islandora_object", "id": "n1"}}}},
+		Resource{"type": "media--image", "id": "m2", "attributes": map[string]interface{}{"name": "Service File.jpg"},
+			"relationships": map[string]interface{}{"field_media_of": map[string]interface{}{"data": map[string]interface{}{"type": "node--islandora_object", "id": "n1"}}}},
+	)
+	return m
+}
+
+func Test_MockServerFilters(t *testing.T) {
+	m := seed(t)
+	defer m.Close()
+
+	fetch := func(u *jsonapi.JsonApiUrl) []interface{} {
+		u.BaseUrl = m.URL
+		res := &jsonapi.JsonApiResponse{}
+		require.Nil(t, u.Fetch(res))
+		var ids []interface{}
+		for _, d := range res.Data {
+			ids = append(ids, d["id"])
+		}
+		return ids
+	}
+
+	assert.Equal(t, []interface{}{"n1"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise Over Hernandez"}))
+	assert.Empty(t, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonset"}))
+	assert.Equal(t, []interface{}{"n1"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "field_member_of.title", Value: "Ansel Adams Images"}))
+	assert.Equal(t, []interface{}{"n1"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "field_featured_item", Value: "1"}))
+	assert.Equal(t, []interface{}{"m1", "m2"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "media", DrupalBundle: "image", Filter: "field_media_of.id", Value: "n1"}))
+	assert.Equal(t, []interface{}{"n1"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "node", DrupalBundle: "islandora_object",
+		RawFilter: "filter[w][condition][path]=field_weight&filter[w][condition][operator]=>=&filter[w][condition][value]=3"}))
+
+	// the raw filter used by the derivative tests
+	assert.Equal(t, []interface{}{"m1"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "media", DrupalBundle: "image",
+		RawFilter: "filter[name-group][condition][operator]=ENDS_WITH&filter[name-group][condition][path]=name&filter[name-group][condition][value]=Thumbnail Image.jpg&filter[of-group][condition][path]=field_media_of.title&filter[of-group][condition][value]=Moonrise Over Hernandez"}))
+
+	// condition groups
+	assert.Equal(t, []interface{}{"m1", "m2"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "media", DrupalBundle: "image",
+		RawFilter: "filter[g][group][conjunction]=OR&filter[a][condition][path]=name&filter[a][condition][value]=Thumbnail Image.jpg&filter[a][condition][memberOf]=g&filter[b][condition][path]=name&filter[b][condition][operator]=STARTS_WITH&filter[b][condition][value]=service&filter[b][condition][memberOf]=g"}))
+	assert.Equal(t, []interface{}{"m2"}, fetch(&jsonapi.JsonApiUrl{DrupalEntity: "media", DrupalBundle: "image",
+		RawFilter: "filter[n][condition][path]=name&filter[n][condition][operator]=IN&filter[n][condition][value][]=Service File.jpg&filter[n][condition][value][]=Original File.jpg"}))
+
+	res, err := http.Get(m.URL + "/jsonapi/node/islandora_object?filter[bad=1")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func Test_MockServerPagesAndIncludes(t *testing.T) {
+	m := seed(t)
+	defer m.Close()
+	m.PageSize = 1
+
+	u := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "media", DrupalBundle: "image"}
+	var ids, included []interface{}
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			ids = append(ids, d["id"])
+		}
+		for _, i := range page.Included {
+			included = append(included, i["id"])
+		}
+		return nil
+	}, "field_media_of.field_member_of")
+	require.Nil(t, err)
+	assert.Equal(t, []interface{}{"m1", "m2"}, ids)
+	assert.Equal(t, []interface{}{"n1", "c1", "n1", "c1"}, included)
+
+	requests := m.Requests()
+	require.Equal(t, 2, len(requests))
+	assert.Equal(t, "/jsonapi/media/image", requests[1].Path)
+	assert.Equal(t, "1", requests[1].Query.Get("page[offset]"))
+
+	// individual resources
+	_, body, err := jsonapi.FetchResource(m.URL+"/jsonapi/node/collection_object/c1", "", "")
+	require.Nil(t, err)
+	assert.Contains(t, string(body), "Ansel Adams Images")
+	_, _, err = jsonapi.FetchResource(m.URL+"/jsonapi/node/collection_object/c2", "", "")
+	assert.NotNil(t, err)
+}
+
+func Test_MockServerSeeding(t *testing.T) {
+	m := NewMockServer()
+	defer m.Close()
+	m.RequireBasicAuth("admin", "password")
+
+	expected := model.ExpectedSubject{}
+	expected.Type, expected.Bundle, expected.Name = model.TaxonomyTerm, model.Subject, "Analog Photography"
+	res := m.AddExpected(expected)
+	res["attributes"].(map[string]interface{})["description"] = map[string]interface{}{"value": "<p>Analog photography</p>"}
+
+	file := filepath.Join(t.TempDir(), "genre.json")
+	require.Nil(t, ioutil.WriteFile(file, []byte(`[{"type": "taxonomy_term--genre", "attributes": {"name": "Maps"}}]`), 0644))
+	require.Nil(t, m.AddFile(file))
+	assert.NotNil(t, m.AddJson([]byte(`{"id": "untyped"}`)))
+
+	u := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: model.TaxonomyTerm, DrupalBundle: model.Subject, Filter: "name", Value: "Analog Photography"}
+	assert.NotNil(t, u.FetchSingle(&jsonapi.JsonApiResponse{}))
+
+	u.Username, u.Password = "admin", "password"
+	subject := &model.JsonApiSubject{}
+	require.Nil(t, u.FetchSingle(subject))
+	assert.Equal(t, "<p>Analog photography</p>", subject.JsonApiData[0].JsonApiAttributes.Description.Value)
+	assert.Equal(t, res["id"], subject.JsonApiData[0].Id)
+
+	genre := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: model.TaxonomyTerm, DrupalBundle: model.Genre, Filter: "name", Value: "Maps", Username: "admin", Password: "password"}
+	assert.Nil(t, genre.FetchSingle(&jsonapi.JsonApiResponse{}))
+
+	requests := m.Requests()
+	require.Equal(t, 3, len(requests))
+	assert.Equal(t, "", requests[0].Username)
+	assert.Equal(t, "admin", requests[1].Username)
+	assert.Equal(t, fmt.Sprintf("/jsonapi/%s/%s", model.TaxonomyTerm, model.Genre), requests[2].Path)
+
+	m.Reset()
+	assert.Empty(t, m.Requests())
+}
+
+func Test_MockServerEntryPoint(t *testing.T) {
+	m := NewMockServer()
+	defer m.Close()
+	m.Add(Resource{"type": "taxonomy_term--genre", "id": "g1", "attributes": map[string]interface{}{"name": "Maps"}})
+
+	res, err := http.Get(m.URL + "/jsonapi")
+	require.Nil(t, err)
+	defer res.Body.Close()
+	doc := map[string]interface{}{}
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&doc))
+	links := doc["links"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"href": m.URL + "/jsonapi/taxonomy_term/genre"}, links["taxonomy_term--genre"])
+	assert.Nil(t, doc["meta"].(map[string]interface{})["links"])
+}
@@ -0,0 +1,180 @@
+// Provides the leveled logger used by the packages of this module, so that a test suite may route their logs (e.g. to
+// zerolog) and raise or lower their verbosity in one place.  By default messages are written using the standard
+// library's log package, at the level named by the IDC_LOG_LEVEL environment variable, or Info if it is unset:
+//
+//	IDC_LOG_LEVEL=debug go test ./...
+//
+// At Debug, the HTTP client of the jsonapi package also logs each request and response, with credentials redacted.
+// A test suite may install its own Logger:
+//
+//	logging.SetLogger(logging.Zerolog(zerolog.New(os.Stderr).Level(zerolog.DebugLevel)))
+package logging
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// The severity of a message
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+// The environment variable naming the minimum level logged by the default logger, e.g. `debug`
+const LevelEnv = "IDC_LOG_LEVEL"
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// Answers the name of the level, e.g. `debug`
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// Parses the name of a level, e.g. `debug` or `WARN`; `warning` is accepted as well as `warn`
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "warning" {
+		name = "warn"
+	}
+	for i, n := range levelNames {
+		if n == name {
+			return Level(i), nil
+		}
+	}
+	return Info, fmt.Errorf("logging: unknown level '%s'", s)
+}
+
+// Logs messages at a minimum level
+type Logger interface {
+	// Logs the message, formatted as by fmt.Sprintf, if the level is enabled
+	Logf(level Level, format string, args ...interface{})
+	// Answers true if messages of the level are logged, so that expensive messages may be skipped
+	Enabled(level Level) bool
+}
+
+// Logs messages of Level and above using a standard library logger, prefixed by their level, e.g. `DEBUG`
+type StdLogger struct {
+	// The logger written to; the standard logger of the log package if nil
+	Out   *log.Logger
+	Level Level
+}
+
+func (l *StdLogger) Logf(level Level, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	msg := strings.ToUpper(level.String()) + " " + fmt.Sprintf(format, args...)
+	if l.Out == nil {
+		_ = log.Output(3, msg)
+		return
+	}
+	_ = l.Out.Output(3, msg)
+}
+
+func (l *StdLogger) Enabled(level Level) bool {
+	return level >= l.Level
+}
+
+type zerologLogger struct {
+	z zerolog.Logger
+}
+
+// Answers a Logger writing to the zerolog logger, at the levels it enables
+func Zerolog(z zerolog.Logger) Logger {
+	return &zerologLogger{z: z}
+}
+
+func (l *zerologLogger) Logf(level Level, format string, args ...interface{}) {
+	if l.Enabled(level) {
+		l.z.WithLevel(zerologLevel(level)).Msgf(format, args...)
+	}
+}
+
+func (l *zerologLogger) Enabled(level Level) bool {
+	return zerologLevel(level) >= l.z.GetLevel() && zerolog.GlobalLevel() <= zerologLevel(level)
+}
+
+func zerologLevel(level Level) zerolog.Level {
+	switch level {
+	case Debug:
+		return zerolog.DebugLevel
+	case Warn:
+		return zerolog.WarnLevel
+	case Error:
+		return zerolog.ErrorLevel
+	}
+	return zerolog.InfoLevel
+}
+
+var (
+	mu      sync.RWMutex
+	current = FromEnv()
+)
+
+// Answers a StdLogger logging at the level named by LevelEnv, or Info if it is unset or unknown
+func FromEnv() Logger {
+	l := &StdLogger{Level: Info}
+	if name, ok := os.LookupEnv(LevelEnv); ok {
+		level, err := ParseLevel(name)
+		if err != nil {
+			l.Logf(Warn, "%s; logging at %s", err, Info)
+		}
+		l.Level = level
+	}
+	return l
+}
+
+// Replaces the logger used by the packages of this module.  A nil logger restores the default (see FromEnv).
+func SetLogger(l Logger) {
+	if l == nil {
+		l = FromEnv()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = l
+}
+
+// Answers the logger used by the packages of this module
+func Current() Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Answers true if the current logger logs messages of the level
+func Enabled(level Level) bool {
+	return Current().Enabled(level)
+}
+
+// Logs the message at Debug using the current logger
+func Debugf(format string, args ...interface{}) {
+	Current().Logf(Debug, format, args...)
+}
+
+// Logs the message at Info using the current logger
+func Infof(format string, args ...interface{}) {
+	Current().Logf(Info, format, args...)
+}
+
+// Logs the message at Warn using the current logger
+func Warnf(format string, args ...interface{}) {
+	Current().Logf(Warn, format, args...)
+}
+
+// Logs the message at Error using the current logger
+func Errorf(format string, args ...interface{}) {
+	Current().Logf(Error, format, args...)
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseLevel(t *testing.T) {
+	for name, level := range map[string]Level{"debug": Debug, "INFO": Info, "warn": Warn, "warning": Warn, " error ": Error} {
+		l, err := ParseLevel(name)
+		require.Nil(t, err)
+		assert.Equal(t, level, l)
+	}
+	_, err := ParseLevel("verbose")
+	assert.NotNil(t, err)
+	assert.Equal(t, "warn", Warn.String())
+}
+
+func Test_StdLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := &StdLogger{Out: log.New(buf, "", 0), Level: Info}
+	l.Logf(Debug, "skipped")
+	l.Logf(Warn, "retrying %s", "/jsonapi")
+	assert.Equal(t, "WARN retrying /jsonapi\n", buf.String())
+	assert.False(t, l.Enabled(Debug))
+	assert.True(t, l.Enabled(Error))
+}
+
+func Test_Zerolog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := Zerolog(zerolog.New(buf).Level(zerolog.InfoLevel))
+	l.Logf(Debug, "skipped")
+	l.Logf(Info, "retrieving %s", "/jsonapi")
+	assert.Equal(t, `{"level":"info","message":"retrieving /jsonapi"}`+"\n", buf.String())
+	assert.False(t, l.Enabled(Debug))
+}
+
+func Test_SetLogger(t *testing.T) {
+	defer SetLogger(nil)
+	buf := &bytes.Buffer{}
+	SetLogger(&StdLogger{Out: log.New(buf, "", 0), Level: Debug})
+	assert.True(t, Enabled(Debug))
+	Debugf("a")
+	Infof("b")
+	Warnf("c")
+	Errorf("d")
+	assert.Equal(t, "DEBUG a\nINFO b\nWARN c\nERROR d\n", buf.String())
+
+	defer os.Unsetenv(LevelEnv)
+	os.Setenv(LevelEnv, "error")
+	SetLogger(nil)
+	assert.False(t, Enabled(Warn))
+	assert.True(t, Enabled(Error))
+	os.Setenv(LevelEnv, "verbose")
+	assert.True(t, FromEnv().Enabled(Info))
+	assert.False(t, FromEnv().Enabled(Debug))
+}
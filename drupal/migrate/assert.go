@@ -0,0 +1,29 @@
+//go:build !notestify
+// +build !notestify
+
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the migration completed without failed rows.  If any rows failed, the messages recorded by the
+// migration are included in the failure, so that the reason for each failure is visible in the test output.
+func AssertSucceeded(t assert.TestingT, r MessageReader, status *Status) bool {
+	if !assert.NotNil(t, status, "no migration status") {
+		return false
+	}
+	if status.Complete() && status.Failed == 0 {
+		return true
+	}
+	messages, err := r.Messages(status.Id)
+	details := FormatMessages(messages)
+	if err != nil {
+		details = fmt.Sprintf("(the messages could not be retrieved: %s)", err)
+	}
+	return assert.Fail(t, fmt.Sprintf("migration '%s' did not succeed", status.Id),
+		"%s: %d imported, %d failed, %d ignored, %d unprocessed of %d\n%s",
+		status.Status, status.Imported, status.Failed, status.Ignored, status.Unprocessed, status.Total, details)
+}
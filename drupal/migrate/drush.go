@@ -0,0 +1,108 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// Runs migrations using drush.  Commands are run locally, or on a remote host if Command names a prefix like
+// `ssh islandora@idc.example.org`, or `docker exec drupal`.
+type DrushRunner struct {
+	// The command prefixing each drush invocation, e.g. `ssh islandora@idc.example.org`; drush is run locally if empty
+	Command []string
+	// The path to drush; `drush` if empty
+	Drush string
+	// The uri of the Drupal site, passed to drush as `--uri`, if not empty
+	Uri string
+
+	mu      sync.Mutex
+	imports map[string]*drushImport
+}
+
+// A running or completed `drush migrate:import`
+type drushImport struct {
+	done   bool
+	err    error
+	output bytes.Buffer
+}
+
+// Starts `drush migrate:import` for the migration, answering once it is started.  If the import fails, the failure is
+// answered by the next invocation of Status.
+func (r *DrushRunner) Import(id string) error {
+	cmd := r.command("migrate:import", id)
+	imp := &drushImport{}
+	cmd.Stdout, cmd.Stderr = &imp.output, &imp.output
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("migrate: unable to start %v: %w", cmd.Args, err)
+	}
+
+	r.mu.Lock()
+	if r.imports == nil {
+		r.imports = map[string]*drushImport{}
+	}
+	r.imports[id] = imp
+	r.mu.Unlock()
+
+	go func() {
+		err := cmd.Wait()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		imp.done = true
+		if err != nil {
+			imp.err = fmt.Errorf("migrate: %v failed: %w: %s", cmd.Args, err, bytes.TrimSpace(imp.output.Bytes()))
+		}
+	}()
+	return nil
+}
+
+// Answers the status of the migration from `drush migrate:status`.  While an import started by Import is running, the
+// migration is reported as importing; if the import failed, its failure is answered.
+func (r *DrushRunner) Status(id string) (*Status, error) {
+	r.mu.Lock()
+	imp := r.imports[id]
+	running := imp != nil && !imp.done
+	var importErr error
+	if imp != nil {
+		importErr = imp.err
+	}
+	r.mu.Unlock()
+	if importErr != nil {
+		return nil, importErr
+	}
+
+	cmd := r.command("migrate:status", id, "--format=json")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %v failed: %w", cmd.Args, err)
+	}
+	var statuses []*Status
+	if err := json.Unmarshal(out, &statuses); err != nil {
+		return nil, fmt.Errorf("migrate: unable to unmarshal the output of %v: %w", cmd.Args, err)
+	}
+	for _, s := range statuses {
+		if s.Id == id {
+			// drush reports Idle until the import has begun processing rows
+			if running && s.Status == Idle {
+				s.Status = "Importing"
+			}
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("migrate: drush reported no status for migration '%s'", id)
+}
+
+func (r *DrushRunner) command(args ...string) *exec.Cmd {
+	drush := r.Drush
+	if drush == "" {
+		drush = "drush"
+	}
+	argv := append(append([]string{}, r.Command...), drush)
+	argv = append(argv, args...)
+	if r.Uri != "" {
+		argv = append(argv, "--uri="+r.Uri)
+	}
+	return exec.Command(argv[0], argv[1:]...)
+}
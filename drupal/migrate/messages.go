@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The path of the endpoint used by RestRunner.Messages if it names none; `%s` is replaced by the migration id
+const DefaultMessagesPath = "/migrate_api/%s/messages"
+
+// A message recorded for a source row by a migration (i.e. a row of its `migrate_message` table), typically explaining
+// why the row failed or was ignored
+type Message struct {
+	// The level of the message: 1 for errors, 2 for warnings, 3 for notices, and 4 for informational messages
+	Level int `json:"level"`
+	// The source ids of the row, e.g. `io_1`
+	SourceIds      string `json:"source_ids"`
+	DestinationIds string `json:"destination_ids"`
+	Message        string `json:"message"`
+}
+
+// Accepts the level serialized as a number or a numeric string, and source ids serialized as a string, a list, or an
+// object, as answered by drush and by REST endpoints
+func (m *Message) UnmarshalJSON(b []byte) error {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*m = Message{SourceIds: ids(raw["source_ids"]), DestinationIds: ids(raw["destination_ids"])}
+	m.Message, _ = raw["message"].(string)
+	switch level := raw["level"].(type) {
+	case float64:
+		m.Level = int(level)
+	case string:
+		m.Level, _ = strconv.Atoi(strings.TrimSpace(level))
+	}
+	return nil
+}
+
+// Answers the message as e.g. `io_1: Missing required field title`
+func (m Message) String() string {
+	return fmt.Sprintf("%s: %s", m.SourceIds, m.Message)
+}
+
+// Reads the messages recorded by migrations
+type MessageReader interface {
+	// Answers the messages recorded by the migration
+	Messages(id string) ([]Message, error)
+}
+
+// Answers the messages of the migration from `drush migrate:messages`
+func (r *DrushRunner) Messages(id string) ([]Message, error) {
+	cmd := r.command("migrate:messages", id, "--format=json")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %v failed: %w", cmd.Args, err)
+	}
+	var messages []Message
+	// drush answers nothing at all, rather than an empty list, for a migration without messages
+	if len(strings.TrimSpace(string(out))) == 0 {
+		return messages, nil
+	}
+	if err := json.Unmarshal(out, &messages); err != nil {
+		return nil, fmt.Errorf("migrate: unable to unmarshal the output of %v: %w", cmd.Args, err)
+	}
+	return messages, nil
+}
+
+// Answers the messages of the migration from the messages endpoint, which must answer a JSON list of Message
+func (r *RestRunner) Messages(id string) ([]Message, error) {
+	body, err := r.do(http.MethodGet, r.MessagesPath, DefaultMessagesPath, id)
+	if err != nil {
+		return nil, err
+	}
+	var messages []Message
+	if err := json.Unmarshal(body, &messages); err != nil {
+		return nil, fmt.Errorf("migrate: unable to unmarshal the messages of '%s': %w", id, err)
+	}
+	return messages, nil
+}
+
+// Answers the messages one per line, for inclusion in failure output
+func FormatMessages(messages []Message) string {
+	lines := make([]string, 0, len(messages))
+	for _, m := range messages {
+		lines = append(lines, m.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Answers the source ids of a message as a string, joining multiple ids with a comma
+func ids(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		var parts []string
+		for _, id := range v {
+			parts = append(parts, ids(id))
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var parts []string
+		for _, k := range keys {
+			parts = append(parts, ids(v[k]))
+		}
+		return strings.Join(parts, ",")
+	}
+	return ""
+}
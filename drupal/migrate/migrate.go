@@ -0,0 +1,125 @@
+// Triggers Drupal migrations and polls their status, so that test suites may run the migrations they verify rather
+// than assuming they were run by external scripting.
+//
+// Migrations are run by a Runner: a RestRunner uses HTTP endpoints exposed by the site, and a DrushRunner invokes
+// drush, locally or over SSH.  Run triggers a migration and blocks until it completes:
+//
+//	r := &migrate.DrushRunner{Command: []string{"ssh", "islandora@idc.example.org"}, Uri: "https://idc.example.org"}
+//	status, err := migrate.Run(r, "idc_ingest_new_items", 10*time.Minute)
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrTimeout = errors.New("timed out waiting for migration")
+
+// The status reported by Drupal for a migration that is not running
+const Idle = "Idle"
+
+// The interval between polls of Run
+var PollInterval = 5 * time.Second
+
+// The status of a migration, as reported by `drush migrate:status`
+type Status struct {
+	Id string `json:"id"`
+	// E.g. `Idle` or `Importing`
+	Status string `json:"status"`
+	// The number of source rows
+	Total int `json:"total"`
+	// The number of source rows imported
+	Imported int `json:"imported"`
+	// The number of source rows not yet processed
+	Unprocessed int `json:"unprocessed"`
+	// The number of source rows that failed to import
+	Failed int `json:"failed"`
+	// The number of source rows ignored, e.g. skipped by a process plugin
+	Ignored      int    `json:"ignored"`
+	LastImported string `json:"last_imported"`
+}
+
+// Accepts counts serialized as numbers, numeric strings, or empty strings, as answered by drush and by REST endpoints
+func (s *Status) UnmarshalJSON(b []byte) error {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	str := func(key string) string {
+		switch v := raw[key].(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+	count := func(key string) (int, error) {
+		v := strings.TrimSpace(str(key))
+		if v == "" || v == "N/A" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("migrate: invalid %s count '%s': %w", key, v, err)
+		}
+		return n, nil
+	}
+
+	*s = Status{Id: str("id"), Status: str("status"), LastImported: str("last_imported")}
+	var err error
+	for _, c := range []struct {
+		key string
+		n   *int
+	}{{"total", &s.Total}, {"imported", &s.Imported}, {"unprocessed", &s.Unprocessed}, {"failed", &s.Failed}, {"ignored", &s.Ignored}} {
+		if *c.n, err = count(c.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Answers true if the migration is not running and every source row has been processed
+func (s *Status) Complete() bool {
+	return s.Status == Idle && s.Unprocessed == 0
+}
+
+// Triggers migrations and reports their status
+type Runner interface {
+	// Triggers the import of the migration, answering once it is triggered; the import may continue afterwards
+	Import(id string) error
+	// Answers the current status of the migration
+	Status(id string) (*Status, error)
+}
+
+// Triggers the import of the migration, and polls its status every PollInterval until it completes.  The final status
+// is answered.  An error wrapping ErrTimeout is answered, along with the last status, if the migration does not complete
+// within the timeout.
+func Run(r Runner, id string, timeout time.Duration) (*Status, error) {
+	if err := r.Import(id); err != nil {
+		return nil, err
+	}
+	return Wait(r, id, timeout)
+}
+
+// Polls the status of the migration every PollInterval until it completes (see Run)
+func Wait(r Runner, id string, timeout time.Duration) (*Status, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := r.Status(id)
+		if err != nil {
+			return nil, err
+		}
+		if status.Complete() {
+			return status, nil
+		}
+		if time.Now().Add(PollInterval).After(deadline) {
+			return status, fmt.Errorf("%w '%s' after %s: %s with %d of %d unprocessed", ErrTimeout, id, timeout, status.Status, status.Unprocessed, status.Total)
+		}
+		time.Sleep(PollInterval)
+	}
+}
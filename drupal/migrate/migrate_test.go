@@ -0,0 +1,155 @@
+//go:build !notestify
+// +build !notestify
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fastPolling() func() {
+	interval := PollInterval
+	PollInterval = 10 * time.Millisecond
+	return func() { PollInterval = interval }
+}
+
+func Test_StatusUnmarshal(t *testing.T) {
+	s := &Status{}
+	require.Nil(t, json.Unmarshal([]byte(`{"id": "idc_ingest_new_items", "status": "Idle", "total": "10", "imported": 8, "unprocessed": "", "failed": "2", "last_imported": "2021-06-01 12:00:00"}`), s))
+	assert.Equal(t, Status{Id: "idc_ingest_new_items", Status: Idle, Total: 10, Imported: 8, Failed: 2, LastImported: "2021-06-01 12:00:00"}, *s)
+	assert.True(t, s.Complete())
+	assert.NotNil(t, json.Unmarshal([]byte(`{"total": "many"}`), s))
+}
+
+func Test_RestRunner(t *testing.T) {
+	defer fastPolling()()
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if user, _, _ := r.BasicAuth(); user != "admin" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/migrate_api/idc_ingest_new_items/import":
+			polls = 0
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/migrate_api/idc_ingest_new_items/status":
+			polls++
+			if polls < 3 {
+				fmt.Fprintf(w, `{"id": "idc_ingest_new_items", "status": "Importing", "total": 10, "imported": %d, "unprocessed": %d}`, polls*3, 10-polls*3)
+				return
+			}
+			w.Write([]byte(`{"id": "idc_ingest_new_items", "status": "Idle", "total": 10, "imported": 10, "unprocessed": 0}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := &RestRunner{BaseUrl: server.URL + "/", Username: "admin", Password: "password"}
+	status, err := Run(r, "idc_ingest_new_items", time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, 10, status.Imported)
+	assert.Equal(t, 3, polls)
+
+	status, err = Run(r, "idc_ingest_new_items", 15*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, "Importing", status.Status)
+
+	_, err = Run(r, "idc_unknown", time.Second)
+	assert.NotNil(t, err)
+	_, err = (&RestRunner{BaseUrl: server.URL}).Status("idc_ingest_new_items")
+	assert.NotNil(t, err)
+}
+
+// A fake drush, which imports in the background by writing the status answered by migrate:status
+const fakeDrush = `#!/bin/sh
+dir=$(dirname "$0")
+case "$1" in
+migrate:import)
+	[ "$2" = "idc_broken" ] && { echo "Migration idc_broken failed" >&2; exit 1; }
+	sleep 0.1
+	echo '[{"id": "'$2'", "status": "Idle", "total": "2", "imported": "2", "unprocessed": "0"}]' > "$dir/status.json"
+	;;
+migrate:status)
+	cat "$dir/status.json"
+	;;
+migrate:messages)
+	if [ "$2" = "idc_broken" ]; then
+		echo '[{"level": "1", "source_ids": "io_2", "destination_ids": "", "message": "Missing required field title"}]'
+	fi
+	;;
+esac
+`
+
+func Test_DrushRunner(t *testing.T) {
+	defer fastPolling()()
+	dir := t.TempDir()
+	drush := filepath.Join(dir, "drush")
+	require.Nil(t, ioutil.WriteFile(drush, []byte(fakeDrush), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "status.json"), []byte(`[{"id": "idc_ingest_media", "status": "Idle", "total": "2", "imported": "0", "unprocessed": "2"}]`), 0644))
+
+	r := &DrushRunner{Command: []string{"sh"}, Drush: drush, Uri: "https://islandora-idc.traefik.me"}
+	assert.Equal(t, []string{"sh", drush, "migrate:status", "idc_ingest_media", "--uri=https://islandora-idc.traefik.me"}, r.command("migrate:status", "idc_ingest_media").Args)
+
+	status, err := Run(r, "idc_ingest_media", 5*time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, 2, status.Imported)
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "status.json"), []byte(`[{"id": "idc_broken", "status": "Idle", "total": "1", "unprocessed": "1"}]`), 0644))
+	_, err = Run(r, "idc_broken", 5*time.Second)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Migration idc_broken failed")
+
+	_, err = r.Status("idc_unknown")
+	assert.NotNil(t, err)
+
+	messages, err := r.Messages("idc_broken")
+	require.Nil(t, err)
+	assert.Equal(t, []Message{{Level: 1, SourceIds: "io_2", Message: "Missing required field title"}}, messages)
+	messages, err = r.Messages("idc_ingest_media")
+	require.Nil(t, err)
+	assert.Empty(t, messages)
+}
+
+func Test_Messages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/migrate_api/idc_ingest_new_items/messages", r.URL.Path)
+		_, _ = w.Write([]byte(`[{"level": 1, "source_ids": ["io_1", "en"], "message": "Invalid date"}, {"level": 2, "source_ids": {"local_id": "io_3"}, "message": "Unknown genre"}]`))
+	}))
+	defer server.Close()
+
+	r := &RestRunner{BaseUrl: server.URL}
+	messages, err := r.Messages("idc_ingest_new_items")
+	require.Nil(t, err)
+	assert.Equal(t, []Message{{Level: 1, SourceIds: "io_1,en", Message: "Invalid date"}, {Level: 2, SourceIds: "io_3", Message: "Unknown genre"}}, messages)
+	assert.Equal(t, "io_1,en: Invalid date\nio_3: Unknown genre", FormatMessages(messages))
+
+	assert.True(t, AssertSucceeded(t, r, &Status{Id: "idc_ingest_new_items", Status: Idle, Total: 2, Imported: 2}))
+	mockT := &failures{}
+	assert.False(t, AssertSucceeded(mockT, r, &Status{Id: "idc_ingest_new_items", Status: Idle, Total: 3, Imported: 1, Failed: 2}))
+	assert.Contains(t, mockT.messages, "io_3: Unknown genre")
+}
+
+// Records the failures of assertions
+type failures struct {
+	messages string
+}
+
+func (f *failures) Errorf(format string, args ...interface{}) {
+	f.messages += fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The paths of the endpoints used by a RestRunner if it names none; `%s` is replaced by the migration id
+const (
+	DefaultImportPath = "/migrate_api/%s/import"
+	DefaultStatusPath = "/migrate_api/%s/status"
+)
+
+// Runs migrations using HTTP endpoints exposed by the Drupal site.  The import endpoint is POSTed to, and must answer
+// a 2xx status once the import is triggered.  The status endpoint must answer a JSON Status, e.g.
+// `{"id": "idc_ingest_new_items", "status": "Idle", "total": 10, "imported": 10, "unprocessed": 0}`.
+type RestRunner struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The path of the import endpoint; DefaultImportPath if empty
+	ImportPath string
+	// The path of the status endpoint; DefaultStatusPath if empty
+	StatusPath string
+	// The path of the messages endpoint; DefaultMessagesPath if empty
+	MessagesPath string
+}
+
+// Triggers the import of the migration
+func (r *RestRunner) Import(id string) error {
+	_, err := r.do(http.MethodPost, r.ImportPath, DefaultImportPath, id)
+	return err
+}
+
+// Answers the status of the migration
+func (r *RestRunner) Status(id string) (*Status, error) {
+	body, err := r.do(http.MethodGet, r.StatusPath, DefaultStatusPath, id)
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{}
+	if err := json.Unmarshal(body, status); err != nil {
+		return nil, fmt.Errorf("migrate: unable to unmarshal the status of '%s': %w", id, err)
+	}
+	return status, nil
+}
+
+func (r *RestRunner) do(method, path, defaultPath, id string) ([]byte, error) {
+	if path == "" {
+		path = defaultPath
+	}
+	u := strings.TrimSuffix(r.BaseUrl, "/") + fmt.Sprintf(path, id)
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	if strings.TrimSpace(r.Username) != "" {
+		req.SetBasicAuth(r.Username, r.Password.Reveal())
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: error reading response body from %s: %w", u, err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("migrate: %d status encountered when requesting %s", res.StatusCode, u)
+	}
+	return body, nil
+}
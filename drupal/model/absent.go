@@ -0,0 +1,44 @@
+package model
+
+import "encoding/json"
+
+// The fixture key marking a fixture as an ExpectedAbsent
+const AbsentKey = "absent"
+
+// Expects that no entity of the type and bundle matches the filter, e.g. that a withdrawn item was excluded by a
+// migration, or that content was removed by a cleanup.  The filter is a field and value, e.g. `title` and
+// `Withdrawn Letter`; an empty Filter filters on the `title` of nodes and the `name` of other entities.
+//
+// As JSON, an ExpectedAbsent carries `"absent": true`, distinguishing it from fixtures of entities that must exist:
+//
+//	{"type": "node", "bundle": "islandora_object", "absent": true, "filter": "title", "value": "Withdrawn Letter"}
+type ExpectedAbsent struct {
+	Expected
+	Filter string `json:"filter,omitempty"`
+	Value  string `json:"value"`
+}
+
+// Answers the field filtered on, defaulting according to the entity type
+func (e ExpectedAbsent) Field() string {
+	if e.Filter != "" {
+		return e.Filter
+	}
+	if e.Type == Node {
+		return "title"
+	}
+	return "name"
+}
+
+// Answers the value filtered on
+func (e ExpectedAbsent) NameOrTitle() string {
+	return e.Value
+}
+
+// Marshals the ExpectedAbsent, marked by AbsentKey
+func (e ExpectedAbsent) MarshalJSON() ([]byte, error) {
+	type plain ExpectedAbsent
+	return json.Marshal(struct {
+		plain
+		Absent bool `json:"absent"`
+	}{plain(e), true})
+}
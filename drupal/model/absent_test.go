@@ -0,0 +1,25 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExpectedAbsent(t *testing.T) {
+	a := ExpectedAbsent{Expected: Expected{Type: Node, Bundle: RepositoryObject}, Value: "Withdrawn Letter"}
+	assert.Equal(t, "title", a.Field())
+	assert.Equal(t, "Withdrawn Letter", a.NameOrTitle())
+
+	b, err := json.Marshal(a)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"type": "node", "bundle": "islandora_object", "absent": true, "value": "Withdrawn Letter"}`, string(b))
+
+	term := ExpectedAbsent{}
+	require.Nil(t, json.Unmarshal([]byte(`{"type": "taxonomy_term", "bundle": "genre", "absent": true, "value": "Posters"}`), &term))
+	assert.Equal(t, "name", term.Field())
+	term.Filter = "field_unique_id"
+	assert.Equal(t, "field_unique_id", term.Field())
+}
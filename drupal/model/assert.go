@@ -0,0 +1,58 @@
+//go:build !notestify
+// +build !notestify
+
+package model
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// Resolve the reference of the data object, useful for references appearing within JSON API `relationships`.  This
+// function formulates a JSON API query based on the type, bundle, and unique identifier of the object, and returns
+// exactly one resource.
+func (jad *JsonApiData) Resolve(t *testing.T, v interface{}) {
+	u := jsonapi.JsonApiUrl{
+		T: t,
+		// TODO FIXME the BaseUrl won't work as expected. Really the caller wants the BaseUrl that was used to retrieve
+		//   the JsonApiData, which means we really need access to the JSON API 'links' object and use the 'self' href.
+		//   But we can't do that easily right now.
+		BaseUrl:      env.BaseUrlOr("https://islandora-idc.traefik.me"),
+		DrupalEntity: jad.Type.Entity(),
+		DrupalBundle: jad.Type.Bundle(),
+		Filter:       "id",
+		Value:        jad.Id,
+	}
+
+	u.GetSingle(v)
+}
+
+// ResolveWithBasicAuth behaves as Resolve, but issues the request with HTTP Basic Auth, using the supplied username and
+// password
+func (jad *JsonApiData) ResolveWithBasicAuth(t *testing.T, v interface{}, username string, password string) {
+	u := jsonapi.JsonApiUrl{
+		T: t,
+		// TODO FIXME the BaseUrl won't work as expected. Really the caller wants the BaseUrl that was used to retrieve
+		//   the JsonApiData, which means we really need access to the JSON API 'links' object and use the 'self' href.
+		//   But we can't do that easily right now.
+		BaseUrl:      env.BaseUrlOr("https://islandora-idc.traefik.me"),
+		DrupalEntity: jad.Type.Entity(),
+		DrupalBundle: jad.Type.Bundle(),
+		Filter:       "id",
+		Value:        jad.Id,
+		Username:     username,
+		Password:     env.Secret(password),
+	}
+
+	u.GetSingle(v)
+}
+
+// Answers the language code of the value string by resolving the Language Taxonomy entity identified in the
+// JsonApiLanguageValue
+func (lv JsonApiLanguageValue) LangCode(t *testing.T) string {
+	jsonApiLang := JsonApiLanguage{}
+	lv.Resolve(t, &jsonApiLang)
+	return jsonApiLang.JsonApiData[0].JsonApiAttributes.LanguageCode
+}
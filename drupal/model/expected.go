@@ -19,18 +19,18 @@ type NamedOrTitled interface {
 }
 
 type Expected struct {
-	Type   string
-	Bundle string
+	Type   string `json:"type"`
+	Bundle string `json:"bundle"`
 }
 
 type ExpectedWithName struct {
 	Expected
-	Name string
+	Name string `json:"name"`
 }
 
 type ExpectedWithTitle struct {
 	Expected
-	Title string
+	Title string `json:"title"`
 }
 
 func (e Expected) EntityType() string {
@@ -57,36 +57,100 @@ func (e ExpectedWithTitle) Field() string {
 	return "title"
 }
 
+// Represents the translated name and description of a taxonomy term in a language other than the site default
+type ExpectedTermTranslation struct {
+	// The language code of the translation, e.g. 'es'
+	Langcode    string `json:"langcode"`
+	Name        string `json:"name"`
+	Description struct {
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
+}
+
+// Represents the translated title (or name) and description of an entity in a language other than the site default
+type ExpectedTranslation struct {
+	// The language code of the translation, e.g. 'es'
+	Langcode string `json:"langcode"`
+	// The title of a node, or the name of another entity
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// Translated entities carry the expected translations of their name and description, in addition to the values of the
+// site default language
+type Translated interface {
+	NamedOrTitled
+	// The expected translations, which may be empty
+	TermTranslations() []ExpectedTermTranslation
+}
+
+// Embedded by Expected taxonomy terms which may be translated
+type ExpectedTranslations struct {
+	Translations []ExpectedTermTranslation `json:"translations,omitempty"`
+}
+
+func (e ExpectedTranslations) TermTranslations() []ExpectedTermTranslation {
+	return e.Translations
+}
+
+// Embedded by Expected content nodes (collections and repository objects), carrying the core flags of a node.  A
+// migrated node must not be promoted to the front page, so unless a fixture carries these flags they are verified
+// against the expected values of its bundle (see verify.DefaultNodeCore).
+type ExpectedNodeCore struct {
+	// Whether the node is promoted to the front page
+	Promote *bool `json:"promote,omitempty"`
+	// Whether the node is sticky at the top of lists
+	Sticky *bool `json:"sticky,omitempty"`
+}
+
+// Answers the flags carried by the node, keyed by fixture key (e.g. `promote`)
+func (e ExpectedNodeCore) Flags() map[string]interface{} {
+	flags := map[string]interface{}{}
+	if e.Promote != nil {
+		flags["promote"] = *e.Promote
+	}
+	if e.Sticky != nil {
+		flags["sticky"] = *e.Sticky
+	}
+	return flags
+}
+
 // Represents the expected results of a migrated person
 type ExpectedPerson struct {
 	ExpectedWithName
+	ExpectedTranslations
 	UniqueId    string   `json:"unique_id"`
 	PrimaryName string   `json:"primary_name"`
 	RestOfName  []string `json:"rest_of_name"`
 	FullerForm  []string `json:"fuller_form"`
-	Prefix      []string
-	Suffix      []string
-	Number      []string
+	Prefix      []string `json:"prefix"`
+	Suffix      []string `json:"suffix"`
+	Number      []string `json:"number"`
 	AltName     []string `json:"alt_name"`
-	Date        []string
-	Knows       []string
+	Date        []string `json:"date"`
+	Knows       []string `json:"knows"`
 	Authority   []struct {
-		Uri  string
-		Name string
-		Type string
-	}
+		Uri  string `json:"uri"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated repository object
 type ExpectedRepoObj struct {
 	ExpectedWithTitle
-	UniqueId         string `json:"unique_id"`
-	Abstract         []LanguageString
+	ExpectedNodeCore
+	UniqueId string `json:"unique_id"`
+	// The legacy Islandora 7 PID, e.g. `islandora:1234`
+	Pid              string           `json:"pid,omitempty"`
+	Abstract         []LanguageString `json:"abstract"`
 	AccessRights     []string         `json:"access_rights"`
 	AltTitle         []LanguageString `json:"alt_title"`
 	CollectionNumber []string         `json:"collection_number"`
@@ -94,294 +158,311 @@ type ExpectedRepoObj struct {
 	CopyrightHolder  []string         `json:"copyright_holder"`
 	Contributor      []struct {
 		RelType string `json:"rel_type"`
-		Name    string
-	}
+		Name    string `json:"name"`
+	} `json:"contributor"`
 	Creator []struct {
 		RelType string `json:"rel_type"`
-		Name    string
-	}
-	CustodialHistory  []LanguageString `json:"custodial_history"`
-	DateAvailable     string           `json:"date_available"`
-	DateCopyrighted   []string         `json:"date_copyrighted"`
-	DateCreated       []string         `json:"date_created"`
-	DatePublished     []string         `json:"date_published"`
-	DigitalIdentifier []string         `json:"digital_identifier"`
-	DigitalPublisher  []string         `json:"digital_publisher"`
-	DisplayHint       string           `json:"display_hints"`
-	DspaceIdentifier  string           `json:"dspace_identifier"`
-	DspaceItemId      string           `json:"dspace_itemid"`
-	Extent            []string
-	FeaturedItem      bool `json:"featured_item"`
-	FindingAid        []struct {
-		Uri   string
-		Title string
-	} `json:"finding_aid"`
-	Genre              []string
-	GeoportalLink      string   `json:"geoportal_link"`
-	AccessTerms        []string `json:"access_terms"`
-	Issn               string
-	IsPartOf           string   `json:"is_part_of"`
-	ItemBarcode        []string `json:"item_barcode"`
-	JhirUri            string   `json:"jhir"`
-	LibraryCatalogLink []string `json:"catalog_link"`
+		Name    string `json:"name"`
+	} `json:"creator"`
+	CustodialHistory   []LanguageString `json:"custodial_history"`
+	DateAvailable      string           `json:"date_available"`
+	DateCopyrighted    []string         `json:"date_copyrighted"`
+	DateCreated        []string         `json:"date_created"`
+	DatePublished      []string         `json:"date_published"`
+	DigitalIdentifier  []string         `json:"digital_identifier"`
+	DigitalPublisher   []string         `json:"digital_publisher"`
+	DisplayHint        string           `json:"display_hints"`
+	DspaceIdentifier   string           `json:"dspace_identifier"`
+	DspaceItemId       string           `json:"dspace_itemid"`
+	Extent             []string         `json:"extent"`
+	FeaturedItem       bool             `json:"featured_item"`
+	FindingAid         []Link           `json:"finding_aid"`
+	Genre              []string         `json:"genre"`
+	GeoportalLink      Link             `json:"geoportal_link"`
+	AccessTerms        []string         `json:"access_terms"`
+	Issn               string           `json:"issn"`
+	IsPartOf           string           `json:"is_part_of"`
+	ItemBarcode        []string         `json:"item_barcode"`
+	JhirUri            string           `json:"jhir"`
+	LibraryCatalogLink []Link           `json:"catalog_link"`
 	Model              struct {
-		Name        string
+		Name        string `json:"name"`
 		ExternalUri string `json:"external_uri"`
-	}
-	OclcNumber       []string `json:"oclc_number"`
-	Publisher        []string
-	PublisherCountry []string `json:"publisher_country"`
-	ResourceType     []string `json:"resource_type"`
-	SpatialCoverage  []string `json:"spatial_coverage"`
-	Subject          []string
+	} `json:"model"`
+	OclcNumber       []string         `json:"oclc_number"`
+	Publisher        []string         `json:"publisher"`
+	PublisherCountry []string         `json:"publisher_country"`
+	ResourceType     []string         `json:"resource_type"`
+	SpatialCoverage  []string         `json:"spatial_coverage"`
+	Subject          []string         `json:"subject"`
 	TableOfContents  []LanguageString `json:"toc"`
 	MemberOf         string           `json:"member_of"`
 	LinkedAgent      []struct {
-		Rel  string
-		Name string
-	}
-	Description []struct {
-		Value    string
-		LangCode string `json:"language"`
-	}
-	Weight int `json:"weight"`
+		Rel  string `json:"rel"`
+		Name string `json:"name"`
+	} `json:"linked_agent"`
+	Description []LanguageString `json:"description"`
+	Weight      int              `json:"weight"`
+	// The values of the fields registered for repository objects (see RegisterField)
+	Extra ExtraFields `json:"-"`
 }
 
 // Represents the expected results of a migrated Access Rights taxonomy term
 type ExpectedAccessRights struct {
 	ExpectedWithName
-	UniqueId  string `json:"unique_id"`
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	ExpectedTranslations
+	UniqueId    string      `json:"unique_id"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Islandora Access Terms taxonomy term
 type ExpectedIslandoraAccessTerms struct {
 	ExpectedWithName
+	ExpectedTranslations
 	UniqueId    string   `json:"unique_id"`
 	Parent      []string `json:"parent"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Copyright and Use taxonomy term
 type ExpectedCopyrightAndUse struct {
 	ExpectedWithName
-	UniqueId  string `json:"unique_id"`
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	ExpectedTranslations
+	UniqueId    string      `json:"unique_id"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Family taxonomy term
 type ExpectedFamily struct {
 	ExpectedWithName
-	UniqueId   string `json:"unique_id"`
-	Date       []string
-	FamilyName string `json:"family_name"`
-	Title      string
-	Authority  []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	ExpectedTranslations
+	UniqueId    string      `json:"unique_id"`
+	Date        []string    `json:"date"`
+	FamilyName  string      `json:"family_name"`
+	Title       string      `json:"title"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 	KnowsAbout []string `json:"knowsAbout"`
 }
 
 // Represents the expected results of a migrated Genre taxonomy term
 type ExpectedGenre struct {
 	ExpectedWithName
-	UniqueId  string `json:"unique_id"`
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	ExpectedTranslations
+	UniqueId    string      `json:"unique_id"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Geolocation taxonomy term
 type ExpectedGeolocation struct {
 	ExpectedWithName
+	ExpectedTranslations
 	UniqueId   string   `json:"unique_id"`
 	GeoAltName []string `json:"geo_alt_name"`
-	Broader    []struct {
-		Uri   string
-		Title string
-	}
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	// The point of the location, as WKT (e.g. `POINT (-76.62 39.33)`) or as an object carrying `lat` and `lon`
+	Coordinates interface{} `json:"coordinates,omitempty"`
+	Broader     []struct {
+		Uri   string `json:"uri"`
+		Title string `json:"title"`
+	} `json:"broader"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Resource Types taxonomy term
 type ExpectedResourceType struct {
 	ExpectedWithName
-	UniqueId  string `json:"unique_id"`
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	ExpectedTranslations
+	UniqueId    string      `json:"unique_id"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Subject taxonomy term
 type ExpectedSubject struct {
 	ExpectedWithName
-	UniqueId  string `json:"unique_id"`
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
+	ExpectedTranslations
+	UniqueId    string      `json:"unique_id"`
+	Authority   []Authority `json:"authority"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Language taxonomy term
 type ExpectedLanguage struct {
 	ExpectedWithName
-	UniqueId     string `json:"unique_id"`
-	LanguageCode string `json:"language_code"`
-	Authority    []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	ExpectedTranslations
+	UniqueId     string      `json:"unique_id"`
+	LanguageCode string      `json:"language_code"`
+	Authority    []Authority `json:"authority"`
+	Description  struct {
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
 }
 
 // Represents the expected results of a migrated Collection entity
 type ExpectedCollection struct {
 	ExpectedWithTitle
-	UniqueId      string `json:"unique_id"`
+	ExpectedNodeCore
+	UniqueId string `json:"unique_id"`
+	// The legacy Islandora 7 PID, e.g. `islandora:1234`
+	Pid           string `json:"pid,omitempty"`
 	TitleLangCode string `json:"title_language"`
 	AltTitle      []struct {
-		Value    string
+		Value    string `json:"value"`
 		LangCode string `json:"language"`
 	} `json:"alternative_title"`
 	Description []struct {
-		Value    string
+		Value    string `json:"value"`
 		LangCode string `json:"language"`
-	}
+	} `json:"description"`
 	ContactEmail     string   `json:"contact_email"`
 	ContactName      string   `json:"contact_name"`
 	CollectionNumber []string `json:"collection_number"`
 	MemberOf         string   `json:"member_of"`
 	AccessTerms      []string `json:"access_terms"`
-	FindingAid       []struct {
-		Uri   string
-		Title string
-	} `json:"finding_aid"`
+	FindingAid       []Link   `json:"finding_aid"`
+	// The values of the fields registered for collections (see RegisterField)
+	Extra ExtraFields `json:"-"`
 }
 
 // Represents the expected results of a migrated Corporate Body taxonomy term
 type ExpectedCorporateBody struct {
 	ExpectedWithName
+	ExpectedTranslations
 	UniqueId    string `json:"unique_id"`
 	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
-	PrimaryName     string   `json:"primary_name"`
-	SubordinateName []string `json:"subordinate_name"`
-	DateOfMeeting   []string `json:"date_of_meeting_or_treaty"`
-	Location        []string `json:"location_of_meeting"`
-	NumberOrSection []string `json:"num_of_section_or_meet"`
-	AltName         []string `json:"corporate_body_alternate_name"`
-	Authority       []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Date         []string
-	Relationship []struct {
-		Name string
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
+	} `json:"description"`
+	PrimaryName     string      `json:"primary_name"`
+	SubordinateName []string    `json:"subordinate_name"`
+	DateOfMeeting   []string    `json:"date_of_meeting_or_treaty"`
+	Location        []string    `json:"location_of_meeting"`
+	NumberOrSection []string    `json:"num_of_section_or_meet"`
+	AltName         []string    `json:"corporate_body_alternate_name"`
+	Authority       []Authority `json:"authority"`
+	Date            []string    `json:"date"`
+	Relationship    []struct {
+		Name string `json:"name"`
 		Rel  string `json:"rel_type"`
 	} `json:"relationships"`
 }
 
 type LanguageString struct {
-	Value    string
+	Value    string `json:"value"`
 	LangCode string `json:"language"`
+	// The hex-encoded SHA-256 of the normalized value (see verify.TextSha256), which may be supplied instead of Value
+	// so that very large values (e.g. a table of contents) need not be carried by the fixture
+	Sha256 string `json:"sha256,omitempty"`
 }
 
 type ExpectedMediaGeneric struct {
 	ExpectedWithName
-	UniqueId     string `json:"unique_id"`
-	OriginalName string `json:"original_name"`
-	Size         int
+	UniqueId     string   `json:"unique_id"`
+	OriginalName string   `json:"original_name"`
+	Size         int      `json:"size"`
 	MimeType     string   `json:"mime_type"`
 	AccessTerms  []string `json:"access_terms"`
 	MediaUse     []string `json:"use"`
 	MediaOf      string   `json:"media_of"`
 	Uri          struct {
-		Url   string
-		Value string
-	}
+		Url   string `json:"url"`
+		Value string `json:"value"`
+	} `json:"uri"`
 	RestrictedAccess bool `json:"restricted_access"`
 }
 
 type ExpectedMediaImage struct {
 	ExpectedMediaGeneric
 	AltText string `json:"alt_text"`
-	Height  int
-	Width   int
+	Height  int    `json:"height"`
+	Width   int    `json:"width"`
+}
+
+// Represents the expected results of a migrated Audio media.  The file URI, mime type and media use are provided by
+// ExpectedMediaGeneric.
+type ExpectedMediaAudio struct {
+	ExpectedMediaGeneric
+	Duration string `json:"duration"`
+}
+
+// Represents the expected results of a migrated Video media.  The file size, URI, and mime type of the video file are
+// provided by ExpectedMediaGeneric.
+type ExpectedMediaVideo struct {
+	ExpectedMediaGeneric
+	Height   int             `json:"height"`
+	Width    int             `json:"width"`
+	Duration string          `json:"duration"`
+	Tracks   []ExpectedTrack `json:"tracks"`
+}
+
+// Represents the expected caption, subtitle, or description track file associated with a Video media
+type ExpectedTrack struct {
+	// The kind of track, e.g. 'captions', 'subtitles', or 'descriptions'
+	Kind     string `json:"kind"`
+	Label    string `json:"label"`
+	LangCode string `json:"language"`
+	MimeType string `json:"mime_type"`
+	Uri      struct {
+		Url   string `json:"url"`
+		Value string `json:"value"`
+	} `json:"uri"`
+}
+
+// Represents the expected results of FITS technical metadata media generated for a repository object.  The file size,
+// mime type, and media use of the FITS XML file are provided by ExpectedMediaGeneric.
+type ExpectedMediaFits struct {
+	ExpectedMediaGeneric
+	// The checksum of the characterized file as reported by FITS, e.g. the value of the `md5checksum` element
+	Checksum string `json:"checksum"`
+	// The algorithm used to compute the checksum, e.g. 'md5'
+	ChecksumAlgorithm string `json:"checksum_algorithm"`
 }
 
 type ExpectedMediaExtractedText struct {
 	ExpectedMediaGeneric
 	ExtractedText struct {
-		Value     string
-		Format    string
-		Processed string
+		Value     string `json:"value"`
+		Format    string `json:"format"`
+		Processed string `json:"processed"`
 	} `json:"extracted_text"`
 }
 
@@ -392,3 +473,50 @@ type ExpectedMediaRemoteVideo struct {
 	RestrictedAccess bool   `json:"restricted_access"`
 	UniqueId         string `json:"unique_id"`
 }
+
+// Represents the expected results of a migrated Drupal user account, e.g. one mapped to an LDAP identity.  Users have
+// no bundle, so Bundle is expected to be 'user'.
+type ExpectedUser struct {
+	ExpectedWithName
+	Mail string `json:"mail"`
+	// True if the account is active, false if it is blocked
+	Status bool `json:"status"`
+	// The machine names of the roles granted to the user, e.g. 'fedoraadmin'
+	Roles    []string `json:"roles"`
+	Timezone string   `json:"timezone"`
+}
+
+// Represents the expected results of a Drupal user role.  Roles have no bundle, so Bundle is expected to be
+// 'user_role'.
+type ExpectedRole struct {
+	Expected
+	// The machine name of the role, e.g. 'fedoraadmin'
+	Id          string   `json:"id"`
+	Label       string   `json:"label"`
+	IsAdmin     bool     `json:"is_admin"`
+	Permissions []string `json:"permissions"`
+}
+
+// Represents the expected results of a Drupal file entity, e.g. the file of a media.  Media-level checks compare the
+// size and mime type recorded by a media, but a file entity may be left temporary (and deleted by cron) or carry a
+// different mime type than its media.  Files have neither a name nor a title, and are matched by their filename.
+type ExpectedFile struct {
+	Expected
+	Filename string `json:"filename"`
+	Uri      struct {
+		Url   string `json:"url"`
+		Value string `json:"value"`
+	} `json:"uri"`
+	MimeType string `json:"filemime"`
+	FileSize int    `json:"filesize"`
+	// True if the file is permanent, false if it is temporary
+	Status bool `json:"status"`
+}
+
+func (e ExpectedFile) NameOrTitle() string {
+	return e.Filename
+}
+
+func (e ExpectedFile) Field() string {
+	return "filename"
+}
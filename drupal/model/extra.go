@@ -0,0 +1,243 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// A field is registered under a key the bundle already has
+var ErrDuplicateField = errors.New("field is already registered")
+
+// Derives the value of a fixture key from a live JSON API resource; created by FromAttribute, FromLinkUri, FromName,
+// FromNames, FromTypedNames, FromLanguageValues, or FromResource
+type FieldGenerator struct {
+	// The attribute or relationship the value is derived from, if known
+	source string
+	value  func(g *generator, r resource) (interface{}, error)
+}
+
+// Copies the value of the JSON API attribute (e.g. `field_rights_statement`) as-is
+func FromAttribute(attribute string) FieldGenerator {
+	return FieldGenerator{attribute, attr("", attribute).value}
+}
+
+// Answers the uri of the link attribute, or the uris of a multi-valued link attribute
+func FromLinkUri(attribute string) FieldGenerator {
+	return FieldGenerator{attribute, linkUri("", attribute).value}
+}
+
+// Resolves the single-valued relationship to the name or title of the referenced entity
+func FromName(relationship string) FieldGenerator {
+	return FieldGenerator{relationship, name("", relationship).value}
+}
+
+// Resolves the multi-valued relationship to the names or titles of the referenced entities
+func FromNames(relationship string) FieldGenerator {
+	return FieldGenerator{relationship, names("", relationship).value}
+}
+
+// Resolves the typed relation to the relationship type and name of each referenced entity, as e.g. `creator` is
+func FromTypedNames(relationship string) FieldGenerator {
+	return FieldGenerator{relationship, typedNames("", relationship).value}
+}
+
+// Resolves the relationship to language taxonomy terms into LanguageString values, as e.g. `abstract` is
+func FromLanguageValues(relationship string) FieldGenerator {
+	return FieldGenerator{relationship, languageValues("", relationship).value}
+}
+
+// Derives the value using the function, which is supplied the JSON API data element of the live resource, carrying
+// its `attributes` and `relationships`
+func FromResource(f func(data map[string]interface{}) (interface{}, error)) FieldGenerator {
+	return FieldGenerator{"", func(g *generator, r resource) (interface{}, error) {
+		return f(r)
+	}}
+}
+
+// A fixture key of a field of a site's own, which the Expected structs of this module do not model (see
+// RegisterField)
+type ExtraField struct {
+	// The fixture key, e.g. `rights_statement`
+	Key string
+	// Derives the value of the key from the live entity
+	Generate FieldGenerator
+	// Answers a pointer to a new value the key is decoded into (e.g. `func() interface{} { return &[]string{} }`), so
+	// that ExtraFields carry typed values.  If nil, values are decoded as generic JSON, e.g. []interface{}.
+	New func() interface{}
+}
+
+// The values of the registered fields (see RegisterField) carried by a fixture, keyed by fixture key
+type ExtraFields map[string]interface{}
+
+var (
+	extraMu     sync.RWMutex
+	extraFields = map[string][]ExtraField{}
+)
+
+// Registers a field of the fixtures of the entity type and bundle (e.g. `node` and `islandora_object`), so that a site
+// may generate and verify fields of its own without forking this module:
+//
+//	func init() {
+//		model.MustRegisterField(model.Node, model.RepositoryObject, model.ExtraField{
+//			Key: "rights_statement", Generate: model.FromName("field_rights_statement")})
+//	}
+//
+// The keys of registered fields are generated by Generate and GenerateFixture, and so are verified by verify.Engine
+// like any other key.  ExpectedRepoObj and ExpectedCollection carry their values in Extra; the Expected structs of
+// other bundles do not carry them.  An error is answered if the bundle is not Generatable, if the key is empty, or if
+// the bundle already has the key.
+func RegisterField(entityType, bundle string, f ExtraField) error {
+	b, ok := generators[entityType+"--"+bundle]
+	if !ok {
+		return fmt.Errorf("%w: %s--%s", ErrUnsupported, entityType, bundle)
+	}
+	if f.Key == "" || f.Generate.value == nil {
+		return fmt.Errorf("a field of %s--%s must have a key and a generator", entityType, bundle)
+	}
+
+	extraMu.Lock()
+	defer extraMu.Unlock()
+	for _, k := range builtinKeys(b) {
+		if k == f.Key {
+			return fmt.Errorf("%w: %s--%s has a built-in '%s'", ErrDuplicateField, entityType, bundle, f.Key)
+		}
+	}
+	for _, registered := range extraFields[entityType+"--"+bundle] {
+		if registered.Key == f.Key {
+			return fmt.Errorf("%w: %s--%s already has '%s'", ErrDuplicateField, entityType, bundle, f.Key)
+		}
+	}
+	extraFields[entityType+"--"+bundle] = append(extraFields[entityType+"--"+bundle], f)
+	return nil
+}
+
+// MustRegisterField behaves as RegisterField, but panics if the field cannot be registered, e.g. from init
+func MustRegisterField(entityType, bundle string, f ExtraField) {
+	if err := RegisterField(entityType, bundle, f); err != nil {
+		panic(err)
+	}
+}
+
+// Answers the fields registered for the entity type and bundle, in the order they were registered
+func RegisteredFields(entityType, bundle string) []ExtraField {
+	extraMu.RLock()
+	defer extraMu.RUnlock()
+	return append([]ExtraField(nil), extraFields[entityType+"--"+bundle]...)
+}
+
+// Removes the fields registered for the entity type and bundle, e.g. when a test completes
+func UnregisterFields(entityType, bundle string) {
+	extraMu.Lock()
+	defer extraMu.Unlock()
+	delete(extraFields, entityType+"--"+bundle)
+}
+
+// Marshals the repository object, with the values of its registered fields
+func (e ExpectedRepoObj) MarshalJSON() ([]byte, error) {
+	type plain ExpectedRepoObj
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// Unmarshals the repository object, decoding the values of its registered fields into Extra
+func (e *ExpectedRepoObj) UnmarshalJSON(b []byte) error {
+	type plain ExpectedRepoObj
+	if err := json.Unmarshal(b, (*plain)(e)); err != nil {
+		return err
+	}
+	var err error
+	e.Extra, err = unmarshalExtra(b, Node, RepositoryObject)
+	return err
+}
+
+// Marshals the collection, with the values of its registered fields
+func (e ExpectedCollection) MarshalJSON() ([]byte, error) {
+	type plain ExpectedCollection
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// Unmarshals the collection, decoding the values of its registered fields into Extra
+func (e *ExpectedCollection) UnmarshalJSON(b []byte) error {
+	type plain ExpectedCollection
+	if err := json.Unmarshal(b, (*plain)(e)); err != nil {
+		return err
+	}
+	var err error
+	e.Extra, err = unmarshalExtra(b, Node, Collection)
+	return err
+}
+
+// Answers the keys the blueprint generates, and those of the JSON representation of its Expected struct
+func builtinKeys(b blueprint) []string {
+	keys := []string{"type", "bundle"}
+	for _, f := range b.fields {
+		keys = append(keys, f.key)
+	}
+	if j, err := json.Marshal(b.new()); err == nil {
+		m := map[string]json.RawMessage{}
+		if json.Unmarshal(j, &m) == nil {
+			for k := range m {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// Answers the JSON object of the value, with the extra fields added to its keys
+func marshalExtra(v interface{}, extra ExtraFields) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, x := range extra {
+		if _, builtin := m[k]; builtin {
+			continue
+		}
+		if m[k], err = json.Marshal(x); err != nil {
+			return nil, fmt.Errorf("unable to marshal '%s': %w", k, err)
+		}
+	}
+	return json.Marshal(m)
+}
+
+// Answers the values of the fields registered for the entity type and bundle that the JSON object carries, or nil if
+// it carries none
+func unmarshalExtra(b []byte, entityType, bundle string) (ExtraFields, error) {
+	fields := RegisteredFields(entityType, bundle)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	var extra ExtraFields
+	for _, f := range fields {
+		raw, ok := m[f.Key]
+		if !ok {
+			continue
+		}
+		var v interface{}
+		if f.New != nil {
+			p := f.New()
+			if err := json.Unmarshal(raw, p); err != nil {
+				return nil, fmt.Errorf("unable to unmarshal '%s': %w", f.Key, err)
+			}
+			v = reflect.ValueOf(p).Elem().Interface()
+		} else if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal '%s': %w", f.Key, err)
+		}
+		if extra == nil {
+			extra = ExtraFields{}
+		}
+		extra[f.Key] = v
+	}
+	return extra, nil
+}
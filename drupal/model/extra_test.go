@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegisterField(t *testing.T) {
+	defer UnregisterFields(Node, RepositoryObject)
+
+	require.Nil(t, RegisterField(Node, RepositoryObject, ExtraField{Key: "rights_subject", Generate: FromNames("field_subject")}))
+	assert.True(t, errors.Is(RegisterField(Node, RepositoryObject, ExtraField{Key: "rights_subject", Generate: FromNames("field_subject")}), ErrDuplicateField))
+	assert.True(t, errors.Is(RegisterField(Node, RepositoryObject, ExtraField{Key: "genre", Generate: FromNames("field_genre")}), ErrDuplicateField))
+	assert.True(t, errors.Is(RegisterField(Node, RepositoryObject, ExtraField{Key: "promote", Generate: FromAttribute("promote")}), ErrDuplicateField))
+	assert.True(t, errors.Is(RegisterField(Node, "page", ExtraField{Key: "body", Generate: FromAttribute("body")}), ErrUnsupported))
+	assert.NotNil(t, RegisterField(Node, RepositoryObject, ExtraField{Key: "weightless"}))
+	assert.Panics(t, func() { MustRegisterField(Node, RepositoryObject, ExtraField{}) })
+
+	assert.Equal(t, 1, len(RegisteredFields(Node, RepositoryObject)))
+	assert.Empty(t, RegisteredFields(Node, Collection))
+}
+
+func Test_GenerateExtraFields(t *testing.T) {
+	server := newGenerateServer(t)
+	defer server.Close()
+	defer UnregisterFields(Node, RepositoryObject)
+
+	MustRegisterField(Node, RepositoryObject, ExtraField{Key: "local_subject", Generate: FromNames("field_subject"),
+		New: func() interface{} { return &[]string{} }})
+	MustRegisterField(Node, RepositoryObject, ExtraField{Key: "local_weight", Generate: FromResource(
+		func(data map[string]interface{}) (interface{}, error) {
+			return data["attributes"].(map[string]interface{})["field_weight"], nil
+		})})
+
+	u := &jsonapi.JsonApiUrl{BaseUrl: server.URL, DrupalEntity: Node, DrupalBundle: RepositoryObject, Filter: "title", Value: "Moonrise"}
+	fixture, err := GenerateFixture(u)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Analog Photography"}, fixture["local_subject"])
+	assert.EqualValues(t, 3, fixture["local_weight"])
+
+	expected, err := Generate(u)
+	require.Nil(t, err)
+	obj := expected.(*ExpectedRepoObj)
+	assert.Equal(t, []string{"Analog Photography"}, obj.Extra["local_subject"])
+	assert.Equal(t, float64(3), obj.Extra["local_weight"])
+
+	// extra fields survive a round trip through JSON, alongside the built-in fields
+	b, err := json.Marshal(obj)
+	require.Nil(t, err)
+	assert.Contains(t, string(b), `"local_subject":["Analog Photography"]`)
+	assert.Contains(t, string(b), `"title":"Moonrise"`)
+	roundTripped := &ExpectedRepoObj{}
+	require.Nil(t, json.Unmarshal(b, roundTripped))
+	assert.Equal(t, obj.Extra, roundTripped.Extra)
+	assert.Equal(t, "Moonrise", roundTripped.Title)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"local_subject": "Analog Photography"}`), &ExpectedRepoObj{}))
+
+	// unregistered keys are not carried
+	UnregisterFields(Node, RepositoryObject)
+	plain := &ExpectedRepoObj{}
+	require.Nil(t, json.Unmarshal(b, plain))
+	assert.Nil(t, plain.Extra)
+	assert.Equal(t, "Moonrise", plain.Title)
+}
@@ -0,0 +1,491 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var ErrUnsupported = errors.New("unsupported entity type and bundle")
+
+// Generates a populated Expected struct from a live JSON API resource, so that test fixtures need not be authored by
+// hand.  The supplied JsonApiUrl must match exactly one resource, typically by filtering on `title` or `name`.
+//
+// Relationships (e.g. subjects, genres, or the collection a repository object is a member of) are resolved to the name
+// or title of the referenced entity using the base url and credentials of the supplied JsonApiUrl.  The returned value
+// is a pointer to an Expected struct (e.g. *ExpectedRepoObj or *ExpectedPerson) that may be marshaled to JSON and
+// checked in as a fixture.  Values that cannot be derived from the JSON API are left empty, so generated fixtures
+// ought to be reviewed before they are committed.
+func Generate(u *jsonapi.JsonApiUrl) (ExpectedEntity, error) {
+	fixture, err := GenerateFixture(u)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, _ := NewExpected(u.DrupalEntity, u.DrupalBundle)
+	if j, err := json.Marshal(fixture); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(j, expected); err != nil {
+		return nil, fmt.Errorf("error converting generated fixture to %T: %w", expected, err)
+	}
+	return expected, nil
+}
+
+// Generates the keys and values of an Expected fixture from a live JSON API resource, as Generate does, but answers
+// them as a map rather than an Expected struct.  Only the keys that may be derived from the JSON API are present, so
+// the map is suitable for comparing against the same keys of an authored fixture.  The keys of the fields registered
+// for the bundle (see RegisterField) are present too.
+func GenerateFixture(u *jsonapi.JsonApiUrl) (map[string]interface{}, error) {
+	b, ok := generators[u.DrupalEntity+"--"+u.DrupalBundle]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s--%s", ErrUnsupported, u.DrupalEntity, u.DrupalBundle)
+	}
+
+	res := &jsonapi.JsonApiResponse{}
+	if err := u.FetchSingle(res); err != nil {
+		return nil, err
+	}
+
+	g := &generator{u: u, resolved: map[string]map[string]interface{}{}}
+	r := resource(res.Data[0])
+	fixture := map[string]interface{}{
+		"type":   r.drupalType().Entity(),
+		"bundle": r.drupalType().Bundle(),
+	}
+	for _, f := range b.fields {
+		v, err := f.value(g, r)
+		if err != nil {
+			return nil, fmt.Errorf("error generating '%s' of %s: %w", f.key, r.drupalType(), err)
+		}
+		fixture[f.key] = v
+	}
+	for _, f := range RegisteredFields(u.DrupalEntity, u.DrupalBundle) {
+		v, err := f.Generate.value(g, r)
+		if err != nil {
+			return nil, fmt.Errorf("error generating '%s' of %s: %w", f.Key, r.drupalType(), err)
+		}
+		fixture[f.Key] = v
+	}
+	return fixture, nil
+}
+
+// Answers an empty Expected struct (e.g. *ExpectedRepoObj) for the entity type and bundle, suitable for unmarshaling
+// a fixture into.  Only the types answered by Generatable are supported.
+func NewExpected(entityType, bundle string) (ExpectedEntity, error) {
+	b, ok := generators[entityType+"--"+bundle]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s--%s", ErrUnsupported, entityType, bundle)
+	}
+	return b.new(), nil
+}
+
+// Answers the entity type and bundle combinations supported by Generate, e.g. `node--islandora_object`
+func Generatable() []string {
+	var types []string
+	for k := range generators {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Answers the fixture key generated from each Drupal field (e.g. `field_subject` is the source of `subject`) of the
+// entity type and bundle, including the fields registered for the bundle (see RegisterField) whose source is known
+func SourceFields(entityType, bundle string) (map[string]string, error) {
+	b, ok := generators[entityType+"--"+bundle]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s--%s", ErrUnsupported, entityType, bundle)
+	}
+	sources := map[string]string{}
+	for _, f := range b.fields {
+		sources[f.source] = f.key
+	}
+	for _, f := range RegisteredFields(entityType, bundle) {
+		if f.Generate.source != "" {
+			sources[f.Generate.source] = f.Key
+		}
+	}
+	return sources, nil
+}
+
+// Describes how to generate each key of an Expected fixture from a JSON API resource
+type blueprint struct {
+	new    func() ExpectedEntity
+	fields []fixtureField
+}
+
+// A key of an Expected fixture, the attribute or relationship it is derived from, and the function deriving its value
+// from a JSON API resource
+type fixtureField struct {
+	key    string
+	source string
+	value  func(g *generator, r resource) (interface{}, error)
+}
+
+// Fields shared by the simple taxonomy terms
+var termFields = []fixtureField{
+	attr("name", "name"),
+	attr("unique_id", "field_unique_id"),
+	attr("description", "description"),
+	attr("authority", "field_authority_link"),
+}
+
+// Fields shared by the content nodes
+var nodeCoreFields = []fixtureField{
+	attr("promote", "promote"),
+	attr("sticky", "sticky"),
+}
+
+var generators = map[string]blueprint{
+	Node + "--" + RepositoryObject: {
+		new: func() ExpectedEntity { return &ExpectedRepoObj{} },
+		fields: append([]fixtureField{
+			attr("title", "title"),
+			attr("unique_id", "field_unique_id"),
+			attr("pid", "field_pid"),
+			languageValues("abstract", "field_abstract"),
+			names("access_rights", "field_access_rights"),
+			languageValues("alt_title", "field_alternative_title"),
+			attr("collection_number", "field_collection_number"),
+			name("copyright_and_use", "field_copyright_and_use"),
+			names("copyright_holder", "field_copyright_holder"),
+			typedNames("contributor", "field_contributor"),
+			typedNames("creator", "field_creator"),
+			languageValues("custodial_history", "field_custodial_history"),
+			attr("date_available", "field_date_available"),
+			attr("date_copyrighted", "field_date_copyrighted"),
+			attr("date_created", "field_date_created"),
+			attr("date_published", "field_date_published"),
+			attr("digital_identifier", "field_digital_identifier"),
+			names("digital_publisher", "field_digital_publisher"),
+			name("display_hints", "field_display_hints"),
+			linkUri("dspace_identifier", "field_dspace_identifier"),
+			attr("dspace_itemid", "field_dspace_item_id"),
+			attr("extent", "field_extent"),
+			attr("featured_item", "field_featured_item"),
+			attr("finding_aid", "field_finding_aid"),
+			names("genre", "field_genre"),
+			attr("geoportal_link", "field_geoportal_link"),
+			names("access_terms", "field_access_terms"),
+			attr("issn", "field_issn"),
+			linkUri("is_part_of", "field_is_part_of"),
+			attr("item_barcode", "field_item_barcode"),
+			linkUri("jhir", "field_jhir"),
+			attr("catalog_link", "field_library_catalog_link"),
+			islandoraModel("model", "field_model"),
+			attr("oclc_number", "field_oclc_number"),
+			names("publisher", "field_publisher"),
+			names("publisher_country", "field_publisher_country"),
+			names("resource_type", "field_resource_type"),
+			names("spatial_coverage", "field_spatial_coverage"),
+			names("subject", "field_subject"),
+			languageValues("toc", "field_table_of_contents"),
+			name("member_of", "field_member_of"),
+			languageValues("description", "field_description"),
+			attr("weight", "field_weight"),
+		}, nodeCoreFields...),
+	},
+	Node + "--" + Collection: {
+		new: func() ExpectedEntity { return &ExpectedCollection{} },
+		fields: append([]fixtureField{
+			attr("title", "title"),
+			attr("unique_id", "field_unique_id"),
+			attr("pid", "field_pid"),
+			languageCode("title_language", "field_title_language"),
+			languageValues("alternative_title", "field_alternative_title"),
+			languageValues("description", "field_description"),
+			attr("contact_email", "field_collection_contact_email"),
+			attr("contact_name", "field_collection_contact_name"),
+			attr("collection_number", "field_collection_number"),
+			name("member_of", "field_member_of"),
+			names("access_terms", "field_access_terms"),
+			attr("finding_aid", "field_finding_aid"),
+		}, nodeCoreFields...),
+	},
+	FileEntity + "--" + File: {
+		new: func() ExpectedEntity { return &ExpectedFile{} },
+		fields: []fixtureField{
+			attr("filename", "filename"),
+			attr("uri", "uri"),
+			attr("filemime", "filemime"),
+			attr("filesize", "filesize"),
+			attr("status", "status"),
+		},
+	},
+	TaxonomyTerm + "--" + Person: {
+		new: func() ExpectedEntity { return &ExpectedPerson{} },
+		fields: []fixtureField{
+			attr("name", "name"),
+			attr("unique_id", "field_unique_id"),
+			attr("primary_name", "field_primary_part_of_name"),
+			attr("rest_of_name", "field_preferred_name_rest"),
+			attr("fuller_form", "field_preferred_name_fuller_form"),
+			attr("prefix", "field_preferred_name_prefix"),
+			attr("suffix", "field_preferred_name_suffix"),
+			attr("number", "field_preferred_name_number"),
+			attr("alt_name", "field_person_alternate_name"),
+			attr("date", "field_date"),
+			personAuthority("authority", "field_authority_link"),
+			attr("description", "description"),
+		},
+	},
+	TaxonomyTerm + "--" + Subject: {
+		new:    func() ExpectedEntity { return &ExpectedSubject{} },
+		fields: termFields,
+	},
+	TaxonomyTerm + "--" + Genre: {
+		new:    func() ExpectedEntity { return &ExpectedGenre{} },
+		fields: termFields,
+	},
+	TaxonomyTerm + "--" + ResourceTypes: {
+		new:    func() ExpectedEntity { return &ExpectedResourceType{} },
+		fields: termFields,
+	},
+	TaxonomyTerm + "--" + AccessRights: {
+		new:    func() ExpectedEntity { return &ExpectedAccessRights{} },
+		fields: termFields,
+	},
+	TaxonomyTerm + "--" + CopyrightAndUse: {
+		new:    func() ExpectedEntity { return &ExpectedCopyrightAndUse{} },
+		fields: termFields,
+	},
+	TaxonomyTerm + "--" + Language: {
+		new:    func() ExpectedEntity { return &ExpectedLanguage{} },
+		fields: append([]fixtureField{attr("language_code", "field_language_code")}, termFields...),
+	},
+	TaxonomyTerm + "--" + GeoLocation: {
+		new: func() ExpectedEntity { return &ExpectedGeolocation{} },
+		fields: append([]fixtureField{
+			attr("geo_alt_name", "field_geo_alt_name"),
+			attr("coordinates", "field_coordinates"),
+			attr("broader", "field_broader"),
+		}, termFields...),
+	},
+}
+
+// Copies the value of a JSON API attribute as-is
+func attr(key, attribute string) fixtureField {
+	return fixtureField{key, attribute, func(g *generator, r resource) (interface{}, error) {
+		return r.attributes()[attribute], nil
+	}}
+}
+
+// Answers the uri of a link attribute, or the uris of a multi-valued link attribute
+func linkUri(key, attribute string) fixtureField {
+	return fixtureField{key, attribute, func(g *generator, r resource) (interface{}, error) {
+		switch v := r.attributes()[attribute].(type) {
+		case map[string]interface{}:
+			return v["uri"], nil
+		case []interface{}:
+			var uris []interface{}
+			for _, link := range v {
+				if m, ok := link.(map[string]interface{}); ok {
+					uris = append(uris, m["uri"])
+				}
+			}
+			return uris, nil
+		default:
+			return nil, nil
+		}
+	}}
+}
+
+// Resolves a single-valued relationship to the name or title of the referenced entity
+func name(key, relationship string) fixtureField {
+	return fixtureField{key, relationship, func(g *generator, r resource) (interface{}, error) {
+		refs := r.relationship(relationship)
+		if len(refs) == 0 {
+			return nil, nil
+		}
+		return g.nameOf(refs[0])
+	}}
+}
+
+// Resolves a multi-valued relationship to the names or titles of the referenced entities
+func names(key, relationship string) fixtureField {
+	return fixtureField{key, relationship, func(g *generator, r resource) (interface{}, error) {
+		var result []string
+		for _, ref := range r.relationship(relationship) {
+			if n, err := g.nameOf(ref); err != nil {
+				return nil, err
+			} else {
+				result = append(result, n)
+			}
+		}
+		return result, nil
+	}}
+}
+
+// Resolves a typed relation (e.g. field_creator) to the relationship type and name of each referenced entity
+func typedNames(key, relationship string) fixtureField {
+	return fixtureField{key, relationship, func(g *generator, r resource) (interface{}, error) {
+		var result []map[string]interface{}
+		for _, ref := range r.relationship(relationship) {
+			n, err := g.nameOf(ref)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, map[string]interface{}{"rel_type": ref.meta()["rel_type"], "name": n})
+		}
+		return result, nil
+	}}
+}
+
+// Resolves a relationship to language taxonomy terms into LanguageString values
+func languageValues(key, relationship string) fixtureField {
+	return fixtureField{key, relationship, func(g *generator, r resource) (interface{}, error) {
+		var result []LanguageString
+		for _, ref := range r.relationship(relationship) {
+			code, err := g.attributeOf(ref, "field_language_code")
+			if err != nil {
+				return nil, err
+			}
+			value, _ := ref.meta()["value"].(string)
+			langCode, _ := code.(string)
+			result = append(result, LanguageString{Value: value, LangCode: langCode})
+		}
+		return result, nil
+	}}
+}
+
+// Resolves a single-valued relationship to a language taxonomy term into its language code
+func languageCode(key, relationship string) fixtureField {
+	return fixtureField{key, relationship, func(g *generator, r resource) (interface{}, error) {
+		refs := r.relationship(relationship)
+		if len(refs) == 0 {
+			return nil, nil
+		}
+		return g.attributeOf(refs[0], "field_language_code")
+	}}
+}
+
+// Resolves the Islandora model of a repository object into its name and external uri
+func islandoraModel(key, relationship string) fixtureField {
+	return fixtureField{key, relationship, func(g *generator, r resource) (interface{}, error) {
+		refs := r.relationship(relationship)
+		if len(refs) == 0 {
+			return nil, nil
+		}
+		n, err := g.nameOf(refs[0])
+		if err != nil {
+			return nil, err
+		}
+		externalUri, err := g.attributeOf(refs[0], "field_external_uri")
+		if err != nil {
+			return nil, err
+		}
+		model := map[string]interface{}{"name": n}
+		if link, ok := externalUri.(map[string]interface{}); ok {
+			model["external_uri"] = link["uri"]
+		}
+		return model, nil
+	}}
+}
+
+// Maps authority links to the representation used by ExpectedPerson
+func personAuthority(key, attribute string) fixtureField {
+	return fixtureField{key, attribute, func(g *generator, r resource) (interface{}, error) {
+		links, _ := r.attributes()[attribute].([]interface{})
+		var result []map[string]interface{}
+		for _, link := range links {
+			if m, ok := link.(map[string]interface{}); ok {
+				result = append(result, map[string]interface{}{"uri": m["uri"], "name": m["title"], "type": m["source"]})
+			}
+		}
+		return result, nil
+	}}
+}
+
+// Resolves and caches the attributes of entities referenced by relationships
+type generator struct {
+	u        *jsonapi.JsonApiUrl
+	resolved map[string]map[string]interface{}
+}
+
+// Answers the name, or title if the entity has no name, of the referenced entity
+func (g *generator) nameOf(ref resource) (string, error) {
+	attrs, err := g.resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	if n, ok := attrs["name"].(string); ok {
+		return n, nil
+	}
+	t, _ := attrs["title"].(string)
+	return t, nil
+}
+
+// Answers the value of the named attribute of the referenced entity
+func (g *generator) attributeOf(ref resource, attribute string) (interface{}, error) {
+	attrs, err := g.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	return attrs[attribute], nil
+}
+
+func (g *generator) resolve(ref resource) (map[string]interface{}, error) {
+	id, _ := ref["id"].(string)
+	if attrs, ok := g.resolved[id]; ok {
+		return attrs, nil
+	}
+
+	u := jsonapi.JsonApiUrl{
+		BaseUrl:      g.u.BaseUrl,
+		DrupalEntity: ref.drupalType().Entity(),
+		DrupalBundle: ref.drupalType().Bundle(),
+		Filter:       "id",
+		Value:        id,
+		Username:     g.u.Username,
+		Password:     g.u.Password,
+	}
+	res := &jsonapi.JsonApiResponse{}
+	if err := u.FetchSingle(res); err != nil {
+		return nil, err
+	}
+
+	attrs := resource(res.Data[0]).attributes()
+	g.resolved[id] = attrs
+	return attrs, nil
+}
+
+// A generic JSON API data element, or a resource identifier appearing in a relationship
+type resource map[string]interface{}
+
+func (r resource) drupalType() jsonapi.DrupalType {
+	t, _ := r["type"].(string)
+	return jsonapi.DrupalType(t)
+}
+
+func (r resource) attributes() map[string]interface{} {
+	attrs, _ := r["attributes"].(map[string]interface{})
+	return attrs
+}
+
+func (r resource) meta() map[string]interface{} {
+	meta, _ := r["meta"].(map[string]interface{})
+	return meta
+}
+
+// Answers the resource identifiers of the named relationship, whether it is single or multi-valued
+func (r resource) relationship(name string) []resource {
+	rels, _ := r["relationships"].(map[string]interface{})
+	rel, _ := rels[name].(map[string]interface{})
+	switch data := rel["data"].(type) {
+	case map[string]interface{}:
+		return []resource{data}
+	case []interface{}:
+		var refs []resource
+		for _, d := range data {
+			if m, ok := d.(map[string]interface{}); ok {
+				refs = append(refs, m)
+			}
+		}
+		return refs
+	default:
+		return nil
+	}
+}
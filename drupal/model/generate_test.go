@@ -0,0 +1,175 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Canned JSON API responses keyed by request path and the value of the id, title, or name filter
+var generateResponses = map[string]string{
+	"/jsonapi/node/islandora_object?Moonrise": `{"data": [{
+		"type": "node--islandora_object",
+		"id": "815a4c04-0be5-44f1-a876-e8ddc11dcf21",
+		"attributes": {
+			"title": "Moonrise",
+			"field_unique_id": "io_1",
+			"field_extent": ["1 photograph"],
+			"field_featured_item": true,
+			"field_jhir": {"uri": "http://jhir.library.jhu.edu/handle/1774.2/1", "title": ""},
+			"field_library_catalog_link": [{"uri": "https://catalyst.library.jhu.edu/1", "title": "Catalyst", "options": []}],
+			"field_geoportal_link": {"uri": "https://geoportal.library.jhu.edu/layer/1", "title": "", "options": {"attributes": {"target": "_blank"}}},
+			"field_weight": 3
+		},
+		"relationships": {
+			"field_subject": {"data": [{"type": "taxonomy_term--subject", "id": "s1"}]},
+			"field_creator": {"data": [{"type": "taxonomy_term--person", "id": "p1", "meta": {"rel_type": "relators:pht"}}]},
+			"field_alternative_title": {"data": [{"type": "taxonomy_term--language", "id": "l1", "meta": {"value": "Salida de la luna"}}]},
+			"field_member_of": {"data": {"type": "node--collection_object", "id": "c1"}},
+			"field_copyright_and_use": {"data": null}
+		}
+	}]}`,
+	"/jsonapi/taxonomy_term/subject?s1":       `{"data": [{"type": "taxonomy_term--subject", "id": "s1", "attributes": {"name": "Analog Photography"}}]}`,
+	"/jsonapi/taxonomy_term/person?p1":        `{"data": [{"type": "taxonomy_term--person", "id": "p1", "attributes": {"name": "Adams, Ansel"}}]}`,
+	"/jsonapi/taxonomy_term/language?l1":      `{"data": [{"type": "taxonomy_term--language", "id": "l1", "attributes": {"name": "Spanish", "field_language_code": "es"}}]}`,
+	"/jsonapi/node/collection_object?c1":      `{"data": [{"type": "node--collection_object", "id": "c1", "attributes": {"title": "Ansel Adams Images"}}]}`,
+	"/jsonapi/taxonomy_term/subject?Analog":   `{"data": [{"type": "taxonomy_term--subject", "id": "s1", "attributes": {"name": "Analog", "field_unique_id": "s_1", "description": {"value": "<p>Analog</p>", "format": "basic_html", "processed": "<p>Analog</p>"}, "field_authority_link": [{"uri": "http://id.loc.gov/1", "title": "LOC", "source": "lcsh"}]}}]}`,
+	"/jsonapi/file/file?moonrise.jpg":         `{"data": [{"type": "file--file", "id": "f1", "attributes": {"filename": "moonrise.jpg", "uri": {"value": "fedora://2021-09/moonrise.jpg", "url": "/_flysystem/fedora/2021-09/moonrise.jpg"}, "filemime": "image/jpeg", "filesize": 1024, "status": false}}]}`,
+	"/jsonapi/taxonomy_term/subject?Multiple": `{"data": [{"type": "taxonomy_term--subject", "id": "s1"}, {"type": "taxonomy_term--subject", "id": "s2"}]}`,
+}
+
+func newGenerateServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		value := q.Get("filter[id]") + q.Get("filter[title]") + q.Get("filter[name]") + q.Get("filter[filename]")
+		body, ok := generateResponses[fmt.Sprintf("%s?%s", r.URL.Path, value)]
+		if !ok {
+			t.Logf("no response for %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func Test_GenerateTerm(t *testing.T) {
+	server := newGenerateServer(t)
+	defer server.Close()
+
+	expected, err := Generate(&jsonapi.JsonApiUrl{
+		BaseUrl:      server.URL,
+		DrupalEntity: TaxonomyTerm,
+		DrupalBundle: Subject,
+		Filter:       "name",
+		Value:        "Analog",
+	})
+	require.Nil(t, err)
+
+	subject := expected.(*ExpectedSubject)
+	assert.Equal(t, TaxonomyTerm, subject.Type)
+	assert.Equal(t, Subject, subject.Bundle)
+	assert.Equal(t, "Analog", subject.Name)
+	assert.Equal(t, "s_1", subject.UniqueId)
+	assert.Equal(t, "basic_html", subject.Description.Format)
+	assert.Equal(t, 1, len(subject.Authority))
+	assert.Equal(t, "http://id.loc.gov/1", subject.Authority[0].Uri)
+	assert.Equal(t, "lcsh", subject.Authority[0].Source)
+}
+
+func Test_GenerateFile(t *testing.T) {
+	server := newGenerateServer(t)
+	defer server.Close()
+
+	expected, err := Generate(&jsonapi.JsonApiUrl{
+		BaseUrl:      server.URL,
+		DrupalEntity: FileEntity,
+		DrupalBundle: File,
+		Filter:       "filename",
+		Value:        "moonrise.jpg",
+	})
+	require.Nil(t, err)
+
+	file := expected.(*ExpectedFile)
+	assert.Equal(t, "moonrise.jpg", file.NameOrTitle())
+	assert.Equal(t, "filename", file.Field())
+	assert.Equal(t, "fedora://2021-09/moonrise.jpg", file.Uri.Value)
+	assert.Equal(t, "/_flysystem/fedora/2021-09/moonrise.jpg", file.Uri.Url)
+	assert.Equal(t, "image/jpeg", file.MimeType)
+	assert.Equal(t, 1024, file.FileSize)
+	assert.False(t, file.Status)
+}
+
+func Test_GenerateRepoObjResolvesRelationships(t *testing.T) {
+	server := newGenerateServer(t)
+	defer server.Close()
+
+	expected, err := Generate(&jsonapi.JsonApiUrl{
+		BaseUrl:      server.URL,
+		DrupalEntity: Node,
+		DrupalBundle: RepositoryObject,
+		Filter:       "title",
+		Value:        "Moonrise",
+	})
+	require.Nil(t, err)
+
+	obj := expected.(*ExpectedRepoObj)
+	assert.Equal(t, "Moonrise", obj.NameOrTitle())
+	assert.Equal(t, "io_1", obj.UniqueId)
+	assert.Equal(t, []string{"1 photograph"}, obj.Extent)
+	assert.True(t, obj.FeaturedItem)
+	assert.Equal(t, 3, obj.Weight)
+	assert.Equal(t, "http://jhir.library.jhu.edu/handle/1774.2/1", obj.JhirUri)
+	assert.Equal(t, []Link{{Uri: "https://catalyst.library.jhu.edu/1", Title: "Catalyst"}}, obj.LibraryCatalogLink)
+	assert.Equal(t, Link{Uri: "https://geoportal.library.jhu.edu/layer/1",
+		Options: map[string]interface{}{"attributes": map[string]interface{}{"target": "_blank"}}}, obj.GeoportalLink)
+	assert.Equal(t, []string{"Analog Photography"}, obj.Subject)
+	assert.Equal(t, "Ansel Adams Images", obj.MemberOf)
+	assert.Equal(t, "", obj.CopyrightAndUse)
+	assert.Equal(t, []LanguageString{{Value: "Salida de la luna", LangCode: "es"}}, obj.AltTitle)
+	require.Equal(t, 1, len(obj.Creator))
+	assert.Equal(t, "relators:pht", obj.Creator[0].RelType)
+	assert.Equal(t, "Adams, Ansel", obj.Creator[0].Name)
+}
+
+func Test_GenerateRequiresSingleResult(t *testing.T) {
+	server := newGenerateServer(t)
+	defer server.Close()
+
+	_, err := Generate(&jsonapi.JsonApiUrl{
+		BaseUrl:      server.URL,
+		DrupalEntity: TaxonomyTerm,
+		DrupalBundle: Subject,
+		Filter:       "name",
+		Value:        "Multiple",
+	})
+	assert.NotNil(t, err)
+}
+
+func Test_GenerateUnsupported(t *testing.T) {
+	_, err := Generate(&jsonapi.JsonApiUrl{DrupalEntity: "user", DrupalBundle: "user"})
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func Test_NewExpected(t *testing.T) {
+	e, err := NewExpected(Node, RepositoryObject)
+	assert.Nil(t, err)
+	assert.IsType(t, &ExpectedRepoObj{}, e)
+
+	_, err = NewExpected(User, User)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func Test_SourceFields(t *testing.T) {
+	sources, err := SourceFields(Node, RepositoryObject)
+	require.Nil(t, err)
+	assert.Equal(t, "catalog_link", sources["field_library_catalog_link"])
+	assert.Equal(t, "title", sources["title"])
+
+	_, err = SourceFields(Node, "page")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// The schemes of link URIs that address the Drupal site itself rather than an external resource
+const (
+	// A path of the site, e.g. `internal:/node/1`
+	InternalScheme = "internal"
+	// An entity of the site, e.g. `entity:node/1`
+	EntityScheme = "entity"
+	// A route of the site, e.g. `route:<front>`
+	RouteScheme = "route"
+)
+
+// Represents the value of a Drupal link field, e.g. field_finding_aid or field_geoportal_link
+type Link struct {
+	Uri   string `json:"uri"`
+	Title string `json:"title,omitempty"`
+	// Options of the link, e.g. `{"attributes": {"target": "_blank"}}`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Decodes a link as serialized by the JSON API, which serializes empty options as an empty list rather than an
+// empty object
+func (l *Link) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Uri     string          `json:"uri"`
+		Title   *string         `json:"title"`
+		Options json.RawMessage `json:"options"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*l = Link{Uri: raw.Uri}
+	if raw.Title != nil {
+		l.Title = *raw.Title
+	}
+	if len(raw.Options) > 0 && raw.Options[0] == '{' {
+		if err := json.Unmarshal(raw.Options, &l.Options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Answers the scheme of the link URI, e.g. `internal` or `https`
+func (l Link) Scheme() string {
+	if i := strings.Index(l.Uri, ":"); i > 0 {
+		return strings.ToLower(l.Uri[:i])
+	}
+	return ""
+}
+
+// Answers true if the link addresses the Drupal site itself, i.e. its URI has the internal, entity, or route scheme
+func (l Link) IsInternal() bool {
+	switch l.Scheme() {
+	case InternalScheme, EntityScheme, RouteScheme:
+		return true
+	}
+	return false
+}
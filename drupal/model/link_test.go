@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LinkUnmarshal(t *testing.T) {
+	var links []Link
+	require.Nil(t, json.Unmarshal([]byte(`[
+		{"uri": "https://aspace.library.jhu.edu/1", "title": "Finding aid", "options": []},
+		{"uri": "internal:/node/1", "title": null, "options": {"attributes": {"target": "_blank"}}},
+		{"uri": "entity:node/2"}
+	]`), &links))
+	assert.Equal(t, []Link{
+		{Uri: "https://aspace.library.jhu.edu/1", Title: "Finding aid"},
+		{Uri: "internal:/node/1", Options: map[string]interface{}{"attributes": map[string]interface{}{"target": "_blank"}}},
+		{Uri: "entity:node/2"},
+	}, links)
+
+	assert.Equal(t, "https", links[0].Scheme())
+	assert.False(t, links[0].IsInternal())
+	assert.True(t, links[1].IsInternal())
+	assert.True(t, links[2].IsInternal())
+	assert.False(t, Link{Uri: "/relative"}.IsInternal())
+}
@@ -12,9 +12,7 @@ package model
 import (
 	"errors"
 	"fmt"
-	"testing"
 
-	"github.com/jhu-idc/idc-golang/drupal/env"
 	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
 )
 
@@ -41,6 +39,38 @@ const (
 	Fits = "fits_technical_metadata"
 	// Constant for the Remote Video media bundle
 	RemoteVideo = "remote_video"
+	// Constant for the Drupal taxonomy term entity type
+	TaxonomyTerm = "taxonomy_term"
+	// Constant for the Drupal media entity type
+	Media = "media"
+	// Constant for the Drupal file entity type, whose only bundle is File
+	FileEntity = "file"
+	// Constant for the Person taxonomy bundle
+	Person = "person"
+	// Constant for the Subject taxonomy bundle
+	Subject = "subject"
+	// Constant for the Genre taxonomy bundle
+	Genre = "genre"
+	// Constant for the Resource Types taxonomy bundle
+	ResourceTypes = "resource_types"
+	// Constant for the Access Rights taxonomy bundle
+	AccessRights = "access_rights"
+	// Constant for the Copyright and Use taxonomy bundle
+	CopyrightAndUse = "copyright_and_use"
+	// Constant for the Language taxonomy bundle
+	Language = "language"
+	// Constant for the Geolocation taxonomy bundle
+	GeoLocation = "geo_location"
+	// Constant for the Family taxonomy bundle
+	Family = "family"
+	// Constant for the Corporate Body taxonomy bundle
+	CorporateBody = "corporate_body"
+	// Constant for the Islandora Access taxonomy bundle
+	IslandoraAccess = "islandora_access"
+	// Constant for the Drupal user entity type, which has no bundle
+	User = "user"
+	// Constant for the Drupal user role entity type, which has no bundle
+	UserRole = "user_role"
 	// Layout for timestamps appearing in JsonApiNodeAttributes
 	TsLayout = "2006-01-02T15:04:05-07:00"
 )
@@ -63,43 +93,21 @@ type JsonApiNodeAttributes struct {
 	ChangedDate string `json:"changed"`
 }
 
-// Resolve the reference of the data object, useful for references appearing within JSON API `relationships`.  This
-// function formulates a JSON API query based on the type, bundle, and unique identifier of the object, and returns
-// exactly one resource.
-func (jad *JsonApiData) Resolve(t *testing.T, v interface{}) {
-	u := jsonapi.JsonApiUrl{
-		T: t,
-		// TODO FIXME the BaseUrl won't work as expected. Really the caller wants the BaseUrl that was used to retrieve
-		//   the JsonApiData, which means we really need access to the JSON API 'links' object and use the 'self' href.
-		//   But we can't do that easily right now.
-		BaseUrl:      env.BaseUrlOr("https://islandora-idc.traefik.me"),
-		DrupalEntity: jad.Type.Entity(),
-		DrupalBundle: jad.Type.Bundle(),
-		Filter:       "id",
-		Value:        jad.Id,
-	}
-
-	u.GetSingle(v)
-}
-
-// ResolveWithBasicAuth behaves as Resolve, but issues the request with HTTP Basic Auth, using the supplied username and
-// password
-func (jad *JsonApiData) ResolveWithBasicAuth(t *testing.T, v interface{}, username string, password string) {
-	u := jsonapi.JsonApiUrl{
-		T: t,
-		// TODO FIXME the BaseUrl won't work as expected. Really the caller wants the BaseUrl that was used to retrieve
-		//   the JsonApiData, which means we really need access to the JSON API 'links' object and use the 'self' href.
-		//   But we can't do that easily right now.
-		BaseUrl:      env.BaseUrlOr("https://islandora-idc.traefik.me"),
-		DrupalEntity: jad.Type.Entity(),
-		DrupalBundle: jad.Type.Bundle(),
-		Filter:       "id",
-		Value:        jad.Id,
-		Username:     username,
-		Password:     password,
-	}
+// Encapsulates the core publishing flags of Drupal content nodes (collections and repository objects) as presented by
+// the JSONAPI
+type JsonApiNodeFlags struct {
+	// Whether the node is promoted to the front page
+	Promote bool `json:"promote"`
+	// Whether the node is sticky at the top of lists
+	Sticky bool `json:"sticky"`
+}
 
-	u.GetSingle(v)
+// Represents an authority link (e.g. to id.loc.gov) of a taxonomy term; shared by JSON API resources and Expected
+// entities
+type Authority struct {
+	Uri    string `json:"uri"`
+	Title  string `json:"title"`
+	Source string `json:"source"`
 }
 
 // Represents the results of a JSONAPI query for a single Person from the Person Taxonomy
@@ -116,18 +124,14 @@ type JsonApiPerson struct {
 				Format    string
 				Processed string
 			}
-			PrimaryPartOfName       string   `json:"field_primary_part_of_name"`
-			PreferredNamePrefix     []string `json:"field_preferred_name_prefix"`
-			PreferredNameRest       []string `json:"field_preferred_name_rest"`
-			PreferredNameSuffix     []string `json:"field_preferred_name_suffix"`
-			PreferredNameFullerForm []string `json:"field_preferred_name_fuller_form"`
-			PreferredNameNumber     []string `json:"field_preferred_name_number"`
-			PersonAlternateName     []string `json:"field_person_alternate_name"`
-			Authority               []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			PrimaryPartOfName       string      `json:"field_primary_part_of_name"`
+			PreferredNamePrefix     []string    `json:"field_preferred_name_prefix"`
+			PreferredNameRest       []string    `json:"field_preferred_name_rest"`
+			PreferredNameSuffix     []string    `json:"field_preferred_name_suffix"`
+			PreferredNameFullerForm []string    `json:"field_preferred_name_fuller_form"`
+			PreferredNameNumber     []string    `json:"field_preferred_name_number"`
+			PersonAlternateName     []string    `json:"field_person_alternate_name"`
+			Authority               []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Relationships struct {
@@ -153,11 +157,7 @@ type JsonApiAccessRights struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -197,11 +197,7 @@ type JsonApiCopyrightAndUse struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -222,11 +218,7 @@ type JsonApiFamily struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Relationships struct {
@@ -246,6 +238,7 @@ type JsonApiCollection struct {
 		Id                string
 		JsonApiAttributes struct {
 			JsonApiNodeAttributes
+			JsonApiNodeFlags
 			Title       string
 			UniqueId    string `json:"field_unique_id"`
 			Description struct {
@@ -255,10 +248,7 @@ type JsonApiCollection struct {
 			ContactEmail     string   `json:"field_collection_contact_email"`
 			ContactName      string   `json:"field_collection_contact_name"`
 			CollectionNumber []string `json:"field_collection_number"`
-			FindingAid       []struct {
-				Uri   string
-				Title string
-			} `json:"field_finding_aid"`
+			FindingAid       []Link   `json:"field_finding_aid"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			AltTitle struct {
@@ -297,6 +287,7 @@ type JsonApiIslandoraObj struct {
 		Id                string
 		JsonApiAttributes struct {
 			JsonApiNodeAttributes
+			JsonApiNodeFlags
 			Title             string
 			UniqueId          string   `json:"field_unique_id"`
 			CollectionNumber  []string `json:"field_collection_number"`
@@ -309,18 +300,12 @@ type JsonApiIslandoraObj struct {
 				Uri   string
 				Title string
 			} `json:"field_dspace_identifier"`
-			DspaceItemid string `json:"field_dspace_item_id"`
-			Description  string
-			Extent       []string `json:"field_extent"`
-			FeaturedItem bool     `json:"field_featured_item"`
-			FindingAid   []struct {
-				Uri   string
-				Title string
-			} `json:"field_finding_aid"`
-			GeoportalLink struct {
-				Uri   string
-				Title string
-			} `json:"field_geoportal_link"`
+			DspaceItemid  string `json:"field_dspace_item_id"`
+			Description   string
+			Extent        []string `json:"field_extent"`
+			FeaturedItem  bool     `json:"field_featured_item"`
+			FindingAid    []Link   `json:"field_finding_aid"`
+			GeoportalLink Link     `json:"field_geoportal_link"`
 			// TODO
 			IsPartOf struct {
 				Uri string
@@ -331,12 +316,9 @@ type JsonApiIslandoraObj struct {
 				Uri   string
 				Title string
 			} `json:"field_jhir"`
-			LibraryCatalogLink []struct {
-				Uri   string
-				Title string
-			} `json:"field_library_catalog_link"`
-			OclcNumber []string `json:"field_oclc_number"`
-			Weight     int      `json:"field_weight"`
+			LibraryCatalogLink []Link   `json:"field_library_catalog_link"`
+			OclcNumber         []string `json:"field_oclc_number"`
+			Weight             int      `json:"field_weight"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Abstract struct {
@@ -425,11 +407,7 @@ type JsonApiGenre struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -446,17 +424,14 @@ type JsonApiGeolocation struct {
 				Uri   string
 				Title string
 			} `json:"field_broader"`
-			GeoAltName  []string `json:"field_geo_alt_name"`
+			GeoAltName  []string    `json:"field_geo_alt_name"`
+			Coordinates interface{} `json:"field_coordinates"`
 			Description struct {
 				Value     string
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -474,11 +449,7 @@ type JsonApiResourceType struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -496,11 +467,7 @@ type JsonApiSubject struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -519,11 +486,7 @@ type JsonApiLanguage struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Authority []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -531,32 +494,33 @@ type JsonApiLanguage struct {
 // Represents an element of a JSONAPI response that encapsulates a string value and a language taxonomy entity
 //
 // In the following example, the objects with a type `taxonomy_term--language` are represented by this struct.
-//   "field_alternative_title": {
-//    "data": [
-//      {
-//        "type": "taxonomy_term--language",
-//        "id": "7397e0c4-df0a-4800-95af-afccc6ff64a5",
-//        "meta": {
-//          "value": "Moonrise Over Hernandez"
-//        }
-//      },
-//      {
-//        "type": "taxonomy_term--language",
-//        "id": "bacfc5b6-b4b9-4239-8744-46dca6a91f0e",
-//        "meta": {
-//          "value": "Salida de la luna sobre Hernández"
-//        }
-//      }
-//    ],
-//    "links": {
-//      "related": {
-//        "href": "http://islandora-idc.traefik.me/jsonapi/node/islandora_object/815a4c04-0be5-44f1-a876-e8ddc11dcf21/field_alternative_title?resourceVersion=id%3A48"
-//      },
-//      "self": {
-//        "href": "http://islandora-idc.traefik.me/jsonapi/node/islandora_object/815a4c04-0be5-44f1-a876-e8ddc11dcf21/relationships/field_alternative_title?resourceVersion=id%3A48"
-//      }
-//    }
-//  }
+//
+//	 "field_alternative_title": {
+//	  "data": [
+//	    {
+//	      "type": "taxonomy_term--language",
+//	      "id": "7397e0c4-df0a-4800-95af-afccc6ff64a5",
+//	      "meta": {
+//	        "value": "Moonrise Over Hernandez"
+//	      }
+//	    },
+//	    {
+//	      "type": "taxonomy_term--language",
+//	      "id": "bacfc5b6-b4b9-4239-8744-46dca6a91f0e",
+//	      "meta": {
+//	        "value": "Salida de la luna sobre Hernández"
+//	      }
+//	    }
+//	  ],
+//	  "links": {
+//	    "related": {
+//	      "href": "http://islandora-idc.traefik.me/jsonapi/node/islandora_object/815a4c04-0be5-44f1-a876-e8ddc11dcf21/field_alternative_title?resourceVersion=id%3A48"
+//	    },
+//	    "self": {
+//	      "href": "http://islandora-idc.traefik.me/jsonapi/node/islandora_object/815a4c04-0be5-44f1-a876-e8ddc11dcf21/relationships/field_alternative_title?resourceVersion=id%3A48"
+//	    }
+//	  }
+//	}
 type JsonApiLanguageValue struct {
 	JsonApiData
 	Meta struct {
@@ -564,14 +528,6 @@ type JsonApiLanguageValue struct {
 	}
 }
 
-// Answers the language code of the value string by resolving the Language Taxonomy entity identified in the
-// JsonApiLanguageValue
-func (lv JsonApiLanguageValue) LangCode(t *testing.T) string {
-	jsonApiLang := JsonApiLanguage{}
-	lv.Resolve(t, &jsonApiLang)
-	return jsonApiLang.JsonApiData[0].JsonApiAttributes.LanguageCode
-}
-
 // Answers the value of the string, the language of which is provided by langCode(...)
 func (lv JsonApiLanguageValue) Value() string {
 	return lv.Meta.Value
@@ -590,18 +546,14 @@ type JsonApiCorporateBody struct {
 				Format    string
 				Processed string
 			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
-			PrimaryName     string   `json:"field_primary_name"`
-			SubordinateName []string `json:"field_subordinate_name"`
-			Location        []string `json:"field_location_of_meeting"`
-			NumberOrSection []string `json:"field_num_of_section_or_meet"`
-			DateOfMeeting   []string `json:"field_date_of_meeting_or_treaty"`
-			AltName         []string `json:"field_corporate_body_alt_name"`
-			Date            []string `json:"field_date"`
+			Authority       []Authority `json:"field_authority_link"`
+			PrimaryName     string      `json:"field_primary_name"`
+			SubordinateName []string    `json:"field_subordinate_name"`
+			Location        []string    `json:"field_location_of_meeting"`
+			NumberOrSection []string    `json:"field_num_of_section_or_meet"`
+			DateOfMeeting   []string    `json:"field_date_of_meeting_or_treaty"`
+			AltName         []string    `json:"field_corporate_body_alt_name"`
+			Date            []string    `json:"field_date"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Relationships struct {
@@ -757,6 +709,7 @@ type JsonApiAudioMedia struct {
 		JsonApiAttributes struct {
 			JsonApiNodeAttributes
 			JsonApiMediaAttributes
+			JsonApiAudioMediaAttributes
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			JsonApiMediaRelationships
@@ -767,6 +720,11 @@ type JsonApiAudioMedia struct {
 	} `json:"data"`
 }
 
+type JsonApiAudioMediaAttributes struct {
+	// Duration of the audio, e.g. '00:03:21'
+	Duration string `json:"field_duration"`
+}
+
 type JsonApiExtractedTextMedia struct {
 	JsonApiData []struct {
 		Type              jsonapi.DrupalType
@@ -834,16 +792,29 @@ type JsonApiVideoMedia struct {
 		JsonApiAttributes struct {
 			JsonApiNodeAttributes
 			JsonApiMediaAttributes
+			JsonApiVideoMediaAttributes
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			JsonApiMediaRelationships
 			File struct {
 				Data RelData
 			} `json:"field_media_video_file"`
+			// Caption, subtitle, and description files of the video.  The meta of each track carries its 'kind',
+			// 'label', and 'srclang'.
+			Track struct {
+				Data []RelData
+			} `json:"field_track"`
 		} `json:"relationships"`
 	} `json:"data"`
 }
 
+type JsonApiVideoMediaAttributes struct {
+	Height int `json:"field_height"`
+	Width  int `json:"field_width"`
+	// Duration of the video, e.g. '00:03:21'
+	Duration string `json:"field_duration"`
+}
+
 type JsonApiFile struct {
 	JsonApiData []struct {
 		Type              jsonapi.DrupalType
@@ -857,6 +828,8 @@ type JsonApiFile struct {
 			}
 			MimeType string `json:"filemime"`
 			FileSize int
+			// True if the file is permanent, false if it is temporary
+			Status bool
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -898,3 +871,38 @@ type JsonApiFitsMedia struct {
 		} `json:"relationships"`
 	} `json:"data"`
 }
+
+// Represents the results of a JSONAPI query for a single Drupal user account
+type JsonApiUser struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			Name        string
+			DisplayName string `json:"display_name"`
+			Mail        string
+			Status      bool
+			Timezone    string
+		} `json:"attributes"`
+		JsonApiRelationships struct {
+			Roles struct {
+				Data []JsonApiData
+			} `json:"roles"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// Represents the results of a JSONAPI query for a single Drupal user role
+type JsonApiRole struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The machine name of the role, e.g. 'fedoraadmin'
+			RoleId      string `json:"drupal_internal__id"`
+			Label       string
+			IsAdmin     bool `json:"is_admin"`
+			Permissions []string
+		} `json:"attributes"`
+	} `json:"data"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// Constant for the Paragraphs entity type, whose bundles (e.g. `text_block`) are particular to a site
+const Paragraph = "paragraph"
+
+// Represents the expected values of a paragraph nested in an entity (e.g. a node) by an entity reference revisions
+// field, or in another paragraph.  Paragraph bundles are particular to a site, so rather than a struct per bundle, the
+// values of a paragraph are keyed by the name of their JSON API attribute.  Paragraphs have neither a name nor a
+// title; they are identified by the field of their host and their position in it.
+type ExpectedParagraph struct {
+	Expected
+	// The values of the attributes of the paragraph, keyed by attribute name, e.g. `field_heading`; attributes not
+	// present are not verified
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// The paragraphs nested in the paragraph, in order, keyed by the name of the field referencing them, e.g.
+	// `field_items`
+	Paragraphs map[string][]ExpectedParagraph `json:"paragraphs,omitempty"`
+}
+
+// Creates an ExpectedParagraph of the bundle (e.g. `text_block`) carrying the values of the fields
+func NewExpectedParagraph(bundle string, fields map[string]interface{}) ExpectedParagraph {
+	return ExpectedParagraph{Expected: Expected{Type: Paragraph, Bundle: bundle}, Fields: fields}
+}
@@ -0,0 +1,11 @@
+package model
+
+// Represents a revision of an entity, e.g. one created by a migration run in update mode
+type ExpectedRevision struct {
+	// The name of the user who created the revision
+	Author string `json:"author,omitempty"`
+	// The revision log message
+	LogMessage string `json:"log_message,omitempty"`
+	// The RFC 3339 time the revision was created, e.g. `2021-06-01T12:00:00+00:00`
+	Timestamp string `json:"timestamp,omitempty"`
+}
@@ -0,0 +1,301 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// A fixture does not conform to the JSON schema of its Expected struct
+var ErrInvalidFixture = errors.New("invalid fixture")
+
+// The version of JSON Schema answered by Schema
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Answers the JSON schema of the fixtures of the entity type and bundle, describing each key of its Expected struct
+// (see NewExpected) and of the fields registered for the bundle (see RegisterField).  A fixture carrying any other key
+// does not conform, so that a misspelled key (e.g. `acess_rights`), which encoding/json would silently ignore, is
+// caught.  The schema of an ExpectedAbsent fixture is answered if absent is true.
+func Schema(entityType, bundle string, absent bool) (map[string]interface{}, error) {
+	var v interface{} = &ExpectedAbsent{}
+	if !absent {
+		expected, err := NewExpected(entityType, bundle)
+		if err != nil {
+			return nil, err
+		}
+		v = expected
+	}
+	schema := SchemaOf(v)
+	schema["$schema"] = schemaDialect
+	schema["title"] = entityType + "--" + bundle
+	properties := schema["properties"].(map[string]interface{})
+	properties["type"] = map[string]interface{}{"const": entityType}
+	properties["bundle"] = map[string]interface{}{"const": bundle}
+	if absent {
+		properties[AbsentKey] = map[string]interface{}{"const": true}
+	}
+	for _, f := range RegisteredFields(entityType, bundle) {
+		properties[f.Key] = map[string]interface{}{}
+		if f.New != nil {
+			properties[f.Key] = schemaOf(reflect.TypeOf(f.New()).Elem(), false)
+		}
+	}
+	return schema, nil
+}
+
+// Answers the JSON schema of the JSON representation of the value (e.g. an &ExpectedSubject{}), derived from the json
+// tags of its struct.  The members of embedded structs are members of the embedding struct.  Values of types that
+// unmarshal themselves (e.g. Link) may be of any JSON type.
+func SchemaOf(v interface{}) map[string]interface{} {
+	return schemaOf(reflect.TypeOf(v), true)
+}
+
+// Answers the schema of the type.  A struct that unmarshals itself is described by its members only if it is the root
+// of the schema.
+func schemaOf(t reflect.Type, root bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !root && (t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)) {
+		return map[string]interface{}{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": []interface{}{"array", "null"}, "items": schemaOf(t.Elem(), false)}
+	case reflect.Map:
+		return map[string]interface{}{"type": []interface{}{"object", "null"}, "additionalProperties": schemaOf(t.Elem(), false)}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		addProperties(t, properties)
+		return map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+	return map[string]interface{}{}
+}
+
+// Adds the schema of each member of the JSON object of the struct type to the properties, including the members of
+// embedded structs
+func addProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			addProperties(ft, properties)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = schemaOf(f.Type, false)
+	}
+}
+
+// Validates the JSON fixture against the schema of its Expected struct (see Schema), answering an error wrapping
+// ErrInvalidFixture that describes each violation, e.g. `$.acess_rights: unknown key (did you mean access_rights?)`.
+// The keys named by allowed (e.g. verify.VerifyOnlyKey) are permitted in addition to those of the schema.  An error
+// wrapping ErrUnsupported is answered if the type and bundle of the fixture have no Expected struct.
+func ValidateFixture(b []byte, allowed ...string) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFixture, err)
+	}
+	fixture, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: a fixture must be a JSON object", ErrInvalidFixture)
+	}
+	entityType, _ := fixture["type"].(string)
+	bundle, _ := fixture["bundle"].(string)
+	absent, _ := fixture[AbsentKey].(bool)
+	schema, err := Schema(entityType, bundle, absent)
+	if err != nil {
+		return err
+	}
+	for _, k := range allowed {
+		schema["properties"].(map[string]interface{})[k] = map[string]interface{}{}
+	}
+
+	var violations []string
+	validate("$", schema, fixture, &violations)
+	if len(violations) > 0 {
+		return fmt.Errorf("%w of %s--%s:\n  %s", ErrInvalidFixture, entityType, bundle, strings.Join(violations, "\n  "))
+	}
+	return nil
+}
+
+// Unmarshals the JSON fixture into its Expected struct (e.g. one answered by NewExpected) strictly, answering an error
+// wrapping jsonapi.ErrStrict if the fixture carries a key the struct does not, or omits a field of the struct other
+// than those tagged `omitempty` (see jsonapi.DecodeStrict), e.g. `$.access_rights: never populated`.  The fields
+// registered for the bundle of the fixture (see RegisterField), AbsentKey if v is an *ExpectedAbsent, and the keys named by allowed (e.g.
+// verify.VerifyOnlyKey) are permitted in addition to those of the struct.
+func UnmarshalStrict(b []byte, v interface{}, allowed ...string) error {
+	fixture := struct {
+		Type   string `json:"type"`
+		Bundle string `json:"bundle"`
+	}{}
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return fmt.Errorf("%w: %s", jsonapi.ErrStrict, err)
+	}
+	if _, ok := v.(*ExpectedAbsent); ok {
+		allowed = append(allowed, AbsentKey)
+	}
+	for _, f := range RegisteredFields(fixture.Type, fixture.Bundle) {
+		allowed = append(allowed, f.Key)
+	}
+	return jsonapi.DecodeStrict(b, v, allowed...)
+}
+
+// Appends the violations of the schema by the value at the path.  Only the keywords answered by SchemaOf and Schema
+// are supported: type, const, properties, additionalProperties, and items.
+func validate(path string, schema map[string]interface{}, v interface{}, violations *[]string) {
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %v, got %v", path, c, v))
+		return
+	}
+	if t, ok := schema["type"]; ok && !conforms(v, t) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, describeType(t), jsonType(v)))
+		return
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional := schema["additionalProperties"]
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if p, ok := properties[k].(map[string]interface{}); ok {
+				validate(path+"."+k, p, v[k], violations)
+			} else if p, ok := additional.(map[string]interface{}); ok {
+				validate(path+"."+k, p, v[k], violations)
+			} else if additional == false {
+				*violations = append(*violations, fmt.Sprintf("%s.%s: unknown key%s", path, k, suggest(k, properties)))
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validate(fmt.Sprintf("%s[%d]", path, i), items, item, violations)
+			}
+		}
+	}
+}
+
+// Answers true if the value is of the JSON type, or of any of the list of JSON types
+func conforms(v interface{}, t interface{}) bool {
+	types, ok := t.([]interface{})
+	if !ok {
+		types = []interface{}{t}
+	}
+	for _, t := range types {
+		actual := jsonType(v)
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// Answers the JSON type of the decoded value; numbers without a fractional part are integers
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+	}
+	return "number"
+}
+
+// Answers the JSON type, or list of JSON types, as e.g. `array or null`
+func describeType(t interface{}) string {
+	types, ok := t.([]interface{})
+	if !ok {
+		return fmt.Sprint(t)
+	}
+	var names []string
+	for _, t := range types {
+		names = append(names, fmt.Sprint(t))
+	}
+	return strings.Join(names, " or ")
+}
+
+// Answers ` (did you mean k?)` naming the property closest to the unknown key, if any is close
+func suggest(key string, properties map[string]interface{}) string {
+	best, bestDistance := "", len(key)/3+1
+	for k := range properties {
+		if d := distance(strings.ToLower(key), strings.ToLower(k)); d < bestDistance || (d == bestDistance && k < best) {
+			best, bestDistance = k, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", best)
+}
+
+// Answers the Levenshtein distance between the strings
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Schema(t *testing.T) {
+	schema, err := Schema(Node, RepositoryObject, false)
+	require.Nil(t, err)
+	assert.Equal(t, "node--islandora_object", schema["title"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"const": "islandora_object"}, properties["bundle"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["title"])
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, properties["promote"])
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["weight"])
+	assert.Equal(t, map[string]interface{}{}, properties["finding_aid"].(map[string]interface{})["items"], "Link unmarshals itself")
+	assert.Equal(t, map[string]interface{}{}, properties["catalog_link"].(map[string]interface{})["items"], "Link unmarshals itself")
+	assert.Equal(t, map[string]interface{}{}, properties["geoportal_link"], "Link unmarshals itself")
+	assert.Contains(t, properties["abstract"].(map[string]interface{})["items"].(map[string]interface{})["properties"], "language")
+	assert.NotContains(t, properties, "Extra")
+	assert.Contains(t, properties, "linked_agent")
+	assert.NotContains(t, properties, "LinkedAgent")
+
+	schema, err = Schema(Node, RepositoryObject, true)
+	require.Nil(t, err)
+	assert.Contains(t, schema["properties"], AbsentKey)
+	assert.Contains(t, schema["properties"], "value")
+
+	_, err = Schema(Node, "page", false)
+	assert.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func Test_ValidateFixture(t *testing.T) {
+	assert.Nil(t, ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "weight": 3,
+		"subject": ["Photography"], "genre": null, "abstract": [{"value": "Moonrise", "language": "en"}],
+		"finding_aid": ["https://example.org/aid"], "geoportal_link": {"uri": "https://example.org/layer/1"},
+		"catalog_link": [{"uri": "https://example.org/catalog/1", "title": "Catalog"}],
+		"linked_agent": [{"rel": "relators:pht", "name": "Adams, Ansel"}]}`)))
+	assert.Nil(t, ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "absent": true, "value": "Withdrawn"}`)))
+
+	err := ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "acess_rights": ["Public"],
+		"weight": 1.5, "subject": "Photography", "abstract": [{"value": "Moonrise", "langauge": "en"}], "verify_only": ["title"]}`))
+	require.True(t, errors.Is(err, ErrInvalidFixture))
+	for _, violation := range []string{
+		"$.acess_rights: unknown key (did you mean access_rights?)",
+		"$.abstract[0].langauge: unknown key (did you mean language?)",
+		"$.subject: expected array or null, got string",
+		"$.weight: expected integer, got number",
+		"$.verify_only: unknown key",
+	} {
+		assert.Contains(t, err.Error(), violation)
+	}
+
+	assert.Nil(t, ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "verify_only": ["title"]}`), "verify_only"))
+	assert.True(t, errors.Is(ValidateFixture([]byte(`{"type": "node", "bundle": "page"}`)), ErrUnsupported))
+	assert.True(t, errors.Is(ValidateFixture([]byte(`[]`)), ErrInvalidFixture))
+	assert.Contains(t, ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "absent": true, "title": "x"}`)).Error(),
+		"$.title: unknown key")
+}
+
+func Test_UnmarshalStrict(t *testing.T) {
+	subject := &ExpectedSubject{}
+	require.Nil(t, UnmarshalStrict([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Photography", "unique_id": "s_1",
+		"authority": [], "description": {"value": "Photography", "format": "basic_html", "processed": "Photography"},
+		"verify_only": ["name"]}`), subject, "verify_only"))
+	assert.Equal(t, "Photography", subject.Description.Value)
+
+	err := UnmarshalStrict([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Photography", "unqiue_id": "s_1",
+		"authority": [], "description": {"value": "Photography"}, "absent": true}`), &ExpectedSubject{})
+	require.True(t, errors.Is(err, jsonapi.ErrStrict))
+	for _, violation := range []string{
+		"$.absent: unknown field",
+		"$.unqiue_id: unknown field",
+		"$.unique_id: never populated",
+		"$.description.format: never populated",
+	} {
+		assert.Contains(t, err.Error(), violation)
+	}
+	assert.NotContains(t, err.Error(), "translations", "omitempty fields need not be populated")
+
+	assert.Nil(t, UnmarshalStrict([]byte(`{"type": "node", "bundle": "islandora_object", "absent": true, "value": "Withdrawn"}`),
+		&ExpectedAbsent{}))
+
+	defer UnregisterFields(Node, Collection)
+	MustRegisterField(Node, Collection, ExtraField{Key: "rights_note", Generate: FromAttribute("field_rights_note")})
+	collection := &ExpectedCollection{}
+	err = UnmarshalStrict([]byte(`{"type": "node", "bundle": "collection_object", "title": "Ansel Adams", "rights_note": "Public"}`),
+		collection)
+	assert.NotContains(t, err.Error(), "rights_note")
+	assert.Equal(t, "Public", collection.Extra["rights_note"])
+}
+
+func Test_ValidateExtraFields(t *testing.T) {
+	defer UnregisterFields(Node, RepositoryObject)
+	MustRegisterField(Node, RepositoryObject, ExtraField{Key: "rights_note", Generate: FromAttribute("field_rights_note"),
+		New: func() interface{} { return new(string) }})
+
+	assert.Nil(t, ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "rights_note": "Public domain"}`)))
+	assert.Contains(t, ValidateFixture([]byte(`{"type": "node", "bundle": "islandora_object", "rights_note": 1}`)).Error(),
+		"$.rights_note: expected string, got integer")
+}
@@ -0,0 +1,244 @@
+// Validates the configuration of a verification run before it starts, so that a misconfiguration (an unreachable
+// site, rejected credentials, or a missing vocabulary) is reported once, with a hint for correcting it, rather than as
+// the same failure of hundreds of entities:
+//
+//	report := preflight.NewChecker(baseUrl, username, password).Run()
+//	if !report.Passed() {
+//		report.WriteText(os.Stderr)
+//		os.Exit(1)
+//	}
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The vocabularies checked by a Checker if it names none: those referenced by IDC migrations
+var DefaultVocabularies = []string{model.AccessRights, model.CopyrightAndUse, model.CorporateBody, model.Family,
+	model.Genre, model.GeoLocation, model.IslandoraAccess, model.Language, model.Person, model.ResourceTypes, model.Subject}
+
+// The prefixes of the names of the checks of reachability and credentials
+const (
+	reachableCheck   = "base url "
+	credentialsCheck = "credentials of "
+)
+
+// The outcome of a single check
+type Check struct {
+	Name string
+	// Set if the check failed
+	Err error
+	// How the configuration may be corrected, if the check failed
+	Hint string
+	// Set if the check was not performed because a check it depends on failed
+	Skipped bool
+}
+
+// Answers the check as e.g. `FAIL credentials: 403 status ... (hint: ...)`
+func (c Check) String() string {
+	switch {
+	case c.Skipped:
+		return fmt.Sprintf("SKIP %s", c.Name)
+	case c.Err == nil:
+		return fmt.Sprintf("OK   %s", c.Name)
+	case c.Hint == "":
+		return fmt.Sprintf("FAIL %s: %s", c.Name, c.Err)
+	}
+	return fmt.Sprintf("FAIL %s: %s\n     hint: %s", c.Name, c.Err, c.Hint)
+}
+
+// The outcomes of the checks of a Checker, in the order they were performed
+type Report struct {
+	Checks []Check
+}
+
+// Answers true if every check was performed and passed
+func (r *Report) Passed() bool {
+	for _, c := range r.Checks {
+		if c.Err != nil || c.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Answers an error describing each failed check, or nil if every check passed
+func (r *Report) Err() error {
+	if r.Passed() {
+		return nil
+	}
+	var failures []string
+	for _, c := range r.Checks {
+		if c.Err != nil {
+			failures = append(failures, c.String())
+		}
+	}
+	return fmt.Errorf("preflight: the configuration is invalid:\n%s", strings.Join(failures, "\n"))
+}
+
+// Answers false if the site was unreachable or rejected the credentials, as opposed to lacking a resource type
+func (r *Report) Reachable() bool {
+	for _, c := range r.Checks {
+		if c.Err != nil && (strings.HasPrefix(c.Name, reachableCheck) || strings.HasPrefix(c.Name, credentialsCheck)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Writes a line per check
+func (r *Report) WriteText(w io.Writer) error {
+	for _, c := range r.Checks {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Checks that a Drupal site is reachable, that the credentials are accepted, and that the required vocabularies and
+// resource types exist.  The checks request only the JSON:API entry point, which lists the resource types of the site
+// and, for an authenticated request, the current user.
+type Checker struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The vocabularies which must exist; DefaultVocabularies if nil
+	Vocabularies []string
+	// Other resource types which must exist, e.g. `node--islandora_object`
+	ResourceTypes []string
+}
+
+// Creates a Checker for the Drupal site at the base url, checking DefaultVocabularies and the node bundles of IDC
+func NewChecker(baseUrl, username, password string) *Checker {
+	return &Checker{BaseUrl: baseUrl, Username: username, Password: env.Secret(password), ResourceTypes: []string{
+		model.Node + "--" + model.Collection, model.Node + "--" + model.RepositoryObject}}
+}
+
+// The links and meta of the JSON:API entry point
+type entryPoint struct {
+	Links map[string]interface{} `json:"links"`
+	Meta  struct {
+		Links struct {
+			Me *struct {
+				Href string `json:"href"`
+			} `json:"me"`
+		} `json:"links"`
+	} `json:"meta"`
+}
+
+// Performs each check, skipping those that depend on a failed check
+func (c *Checker) Run() *Report {
+	r := &Report{}
+	entry, ok := c.checkReachable(r)
+	if ok && strings.TrimSpace(c.Username) != "" {
+		ok = c.checkCredentials(r, entry)
+	}
+
+	vocabularies := c.Vocabularies
+	if vocabularies == nil {
+		vocabularies = DefaultVocabularies
+	}
+	var types []string
+	for _, v := range vocabularies {
+		types = append(types, model.TaxonomyTerm+"--"+v)
+	}
+	types = append(types, c.ResourceTypes...)
+	for _, t := range types {
+		check := Check{Name: "resource type " + t}
+		switch _, exists := entry.Links[t]; {
+		case !ok:
+			check.Skipped = true
+		case !exists:
+			check.Err = fmt.Errorf("%s is not listed by the JSON:API entry point", t)
+			check.Hint = "create the vocabulary or bundle, or confirm that JSON:API exposes it and the user may view it"
+		}
+		r.Checks = append(r.Checks, check)
+	}
+	return r
+}
+
+// Checks that the base url is valid and its JSON:API entry point answers, answering the entry point
+func (c *Checker) checkReachable(r *Report) (*entryPoint, bool) {
+	check := Check{Name: reachableCheck + c.BaseUrl}
+	defer func() { r.Checks = append(r.Checks, check) }()
+
+	u, err := url.Parse(c.BaseUrl)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		check.Err = fmt.Errorf("'%s' is not an absolute url", c.BaseUrl)
+		check.Hint = "set DRUPAL_BASE_URL to the url of the site, e.g. https://islandora-idc.traefik.me"
+		return &entryPoint{}, false
+	}
+	res, entry, err := c.get("", "")
+	switch {
+	case err != nil && res == nil:
+		check.Err = err
+		check.Hint = "confirm that the site is running and that its host name resolves from here"
+	case err != nil:
+		check.Err = err
+		check.Hint = "confirm that the JSON:API module is enabled, and that the base url does not include a path like /jsonapi"
+	}
+	return entry, check.Err == nil
+}
+
+// Checks that the credentials are accepted, i.e. that an authenticated request of the entry point identifies the user
+func (c *Checker) checkCredentials(r *Report, anonymous *entryPoint) bool {
+	check := Check{Name: credentialsCheck + c.Username}
+	defer func() { r.Checks = append(r.Checks, check) }()
+
+	res, entry, err := c.get(c.Username, c.Password.Reveal())
+	switch {
+	case res != nil && (res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden):
+		check.Err = fmt.Errorf("%d status answered for the credentials", res.StatusCode)
+		check.Hint = "confirm DRUPAL_USERNAME and DRUPAL_PASSWORD, and that the account is not blocked"
+	case err != nil:
+		check.Err = err
+	case entry.Meta.Links.Me == nil:
+		check.Err = fmt.Errorf("the site did not authenticate the request")
+		check.Hint = "confirm that the HTTP Basic Authentication module is enabled and that JSON:API accepts basic_auth"
+	}
+	if check.Err == nil {
+		*anonymous = *entry
+	}
+	return check.Err == nil
+}
+
+// Requests the JSON:API entry point, authenticating if the username is not empty
+func (c *Checker) get(username, password string) (*http.Response, *entryPoint, error) {
+	u := strings.TrimSuffix(c.BaseUrl, "/") + "/jsonapi"
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, &entryPoint{}, err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	req.Header.Set("Accept", "application/vnd.api+json")
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return nil, &entryPoint{}, fmt.Errorf("error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res, &entryPoint{}, fmt.Errorf("error reading response body from %s: %w", u, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return res, &entryPoint{}, fmt.Errorf("%d status encountered when requesting %s", res.StatusCode, u)
+	}
+	entry := &entryPoint{}
+	if err := json.Unmarshal(body, entry); err != nil || entry.Links == nil {
+		return res, &entryPoint{}, fmt.Errorf("%s did not answer a JSON:API entry point", u)
+	}
+	return res, entry, nil
+}
@@ -0,0 +1,77 @@
+package preflight
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jsonapi" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		username, password, ok := r.BasicAuth()
+		switch {
+		case !ok:
+			_, _ = w.Write([]byte(`{"links": {"node--islandora_object": {"href": "/jsonapi/node/islandora_object"}}, "meta": {}}`))
+		case username == "admin" && password == "password":
+			_, _ = w.Write([]byte(`{"links": {"node--islandora_object": {"href": "/jsonapi/node/islandora_object"},
+				"taxonomy_term--genre": {"href": "/jsonapi/taxonomy_term/genre"}},
+				"meta": {"links": {"me": {"href": "/jsonapi/user/user/u1", "meta": {"id": "u1"}}}}}`))
+		case username == "ignored":
+			_, _ = w.Write([]byte(`{"links": {}, "meta": {}}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+}
+
+func Test_Run(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+
+	c := &Checker{BaseUrl: server.URL, Username: "admin", Password: "password", Vocabularies: []string{"genre"}, ResourceTypes: []string{"node--islandora_object"}}
+	r := c.Run()
+	assert.True(t, r.Passed(), "%v", r.Checks)
+	assert.Nil(t, r.Err())
+	assert.Equal(t, 4, len(r.Checks))
+
+	c.Vocabularies = []string{"genre", "subject"}
+	r = c.Run()
+	require.NotNil(t, r.Err())
+	assert.Contains(t, r.Err().Error(), "taxonomy_term--subject is not listed")
+	assert.True(t, r.Reachable())
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteText(buf))
+	assert.Contains(t, buf.String(), "OK   resource type taxonomy_term--genre\n")
+
+	// anonymous requests see only the islandora_object bundle
+	r = (&Checker{BaseUrl: server.URL, Vocabularies: []string{"genre"}}).Run()
+	assert.False(t, r.Passed())
+	assert.Equal(t, "FAIL resource type taxonomy_term--genre", r.Checks[1].String()[:39])
+
+	r = (&Checker{BaseUrl: server.URL, Username: "admin", Password: "wrong"}).Run()
+	assert.False(t, r.Passed())
+	assert.Contains(t, r.Checks[1].String(), "403 status answered for the credentials")
+	assert.False(t, r.Reachable())
+	assert.True(t, r.Checks[2].Skipped)
+	assert.Contains(t, r.Err().Error(), "hint: confirm DRUPAL_USERNAME")
+	assert.NotContains(t, r.Err().Error(), "SKIP")
+
+	r = (&Checker{BaseUrl: server.URL, Username: "ignored"}).Run()
+	assert.Contains(t, r.Checks[1].String(), "did not authenticate")
+
+	r = NewChecker(server.URL+"/drupal", "", "").Run()
+	assert.Contains(t, r.Checks[0].String(), "404 status")
+	assert.False(t, r.Reachable())
+	assert.Equal(t, 1+len(DefaultVocabularies)+2, len(r.Checks))
+
+	r = NewChecker("islandora-idc.traefik.me", "", "").Run()
+	assert.Contains(t, r.Err().Error(), "is not an absolute url")
+}
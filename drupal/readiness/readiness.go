@@ -0,0 +1,231 @@
+// Waits until the services of an IDC stack are ready to be tested, so that a suite started alongside
+// `docker-compose up` does not race the startup of Drupal and its dependencies:
+//
+//	func TestMain(m *testing.M) {
+//		w, err := readiness.FromEnv()
+//		if err == nil {
+//			err = w.Wait()
+//		}
+//		if err != nil {
+//			log.Fatalf("%s", err)
+//		}
+//		os.Exit(m.Run())
+//	}
+//
+// Drupal is ready once its JSON:API entry point and login page answer.  Solr and Fedora are checked too if their urls
+// are configured (see SolrUrlEnv and FedoraUrlEnv).
+package readiness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+)
+
+// The environment variables configuring FromEnv
+const (
+	// Names the checks performed, separated by commas, e.g. `jsonapi,solr`; every configured check if unset
+	ChecksEnv = "IDC_READY_CHECKS"
+	// The url of a Solr endpoint answering once Solr is ready, e.g. `http://solr:8983/solr/ISLANDORA/admin/ping`
+	SolrUrlEnv = "IDC_READY_SOLR_URL"
+	// The url of a Fedora endpoint answering once Fedora is ready, e.g. `http://fcrepo:8080/fcrepo/rest`
+	FedoraUrlEnv = "IDC_READY_FEDORA_URL"
+	// How long to wait for readiness, e.g. `5m`; DefaultTimeout if unset
+	TimeoutEnv = "IDC_READY_TIMEOUT"
+)
+
+// The names of the checks answered by DrupalChecks and FromEnv
+const (
+	JsonApi = "jsonapi"
+	Login   = "login"
+	Solr    = "solr"
+	Fedora  = "fedora"
+)
+
+// How long a Waiter waits, unless configured otherwise
+const DefaultTimeout = 5 * time.Minute
+
+// The interval between the polls of a Waiter, unless configured otherwise
+var PollInterval = 2 * time.Second
+
+// A service was not ready before the timeout elapsed
+var ErrNotReady = errors.New("readiness: not ready")
+
+// A health check of a service
+type Check struct {
+	// Names the check, e.g. `jsonapi`
+	Name string
+	// The url requested
+	Url      string
+	Username string
+	Password env.Secret
+	// The statuses answered by a ready service; 200 if empty
+	Statuses []int
+	// Answers an error if the body of a response of a ready status does not show the service to be ready, e.g. the
+	// installer page of a Drupal not yet installed; optional
+	Validate func(body []byte) error
+}
+
+// Requests the url of the check, answering nil if the service is ready
+func (c Check) Probe() error {
+	req, err := http.NewRequest(http.MethodGet, c.Url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password.Reveal())
+	}
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: error requesting %s: %w", c.Name, c.Url, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("%s: error reading response body from %s: %w", c.Name, c.Url, err)
+	}
+	statuses := c.Statuses
+	if len(statuses) == 0 {
+		statuses = []int{http.StatusOK}
+	}
+	ready := false
+	for _, s := range statuses {
+		ready = ready || s == res.StatusCode
+	}
+	if !ready {
+		return fmt.Errorf("%s: %d status encountered when requesting %s", c.Name, res.StatusCode, c.Url)
+	}
+	if c.Validate != nil {
+		if err := c.Validate(body); err != nil {
+			return fmt.Errorf("%s: %s: %w", c.Name, c.Url, err)
+		}
+	}
+	return nil
+}
+
+// Answers the checks of the Drupal site at the base url: that its JSON:API entry point answers, and that its login
+// page answers
+func DrupalChecks(baseUrl string) []Check {
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+	return []Check{
+		{Name: JsonApi, Url: baseUrl + "/jsonapi", Validate: entryPoint},
+		{Name: Login, Url: baseUrl + "/user/login"},
+	}
+}
+
+// Answers an error unless the body is a JSON:API entry point, which links to the resources of the site
+func entryPoint(body []byte) error {
+	doc := struct {
+		Links map[string]interface{} `json:"links"`
+	}{}
+	if err := json.Unmarshal(body, &doc); err != nil || len(doc.Links) == 0 {
+		return errors.New("not a JSON:API entry point")
+	}
+	return nil
+}
+
+// Polls checks until every service is ready
+type Waiter struct {
+	Checks []Check
+	// How long Wait waits; DefaultTimeout if not positive
+	Timeout time.Duration
+	// The interval between polls; PollInterval if not positive
+	Interval time.Duration
+}
+
+// Creates a Waiter of the checks, waiting DefaultTimeout
+func NewWaiter(checks ...Check) *Waiter {
+	return &Waiter{Checks: checks, Timeout: DefaultTimeout}
+}
+
+// Creates a Waiter of the checks configured by the environment: those of the Drupal site at DRUPAL_BASE_URL (see
+// DrupalChecks), and of Solr and Fedora if SolrUrlEnv and FedoraUrlEnv are set.  If ChecksEnv is set, only the checks
+// it names are performed.  The Waiter waits for TimeoutEnv, or DefaultTimeout.  An error is answered if the
+// environment is invalid, e.g. if ChecksEnv names a check that is not configured.
+func FromEnv() (*Waiter, error) {
+	baseUrl, err := env.BaseUrlE()
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := env.GetEnvOrDuration(TimeoutEnv, DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	checks := DrupalChecks(baseUrl)
+	for _, service := range []struct{ name, envVar string }{{Solr, SolrUrlEnv}, {Fedora, FedoraUrlEnv}} {
+		if u := env.GetEnvOr(service.envVar, ""); u != "" {
+			checks = append(checks, Check{Name: service.name, Url: u})
+		}
+	}
+
+	if names := env.GetEnvOr(ChecksEnv, ""); names != "" {
+		var selected []Check
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			found := false
+			for _, c := range checks {
+				if c.Name == name {
+					selected, found = append(selected, c), true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%w: %s names check '%s', which is not configured", env.ErrInvalidConfig, ChecksEnv, name)
+			}
+		}
+		checks = selected
+	}
+	return &Waiter{Checks: checks, Timeout: timeout}, nil
+}
+
+// Probes each check once, answering the errors of the checks that are not ready, keyed by name
+func (w *Waiter) Poll() map[string]error {
+	failures := map[string]error{}
+	for _, c := range w.Checks {
+		if err := c.Probe(); err != nil {
+			failures[c.Name] = err
+		}
+	}
+	return failures
+}
+
+// Polls the checks until every service is ready, or the timeout elapses.  Services found ready are not checked again.
+// An error wrapping ErrNotReady, describing the last failure of each service that is not ready, is answered if the
+// timeout elapses.
+func (w *Waiter) Wait() error {
+	timeout, interval := w.Timeout, w.Interval
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if interval <= 0 {
+		interval = PollInterval
+	}
+	deadline := time.Now().Add(timeout)
+	pending := append([]Check(nil), w.Checks...)
+	for {
+		var failures []string
+		var remaining []Check
+		for _, c := range pending {
+			if err := c.Probe(); err != nil {
+				failures = append(failures, err.Error())
+				remaining = append(remaining, c)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		pending = remaining
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("%w after %s:\n  %s", ErrNotReady, timeout, strings.Join(failures, "\n  "))
+		}
+		logging.Infof("Waiting for %d services to be ready: %s", len(pending), strings.Join(failures, "; "))
+		time.Sleep(interval)
+	}
+}
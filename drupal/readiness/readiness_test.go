@@ -0,0 +1,105 @@
+package readiness
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Answers a Drupal that is ready once it has answered the supplied number of requests for its JSON:API entry point
+func newDrupal(startup int32) *httptest.Server {
+	var requests int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi":
+			if atomic.AddInt32(&requests, 1) <= startup {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"data": [], "links": {"node--islandora_object": {"href": "/jsonapi/node/islandora_object"}}}`))
+		case "/user/login":
+			w.Write([]byte(`<html>Log in</html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func Test_Wait(t *testing.T) {
+	drupal := newDrupal(2)
+	defer drupal.Close()
+
+	w := NewWaiter(DrupalChecks(drupal.URL + "/")...)
+	w.Interval = time.Millisecond
+	assert.Contains(t, w.Poll(), JsonApi)
+	assert.NotContains(t, w.Poll(), Login)
+	require.Nil(t, w.Wait())
+	assert.Empty(t, w.Poll())
+
+	w = NewWaiter(Check{Name: "installer", Url: drupal.URL + "/user/login", Validate: entryPoint},
+		Check{Name: Solr, Url: drupal.URL + "/solr/admin/ping"})
+	w.Timeout, w.Interval = 5*time.Millisecond, time.Millisecond
+	err := w.Wait()
+	require.True(t, errors.Is(err, ErrNotReady))
+	assert.Contains(t, err.Error(), "installer: "+drupal.URL+"/user/login: not a JSON:API entry point")
+	assert.Contains(t, err.Error(), "solr: 404 status encountered when requesting "+drupal.URL+"/solr/admin/ping")
+
+	w.Checks[1].Statuses = []int{http.StatusNotFound}
+	assert.Equal(t, []string{"installer"}, keys(w.Poll()))
+}
+
+func Test_FromEnv(t *testing.T) {
+	for k, v := range map[string]string{"DRUPAL_BASE_URL": "https://islandora-idc.traefik.me", SolrUrlEnv: "http://solr:8983/solr/ISLANDORA/admin/ping",
+		FedoraUrlEnv: "", ChecksEnv: "", TimeoutEnv: "90s"} {
+		prev, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		defer func(k, prev string, ok bool) {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		}(k, prev, ok)
+	}
+
+	w, err := FromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, 90*time.Second, w.Timeout)
+	var urls []string
+	for _, c := range w.Checks {
+		urls = append(urls, c.Name+" "+c.Url)
+	}
+	assert.Equal(t, []string{"jsonapi https://islandora-idc.traefik.me/jsonapi", "login https://islandora-idc.traefik.me/user/login",
+		"solr http://solr:8983/solr/ISLANDORA/admin/ping"}, urls)
+
+	os.Setenv(ChecksEnv, "solr, jsonapi")
+	w, err = FromEnv()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(w.Checks))
+	assert.Equal(t, Solr, w.Checks[0].Name)
+	assert.Equal(t, JsonApi, w.Checks[1].Name)
+
+	os.Setenv(ChecksEnv, "fedora")
+	_, err = FromEnv()
+	assert.True(t, errors.Is(err, env.ErrInvalidConfig))
+	os.Setenv(ChecksEnv, "")
+	os.Setenv(TimeoutEnv, "soon")
+	_, err = FromEnv()
+	assert.NotNil(t, err)
+}
+
+func keys(m map[string]error) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,156 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The path of this library's module, whose version is recorded by CaptureEnvironment
+const modulePath = "github.com/jhu-idc/idc-golang"
+
+// The environment variables carrying the git commit of the code under test, as set by common CI systems, recorded by
+// CaptureEnvironment if set
+var GitShaEnvVars = []string{"GIT_SHA", "GIT_COMMIT", "GITHUB_SHA", "CI_COMMIT_SHA", "BUILDKITE_COMMIT", "CIRCLE_SHA1",
+	"TRAVIS_COMMIT", "BUILD_VCS_NUMBER"}
+
+// Matches the version of Drupal in the first line of core/CHANGELOG.txt, e.g. `Drupal 9.2.6, 2021-09-15`
+var changelogVersion = regexp.MustCompile(`^Drupal (\d+\.\d+\.\d+[^\s,]*)`)
+
+// Matches the major version of Drupal in the X-Generator header, e.g. `Drupal 9 (https://www.drupal.org)`
+var generatorVersion = regexp.MustCompile(`^Drupal (\d+)`)
+
+// The details of the environment a report was made in, so that a report remains interpretable long after its run
+type Environment struct {
+	// The base url of the Drupal site verified
+	BaseUrl string `json:"base_url,omitempty"`
+	// The base url of the assets server
+	AssetsBaseUrl string `json:"assets_base_url,omitempty"`
+	// The name of the active env.Profile, if any
+	Profile string `json:"profile,omitempty"`
+	// The version of Drupal, e.g. `9.2.6`, or only its major version if no more is exposed; see ProbeDrupal
+	DrupalVersion string `json:"drupal_version,omitempty"`
+	// The versions of the enabled Drupal modules, keyed by module name, if the site exposes them
+	Modules map[string]string `json:"modules,omitempty"`
+	// The git commits of the code under test, keyed by the environment variables carrying them (see GitShaEnvVars)
+	Git map[string]string `json:"git,omitempty"`
+	// The version of this library, e.g. `v1.4.0`, or `(devel)` if it is the main module
+	LibraryVersion string `json:"library_version,omitempty"`
+	GoVersion      string `json:"go_version,omitempty"`
+}
+
+// Captures the details of the environment that are known locally: the base urls of Drupal and the assets server
+// (see env.BaseUrlOr), the active profile, the git commits of GitShaEnvVars, and the versions of this library and of
+// Go.  The version of Drupal is captured by ProbeDrupal.
+func CaptureEnvironment() *Environment {
+	e := &Environment{
+		BaseUrl:        env.BaseUrlOr(""),
+		AssetsBaseUrl:  env.AssetsBaseUrlOr(""),
+		LibraryVersion: libraryVersion(),
+		GoVersion:      runtime.Version(),
+	}
+	e.Profile, _, _ = env.ActiveProfile()
+	for _, envVar := range GitShaEnvVars {
+		if sha := os.Getenv(envVar); sha != "" {
+			if e.Git == nil {
+				e.Git = map[string]string{}
+			}
+			e.Git[envVar] = sha
+		}
+	}
+	return e
+}
+
+// Records the version of the Drupal site at the base url: the full version from `core/CHANGELOG.txt` if it is served,
+// otherwise the major version from the X-Generator header of the front page.  An error is answered if neither is
+// available.
+func (e *Environment) ProbeDrupal(baseUrl string) error {
+	e.BaseUrl = baseUrl
+	base := strings.TrimSuffix(baseUrl, "/")
+	if res, err := jsonapi.HTTPClient().Get(base + "/core/CHANGELOG.txt"); err == nil {
+		defer res.Body.Close()
+		if res.StatusCode == http.StatusOK {
+			s := bufio.NewScanner(res.Body)
+			for s.Scan() {
+				if m := changelogVersion.FindStringSubmatch(strings.TrimSpace(s.Text())); m != nil {
+					e.DrupalVersion = m[1]
+					return nil
+				}
+				if strings.TrimSpace(s.Text()) != "" {
+					break
+				}
+			}
+		}
+	}
+	res, err := jsonapi.HTTPClient().Get(base + "/")
+	if err != nil {
+		return fmt.Errorf("report: error probing the version of Drupal at %s: %w", baseUrl, err)
+	}
+	res.Body.Close()
+	if m := generatorVersion.FindStringSubmatch(res.Header.Get("X-Generator")); m != nil {
+		e.DrupalVersion = m[1]
+		return nil
+	}
+	return fmt.Errorf("report: %s exposes no version of Drupal", baseUrl)
+}
+
+// Answers the environment as e.g. `Drupal 9.2.6 at https://islandora-idc.traefik.me, idc-golang v1.4.0, go1.16.15,
+// GITHUB_SHA 1a2b3c4`
+func (e *Environment) String() string {
+	var parts []string
+	drupal := "Drupal"
+	if e.DrupalVersion != "" {
+		drupal += " " + e.DrupalVersion
+	}
+	if e.BaseUrl != "" {
+		drupal += " at " + e.BaseUrl
+	}
+	if e.Profile != "" {
+		drupal += fmt.Sprintf(" (profile %s)", e.Profile)
+	}
+	parts = append(parts, drupal)
+	if e.LibraryVersion != "" {
+		parts = append(parts, "idc-golang "+e.LibraryVersion)
+	}
+	if e.GoVersion != "" {
+		parts = append(parts, e.GoVersion)
+	}
+	vars := make([]string, 0, len(e.Git))
+	for envVar := range e.Git {
+		vars = append(vars, envVar)
+	}
+	sort.Strings(vars)
+	for _, envVar := range vars {
+		parts = append(parts, envVar+" "+e.Git[envVar])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Answers the version of this library from the build info of the binary, or the empty string if it is unavailable
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CaptureEnvironment(t *testing.T) {
+	for k, v := range map[string]string{"DRUPAL_BASE_URL": "https://islandora-idc.traefik.me", "GITHUB_SHA": "1a2b3c4", "GIT_SHA": ""} {
+		prev, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		defer func(k, prev string, ok bool) {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		}(k, prev, ok)
+	}
+
+	e := CaptureEnvironment()
+	assert.Equal(t, "https://islandora-idc.traefik.me", e.BaseUrl)
+	assert.Equal(t, map[string]string{"GITHUB_SHA": "1a2b3c4"}, e.Git)
+	assert.Equal(t, runtime.Version(), e.GoVersion)
+	assert.Equal(t, e, New(time.Now()).Environment)
+
+	e.DrupalVersion, e.LibraryVersion, e.GoVersion = "9.2.6", "v1.4.0", "go1.16.15"
+	assert.Equal(t, "Drupal 9.2.6 at https://islandora-idc.traefik.me, idc-golang v1.4.0, go1.16.15, GITHUB_SHA 1a2b3c4", e.String())
+	assert.Equal(t, "Drupal", (&Environment{}).String())
+}
+
+func Test_ProbeDrupal(t *testing.T) {
+	changelog := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/core/CHANGELOG.txt":
+			if changelog {
+				w.Write([]byte("Drupal 9.2.6, 2021-09-15\n-----------------------\n- Fixed security issues"))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case "/":
+			w.Header().Set("X-Generator", "Drupal 9 (https://www.drupal.org)")
+		}
+	}))
+	defer server.Close()
+
+	e := &Environment{}
+	require.Nil(t, e.ProbeDrupal(server.URL+"/"))
+	assert.Equal(t, "9.2.6", e.DrupalVersion)
+	assert.Equal(t, server.URL+"/", e.BaseUrl)
+
+	changelog = false
+	require.Nil(t, e.ProbeDrupal(server.URL))
+	assert.Equal(t, "9", e.DrupalVersion)
+
+	unversioned := httptest.NewServer(http.NotFoundHandler())
+	defer unversioned.Close()
+	err := (&Environment{}).ProbeDrupal(unversioned.URL)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "exposes no version of Drupal")
+}
+
+func Test_WriteEnvironment(t *testing.T) {
+	r := newReport()
+	r.Environment = &Environment{BaseUrl: "https://islandora-idc.traefik.me", DrupalVersion: "9.2.6",
+		Modules: map[string]string{"islandora": "8.x-2.1"}}
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteText(buf))
+	assert.Contains(t, buf.String(), "\nDrupal 9.2.6 at https://islandora-idc.traefik.me\n")
+
+	buf.Reset()
+	require.Nil(t, r.WriteJson(buf))
+	require.Nil(t, Validate(buf.Bytes()))
+	doc := struct {
+		DurationMs  int64        `json:"duration_ms"`
+		Environment *Environment `json:"environment"`
+	}{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, int64(3000), doc.DurationMs)
+	assert.Equal(t, r.Environment, doc.Environment)
+}
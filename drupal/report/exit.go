@@ -0,0 +1,70 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// The exit status of a verification run, so that CI pipelines may branch on the class of its outcome
+type ExitCode int
+
+const (
+	// Every result passed (or, failing on errors only, passed with warnings)
+	ExitPassed ExitCode = 0
+	// A result failed or errored, or, failing on warnings, a result carried a warning
+	ExitFailed ExitCode = 1
+	// The configuration of the run was invalid, e.g. a missing base url or an unknown flag
+	ExitConfig ExitCode = 2
+	// The Drupal site was unreachable, or rejected the credentials of the run
+	ExitUnreachable ExitCode = 3
+	// The run itself failed, e.g. its fixtures could not be listed or its report could not be written
+	ExitInternal ExitCode = 4
+)
+
+// The least severe outcome of a result that fails a run
+type FailOn string
+
+const (
+	// Results that failed or errored fail the run
+	FailOnErrors FailOn = "errors"
+	// Results carrying a warning fail the run too: drift (see verify.Result.Drift) or unverified keys
+	FailOnWarnings FailOn = "warnings"
+)
+
+// Parses a FailOn, e.g. `warnings`
+func ParseFailOn(s string) (FailOn, error) {
+	switch f := FailOn(s); f {
+	case FailOnErrors, FailOnWarnings:
+		return f, nil
+	}
+	return "", fmt.Errorf("report: unknown failure threshold '%s', expected '%s' or '%s'", s, FailOnErrors,
+		FailOnWarnings)
+}
+
+// Answers true if the result passed, but drifted or left keys unverified
+func Warned(result *verify.Result) bool {
+	return result.Passed() && (len(result.Drift) > 0 || len(result.Unverified) > 0)
+}
+
+// Answers ExitFailed if a result failed or errored, or if failOn is FailOnWarnings and a result carried a warning
+// (see Warned); otherwise ExitPassed
+func (r *Report) ExitCode(failOn FailOn) (ExitCode, error) {
+	if !r.Passed() {
+		return ExitFailed, nil
+	}
+	if failOn != FailOnWarnings {
+		return ExitPassed, nil
+	}
+	code := ExitPassed
+	err := r.each(func(_ int, result *verify.Result) error {
+		if Warned(result) {
+			code = ExitFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return ExitInternal, err
+	}
+	return code, nil
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseFailOn(t *testing.T) {
+	f, err := ParseFailOn("warnings")
+	require.Nil(t, err)
+	assert.Equal(t, FailOnWarnings, f)
+	_, err = ParseFailOn("never")
+	assert.NotNil(t, err)
+}
+
+func Test_ExitCode(t *testing.T) {
+	passed := &verify.Result{Key: "Moonrise"}
+	drifted := &verify.Result{Key: "Moonset", Drift: []verify.Mismatch{{Path: "featured_item", Expected: true, Actual: 1}}}
+	unverified := &verify.Result{Key: "Sunrise", Unverified: []string{"extent"}}
+	failed := &verify.Result{Key: "Sunset", Mismatches: []verify.Mismatch{{Path: "genre[0]", Expected: "Maps", Actual: "Map"}}}
+	errored := &verify.Result{Key: "Noon", Err: errors.New("404 status")}
+
+	assert.False(t, Warned(passed))
+	assert.True(t, Warned(drifted))
+	assert.True(t, Warned(unverified))
+	assert.False(t, Warned(failed))
+
+	for _, c := range []struct {
+		results []*verify.Result
+		failOn  FailOn
+		code    ExitCode
+	}{
+		{[]*verify.Result{passed}, FailOnWarnings, ExitPassed},
+		{[]*verify.Result{passed, drifted}, FailOnErrors, ExitPassed},
+		{[]*verify.Result{passed, drifted}, FailOnWarnings, ExitFailed},
+		{[]*verify.Result{unverified}, FailOnWarnings, ExitFailed},
+		{[]*verify.Result{passed, failed}, FailOnErrors, ExitFailed},
+		{[]*verify.Result{errored}, FailOnErrors, ExitFailed},
+	} {
+		code, err := New(time.Now(), c.results...).ExitCode(c.failOn)
+		require.Nil(t, err)
+		assert.Equal(t, c.code, code, "%d results failing on %s", len(c.results), c.failOn)
+	}
+
+	// results spilled to disk are classified too
+	r := &Report{MaxResults: 1}
+	defer r.Close()
+	require.Nil(t, r.Add(drifted, passed))
+	require.Equal(t, 1, r.Spilled())
+	code, err := r.ExitCode(FailOnWarnings)
+	require.Nil(t, err)
+	assert.Equal(t, ExitFailed, code)
+}
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// The name of the test suites written by WriteJUnit
+const junitName = "idc-verify"
+
+// A JUnit test case of a result
+type junitCase struct {
+	XMLName    xml.Name         `xml:"testcase"`
+	Name       string           `xml:"name,attr"`
+	Classname  string           `xml:"classname,attr"`
+	File       string           `xml:"file,attr,omitempty"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitFailure    `xml:"failure,omitempty"`
+	Error      *junitFailure    `xml:"error,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Property []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Writes the report as JUnit XML, for CI servers that chart the outcomes of tests: a test suite per entity type and
+// bundle (e.g. `node--islandora_object`), ordered by type and bundle, holding a test case per result named by the key
+// of its fixture.  The PID of a result, and the keys of its fixture that were checked and that were unverified, are
+// properties of its test case.  Failed results are failures and errored results are errors, detailed as they are by
+// WriteText; the drift of a passed result is written as its output.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	s := r.Summary()
+	err := enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "testsuites"}, Attr: []xml.Attr{
+		attr("name", junitName), attr("tests", strconv.Itoa(s.Total)), attr("failures", strconv.Itoa(s.Failed)),
+		attr("errors", strconv.Itoa(s.Errored)), attr("time", seconds(r.Finished.Sub(r.Started))),
+	}})
+	if err != nil {
+		return err
+	}
+	for _, b := range r.Bundles() {
+		if err := r.writeJUnitSuite(enc, b); err != nil {
+			return err
+		}
+	}
+	if err := enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "testsuites"}}); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// Writes the test suite of the results of the entity type and bundle
+func (r *Report) writeJUnitSuite(enc *xml.Encoder, b BundleSummary) error {
+	suite := xml.StartElement{Name: xml.Name{Local: "testsuite"}, Attr: []xml.Attr{
+		attr("name", typeName(b.Type, b.Bundle)), attr("tests", strconv.Itoa(b.Total)), attr("failures", strconv.Itoa(b.Failed)),
+		attr("errors", strconv.Itoa(b.Errored)),
+	}}
+	if !r.Started.IsZero() {
+		suite.Attr = append(suite.Attr, attr("timestamp", r.Started.UTC().Format("2006-01-02T15:04:05")))
+	}
+	if err := enc.EncodeToken(suite); err != nil {
+		return err
+	}
+	err := r.each(func(_ int, result *verify.Result) error {
+		if result.Type != b.Type || result.Bundle != b.Bundle {
+			return nil
+		}
+		return enc.Encode(junitCaseOf(result))
+	})
+	if err != nil {
+		return err
+	}
+	return enc.EncodeToken(suite.End())
+}
+
+// Answers the test case of the result
+func junitCaseOf(result *verify.Result) junitCase {
+	c := junitCase{Name: result.Key, Classname: typeName(result.Type, result.Bundle), File: result.Fixture,
+		Time: seconds(result.Duration)}
+	if c.Name == "" {
+		c.Name = result.Fixture
+	}
+	for _, p := range []struct {
+		name   string
+		values []string
+	}{{"pid", []string{result.Pid}}, {"checked", result.Checked}, {"unverified", result.Unverified}} {
+		if v := strings.Join(p.values, ", "); v != "" {
+			if c.Properties == nil {
+				c.Properties = &junitProperties{}
+			}
+			c.Properties.Property = append(c.Properties.Property, junitProperty{Name: p.name, Value: v})
+		}
+	}
+	text := strings.Join(details(result), "\n")
+	switch {
+	case result.Err != nil:
+		c.Error = &junitFailure{Message: result.Err.Error(), Text: text}
+	case !result.Passed():
+		c.Failure = &junitFailure{Message: fmt.Sprintf("%d mismatches, %d violations", len(result.Mismatches),
+			len(result.Violations)), Text: text}
+	default:
+		c.SystemOut = text
+	}
+	return c
+}
+
+func attr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// Answers the duration in seconds, as JUnit XML represents it, e.g. `1.250`
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteJUnit(t *testing.T) {
+	r := newReport()
+	r.Results[1].Duration = 1250 * time.Millisecond
+	r.Results[1].Checked = []string{"genre", "title"}
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteJUnit(buf))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="idc-verify" tests="3" failures="1" errors="1" time="3.000">
+  <testsuite name="node--islandora_object" tests="2" failures="1" errors="1" timestamp="2021-06-01T12:00:00">
+    <testcase name="Moonrise" classname="node--islandora_object" time="1.250">
+      <properties>
+        <property name="checked" value="genre, title"></property>
+      </properties>
+      <failure message="1 mismatches, 1 violations">genre[1]: expected &#34;Photograph&#34;, got &#34;Photographs&#34;&#xA;publisher-country-requires-publisher: publisher is empty</failure>
+    </testcase>
+    <testcase name="Moonset" classname="node--islandora_object" time="0.000">
+      <error message="no resource matched">no resource matched</error>
+    </testcase>
+  </testsuite>
+  <testsuite name="taxonomy_term--subject" tests="1" failures="0" errors="0" timestamp="2021-06-01T12:00:00">
+    <testcase name="Analog Photography" classname="taxonomy_term--subject" file="subject.json" time="0.000">
+      <properties>
+        <property name="unverified" value="translations"></property>
+      </properties>
+      <system-out>drift: featured_item: expected true, got 1</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+
+	// the document is well-formed, and a result without a key is named by its fixture
+	r = &Report{Results: []*verify.Result{{Fixture: "untitled.json", Type: "node", Bundle: "page"}}}
+	buf.Reset()
+	require.Nil(t, r.WriteJUnit(buf))
+	doc := struct {
+		Suites []struct {
+			Cases []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}{}
+	require.Nil(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "untitled.json", doc.Suites[0].Cases[0].Name)
+}
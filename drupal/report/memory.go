@@ -0,0 +1,78 @@
+package report
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+)
+
+// The interval between the logs of a MemoryMonitor if its Interval is not positive
+const DefaultMemoryInterval = 30 * time.Second
+
+// Logs the memory statistics of the process periodically, so that the growth of a long run is visible in its log
+// before the run is killed, and lets the run shed memory once its heap exceeds a soft limit:
+//
+//	m := &report.MemoryMonitor{SoftLimit: 2 << 30, OnLimit: func(*runtime.MemStats) { resolver.Reset() }}
+//	defer m.Start()()
+type MemoryMonitor struct {
+	// The interval between logs; DefaultMemoryInterval if not positive
+	Interval time.Duration
+	// The heap size, in bytes, beyond which OnLimit is invoked and memory is returned to the operating system; no
+	// limit if zero
+	SoftLimit uint64
+	// Invoked with the statistics of each interval whose heap exceeds SoftLimit, e.g. to empty caches
+	OnLimit func(stats *runtime.MemStats)
+	// Logs the statistics; logging.Infof if nil
+	Logf func(format string, args ...interface{})
+}
+
+// Logs the memory statistics every Interval until the answered function is invoked, which returns once logging has
+// stopped
+func (m *MemoryMonitor) Start() (stop func()) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultMemoryInterval
+	}
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				m.Check()
+			}
+		}
+	}()
+	once := sync.Once{}
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+}
+
+// Logs the current memory statistics, and invokes OnLimit if the heap exceeds SoftLimit
+func (m *MemoryMonitor) Check() {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+	logf := m.Logf
+	if logf == nil {
+		logf = logging.Infof
+	}
+	logf("Memory: heap %d MiB, system %d MiB, %d GCs, %d goroutines", stats.HeapAlloc>>20, stats.Sys>>20,
+		stats.NumGC, runtime.NumGoroutine())
+	if m.SoftLimit == 0 || stats.HeapAlloc <= m.SoftLimit {
+		return
+	}
+	logf("Memory: heap exceeds the soft limit of %d MiB", m.SoftLimit>>20)
+	if m.OnLimit != nil {
+		m.OnLimit(stats)
+	}
+	debug.FreeOSMemory()
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MemoryMonitor(t *testing.T) {
+	var logs []string
+	limited := 0
+	m := &MemoryMonitor{SoftLimit: 1, OnLimit: func(*runtime.MemStats) { limited++ },
+		Logf: func(format string, args ...interface{}) { logs = append(logs, fmt.Sprintf(format, args...)) }}
+	m.Check()
+	assert.Equal(t, 1, limited)
+	assert.Equal(t, 2, len(logs))
+	assert.Contains(t, logs[0], "Memory: heap ")
+
+	logs = nil
+	m = &MemoryMonitor{Interval: time.Millisecond, Logf: func(format string, args ...interface{}) { logs = append(logs, format) }}
+	stop := m.Start()
+	time.Sleep(20 * time.Millisecond)
+	stop()
+	stop()
+	assert.NotEmpty(t, logs)
+}
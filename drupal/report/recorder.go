@@ -0,0 +1,93 @@
+package report
+
+import (
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// Records the results of verifications made by a test suite, e.g. by verify.RunAsSubtests, so that the suite writes
+// machine-readable reports of its own, independent of the output of `go test`:
+//
+//	var recorder = report.NewRecorder()
+//
+//	func TestFixtures(t *testing.T) {
+//		verify.RunAsSubtests(t, []string{"testdata/expected"}, verify.SubtestOptions{Engine: engine, OnResult: recorder.Record})
+//	}
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		if err := recorder.WriteFiles("verify.json", "verify.xml"); err != nil {
+//			log.Printf("%s", err)
+//		}
+//		os.Exit(code)
+//	}
+type Recorder struct {
+	started time.Time
+	mu      sync.Mutex
+	results []*verify.Result
+}
+
+// Creates a Recorder of a run starting now
+func NewRecorder() *Recorder {
+	return &Recorder{started: time.Now()}
+}
+
+// Records the result.  Safe for concurrent use, e.g. by parallel subtests.
+func (r *Recorder) Record(result *verify.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+}
+
+// Answers a report of the results recorded, finished now.  Results are ordered by fixture, then by the entity type,
+// bundle, and key of the fixture, so that the reports of successive runs may be compared however parallel tests
+// interleave.
+func (r *Recorder) Report() *Report {
+	r.mu.Lock()
+	results := append([]*verify.Result(nil), r.results...)
+	r.mu.Unlock()
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch {
+		case a.Fixture != b.Fixture:
+			return a.Fixture < b.Fixture
+		case a.Type != b.Type:
+			return a.Type < b.Type
+		case a.Bundle != b.Bundle:
+			return a.Bundle < b.Bundle
+		}
+		return a.Key < b.Key
+	})
+	return New(r.started, results...)
+}
+
+// Writes a report of the results recorded (see Report) as JSON (see WriteJson) to the jsonFile, and as JUnit XML (see
+// WriteJUnit) to the junitFile.  Either file name may be empty, in which case that report is not written.
+func (r *Recorder) WriteFiles(jsonFile, junitFile string) error {
+	report := r.Report()
+	if err := writeFile(jsonFile, report.WriteJson); err != nil {
+		return err
+	}
+	return writeFile(junitFile, report.WriteJUnit)
+}
+
+// Creates the file and writes it, unless the name is empty
+func writeFile(name string, write func(w io.Writer) error) error {
+	if name == "" {
+		return nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
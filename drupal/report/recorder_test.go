@@ -0,0 +1,54 @@
+package report
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Recorder(t *testing.T) {
+	r := NewRecorder()
+	wg := &sync.WaitGroup{}
+	for _, result := range []*verify.Result{
+		{Fixture: "b.json", Type: "node", Bundle: "islandora_object", Key: "Moonset", Checked: []string{"title"}},
+		{Fixture: "a.json", Type: "taxonomy_term", Bundle: "subject", Key: "Analog Photography", Err: errors.New("no resource matched")},
+		{Type: "node", Bundle: "islandora_object", Key: "Moonrise"},
+	} {
+		wg.Add(1)
+		go func(result *verify.Result) {
+			defer wg.Done()
+			r.Record(result)
+		}(result)
+	}
+	wg.Wait()
+
+	report := r.Report()
+	require.Equal(t, 3, len(report.Results))
+	assert.Equal(t, "Moonrise", report.Results[0].Key)
+	assert.Equal(t, "a.json", report.Results[1].Fixture)
+	assert.Equal(t, "b.json", report.Results[2].Fixture)
+	assert.Equal(t, Summary{Total: 3, Passed: 2, Errored: 1}, report.Summary())
+
+	dir := t.TempDir()
+	jsonFile, junitFile := filepath.Join(dir, "verify.json"), filepath.Join(dir, "verify.xml")
+	require.Nil(t, r.WriteFiles(jsonFile, junitFile))
+	doc, err := ioutil.ReadFile(jsonFile)
+	require.Nil(t, err)
+	assert.Nil(t, Validate(doc))
+	assert.Contains(t, string(doc), `"checked": [
+        "title"
+      ]`)
+	xml, err := ioutil.ReadFile(junitFile)
+	require.Nil(t, err)
+	assert.Contains(t, string(xml), `<error message="no resource matched">`)
+
+	require.Nil(t, r.WriteFiles("", filepath.Join(dir, "only.xml")))
+	assert.FileExists(t, filepath.Join(dir, "only.xml"))
+	assert.NotNil(t, r.WriteFiles(filepath.Join(dir, "missing", "verify.json"), ""))
+}
@@ -0,0 +1,334 @@
+// Collects the outcomes of verifying fixtures against a Drupal site, and writes them as text for people or as JSON
+// for dashboards and scripts.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/dblog"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// The outcomes of a verification run
+type Report struct {
+	// The id of the verification run, as sent in the jsonapi.RunHeader of its requests; empty if unknown
+	RunId    string
+	Started  time.Time
+	Finished time.Time
+	// The results held in memory; see Add
+	Results []*verify.Result
+	// The maximum number of results held in memory by Add; unbounded if not positive
+	MaxResults int
+	// The directory of the file results are spilled to by Add; the default directory for temporary files if empty
+	SpillDir string
+	// The environment the run was made in; captured by New (see CaptureEnvironment)
+	Environment *Environment
+	// The entries of Drupal's database log captured during the run, e.g. the offending entries answered by
+	// dblog.Window.AssertNoneAtLeast; see AddLog
+	Log []dblog.Entry
+
+	spill   *os.File
+	spilled Summary
+	// The counts of the spilled results of each entity type and bundle, keyed by e.g. `node--islandora_object`
+	spilledBundles map[string]BundleSummary
+}
+
+// Counts of the results of a Report
+type Summary struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+	// Results whose live entity differed from the fixture, or whose fixture violated a rule
+	Failed int `json:"failed"`
+	// Results whose fixture could not be read, or whose live entity could not be retrieved
+	Errored int `json:"errored"`
+}
+
+// Counts of the results of the fixtures of an entity type and bundle
+type BundleSummary struct {
+	Type   string `json:"type"`
+	Bundle string `json:"bundle"`
+	Summary
+}
+
+// Creates a Report of the results of the current run (see jsonapi.RunId), finished now, in the environment captured by
+// CaptureEnvironment
+func New(started time.Time, results ...*verify.Result) *Report {
+	return &Report{RunId: jsonapi.RunId(), Started: started, Finished: time.Now(), Results: results,
+		Environment: CaptureEnvironment()}
+}
+
+// Answers the counts of passed, failed, and errored results
+func (r *Report) Summary() Summary {
+	s := r.spilled
+	for _, result := range r.Results {
+		s.count(result)
+	}
+	return s
+}
+
+// Answers the counts of passed, failed, and errored results of each entity type and bundle, ordered by type and
+// bundle
+func (r *Report) Bundles() []BundleSummary {
+	bundles := map[string]BundleSummary{}
+	for k, s := range r.spilledBundles {
+		bundles[k] = s
+	}
+	for _, result := range r.Results {
+		countBundle(bundles, result)
+	}
+	summaries := make([]BundleSummary, 0, len(bundles))
+	for _, s := range bundles {
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Type != summaries[j].Type {
+			return summaries[i].Type < summaries[j].Type
+		}
+		return summaries[i].Bundle < summaries[j].Bundle
+	})
+	return summaries
+}
+
+// Answers the entity type and bundle as e.g. `node--islandora_object`, or `unknown` for the results of fixtures that
+// could not be read
+func typeName(entityType, bundle string) string {
+	if entityType == "" && bundle == "" {
+		return "unknown"
+	}
+	return entityType + "--" + bundle
+}
+
+// Counts the result in the summary of its entity type and bundle
+func countBundle(bundles map[string]BundleSummary, result *verify.Result) {
+	k := result.Type + "--" + result.Bundle
+	s := bundles[k]
+	s.Type, s.Bundle = result.Type, result.Bundle
+	s.count(result)
+	bundles[k] = s
+}
+
+// Attaches entries of Drupal's database log to the report, e.g. the warnings logged during the run
+func (r *Report) AddLog(entries ...dblog.Entry) {
+	r.Log = append(r.Log, entries...)
+}
+
+// Answers true if every result passed
+func (r *Report) Passed() bool {
+	s := r.Summary()
+	return s.Passed == s.Total
+}
+
+// Writes a line per result followed by the details of each failure, the entries of the database log, the summary of
+// each entity type and bundle, and a summary of the report
+func (r *Report) WriteText(w io.Writer) error {
+	ew := &errWriter{w: w}
+	err := r.each(func(_ int, result *verify.Result) error {
+		status := "PASS"
+		if result.Err != nil {
+			status = "ERROR"
+		} else if !result.Passed() {
+			status = "FAIL"
+		}
+		ew.printf("%-5s %s--%s %q", status, result.Type, result.Bundle, result.Key)
+		if result.Pid != "" && result.Pid != result.Key {
+			ew.printf(" [%s]", result.Pid)
+		}
+		if result.Fixture != "" {
+			ew.printf(" (%s)", result.Fixture)
+		}
+		ew.printf("\n")
+		for _, line := range details(result) {
+			ew.printf("      %s\n", line)
+		}
+		return ew.err
+	})
+	if err != nil {
+		return err
+	}
+	if len(r.Log) > 0 {
+		ew.printf("DBLOG %d entries logged by Drupal\n", len(r.Log))
+		for _, e := range r.Log {
+			ew.printf("      %s\n", e)
+		}
+	}
+	r.writeSummary(ew)
+	return ew.err
+}
+
+// Answers the lines detailing the outcome of the result: its error, mismatches, violations, and drift
+func details(result *verify.Result) []string {
+	var lines []string
+	if result.Err != nil {
+		lines = append(lines, result.Err.Error())
+	}
+	for _, m := range result.Mismatches {
+		lines = append(lines, m.String())
+	}
+	for _, v := range result.Violations {
+		lines = append(lines, v.String())
+	}
+	for _, d := range result.Drift {
+		lines = append(lines, "drift: "+d.String())
+	}
+	return lines
+}
+
+// Writes the summary lines of each entity type and bundle, if there is more than one, and of the report
+func (r *Report) writeSummary(ew *errWriter) {
+	if bundles := r.Bundles(); len(bundles) > 1 {
+		for _, b := range bundles {
+			ew.printf("%s: %d passed, %d failed, %d errored of %d\n", typeName(b.Type, b.Bundle), b.Passed, b.Failed, b.Errored,
+				b.Total)
+		}
+	}
+	s := r.Summary()
+	ew.printf("%d passed, %d failed, %d errored of %d in %s", s.Passed, s.Failed, s.Errored, s.Total,
+		r.Finished.Sub(r.Started).Round(time.Millisecond))
+	if r.RunId != "" {
+		ew.printf(" (run %s)", r.RunId)
+	}
+	ew.printf("\n")
+	if r.Environment != nil {
+		ew.printf("%s\n", r.Environment)
+	}
+}
+
+// The JSON representation of a Report
+type jsonReport struct {
+	SchemaVersion string          `json:"schema_version"`
+	RunId         string          `json:"run_id,omitempty"`
+	Started       time.Time       `json:"started"`
+	Finished      time.Time       `json:"finished"`
+	DurationMs    int64           `json:"duration_ms"`
+	Environment   *Environment    `json:"environment,omitempty"`
+	Summary       Summary         `json:"summary"`
+	Bundles       []BundleSummary `json:"bundles"`
+	Results       []jsonResult    `json:"results"`
+	Groups        []jsonGroup     `json:"groups"`
+	Log           []jsonLogEntry  `json:"log,omitempty"`
+}
+
+type jsonLogEntry struct {
+	Wid       int64  `json:"wid"`
+	Type      string `json:"type"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Location  string `json:"location,omitempty"`
+}
+
+type jsonGroup struct {
+	Signature string `json:"signature"`
+	// The indexes of the results sharing the signature
+	Results []int `json:"results"`
+}
+
+type jsonResult struct {
+	Fixture    string          `json:"fixture,omitempty"`
+	Type       string          `json:"type"`
+	Bundle     string          `json:"bundle"`
+	Key        string          `json:"key"`
+	Pid        string          `json:"pid,omitempty"`
+	Passed     bool            `json:"passed"`
+	Error      string          `json:"error,omitempty"`
+	Mismatches []jsonMismatch  `json:"mismatches"`
+	Violations []jsonViolation `json:"violations"`
+	Drift      []jsonMismatch  `json:"drift"`
+	Unverified []string        `json:"unverified"`
+	Checked    []string        `json:"checked"`
+	VerifyOnly []string        `json:"verify_only,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+type jsonMismatch struct {
+	Path       string      `json:"path"`
+	Expected   interface{} `json:"expected"`
+	Actual     interface{} `json:"actual"`
+	ActualPath string      `json:"actual_path,omitempty"`
+}
+
+type jsonViolation struct {
+	Rule  string `json:"rule"`
+	Error string `json:"error"`
+}
+
+// Writes the report as an indented JSON document conforming to Schema
+func (r *Report) WriteJson(w io.Writer) error {
+	doc := jsonReport{SchemaVersion: SchemaVersion, RunId: r.RunId, Started: r.Started, Finished: r.Finished,
+		DurationMs: r.Finished.Sub(r.Started).Milliseconds(), Environment: r.Environment, Summary: r.Summary(), Bundles: r.Bundles(), Results: []jsonResult{}}
+	err := r.each(func(_ int, result *verify.Result) error {
+		jr := jsonResult{
+			Fixture:    result.Fixture,
+			Type:       result.Type,
+			Bundle:     result.Bundle,
+			Key:        result.Key,
+			Pid:        result.Pid,
+			Passed:     result.Passed(),
+			Mismatches: []jsonMismatch{},
+			Violations: []jsonViolation{},
+			Drift:      []jsonMismatch{},
+			Unverified: append([]string{}, result.Unverified...),
+			Checked:    append([]string{}, result.Checked...),
+			VerifyOnly: result.VerifyOnly,
+			DurationMs: result.Duration.Milliseconds(),
+		}
+		if result.Err != nil {
+			jr.Error = result.Err.Error()
+		}
+		for _, m := range result.Mismatches {
+			jr.Mismatches = append(jr.Mismatches, jsonMismatch{Path: m.Path, Expected: m.Expected, Actual: m.Actual, ActualPath: m.ActualPath})
+		}
+		for _, d := range result.Drift {
+			jr.Drift = append(jr.Drift, jsonMismatch{Path: d.Path, Expected: d.Expected, Actual: d.Actual})
+		}
+		for _, v := range result.Violations {
+			jr.Violations = append(jr.Violations, jsonViolation{Rule: v.Rule, Error: v.Err.Error()})
+		}
+		doc.Results = append(doc.Results, jr)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	groups, index, err := r.groups()
+	if err != nil {
+		return err
+	}
+	doc.Groups = []jsonGroup{}
+	for _, g := range groups {
+		jg := jsonGroup{Signature: g.Signature, Results: []int{}}
+		for _, result := range g.Results {
+			jg.Results = append(jg.Results, index[result])
+		}
+		doc.Groups = append(doc.Groups, jg)
+	}
+	for _, e := range r.Log {
+		je := jsonLogEntry{Wid: e.Wid, Type: e.Type, Severity: e.Severity.String(), Message: e.Message, Location: e.Location}
+		if !e.Timestamp.IsZero() {
+			je.Timestamp = e.Timestamp.Format(time.RFC3339)
+		}
+		doc.Log = append(doc.Log, je)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// Retains the first error encountered while writing, so that a report may be written without checking each write
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/dblog"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReport() *Report {
+	started := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	return &Report{Started: started, Finished: started.Add(3 * time.Second), Results: []*verify.Result{
+		{Fixture: "subject.json", Type: "taxonomy_term", Bundle: "subject", Key: "Analog Photography", Unverified: []string{"translations"},
+			Drift: []verify.Mismatch{{Path: "featured_item", Expected: true, Actual: 1.0}}},
+		{Type: "node", Bundle: "islandora_object", Key: "Moonrise",
+			Mismatches: []verify.Mismatch{{Path: "genre[1]", Expected: "Photograph", Actual: "Photographs"}},
+			Violations: []verify.Violation{{Rule: "publisher-country-requires-publisher", Err: errors.New("publisher is empty")}}},
+		{Type: "node", Bundle: "islandora_object", Key: "Moonset", Err: errors.New("no resource matched")},
+	}}
+}
+
+func Test_Summary(t *testing.T) {
+	r := newReport()
+	assert.Equal(t, Summary{Total: 3, Passed: 1, Failed: 1, Errored: 1}, r.Summary())
+	assert.False(t, r.Passed())
+	assert.True(t, New(time.Now(), r.Results[0]).Passed())
+	assert.True(t, New(time.Now()).Passed())
+}
+
+func Test_WriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.Nil(t, newReport().WriteText(buf))
+	assert.Equal(t, `PASS  taxonomy_term--subject "Analog Photography" (subject.json)
+      drift: featured_item: expected true, got 1
+FAIL  node--islandora_object "Moonrise"
+      genre[1]: expected "Photograph", got "Photographs"
+      publisher-country-requires-publisher: publisher is empty
+ERROR node--islandora_object "Moonset"
+      no resource matched
+node--islandora_object: 0 passed, 1 failed, 1 errored of 2
+taxonomy_term--subject: 1 passed, 0 failed, 0 errored of 1
+1 passed, 1 failed, 1 errored of 3 in 3s
+`, buf.String())
+}
+
+func Test_Bundles(t *testing.T) {
+	r := newReport()
+	expected := []BundleSummary{
+		{Type: "node", Bundle: "islandora_object", Summary: Summary{Total: 2, Failed: 1, Errored: 1}},
+		{Type: "taxonomy_term", Bundle: "subject", Summary: Summary{Total: 1, Passed: 1}},
+	}
+	assert.Equal(t, expected, r.Bundles())
+
+	// spilled results are counted
+	spilled := &Report{MaxResults: 1, SpillDir: t.TempDir()}
+	defer spilled.Close()
+	require.Nil(t, spilled.Add(r.Results...))
+	assert.Equal(t, 2, spilled.Spilled())
+	assert.Equal(t, expected, spilled.Bundles())
+}
+
+func Test_WriteJson(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.Nil(t, newReport().WriteJson(buf))
+
+	doc := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2021-06-01T12:00:00Z", doc["started"])
+	assert.Equal(t, map[string]interface{}{"total": 3.0, "passed": 1.0, "failed": 1.0, "errored": 1.0}, doc["summary"])
+
+	results := doc["results"].([]interface{})
+	require.Equal(t, 3, len(results))
+	assert.Equal(t, true, results[0].(map[string]interface{})["passed"])
+	assert.Equal(t, []interface{}{"translations"}, results[0].(map[string]interface{})["unverified"])
+	assert.Equal(t, 1, len(results[0].(map[string]interface{})["drift"].([]interface{})))
+	assert.Equal(t, []interface{}{map[string]interface{}{"path": "genre[1]", "expected": "Photograph", "actual": "Photographs"}},
+		results[1].(map[string]interface{})["mismatches"])
+	assert.Equal(t, "no resource matched", results[2].(map[string]interface{})["error"])
+}
+
+func Test_RunId(t *testing.T) {
+	assert.Equal(t, jsonapi.RunId(), New(time.Now()).RunId)
+
+	r := newReport()
+	r.RunId = "3f2b1c4d-0000-4000-8000-000000000001"
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteText(buf))
+	assert.Contains(t, buf.String(), "of 3 in 3s (run 3f2b1c4d-0000-4000-8000-000000000001)\n")
+
+	buf.Reset()
+	require.Nil(t, r.WriteJson(buf))
+	assert.Contains(t, buf.String(), `"run_id": "3f2b1c4d-0000-4000-8000-000000000001"`)
+	assert.Nil(t, Validate(buf.Bytes()))
+}
+
+func Test_Pid(t *testing.T) {
+	r := newReport()
+	r.Results[1].Pid = "islandora:1234"
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteText(buf))
+	assert.Contains(t, buf.String(), `"Moonrise" [islandora:1234]`)
+
+	buf.Reset()
+	require.Nil(t, r.WriteJson(buf))
+	assert.Contains(t, buf.String(), `"pid": "islandora:1234"`)
+	assert.Equal(t, 1, strings.Count(buf.String(), `"pid"`))
+	assert.Nil(t, Validate(buf.Bytes()))
+}
+
+func Test_ActualPath(t *testing.T) {
+	r := newReport()
+	r.Results[1].Mismatches[0].ActualPath = "genre[3]"
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteText(buf))
+	assert.Contains(t, buf.String(), `genre[1]: expected "Photograph", got "Photographs" at genre[3]`)
+
+	buf.Reset()
+	require.Nil(t, r.WriteJson(buf))
+	assert.Contains(t, buf.String(), `"actual_path": "genre[3]"`)
+	assert.Equal(t, 1, strings.Count(buf.String(), `"actual_path"`))
+	assert.Nil(t, Validate(buf.Bytes()))
+}
+
+func Test_Log(t *testing.T) {
+	r := newReport()
+	r.AddLog(dblog.Entry{Wid: 1021, Type: "php", Severity: dblog.Warning, Message: "Warning: Undefined array key \"alt\"",
+		Timestamp: time.Date(2021, 6, 1, 12, 0, 1, 0, time.UTC), Location: "https://islandora-idc.traefik.me/batch"})
+	r.AddLog(dblog.Entry{Wid: 1020, Type: "migrate", Severity: dblog.Error, Message: "Missing file",
+		Timestamp: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)})
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteText(buf))
+	assert.Contains(t, buf.String(), `no resource matched
+DBLOG 2 entries logged by Drupal
+      [warning] 2021-06-01T12:00:01Z php: Warning: Undefined array key "alt"
+      [error] 2021-06-01T12:00:00Z migrate: Missing file
+node--islandora_object:`)
+
+	buf.Reset()
+	require.Nil(t, r.WriteJson(buf))
+	require.Nil(t, Validate(buf.Bytes()))
+	doc := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"wid": 1021.0, "type": "php", "severity": "warning", "message": "Warning: Undefined array key \"alt\"",
+			"timestamp": "2021-06-01T12:00:01Z", "location": "https://islandora-idc.traefik.me/batch"},
+		map[string]interface{}{"wid": 1020.0, "type": "migrate", "severity": "error", "message": "Missing file",
+			"timestamp": "2021-06-01T12:00:00Z"},
+	}, doc["log"])
+
+	// reports without log entries are unchanged
+	buf.Reset()
+	require.Nil(t, newReport().WriteJson(buf))
+	assert.NotContains(t, buf.String(), `"log"`)
+}
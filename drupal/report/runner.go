@@ -0,0 +1,88 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// The number of fixtures a Runner verifies at once, unless configured otherwise
+const DefaultConcurrency = 4
+
+// Verifies fixtures concurrently, collecting their results in a Report, so that a directory of fixtures is verified
+// without a test function per fixture:
+//
+//	r := report.New(time.Now())
+//	err := report.NewRunner(engine).Run(r, "testdata/expected")
+//	r.WriteText(os.Stdout)
+type Runner struct {
+	// Verifies each fixture
+	Engine *verify.Engine
+	// The number of fixtures verified at once; DefaultConcurrency if not positive
+	Concurrency int
+}
+
+// Creates a Runner of the engine verifying DefaultConcurrency fixtures at once
+func NewRunner(engine *verify.Engine) *Runner {
+	return &Runner{Engine: engine, Concurrency: DefaultConcurrency}
+}
+
+// Verifies each fixture, which is a `.json` file or a directory whose fixtures (and those of its subdirectories) are
+// verified (see verify.FixturePaths), and adds the results to the report.  The live entities of the fixtures are
+// retrieved concurrently, but results are added in the order of the fixtures, so that the reports of successive runs
+// may be compared.  A fixture that cannot be read is recorded as an errored result.  The report is Finished when the
+// last result is added.  An error is answered if a directory cannot be listed or a result cannot be added, in which
+// case the remaining fixtures are not verified.
+func (r *Runner) Run(report *Report, fixtures ...string) error {
+	var paths []string
+	for _, f := range fixtures {
+		if info, err := os.Stat(f); err == nil && info.IsDir() {
+			dirPaths, err := verify.FixturePaths(f)
+			if err != nil {
+				return fmt.Errorf("report: unable to list the fixtures of %s: %w", f, err)
+			}
+			paths = append(paths, dirPaths...)
+		} else {
+			paths = append(paths, f)
+		}
+	}
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	// each result is sent on the channel of its fixture, which is buffered so that workers never wait on the report
+	results := make([]chan *verify.Result, len(paths))
+	for i := range results {
+		results[i] = make(chan *verify.Result, 1)
+	}
+	next, stop := make(chan int), make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(next)
+		for i := range paths {
+			select {
+			case next <- i:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			for i := range next {
+				results[i] <- r.Engine.VerifyFile(paths[i])
+			}
+		}()
+	}
+
+	for i, result := range results {
+		if err := report.Add(<-result); err != nil {
+			return fmt.Errorf("report: unable to record the result of %s: %w", paths[i], err)
+		}
+	}
+	report.Finished = time.Now()
+	return nil
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Runner(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	dir := t.TempDir()
+	for i, name := range []string{"Maps", "Photographs", "Posters", "Prints", "Slides", "Zines"} {
+		m.Add(jsonapitest.Resource{"type": "taxonomy_term--genre", "id": name, "attributes": map[string]interface{}{"name": name}})
+		fixture := `{"type": "taxonomy_term", "bundle": "genre", "name": "` + name + `"}`
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, string(rune('a'+i))+".json"), []byte(fixture), 0644))
+	}
+	missing := filepath.Join(dir, "missing.json")
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "nested"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "nested", "subject.json"),
+		[]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography"}`), 0644))
+
+	r := New(time.Now())
+	defer r.Close()
+	r.MaxResults = 2
+	r.SpillDir = t.TempDir()
+	runner := NewRunner(verify.NewEngine(m.URL, "", ""))
+	runner.Concurrency = 3
+	require.Nil(t, runner.Run(r, dir, missing))
+
+	// results are in the order of the fixtures, however the verifications complete
+	var fixtures []string
+	require.Nil(t, r.each(func(_ int, result *verify.Result) error {
+		fixtures = append(fixtures, filepath.Base(result.Fixture))
+		return nil
+	}))
+	assert.Equal(t, []string{"a.json", "b.json", "c.json", "d.json", "e.json", "f.json", "subject.json", "missing.json"}, fixtures)
+	assert.Equal(t, []BundleSummary{
+		{Type: "", Bundle: "", Summary: Summary{Total: 1, Errored: 1}},
+		{Type: "taxonomy_term", Bundle: "genre", Summary: Summary{Total: 6, Passed: 6}},
+		{Type: "taxonomy_term", Bundle: "subject", Summary: Summary{Total: 1, Errored: 1}},
+	}, r.Bundles())
+	assert.False(t, r.Finished.Before(r.Started))
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, r.WriteJUnit(buf))
+	assert.Contains(t, buf.String(), `<testsuite name="unknown" tests="1" failures="0" errors="1"`)
+}
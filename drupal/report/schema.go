@@ -0,0 +1,140 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// The version of Schema that reports written by WriteJson conform to.  Minor versions only add optional properties;
+// properties are removed, retyped, or made required only by a new major version.
+const SchemaVersion = "1.7"
+
+// The JSON schema of reports written by WriteJson
+//
+//go:embed schema.json
+var Schema []byte
+
+// Validates the JSON report against Schema, answering an error describing each violation.  Only the keywords used by
+// Schema are supported: type, required, properties, items, minimum, and local $ref.
+func Validate(doc []byte) error {
+	var schema, v interface{}
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		return fmt.Errorf("report: invalid schema: %w", err)
+	}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return fmt.Errorf("report: %w", err)
+	}
+	s := &validator{root: schema.(map[string]interface{})}
+	s.validate("$", s.root, v)
+	if len(s.errors) > 0 {
+		return fmt.Errorf("report: the report does not conform to schema %s:\n%s", SchemaVersion, strings.Join(s.errors, "\n"))
+	}
+	return nil
+}
+
+// Accumulates the violations of a schema
+type validator struct {
+	root   map[string]interface{}
+	errors []string
+}
+
+func (s *validator) validate(path string, schema map[string]interface{}, v interface{}) {
+	if ref, ok := schema["$ref"].(string); ok {
+		s.validate(path, s.resolve(ref), v)
+		return
+	}
+	if t, ok := schema["type"].(string); ok && !hasType(v, t) {
+		s.errors = append(s.errors, fmt.Sprintf("%s: expected %s, got %s", path, t, describe(v)))
+		return
+	}
+	if min, ok := schema["minimum"].(float64); ok {
+		if n, ok := v.(float64); ok && n < min {
+			s.errors = append(s.errors, fmt.Sprintf("%s: %v is less than %v", path, n, min))
+		}
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		required, _ := schema["required"].([]interface{})
+		for _, r := range required {
+			if _, ok := v[r.(string)]; !ok {
+				s.errors = append(s.errors, fmt.Sprintf("%s: %s is required", path, r))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if p, ok := properties[k].(map[string]interface{}); ok {
+				s.validate(path+"."+k, p, v[k])
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				s.validate(fmt.Sprintf("%s[%d]", path, i), items, item)
+			}
+		}
+	}
+}
+
+// Answers the schema referenced by a local $ref, e.g. `#/$defs/mismatch`
+func (s *validator) resolve(ref string) map[string]interface{} {
+	var schema interface{} = s.root
+	for _, seg := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, _ := schema.(map[string]interface{})
+		schema = m[seg]
+	}
+	if m, ok := schema.(map[string]interface{}); ok {
+		return m
+	}
+	panic(fmt.Sprintf("report: unresolvable $ref '%s' in schema", ref))
+}
+
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+func describe(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	return "number"
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Validate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.Nil(t, newReport().WriteJson(buf))
+	assert.Nil(t, Validate(buf.Bytes()))
+	assert.Contains(t, buf.String(), `"schema_version": "`+SchemaVersion+`"`)
+
+	err := Validate([]byte(`{"schema_version": "1.0", "started": "", "finished": "", "summary": {"total": -1, "passed": 0.5, "failed": 0},
+		"results": [{"type": "node", "bundle": "islandora_object", "key": "Moonrise", "passed": "yes", "mismatches": [{"path": "genre"}],
+		"violations": [], "drift": [], "unverified": [], "duration_ms": 1}]}`))
+	require.NotNil(t, err)
+	for _, violation := range []string{
+		"$.summary: errored is required",
+		"$.summary.passed: expected integer, got number",
+		"$.summary.total: -1 is less than 0",
+		"$.results[0].passed: expected boolean, got string",
+		"$.results[0].mismatches[0]: expected is required",
+	} {
+		assert.Contains(t, err.Error(), violation)
+	}
+	assert.NotNil(t, Validate([]byte(`[]`)))
+}
+
+// The schema published for each version is frozen in testdata, so a change to the schema requires a new version
+func Test_SchemaVersion(t *testing.T) {
+	published, err := ioutil.ReadFile(filepath.Join("testdata", "schema-"+SchemaVersion+".json"))
+	require.Nil(t, err, "the schema of version %s must be published to testdata", SchemaVersion)
+	assert.JSONEq(t, string(published), string(Schema), "the schema changed without a new SchemaVersion")
+}
+
+// Each published schema of the current major version must be evolved into the current schema compatibly: no property
+// may be removed or retyped, and no property may become required
+func Test_SchemaCompatible(t *testing.T) {
+	major := strings.SplitN(SchemaVersion, ".", 2)[0]
+	paths, err := filepath.Glob(filepath.Join("testdata", "schema-"+major+".*.json"))
+	require.Nil(t, err)
+	require.NotEmpty(t, paths)
+
+	var current map[string]interface{}
+	require.Nil(t, json.Unmarshal(Schema, &current))
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		require.Nil(t, err)
+		var published map[string]interface{}
+		require.Nil(t, json.Unmarshal(b, &published))
+		assertCompatible(t, path, "$", published, current, published, current)
+	}
+}
+
+func assertCompatible(t *testing.T, version, path string, published, current, publishedRoot, currentRoot map[string]interface{}) {
+	published, current = deref(publishedRoot, published), deref(currentRoot, current)
+	assert.Equal(t, published["type"], current["type"], "%s: %s is retyped", version, path)
+
+	required := map[string]bool{}
+	for _, r := range asSlice(published["required"]) {
+		required[r.(string)] = true
+	}
+	for _, r := range asSlice(current["required"]) {
+		assert.True(t, required[r.(string)], "%s: %s.%s became required", version, path, r)
+	}
+
+	publishedProperties, _ := published["properties"].(map[string]interface{})
+	currentProperties, _ := current["properties"].(map[string]interface{})
+	for name, p := range publishedProperties {
+		c, ok := currentProperties[name].(map[string]interface{})
+		if assert.True(t, ok, "%s: %s.%s is removed", version, path, name) {
+			assertCompatible(t, version, path+"."+name, p.(map[string]interface{}), c, publishedRoot, currentRoot)
+		}
+	}
+	if items, ok := published["items"].(map[string]interface{}); ok {
+		c, ok := current["items"].(map[string]interface{})
+		if assert.True(t, ok, "%s: the items of %s are removed", version, path) {
+			assertCompatible(t, version, path+"[]", items, c, publishedRoot, currentRoot)
+		}
+	}
+}
+
+func deref(root, schema map[string]interface{}) map[string]interface{} {
+	if ref, ok := schema["$ref"].(string); ok {
+		return (&validator{root: root}).resolve(ref)
+	}
+	return schema
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
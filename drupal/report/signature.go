@@ -0,0 +1,139 @@
+package report
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// Matches the indexes of a mismatch path, e.g. `[1]` of `genre[1]`
+var pathIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Matches the quoted values of an error message, e.g. the title of `no resource matched 'Moonrise'`
+var quoted = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+
+// The results sharing a failure signature
+type Group struct {
+	// Identifies the failure, e.g. `rights missing`, `genre differs`, or `rule publisher-country-requires-publisher`
+	Signature string
+	// The failed or errored results sharing the signature, in the order of the report
+	Results []*verify.Result
+}
+
+// Answers the distinct failure signatures of the result, sorted; none if the result passed.  A mismatch is signed by
+// its fixture key (disregarding list indexes and map keys) and whether the live value is missing, unexpected, or
+// differs; a violation by its rule; and an error by its message with quoted values elided.
+func Signatures(result *verify.Result) []string {
+	seen := map[string]bool{}
+	if result.Err != nil {
+		seen["error: "+quoted.ReplaceAllString(result.Err.Error(), "'…'")] = true
+	}
+	for _, m := range result.Mismatches {
+		key := pathIndex.ReplaceAllString(m.Path, "")
+		if i := strings.Index(key, "."); i >= 0 {
+			key = key[:i]
+		}
+		switch {
+		case isEmpty(m.Actual):
+			seen[key+" missing"] = true
+		case isEmpty(m.Expected):
+			seen[key+" unexpected"] = true
+		default:
+			seen[key+" differs"] = true
+		}
+	}
+	for _, v := range result.Violations {
+		seen["rule "+v.Rule] = true
+	}
+
+	signatures := make([]string, 0, len(seen))
+	for s := range seen {
+		signatures = append(signatures, s)
+	}
+	sort.Strings(signatures)
+	return signatures
+}
+
+// Answers the failed and errored results grouped by failure signature, largest group first, and then by signature.
+// A result with several signatures is a member of each of their groups.  Failed results spilled to disk (see Add) are
+// read back into memory; nil is answered if they cannot be read.
+func (r *Report) Groups() []Group {
+	groups, _, err := r.groups()
+	if err != nil {
+		return nil
+	}
+	return groups
+}
+
+// Answers the groups of the report, and the index of each grouped result within the report
+func (r *Report) groups() ([]Group, map[*verify.Result]int, error) {
+	bySignature := map[string]*Group{}
+	index := map[*verify.Result]int{}
+	err := r.each(func(i int, result *verify.Result) error {
+		for _, s := range Signatures(result) {
+			g, ok := bySignature[s]
+			if !ok {
+				g = &Group{Signature: s}
+				bySignature[s] = g
+			}
+			g.Results = append(g.Results, result)
+			index[result] = i
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups := make([]Group, 0, len(bySignature))
+	for _, g := range bySignature {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].Results) != len(groups[j].Results) {
+			return len(groups[i].Results) > len(groups[j].Results)
+		}
+		return groups[i].Signature < groups[j].Signature
+	})
+	return groups, index, nil
+}
+
+// Writes a line per failure signature with the number of results sharing it, followed by a line per result, and a
+// summary.  Unlike WriteText, the details of each failure are not written, so that hundreds of results failing alike
+// are summarized by a single group.
+func (r *Report) WriteGroups(w io.Writer) error {
+	ew := &errWriter{w: w}
+	groups, _, err := r.groups()
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		ew.printf("%s (%d)\n", g.Signature, len(g.Results))
+		for _, result := range g.Results {
+			ew.printf("      %s--%s %q", result.Type, result.Bundle, result.Key)
+			if result.Fixture != "" {
+				ew.printf(" (%s)", result.Fixture)
+			}
+			ew.printf("\n")
+		}
+	}
+	r.writeSummary(ew)
+	return ew.err
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
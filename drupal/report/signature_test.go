@@ -0,0 +1,93 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGroupedReport() *Report {
+	started := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := &Report{Started: started, Finished: started.Add(3 * time.Second)}
+	for _, title := range []string{"Moonrise", "Moonset", "Sunrise"} {
+		r.Results = append(r.Results, &verify.Result{Type: "node", Bundle: "islandora_object", Key: title,
+			Mismatches: []verify.Mismatch{{Path: "rights[0]", Expected: "In Copyright"}}})
+	}
+	r.Results = append(r.Results,
+		&verify.Result{Type: "node", Bundle: "islandora_object", Key: "Eclipse", Fixture: "eclipse.json",
+			Mismatches: []verify.Mismatch{
+				{Path: "genre[1]", Expected: "Maps", Actual: "Map"},
+				{Path: "genre[2].name", Expected: "Posters", Actual: "Poster"},
+				{Path: "rights", Expected: []interface{}{"In Copyright"}, Actual: []interface{}{}},
+			}},
+		&verify.Result{Type: "node", Bundle: "islandora_object", Key: "Comet", Mismatches: []verify.Mismatch{{Path: "extent", Actual: "2 boxes"}},
+			Violations: []verify.Violation{{Rule: "publisher-country-requires-publisher", Err: errors.New("publisher is empty")}}},
+		&verify.Result{Type: "node", Bundle: "islandora_object", Key: "Aurora", Err: errors.New("no resource matched 'Aurora'")},
+		&verify.Result{Type: "node", Bundle: "islandora_object", Key: "Nebula", Err: errors.New(`no resource matched "Nebula"`)},
+		&verify.Result{Type: "node", Bundle: "islandora_object", Key: "Meteor"},
+	)
+	return r
+}
+
+func Test_Signatures(t *testing.T) {
+	r := newGroupedReport()
+	assert.Equal(t, []string{"rights missing"}, Signatures(r.Results[0]))
+	assert.Equal(t, []string{"genre differs", "rights missing"}, Signatures(r.Results[3]))
+	assert.Equal(t, []string{"extent unexpected", "rule publisher-country-requires-publisher"}, Signatures(r.Results[4]))
+	assert.Equal(t, []string{"error: no resource matched '…'"}, Signatures(r.Results[5]))
+	assert.Empty(t, Signatures(r.Results[7]))
+}
+
+func Test_Groups(t *testing.T) {
+	groups := newGroupedReport().Groups()
+	var signatures []string
+	for _, g := range groups {
+		signatures = append(signatures, g.Signature)
+	}
+	assert.Equal(t, []string{"rights missing", "error: no resource matched '…'", "extent unexpected", "genre differs",
+		"rule publisher-country-requires-publisher"}, signatures)
+	require.Equal(t, 4, len(groups[0].Results))
+	assert.Equal(t, "Eclipse", groups[0].Results[3].Key)
+}
+
+func Test_WriteGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := newGroupedReport()
+	r.Results = r.Results[3:6]
+	require.Nil(t, r.WriteGroups(buf))
+	assert.Equal(t, `error: no resource matched '…' (1)
+      node--islandora_object "Aurora"
+extent unexpected (1)
+      node--islandora_object "Comet"
+genre differs (1)
+      node--islandora_object "Eclipse" (eclipse.json)
+rights missing (1)
+      node--islandora_object "Eclipse" (eclipse.json)
+rule publisher-country-requires-publisher (1)
+      node--islandora_object "Comet"
+0 passed, 2 failed, 1 errored of 3 in 3s
+`, buf.String())
+}
+
+func Test_WriteJsonGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.Nil(t, newGroupedReport().WriteJson(buf))
+	require.Nil(t, Validate(buf.Bytes()))
+
+	doc := struct {
+		Groups []struct {
+			Signature string
+			Results   []int
+		}
+	}{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, 5, len(doc.Groups))
+	assert.Equal(t, "rights missing", doc.Groups[0].Signature)
+	assert.Equal(t, []int{0, 1, 2, 3}, doc.Groups[0].Results)
+}
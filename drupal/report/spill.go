@@ -0,0 +1,145 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// A result as written to the spill file of a Report
+type spilledResult struct {
+	Fixture    string            `json:"fixture,omitempty"`
+	Type       string            `json:"type"`
+	Bundle     string            `json:"bundle"`
+	Key        string            `json:"key"`
+	Pid        string            `json:"pid,omitempty"`
+	Mismatches []verify.Mismatch `json:"mismatches,omitempty"`
+	Drift      []verify.Mismatch `json:"drift,omitempty"`
+	Unverified []string          `json:"unverified,omitempty"`
+	Checked    []string          `json:"checked,omitempty"`
+	VerifyOnly []string          `json:"verify_only,omitempty"`
+	Violations []jsonViolation   `json:"violations,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Duration   time.Duration     `json:"duration"`
+}
+
+// Appends the results to the report.  If MaxResults is positive, the earliest results are spilled to a temporary
+// file once more than MaxResults are held in memory, and are read back one at a time as the report is written, so
+// that a run verifying many thousands of fixtures holds a bounded number of results in memory.  Close removes the
+// spill file.
+func (r *Report) Add(results ...*verify.Result) error {
+	r.Results = append(r.Results, results...)
+	if r.MaxResults <= 0 || len(r.Results) <= r.MaxResults {
+		return nil
+	}
+	if r.spill == nil {
+		f, err := ioutil.TempFile(r.SpillDir, "idc-report-*.jsonl")
+		if err != nil {
+			return fmt.Errorf("report: unable to create a spill file: %w", err)
+		}
+		r.spill = f
+	}
+	excess := len(r.Results) - r.MaxResults
+	enc := json.NewEncoder(r.spill)
+	for _, result := range r.Results[:excess] {
+		s := spilledResult{Fixture: result.Fixture, Type: result.Type, Bundle: result.Bundle, Key: result.Key, Pid: result.Pid,
+			Mismatches: result.Mismatches, Drift: result.Drift, Unverified: result.Unverified, Checked: result.Checked,
+			VerifyOnly: result.VerifyOnly, Duration: result.Duration}
+		if result.Err != nil {
+			s.Error = result.Err.Error()
+		}
+		for _, v := range result.Violations {
+			s.Violations = append(s.Violations, jsonViolation{Rule: v.Rule, Error: v.Err.Error()})
+		}
+		if err := enc.Encode(s); err != nil {
+			return fmt.Errorf("report: unable to spill a result to %s: %w", r.spill.Name(), err)
+		}
+		r.spilled.count(result)
+		if r.spilledBundles == nil {
+			r.spilledBundles = map[string]BundleSummary{}
+		}
+		countBundle(r.spilledBundles, result)
+	}
+	// copy the retained results, so that the spilled results may be collected
+	r.Results = append([]*verify.Result(nil), r.Results[excess:]...)
+	return nil
+}
+
+// Answers the number of results spilled to disk by Add
+func (r *Report) Spilled() int {
+	return r.spilled.Total
+}
+
+// Removes the spill file, if any.  The spilled results are discarded.
+func (r *Report) Close() error {
+	if r.spill == nil {
+		return nil
+	}
+	name := r.spill.Name()
+	err := r.spill.Close()
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	r.spill, r.spilled, r.spilledBundles = nil, Summary{}, nil
+	return err
+}
+
+// Invokes fn with each result of the report and its index, reading spilled results back from disk one at a time
+func (r *Report) each(fn func(i int, result *verify.Result) error) error {
+	i := 0
+	if r.spill != nil {
+		info, err := r.spill.Stat()
+		if err != nil {
+			return fmt.Errorf("report: unable to read spilled results: %w", err)
+		}
+		dec := json.NewDecoder(io.NewSectionReader(r.spill, 0, info.Size()))
+		for ; i < r.spilled.Total; i++ {
+			s := spilledResult{}
+			if err := dec.Decode(&s); err != nil {
+				return fmt.Errorf("report: unable to read spilled results from %s: %w", r.spill.Name(), err)
+			}
+			if err := fn(i, s.result()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, result := range r.Results {
+		if err := fn(i, result); err != nil {
+			return err
+		}
+		i++
+	}
+	return nil
+}
+
+func (s spilledResult) result() *verify.Result {
+	result := &verify.Result{Fixture: s.Fixture, Type: s.Type, Bundle: s.Bundle, Key: s.Key, Pid: s.Pid,
+		Mismatches: s.Mismatches, Drift: s.Drift, Unverified: s.Unverified, Checked: s.Checked, VerifyOnly: s.VerifyOnly,
+		Duration: s.Duration}
+	if s.Error != "" {
+		result.Err = errors.New(s.Error)
+	}
+	for _, v := range s.Violations {
+		result.Violations = append(result.Violations, verify.Violation{Rule: v.Rule, Err: errors.New(v.Error)})
+	}
+	return result
+}
+
+// Counts the result
+func (s *Summary) count(result *verify.Result) {
+	s.Total++
+	switch {
+	case result.Err != nil:
+		s.Errored++
+	case result.Passed():
+		s.Passed++
+	default:
+		s.Failed++
+	}
+}
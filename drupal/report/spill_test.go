@@ -0,0 +1,43 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Spill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spill")
+	require.Nil(t, err)
+	expected := newGroupedReport()
+	r := &Report{Started: expected.Started, Finished: expected.Finished, MaxResults: 2, SpillDir: dir}
+	for _, result := range expected.Results {
+		require.Nil(t, r.Add(result))
+	}
+	assert.Equal(t, 2, len(r.Results))
+	assert.Equal(t, len(expected.Results)-2, r.Spilled())
+	assert.Equal(t, expected.Summary(), r.Summary())
+	files, _ := ioutil.ReadDir(dir)
+	assert.Equal(t, 1, len(files))
+
+	// spilled results are written exactly as results held in memory
+	for _, write := range []func(*Report, *bytes.Buffer) error{
+		func(r *Report, buf *bytes.Buffer) error { return r.WriteText(buf) },
+		func(r *Report, buf *bytes.Buffer) error { return r.WriteJson(buf) },
+		func(r *Report, buf *bytes.Buffer) error { return r.WriteGroups(buf) },
+	} {
+		want, got := &bytes.Buffer{}, &bytes.Buffer{}
+		require.Nil(t, write(expected, want))
+		require.Nil(t, write(r, got))
+		assert.Equal(t, want.String(), got.String())
+	}
+	assert.Nil(t, Validate(func() []byte { buf := &bytes.Buffer{}; _ = r.WriteJson(buf); return buf.Bytes() }()))
+
+	require.Nil(t, r.Close())
+	files, _ = ioutil.ReadDir(dir)
+	assert.Equal(t, 0, len(files))
+	assert.Equal(t, 0, r.Spilled())
+}
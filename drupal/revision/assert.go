@@ -0,0 +1,44 @@
+//go:build !notestify
+// +build !notestify
+
+package revision
+
+import (
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the node has the number of revisions
+func AssertRevisionCount(t assert.TestingT, expected int, actual []Revision) bool {
+	return assert.Equal(t, expected, len(actual), "unexpected number of revisions")
+}
+
+// Asserts that the revisions match the expected revisions, oldest first.  Only the non-empty values of each expected
+// revision are compared; timestamps are compared as instants.
+func AssertRevisions(t assert.TestingT, expected []model.ExpectedRevision, actual []Revision) bool {
+	if !AssertRevisionCount(t, len(expected), actual) {
+		return false
+	}
+	ok := true
+	for i, e := range expected {
+		a := actual[i]
+		if e.Author != "" {
+			ok = assert.Equal(t, e.Author, a.Author, "author of revision %d (%d) differs", i, a.Id) && ok
+		}
+		if e.LogMessage != "" {
+			ok = assert.Equal(t, e.LogMessage, a.LogMessage, "log message of revision %d (%d) differs", i, a.Id) && ok
+		}
+		if e.Timestamp != "" {
+			ts, err := time.Parse(time.RFC3339, e.Timestamp)
+			if !assert.Nil(t, err, "invalid expected timestamp of revision %d: %s", i, err) {
+				ok = false
+				continue
+			}
+			ok = assert.True(t, ts.Equal(a.Timestamp), "timestamp of revision %d (%d) differs: expected %s, got %s", i, a.Id,
+				e.Timestamp, a.Timestamp.Format(time.RFC3339)) && ok
+		}
+	}
+	return ok
+}
@@ -0,0 +1,172 @@
+// Retrieves the revisions of nodes, so that tests may verify the revisions created by migrations run in update mode:
+//
+//	c := revision.NewClient(baseUrl, username, password)
+//	revs, err := c.Revisions(model.RepositoryObject, uuid)
+//	revision.AssertRevisions(t, []model.ExpectedRevision{
+//		{Author: "migration", LogMessage: "Created by idc_ingest_new_items"},
+//		{Author: "migration", LogMessage: "Updated by idc_ingest_new_items"},
+//	}, revs)
+//
+// Each revision is retrieved from JSON:API using the `resourceVersion` query parameter.  JSON:API cannot list the
+// revisions of a node, so their ids are taken from the node's revisions page, `/node/{nid}/revisions`, which requires
+// the `view all revisions` permission.
+package revision
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The resourceVersion of the latest revision of an entity
+const LatestVersion = "rel:latest-version"
+
+// The path of the revisions page of a node; `%d` is replaced by the node id
+const RevisionsPath = "/node/%d/revisions"
+
+// Matches the links of the revisions page to each revision, capturing the revision id
+var revisionLink = regexp.MustCompile(`/node/\d+/revisions/(\d+)/(?:view|revert|delete)`)
+
+// A revision of a node
+type Revision struct {
+	// The revision id, i.e. `drupal_internal__vid`
+	Id int
+	// The node id, i.e. `drupal_internal__nid`
+	Nid  int
+	Uuid string
+	// The name of the user who created the revision, resolved from the `revision_uid` relationship
+	Author     string
+	LogMessage string
+	Timestamp  time.Time
+}
+
+// Retrieves the revisions of nodes
+type Client struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the Drupal site at the base url
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the latest revision of the node of the bundle with the uuid
+func (c *Client) Latest(bundle, uuid string) (*Revision, error) {
+	return c.fetch(bundle, uuid, LatestVersion)
+}
+
+// Answers the revision with the id of the node of the bundle with the uuid
+func (c *Client) Revision(bundle, uuid string, id int) (*Revision, error) {
+	return c.fetch(bundle, uuid, "id:"+strconv.Itoa(id))
+}
+
+// Answers every revision of the node of the bundle with the uuid, oldest first
+func (c *Client) Revisions(bundle, uuid string) ([]Revision, error) {
+	latest, err := c.Latest(bundle, uuid)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := c.RevisionIds(latest.Nid)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []Revision{*latest}
+	for _, id := range ids {
+		if id == latest.Id {
+			continue
+		}
+		r, err := c.Revision(bundle, uuid, id)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *r)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Id < revisions[j].Id })
+	return revisions, nil
+}
+
+// Answers the ids of the revisions linked by the revisions page of the node, ascending.  The current revision is not
+// linked by the page, so it may be absent.
+func (c *Client) RevisionIds(nid int) ([]int, error) {
+	u := strings.TrimSuffix(c.BaseUrl, "/") + fmt.Sprintf(RevisionsPath, nid)
+	_, body, err := jsonapi.FetchResource(u, c.Username, c.Password.Reveal())
+	if err != nil {
+		return nil, fmt.Errorf("revision: %w", err)
+	}
+	seen := map[int]bool{}
+	var ids []int
+	for _, m := range revisionLink.FindAllStringSubmatch(string(body), -1) {
+		id, _ := strconv.Atoi(m[1])
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (c *Client) fetch(bundle, uuid, version string) (*Revision, error) {
+	base, err := (&jsonapi.JsonApiUrl{BaseUrl: c.BaseUrl, DrupalEntity: model.Node, DrupalBundle: bundle}).Url()
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/%s?resourceVersion=%s&include=revision_uid", base, uuid, version)
+	res, body, err := jsonapi.FetchResource(u, c.Username, c.Password.Reveal())
+	if err == nil {
+		err = jsonapi.CheckJson(res, body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("revision: %w", err)
+	}
+
+	doc := struct {
+		Data     map[string]interface{}   `json:"data"`
+		Included []map[string]interface{} `json:"included"`
+	}{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("revision: unable to unmarshal %s: %w", u, err)
+	}
+	return newRevision(doc.Data, &jsonapi.JsonApiPage{Included: doc.Included})
+}
+
+// Answers the revision carried by the resource object, resolving its author from the included resources of the page
+func newRevision(data map[string]interface{}, page *jsonapi.JsonApiPage) (*Revision, error) {
+	attributes, _ := data["attributes"].(map[string]interface{})
+	r := &Revision{}
+	r.Uuid, _ = data["id"].(string)
+	vid, _ := attributes["drupal_internal__vid"].(float64)
+	nid, _ := attributes["drupal_internal__nid"].(float64)
+	r.Id, r.Nid = int(vid), int(nid)
+	r.LogMessage, _ = attributes["revision_log"].(string)
+	if ts, ok := attributes["revision_timestamp"].(string); ok && ts != "" {
+		t, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			return nil, fmt.Errorf("revision: invalid revision_timestamp '%s' of %s: %w", ts, r.Uuid, err)
+		}
+		r.Timestamp = t
+	}
+
+	relationships, _ := data["relationships"].(map[string]interface{})
+	rel, _ := relationships["revision_uid"].(map[string]interface{})
+	ref, _ := rel["data"].(map[string]interface{})
+	user := page.Related(ref)
+	userAttributes, _ := user["attributes"].(map[string]interface{})
+	if name, ok := userAttributes["name"].(string); ok && name != "" {
+		r.Author = name
+	} else {
+		r.Author, _ = userAttributes["display_name"].(string)
+	}
+	return r, nil
+}
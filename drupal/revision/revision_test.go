@@ -0,0 +1,91 @@
+//go:build !notestify
+// +build !notestify
+
+package revision
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const uuid = "0b4d8c6a-4e61-4fc1-8d2b-7d0d1c6a0b11"
+
+// A node with revisions 3 and 7, created by the migration user, and a current revision 9 created by an editor
+func newServer() *httptest.Server {
+	revisions := map[string]string{
+		"id:3":        `{"drupal_internal__vid": 3, "revision_log": "Created by idc_ingest_new_items", "revision_timestamp": "2021-06-01T12:00:00+00:00"}`,
+		"id:7":        `{"drupal_internal__vid": 7, "revision_log": "Updated by idc_ingest_new_items", "revision_timestamp": "2021-06-02T12:00:00+00:00"}`,
+		LatestVersion: `{"drupal_internal__vid": 9, "revision_log": null, "revision_timestamp": "2021-06-03T08:00:00-04:00"}`,
+	}
+	authors := map[string]string{"id:3": "migration", "id:7": "migration", LatestVersion: "editor"}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/node/1/revisions":
+			w.Write([]byte(`<table><tr><td>Current revision</td></tr>
+				<tr><td><a href="/node/1/revisions/7/view">7</a> <a href="/node/1/revisions/7/revert">Revert</a></td></tr>
+				<tr><td><a href="/node/1/revisions/3/view">3</a></td></tr></table>`))
+		case "/jsonapi/node/islandora_object/" + uuid:
+			version := r.URL.Query().Get("resourceVersion")
+			attributes, ok := revisions[version]
+			if !ok || r.URL.Query().Get("include") != "revision_uid" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprintf(w, `{"data": {"type": "node--islandora_object", "id": "%s", "attributes": %s,
+				"relationships": {"revision_uid": {"data": {"type": "user--user", "id": "u-%s"}}}},
+				"included": [{"type": "user--user", "id": "u-%s", "attributes": {"display_name": "%s"}}]}`,
+				uuid, attributes[:len(attributes)-1]+`, "drupal_internal__nid": 1}`, authors[version], authors[version], authors[version])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func Test_Revisions(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+	c := NewClient(server.URL, "", "")
+
+	latest, err := c.Latest(model.RepositoryObject, uuid)
+	require.Nil(t, err)
+	assert.Equal(t, 9, latest.Id)
+	assert.Equal(t, 1, latest.Nid)
+	assert.Equal(t, "editor", latest.Author)
+	assert.Empty(t, latest.LogMessage)
+
+	ids, err := c.RevisionIds(1)
+	require.Nil(t, err)
+	assert.Equal(t, []int{3, 7}, ids)
+
+	revs, err := c.Revisions(model.RepositoryObject, uuid)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(revs))
+	assert.Equal(t, []int{3, 7, 9}, []int{revs[0].Id, revs[1].Id, revs[2].Id})
+
+	assert.True(t, AssertRevisionCount(t, 3, revs))
+	assert.True(t, AssertRevisions(t, []model.ExpectedRevision{
+		{Author: "migration", LogMessage: "Created by idc_ingest_new_items", Timestamp: "2021-06-01T12:00:00Z"},
+		{Author: "migration", LogMessage: "Updated by idc_ingest_new_items"},
+		{Author: "editor", Timestamp: "2021-06-03T12:00:00Z"},
+	}, revs))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertRevisions(rec, []model.ExpectedRevision{{Author: "migration"}}, revs))
+	assert.Contains(t, rec.String(), "unexpected number of revisions")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertRevisions(rec, []model.ExpectedRevision{
+		{Author: "editor"}, {LogMessage: "Created"}, {Timestamp: "2021-06-03T08:00:00Z"},
+	}, revs))
+	assert.Contains(t, rec.String(), "author of revision 0 (3) differs")
+	assert.Contains(t, rec.String(), "log message of revision 1 (7) differs")
+	assert.Contains(t, rec.String(), "timestamp of revision 2 (9) differs")
+
+	_, err = c.Revision(model.RepositoryObject, uuid, 4)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,204 @@
+// Repoints relationships in bulk using JSON:API PATCH requests, e.g. to move every child of one collection to another
+// when a partial migration is re-run in a shared staging site, or when tearing down and repairing test content.
+//
+// Changes are planned before they are applied, so that the plan may be reviewed (a dry run):
+//
+//	w := rewrite.NewRewriter(env.BaseUrl(), env.UsernameOr("admin"), env.PasswordOr(""))
+//	changes, err := w.Plan("node", "islandora_object", "field_member_of", collectionA, "node--collection_object", collectionB)
+//	rewrite.WritePlan(os.Stdout, changes)
+//	err = w.Apply(changes)
+//
+// Drupal must permit JSON:API write operations, and the user must be permitted to update the entities.
+package rewrite
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// Applying a plan failed part of the way through
+var ErrPartial = errors.New("rewrite: changes were partially applied")
+
+// A planned change of a relationship of one entity
+type Change struct {
+	// The type of the entity, e.g. `node--islandora_object`
+	Type string
+	Id   string
+	// The title or name of the entity, if it has one
+	Label string
+	// The relationship changed, e.g. `field_member_of`
+	Relationship string
+	// The ids of the entities referenced before and after the change
+	From []string
+	To   []string
+	// The resource identifiers of the relationship after the change, carrying the meta of the references kept
+	data []map[string]interface{}
+	// Whether the relationship is single-valued
+	single bool
+}
+
+// Answers the change as e.g. `node--islandora_object 4f2a... (Moonrise): field_member_of [c1] -> [c2]`
+func (c Change) String() string {
+	entity := c.Type + " " + c.Id
+	if c.Label != "" {
+		entity += fmt.Sprintf(" (%s)", c.Label)
+	}
+	return fmt.Sprintf("%s: %s [%s] -> [%s]", entity, c.Relationship, strings.Join(c.From, ", "), strings.Join(c.To, ", "))
+}
+
+// Plans and applies changes of relationships
+type Rewriter struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Rewriter of the Drupal site at the base url.  If the username is not empty, requests are authenticated
+// using HTTP Basic Auth.
+func NewRewriter(baseUrl, username, password string) *Rewriter {
+	return &Rewriter{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Plans to repoint the relationship (e.g. `field_member_of`) of every entity of the entity type and bundle that
+// references the entity with the id `from` to reference the entity of the Drupal type `toType` (e.g.
+// `node--collection_object`) with the id `to` instead.  The type of `to` is required, as it need not be the type of
+// `from`, e.g. when a member of a collection is moved to a compound object.  Other references of a multi-valued
+// relationship are kept, in order; a reference to `to` is not duplicated.  Changes are ordered by the id of the
+// entity.  Nothing is changed until the plan is applied.
+func (w *Rewriter) Plan(entityType, bundle, relationship, from, toType, to string) ([]Change, error) {
+	if from == "" || to == "" || from == to {
+		return nil, fmt.Errorf("rewrite: distinct ids to repoint from and to are required, not '%s' and '%s'", from, to)
+	}
+	t, err := jsonapi.ParseDrupalType(toType)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite: the type of %s is required: %w", to, err)
+	}
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      w.BaseUrl,
+		DrupalEntity: entityType,
+		DrupalBundle: bundle,
+		Filter:       relationship + ".id",
+		Value:        from,
+		Username:     w.Username,
+		Password:     w.Password,
+	}
+	var changes []Change
+	err = u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			if c, ok := plan(d, relationship, from, string(t), to); ok {
+				changes = append(changes, c)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rewrite: error finding the entities referencing %s: %w", from, err)
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Id < changes[j].Id })
+	return changes, nil
+}
+
+// Applies the planned changes in order, PATCHing the relationship of each entity.  If a change fails, the changes
+// following it are not applied, and an error wrapping ErrPartial (if any change was applied) is answered.
+func (w *Rewriter) Apply(changes []Change) error {
+	for i, c := range changes {
+		entityType, bundle := jsonapi.DrupalType(c.Type).Entity(), jsonapi.DrupalType(c.Type).Bundle()
+		target := strings.TrimSuffix(w.BaseUrl, "/") + "/jsonapi/" + entityType + "/" + bundle + "/" + c.Id
+		var data interface{} = c.data
+		if c.single {
+			data = nil
+			if len(c.data) > 0 {
+				data = c.data[0]
+			}
+		}
+		doc := map[string]interface{}{"data": map[string]interface{}{
+			"type":          c.Type,
+			"id":            c.Id,
+			"relationships": map[string]interface{}{c.Relationship: map[string]interface{}{"data": data}},
+		}}
+		if _, err := jsonapi.UpdateResource(target, w.Username, w.Password.Reveal(), doc); err != nil {
+			if i > 0 {
+				return fmt.Errorf("%w (%d of %d applied): %s: %s", ErrPartial, i, len(changes), c, err)
+			}
+			return fmt.Errorf("rewrite: %s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// Plans and, unless dryRun is true, applies the changes repointing the relationship (see Plan), answering the
+// changes planned
+func (w *Rewriter) Repoint(entityType, bundle, relationship, from, toType, to string, dryRun bool) ([]Change, error) {
+	changes, err := w.Plan(entityType, bundle, relationship, from, toType, to)
+	if err != nil || dryRun {
+		return changes, err
+	}
+	return changes, w.Apply(changes)
+}
+
+// Writes the planned changes, one per line, followed by their number
+func WritePlan(out io.Writer, changes []Change) error {
+	for _, c := range changes {
+		if _, err := fmt.Fprintln(out, c); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d changes planned\n", len(changes))
+	return err
+}
+
+// Answers the change repointing the relationship of the data element from one id to another of the type `toType`, or
+// false if the element does not reference `from`
+func plan(d map[string]interface{}, relationship, from, toType, to string) (Change, bool) {
+	c := Change{Relationship: relationship}
+	c.Type, _ = d["type"].(string)
+	c.Id, _ = d["id"].(string)
+	attrs, _ := d["attributes"].(map[string]interface{})
+	if c.Label, _ = attrs["title"].(string); c.Label == "" {
+		c.Label, _ = attrs["name"].(string)
+	}
+
+	rels, _ := d["relationships"].(map[string]interface{})
+	rel, _ := rels[relationship].(map[string]interface{})
+	var refs []map[string]interface{}
+	switch data := rel["data"].(type) {
+	case map[string]interface{}:
+		refs, c.single = []map[string]interface{}{data}, true
+	case []interface{}:
+		for _, item := range data {
+			if ref, ok := item.(map[string]interface{}); ok {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	found, present := false, false
+	for _, ref := range refs {
+		id, _ := ref["id"].(string)
+		c.From = append(c.From, id)
+		present = present || id == to
+	}
+	for _, ref := range refs {
+		id, _ := ref["id"].(string)
+		switch {
+		case id == from && !found:
+			found = true
+			if !present {
+				c.data = append(c.data, map[string]interface{}{"type": toType, "id": to})
+				c.To = append(c.To, to)
+			}
+		case id == from:
+			// a duplicate reference to `from` is dropped
+		default:
+			c.data = append(c.data, ref)
+			c.To = append(c.To, id)
+		}
+	}
+	return c, found
+}
@@ -0,0 +1,156 @@
+package rewrite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func node(id, title string, memberOf ...string) jsonapitest.Resource {
+	var refs []interface{}
+	for _, parent := range memberOf {
+		refs = append(refs, map[string]interface{}{"type": "node--collection_object", "id": parent})
+	}
+	return jsonapitest.Resource{"type": "node--islandora_object", "id": id, "attributes": map[string]interface{}{"title": title},
+		"relationships": map[string]interface{}{
+			"field_member_of": map[string]interface{}{"data": refs},
+			"field_model":     map[string]interface{}{"data": map[string]interface{}{"type": "taxonomy_term--islandora_models", "id": "m1"}},
+		}}
+}
+
+func newServer() *jsonapitest.MockServer {
+	m := jsonapitest.NewMockServer()
+	m.PageSize = 1
+	m.Add(
+		node("o1", "Moonrise", "a"),
+		node("o2", "Church", "c", "a"),
+		node("o3", "Cemetery", "a", "b"),
+		node("o4", "Hernandez", "c"),
+	)
+	return m
+}
+
+func Test_Plan(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	w := NewRewriter(m.URL, "", "")
+
+	changes, err := w.Plan("node", "islandora_object", "field_member_of", "a", "node--collection_object", "b")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(changes))
+	assert.Equal(t, "node--islandora_object o1 (Moonrise): field_member_of [a] -> [b]", changes[0].String())
+	assert.Equal(t, []string{"c", "b"}, changes[1].To)
+	// a reference to `b` is not duplicated
+	assert.Equal(t, []string{"a", "b"}, changes[2].From)
+	assert.Equal(t, []string{"b"}, changes[2].To)
+
+	out := &bytes.Buffer{}
+	require.Nil(t, WritePlan(out, changes))
+	assert.Contains(t, out.String(), "o2 (Church): field_member_of [c, a] -> [c, b]\n")
+	assert.Contains(t, out.String(), "3 changes planned\n")
+
+	// planning changes nothing
+	changes, err = w.Plan("node", "islandora_object", "field_member_of", "a", "node--collection_object", "b")
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(changes))
+
+	_, err = w.Plan("node", "islandora_object", "field_member_of", "a", "node--collection_object", "a")
+	assert.NotNil(t, err)
+	_, err = w.Plan("node", "islandora_object", "field_member_of", "a", "", "b")
+	assert.NotNil(t, err)
+}
+
+// A member of a collection may be moved to an entity of another bundle, e.g. a compound object
+func Test_ApplyCrossBundle(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "p1", "attributes": map[string]interface{}{"title": "Photographs"}})
+	w := NewRewriter(m.URL, "", "")
+
+	changes, err := w.Repoint("node", "islandora_object", "field_member_of", "c", "node--islandora_object", "p1", false)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(changes))
+
+	u := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "field_member_of.id", Value: "p1"}
+	var members []string
+	require.Nil(t, u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			refs := d["relationships"].(map[string]interface{})["field_member_of"].(map[string]interface{})["data"].([]interface{})
+			for _, ref := range refs {
+				if ref := ref.(map[string]interface{}); ref["id"] == "p1" {
+					members = append(members, fmt.Sprintf("%s -> %s", d["id"], ref["type"]))
+				}
+			}
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"o2 -> node--islandora_object", "o4 -> node--islandora_object"}, members)
+}
+
+// Changes are ordered by entity id, whatever order Drupal answers the entities in
+func Test_PlanOrdering(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	m.Add(node("o3", "Cemetery", "a"), node("o1", "Moonrise", "a"), node("o2", "Church", "a"))
+
+	changes, err := NewRewriter(m.URL, "", "").Plan("node", "islandora_object", "field_member_of", "a", "node--collection_object", "b")
+	require.Nil(t, err)
+	var ids []string
+	for _, c := range changes {
+		ids = append(ids, c.Id)
+	}
+	assert.Equal(t, []string{"o1", "o2", "o3"}, ids)
+}
+
+func Test_Apply(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	w := NewRewriter(m.URL, "", "")
+
+	changes, err := w.Repoint("node", "islandora_object", "field_member_of", "a", "node--collection_object", "b", false)
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(changes))
+
+	changes, err = w.Plan("node", "islandora_object", "field_member_of", "a", "node--collection_object", "b")
+	require.Nil(t, err)
+	assert.Empty(t, changes)
+	changes, err = w.Plan("node", "islandora_object", "field_member_of", "b", "node--collection_object", "a")
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(changes))
+	assert.Equal(t, []string{"c", "b"}, changes[1].From)
+
+	// single-valued relationships are written as a single resource identifier
+	changes, err = w.Repoint("node", "islandora_object", "field_model", "m1", "taxonomy_term--islandora_models", "m2", false)
+	require.Nil(t, err)
+	assert.Equal(t, 4, len(changes))
+	changes, err = w.Plan("node", "islandora_object", "field_model", "m2", "taxonomy_term--islandora_models", "m1")
+	require.Nil(t, err)
+	assert.Equal(t, 4, len(changes))
+}
+
+func Test_ApplyPartial(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	w := NewRewriter(m.URL, "", "")
+
+	changes, err := w.Repoint("node", "islandora_object", "field_member_of", "c", "node--collection_object", "d", true)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(changes))
+
+	// the second entity is deleted after the plan is made
+	changes[1].Id = "o5"
+	err = w.Apply(changes)
+	assert.True(t, errors.Is(err, ErrPartial))
+	assert.Contains(t, err.Error(), "1 of 2 applied")
+
+	err = w.Apply(changes[1:])
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrPartial))
+}
@@ -0,0 +1,18 @@
+//go:build !notestify
+// +build !notestify
+
+package session
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that a GET of the path within the session is answered the expected status, e.g. http.StatusForbidden for
+// `/node/1/edit` if the user may not edit the node
+func (s *Session) AssertStatus(t assert.TestingT, path string, expected int) bool {
+	actual, err := s.Status(path)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	return assert.Equal(t, expected, actual, "unexpected status of %s for user '%s'", path, s.Username)
+}
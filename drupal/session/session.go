@@ -0,0 +1,185 @@
+// Logs in to Drupal using its login form, so that verification steps may request regular Drupal pages that are not
+// exposed by JSON:API, e.g. to check access to `/node/{nid}/edit`:
+//
+//	s, err := session.Login(baseUrl, "editor", password)
+//	status, err := s.Status("/node/1/edit")
+//	s.AssertStatus(t, "/node/1/edit", http.StatusForbidden)
+//
+// A Session is also a jsonapi.AuthProvider, so the HTTP client of the jsonapi package may use the session instead of
+// HTTP Basic Auth:
+//
+//	jsonapi.Configure(jsonapi.ClientConfig{Auth: s, AuthBaseUrl: s.BaseUrl})
+package session
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var ErrLoginFailed = errors.New("session: login failed")
+
+// The paths of the pages used by a Session
+const (
+	LoginPath  = "/user/login"
+	LogoutPath = "/user/logout"
+	TokenPath  = "/session/token"
+)
+
+// The header carrying the CSRF token of unsafe requests, as required by Drupal's REST and JSON:API modules for
+// session-authenticated requests
+const CsrfHeader = "X-CSRF-Token"
+
+var formBuildId = regexp.MustCompile(`<input[^>]*name="form_build_id"[^>]*>`)
+var inputValue = regexp.MustCompile(`value="([^"]*)"`)
+
+// An authenticated Drupal session, carried by a session cookie
+type Session struct {
+	BaseUrl  string
+	Username string
+	// The cookies of the session
+	Jar http.CookieJar
+	// The CSRF token of the session, sent with unsafe requests
+	CsrfToken string
+}
+
+// Logs in to the Drupal site at the base url by submitting its login form, and answers the session.  The CSRF token of
+// the session is retrieved from `/session/token`.  An error wrapping ErrLoginFailed is answered if Drupal does not
+// establish a session, e.g. because the credentials are rejected.
+func Login(baseUrl, username, password string) (*Session, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("session: %w", err)
+	}
+	s := &Session{BaseUrl: strings.TrimSuffix(baseUrl, "/"), Username: username, Jar: jar}
+	client := s.client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	form, err := s.read(client, http.MethodGet, LoginPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	input := formBuildId.FindString(form)
+	value := inputValue.FindStringSubmatch(input)
+	if value == nil {
+		return nil, fmt.Errorf("session: no login form found at %s", s.BaseUrl+LoginPath)
+	}
+
+	values := url.Values{
+		"name":          {username},
+		"pass":          {password},
+		"form_build_id": {html.UnescapeString(value[1])},
+		"form_id":       {"user_login_form"},
+		"op":            {"Log in"},
+	}
+	if _, err := s.read(client, http.MethodPost, LoginPath, values); err != nil {
+		return nil, err
+	}
+	if !s.LoggedIn() {
+		return nil, fmt.Errorf("%w for user '%s' at %s", ErrLoginFailed, username, s.BaseUrl)
+	}
+
+	token, err := s.read(client, http.MethodGet, TokenPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.CsrfToken = strings.TrimSpace(token)
+	return s, nil
+}
+
+// Answers true if the session carries a Drupal session cookie, i.e. one named `SESS...` or `SSESS...`
+func (s *Session) LoggedIn() bool {
+	u, err := url.Parse(s.BaseUrl)
+	if err != nil {
+		return false
+	}
+	for _, c := range s.Jar.Cookies(u) {
+		if strings.HasPrefix(c.Name, "SESS") || strings.HasPrefix(c.Name, "SSESS") {
+			return true
+		}
+	}
+	return false
+}
+
+// Ends the session
+func (s *Session) Logout() error {
+	client := s.client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	_, err := s.read(client, http.MethodGet, LogoutPath, nil)
+	return err
+}
+
+// Sets the cookies of the session on the request, and its CSRF token if the request is unsafe (e.g. a POST)
+func (s *Session) Authenticate(req *http.Request) error {
+	for _, c := range s.Jar.Cookies(req.URL) {
+		req.AddCookie(c)
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+	default:
+		if s.CsrfToken != "" {
+			req.Header.Set(CsrfHeader, s.CsrfToken)
+		}
+	}
+	return nil
+}
+
+// Answers a transport sending requests with the cookies and CSRF token of the session, using the transport of the
+// jsonapi package's HTTP client
+func (s *Session) RoundTripper() http.RoundTripper {
+	return &jsonapi.AuthTransport{Provider: s, Transport: jsonapi.HTTPClient().Transport, BaseUrl: s.BaseUrl}
+}
+
+// Answers an HTTP client sending requests within the session.  Redirects are followed.
+func (s *Session) Client() *http.Client {
+	return s.client()
+}
+
+// Answers the HTTP status of a GET of the path (e.g. `/node/1/edit`) within the session, without following redirects
+func (s *Session) Status(path string) (int, error) {
+	client := s.client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	res, err := client.Get(s.BaseUrl + path)
+	if err != nil {
+		return 0, fmt.Errorf("session: error requesting %s: %w", s.BaseUrl+path, err)
+	}
+	res.Body.Close()
+	return res.StatusCode, nil
+}
+
+func (s *Session) client() *http.Client {
+	base := jsonapi.HTTPClient()
+	return &http.Client{Transport: base.Transport, Timeout: base.Timeout, Jar: s.Jar}
+}
+
+// Sends the request, answering the response body.  Responses other than 2xx and 3xx are answered as errors.
+func (s *Session) read(client *http.Client, method, path string, form url.Values) (string, error) {
+	u := s.BaseUrl + path
+	var res *http.Response
+	var err error
+	if method == http.MethodPost {
+		res, err = client.PostForm(u, form)
+	} else {
+		res, err = client.Get(u)
+	}
+	if err != nil {
+		return "", fmt.Errorf("session: error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("session: error reading response body from %s: %w", u, err)
+	}
+	if res.StatusCode >= 400 {
+		return "", fmt.Errorf("session: %d status encountered when requesting %s", res.StatusCode, u)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,122 @@
+//go:build !notestify
+// +build !notestify
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sessionCookie = "SESSd41d8cd98f00b204e9800998ecf8427e"
+
+// A Drupal site whose editor may edit node 1, but not node 2
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie(sessionCookie)
+		loggedIn := err == nil && c.Value == "editor-session"
+		switch {
+		case r.URL.Path == LoginPath && r.Method == http.MethodGet:
+			w.Write([]byte(`<form id="user-login-form"><input type="hidden" name="form_build_id" value="form-abc&amp;1" />
+				<input type="hidden" name="form_id" value="user_login_form" /></form>`))
+		case r.URL.Path == LoginPath && r.Method == http.MethodPost:
+			require.Nil(t, r.ParseForm())
+			assert.Equal(t, "form-abc&1", r.PostForm.Get("form_build_id"))
+			assert.Equal(t, "user_login_form", r.PostForm.Get("form_id"))
+			if r.PostForm.Get("name") != "editor" || r.PostForm.Get("pass") != "moonrise" {
+				w.Write([]byte(`<div role="alert">Unrecognized username or password.</div>`))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "editor-session", Path: "/"})
+			http.Redirect(w, r, "/user/2", http.StatusSeeOther)
+		case r.URL.Path == TokenPath:
+			w.Write([]byte("csrf-123"))
+		case r.URL.Path == LogoutPath:
+			http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1})
+			http.Redirect(w, r, "/", http.StatusFound)
+		case !loggedIn:
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/node/1/edit":
+			w.Write([]byte("edit form"))
+		case r.URL.Path == "/node/2/edit":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/jsonapi/node/islandora_object" && r.Method == http.MethodPost:
+			if r.Header.Get(CsrfHeader) != "csrf-123" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"type": "node--islandora_object", "id": "n1"}}`))
+		default:
+			w.Write([]byte(`{"data": []}`))
+		}
+	}))
+}
+
+func Test_Login(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	s, err := Login(server.URL+"/", "editor", "moonrise")
+	require.Nil(t, err)
+	assert.True(t, s.LoggedIn())
+	assert.Equal(t, "csrf-123", s.CsrfToken)
+
+	assert.True(t, s.AssertStatus(t, "/node/1/edit", http.StatusOK))
+	assert.True(t, s.AssertStatus(t, "/node/2/edit", http.StatusForbidden))
+	rec := &asserttest.Recorder{}
+	assert.False(t, s.AssertStatus(rec, "/node/2/edit", http.StatusOK))
+	assert.Contains(t, rec.String(), "unexpected status of /node/2/edit for user 'editor'")
+
+	res, err := s.Client().Get(server.URL + "/node/1/edit")
+	require.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	require.Nil(t, s.Logout())
+	assert.False(t, s.LoggedIn())
+	status, err := s.Status("/node/1/edit")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func Test_LoginFailed(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	_, err := Login(server.URL, "editor", "sunset")
+	assert.True(t, errors.Is(err, ErrLoginFailed))
+
+	_, err = Login(server.URL+"/missing", "editor", "moonrise")
+	assert.NotNil(t, err)
+}
+
+func Test_SessionAuthProvider(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+	defer jsonapi.SetHTTPClient(nil)
+
+	s, err := Login(server.URL, "editor", "moonrise")
+	require.Nil(t, err)
+
+	// unsafe requests carry the CSRF token
+	client := &http.Client{Transport: s.RoundTripper()}
+	res, err := client.Post(server.URL+"/jsonapi/node/islandora_object", "application/vnd.api+json", strings.NewReader(`{}`))
+	require.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	require.Nil(t, jsonapi.Configure(jsonapi.ClientConfig{Auth: s, AuthBaseUrl: server.URL}))
+	_, err = jsonapi.CreateResource(server.URL+"/jsonapi/node/islandora_object", "", "", map[string]interface{}{})
+	assert.Nil(t, err)
+	_, _, err = jsonapi.FetchResource(server.URL+"/node/1/edit", "", "")
+	assert.Nil(t, err)
+}
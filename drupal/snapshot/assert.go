@@ -0,0 +1,34 @@
+//go:build !notestify
+// +build !notestify
+
+package snapshot
+
+import (
+	"errors"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the normalized document matches the golden file of the snapshot name (see Match), reporting the
+// differences
+func (s *Snapshotter) Assert(t assert.TestingT, name string, doc []byte, msgAndArgs ...interface{}) bool {
+	golden, actual, err := s.match(name, doc)
+	if errors.Is(err, ErrMismatch) {
+		return assert.JSONEq(t, string(golden), string(actual), msgAndArgs...)
+	}
+	return assert.Nil(t, err, "error matching snapshot %s: %s", name, err)
+}
+
+// Asserts that the JSON:API response of the url matches the golden file of the snapshot name (see Match)
+func (s *Snapshotter) AssertUrl(t assert.TestingT, name string, u *jsonapi.JsonApiUrl, msgAndArgs ...interface{}) bool {
+	target, err := u.Url()
+	if !assert.Nil(t, err, "invalid url: %s", err) {
+		return false
+	}
+	_, body, err := jsonapi.FetchResource(target, u.Username, u.Password.Reveal())
+	if !assert.Nil(t, err, "error retrieving %s: %s", target, err) {
+		return false
+	}
+	return s.Assert(t, name, body, msgAndArgs...)
+}
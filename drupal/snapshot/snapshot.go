@@ -0,0 +1,166 @@
+// Approval testing of JSON:API responses: the first run of a test records the normalized response to a golden file,
+// and later runs compare the response against it, so that fixtures need not be written by hand.
+//
+// Responses are normalized before they are recorded or compared: members that differ between otherwise identical
+// sites (ids, timestamps, and links carrying the host) are removed (see DefaultExclusions), and the document is
+// written in canonical form (see package canonical), so that golden files diff well in review:
+//
+//	var snapshots = snapshot.New("testdata/snapshots")
+//
+//	func Test_Moonrise(t *testing.T) {
+//		u := &jsonapi.JsonApiUrl{BaseUrl: env.BaseUrl(), DrupalEntity: "node", DrupalBundle: "islandora_object",
+//			Filter: "title", Value: "Moonrise"}
+//		snapshots.AssertUrl(t, "moonrise", u)
+//	}
+//
+// Golden files are re-recorded by running the tests with UPDATE_SNAPSHOTS=1.
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/canonical"
+	"github.com/jhu-idc/idc-golang/drupal/logging"
+)
+
+// The environment variable which, if true, causes golden files to be re-recorded rather than compared
+const UpdateEnv = "UPDATE_SNAPSHOTS"
+
+// A document does not match its golden file
+var ErrMismatch = errors.New("snapshot: document differs from its snapshot")
+
+// The members removed from documents by default: those differing between otherwise identical sites, or between runs
+// of a migration
+var DefaultExclusions = []string{"id", "uuid", "created", "changed", "revision_timestamp", "revision_created",
+	"drupal_internal__*", "links", "jsonapi"}
+
+// Matches the characters of a snapshot name that are replaced in the name of its golden file
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._/-]+`)
+
+// Records and compares snapshots of JSON documents in a directory of golden files
+type Snapshotter struct {
+	// The directory of the golden files
+	Dir string
+	// The members removed from documents before they are recorded or compared: a key (e.g. `created`), matched at any
+	// depth, or a dotted path from the root (e.g. `data.attributes.field_weight`), either of which may carry the
+	// wildcards of path.Match.  Array indexes are not part of a path.
+	Exclude []string
+	// The members whose lists are sorted before they are recorded or compared, because their order is not
+	// significant, e.g. `field_subject`; matched as Exclude is matched
+	Unordered []string
+	// Whether golden files are re-recorded rather than compared; true if UpdateEnv is true when New is invoked
+	Update bool
+}
+
+// Creates a Snapshotter of the golden files in the directory, excluding DefaultExclusions
+func New(dir string) *Snapshotter {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return &Snapshotter{Dir: dir, Exclude: append([]string{}, DefaultExclusions...), Update: update}
+}
+
+// Answers the path of the golden file of the snapshot name, e.g. `testdata/snapshots/moonrise.json`.  Characters
+// other than letters, digits, `.`, `_`, `-` and `/` are replaced by `_`, so that e.g. t.Name() may be used as a name.
+func (s *Snapshotter) Path(name string) (string, error) {
+	safe := unsafeName.ReplaceAllString(name, "_")
+	clean := path.Clean(safe)
+	if safe == "" || path.IsAbs(safe) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("snapshot: invalid snapshot name '%s'", name)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(clean)+".json"), nil
+}
+
+// Compares the normalized document with the golden file of the snapshot name, answering an error wrapping
+// ErrMismatch if they differ.  If the golden file does not exist, or Update is true, the normalized document is
+// recorded instead.
+func (s *Snapshotter) Match(name string, doc []byte) error {
+	_, _, err := s.match(name, doc)
+	return err
+}
+
+// Answers the golden and normalized documents, and whether they differ
+func (s *Snapshotter) match(name string, doc []byte) ([]byte, []byte, error) {
+	file, err := s.Path(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	actual, err := normalize(doc, s.Exclude, s.Unordered)
+	if err != nil {
+		return nil, nil, err
+	}
+	golden, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("snapshot: error reading %s: %w", file, err)
+	}
+	if err != nil || s.Update {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, nil, fmt.Errorf("snapshot: %w", err)
+		}
+		if err := ioutil.WriteFile(file, actual, 0644); err != nil {
+			return nil, nil, fmt.Errorf("snapshot: %w", err)
+		}
+		logging.Infof("Recorded snapshot %s", file)
+		return actual, actual, nil
+	}
+	if !bytes.Equal(bytes.TrimSpace(golden), bytes.TrimSpace(actual)) {
+		// the golden file may have been edited by hand, so compare it normalized too
+		if normalized, err := normalize(golden, s.Exclude, s.Unordered); err != nil || !bytes.Equal(normalized, actual) {
+			return golden, actual, fmt.Errorf("%w: %s (run with %s=1 to re-record it)", ErrMismatch, file, UpdateEnv)
+		}
+	}
+	return golden, actual, nil
+}
+
+// Answers the JSON document with the excluded members removed (see Snapshotter.Exclude), in canonical form (see
+// canonical.Format)
+func Normalize(doc []byte, exclude ...string) ([]byte, error) {
+	return normalize(doc, exclude, nil)
+}
+
+// Answers the JSON document with the excluded members removed, in canonical form with the lists of the unordered
+// keys sorted
+func normalize(doc []byte, exclude, unordered []string) ([]byte, error) {
+	v, err := canonical.Decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("snapshot: invalid exclusion '%s': %w", pattern, err)
+		}
+	}
+	return canonical.Encode(prune(v, "", exclude), unordered...)
+}
+
+// Answers the value with the excluded members of its objects removed, at any depth
+func prune(v interface{}, at string, exclude []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		pruned := make(map[string]interface{}, len(v))
+		for k, member := range v {
+			p := k
+			if at != "" {
+				p = at + "." + k
+			}
+			if !canonical.Matches(k, p, exclude) {
+				pruned[k] = prune(member, p, exclude)
+			}
+		}
+		return pruned
+	case []interface{}:
+		pruned := make([]interface{}, len(v))
+		for i, item := range v {
+			pruned[i] = prune(item, at, exclude)
+		}
+		return pruned
+	}
+	return v
+}
@@ -0,0 +1,152 @@
+//go:build !notestify
+// +build !notestify
+
+package snapshot
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const moonrise = `{"jsonapi": {"version": "1.0"}, "data": [{"type": "node--islandora_object", "id": "b4f3c9a2",
+	"links": {"self": {"href": "https://islandora-idc.traefik.me/jsonapi/node/islandora_object/b4f3c9a2"}},
+	"attributes": {"title": "Moonrise", "drupal_internal__nid": 12, "created": "2021-09-08T20:06:18+00:00",
+		"field_weight": 3, "field_extent": ["1 photograph", "8 x 10 in."]},
+	"relationships": {"field_genre": {"data": [{"type": "taxonomy_term--genre", "id": "g1", "meta": {"drupal_internal__target_id": 4}}]}}}]}`
+
+func Test_Normalize(t *testing.T) {
+	b, err := Normalize([]byte(moonrise), DefaultExclusions...)
+	require.Nil(t, err)
+	assert.Equal(t, `{
+  "data": [
+    {
+      "attributes": {
+        "field_extent": [
+          "1 photograph",
+          "8 x 10 in."
+        ],
+        "field_weight": 3,
+        "title": "Moonrise"
+      },
+      "relationships": {
+        "field_genre": {
+          "data": [
+            {
+              "meta": {},
+              "type": "taxonomy_term--genre"
+            }
+          ]
+        }
+      },
+      "type": "node--islandora_object"
+    }
+  ]
+}
+`, string(b))
+
+	b, err = Normalize([]byte(moonrise), "data.attributes.field_*", "data.relationships", "jsonapi", "links", "id")
+	require.Nil(t, err)
+	assert.Contains(t, string(b), `"drupal_internal__nid": 12`)
+	assert.NotContains(t, string(b), "field_")
+
+	_, err = Normalize([]byte(`{"title": `))
+	assert.NotNil(t, err)
+	_, err = Normalize([]byte(`{}`), "[")
+	assert.NotNil(t, err)
+}
+
+func Test_Match(t *testing.T) {
+	s := New(t.TempDir())
+	s.Update = false
+
+	// recorded on the first run
+	require.Nil(t, s.Match("objects/moonrise", []byte(moonrise)))
+	file, err := s.Path("objects/moonrise")
+	require.Nil(t, err)
+	golden, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	assert.Contains(t, string(golden), `"title": "Moonrise"`)
+
+	// excluded members may differ
+	changed := []byte(`{"data": [{"type": "node--islandora_object", "id": "0e1d2c3b",
+		"attributes": {"title": "Moonrise", "drupal_internal__nid": 14, "created": "2022-01-01T00:00:00+00:00",
+			"field_weight": 3, "field_extent": ["1 photograph", "8 x 10 in."]},
+		"relationships": {"field_genre": {"data": [{"type": "taxonomy_term--genre", "id": "g7", "meta": {"drupal_internal__target_id": 9}}]}}}]}`)
+	assert.Nil(t, s.Match("objects/moonrise", changed))
+	assert.True(t, s.Assert(t, "objects/moonrise", []byte(moonrise)))
+
+	reordered := []byte(`{"data": [{"type": "node--islandora_object", "attributes": {"title": "Moonrise",
+		"field_weight": 3, "field_extent": ["8 x 10 in.", "1 photograph"]}, "relationships": {}}]}`)
+	err = s.Match("objects/moonrise", reordered)
+	assert.True(t, errors.Is(err, ErrMismatch))
+	assert.Contains(t, err.Error(), "UPDATE_SNAPSHOTS=1")
+	rec := &asserttest.Recorder{}
+	assert.False(t, s.Assert(rec, "objects/moonrise", reordered))
+	assert.Contains(t, rec.String(), `"8 x 10 in."`)
+
+	// re-recorded when updating
+	s.Update = true
+	require.Nil(t, s.Match("objects/moonrise", reordered))
+	s.Update = false
+	assert.Nil(t, s.Match("objects/moonrise", reordered))
+
+	// hand-edited golden files are normalized
+	require.Nil(t, ioutil.WriteFile(file, []byte(`{"data": [{"relationships": {}, "type": "node--islandora_object",
+		"attributes": {"title": "Moonrise", "field_weight": 3, "field_extent": ["8 x 10 in.", "1 photograph"]}, "id": "x"}]}`), 0644))
+	assert.Nil(t, s.Match("objects/moonrise", reordered))
+
+	// unordered lists may be reordered
+	s.Unordered = []string{"field_extent"}
+	assert.Nil(t, s.Match("objects/moonrise", []byte(`{"data": [{"type": "node--islandora_object",
+		"attributes": {"title": "Moonrise", "field_weight": 3, "field_extent": ["1 photograph", "8 x 10 in."]}, "relationships": {}}]}`)))
+
+	_, err = s.Path("../moonrise")
+	assert.NotNil(t, err)
+	p, err := s.Path("Test_Match/moonrise over hernandez")
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(s.Dir, "Test_Match", "moonrise_over_hernandez.json"), p)
+}
+
+func Test_New(t *testing.T) {
+	prev, ok := os.LookupEnv(UpdateEnv)
+	defer func() {
+		if ok {
+			os.Setenv(UpdateEnv, prev)
+		} else {
+			os.Unsetenv(UpdateEnv)
+		}
+	}()
+	os.Setenv(UpdateEnv, "1")
+	assert.True(t, New("testdata").Update)
+	os.Setenv(UpdateEnv, "")
+	assert.False(t, New("testdata").Update)
+}
+
+func Test_AssertUrl(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "g1", "attributes": map[string]interface{}{"name": "Maps"}})
+	s := New(t.TempDir())
+	s.Update = false
+
+	u := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "taxonomy_term", DrupalBundle: "genre", Filter: "name", Value: "Maps"}
+	assert.True(t, s.AssertUrl(t, "genre", u))
+	assert.True(t, s.AssertUrl(t, "genre", u))
+	m.Add(jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "g2", "attributes": map[string]interface{}{"name": "Maps"}})
+	rec := &asserttest.Recorder{}
+	assert.False(t, s.AssertUrl(rec, "genre", u))
+	assert.Contains(t, rec.String(), "Not equal")
+	assert.Contains(t, rec.String(), `"Maps"`)
+	rec = &asserttest.Recorder{}
+	assert.False(t, s.AssertUrl(rec, "genre", &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "taxonomy_term"}))
+	assert.Contains(t, rec.String(), "drupal bundle must not be empty")
+}
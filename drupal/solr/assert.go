@@ -0,0 +1,43 @@
+//go:build !notestify
+// +build !notestify
+
+package solr
+
+import (
+	"sort"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the values of each field of the document equal the expected values, without regard to order
+func AssertDocument(t assert.TestingT, doc Document, expected map[string][]string) bool {
+	fields := make([]string, 0, len(expected))
+	for field := range expected {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	ok := true
+	for _, field := range fields {
+		ok = assert.ElementsMatch(t, expected[field], doc.Values(field), "indexed field %s differs", field) && ok
+	}
+	return ok
+}
+
+// Asserts that the values of each field of the document equal the values of the attribute of the node it is mapped
+// to, e.g. `{"tm_X3b_en_title": "title"}`.  The node is a JSON:API resource object.  Formatted text attributes are
+// compared on their value, and multiple values without regard to order.
+func AssertMatchesNode(t assert.TestingT, doc Document, node map[string]interface{}, fields map[string]string) bool {
+	attributes, _ := node["attributes"].(map[string]interface{})
+	sorted := make([]string, 0, len(fields))
+	for field := range fields {
+		sorted = append(sorted, field)
+	}
+	sort.Strings(sorted)
+	ok := true
+	for _, field := range sorted {
+		attribute := fields[field]
+		ok = assert.ElementsMatch(t, flatten(attributes[attribute]), doc.Values(field),
+			"indexed field %s differs from attribute %s of %s", field, attribute, node["id"]) && ok
+	}
+	return ok
+}
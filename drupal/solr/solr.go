@@ -0,0 +1,168 @@
+// Queries the Solr core indexed by Drupal's Search API, so that tests may verify that migrated entities are indexed,
+// and that the indexed values match those of the entity.
+//
+// Indexing is asynchronous, so WaitForIndexed polls the core until a document for the entity appears:
+//
+//	c := solr.NewClient("http://solr:8983/solr/ISLANDORA", "", "")
+//	doc, err := c.WaitForIndexed("Moonrise", time.Minute)
+//	solr.AssertMatchesNode(t, doc, node, map[string]string{"tm_X3b_en_title": "title"})
+package solr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var (
+	ErrNotIndexed = errors.New("solr: no document is indexed")
+	ErrAmbiguous  = errors.New("solr: more than one document is indexed")
+)
+
+// The fields of a Search API document carrying the title and UUID of the indexed entity, if a Client names none
+const (
+	DefaultTitleField = "tm_X3b_en_title"
+	DefaultUuidField  = "ss_uuid"
+)
+
+// The interval between polls of WaitForIndexed
+var PollInterval = 2 * time.Second
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// A document of the Solr index, keyed by field
+type Document map[string]interface{}
+
+// Answers the values of the field as strings; a single-valued field answers a single value
+func (d Document) Values(field string) []string {
+	return flatten(d[field])
+}
+
+// Queries a Solr core
+type Client struct {
+	// The url of the core, e.g. `http://solr:8983/solr/ISLANDORA`
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The field carrying the title of the indexed entity; DefaultTitleField if empty
+	TitleField string
+	// The field carrying the UUID of the indexed entity; DefaultUuidField if empty
+	UuidField string
+}
+
+// Creates a Client for the Solr core at the url
+func NewClient(coreUrl, username, password string) *Client {
+	return &Client{BaseUrl: coreUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the documents matching the Solr query, e.g. `ss_type:islandora_object`, up to the number of rows
+func (c *Client) Query(q string, rows int) ([]Document, error) {
+	params := url.Values{"q": {q}, "rows": {strconv.Itoa(rows)}, "wt": {"json"}}
+	u := strings.TrimSuffix(c.BaseUrl, "/") + "/select?" + params.Encode()
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("solr: %w", err)
+	}
+	if strings.TrimSpace(c.Username) != "" {
+		req.SetBasicAuth(c.Username, c.Password.Reveal())
+	}
+
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("solr: error requesting %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("solr: error reading response body from %s: %w", u, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("solr: %d status encountered when requesting %s", res.StatusCode, u)
+	}
+
+	var answer struct {
+		Response struct {
+			Docs []Document `json:"docs"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &answer); err != nil {
+		return nil, fmt.Errorf("solr: unable to unmarshal the response from %s: %w", u, err)
+	}
+	return answer.Response.Docs, nil
+}
+
+// Answers the single document indexed for the entity with the title or UUID.  An error wrapping ErrNotIndexed or
+// ErrAmbiguous is answered if no document, or more than one document, is indexed.
+func (c *Client) Find(titleOrUuid string) (Document, error) {
+	field := c.TitleField
+	if field == "" {
+		field = DefaultTitleField
+	}
+	if uuidPattern.MatchString(titleOrUuid) {
+		if field = c.UuidField; field == "" {
+			field = DefaultUuidField
+		}
+	}
+	docs, err := c.Query(fmt.Sprintf("%s:%s", field, Phrase(titleOrUuid)), 2)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, fmt.Errorf("%w for '%s'", ErrNotIndexed, titleOrUuid)
+	case 1:
+		return docs[0], nil
+	}
+	return nil, fmt.Errorf("%w for '%s'", ErrAmbiguous, titleOrUuid)
+}
+
+// Polls the core every PollInterval until a document for the entity with the title or UUID is indexed, answering the
+// document.  An error wrapping ErrNotIndexed is answered if no document is indexed within the timeout.
+func (c *Client) WaitForIndexed(titleOrUuid string, timeout time.Duration) (Document, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		doc, err := c.Find(titleOrUuid)
+		if !errors.Is(err, ErrNotIndexed) || time.Now().Add(PollInterval).After(deadline) {
+			return doc, err
+		}
+		time.Sleep(PollInterval)
+	}
+}
+
+// Answers the value quoted as a Solr phrase
+func Phrase(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// Answers the values as strings, answering the value of formatted text
+func flatten(v interface{}) []string {
+	switch v := v.(type) {
+	case nil:
+		return []string{}
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case map[string]interface{}:
+		return flatten(v["value"])
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			values = append(values, flatten(item)...)
+		}
+		return values
+	}
+	return []string{fmt.Sprintf("%v", v)}
+}
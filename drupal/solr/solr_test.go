@@ -0,0 +1,88 @@
+//go:build !notestify
+// +build !notestify
+
+package solr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(indexAfter int32) *httptest.Server {
+	var requests int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/solr/ISLANDORA/select" || r.URL.Query().Get("wt") != "json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		docs := `[]`
+		switch r.URL.Query().Get("q") {
+		case `tm_X3b_en_title:"Moonrise"`, `ss_uuid:"0b4d8c6a-4e61-4fc1-8d2b-7d0d1c6a0b11"`:
+			if atomic.AddInt32(&requests, 1) > indexAfter {
+				docs = `[{"ss_uuid": "0b4d8c6a-4e61-4fc1-8d2b-7d0d1c6a0b11", "tm_X3b_en_title": ["Moonrise"], "sm_genre": ["Photographs", "Maps"], "is_nid": 1}]`
+			}
+		case `tm_X3b_en_title:"Untitled"`:
+			docs = `[{"is_nid": 2}, {"is_nid": 3}]`
+		}
+		_, _ = w.Write([]byte(`{"response": {"numFound": 1, "docs": ` + docs + `}}`))
+	}))
+}
+
+func Test_Find(t *testing.T) {
+	server := newServer(0)
+	defer server.Close()
+	c := NewClient(server.URL+"/solr/ISLANDORA/", "", "")
+
+	doc, err := c.Find("Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1"}, doc.Values("is_nid"))
+	doc, err = c.Find("0b4d8c6a-4e61-4fc1-8d2b-7d0d1c6a0b11")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Moonrise"}, doc.Values("tm_X3b_en_title"))
+
+	_, err = c.Find("Hernandez")
+	assert.ErrorIs(t, err, ErrNotIndexed)
+	_, err = c.Find("Untitled")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+	_, err = NewClient(server.URL+"/solr/OTHER", "", "").Find("Moonrise")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, `"a \"quoted\" \\ title"`, Phrase(`a "quoted" \ title`))
+}
+
+func Test_WaitForIndexed(t *testing.T) {
+	interval := PollInterval
+	PollInterval = 10 * time.Millisecond
+	defer func() { PollInterval = interval }()
+
+	server := newServer(2)
+	defer server.Close()
+	c := NewClient(server.URL+"/solr/ISLANDORA", "", "")
+
+	doc, err := c.WaitForIndexed("Moonrise", time.Second)
+	require.Nil(t, err)
+	assert.True(t, AssertDocument(t, doc, map[string][]string{"sm_genre": {"Maps", "Photographs"}}))
+
+	_, err = c.WaitForIndexed("Hernandez", 50*time.Millisecond)
+	assert.ErrorIs(t, err, ErrNotIndexed)
+}
+
+func Test_AssertMatchesNode(t *testing.T) {
+	doc := Document{"tm_X3b_en_title": []interface{}{"Moonrise"}, "tm_X3b_en_description": []interface{}{"<p>Moonrise</p>"}, "bs_featured": true}
+	node := map[string]interface{}{"id": "n1", "attributes": map[string]interface{}{
+		"title":               "Moonrise",
+		"field_description":   []interface{}{map[string]interface{}{"value": "<p>Moonrise</p>", "format": "basic_html"}},
+		"field_featured_item": false,
+	}}
+	assert.True(t, AssertMatchesNode(t, doc, node, map[string]string{"tm_X3b_en_title": "title", "tm_X3b_en_description": "field_description"}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertMatchesNode(rec, doc, node, map[string]string{"bs_featured": "field_featured_item"}))
+	assert.Contains(t, rec.String(), "indexed field bs_featured differs from attribute field_featured_item of n1")
+}
@@ -0,0 +1,228 @@
+// Provides lookup, creation, and traversal of taxonomy terms keyed by vocabulary, so that migration tests and fixture
+// setup may manage the terms of every vocabulary represented by the Expected models (e.g. genre, subject, and
+// geo_location).
+package taxonomy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The vocabularies represented by the Expected models
+var Vocabularies = []string{
+	model.AccessRights,
+	model.CopyrightAndUse,
+	model.CorporateBody,
+	model.Family,
+	model.Genre,
+	model.GeoLocation,
+	model.IslandoraAccess,
+	model.Language,
+	model.Person,
+	model.ResourceTypes,
+	model.Subject,
+}
+
+// The id Drupal uses to reference the parent of terms at the root of a vocabulary
+const virtualParent = "virtual"
+
+// A taxonomy term
+type Term struct {
+	Id         string
+	Vocabulary string
+	Name       string
+	Weight     int
+	// The ids of the parents of the term; empty if the term is at the root of its vocabulary
+	Parents []string
+	// Every attribute of the term, including name and weight, as answered by the JSON API
+	Attributes map[string]interface{}
+}
+
+// A term and its children, ordered by weight and then by name
+type Node struct {
+	*Term
+	Children []*Node
+}
+
+// Manages the taxonomy terms of a Drupal site
+type Client struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the Drupal site at the base url.  If the username is not empty, requests are authenticated
+// using HTTP Basic Auth; creating terms requires a user permitted to do so.
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the term with the supplied name in the vocabulary.  An error wrapping jsonapi.ErrTermNotFound or
+// jsonapi.ErrAmbiguousTerm is answered if zero or more than one term carries the name.
+func (c *Client) FindTermByName(vocabulary, name string) (*Term, error) {
+	res := &jsonapi.JsonApiResponse{}
+	if err := c.url(vocabulary, "name", name).Fetch(res); err != nil {
+		return nil, err
+	}
+
+	switch len(res.Data) {
+	case 0:
+		return nil, fmt.Errorf("%w: '%s' in vocabulary '%s'", jsonapi.ErrTermNotFound, name, vocabulary)
+	case 1:
+		return newTerm(res.Data[0]), nil
+	}
+	return nil, fmt.Errorf("%w: '%s' in vocabulary '%s' matches %d terms", jsonapi.ErrAmbiguousTerm, name, vocabulary, len(res.Data))
+}
+
+// Answers the term with the supplied name in the vocabulary, creating it if it does not exist.  The fields are the
+// attributes of a created term in addition to its name (e.g. `description` or `field_authority_link`); they are not
+// applied to an existing term.  True is answered if the term was created.
+func (c *Client) EnsureTerm(vocabulary, name string, fields map[string]interface{}) (*Term, bool, error) {
+	term, err := c.FindTermByName(vocabulary, name)
+	if err == nil || !errors.Is(err, jsonapi.ErrTermNotFound) {
+		return term, false, err
+	}
+
+	attributes := map[string]interface{}{}
+	for k, v := range fields {
+		attributes[k] = v
+	}
+	attributes["name"] = name
+	doc := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       model.TaxonomyTerm + "--" + vocabulary,
+			"attributes": attributes,
+		},
+	}
+
+	u, err := c.url(vocabulary, "", "").Url()
+	if err != nil {
+		return nil, false, err
+	}
+	body, err := jsonapi.CreateResource(u, c.Username, c.Password.Reveal(), doc)
+	if err != nil {
+		return nil, false, err
+	}
+	res := &jsonapi.JsonApiResponse{}
+	if err := json.Unmarshal(body, res); err != nil || len(res.Data) != 1 {
+		return nil, false, fmt.Errorf("unable to read the term created in vocabulary '%s': %v", vocabulary, err)
+	}
+	return newTerm(res.Data[0]), true, nil
+}
+
+// Answers every term of the vocabulary, in the order answered by Drupal
+func (c *Client) Terms(vocabulary string) ([]*Term, error) {
+	var terms []*Term
+	err := c.url(vocabulary, "", "").FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			terms = append(terms, newTerm(d))
+		}
+		return nil
+	})
+	return terms, err
+}
+
+// Answers the terms at the root of the vocabulary, each carrying its descendants.  Siblings are ordered by weight,
+// and then by name.  A term with more than one parent appears beneath each of them.
+func (c *Client) TermHierarchy(vocabulary string) ([]*Node, error) {
+	terms, err := c.Terms(vocabulary)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*Node{}
+	for _, term := range terms {
+		nodes[term.Id] = &Node{Term: term}
+	}
+	var roots []*Node
+	for _, term := range terms {
+		node := nodes[term.Id]
+		attached := false
+		for _, parent := range term.Parents {
+			if p, ok := nodes[parent]; ok {
+				p.Children = append(p.Children, node)
+				attached = true
+			}
+		}
+		// terms whose parents are not visible (e.g. unpublished) are treated as roots
+		if !attached {
+			roots = append(roots, node)
+		}
+	}
+
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+	sortNodes(roots)
+	return roots, nil
+}
+
+// Answers the names of the node and its descendants, indented by depth, e.g. for logging a hierarchy
+func (n *Node) String() string {
+	var b strings.Builder
+	n.write(&b, 0)
+	return b.String()
+}
+
+func (n *Node) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(n.Name)
+	b.WriteString("\n")
+	for _, child := range n.Children {
+		child.write(b, depth+1)
+	}
+}
+
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Weight != nodes[j].Weight {
+			return nodes[i].Weight < nodes[j].Weight
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func (c *Client) url(vocabulary, filter, value string) *jsonapi.JsonApiUrl {
+	return &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: model.TaxonomyTerm,
+		DrupalBundle: vocabulary,
+		Filter:       filter,
+		Value:        value,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+}
+
+// Creates a Term from a JSON API resource object
+func newTerm(data map[string]interface{}) *Term {
+	term := &Term{}
+	term.Id, _ = data["id"].(string)
+	if t, ok := data["type"].(string); ok {
+		term.Vocabulary = jsonapi.DrupalType(t).Bundle()
+	}
+	term.Attributes, _ = data["attributes"].(map[string]interface{})
+	term.Name, _ = term.Attributes["name"].(string)
+	if weight, ok := term.Attributes["weight"].(float64); ok {
+		term.Weight = int(weight)
+	}
+
+	relationships, _ := data["relationships"].(map[string]interface{})
+	parent, _ := relationships["parent"].(map[string]interface{})
+	refs, _ := parent["data"].([]interface{})
+	for _, ref := range refs {
+		if ref, ok := ref.(map[string]interface{}); ok && ref["id"] != virtualParent {
+			if id, ok := ref["id"].(string); ok {
+				term.Parents = append(term.Parents, id)
+			}
+		}
+	}
+	return term
+}
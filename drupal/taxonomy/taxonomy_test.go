@@ -0,0 +1,83 @@
+package taxonomy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func term(id, name string, weight int, parents ...string) jsonapitest.Resource {
+	var refs []interface{}
+	for _, p := range parents {
+		refs = append(refs, map[string]interface{}{"type": "taxonomy_term--genre", "id": p})
+	}
+	if len(refs) == 0 {
+		refs = append(refs, map[string]interface{}{"type": "taxonomy_term--genre", "id": "virtual"})
+	}
+	return jsonapitest.Resource{
+		"type":          "taxonomy_term--genre",
+		"id":            id,
+		"attributes":    map[string]interface{}{"name": name, "weight": weight},
+		"relationships": map[string]interface{}{"parent": map[string]interface{}{"data": refs}},
+	}
+}
+
+func Test_FindAndEnsureTerm(t *testing.T) {
+	server := jsonapitest.NewMockServer()
+	defer server.Close()
+	server.Add(term("g1", "Maps", 0), term("g2", "Photographs", 0), term("g3", "Photographs", 1))
+
+	c := NewClient(server.URL, "admin", "password")
+	maps, err := c.FindTermByName(model.Genre, "Maps")
+	require.Nil(t, err)
+	assert.Equal(t, "g1", maps.Id)
+	assert.Equal(t, model.Genre, maps.Vocabulary)
+	assert.Empty(t, maps.Parents)
+
+	_, err = c.FindTermByName(model.Genre, "Photographs")
+	assert.ErrorIs(t, err, jsonapi.ErrAmbiguousTerm)
+	_, err = c.FindTermByName(model.Genre, "Posters")
+	assert.ErrorIs(t, err, jsonapi.ErrTermNotFound)
+
+	existing, created, err := c.EnsureTerm(model.Genre, "Maps", nil)
+	require.Nil(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "g1", existing.Id)
+
+	posters, created, err := c.EnsureTerm(model.Genre, "Posters", map[string]interface{}{"description": map[string]interface{}{"value": "Posters"}})
+	require.Nil(t, err)
+	assert.True(t, created)
+	assert.NotEmpty(t, posters.Id)
+
+	found, err := c.FindTermByName(model.Genre, "Posters")
+	require.Nil(t, err)
+	assert.Equal(t, posters.Id, found.Id)
+
+	requests := server.Requests()
+	assert.Equal(t, http.MethodPost, requests[len(requests)-2].Method)
+	assert.Equal(t, "admin", requests[len(requests)-2].Username)
+}
+
+func Test_TermHierarchy(t *testing.T) {
+	server := jsonapitest.NewMockServer()
+	defer server.Close()
+	server.PageSize = 2
+	server.Add(
+		term("g1", "Cartographic", 0),
+		term("g2", "Maps", 1, "g1"),
+		term("g3", "Atlases", 1, "g1"),
+		term("g4", "Globes", 0, "g1"),
+		term("g5", "Art", 0),
+		term("g6", "Orphan", 0, "missing"),
+	)
+
+	roots, err := NewClient(server.URL, "", "").TermHierarchy(model.Genre)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(roots))
+	assert.Equal(t, "Art\nCartographic\n  Globes\n  Atlases\n  Maps\nOrphan\n", roots[0].String()+roots[1].String()+roots[2].String())
+}
@@ -0,0 +1,37 @@
+//go:build !notestify
+// +build !notestify
+
+package triplestore
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that the subject carries each expected value of each predicate (see Has)
+func (c *Client) AssertTriples(t assert.TestingT, subject string, expected map[string][]string) bool {
+	missing, err := c.Missing(subject, expected)
+	if !assert.Nil(t, err, "error querying the triples of %s: %s", subject, err) {
+		return false
+	}
+	ok := true
+	for _, p := range sortedKeys(missing) {
+		ok = assert.Fail(t, "missing triples", "%s %s lacks %q", subject, p, missing[p]) && ok
+	}
+	return ok
+}
+
+// Asserts that the triples indexed for the repository object carry its title, the IRI of its model, and the IRIs of
+// the collections it is a member of.  Empty expectations are not asserted.
+func (c *Client) AssertObject(t assert.TestingT, subject, title, model string, memberOf ...string) bool {
+	expected := map[string][]string{}
+	if title != "" {
+		expected[TitlePredicate] = []string{title}
+	}
+	if model != "" {
+		expected[ModelPredicate] = []string{model}
+	}
+	if len(memberOf) > 0 {
+		expected[MemberOfPredicate] = memberOf
+	}
+	return c.AssertTriples(t, subject, expected)
+}
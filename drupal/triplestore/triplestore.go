@@ -0,0 +1,211 @@
+// Runs SPARQL queries against the triplestore (Blazegraph) into which Islandora indexes Drupal entities, so that tests
+// may verify the triples indexed for migrated objects.
+//
+// Indexing is asynchronous, so WaitFor polls the triplestore until the expected triples are present:
+//
+//	c := triplestore.NewClient("http://blazegraph:8080/bigdata/namespace/islandora/sparql", "", "")
+//	err := c.WaitFor("http://drupal/node/1?_format=jsonld", map[string][]string{"dcterms:title": {"Moonrise"}}, time.Minute)
+package triplestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/fedora"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var ErrTimeout = errors.New("triplestore: timed out waiting for triples")
+
+// The interval between polls of WaitFor
+var PollInterval = 2 * time.Second
+
+// The predicates of the triples indexed for a repository object, following the RDF mapping of Islandora's
+// islandora_object bundle
+var (
+	TitlePredicate    = "http://purl.org/dc/terms/title"
+	ModelPredicate    = "https://schema.org/additionalType"
+	MemberOfPredicate = "http://pcdm.org/models#memberOf"
+)
+
+// An RDF term bound to a variable of a SELECT query
+type Term struct {
+	// `uri`, `literal`, or `bnode`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	// The language of a literal, e.g. `en`
+	Lang string `json:"xml:lang,omitempty"`
+	// The datatype IRI of a typed literal
+	Datatype string `json:"datatype,omitempty"`
+}
+
+// The terms bound by a solution of a SELECT query, keyed by variable
+type Binding map[string]Term
+
+// The results of a SELECT query
+type Results struct {
+	Vars     []string
+	Bindings []Binding
+}
+
+// Answers the values bound to the variable by each solution, omitting solutions that leave it unbound
+func (r *Results) Values(variable string) []string {
+	values := []string{}
+	for _, b := range r.Bindings {
+		if term, ok := b[variable]; ok {
+			values = append(values, term.Value)
+		}
+	}
+	return values
+}
+
+// Queries a SPARQL endpoint
+type Client struct {
+	// The url of the SPARQL endpoint, e.g. `http://blazegraph:8080/bigdata/namespace/islandora/sparql`
+	Endpoint string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the SPARQL endpoint
+func NewClient(endpoint, username, password string) *Client {
+	return &Client{Endpoint: endpoint, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the results of the SELECT query
+func (c *Client) Select(query string) (*Results, error) {
+	var answer struct {
+		Head struct {
+			Vars []string `json:"vars"`
+		} `json:"head"`
+		Results struct {
+			Bindings []Binding `json:"bindings"`
+		} `json:"results"`
+	}
+	if err := c.query(query, &answer); err != nil {
+		return nil, err
+	}
+	return &Results{Vars: answer.Head.Vars, Bindings: answer.Results.Bindings}, nil
+}
+
+// Answers the result of the ASK query
+func (c *Client) Ask(query string) (bool, error) {
+	var answer struct {
+		Boolean *bool `json:"boolean"`
+	}
+	if err := c.query(query, &answer); err != nil {
+		return false, err
+	}
+	if answer.Boolean == nil {
+		return false, fmt.Errorf("triplestore: the response to an ASK query carries no boolean")
+	}
+	return *answer.Boolean, nil
+}
+
+// Answers the values of the predicate of the subject, as IRIs or lexical forms.  The predicate may be an IRI or a
+// prefixed name of fedora.Prefixes.
+func (c *Client) Values(subject, predicate string) ([]string, error) {
+	results, err := c.Select(fmt.Sprintf("SELECT ?o WHERE { %s %s ?o }", Iri(subject), Iri(fedora.Expand(predicate))))
+	if err != nil {
+		return nil, err
+	}
+	return results.Values("o"), nil
+}
+
+// Answers true if the subject carries the predicate with a value whose IRI or lexical form is the object, without
+// regard to the language or datatype of a literal
+func (c *Client) Has(subject, predicate, object string) (bool, error) {
+	return c.Ask(fmt.Sprintf("ASK { %s %s ?o FILTER(str(?o) = %s) }", Iri(subject), Iri(fedora.Expand(predicate)), Literal(fedora.Expand(object))))
+}
+
+// Answers the expected values, keyed by predicate, that the subject does not carry (see Has)
+func (c *Client) Missing(subject string, expected map[string][]string) (map[string][]string, error) {
+	missing := map[string][]string{}
+	for _, p := range sortedKeys(expected) {
+		for _, o := range expected[p] {
+			ok, err := c.Has(subject, p, o)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				missing[p] = append(missing[p], o)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// Polls the triplestore every PollInterval until the subject carries each expected value of each predicate.  An error
+// wrapping ErrTimeout, listing the missing values, is answered if they are not all present within the timeout.
+func (c *Client) WaitFor(subject string, expected map[string][]string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		missing, err := c.Missing(subject, expected)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if time.Now().Add(PollInterval).After(deadline) {
+			return fmt.Errorf("%w of %s after %s: missing %v", ErrTimeout, subject, timeout, missing)
+		}
+		time.Sleep(PollInterval)
+	}
+}
+
+func (c *Client) query(query string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint, strings.NewReader(url.Values{"query": {query}}.Encode()))
+	if err != nil {
+		return fmt.Errorf("triplestore: %w", err)
+	}
+	if strings.TrimSpace(c.Username) != "" {
+		req.SetBasicAuth(c.Username, c.Password.Reveal())
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/sparql-results+json")
+
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("triplestore: error querying %s: %w", c.Endpoint, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("triplestore: error reading response body from %s: %w", c.Endpoint, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("triplestore: %d status encountered when querying %s: %s", res.StatusCode, c.Endpoint, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("triplestore: unable to unmarshal the results from %s: %w", c.Endpoint, err)
+	}
+	return nil
+}
+
+// Answers the IRI as a SPARQL IRI reference, e.g. `<http://purl.org/dc/terms/title>`
+func Iri(iri string) string {
+	return "<" + strings.NewReplacer("<", "%3C", ">", "%3E", " ", "%20", `"`, "%22").Replace(iri) + ">"
+}
+
+// Answers the value as a SPARQL string literal, e.g. `"Moonrise"`
+func Literal(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`).Replace(value) + `"`
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
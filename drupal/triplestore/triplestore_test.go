@@ -0,0 +1,133 @@
+//go:build !notestify
+// +build !notestify
+
+package triplestore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const subject = "http://drupal/node/1?_format=jsonld"
+
+// A SPARQL endpoint answering the ASK and SELECT queries issued by Client from a set of triples
+type fakeStore struct {
+	mu      sync.Mutex
+	triples [][3]Term
+}
+
+var (
+	askPattern    = regexp.MustCompile(`^ASK \{ <([^>]*)> <([^>]*)> \?o FILTER\(str\(\?o\) = ("(?:[^"\\]|\\.)*")\) \}$`)
+	selectPattern = regexp.MustCompile(`^SELECT \?o WHERE \{ <([^>]*)> <([^>]*)> \?o \}$`)
+)
+
+func (s *fakeStore) add(p string, o Term) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.triples = append(s.triples, [3]Term{{Type: "uri", Value: subject}, {Type: "uri", Value: p}, o})
+}
+
+func (s *fakeStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method != http.MethodPost || r.Header.Get("Accept") != "application/sparql-results+json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	q := r.PostFormValue("query")
+	var answer interface{}
+	if m := askPattern.FindStringSubmatch(q); m != nil {
+		object, _ := strconv.Unquote(m[3])
+		found := false
+		for _, t := range s.triples {
+			found = found || (t[0].Value == m[1] && t[1].Value == m[2] && t[2].Value == object)
+		}
+		answer = map[string]interface{}{"head": map[string]interface{}{}, "boolean": found}
+	} else if m := selectPattern.FindStringSubmatch(q); m != nil {
+		bindings := []Binding{}
+		for _, t := range s.triples {
+			if t[0].Value == m[1] && t[1].Value == m[2] {
+				bindings = append(bindings, Binding{"o": t[2]})
+			}
+		}
+		answer = map[string]interface{}{"head": map[string]interface{}{"vars": []string{"o"}}, "results": map[string]interface{}{"bindings": bindings}}
+	} else {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("MALFORMED QUERY: " + q))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(answer)
+}
+
+func Test_Client(t *testing.T) {
+	store := &fakeStore{}
+	store.add(TitlePredicate, Term{Type: "literal", Value: "Moonrise", Lang: "en"})
+	store.add(MemberOfPredicate, Term{Type: "uri", Value: "http://drupal/node/2?_format=jsonld"})
+	store.add(ModelPredicate, Term{Type: "uri", Value: "http://purl.org/coar/resource_type/c_c513"})
+	store.add(MemberOfPredicate, Term{Type: "uri", Value: "http://drupal/node/3?_format=jsonld"})
+	server := httptest.NewServer(store)
+	defer server.Close()
+	c := NewClient(server.URL, "", "")
+
+	values, err := c.Values(subject, "pcdm:memberOf")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"http://drupal/node/2?_format=jsonld", "http://drupal/node/3?_format=jsonld"}, values)
+
+	results, err := c.Select("SELECT ?o WHERE { <" + subject + "> <" + TitlePredicate + "> ?o }")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"o"}, results.Vars)
+	assert.Equal(t, Term{Type: "literal", Value: "Moonrise", Lang: "en"}, results.Bindings[0]["o"])
+
+	ok, err := c.Has(subject, "dcterms:title", "Moonrise")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = c.Has(subject, "dcterms:title", `Moonrise "over" Hernandez`)
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, c.AssertObject(t, subject, "Moonrise", "http://purl.org/coar/resource_type/c_c513", "http://drupal/node/2?_format=jsonld"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, c.AssertObject(rec, subject, "Moonset", ""))
+	assert.Contains(t, rec.String(), `http://purl.org/dc/terms/title lacks ["Moonset"]`)
+
+	_, err = c.Select("DESCRIBE <" + subject + ">")
+	assert.Contains(t, err.Error(), "MALFORMED QUERY")
+	_, err = c.Ask("SELECT ?o WHERE { <" + subject + "> <" + TitlePredicate + "> ?o }")
+	assert.NotNil(t, err)
+}
+
+func Test_WaitFor(t *testing.T) {
+	interval := PollInterval
+	PollInterval = 10 * time.Millisecond
+	defer func() { PollInterval = interval }()
+
+	store := &fakeStore{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	c := NewClient(server.URL, "", "")
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		store.add(TitlePredicate, Term{Type: "literal", Value: "Moonrise"})
+	}()
+	assert.Nil(t, c.WaitFor(subject, map[string][]string{"dcterms:title": {"Moonrise"}}, 5*time.Second))
+
+	err := c.WaitFor(subject, map[string][]string{"dcterms:title": {"Moonset"}}, 50*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Contains(t, err.Error(), "Moonset")
+}
+
+func Test_Escaping(t *testing.T) {
+	assert.Equal(t, `"a \"quoted\" \\ title\n"`, Literal("a \"quoted\" \\ title\n"))
+	assert.Equal(t, `<http://example.org/a%20b%3E>`, Iri("http://example.org/a b>"))
+}
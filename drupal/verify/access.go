@@ -0,0 +1,189 @@
+package verify
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// A user as whom access is verified, e.g. an anonymous, an authenticated, or an administrative user
+type Role struct {
+	// Names the role in violations, e.g. `anonymous`
+	Name string
+	// The user authenticated as using HTTP Basic Auth; the anonymous user if empty
+	Username string
+	Password env.Secret
+}
+
+// The anonymous user
+var Anonymous = Role{Name: "anonymous"}
+
+// The access a role is expected to have to a node and to the files of its media
+type AccessExpectation struct {
+	Role Role
+	// The status expected of the JSON API resource of the node: 200 if the role may view it, 403 if not
+	Node int
+	// The status expected of the files of the media of the node, e.g. 403 for the files of an embargoed object; not
+	// checked if zero
+	Media int
+}
+
+// Expects the role to be able to view the node and the files of its media
+func Allowed(role Role) AccessExpectation {
+	return AccessExpectation{Role: role, Node: http.StatusOK, Media: http.StatusOK}
+}
+
+// Expects the role to be forbidden from viewing the node and the files of its media
+func Forbidden(role Role) AccessExpectation {
+	return AccessExpectation{Role: role, Node: http.StatusForbidden, Media: http.StatusForbidden}
+}
+
+// A request answered with a status other than the status expected for the role
+type AccessViolation struct {
+	Role     string
+	Url      string
+	Expected int
+	Actual   int
+}
+
+// Answers the violation as e.g. `anonymous: https://islandora-idc.traefik.me/system/files/moonrise.jpg answered 200,
+// not 403`
+func (v AccessViolation) String() string {
+	return fmt.Sprintf("%s: %s answered %d, not %d", v.Role, v.Url, v.Actual, v.Expected)
+}
+
+// Verifies that access terms and embargoes hide nodes, and the files of their media, from the roles that ought not
+// view them, by requesting the same node and files as each role.  The media of the node are discovered as the user of
+// the AccessChecker, who ought to be able to view them all, e.g. an administrator.
+type AccessChecker struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates an AccessChecker for the Drupal site at the base url, discovering media as the user
+func NewAccessChecker(baseUrl, username, password string) *AccessChecker {
+	return &AccessChecker{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the urls of the files of the media of the node with the UUID, sorted
+func (c *AccessChecker) MediaUrls(uuid string) ([]string, error) {
+	var bundles []string
+	for bundle := range mediaFileFields {
+		bundles = append(bundles, bundle)
+	}
+	sort.Strings(bundles)
+
+	seen := map[string]bool{}
+	var urls []string
+	for _, bundle := range bundles {
+		field := mediaFileFields[bundle]
+		u := &jsonapi.JsonApiUrl{
+			BaseUrl:      c.BaseUrl,
+			DrupalEntity: model.Media,
+			DrupalBundle: bundle,
+			Filter:       "field_media_of.id",
+			Value:        uuid,
+			Username:     c.Username,
+			Password:     c.Password,
+		}
+		err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+			for _, media := range page.Data {
+				file := page.Related(nested(media, "relationships", field, "data"))
+				if fileUrl := str(nested(file, "attributes", "uri"), "url"); fileUrl != "" && !seen[fileUrl] {
+					seen[fileUrl] = true
+					urls = append(urls, c.absolute(fileUrl))
+				}
+			}
+			return nil
+		}, field)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve the %s media of %s: %w", bundle, uuid, err)
+		}
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
+
+// Requests the node of the Drupal type (e.g. `node--islandora_object`) with the UUID, and the files of its media (see
+// MediaUrls), as the role of each expectation, answering the requests whose status differs from the status expected,
+// ordered as the expectations.  An error is answered if the type is invalid, the media cannot be discovered, or a
+// request cannot be executed.
+func (c *AccessChecker) Check(drupalType, uuid string, expectations ...AccessExpectation) ([]AccessViolation, error) {
+	t, err := jsonapi.ParseDrupalType(drupalType)
+	if err != nil {
+		return nil, err
+	}
+	nodeUrl, err := jsonapi.ResourceUrl(c.BaseUrl, t, uuid).Url()
+	if err != nil {
+		return nil, err
+	}
+	var mediaUrls []string
+	for _, e := range expectations {
+		if e.Media != 0 {
+			if mediaUrls, err = c.MediaUrls(uuid); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	var violations []AccessViolation
+	check := func(u string, role Role, expected int) error {
+		actual, err := statusAs(u, role)
+		if err == nil && actual != expected {
+			violations = append(violations, AccessViolation{Role: role.Name, Url: u, Expected: expected, Actual: actual})
+		}
+		return err
+	}
+	for _, e := range expectations {
+		if err := check(nodeUrl, e.Role, e.Node); err != nil {
+			return nil, err
+		}
+		for _, u := range mediaUrls {
+			if e.Media == 0 {
+				break
+			}
+			if err := check(u, e.Role, e.Media); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return violations, nil
+}
+
+// Answers the url of a file, resolved against the base url if it is a path, e.g. `/system/files/moonrise.jpg`
+func (c *AccessChecker) absolute(fileUrl string) string {
+	u, err := url.Parse(fileUrl)
+	if err != nil || u.IsAbs() {
+		return fileUrl
+	}
+	return strings.TrimSuffix(c.BaseUrl, "/") + "/" + strings.TrimPrefix(fileUrl, "/")
+}
+
+// Answers the status of a GET of the url as the role.  Redirects (e.g. to the login page) are followed.  The requests
+// of a role without a username carry no credentials, even if the jsonapi package is configured to authenticate every
+// request (see jsonapi.WithoutAuth).
+func statusAs(u string, role Role) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	if role.Username != "" {
+		req.SetBasicAuth(role.Username, role.Password.Reveal())
+	} else {
+		req = req.WithContext(jsonapi.WithoutAuth(req.Context()))
+	}
+	res, err := jsonapi.HTTPClient().Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error requesting %s as %s: %w", u, role.Name, err)
+	}
+	res.Body.Close()
+	return res.StatusCode, nil
+}
@@ -0,0 +1,120 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const restrictedId = "329c57a2-97f2-4350-8b54-439237c68311"
+
+// Answers a site whose restricted node may be viewed by `reader` and `admin`, but whose private file may be viewed only
+// by `admin`
+func newRestrictedSite() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ := r.BasicAuth()
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object/"+restrictedId:
+			if username == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"data": {"type": "node--islandora_object", "id": "` + restrictedId + `"}}`))
+		case r.URL.Path == "/jsonapi/media/image":
+			w.Write([]byte(`{"data": [
+				{"type": "media--image", "id": "m1", "relationships": {"field_media_image": {"data": {"type": "file--file", "id": "f1"}}}},
+				{"type": "media--image", "id": "m2", "relationships": {"field_media_image": {"data": {"type": "file--file", "id": "f2"}}}}],
+				"included": [
+				{"type": "file--file", "id": "f1", "attributes": {"uri": {"value": "private://moonrise.jpg", "url": "/system/files/moonrise.jpg"}}},
+				{"type": "file--file", "id": "f2", "attributes": {"uri": {"value": "public://moonrise.tn.jpg", "url": "` + "http://" + r.Host + `/sites/default/files/moonrise.tn.jpg"}}}]}`))
+		case strings.HasPrefix(r.URL.Path, "/jsonapi/media/"):
+			w.Write([]byte(`{"data": []}`))
+		case r.URL.Path == "/system/files/moonrise.jpg" && username != "admin":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/system/files/moonrise.jpg" || r.URL.Path == "/sites/default/files/moonrise.tn.jpg":
+			w.Write([]byte("jpeg"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func Test_AccessChecker(t *testing.T) {
+	server := newRestrictedSite()
+	defer server.Close()
+	c := NewAccessChecker(server.URL, "admin", "password")
+	admin := Role{Name: "admin", Username: "admin", Password: "password"}
+	reader := Role{Name: "reader", Username: "reader", Password: "password"}
+
+	urls, err := c.MediaUrls(restrictedId)
+	require.Nil(t, err)
+	assert.Equal(t, []string{server.URL + "/sites/default/files/moonrise.tn.jpg", server.URL + "/system/files/moonrise.jpg"}, urls)
+
+	violations, err := c.Check("node--islandora_object", restrictedId, Allowed(admin),
+		AccessExpectation{Role: reader, Node: http.StatusOK}, AccessExpectation{Role: Anonymous, Node: http.StatusForbidden})
+	require.Nil(t, err)
+	assert.Empty(t, violations)
+
+	// the public thumbnail is visible to every role, and the private file to admin alone
+	violations, err = c.Check("node--islandora_object", restrictedId, Forbidden(Anonymous), Allowed(reader))
+	require.Nil(t, err)
+	var described []string
+	for _, v := range violations {
+		described = append(described, v.String())
+	}
+	assert.Equal(t, []string{
+		"anonymous: " + server.URL + "/sites/default/files/moonrise.tn.jpg answered 200, not 403",
+		"reader: " + server.URL + "/system/files/moonrise.jpg answered 403, not 200",
+	}, described)
+
+	assert.True(t, c.AssertAccess(t, "node--islandora_object", restrictedId, Allowed(admin)))
+	rec := &asserttest.Recorder{}
+	assert.False(t, c.AssertAccess(rec, "node--islandora_object", restrictedId, Allowed(Anonymous)))
+	assert.Contains(t, rec.String(), "anonymous: ")
+	assert.Contains(t, rec.String(), "answered 403, not 200")
+
+	_, err = c.Check("node--", restrictedId, Allowed(admin))
+	assert.NotNil(t, err)
+	server.Close()
+	_, err = c.Check("node--islandora_object", restrictedId, Forbidden(Anonymous))
+	assert.NotNil(t, err)
+}
+
+func Test_AccessCheckerAnonymousWithAuth(t *testing.T) {
+	var mu sync.Mutex
+	authorizations := map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorizations[r.URL.Path] = append(authorizations[r.URL.Path], r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data": {"type": "node--islandora_object", "id": "` + restrictedId + `"}}`))
+	}))
+	defer server.Close()
+	require.Nil(t, jsonapi.Configure(jsonapi.ClientConfig{Auth: jsonapi.BearerToken("eyJ0eXAi"), AuthBaseUrl: server.URL}))
+	defer jsonapi.SetHTTPClient(nil)
+
+	c := NewAccessChecker(server.URL, "", "")
+	violations, err := c.Check("node--islandora_object", restrictedId, AccessExpectation{Role: Anonymous, Node: http.StatusForbidden})
+	require.Nil(t, err)
+	assert.Empty(t, violations)
+	assert.Equal(t, []string{""}, authorizations["/jsonapi/node/islandora_object/"+restrictedId])
+
+	// other requests are still authenticated by the configured provider
+	_, _, err = jsonapi.FetchResource(server.URL+"/jsonapi/node/islandora_object/"+restrictedId, "", "")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"", "Bearer eyJ0eXAi"}, authorizations["/jsonapi/node/islandora_object/"+restrictedId])
+}
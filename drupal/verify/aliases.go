@@ -0,0 +1,186 @@
+package verify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The Drupal type of the path alias entities of Drupal 8.8 and later, whose aliases include those of every entity
+const PathAliasType = "path_alias--path_alias"
+
+// An entity, or a path alias entity, claiming a url alias
+type AliasClaim struct {
+	// The type of the claiming entity, e.g. `node--islandora_object` or PathAliasType
+	Type string
+	Id   string
+	// The title or name of the claiming entity, if it has one
+	Label string
+	// The system path aliased by the claim, e.g. `/node/12`, if it is known
+	Path string
+	// The alias as claimed, e.g. `/moonrise-over-hernandez`
+	Alias string
+}
+
+// Answers the claim as e.g. `node--islandora_object "Moonrise" (n1)` or `path_alias--path_alias /node/12 (a1)`
+func (c AliasClaim) String() string {
+	switch {
+	case c.Label != "":
+		return fmt.Sprintf("%s %q (%s)", c.Type, c.Label, c.Id)
+	case c.Path != "":
+		return fmt.Sprintf("%s %s (%s)", c.Type, c.Path, c.Id)
+	}
+	return fmt.Sprintf("%s (%s)", c.Type, c.Id)
+}
+
+// A url alias of a language claimed more than once.  Drupal answers only one of the claimants at the alias, so the
+// others are silently unreachable by it.
+type AliasCollision struct {
+	// The alias, normalized: lower-cased and without a trailing slash, as Drupal matches aliases
+	Alias    string
+	Langcode string
+	// The claims of the alias, in the order the entities were retrieved
+	Claims []AliasClaim
+}
+
+// Answers true if each claim aliases the same system path, i.e. the alias was duplicated (e.g. by a migration run
+// twice) rather than claimed by different entities
+func (c AliasCollision) Duplicate() bool {
+	for _, claim := range c.Claims {
+		if claim.Path == "" || claim.Path != c.Claims[0].Path {
+			return false
+		}
+	}
+	return true
+}
+
+// Answers the collision as e.g. `alias /moonrise (en) is claimed by node--islandora_object "Moonrise" (n1),
+// node--islandora_object "Moonrise" (n2)`
+func (c AliasCollision) String() string {
+	claims := make([]string, 0, len(c.Claims))
+	for _, claim := range c.Claims {
+		claims = append(claims, claim.String())
+	}
+	kind := "claimed"
+	if c.Duplicate() {
+		kind = "duplicated"
+	}
+	return fmt.Sprintf("alias %s (%s) is %s by %s", c.Alias, c.Langcode, kind, strings.Join(claims, ", "))
+}
+
+// Audits the url aliases of a Drupal site for collisions, e.g. two migrated nodes claiming the same pathauto alias,
+// which leaves all but one of them unreachable by the alias.
+type AliasAuditor struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates an AliasAuditor for the Drupal site at the base url
+func NewAliasAuditor(baseUrl, username, password string) *AliasAuditor {
+	return &AliasAuditor{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the aliases claimed more than once among the entities of the Drupal types (e.g. `node--islandora_object`
+// and `node--collection_object`), ordered by alias and language, their claims ordered by type and id.  The alias of an entity is read from its `path`
+// attribute; the path alias entities themselves may be audited by supplying PathAliasType, which also detects
+// duplicated aliases of the same entity.  An error is answered if a type is invalid or its entities cannot be
+// retrieved.
+func (a *AliasAuditor) Check(drupalTypes ...string) ([]AliasCollision, error) {
+	claims := map[[2]string][]AliasClaim{}
+	for _, s := range drupalTypes {
+		t, err := jsonapi.ParseDrupalType(s)
+		if err != nil {
+			return nil, err
+		}
+		u := &jsonapi.JsonApiUrl{
+			BaseUrl:      a.BaseUrl,
+			DrupalEntity: t.Entity(),
+			DrupalBundle: t.Bundle(),
+			Username:     a.Username,
+			Password:     a.Password,
+		}
+		err = u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+			for _, d := range page.Data {
+				if claim, langcode, ok := aliasClaimOf(d); ok {
+					key := [2]string{normalizeAlias(claim.Alias), langcode}
+					claims[key] = append(claims[key], claim)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var collisions []AliasCollision
+	for key, c := range claims {
+		if len(c) > 1 {
+			sort.SliceStable(c, func(i, j int) bool {
+				if c[i].Type != c[j].Type {
+					return c[i].Type < c[j].Type
+				}
+				return c[i].Id < c[j].Id
+			})
+			collisions = append(collisions, AliasCollision{Alias: key[0], Langcode: key[1], Claims: c})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		if collisions[i].Alias != collisions[j].Alias {
+			return collisions[i].Alias < collisions[j].Alias
+		}
+		return collisions[i].Langcode < collisions[j].Langcode
+	})
+	return collisions, nil
+}
+
+// Answers the alias claimed by the resource object and its language, or false if it claims none
+func aliasClaimOf(data map[string]interface{}) (AliasClaim, string, bool) {
+	claim := AliasClaim{}
+	claim.Type, _ = data["type"].(string)
+	claim.Id, _ = data["id"].(string)
+	attributes, _ := data["attributes"].(map[string]interface{})
+
+	var langcode string
+	if claim.Type == PathAliasType {
+		claim.Alias, _ = attributes["alias"].(string)
+		claim.Path, _ = attributes["path"].(string)
+		langcode, _ = attributes["langcode"].(string)
+	} else {
+		path, _ := attributes["path"].(map[string]interface{})
+		claim.Alias, _ = path["alias"].(string)
+		langcode, _ = path["langcode"].(string)
+		if title, ok := attributes["title"].(string); ok {
+			claim.Label = title
+		} else {
+			claim.Label, _ = attributes["name"].(string)
+		}
+		entity := jsonapi.DrupalType(claim.Type).Entity()
+		for _, k := range []string{"drupal_internal__nid", "drupal_internal__tid", "drupal_internal__mid", "drupal_internal__id"} {
+			if id, ok := attributes[k]; ok && id != nil {
+				claim.Path = fmt.Sprintf("/%s/%v", strings.Replace(entity, "taxonomy_term", "taxonomy/term", 1), id)
+				break
+			}
+		}
+	}
+	if claim.Alias == "" {
+		return claim, "", false
+	}
+	if langcode == "" {
+		langcode = "und"
+	}
+	return claim, langcode, true
+}
+
+// Answers the alias as Drupal matches it: case-insensitively, and disregarding a trailing slash
+func normalizeAlias(alias string) string {
+	alias = strings.ToLower(strings.TrimSpace(alias))
+	if len(alias) > 1 {
+		alias = strings.TrimSuffix(alias, "/")
+	}
+	return alias
+}
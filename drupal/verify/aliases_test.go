@@ -0,0 +1,97 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pathField(alias, langcode string) map[string]interface{} {
+	return map[string]interface{}{"alias": alias, "pid": 1, "langcode": langcode}
+}
+
+func Test_AliasAuditor(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 2
+	m.Add(
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{
+			"title": "Moonrise", "drupal_internal__nid": 12, "path": pathField("/moonrise", "en")}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n2", "attributes": map[string]interface{}{
+			"title": "Moonrise", "drupal_internal__nid": 13, "path": pathField("/Moonrise/", "en")}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n3", "attributes": map[string]interface{}{
+			"title": "Moonrise (Spanish)", "path": pathField("/moonrise", "es")}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n4", "attributes": map[string]interface{}{
+			"title": "Unaliased", "path": map[string]interface{}{"alias": nil, "pid": nil, "langcode": "en"}}},
+		jsonapitest.Resource{"type": "node--collection_object", "id": "c1", "attributes": map[string]interface{}{
+			"title": "Ansel Adams", "path": pathField("/collections/adams", "en")}},
+		jsonapitest.Resource{"type": "taxonomy_term--subject", "id": "s1", "attributes": map[string]interface{}{
+			"name": "Adams", "drupal_internal__tid": 7, "path": pathField("/collections/adams", "en")}},
+		jsonapitest.Resource{"type": "path_alias--path_alias", "id": "a1", "attributes": map[string]interface{}{
+			"alias": "/moonset", "path": "/node/14", "langcode": "en"}},
+		jsonapitest.Resource{"type": "path_alias--path_alias", "id": "a2", "attributes": map[string]interface{}{
+			"alias": "/moonset", "path": "/node/14", "langcode": "en"}},
+		jsonapitest.Resource{"type": "path_alias--path_alias", "id": "a3", "attributes": map[string]interface{}{
+			"alias": "/moonrise", "path": "/node/12", "langcode": "en"}},
+	)
+
+	a := NewAliasAuditor(m.URL, "", "")
+	collisions, err := a.Check("node--islandora_object")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(collisions))
+	assert.Equal(t, "/moonrise", collisions[0].Alias)
+	assert.Equal(t, "en", collisions[0].Langcode)
+	assert.False(t, collisions[0].Duplicate())
+	assert.Equal(t, `alias /moonrise (en) is claimed by node--islandora_object "Moonrise" (n1), node--islandora_object "Moonrise" (n2)`,
+		collisions[0].String())
+	assert.Equal(t, "/node/12", collisions[0].Claims[0].Path)
+
+	// aliases collide across bundles
+	collisions, err = a.Check("node--collection_object", "taxonomy_term--subject")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(collisions))
+	assert.Equal(t, "/taxonomy/term/7", collisions[0].Claims[1].Path)
+
+	collisions, err = a.Check(PathAliasType)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(collisions))
+	assert.True(t, collisions[0].Duplicate())
+	assert.Equal(t, "alias /moonset (en) is duplicated by path_alias--path_alias /node/14 (a1), path_alias--path_alias /node/14 (a2)",
+		collisions[0].String())
+
+	assert.True(t, a.AssertUnique(t, "node--collection_object"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, a.AssertUnique(rec, "node--islandora_object"))
+	assert.Contains(t, rec.String(), `alias /moonrise (en) is claimed by node--islandora_object "Moonrise" (n1), node--islandora_object "Moonrise" (n2)`)
+	rec = &asserttest.Recorder{}
+	assert.False(t, a.AssertUnique(rec, "node--"))
+	assert.Contains(t, rec.String(), "'node--' has an empty bundle")
+}
+
+// Collisions are ordered by alias and language, and their claims by type and id, whatever order Drupal answers them in
+func Test_AliasAuditorOrdering(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	for _, id := range []string{"a4", "a2", "a3", "a1"} {
+		alias := "/moonset"
+		if id == "a4" || id == "a1" {
+			alias = "/moonrise"
+		}
+		m.Add(jsonapitest.Resource{"type": "path_alias--path_alias", "id": id, "attributes": map[string]interface{}{
+			"alias": alias, "path": "/node/" + id, "langcode": "en"}})
+	}
+
+	collisions, err := NewAliasAuditor(m.URL, "", "").Check(PathAliasType)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(collisions))
+	assert.Equal(t, "alias /moonrise (en) is claimed by path_alias--path_alias /node/a1 (a1), path_alias--path_alias /node/a4 (a4)",
+		collisions[0].String())
+	assert.Equal(t, "alias /moonset (en) is claimed by path_alias--path_alias /node/a2 (a2), path_alias--path_alias /node/a3 (a3)",
+		collisions[1].String())
+}
@@ -0,0 +1,141 @@
+package verify
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/collection"
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// Alt text that describes nothing, compared without regard to case or surrounding punctuation.  Sites may append
+// their own.
+var PlaceholderAltText = []string{"alt", "alt text", "image", "img", "photo", "photograph", "picture", "thumbnail",
+	"placeholder", "untitled", "none", "n/a", "na", "tbd", "todo", "-"}
+
+var (
+	// Alt text naming a file, e.g. `moonrise.jpg`
+	fileNameAltText = regexp.MustCompile(`(?i)^\S+\.(jpe?g|png|gif|tiff?|jp2|bmp|webp)$`)
+	// Alt text naming a camera's image, e.g. `IMG_0042` or `DSC 1234`
+	cameraAltText = regexp.MustCompile(`(?i)^(img|dsc|dscn|dcim|pxl)[ _-]?\d+$`)
+	uuidPattern   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// An image media whose alt text is missing, or is a placeholder
+type MissingAltText struct {
+	MediaId   string
+	MediaName string
+	// The ids of the entities the media is of (`field_media_of`)
+	MediaOf []string
+	// The alt text, if any
+	Alt    string
+	Reason string
+}
+
+// Answers the media as e.g. `image media 'Moonrise' (m1): alt text "image" is a placeholder`
+func (m MissingAltText) String() string {
+	return fmt.Sprintf("image media '%s' (%s): %s", m.MediaName, m.MediaId, m.Reason)
+}
+
+// Answers why the alt text is unacceptable, or the empty string if it is acceptable.  Alt text is unacceptable if it
+// is blank, is one of PlaceholderAltText, or names a file.
+func AltTextProblem(alt string) string {
+	normalized := strings.ToLower(strings.Trim(NormalizeText(alt), " .:;,"))
+	switch {
+	case normalized == "":
+		return "alt text is missing"
+	case fileNameAltText.MatchString(normalized) || cameraAltText.MatchString(normalized):
+		return fmt.Sprintf("alt text %q names a file", alt)
+	}
+	for _, p := range PlaceholderAltText {
+		if normalized == p {
+			return fmt.Sprintf("alt text %q is a placeholder", alt)
+		}
+	}
+	return ""
+}
+
+// Audits the alt text of every image media, answering the media whose alt text is missing or is a placeholder (see
+// AltTextProblem), ordered by media id.  If ids are supplied, only the media of the entities with those ids are
+// audited.
+func AuditAltText(baseUrl, username, password string, ids ...string) ([]MissingAltText, error) {
+	of := map[string]bool{}
+	for _, id := range ids {
+		of[id] = true
+	}
+	u := imageMediaUrl(baseUrl, username, password)
+	return auditAltText(u, func(mediaOf []string) bool {
+		if len(of) == 0 {
+			return true
+		}
+		for _, id := range mediaOf {
+			if of[id] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Audits the alt text of the image media of the collection with the title or UUID, and of its descendants
+func AuditCollectionAltText(baseUrl, username, password, titleOrUuid string) ([]MissingAltText, error) {
+	c := collection.NewClient(baseUrl, username, password)
+	root, err := c.Find(titleOrUuid)
+	if err != nil {
+		return nil, err
+	}
+	members, err := c.Descendants(titleOrUuid, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{root.Id}
+	for _, m := range members {
+		ids = append(ids, m.Id)
+	}
+	return AuditAltText(baseUrl, username, password, ids...)
+}
+
+func imageMediaUrl(baseUrl, username, password string) *jsonapi.JsonApiUrl {
+	return &jsonapi.JsonApiUrl{
+		BaseUrl:      baseUrl,
+		DrupalEntity: model.Media,
+		DrupalBundle: model.Image,
+		Username:     username,
+		Password:     env.Secret(password),
+	}
+}
+
+// Answers the image media answered by the url whose alt text is unacceptable, if the ids of the entities they are of
+// are selected
+func auditAltText(u *jsonapi.JsonApiUrl, selected func(mediaOf []string) bool) ([]MissingAltText, error) {
+	field := mediaFileFields[model.Image]
+	var missing []MissingAltText
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, media := range page.Data {
+			m := MissingAltText{MediaName: str(nested(media, "attributes"), "name")}
+			m.MediaId, _ = media["id"].(string)
+			refs, _ := nested(media, "relationships", "field_media_of")["data"].([]interface{})
+			for _, ref := range refs {
+				ref, _ := ref.(map[string]interface{})
+				m.MediaOf = append(m.MediaOf, str(ref, "id"))
+			}
+			if !selected(m.MediaOf) {
+				continue
+			}
+			m.Alt = str(nested(media, "relationships", field, "data", "meta"), "alt")
+			if m.Reason = AltTextProblem(m.Alt); m.Reason != "" {
+				missing = append(missing, m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to audit the alt text of image media: %w", err)
+	}
+	sort.SliceStable(missing, func(i, j int) bool { return missing[i].MediaId < missing[j].MediaId })
+	return missing, nil
+}
@@ -0,0 +1,99 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AltTextProblem(t *testing.T) {
+	assert.Equal(t, "", AltTextProblem("Moonrise over Hernandez, New Mexico"))
+	assert.Equal(t, "alt text is missing", AltTextProblem(" "))
+	assert.Equal(t, `alt text "Image." is a placeholder`, AltTextProblem("Image."))
+	assert.Equal(t, `alt text "N/A" is a placeholder`, AltTextProblem("N/A"))
+	assert.Equal(t, `alt text "moonrise.JPG" names a file`, AltTextProblem("moonrise.JPG"))
+	assert.Equal(t, `alt text "IMG_0042" names a file`, AltTextProblem("IMG_0042"))
+}
+
+func imageMedia(id, name, alt string, mediaOf ...string) jsonapitest.Resource {
+	var refs []interface{}
+	for _, of := range mediaOf {
+		refs = append(refs, map[string]interface{}{"type": "node--islandora_object", "id": of})
+	}
+	file := map[string]interface{}{"type": "file--file", "id": "f" + id}
+	if alt != "" {
+		file["meta"] = map[string]interface{}{"alt": alt, "width": 640.0, "height": 480.0}
+	}
+	return jsonapitest.Resource{"type": "media--image", "id": id, "attributes": map[string]interface{}{"name": name},
+		"relationships": map[string]interface{}{
+			"field_media_of":    map[string]interface{}{"data": refs},
+			"field_media_image": map[string]interface{}{"data": file},
+		}}
+}
+
+func Test_AuditAltText(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(
+		jsonapitest.Resource{"type": "node--collection_object", "id": "c1", "attributes": map[string]interface{}{"title": "Ansel Adams Images"}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "o1", "attributes": map[string]interface{}{"title": "Moonrise"},
+			"relationships": map[string]interface{}{"field_member_of": map[string]interface{}{"data": []interface{}{
+				map[string]interface{}{"type": "node--collection_object", "id": "c1"}}}}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "o2", "attributes": map[string]interface{}{"title": "Elsewhere"}},
+		imageMedia("m1", "Moonrise", "Moonrise over Hernandez", "o1"),
+		imageMedia("m2", "Moonrise service file", "", "o1"),
+		imageMedia("m3", "Elsewhere", "photo", "o2"),
+	)
+
+	missing, err := AuditAltText(m.URL, "", "")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(missing))
+	assert.Equal(t, "image media 'Moonrise service file' (m2): alt text is missing", missing[0].String())
+	assert.Equal(t, []string{"o1"}, missing[0].MediaOf)
+	assert.Equal(t, "photo", missing[1].Alt)
+
+	missing, err = AuditAltText(m.URL, "", "", "o2")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(missing))
+	assert.Equal(t, "m3", missing[0].MediaId)
+
+	missing, err = AuditCollectionAltText(m.URL, "", "", "Ansel Adams Images")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(missing))
+	assert.Equal(t, "m2", missing[0].MediaId)
+	_, err = AuditCollectionAltText(m.URL, "", "", "Moonset")
+	assert.NotNil(t, err)
+
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertAltText(rec, m.URL, "", "", "Moonrise"))
+	assert.Contains(t, rec.String(), "image media 'Moonrise service file' (m2): alt text is missing")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertAltText(rec, m.URL, "", "", "Elsewhere"))
+	assert.Contains(t, rec.String(), `image media 'Elsewhere' (m3): alt text "photo" is a placeholder`)
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "00000000-0000-4000-8000-000000000001", "attributes": map[string]interface{}{"title": "Moonset"}},
+		imageMedia("m4", "Moonset", "Moonset over the Sierra Nevada", "00000000-0000-4000-8000-000000000001"))
+	assert.True(t, AssertAltText(t, m.URL, "", "", "Moonset"))
+	assert.True(t, AssertAltText(t, m.URL, "", "", "00000000-0000-4000-8000-000000000001"))
+}
+
+// The audit answers media ordered by id, whatever order Drupal answers them in
+func Test_AuditAltTextOrdering(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	m.Add(imageMedia("m3", "Third", "", "o1"), imageMedia("m1", "First", "", "o1"), imageMedia("m2", "Second", "", "o1"))
+
+	missing, err := AuditAltText(m.URL, "", "")
+	require.Nil(t, err)
+	var ids []string
+	for _, media := range missing {
+		ids = append(ids, media.MediaId)
+	}
+	assert.Equal(t, []string{"m1", "m2", "m3"}, ids)
+}
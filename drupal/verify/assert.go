@@ -0,0 +1,331 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that no alias is claimed more than once among the entities of the Drupal types (see Check)
+func (a *AliasAuditor) AssertUnique(t assert.TestingT, drupalTypes ...string) bool {
+	collisions, err := a.Check(drupalTypes...)
+	if !assert.Nil(t, err, "error auditing the aliases of %s: %s", strings.Join(drupalTypes, ", "), err) {
+		return false
+	}
+	ok := true
+	for _, c := range collisions {
+		ok = assert.Fail(t, "alias collision", "%s", c) && ok
+	}
+	return ok
+}
+
+// Asserts that the url alias of the entity of the Drupal type with the UUID matches the pattern (see CheckAlias)
+func (p *PathVerifier) AssertAlias(t assert.TestingT, drupalType, uuid, pattern string) bool {
+	err := p.CheckAlias(drupalType, uuid, pattern)
+	return assert.Nil(t, err, "%s", err)
+}
+
+// Asserts that the legacy url answers a 301 redirecting to the alias (see CheckRedirect)
+func (p *PathVerifier) AssertRedirect(t assert.TestingT, legacyUrl, alias string) bool {
+	err := p.CheckRedirect(legacyUrl, alias)
+	return assert.Nil(t, err, "%s", err)
+}
+
+// Asserts that each role has the access expected of it to the node of the Drupal type with the UUID, and to the files
+// of its media (see Check), e.g.
+//
+//	c.AssertAccess(t, "node--islandora_object", uuid, verify.Forbidden(verify.Anonymous), verify.Allowed(reader), verify.Allowed(admin))
+func (c *AccessChecker) AssertAccess(t assert.TestingT, drupalType, uuid string, expectations ...AccessExpectation) bool {
+	violations, err := c.Check(drupalType, uuid, expectations...)
+	if !assert.Nil(t, err, "error verifying access to %s %s: %s", drupalType, uuid, err) {
+		return false
+	}
+	ok := true
+	for _, v := range violations {
+		ok = assert.Fail(t, "unexpected access", "%s", v) && ok
+	}
+	return ok
+}
+
+// Asserts that the paragraphs referenced by the field of the entity matched by the url, and the paragraphs nested in
+// them, carry the expected bundles and values (see VerifyParagraphs)
+func AssertParagraphs(t assert.TestingT, u *jsonapi.JsonApiUrl, field string, expected []model.ExpectedParagraph) bool {
+	mismatches, err := VerifyParagraphs(u, field, expected)
+	if !assert.Nil(t, err, "error verifying the paragraphs of %s: %s", field, err) {
+		return false
+	}
+	ok := true
+	for _, m := range mismatches {
+		ok = assert.Fail(t, "paragraph mismatch", "%s", m) && ok
+	}
+	return ok
+}
+
+// Asserts that every image media of the repository object with the title or UUID carries acceptable alt text (see
+// AltTextProblem)
+func AssertAltText(t assert.TestingT, baseUrl, username, password, titleOrUuid string) bool {
+	u := imageMediaUrl(baseUrl, username, password)
+	u.Filter, u.Value = "field_media_of.title", titleOrUuid
+	if uuidPattern.MatchString(titleOrUuid) {
+		u.Filter = "field_media_of.id"
+	}
+	missing, err := auditAltText(u, func([]string) bool { return true })
+	if !assert.Nil(t, err, "error auditing the alt text of the media of '%s': %s", titleOrUuid, err) {
+		return false
+	}
+	ok := true
+	for _, m := range missing {
+		ok = assert.Fail(t, "unacceptable alt text", "%s", m) && ok
+	}
+	return ok
+}
+
+// Asserts that the values (e.g. the creators of a view, in the order it answered them) are sorted by the comparator,
+// or by ByteOrder if it is nil, reporting the first pair out of order:
+//
+//	verify.AssertSorted(t, names, verify.Collation("es", collate.IgnoreCase))
+func AssertSorted(t assert.TestingT, values []string, cmp Comparator, msgAndArgs ...interface{}) bool {
+	if i := outOfOrder(values, cmp); i >= 0 {
+		return assert.Fail(t, fmt.Sprintf("values are not sorted: %q (at %d) sorts before %q (at %d)",
+			values[i+1], i+1, values[i], i), msgAndArgs...)
+	}
+	return true
+}
+
+// Asserts that the actual extents are semantically equal to the expected extents, in order
+func AssertExtents(t assert.TestingT, expected, actual []string) bool {
+	if !assert.Equal(t, len(expected), len(actual), "extent count differs: expected %q, got %q", expected, actual) {
+		return false
+	}
+	ok := true
+	for i := range expected {
+		ok = assert.True(t, EqualExtent(expected[i], actual[i]), "extent %d differs: expected %q, got %q", i, expected[i], actual[i]) && ok
+	}
+	return ok
+}
+
+// Asserts that the actual value is a point within GeoTolerance of the expected point, whatever their representations
+func AssertPoint(t assert.TestingT, expected, actual interface{}) bool {
+	e, err := ParsePoint(expected)
+	if !assert.Nil(t, err, "expected point: %s", err) {
+		return false
+	}
+	a, err := ParsePoint(actual)
+	if !assert.Nil(t, err, "actual point: %s", err) {
+		return false
+	}
+	return assert.True(t, e.Near(a, GeoTolerance), "point differs: expected %s, got %s", e, a)
+}
+
+// Asserts that no entity of the entity type and bundle carries a dangling reference
+func (c *IntegrityChecker) AssertBundle(t assert.TestingT, entityType, bundle string) bool {
+	dangling, err := c.CheckBundle(entityType, bundle)
+	if !assert.Nil(t, err, "error checking the references of %s--%s: %s", entityType, bundle, err) {
+		return false
+	}
+	ok := true
+	for _, d := range dangling {
+		ok = assert.Fail(t, "dangling reference", "%s", d) && ok
+	}
+	return ok
+}
+
+// Asserts that the expected and actual URIs are equal after canonicalization (see CanonicalUri)
+func AssertUri(t assert.TestingT, expected, actual string, opts ...UriOption) bool {
+	if EqualUri(expected, actual, opts...) {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("URIs are not equal:\nexpected: %s\nactual  : %s\ncanonical expected: %s\ncanonical actual  : %s",
+		expected, actual, CanonicalUri(expected, opts...), CanonicalUri(actual, opts...)))
+}
+
+// Asserts that the expected and actual URIs (e.g. of a multi-valued authority link field) are
+// equal in number and order, and that each pair of URIs is equal after canonicalization
+func AssertUris(t assert.TestingT, expected, actual []string, opts ...UriOption) bool {
+	if !assert.Equal(t, len(expected), len(actual), "number of URIs differ: expected %v, actual %v", expected, actual) {
+		return false
+	}
+	ok := true
+	for i := range expected {
+		ok = AssertUri(t, expected[i], actual[i], opts...) && ok
+	}
+	return ok
+}
+
+// Asserts that the expected and actual authority links are equal in number and order, and that each pair of
+// authority links has the same source and canonically equal URIs.  Authority titles are not compared.
+func AssertAuthorities(t assert.TestingT, expected, actual []model.Authority, opts ...UriOption) bool {
+	if !assert.Equal(t, len(expected), len(actual), "number of authority links differ") {
+		return false
+	}
+	ok := true
+	for i := range expected {
+		ok = assert.Equal(t, expected[i].Source, actual[i].Source, "source of authority link %d differs", i) && ok
+		ok = AssertUri(t, expected[i].Uri, actual[i].Uri, opts...) && ok
+	}
+	return ok
+}
+
+// Asserts that the expected and actual links (e.g. of field_finding_aid or field_library_catalog_link) are equal in number and order, and that each
+// pair of links is equal (see EqualLink)
+func AssertLinks(t assert.TestingT, expected, actual []model.Link, opts ...UriOption) bool {
+	if !assert.Equal(t, len(expected), len(actual), "number of links differ: expected %v, actual %v", expected, actual) {
+		return false
+	}
+	ok := true
+	for i := range expected {
+		ok = assert.True(t, EqualLink(expected[i], actual[i], opts...), "link %d differs:\nexpected: %+v\nactual  : %+v", i, expected[i], actual[i]) && ok
+	}
+	return ok
+}
+
+// Asserts that the embed url stored by Drupal's media source field is the same video as the expected embed url, once
+// both are in canonical form (see CanonicalVideoUrl).
+//
+// If the environment variable 'VERIFY_OEMBED' is true, the oEmbed provider is also asked to resolve the video, and
+// the response must carry a title.  This check requires network access to the provider, so it is disabled by default.
+func AssertRemoteVideo(t assert.TestingT, expected model.ExpectedMediaRemoteVideo, actualEmbedUrl string) bool {
+	expectedUrl, err := CanonicalVideoUrl(expected.EmbedUrl)
+	if !assert.Nil(t, err, "expected embed url is not valid: %s", err) {
+		return false
+	}
+	actualUrl, err := CanonicalVideoUrl(actualEmbedUrl)
+	if !assert.Nil(t, err, "actual embed url is not valid: %s", err) {
+		return false
+	}
+	if !assert.Equal(t, expectedUrl, actualUrl, "embed urls refer to different videos") {
+		return false
+	}
+
+	if env.VerifyOembedOr(false) {
+		_, err := FetchOembed(actualEmbedUrl)
+		return assert.Nil(t, err, "unable to resolve %s using its oEmbed provider: %s", actualEmbedUrl, err)
+	}
+	return true
+}
+
+// Asserts that the single entity of the type and bundle with the title or name is owned by the expected owner of the
+// bundle
+func (c *OwnershipChecker) AssertOwner(t assert.TestingT, entityType, bundle, titleOrName string) bool {
+	expected, err := c.ExpectedOwner(bundle)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	actual, err := c.Owner(entityType, bundle, titleOrName)
+	if !assert.Nil(t, err, "error resolving the owner of '%s': %s", titleOrName, err) {
+		return false
+	}
+	return assert.Equal(t, expected, actual, "%s--%s '%s' has an unexpected owner", entityType, bundle, titleOrName)
+}
+
+// Asserts that every entity of the type and bundle is owned by the expected owner of the bundle
+func (c *OwnershipChecker) AssertBundle(t assert.TestingT, entityType, bundle string) bool {
+	violations, err := c.CheckBundle(entityType, bundle)
+	if !assert.Nil(t, err, "error checking the owners of %s--%s: %s", entityType, bundle, err) {
+		return false
+	}
+	ok := true
+	for _, v := range violations {
+		ok = assert.Fail(t, "unexpected owner", "%s", v) && ok
+	}
+	return ok
+}
+
+// Asserts that the entity violates none of the rules in the registry.  If the registry is nil, DefaultRules is used.
+func AssertRules(t assert.TestingT, e model.ExpectedEntity, rules *Rules) bool {
+	if rules == nil {
+		rules = DefaultRules
+	}
+	ok := true
+	for _, v := range rules.Evaluate(e) {
+		ok = assert.Fail(t, fmt.Sprintf("%s %s violates rule %s", e.EntityType(), e.EntityBundle(), v))
+	}
+	return ok
+}
+
+// Asserts that the actual value matches the expected value (see EqualText).  Language codes are not compared.
+func AssertText(t assert.TestingT, expected model.LanguageString, actual string) bool {
+	if EqualText(expected, actual) {
+		return true
+	}
+	if expected.Sha256 != "" {
+		return assert.Fail(t, "SHA-256 of the normalized value differs",
+			"expected: %s\nactual  : %s (of %d characters beginning '%s')", expected.Sha256, TextSha256(actual),
+			len(actual), abbreviate(NormalizeText(actual), 80))
+	}
+	return assert.Equal(t, NormalizeText(expected.Value), NormalizeText(actual), "normalized values differ")
+}
+
+// Asserts that the actual values (e.g. of field_table_of_contents) match the expected values in number and order
+// (see EqualText).  Language codes are not compared.
+func AssertTexts(t assert.TestingT, expected []model.LanguageString, actual []model.JsonApiLanguageValue) bool {
+	if !assert.Equal(t, len(expected), len(actual), "number of values differ") {
+		return false
+	}
+	ok := true
+	for i := range expected {
+		ok = AssertText(t, expected[i], actual[i].Value()) && ok
+	}
+	return ok
+}
+
+// Answers at most the first n runes of s
+func abbreviate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+// Asserts that each expected translation of the term exists, and carries the expected name and description.  The
+// term is resolved by its name in the site default language, so the resolver ought to be shared with the assertions
+// made in the default language.
+func AssertTermTranslations(t assert.TestingT, r *jsonapi.TermResolver, expected model.Translated) bool {
+	if len(expected.TermTranslations()) == 0 {
+		return true
+	}
+
+	id, err := r.Resolve(expected.EntityBundle(), expected.NameOrTitle())
+	if !assert.Nil(t, err, "unable to resolve term '%s': %s", expected.NameOrTitle(), err) {
+		return false
+	}
+
+	ok := true
+	for _, e := range expected.TermTranslations() {
+		actual, err := FetchTermTranslation(r, expected.EntityBundle(), id, e.Langcode)
+		if !assert.Nil(t, err, "unable to retrieve the '%s' translation of term '%s': %s", e.Langcode, expected.NameOrTitle(), err) {
+			ok = false
+			continue
+		}
+		ok = assert.Equal(t, e.Name, actual.Name, "name of the '%s' translation differs", e.Langcode) && ok
+		ok = assert.Equal(t, e.Description.Value, actual.Description.Value, "description of the '%s' translation differs", e.Langcode) && ok
+		ok = assert.Equal(t, e.Description.Format, actual.Description.Format, "description format of the '%s' translation differs", e.Langcode) && ok
+	}
+	return ok
+}
+
+// Asserts that each expected translation of the entity of the type and bundle with the UUID exists, and carries the
+// expected title (or name) and, if the expected translation carries one, description
+func AssertTranslations(t assert.TestingT, baseUrl, username, password, entityType, bundle, id string, expected ...model.ExpectedTranslation) bool {
+	ok := true
+	for _, e := range expected {
+		actual, err := FetchTranslation(baseUrl, username, password, entityType, bundle, id, e.Langcode)
+		if !assert.Nil(t, err, "unable to retrieve the '%s' translation of %s--%s %s: %s", e.Langcode, entityType, bundle, id, err) {
+			ok = false
+			continue
+		}
+		ok = assert.Equal(t, e.Title, actual.Title, "title of the '%s' translation differs", e.Langcode) && ok
+		if e.Description != "" {
+			ok = assert.Equal(t, e.Description, actual.Description, "description of the '%s' translation differs", e.Langcode) && ok
+		}
+	}
+	return ok
+}
@@ -0,0 +1,31 @@
+package verify
+
+import (
+	"strings"
+)
+
+// Answers the boolean represented by a JSON value, as serialized by any of the serializers used by Drupal: true and
+// false, 1 and 0, or the strings "true", "false", "1", and "0".  False is answered for ok if the value represents no
+// boolean.
+func ParseBool(v interface{}) (value bool, ok bool) {
+	switch v := v.(type) {
+	case bool:
+		return v, true
+	case float64:
+		if v == 0 || v == 1 {
+			return v == 1, true
+		}
+	case int:
+		if v == 0 || v == 1 {
+			return v == 1, true
+		}
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "1", "true":
+			return true, true
+		case "0", "false":
+			return false, true
+		}
+	}
+	return false, false
+}
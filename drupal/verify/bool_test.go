@@ -0,0 +1,53 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseBool(t *testing.T) {
+	for _, v := range []interface{}{true, 1.0, 1, "1", "true", " TRUE "} {
+		value, ok := ParseBool(v)
+		assert.True(t, ok && value, "%v", v)
+	}
+	for _, v := range []interface{}{false, 0.0, 0, "0", "false"} {
+		value, ok := ParseBool(v)
+		assert.True(t, ok && !value, "%v", v)
+	}
+	for _, v := range []interface{}{nil, 2.0, "yes", []interface{}{}} {
+		_, ok := ParseBool(v)
+		assert.False(t, ok, "%v", v)
+	}
+}
+
+func Test_EngineBooleans(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(
+		jsonapitest.Resource{"type": "node--islandora_object", "attributes": map[string]interface{}{"title": "Moonrise", "field_featured_item": 1}},
+		jsonapitest.Resource{"type": "node--islandora_object", "attributes": map[string]interface{}{"title": "Moonset", "field_featured_item": true}},
+	)
+	e := NewEngine(m.URL, "", "")
+
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "featured_item": true}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed())
+	require.Equal(t, 1, len(r.Drift))
+	assert.Equal(t, "featured_item: expected true, got 1", r.Drift[0].String())
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonset", "featured_item": true}`))
+	assert.True(t, r.Passed())
+	assert.Empty(t, r.Drift)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "featured_item": false}`))
+	assert.Equal(t, 1, len(r.Mismatches))
+	assert.Empty(t, r.Drift)
+
+	e.StrictBooleans = true
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "featured_item": true}`))
+	assert.False(t, r.Passed())
+	assert.Equal(t, 1, len(r.Mismatches))
+}
@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Orders two strings, answering a negative number if a sorts before b, a positive number if after, and zero if they
+// sort together
+type Comparator func(a, b string) int
+
+// Orders strings by their bytes, as Go and a naive database collation do, so that e.g. "Zuñiga" sorts before
+// "adams" and "Ávila" after "Zuñiga"
+var ByteOrder Comparator = strings.Compare
+
+// Answers a comparator ordering strings as a reader of the language (a BCP 47 tag, e.g. `en` or `es`) expects, per
+// the Unicode Collation Algorithm, so that e.g. "Ávila" sorts with "Avila" rather than after "Zuñiga".  Options
+// loosen the collation further, e.g. collate.IgnoreCase, collate.IgnoreDiacritics or collate.Numeric (which sorts
+// "Box 9" before "Box 10").  An unrecognized language collates as the root locale.  The comparator may be used
+// concurrently.
+func Collation(lang string, options ...collate.Option) Comparator {
+	c := collate.New(language.Make(lang), options...)
+	var mu sync.Mutex
+	return func(a, b string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return c.CompareString(a, b)
+	}
+}
+
+// Answers a comparator ordering strings in the reverse of the order of the comparator, e.g. to assert upon a view
+// sorted descending
+func Descending(cmp Comparator) Comparator {
+	return func(a, b string) int {
+		return cmp(b, a)
+	}
+}
+
+// Answers true if the values are sorted by the comparator, or by ByteOrder if it is nil
+func IsSorted(values []string, cmp Comparator) bool {
+	return outOfOrder(values, cmp) < 0
+}
+
+// Answers the index of the first value sorting after its successor, or -1 if the values are sorted
+func outOfOrder(values []string, cmp Comparator) int {
+	if cmp == nil {
+		cmp = ByteOrder
+	}
+	for i := 0; i+1 < len(values); i++ {
+		if cmp(values[i], values[i+1]) > 0 {
+			return i
+		}
+	}
+	return -1
+}
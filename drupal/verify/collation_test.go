@@ -0,0 +1,37 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/collate"
+)
+
+func Test_Collation(t *testing.T) {
+	names := []string{"Adams", "Avila", "Ávila", "Éluard", "Lange", "Zuñiga"}
+	assert.False(t, IsSorted(names, nil))
+	assert.False(t, IsSorted(names, ByteOrder))
+	assert.True(t, IsSorted(names, Collation("en")))
+	assert.False(t, IsSorted([]string{"Ávila", "Avila"}, Collation("en")))
+
+	// case and diacritics are significant unless ignored
+	assert.False(t, IsSorted([]string{"avila", "Avila", "Ávila", "ávila"}, Collation("en")))
+	assert.True(t, IsSorted([]string{"Ávila", "avila", "Avila", "ávila"}, Collation("en", collate.IgnoreCase, collate.IgnoreDiacritics)))
+
+	// ö sorts after z in Swedish, but with o in English
+	assert.True(t, IsSorted([]string{"Ohlsson", "Zorn", "Östberg"}, Collation("sv")))
+	assert.False(t, IsSorted([]string{"Ohlsson", "Zorn", "Östberg"}, Collation("en")))
+
+	assert.True(t, IsSorted([]string{"Box 9", "Box 10"}, Collation("en", collate.Numeric)))
+	assert.True(t, IsSorted([]string{"Zuñiga", "Lange", "Ávila", "Adams"}, Descending(Collation("en"))))
+	assert.True(t, IsSorted(nil, Collation("not a language")))
+
+	assert.True(t, AssertSorted(t, names, Collation("en")))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertSorted(rec, names, ByteOrder, "creators of %s", "the view"))
+	assert.Contains(t, rec.String(), `values are not sorted: "Lange" (at 4) sorts before "Éluard" (at 3)`)
+}
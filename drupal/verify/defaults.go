@@ -0,0 +1,90 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// The name of the file carrying the default values of the fixtures in its directory and their subdirectories, e.g.
+// the publisher, rights and collection number shared by the objects of a collection.  A defaults file is not itself
+// a fixture.
+const DefaultsFile = "_defaults.json"
+
+// Reads the fixture at the path, merged with the defaults of its directory and of each ancestor directory.  Values are
+// taken from the first of the following that carries the key:
+//  1. the fixture
+//  2. the DefaultsFile of the fixture's directory
+//  3. the DefaultsFile of each ancestor directory, nearest first
+//
+// Keys are merged at the top level only, so a fixture's `genre` replaces the default `genre` entirely rather than
+// being merged with it.  A fixture key whose value is null removes the default.  The fixture is answered unchanged if
+// no defaults apply.
+func LoadFixture(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := Defaults(filepath.Dir(path))
+	if err != nil || len(defaults) == 0 {
+		return b, err
+	}
+
+	fixture := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal fixture %s: %w", path, err)
+	}
+	for k, v := range fixture {
+		if v == nil {
+			delete(defaults, k)
+		} else {
+			defaults[k] = v
+		}
+	}
+	return json.Marshal(defaults)
+}
+
+// Answers the defaults applying to the fixtures of the directory: the merged DefaultsFile of the directory and of each
+// ancestor directory, the nearest taking precedence (see LoadFixture).  An empty map is answered if no DefaultsFile
+// applies.
+func Defaults(dir string) (map[string]interface{}, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for {
+		dirs = append(dirs, abs)
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			break
+		}
+		abs = parent
+	}
+
+	defaults := map[string]interface{}{}
+	// farthest first, so that nearer defaults replace them
+	for i := len(dirs) - 1; i >= 0; i-- {
+		path := filepath.Join(dirs[i], DefaultsFile)
+		b, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		d := map[string]interface{}{}
+		if err := json.Unmarshal(b, &d); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal defaults %s: %w", path, err)
+		}
+		for k, v := range d {
+			if v == nil {
+				delete(defaults, k)
+			} else {
+				defaults[k] = v
+			}
+		}
+	}
+	return defaults, nil
+}
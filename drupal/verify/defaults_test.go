@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadFixture(t *testing.T) {
+	dir := t.TempDir()
+	coll := filepath.Join(dir, "ansel-adams")
+	require.Nil(t, os.Mkdir(coll, 0755))
+	write := func(path, content string) {
+		require.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	write(filepath.Join(dir, DefaultsFile), `{"type": "node", "bundle": "islandora_object", "publisher": ["JHU"], "rights": "In Copyright"}`)
+	write(filepath.Join(coll, DefaultsFile), `{"rights": "No Copyright", "collection_number": "MS-0001", "publisher": null}`)
+	write(filepath.Join(coll, "moonrise.json"), `{"title": "Moonrise", "collection_number": null, "genre": ["Photographs"]}`)
+	write(filepath.Join(dir, "unmerged.json"), `{"title": "Moonset", "rights": "Unknown"}`)
+
+	defaults, err := Defaults(coll)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "node", "bundle": "islandora_object", "rights": "No Copyright",
+		"collection_number": "MS-0001"}, defaults)
+
+	b, err := LoadFixture(filepath.Join(coll, "moonrise.json"))
+	require.Nil(t, err)
+	fixture := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(b, &fixture))
+	assert.Equal(t, map[string]interface{}{"type": "node", "bundle": "islandora_object", "rights": "No Copyright",
+		"title": "Moonrise", "genre": []interface{}{"Photographs"}}, fixture)
+
+	b, err = LoadFixture(filepath.Join(dir, "unmerged.json"))
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(b, &fixture))
+	assert.Equal(t, "Unknown", fixture["rights"])
+	assert.Equal(t, []interface{}{"JHU"}, fixture["publisher"])
+
+	// fixtures are answered unchanged if no defaults apply
+	other := t.TempDir()
+	write(filepath.Join(other, "a.json"), `{"title": "A"}`)
+	b, err = LoadFixture(filepath.Join(other, "a.json"))
+	require.Nil(t, err)
+	assert.Equal(t, `{"title": "A"}`, string(b))
+
+	write(filepath.Join(coll, "broken.json"), `{"title": `)
+	_, err = LoadFixture(filepath.Join(coll, "broken.json"))
+	assert.NotNil(t, err)
+
+	paths, err := FixturePaths(dir)
+	require.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(coll, "broken.json"), filepath.Join(coll, "moonrise.json"), filepath.Join(dir, "unmerged.json")}, paths)
+}
@@ -0,0 +1,616 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/diff"
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The fixture key listing the only keys to verify, so that a fixture of an enormous object may spot-check a few
+// fields, e.g. `"verify_only": ["title", "genre"]`
+const VerifyOnlyKey = "verify_only"
+
+// The fixture key carrying the legacy PID of an entity, which identifies the entity if the fixture carries neither a
+// title nor a name
+const pidKey = "pid"
+
+// A value of a fixture that differs from the live entity.  A list differing in length is reported as a Mismatch per
+// differing delta (index), the shorter list contributing nothing at the deltas it lacks.
+type Mismatch struct {
+	// The location of the value within the fixture, e.g. `genre[1]` or `model.name`
+	Path     string
+	Expected interface{}
+	Actual   interface{}
+	// The location of the actual value within the live entity, if it differs from Path, e.g. `subject[7]` when the
+	// lists of the key are compared regardless of order (see Engine.UnorderedKeys)
+	ActualPath string
+}
+
+// Answers the mismatch as e.g. `genre[1]: expected "Maps", got "Map"`, or `subject[2]: expected "Maps", got "Map" at
+// subject[7]` if the actual value was found elsewhere
+func (m Mismatch) String() string {
+	return m.Format(false)
+}
+
+// Answers the mismatch as String does, coloring the expected value red and the actual value green if color is true
+// (see diff.Colorize)
+func (m Mismatch) Format(color bool) string {
+	s := m.Difference().Format(color)
+	if m.ActualPath != "" {
+		s += " at " + m.ActualPath
+	}
+	return s
+}
+
+// Answers the mismatch as a diff.Difference
+func (m Mismatch) Difference() diff.Difference {
+	return diff.Difference{Path: m.Path, Expected: m.Expected, Actual: m.Actual}
+}
+
+// The outcome of verifying a single fixture against its live entity
+type Result struct {
+	// The file the fixture was read from, if any
+	Fixture string
+	Type    string
+	Bundle  string
+	// The title or name identifying the entity, or its legacy PID if the fixture carries neither
+	Key string
+	// The legacy Islandora 7 PID of the entity, e.g. `islandora:1234`, if the fixture or the live entity carries one
+	Pid        string
+	Mismatches []Mismatch
+	// Values that compared equal only leniently, e.g. a fixture's `true` and a live `1` (see Engine.StrictBooleans)
+	Drift []Mismatch
+	// The keys of the fixture that cannot be derived from the JSON API, and so were not verified
+	Unverified []string
+	// The keys of the fixture compared with the live entity, in the order compared
+	Checked []string
+	// The keys the fixture limited verification to (see VerifyOnlyKey); empty if every key was verified
+	VerifyOnly []string
+	Violations []Violation
+	// Set if the fixture could not be read, or the live entity could not be retrieved
+	Err      error
+	Duration time.Duration
+}
+
+// Answers true if the live entity matched the fixture and the fixture satisfied its rules
+func (r *Result) Passed() bool {
+	return r.Err == nil && len(r.Mismatches) == 0 && len(r.Violations) == 0
+}
+
+// Verifies Expected fixtures against the live entities of a Drupal site.  Each fixture is compared with the fixture
+// generated from its live entity (see model.GenerateFixture), so only the types answered by model.Generatable are
+// supported.  Only the keys present in a fixture are compared: maps are compared key by key, and lists element by
+// element.  A fixture carrying a VerifyOnlyKey list is compared on the listed keys only, and is not evaluated against
+// the Rules, which presume a complete fixture.  A model.ExpectedAbsent fixture passes only if no entity matches it.
+type Engine struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The rules evaluated against each fixture; DefaultRules if nil
+	Rules *Rules
+	// Booleans are serialized as true and false by some serializers, and as 1 and 0 (or "1" and "0") by others.  By
+	// default a fixture's boolean compares equal to any representation of the same value, and differing
+	// representations are recorded as Result.Drift.  If StrictBooleans is true, differing representations are
+	// mismatches.
+	StrictBooleans bool
+	// The fixture keys whose lists are compared regardless of order, e.g. `subject`.  Each expected value is matched
+	// with an equal live value at any delta; the values left unmatched are reported at their deltas in the fixture
+	// and, for live values, in the live entity (see Mismatch.ActualPath).
+	UnorderedKeys []string
+	// Finds the live entities of the fixtures of particular bundles, keyed by entity type and bundle (e.g.
+	// `node--islandora_object`).  The live entities of other bundles are found by the title, name or filename of
+	// their fixtures.
+	Resolvers map[string]Resolver
+	// The expected core flags of the nodes of each bundle, keyed by bundle (e.g. `islandora_object`), verified for
+	// the fixtures that do not carry them if the live entity carries them; DefaultNodeCore if nil
+	NodeCore map[string]model.ExpectedNodeCore
+	// Fixtures are validated against the JSON schema of their Expected struct (see model.ValidateFixture) before they
+	// are verified, so that a misspelled key fails the fixture rather than going unverified.  If SkipValidation is
+	// true, they are not.
+	SkipValidation bool
+	// Fixtures are unmarshaled into their Expected structs leniently, as encoding/json does.  If Strict is true, they
+	// are unmarshaled strictly (see model.UnmarshalStrict), so that a fixture omitting a field of its struct fails
+	// rather than expecting the zero value of the field.  Fixtures carrying a VerifyOnlyKey list are partial by
+	// design, and are always unmarshaled leniently.
+	Strict bool
+}
+
+// Migrated repository objects and collections must be neither promoted to the front page nor sticky
+var DefaultNodeCore = map[string]model.ExpectedNodeCore{
+	model.RepositoryObject: {Promote: new(bool), Sticky: new(bool)},
+	model.Collection:       {Promote: new(bool), Sticky: new(bool)},
+}
+
+// Creates an Engine for the Drupal site at the base url
+func NewEngine(baseUrl, username, password string) *Engine {
+	return &Engine{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Verifies the fixture read from the file, merged with the defaults of its directory (see LoadFixture)
+func (e *Engine) VerifyFile(path string) *Result {
+	b, err := LoadFixture(path)
+	if err != nil {
+		return &Result{Fixture: path, Err: err}
+	}
+	r := e.VerifyJson(b)
+	r.Fixture = path
+	return r
+}
+
+// Verifies each `.json` fixture in the directory and its subdirectories, in lexical order of their paths.  Files named
+// DefaultsFile are not fixtures, and are merged into the fixtures instead.
+func (e *Engine) VerifyDir(dir string) ([]*Result, error) {
+	paths, err := FixturePaths(dir)
+	var results []*Result
+	for _, path := range paths {
+		results = append(results, e.VerifyFile(path))
+	}
+	return results, err
+}
+
+// Answers the paths of the `.json` fixtures in the directory and its subdirectories, in lexical order.  DefaultsFile
+// is not a fixture, and is omitted.
+func FixturePaths(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".json") && info.Name() != DefaultsFile {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// Verifies the fixture carried by the JSON document
+func (e *Engine) VerifyJson(b []byte) *Result {
+	start := time.Now()
+	r := &Result{}
+	defer func() { r.Duration = time.Since(start) }()
+
+	fixture := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		r.Err = fmt.Errorf("unable to unmarshal fixture: %w", err)
+		return r
+	}
+	r.Type, _ = fixture["type"].(string)
+	r.Bundle, _ = fixture["bundle"].(string)
+	if !e.SkipValidation {
+		if r.Err = model.ValidateFixture(b, VerifyOnlyKey); r.Err != nil {
+			return r
+		}
+	}
+	if absent, _ := fixture[model.AbsentKey].(bool); absent {
+		e.verifyAbsent(b, r)
+		return r
+	}
+
+	r.Pid, _ = fixture[pidKey].(string)
+	keyField := "title"
+	for _, k := range []string{"title", "name", "filename"} {
+		if _, ok := fixture[k]; ok {
+			keyField = k
+			break
+		}
+	}
+	r.Key, _ = fixture[keyField].(string)
+	if r.Key == "" && r.Pid != "" {
+		keyField, r.Key = jsonapi.PidField, r.Pid
+	}
+	resolver := e.Resolvers[r.Type+"--"+r.Bundle]
+	if r.Key == "" && resolver == nil {
+		r.Err = fmt.Errorf("fixture of %s--%s carries neither a title, a name nor a filename", r.Type, r.Bundle)
+		return r
+	}
+
+	if r.VerifyOnly, r.Err = verifyOnly(fixture); r.Err != nil {
+		return r
+	}
+
+	expected, err := model.NewExpected(r.Type, r.Bundle)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if err := e.unmarshal(b, expected, len(r.VerifyOnly) > 0); err != nil {
+		r.Err = fmt.Errorf("unable to unmarshal fixture to %T: %w", expected, err)
+		return r
+	}
+	if len(r.VerifyOnly) == 0 {
+		rules := e.Rules
+		if rules == nil {
+			rules = DefaultRules
+		}
+		r.Violations = rules.Evaluate(expected)
+	}
+
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      e.BaseUrl,
+		DrupalEntity: r.Type,
+		DrupalBundle: r.Bundle,
+		Filter:       keyField,
+		Value:        r.Key,
+		Username:     e.Username,
+		Password:     e.Password,
+	}
+	if resolver != nil {
+		uuid, err := resolver.Resolve(e, fixture)
+		if err != nil {
+			r.Err = fmt.Errorf("unable to resolve the %s--%s of the fixture: %w", r.Type, r.Bundle, err)
+			return r
+		}
+		u.Filter, u.Value = "id", uuid
+		if r.Key == "" {
+			r.Key = uuid
+		}
+	}
+	generated, err := model.GenerateFixture(u)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	actual, err := normalize(generated)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if pid, ok := actual[pidKey].(string); ok && r.Pid == "" {
+		r.Pid = pid
+	}
+
+	keys := r.VerifyOnly
+	var defaulted map[string]bool
+	if len(keys) == 0 && r.Type == model.Node {
+		defaulted = e.addNodeCore(fixture, r.Bundle)
+	}
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(fixture))
+		for k := range fixture {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	c := &comparison{strictBooleans: e.StrictBooleans, unordered: map[string]bool{}}
+	for _, k := range e.UnorderedKeys {
+		c.unordered[k] = true
+	}
+	for _, k := range keys {
+		if k == "type" || k == "bundle" || k == VerifyOnlyKey {
+			continue
+		}
+		a, ok := actual[k]
+		if defaulted[k] && a == nil {
+			continue
+		}
+		if !ok {
+			r.Unverified = append(r.Unverified, k)
+			continue
+		}
+		r.Checked = append(r.Checked, k)
+		c.compare(k, fixture[k], a)
+	}
+	r.Mismatches, r.Drift = c.mismatches, c.drift
+	return r
+}
+
+// Adds the expected core flags of the bundle to the fixture of a node, unless the fixture carries them, answering the
+// keys added.  An added flag is verified only if the live entity carries it, so that a site not exposing the flags
+// to JSON:API is not failed by a default.
+func (e *Engine) addNodeCore(fixture map[string]interface{}, bundle string) map[string]bool {
+	nodeCore := e.NodeCore
+	if nodeCore == nil {
+		nodeCore = DefaultNodeCore
+	}
+	added := map[string]bool{}
+	for k, v := range nodeCore[bundle].Flags() {
+		if _, ok := fixture[k]; !ok {
+			fixture[k] = v
+			added[k] = true
+		}
+	}
+	return added
+}
+
+// Verifies the expected entity, which must be one of the types answered by model.Generatable
+func (e *Engine) Verify(expected model.ExpectedEntity) *Result {
+	b, err := json.Marshal(expected)
+	if err != nil {
+		return &Result{Type: expected.EntityType(), Bundle: expected.EntityBundle(), Err: err}
+	}
+	return e.VerifyJson(b)
+}
+
+// Unmarshals the fixture into its Expected struct, strictly if the Engine is Strict and the fixture is not partial
+func (e *Engine) unmarshal(b []byte, v interface{}, partial bool) error {
+	if e.Strict && !partial {
+		return model.UnmarshalStrict(b, v, VerifyOnlyKey)
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Verifies that no entity matches the filter of the ExpectedAbsent carried by the JSON document.  Each matching
+// entity is recorded as a Mismatch of the AbsentKey.
+func (e *Engine) verifyAbsent(b []byte, r *Result) {
+	absent := &model.ExpectedAbsent{}
+	if err := e.unmarshal(b, absent, false); err != nil {
+		r.Err = fmt.Errorf("unable to unmarshal fixture to %T: %w", absent, err)
+		return
+	}
+	r.Key = absent.Value
+	if r.Key == "" {
+		r.Err = fmt.Errorf("absent fixture of %s--%s carries no value", r.Type, r.Bundle)
+		return
+	}
+
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      e.BaseUrl,
+		DrupalEntity: r.Type,
+		DrupalBundle: r.Bundle,
+		Filter:       absent.Field(),
+		Value:        absent.Value,
+		Username:     e.Username,
+		Password:     e.Password,
+	}
+	var matches []interface{}
+	r.Err = u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			matches = append(matches, d["id"])
+		}
+		return nil
+	})
+	if r.Err == nil {
+		r.Checked = []string{model.AbsentKey}
+	}
+	if r.Err == nil && len(matches) > 0 {
+		r.Mismatches = append(r.Mismatches, Mismatch{Path: model.AbsentKey, Actual: matches})
+	}
+}
+
+// Answers the sorted keys listed by the VerifyOnlyKey of the fixture, or an error if the list is malformed or names a
+// key the fixture does not carry
+func verifyOnly(fixture map[string]interface{}) ([]string, error) {
+	v, ok := fixture[VerifyOnlyKey]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of keys, not %s", VerifyOnlyKey, describe(v))
+	}
+	keys := make([]string, 0, len(list))
+	for _, item := range list {
+		k, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a list of keys, not %s", VerifyOnlyKey, describe(v))
+		}
+		if _, present := fixture[k]; !present {
+			return nil, fmt.Errorf("%s names '%s', which the fixture does not carry", VerifyOnlyKey, k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Answers the generated fixture as it would be read from JSON, so that its values compare equal to those of an
+// authored fixture
+func normalize(generated map[string]interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(generated)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	return normalized, json.Unmarshal(b, &normalized)
+}
+
+// Compares the string values of particular fixture keys semantically rather than exactly, keyed by fixture key
+var semanticKeys = map[string]func(expected, actual string) bool{
+	"extent": EqualExtent,
+}
+
+// Compares the values of particular fixture keys semantically whatever their types, keyed by fixture key.  Lists of
+// these keys are still compared delta by delta.
+var semanticValues = map[string]func(expected, actual interface{}) bool{
+	"coordinates":    EqualPoint,
+	"authority":      equalLinkValue,
+	"finding_aid":    equalLinkValue,
+	"geoportal_link": equalLinkValue,
+	"catalog_link":   equalLinkValue,
+}
+
+// Answers true if the link-shaped values (e.g. of an authority link or finding aid) are equal: their `uri` members
+// after canonicalization (see EqualUri), and the other members of the expected value exactly (an empty member equals
+// an absent one)
+func equalLinkValue(expected, actual interface{}) bool {
+	e, eok := expected.(map[string]interface{})
+	a, aok := actual.(map[string]interface{})
+	if !eok || !aok {
+		return reflect.DeepEqual(expected, actual)
+	}
+	au, _ := a["uri"].(string)
+	for k, v := range e {
+		if k == "uri" {
+			if eu, _ := v.(string); !EqualUri(eu, au) {
+				return false
+			}
+		} else if !(isEmpty(v) && isEmpty(a[k])) && !reflect.DeepEqual(v, a[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Accumulates the differences between expected and actual values
+type comparison struct {
+	strictBooleans bool
+	// The fixture keys whose lists are compared regardless of order
+	unordered  map[string]bool
+	mismatches []Mismatch
+	drift      []Mismatch
+}
+
+// Compares an expected and actual value.  Maps are compared on the keys of the expected map only, lists delta by
+// delta (or regardless of order for unordered keys), and empty values (e.g. "" or []) are equal to absent values.
+func (c *comparison) compare(path string, expected, actual interface{}) {
+	if isEmpty(expected) && isEmpty(actual) {
+		return
+	}
+	if equal, ok := semanticValues[rootKey(path)]; ok {
+		if _, list := expected.([]interface{}); !list {
+			if !equal(expected, actual) {
+				c.mismatches = append(c.mismatches, Mismatch{Path: path, Expected: expected, Actual: actual})
+			}
+			return
+		}
+	}
+
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			c.compare(path+"."+k, e[k], a[k])
+		}
+		return
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		if c.unordered[rootKey(path)] {
+			c.compareUnordered(path, e, a)
+			return
+		}
+		for i := 0; i < len(e) || i < len(a); i++ {
+			c.compare(fmt.Sprintf("%s[%d]", path, i), at(e, i), at(a, i))
+		}
+		return
+	case bool:
+		if a, ok := ParseBool(actual); ok && a == e {
+			if _, same := actual.(bool); !same {
+				if c.strictBooleans {
+					c.mismatches = append(c.mismatches, Mismatch{Path: path, Expected: expected, Actual: actual})
+				} else {
+					c.drift = append(c.drift, Mismatch{Path: path, Expected: expected, Actual: actual})
+				}
+			}
+			return
+		}
+	}
+
+	if equal, ok := semanticKeys[rootKey(path)]; ok {
+		e, eok := expected.(string)
+		a, aok := actual.(string)
+		if eok && aok && equal(e, a) {
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		c.mismatches = append(c.mismatches, Mismatch{Path: path, Expected: expected, Actual: actual})
+	}
+}
+
+// Compares lists regardless of order: each expected value is matched with the first unmatched actual value equal to
+// it.  The values left unmatched are then paired in order of their deltas, and any left over are missing or
+// unexpected.
+func (c *comparison) compareUnordered(path string, expected, actual []interface{}) {
+	matched := make([]bool, len(actual))
+	var unmatched []int
+	for i, e := range expected {
+		found := false
+		for j, a := range actual {
+			if matched[j] {
+				continue
+			}
+			trial := &comparison{strictBooleans: c.strictBooleans, unordered: c.unordered}
+			trial.compare(path, e, a)
+			if len(trial.mismatches) == 0 {
+				matched[j], found = true, true
+				c.drift = append(c.drift, trial.drift...)
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, i)
+		}
+	}
+	var extra []int
+	for j := range actual {
+		if !matched[j] {
+			extra = append(extra, j)
+		}
+	}
+
+	for k := 0; k < len(unmatched) || k < len(extra); k++ {
+		switch {
+		case k >= len(extra):
+			i := unmatched[k]
+			c.mismatches = append(c.mismatches, Mismatch{Path: fmt.Sprintf("%s[%d]", path, i), Expected: expected[i]})
+		case k >= len(unmatched):
+			j := extra[k]
+			c.mismatches = append(c.mismatches, Mismatch{Path: fmt.Sprintf("%s[%d]", path, j), Actual: actual[j]})
+		default:
+			i, j := unmatched[k], extra[k]
+			m := Mismatch{Path: fmt.Sprintf("%s[%d]", path, i), Expected: expected[i], Actual: actual[j]}
+			if i != j {
+				m.ActualPath = fmt.Sprintf("%s[%d]", path, j)
+			}
+			c.mismatches = append(c.mismatches, m)
+		}
+	}
+}
+
+// Answers the value at the delta of the list, or nil if the list is shorter
+func at(list []interface{}, i int) interface{} {
+	if i < len(list) {
+		return list[i]
+	}
+	return nil
+}
+
+// Answers the fixture key of a path, e.g. `extent` from `extent[1]`
+func rootKey(path string) string {
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// Answers the value as JSON, or "nothing" if the value is absent
+func describe(v interface{}) string {
+	return diff.Describe(v)
+}
@@ -0,0 +1,354 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEngineServer() *jsonapitest.MockServer {
+	m := jsonapitest.NewMockServer()
+	m.Add(
+		jsonapitest.Resource{"type": "taxonomy_term--subject", "id": "s1", "attributes": map[string]interface{}{
+			"name": "Analog Photography", "field_unique_id": "s_1",
+			"description":          map[string]interface{}{"value": "<p>Analog</p>", "format": "basic_html", "processed": "<p>Analog</p>"},
+			"field_authority_link": []interface{}{map[string]interface{}{"uri": "http://id.loc.gov/1", "title": "LOC", "source": "lcsh"}},
+		}},
+		jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "g1", "attributes": map[string]interface{}{"name": "Maps"}},
+		jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "g2", "attributes": map[string]interface{}{"name": "Photographs"}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1",
+			"attributes": map[string]interface{}{"title": "Moonrise", "field_unique_id": "io_1", "field_pid": "islandora:1234"},
+			"relationships": map[string]interface{}{"field_genre": map[string]interface{}{"data": []interface{}{
+				map[string]interface{}{"type": "taxonomy_term--genre", "id": "g1"},
+				map[string]interface{}{"type": "taxonomy_term--genre", "id": "g2"},
+			}}},
+		},
+	)
+	return m
+}
+
+func Test_EngineVerifyJson(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+	e := NewEngine(m.URL, "", "")
+
+	r := e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography", "unique_id": "s_1",
+		"description": {"value": "<p>Analog</p>"}, "authority": [{"uri": "http://id.loc.gov/1", "title": "LOC", "source": "lcsh"}],
+		"translations": [{"langcode": "es", "name": "Fotografía analógica"}]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	assert.Equal(t, "Analog Photography", r.Key)
+	assert.Equal(t, []string{"translations"}, r.Unverified)
+	assert.Equal(t, []string{"authority", "description", "name", "unique_id"}, r.Checked)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "unique_id": "io_2",
+		"genre": ["Maps", "Photograph"], "publisher_country": ["United States"]}`))
+	require.Nil(t, r.Err)
+	assert.False(t, r.Passed())
+	require.Equal(t, 3, len(r.Mismatches))
+	assert.Equal(t, `genre[1]: expected "Photograph", got "Photographs"`, r.Mismatches[0].String())
+	assert.Equal(t, `publisher_country: expected ["United States"], got nothing`, r.Mismatches[1].String())
+	assert.Equal(t, `unique_id: expected "io_2", got "io_1"`, r.Mismatches[2].String())
+	assert.Equal(t, 2, len(r.Violations))
+
+	// lists differing in length are reported at each differing delta
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "genre": ["Maps"]}`))
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, `genre[1]: expected nothing, got "Photographs"`, r.Mismatches[0].String())
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise",
+		"genre": ["Photographs", "Maps", "Globes"]}`))
+	require.Equal(t, 3, len(r.Mismatches))
+	assert.Equal(t, `genre[0]: expected "Photographs", got "Maps"`, r.Mismatches[0].String())
+	assert.Equal(t, `genre[1]: expected "Maps", got "Photographs"`, r.Mismatches[1].String())
+	assert.Equal(t, `genre[2]: expected "Globes", got nothing`, r.Mismatches[2].String())
+
+	// the live entity does not exist
+	r = e.Verify(model.ExpectedSubject{ExpectedWithName: model.ExpectedWithName{
+		Expected: model.Expected{Type: model.TaxonomyTerm, Bundle: model.Subject}, Name: "Digital Photography"}})
+	assert.NotNil(t, r.Err)
+	assert.False(t, r.Passed())
+
+	r = e.VerifyJson([]byte(`{"type": "media", "bundle": "image", "name": "Thumbnail"}`))
+	assert.ErrorIs(t, r.Err, model.ErrUnsupported)
+	assert.NotNil(t, e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object"}`)).Err)
+	assert.NotNil(t, e.VerifyJson([]byte(`[]`)).Err)
+}
+
+func Test_EngineUnorderedKeys(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+	e := NewEngine(m.URL, "", "")
+	e.UnorderedKeys = []string{"genre"}
+
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "genre": ["Photographs", "Maps"]}`))
+	require.Nil(t, r.Err)
+	assert.Empty(t, r.Mismatches)
+
+	// unmatched values are reported at their deltas in the fixture and in the live entity
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "genre": ["Photographs", "Globes"]}`))
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, `genre[1]: expected "Globes", got "Maps" at genre[0]`, r.Mismatches[0].String())
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "genre": ["Photographs"]}`))
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, `genre[0]: expected nothing, got "Maps"`, r.Mismatches[0].String())
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "genre": ["Maps", "Photographs", "Globes"]}`))
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, `genre[2]: expected "Globes", got nothing`, r.Mismatches[0].String())
+}
+
+// The uris of authority and link keys are compared canonically (see EqualUri)
+func Test_EngineUris(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(
+		jsonapitest.Resource{"type": "taxonomy_term--subject", "id": "s1", "attributes": map[string]interface{}{
+			"name":                 "Analog Photography",
+			"field_authority_link": []interface{}{map[string]interface{}{"uri": "HTTP://id.loc.gov/authorities/subjects/sh85006645/", "title": "LOC", "source": "lcsh"}},
+		}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{
+			"title":                      "Moonrise",
+			"field_finding_aid":          []interface{}{map[string]interface{}{"uri": "https://aspace.library.jhu.edu/repositories/3/resources/1/", "title": "Finding aid"}},
+			"field_geoportal_link":       map[string]interface{}{"uri": "https://geoportal.library.jhu.edu/layer/%7e1", "title": ""},
+			"field_library_catalog_link": []interface{}{map[string]interface{}{"uri": "https://Catalyst.library.jhu.edu/catalog/bib_1", "title": ""}},
+		}},
+	)
+	e := NewEngine(m.URL, "", "")
+
+	r := e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography",
+		"authority": [{"uri": "http://id.loc.gov/authorities/subjects/sh85006645", "title": "LOC", "source": "lcsh"}]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise",
+		"finding_aid": [{"uri": "https://aspace.library.jhu.edu/repositories/3/resources/1", "title": "Finding aid"}],
+		"geoportal_link": {"uri": "https://geoportal.library.jhu.edu/layer/~1"},
+		"catalog_link": [{"uri": "https://catalyst.library.jhu.edu/catalog/bib_1"}]}`))
+	require.Nil(t, r.Err)
+	assert.Empty(t, r.Mismatches)
+
+	// members other than the uri are still compared, as are the uris themselves
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography",
+		"authority": [{"uri": "http://id.loc.gov/authorities/subjects/sh85006645", "source": "viaf"}]}`))
+	require.Nil(t, r.Err)
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, "authority[0]", r.Mismatches[0].Path)
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise",
+		"finding_aid": [{"uri": "https://aspace.library.jhu.edu/repositories/3/resources/2"}],
+		"geoportal_link": {"uri": "https://geoportal.library.jhu.edu/layer/2"}}`))
+	require.Nil(t, r.Err)
+	require.Equal(t, 2, len(r.Mismatches))
+	assert.Equal(t, "finding_aid[0]", r.Mismatches[0].Path)
+	assert.Equal(t, "geoportal_link", r.Mismatches[1].Path)
+}
+
+func Test_EngineVerifyOnly(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+	e := NewEngine(m.URL, "", "")
+
+	// the mismatched unique_id and the rule violations of publisher_country are disregarded
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "unique_id": "io_2",
+		"genre": ["Maps", "Photographs"], "publisher_country": ["United States"], "verify_only": ["genre"]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v %v", r.Mismatches, r.Violations)
+	assert.Equal(t, []string{"genre"}, r.VerifyOnly)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "unique_id": "io_2",
+		"genre": ["Maps"], "verify_only": ["unique_id", "genre"]}`))
+	require.Nil(t, r.Err)
+	require.Equal(t, 2, len(r.Mismatches))
+	assert.Equal(t, "genre[1]", r.Mismatches[0].Path)
+	assert.Equal(t, "unique_id", r.Mismatches[1].Path)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "verify_only": ["genre"]}`))
+	assert.NotNil(t, r.Err)
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "verify_only": "title"}`))
+	assert.NotNil(t, r.Err)
+}
+
+func Test_EngineNodeCore(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{
+			"title": "Moonrise", "promote": true, "sticky": false}},
+		jsonapitest.Resource{"type": "node--collection_object", "id": "c1", "attributes": map[string]interface{}{
+			"title": "Ansel Adams", "promote": false, "sticky": true}},
+	)
+	e := NewEngine(m.URL, "", "")
+
+	// migrated nodes are verified as neither promoted nor sticky by default
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise"}`))
+	require.Nil(t, r.Err)
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, "promote: expected false, got true", r.Mismatches[0].String())
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "collection_object", "title": "Ansel Adams"}`))
+	require.Nil(t, r.Err)
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, "sticky", r.Mismatches[0].Path)
+
+	// a fixture's own flags win, and only listed keys are verified
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "promote": true}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "verify_only": ["title"]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	// per-bundle expected values
+	promoted := true
+	e.NodeCore = map[string]model.ExpectedNodeCore{model.RepositoryObject: {Promote: &promoted}}
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "collection_object", "title": "Ansel Adams"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	expected := &model.ExpectedRepoObj{}
+	require.Nil(t, json.Unmarshal([]byte(`{"promote": false}`), expected))
+	assert.Equal(t, map[string]interface{}{"promote": false}, expected.Flags())
+}
+
+func Test_EngineExtraFields(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{
+		"title": "Moonrise", "field_rights_note": "Public domain"}})
+	e := NewEngine(m.URL, "", "")
+	fixture := []byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "rights_note": "In copyright"}`)
+
+	// unregistered keys are invalid, or cannot be verified if fixtures are not validated
+	r := e.VerifyJson(fixture)
+	assert.True(t, errors.Is(r.Err, model.ErrInvalidFixture))
+	e.SkipValidation = true
+	r = e.VerifyJson(fixture)
+	require.Nil(t, r.Err)
+	assert.Equal(t, []string{"rights_note"}, r.Unverified)
+	e.SkipValidation = false
+
+	model.MustRegisterField(model.Node, model.RepositoryObject, model.ExtraField{Key: "rights_note",
+		Generate: model.FromAttribute("field_rights_note")})
+	defer model.UnregisterFields(model.Node, model.RepositoryObject)
+	r = e.VerifyJson(fixture)
+	require.Nil(t, r.Err)
+	assert.Empty(t, r.Unverified)
+	require.Equal(t, 1, len(r.Mismatches), "%v", r.Mismatches)
+	assert.Equal(t, `rights_note: expected "In copyright", got "Public domain"`, r.Mismatches[0].String())
+}
+
+func Test_EngineStrict(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+	e := NewEngine(m.URL, "", "")
+	partial := []byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography", "unique_id": "s_1",
+		"description": {"value": "<p>Analog</p>"}}`)
+
+	// a fixture omitting fields of its Expected struct expects their zero values, unless it is unmarshaled strictly
+	r := e.VerifyJson(partial)
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	e.Strict = true
+	r = e.VerifyJson(partial)
+	require.True(t, errors.Is(r.Err, jsonapi.ErrStrict))
+	for _, field := range []string{"$.authority", "$.description.format", "$.description.processed"} {
+		assert.Contains(t, r.Err.Error(), field+": never populated")
+	}
+
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography", "unique_id": "s_1",
+		"description": {"value": "<p>Analog</p>", "format": "basic_html", "processed": "<p>Analog</p>"},
+		"authority": [{"uri": "http://id.loc.gov/1", "title": "LOC", "source": "lcsh"}]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	// partial fixtures are unmarshaled leniently
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Analog Photography", "unique_id": "s_1",
+		"verify_only": ["unique_id"]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "absent": true, "value": "Withdrawn"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed())
+}
+
+func Test_EngineVerifyAbsent(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+	e := NewEngine(m.URL, "", "")
+
+	r := e.Verify(model.ExpectedAbsent{Expected: model.Expected{Type: "node", Bundle: "islandora_object"}, Value: "Withdrawn Letter"})
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed())
+	assert.Equal(t, "Withdrawn Letter", r.Key)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "absent": true, "value": "Moonrise"}`))
+	require.Nil(t, r.Err)
+	assert.False(t, r.Passed())
+	assert.Equal(t, `absent: expected nothing, got ["n1"]`, r.Mismatches[0].String())
+	assert.Equal(t, []string{"absent"}, r.Checked)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "absent": true, "filter": "field_unique_id", "value": "io_1"}`))
+	require.Nil(t, r.Err)
+	assert.False(t, r.Passed())
+
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "genre", "absent": true, "value": "Posters"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed())
+
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "genre", "absent": true}`))
+	assert.NotNil(t, r.Err)
+}
+
+func Test_EngineVerifyPid(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+	e := NewEngine(m.URL, "", "")
+
+	// the PID of the live entity is reported, though the fixture carries none
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "genre": ["Maps", "Photographs"]}`))
+	require.Nil(t, r.Err)
+	assert.Equal(t, "islandora:1234", r.Pid)
+
+	// a fixture lacking a title is resolved by its PID
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "pid": "islandora:1234", "unique_id": "io_1"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	assert.Equal(t, "islandora:1234", r.Key)
+	assert.Equal(t, "islandora:1234", r.Pid)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "pid": "islandora:9999"}`))
+	assert.NotNil(t, r.Err)
+	assert.Equal(t, "islandora:9999", r.Pid)
+}
+
+func Test_EngineVerifyDir(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+
+	dir := t.TempDir()
+	require.Nil(t, os.Mkdir(filepath.Join(dir, "terms"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "terms", "genre.json"), []byte(`{"type": "taxonomy_term", "bundle": "genre", "name": "Maps"}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "object.json"), []byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise"}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte(`not a fixture`), 0644))
+
+	results, err := NewEngine(m.URL, "", "").VerifyDir(dir)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(results))
+	assert.Equal(t, filepath.Join(dir, "object.json"), results[0].Fixture)
+	assert.Equal(t, "Maps", results[1].Key)
+	assert.True(t, results[0].Passed())
+	assert.True(t, results[1].Passed())
+
+	assert.NotNil(t, NewEngine(m.URL, "", "").VerifyFile(filepath.Join(dir, "missing.json")).Err)
+}
@@ -0,0 +1,85 @@
+package verify
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The components of an extent or physical description, e.g. `3 linear feet (5 boxes) : gelatin silver ; 20 x 25 cm`.
+// Components are normalized: lower-cased, with whitespace collapsed and trailing punctuation removed.
+type Extent struct {
+	// The extent as supplied
+	Raw string
+	// The leading quantity, e.g. 3; zero if the extent has none
+	Count float64
+	// Whether the extent has a leading quantity
+	Counted bool
+	// The unit following the quantity, e.g. `linear feet`, or the entire extent if it has no structure
+	Unit string
+	// The parenthetical qualifier, e.g. `5 boxes`
+	Qualifier string
+	// Other physical details following a colon, e.g. `gelatin silver`
+	Details string
+	// Dimensions following a semicolon, e.g. `20 x 25 cm`
+	Dimensions string
+}
+
+var (
+	extentCount     = regexp.MustCompile(`^(\d+(?:[.,]\d+)*)\s*(.*)$`)
+	extentQualifier = regexp.MustCompile(`\(([^)]*)\)`)
+	trailingPunct   = regexp.MustCompile(`[\s.,;:]+$`)
+)
+
+// Parses an extent into its components.  Extents lacking structure are answered with their normalized value as the
+// Unit, so that they still compare semantically.
+func ParseExtent(s string) Extent {
+	e := Extent{Raw: s}
+	rest := s
+	if i := strings.Index(rest, ";"); i >= 0 {
+		e.Dimensions = normalizeExtent(rest[i+1:])
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, ":"); i >= 0 {
+		e.Details = normalizeExtent(rest[i+1:])
+		rest = rest[:i]
+	}
+	if m := extentQualifier.FindStringSubmatch(rest); m != nil {
+		e.Qualifier = normalizeExtent(m[1])
+		rest = strings.Replace(rest, m[0], " ", 1)
+	}
+	rest = normalizeExtent(rest)
+	if m := extentCount.FindStringSubmatch(rest); m != nil {
+		if count, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64); err == nil {
+			e.Count, e.Counted = count, true
+			rest = m[2]
+		}
+	}
+	e.Unit = rest
+	return e
+}
+
+// Parses each value of a multi-valued extent
+func ParseExtents(values []string) []Extent {
+	extents := make([]Extent, 0, len(values))
+	for _, v := range values {
+		extents = append(extents, ParseExtent(v))
+	}
+	return extents
+}
+
+// Answers true if the extents carry the same components, disregarding case, whitespace and trailing punctuation, e.g.
+// `2 boxes` and `2 Boxes.`
+func (e Extent) Equal(other Extent) bool {
+	return e.Count == other.Count && e.Counted == other.Counted && e.Unit == other.Unit &&
+		e.Qualifier == other.Qualifier && e.Details == other.Details && e.Dimensions == other.Dimensions
+}
+
+// Answers true if the extents are semantically equal (see Extent.Equal)
+func EqualExtent(expected, actual string) bool {
+	return ParseExtent(expected).Equal(ParseExtent(actual))
+}
+
+func normalizeExtent(s string) string {
+	return strings.ToLower(trailingPunct.ReplaceAllString(NormalizeText(s), ""))
+}
@@ -0,0 +1,52 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseExtent(t *testing.T) {
+	assert.Equal(t, Extent{Raw: "3 Linear Feet (5 boxes) : gelatin silver ; 20 x 25 cm.", Count: 3, Counted: true, Unit: "linear feet",
+		Qualifier: "5 boxes", Details: "gelatin silver", Dimensions: "20 x 25 cm"},
+		ParseExtent("3 Linear Feet (5 boxes) : gelatin silver ; 20 x 25 cm."))
+	assert.Equal(t, Extent{Raw: "1,200 photographs", Count: 1200, Counted: true, Unit: "photographs"}, ParseExtent("1,200 photographs"))
+	assert.Equal(t, Extent{Raw: "0.5 cubic feet", Count: 0.5, Counted: true, Unit: "cubic feet"}, ParseExtent("0.5 cubic feet"))
+	assert.Equal(t, Extent{Raw: " Various sizes. ", Unit: "various sizes"}, ParseExtent(" Various sizes. "))
+	assert.Equal(t, 2, len(ParseExtents([]string{"2 boxes", "1 folder"})))
+
+	assert.True(t, EqualExtent("2 boxes", "2 boxes."))
+	assert.True(t, EqualExtent("2  Boxes", "2 boxes"))
+	assert.True(t, EqualExtent("1 photograph: b&w;  8 x 10 in.", "1 photograph : b&w ; 8 x 10 in"))
+	assert.False(t, EqualExtent("2 boxes", "3 boxes"))
+	assert.False(t, EqualExtent("2 boxes", "2 folders"))
+	assert.False(t, EqualExtent("1 photograph ; 8 x 10 in", "1 photograph ; 4 x 5 in"))
+
+	assert.True(t, AssertExtents(t, []string{"2 boxes", "1 folder."}, []string{"2 boxes.", "1 folder"}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertExtents(rec, []string{"2 boxes"}, []string{"2 boxes", "1 folder"}))
+	assert.Contains(t, rec.String(), `extent count differs: expected ["2 boxes"], got ["2 boxes" "1 folder"]`)
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertExtents(rec, []string{"2 boxes"}, []string{"1 folder"}))
+	assert.Contains(t, rec.String(), `extent 0 differs: expected "2 boxes", got "1 folder"`)
+}
+
+func Test_EngineExtent(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "attributes": map[string]interface{}{"title": "Moonrise", "field_extent": []interface{}{"2 boxes."}}})
+	e := NewEngine(m.URL, "", "")
+
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "extent": ["2 Boxes"]}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "extent": ["3 boxes"]}`))
+	assert.Equal(t, "extent[0]", r.Mismatches[0].Path)
+}
@@ -0,0 +1,22 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Asserts that at least one FITS technical metadata media is a media of the repository object with the supplied
+// title, and answers the FITS media that were found.  FITS media are generated asynchronously by Islandora, so callers
+// verifying freshly ingested objects may need to wait before invoking this function.
+func AssertFitsMediaOf(t *testing.T, baseUrl, title string) *model.JsonApiFitsMedia {
+	res := &model.JsonApiFitsMedia{}
+	jsonapi.MediaOfUrl(t, baseUrl, model.Fits, title).Get(res)
+	assert.NotEmpty(t, res.JsonApiData, "no FITS technical metadata media found for repository object '%s'", title)
+	return res
+}
@@ -0,0 +1,26 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertFitsMediaOf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/jsonapi/media/fits_technical_metadata", r.URL.Path)
+		require.Equal(t, "Moonrise", r.URL.Query().Get("filter[field_media_of.title]"))
+		w.Write([]byte(`{"data": [{"type": "media--fits_technical_metadata", "id": "f1", "attributes": {"field_mime_type": "application/xml"}}]}`))
+	}))
+	defer server.Close()
+
+	fits := AssertFitsMediaOf(t, server.URL, "Moonrise")
+	require.Equal(t, 1, len(fits.JsonApiData))
+	assert.Equal(t, "application/xml", fits.JsonApiData[0].JsonApiAttributes.MimeType)
+}
@@ -0,0 +1,87 @@
+package verify
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// The largest difference, in degrees, between the latitudes (or longitudes) of points that compare equal.  The default
+// of 0.000001 degrees is about 11 cm at the equator, below the precision at which coordinates are recorded.
+var GeoTolerance = 0.000001
+
+// A geographic point in WGS 84 coordinates
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+var (
+	wktPoint = regexp.MustCompile(`(?i)^\s*(?:srid=\d+\s*;\s*)?point\s*\(\s*(\S+)\s+(\S+)\s*\)\s*$`)
+	latLon   = regexp.MustCompile(`^\s*([^,\s]+)\s*,\s*([^,\s]+)\s*$`)
+)
+
+// Parses a point from any of the representations of a geofield, which differ by serializer and environment:
+//   - a WKT string, e.g. `POINT (-76.62 39.33)`, whose longitude precedes its latitude
+//   - a lat,lon string, e.g. `39.33,-76.62`
+//   - an object carrying `lat` and `lon` (or `lng`), as numbers or numeric strings
+//   - an object carrying a WKT `value`, e.g. a geofield lacking its computed `lat` and `lon`
+//   - a list of exactly one of the above, e.g. a multi-valued geofield
+func ParsePoint(v interface{}) (Point, error) {
+	switch p := v.(type) {
+	case string:
+		if m := wktPoint.FindStringSubmatch(p); m != nil {
+			return parseCoordinates(p, m[2], m[1])
+		}
+		if m := latLon.FindStringSubmatch(p); m != nil {
+			return parseCoordinates(p, m[1], m[2])
+		}
+	case map[string]interface{}:
+		lon, ok := p["lon"]
+		if !ok {
+			lon = p["lng"]
+		}
+		if lat, ok := p["lat"]; ok && lat != nil && lon != nil {
+			return parseCoordinates(p, fmt.Sprint(lat), fmt.Sprint(lon))
+		}
+		if wkt, ok := p["value"].(string); ok {
+			return ParsePoint(wkt)
+		}
+	case []interface{}:
+		if len(p) == 1 {
+			return ParsePoint(p[0])
+		}
+	}
+	return Point{}, fmt.Errorf("unable to parse a point from %s", describe(v))
+}
+
+func parseCoordinates(v interface{}, lat, lon string) (Point, error) {
+	la, laErr := strconv.ParseFloat(lat, 64)
+	lo, loErr := strconv.ParseFloat(lon, 64)
+	if laErr != nil || loErr != nil || math.Abs(la) > 90 || math.Abs(lo) > 180 {
+		return Point{}, fmt.Errorf("unable to parse a point from %s: invalid coordinates", describe(v))
+	}
+	return Point{Lat: la, Lon: lo}, nil
+}
+
+// Answers true if the latitudes and the longitudes of the points differ by no more than the tolerance, in degrees
+func (p Point) Near(other Point, tolerance float64) bool {
+	return math.Abs(p.Lat-other.Lat) <= tolerance && math.Abs(p.Lon-other.Lon) <= tolerance
+}
+
+// Answers the point in WKT, e.g. `POINT (-76.62 39.33)`
+func (p Point) String() string {
+	return fmt.Sprintf("POINT (%s %s)", strconv.FormatFloat(p.Lon, 'f', -1, 64), strconv.FormatFloat(p.Lat, 'f', -1, 64))
+}
+
+// Answers true if both values parse as points (see ParsePoint) within GeoTolerance of each other, so that e.g. the WKT
+// `POINT (-76.62 39.33)` equals the object `{"lat": 39.33, "lon": -76.62}`
+func EqualPoint(expected, actual interface{}) bool {
+	e, err := ParsePoint(expected)
+	if err != nil {
+		return false
+	}
+	a, err := ParsePoint(actual)
+	return err == nil && e.Near(a, GeoTolerance)
+}
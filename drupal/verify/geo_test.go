@@ -0,0 +1,84 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParsePoint(t *testing.T) {
+	baltimore := Point{Lat: 39.33, Lon: -76.62}
+	for _, v := range []interface{}{
+		"POINT (-76.62 39.33)",
+		"point(-76.62 39.33)",
+		"SRID=4326;POINT (-76.62 39.33)",
+		"39.33,-76.62",
+		"39.33, -76.62",
+		map[string]interface{}{"lat": 39.33, "lon": -76.62},
+		map[string]interface{}{"lat": "39.33", "lng": "-76.62"},
+		map[string]interface{}{"value": "POINT (-76.62 39.33)", "geo_type": "Point"},
+		[]interface{}{map[string]interface{}{"value": "POINT (-76.62 39.33)", "lat": 39.33, "lon": -76.62}},
+	} {
+		p, err := ParsePoint(v)
+		assert.Nil(t, err, "%v: %s", v, err)
+		assert.Equal(t, baltimore, p, "%v", v)
+	}
+
+	for _, v := range []interface{}{
+		"Baltimore", "POINT (-76.62)", "LINESTRING (30 10, 10 30)", "39.33,-200", "91,0", 39.33,
+		map[string]interface{}{"lat": 39.33}, []interface{}{"39.33,-76.62", "0,0"}, nil,
+	} {
+		_, err := ParsePoint(v)
+		assert.NotNil(t, err, "%v", v)
+	}
+
+	assert.Equal(t, "POINT (-76.62 39.33)", baltimore.String())
+}
+
+func Test_EqualPoint(t *testing.T) {
+	assert.True(t, EqualPoint("POINT (-76.62 39.33)", map[string]interface{}{"lat": 39.33, "lon": -76.62}))
+	assert.True(t, EqualPoint("39.33,-76.62", "POINT (-76.6200004 39.3299996)"))
+	assert.False(t, EqualPoint("POINT (-76.62 39.33)", "POINT (-76.62 39.34)"))
+	assert.False(t, EqualPoint("POINT (-76.62 39.33)", "POINT (39.33 -76.62)"))
+	assert.False(t, EqualPoint("POINT (-76.62 39.33)", nil))
+
+	assert.True(t, AssertPoint(t, "POINT (-76.62 39.33)", map[string]interface{}{"lat": "39.33", "lon": "-76.62"}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertPoint(rec, "POINT (-76.62 39.33)", "POINT (-76.62 39.34)"))
+	assert.Contains(t, rec.String(), "point differs: expected POINT (-76.62 39.33), got POINT (-76.62 39.34)")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertPoint(rec, "Baltimore", "POINT (-76.62 39.33)"))
+	assert.Contains(t, rec.String(), `expected point: unable to parse a point from "Baltimore"`)
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertPoint(rec, "POINT (-76.62 39.33)", "Baltimore"))
+	assert.Contains(t, rec.String(), `actual point: unable to parse a point from "Baltimore"`)
+}
+
+func Test_EngineCoordinates(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "taxonomy_term--geo_location", "attributes": map[string]interface{}{
+		"name":              "Baltimore",
+		"field_coordinates": map[string]interface{}{"value": "POINT (-76.62 39.33)", "lat": 39.33, "lon": -76.62},
+	}})
+	e := NewEngine(m.URL, "", "")
+
+	r := e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "geo_location", "name": "Baltimore", "coordinates": "POINT (-76.62 39.33)"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "geo_location", "name": "Baltimore", "coordinates": {"lat": 39.33, "lon": -76.62}}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "geo_location", "name": "Baltimore", "coordinates": "POINT (-76.61 39.29)"}`))
+	require.Nil(t, r.Err)
+	require.Equal(t, 1, len(r.Mismatches))
+	assert.Equal(t, "coordinates", r.Mismatches[0].Path)
+}
@@ -0,0 +1,6 @@
+package verify
+
+// Answers a resource identifier object, e.g. of the data of a relationship
+func ref(drupalType, id string) map[string]interface{} {
+	return map[string]interface{}{"type": drupalType, "id": id}
+}
@@ -0,0 +1,182 @@
+package verify
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The relationships checked by an IntegrityChecker if it names none
+var DefaultReferenceFields = []string{
+	"field_member_of",
+	"field_access_terms",
+	"field_linked_agent",
+	"field_creator",
+	"field_contributor",
+	"field_subject",
+	"field_genre",
+}
+
+// The resource identifier Drupal answers for a reference to an entity that no longer exists
+const missingId = "missing"
+
+// A reference from an entity to another entity, carried by a relationship
+type Reference struct {
+	SourceType  string
+	SourceId    string
+	SourceTitle string
+	// The relationship carrying the reference, e.g. `field_member_of`
+	Field      string
+	TargetType string
+	TargetId   string
+}
+
+// A reference whose target does not exist
+type DanglingReference struct {
+	Reference
+	Reason string
+}
+
+// Answers the dangling reference as e.g.
+// `node--islandora_object "Moonrise" (n1) field_subject -> taxonomy_term--subject s2: not found`
+func (d DanglingReference) String() string {
+	return fmt.Sprintf("%s %q (%s) %s -> %s %s: %s", d.SourceType, d.SourceTitle, d.SourceId, d.Field, d.TargetType, d.TargetId, d.Reason)
+}
+
+// Confirms that the references of every entity of a bundle resolve to existing entities, catching the corruption of a
+// partial migration that per-entity tests miss.  Each distinct target is retrieved once.
+type IntegrityChecker struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The relationships to check; DefaultReferenceFields if empty
+	Fields []string
+
+	exists map[string]bool
+}
+
+// Creates an IntegrityChecker for the Drupal site at the base url
+func NewIntegrityChecker(baseUrl, username, password string) *IntegrityChecker {
+	return &IntegrityChecker{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the dangling references of every entity of the entity type and bundle (e.g. `node` and
+// `islandora_object`), ordered by source entity and field.  An error is answered if the entities or the targets of
+// their references cannot be retrieved.
+func (c *IntegrityChecker) CheckBundle(entityType, bundle string) ([]DanglingReference, error) {
+	fields := c.Fields
+	if len(fields) == 0 {
+		fields = DefaultReferenceFields
+	}
+	if c.exists == nil {
+		c.exists = map[string]bool{}
+	}
+
+	var references []Reference
+	u := c.url(entityType, bundle)
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			references = append(references, referencesOf(d, fields)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// references are ordered by source and field, their targets remaining in the order of the relationship
+	sort.SliceStable(references, func(i, j int) bool {
+		if references[i].SourceId != references[j].SourceId {
+			return references[i].SourceId < references[j].SourceId
+		}
+		return references[i].Field < references[j].Field
+	})
+	var dangling []DanglingReference
+	for _, ref := range references {
+		if ref.TargetId == missingId || ref.TargetId == "" {
+			dangling = append(dangling, DanglingReference{Reference: ref, Reason: "the referenced entity was deleted"})
+			continue
+		}
+		ok, err := c.exist(ref.TargetType, ref.TargetId)
+		if err != nil {
+			return dangling, err
+		}
+		if !ok {
+			dangling = append(dangling, DanglingReference{Reference: ref, Reason: "not found"})
+		}
+	}
+	return dangling, nil
+}
+
+// Answers whether the entity exists, retrieving it if it has not been retrieved already
+func (c *IntegrityChecker) exist(drupalType, id string) (bool, error) {
+	key := drupalType + "/" + id
+	if ok, checked := c.exists[key]; checked {
+		return ok, nil
+	}
+	t := jsonapi.DrupalType(drupalType)
+	u, err := c.url(t.Entity(), t.Bundle()).Url()
+	if err != nil {
+		return false, err
+	}
+	res, _, err := jsonapi.FetchResource(u+"/"+id, c.Username, c.Password.Reveal())
+	switch {
+	case err == nil:
+		c.exists[key] = true
+	case res != nil && res.StatusCode == http.StatusNotFound:
+		c.exists[key] = false
+	default:
+		return false, err
+	}
+	return c.exists[key], nil
+}
+
+func (c *IntegrityChecker) url(entityType, bundle string) *jsonapi.JsonApiUrl {
+	return &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: entityType,
+		DrupalBundle: bundle,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+}
+
+// Answers the references carried by the named relationships of the resource object, ordered by field
+func referencesOf(data map[string]interface{}, fields []string) []Reference {
+	source := Reference{}
+	source.SourceType, _ = data["type"].(string)
+	source.SourceId, _ = data["id"].(string)
+	attributes, _ := data["attributes"].(map[string]interface{})
+	if title, ok := attributes["title"].(string); ok {
+		source.SourceTitle = title
+	} else {
+		source.SourceTitle, _ = attributes["name"].(string)
+	}
+
+	sorted := append([]string(nil), fields...)
+	sort.Strings(sorted)
+	relationships, _ := data["relationships"].(map[string]interface{})
+	var references []Reference
+	for _, field := range sorted {
+		rel, _ := relationships[field].(map[string]interface{})
+		var refs []interface{}
+		switch d := rel["data"].(type) {
+		case map[string]interface{}:
+			refs = []interface{}{d}
+		case []interface{}:
+			refs = d
+		}
+		for _, r := range refs {
+			r, _ := r.(map[string]interface{})
+			ref := source
+			ref.Field = field
+			ref.TargetType, _ = r["type"].(string)
+			ref.TargetId, _ = r["id"].(string)
+			references = append(references, ref)
+		}
+	}
+	return references
+}
@@ -0,0 +1,85 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IntegrityChecker(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	m.Add(
+		jsonapitest.Resource{"type": "node--collection_object", "id": "c1", "attributes": map[string]interface{}{"title": "Ansel Adams Images"}},
+		jsonapitest.Resource{"type": "taxonomy_term--subject", "id": "s1", "attributes": map[string]interface{}{"name": "Analog Photography"}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{"title": "Moonrise"},
+			"relationships": map[string]interface{}{
+				"field_member_of": map[string]interface{}{"data": []interface{}{ref("node--collection_object", "c1")}},
+				"field_subject":   map[string]interface{}{"data": []interface{}{ref("taxonomy_term--subject", "s1"), ref("taxonomy_term--subject", "s2")}},
+				"field_model":     map[string]interface{}{"data": ref("taxonomy_term--islandora_models", "unchecked")},
+			}},
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n2", "attributes": map[string]interface{}{"title": "Moonset"},
+			"relationships": map[string]interface{}{
+				"field_member_of": map[string]interface{}{"data": []interface{}{ref("node--collection_object", "c1")}},
+				"field_genre":     map[string]interface{}{"data": []interface{}{ref("unknown", "missing")}},
+			}},
+	)
+
+	c := NewIntegrityChecker(m.URL, "", "")
+	dangling, err := c.CheckBundle("node", "islandora_object")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(dangling))
+	assert.Equal(t, `node--islandora_object "Moonrise" (n1) field_subject -> taxonomy_term--subject s2: not found`, dangling[0].String())
+	assert.Equal(t, "field_genre", dangling[1].Field)
+	assert.Equal(t, "Moonset", dangling[1].SourceTitle)
+
+	// the collection is retrieved once, although it is referenced twice
+	collectionRequests := 0
+	for _, r := range m.Requests() {
+		if r.Path == "/jsonapi/node/collection_object/c1" {
+			collectionRequests++
+		}
+	}
+	assert.Equal(t, 1, collectionRequests)
+
+	rec := &asserttest.Recorder{}
+	assert.False(t, c.AssertBundle(rec, "node", "islandora_object"))
+	assert.Contains(t, rec.String(), "field_subject -> taxonomy_term--subject s2: not found")
+	c.Fields = []string{"field_member_of"}
+	assert.True(t, c.AssertBundle(t, "node", "islandora_object"))
+	rec = &asserttest.Recorder{}
+	assert.False(t, NewIntegrityChecker("", "", "").AssertBundle(rec, "node", "islandora_object"))
+	assert.Contains(t, rec.String(), "base url must not be empty")
+}
+
+// Dangling references are ordered by source and field, whatever order Drupal answers the sources in
+func Test_IntegrityCheckerOrdering(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	dangling := func(id string) jsonapitest.Resource {
+		return jsonapitest.Resource{"type": "node--islandora_object", "id": id, "relationships": map[string]interface{}{
+			"field_subject":   map[string]interface{}{"data": []interface{}{ref("taxonomy_term--subject", "s2"), ref("taxonomy_term--subject", "s1")}},
+			"field_member_of": map[string]interface{}{"data": []interface{}{ref("node--collection_object", "c1")}},
+		}}
+	}
+	m.Add(dangling("n2"), dangling("n1"))
+
+	references, err := NewIntegrityChecker(m.URL, "", "").CheckBundle("node", "islandora_object")
+	require.Nil(t, err)
+	var order []string
+	for _, r := range references {
+		order = append(order, r.SourceId+" "+r.Field+" "+r.TargetId)
+	}
+	assert.Equal(t, []string{
+		"n1 field_member_of c1", "n1 field_subject s2", "n1 field_subject s1",
+		"n2 field_member_of c1", "n2 field_subject s2", "n2 field_subject s1",
+	}, order)
+}
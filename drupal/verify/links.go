@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// Answers true if the expected and actual authority links are equal in number and order, and each pair of authority
+// links has the same source and canonically equal URIs
+func EqualAuthorities(expected, actual []model.Authority, opts ...UriOption) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if expected[i].Source != actual[i].Source || !EqualUri(expected[i].Uri, actual[i].Uri, opts...) {
+			return false
+		}
+	}
+	return true
+}
+
+// Answers the canonical form of a link URI.  URIs addressing the Drupal site itself are answered as an `internal:`
+// path, so that `entity:node/1` equals `internal:/node/1` and `route:<front>` equals `internal:/`; other URIs are
+// canonicalized by CanonicalUri.
+func CanonicalLinkUri(uri string, opts ...UriOption) string {
+	link := model.Link{Uri: strings.TrimSpace(uri)}
+	rest := link.Uri[strings.Index(link.Uri, ":")+1:]
+	switch link.Scheme() {
+	case model.InternalScheme, model.EntityScheme:
+		return model.InternalScheme + ":/" + strings.Trim(rest, "/")
+	case model.RouteScheme:
+		if rest == "<front>" {
+			return model.InternalScheme + ":/"
+		}
+		return link.Uri
+	}
+	return CanonicalUri(uri, opts...)
+}
+
+// Answers true if the links have canonically equal URIs (see CanonicalLinkUri) and equal titles.  The title and
+// options of the actual link are only compared if the expected link carries them.
+func EqualLink(expected, actual model.Link, opts ...UriOption) bool {
+	if CanonicalLinkUri(expected.Uri, opts...) != CanonicalLinkUri(actual.Uri, opts...) {
+		return false
+	}
+	if expected.Title != "" && NormalizeText(expected.Title) != NormalizeText(actual.Title) {
+		return false
+	}
+	return len(expected.Options) == 0 || reflect.DeepEqual(expected.Options, actual.Options)
+}
@@ -0,0 +1,99 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var ErrUnsupportedVideo = errors.New("unsupported remote video url")
+
+// oEmbed endpoints of the supported remote video providers, keyed by provider name
+var oembedEndpoints = map[string]string{
+	"youtube": "https://www.youtube.com/oembed",
+	"vimeo":   "https://vimeo.com/api/oembed.json",
+}
+
+// Matches a Vimeo video id; other segments of Vimeo urls name channels, groups, users and the like
+var vimeoId = regexp.MustCompile(`^[0-9]+$`)
+
+// The subset of an oEmbed response (https://oembed.com/#section2.3) used for verification
+type Oembed struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	Html         string `json:"html"`
+}
+
+// Answers the canonical form of a YouTube or Vimeo video URL, which is the form Drupal's oEmbed media source stores:
+//   - `https://www.youtube.com/watch?v=<id>` for YouTube URLs, including `youtu.be/<id>` and `youtube.com/embed/<id>`
+//   - `https://vimeo.com/<id>` for Vimeo URLs, including `player.vimeo.com/video/<id>`
+//
+// An error wrapping ErrUnsupportedVideo is answered for URLs of any other provider.
+func CanonicalVideoUrl(videoUrl string) (string, error) {
+	provider, id, err := parseVideoUrl(videoUrl)
+	if err != nil {
+		return "", err
+	}
+	switch provider {
+	case "youtube":
+		return "https://www.youtube.com/watch?v=" + id, nil
+	default:
+		return "https://vimeo.com/" + id, nil
+	}
+}
+
+// Answers the provider ('youtube' or 'vimeo') and the provider's identifier for the video
+func parseVideoUrl(videoUrl string) (provider, id string, err error) {
+	u, err := url.Parse(strings.TrimSpace(videoUrl))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s: %s", ErrUnsupportedVideo, videoUrl, err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case host == "youtu.be" && segments[0] != "":
+		return "youtube", segments[0], nil
+	case (host == "youtube.com" || host == "m.youtube.com") && u.Query().Get("v") != "":
+		return "youtube", u.Query().Get("v"), nil
+	case host == "youtube.com" && len(segments) == 2 && segments[0] == "embed":
+		return "youtube", segments[1], nil
+	case host == "vimeo.com" && vimeoId.MatchString(segments[len(segments)-1]):
+		return "vimeo", segments[len(segments)-1], nil
+	case host == "player.vimeo.com" && len(segments) == 2 && segments[0] == "video" && vimeoId.MatchString(segments[1]):
+		return "vimeo", segments[1], nil
+	}
+	return "", "", fmt.Errorf("%w: %s", ErrUnsupportedVideo, videoUrl)
+}
+
+// Retrieves the oEmbed representation of the video from its provider, answering an error if the provider does not
+// respond with a 200, or the response does not carry a title.
+func FetchOembed(videoUrl string) (*Oembed, error) {
+	provider, _, err := parseVideoUrl(videoUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	canonical, _ := CanonicalVideoUrl(videoUrl)
+	oembedUrl := fmt.Sprintf("%s?format=json&url=%s", oembedEndpoints[provider], url.QueryEscape(canonical))
+	_, body, err := jsonapi.FetchResource(oembedUrl, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	oembed := &Oembed{}
+	if err := json.Unmarshal(body, oembed); err != nil {
+		return nil, fmt.Errorf("error unmarshaling oEmbed response from %s: %w", oembedUrl, err)
+	}
+	if strings.TrimSpace(oembed.Title) == "" {
+		return nil, fmt.Errorf("oEmbed response from %s is missing a title", oembedUrl)
+	}
+	return oembed, nil
+}
@@ -0,0 +1,77 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CanonicalVideoUrl(t *testing.T) {
+	cases := map[string]string{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ":        "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"http://youtube.com/watch?v=dQw4w9WgXcQ&t=10s":       "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://youtu.be/dQw4w9WgXcQ":                       "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://www.youtube.com/embed/dQw4w9WgXcQ":          "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://vimeo.com/76979871":                         "https://vimeo.com/76979871",
+		"https://vimeo.com/channels/staffpicks/76979871":     "https://vimeo.com/76979871",
+		"https://player.vimeo.com/video/76979871?autoplay=1": "https://vimeo.com/76979871",
+	}
+	for videoUrl, expected := range cases {
+		actual, err := CanonicalVideoUrl(videoUrl)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual, "canonicalizing %s", videoUrl)
+	}
+
+	for _, videoUrl := range []string{
+		"https://www.example.org/video/1",
+		"https://vimeo.com/channels/staffpicks",
+		"https://vimeo.com/",
+		"https://player.vimeo.com/video/staffpicks",
+	} {
+		_, err := CanonicalVideoUrl(videoUrl)
+		assert.ErrorIs(t, err, ErrUnsupportedVideo, videoUrl)
+	}
+}
+
+func Test_FetchOembed(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("url"))
+		if r.URL.Path == "/untitled" {
+			w.Write([]byte(`{"type": "video"}`))
+			return
+		}
+		w.Write([]byte(`{"type": "video", "title": "The New Vimeo Player", "provider_name": "Vimeo"}`))
+	}))
+	defer server.Close()
+
+	original := oembedEndpoints["vimeo"]
+	defer func() { oembedEndpoints["vimeo"] = original }()
+
+	oembedEndpoints["vimeo"] = server.URL + "/oembed"
+	oembed, err := FetchOembed("https://player.vimeo.com/video/76979871")
+	require.Nil(t, err)
+	assert.Equal(t, "The New Vimeo Player", oembed.Title)
+	assert.Equal(t, "Vimeo", oembed.ProviderName)
+
+	oembedEndpoints["vimeo"] = server.URL + "/untitled"
+	_, err = FetchOembed("https://vimeo.com/76979871")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"https://vimeo.com/76979871", "https://vimeo.com/76979871"}, requested)
+}
+
+func Test_AssertRemoteVideo(t *testing.T) {
+	expected := model.ExpectedMediaRemoteVideo{EmbedUrl: "https://youtu.be/dQw4w9WgXcQ"}
+	assert.True(t, AssertRemoteVideo(t, expected, "https://www.youtube.com/watch?v=dQw4w9WgXcQ"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertRemoteVideo(rec, expected, "https://www.youtube.com/watch?v=9bZkp7q19f0"))
+	assert.Contains(t, rec.String(), "embed urls refer to different videos")
+}
@@ -0,0 +1,141 @@
+package verify
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The relationship carrying the user who authored an entity
+const ownerRelationship = "uid"
+
+// An entity that is not owned by its expected owner
+type OwnershipViolation struct {
+	Type  string
+	Id    string
+	Title string
+	// The name of the expected owner
+	Expected string
+	// The name of the actual owner; empty if the entity is owned by the anonymous user or its owner cannot be resolved
+	Actual string
+}
+
+// Answers the violation as e.g. `node--islandora_object "Moonrise" (n1) is owned by "admin", not "migration"`
+func (v OwnershipViolation) String() string {
+	return fmt.Sprintf("%s %q (%s) is owned by %q, not %q", v.Type, v.Title, v.Id, v.Actual, v.Expected)
+}
+
+// Verifies that migrated entities are owned by a designated migration user, catching content accidentally owned by
+// the administrator or the anonymous user.  The owner of an entity is the user referenced by its `uid` relationship,
+// resolved to the user's name.
+type OwnershipChecker struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+	// The name of the expected owner of each bundle, e.g. `islandora_object`
+	Owners map[string]string
+	// The name of the expected owner of bundles absent from Owners
+	DefaultOwner string
+}
+
+// Creates an OwnershipChecker for the Drupal site at the base url, expecting every bundle to be owned by the owner
+func NewOwnershipChecker(baseUrl, username, password, owner string) *OwnershipChecker {
+	return &OwnershipChecker{BaseUrl: baseUrl, Username: username, Password: env.Secret(password), Owners: map[string]string{}, DefaultOwner: owner}
+}
+
+// Answers the name of the expected owner of the bundle, or an error if none is configured
+func (c *OwnershipChecker) ExpectedOwner(bundle string) (string, error) {
+	if owner, ok := c.Owners[bundle]; ok {
+		return owner, nil
+	}
+	if c.DefaultOwner == "" {
+		return "", fmt.Errorf("no expected owner is configured for bundle '%s'", bundle)
+	}
+	return c.DefaultOwner, nil
+}
+
+// Answers the name of the owner of the single entity of the type and bundle with the title or name.  The empty string
+// is answered for entities owned by the anonymous user.
+func (c *OwnershipChecker) Owner(entityType, bundle, titleOrName string) (string, error) {
+	filter := "title"
+	if entityType != "node" {
+		filter = "name"
+	}
+	var owners []string
+	err := c.url(entityType, bundle, filter, titleOrName).FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			owners = append(owners, ownerName(page, d))
+		}
+		return nil
+	}, ownerRelationship)
+	if err != nil {
+		return "", err
+	}
+	if len(owners) != 1 {
+		return "", fmt.Errorf("exactly one %s--%s is expected to match '%s', but found %d", entityType, bundle, titleOrName, len(owners))
+	}
+	return owners[0], nil
+}
+
+// Answers the entities of the type and bundle that are not owned by the expected owner of the bundle, ordered by title
+func (c *OwnershipChecker) CheckBundle(entityType, bundle string) ([]OwnershipViolation, error) {
+	expected, err := c.ExpectedOwner(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []OwnershipViolation
+	err = c.url(entityType, bundle, "", "").FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			if actual := ownerName(page, d); actual != expected {
+				v := OwnershipViolation{Expected: expected, Actual: actual}
+				v.Type, _ = d["type"].(string)
+				v.Id, _ = d["id"].(string)
+				attributes, _ := d["attributes"].(map[string]interface{})
+				if v.Title, _ = attributes["title"].(string); v.Title == "" {
+					v.Title, _ = attributes["name"].(string)
+				}
+				violations = append(violations, v)
+			}
+		}
+		return nil
+	}, ownerRelationship)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Title < violations[j].Title })
+	return violations, nil
+}
+
+func (c *OwnershipChecker) url(entityType, bundle, filter, value string) *jsonapi.JsonApiUrl {
+	return &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: entityType,
+		DrupalBundle: bundle,
+		Filter:       filter,
+		Value:        value,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+}
+
+// Answers the name of the user referenced by the `uid` relationship of the resource object, resolved from the
+// included resources of the page.  The user's `name` is only answered to users permitted to see it, so its
+// `display_name` is answered otherwise.  The anonymous user (uid 0) is answered as the empty string.
+func ownerName(page *jsonapi.JsonApiPage, data map[string]interface{}) string {
+	relationships, _ := data["relationships"].(map[string]interface{})
+	rel, _ := relationships[ownerRelationship].(map[string]interface{})
+	ref, _ := rel["data"].(map[string]interface{})
+	if meta, ok := ref["meta"].(map[string]interface{}); ok && meta["drupal_internal__target_id"] == 0.0 {
+		return ""
+	}
+	user := page.Related(ref)
+	attributes, _ := user["attributes"].(map[string]interface{})
+	if name, ok := attributes["name"].(string); ok && name != "" {
+		return name
+	}
+	name, _ := attributes["display_name"].(string)
+	return name
+}
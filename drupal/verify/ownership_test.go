@@ -0,0 +1,68 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func owned(bundle, id, title string, uid map[string]interface{}) jsonapitest.Resource {
+	return jsonapitest.Resource{"type": "node--" + bundle, "id": id, "attributes": map[string]interface{}{"title": title},
+		"relationships": map[string]interface{}{"uid": map[string]interface{}{"data": uid}}}
+}
+
+func Test_OwnershipChecker(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	anonymous := ref("user--user", "u0")
+	anonymous["meta"] = map[string]interface{}{"drupal_internal__target_id": 0}
+	m.Add(
+		jsonapitest.Resource{"type": "user--user", "id": "u0", "attributes": map[string]interface{}{"display_name": "Anonymous"}},
+		jsonapitest.Resource{"type": "user--user", "id": "u1", "attributes": map[string]interface{}{"name": "admin", "display_name": "admin"}},
+		jsonapitest.Resource{"type": "user--user", "id": "u2", "attributes": map[string]interface{}{"display_name": "migration"}},
+		owned("islandora_object", "n1", "Moonrise", ref("user--user", "u2")),
+		owned("islandora_object", "n2", "Moonset", ref("user--user", "u1")),
+		owned("islandora_object", "n3", "Eclipse", anonymous),
+		owned("collection_object", "c1", "Ansel Adams Images", ref("user--user", "u1")),
+	)
+
+	c := NewOwnershipChecker(m.URL, "", "", "migration")
+	owner, err := c.Owner("node", "islandora_object", "Moonset")
+	require.Nil(t, err)
+	assert.Equal(t, "admin", owner)
+	owner, err = c.Owner("node", "islandora_object", "Eclipse")
+	require.Nil(t, err)
+	assert.Equal(t, "", owner)
+	_, err = c.Owner("node", "islandora_object", "Sunrise")
+	assert.NotNil(t, err)
+
+	assert.True(t, c.AssertOwner(t, "node", "islandora_object", "Moonrise"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, c.AssertOwner(rec, "node", "islandora_object", "Moonset"))
+	assert.Contains(t, rec.String(), "node--islandora_object 'Moonset' has an unexpected owner")
+
+	violations, err := c.CheckBundle("node", "islandora_object")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(violations))
+	assert.Equal(t, `node--islandora_object "Eclipse" (n3) is owned by "", not "migration"`, violations[0].String())
+	assert.Equal(t, "Moonset", violations[1].Title)
+	rec = &asserttest.Recorder{}
+	assert.False(t, c.AssertBundle(rec, "node", "islandora_object"))
+	assert.Contains(t, rec.String(), `node--islandora_object "Eclipse" (n3) is owned by "", not "migration"`)
+
+	// per-bundle owners
+	c.Owners["collection_object"] = "admin"
+	assert.True(t, c.AssertBundle(t, "node", "collection_object"))
+	c.DefaultOwner = ""
+	_, err = c.CheckBundle("node", "islandora_object")
+	assert.NotNil(t, err)
+	rec = &asserttest.Recorder{}
+	assert.False(t, c.AssertOwner(rec, "node", "islandora_object", "Moonrise"))
+	assert.Contains(t, rec.String(), "no expected owner is configured for bundle 'islandora_object'")
+}
@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// Compares the paragraphs referenced by the entity reference revisions field (e.g. `field_sections`) of the single
+// entity matched by the url with the expected paragraphs, answering the values that differ.  The paragraphs, and those
+// nested in them, are retrieved in a single request by including them (see jsonapi.JsonApiUrl.FetchIncluding), and
+// each is resolved to the revision its host references; an error wrapping jsonapi.ErrStaleRevision is answered if
+// Drupal includes another revision.  Mismatches are located by field and position, e.g. `field_sections[1].bundle` or
+// `field_sections[0].field_items[2].field_caption`, and a paragraph missing from either side is reported by its
+// bundle.
+func VerifyParagraphs(u *jsonapi.JsonApiUrl, field string, expected []model.ExpectedParagraph) ([]Mismatch, error) {
+	host, page, err := u.FetchIncluding(includePaths(field, expected)...)
+	if err != nil {
+		return nil, err
+	}
+	c := &comparison{unordered: map[string]bool{}}
+	if err := compareParagraphs(c, page, host, field, field, expected); err != nil {
+		return nil, err
+	}
+	return c.mismatches, nil
+}
+
+// Answers the include paths retrieving the paragraphs of the field and the paragraphs nested in them, e.g.
+// `field_sections` and `field_sections.field_items`, sorted
+func includePaths(field string, expected []model.ExpectedParagraph) []string {
+	paths := map[string]bool{field: true}
+	for _, p := range expected {
+		for nested, children := range p.Paragraphs {
+			for _, path := range includePaths(nested, children) {
+				paths[field+"."+path] = true
+			}
+		}
+	}
+	var sorted []string
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Compares the paragraphs referenced by the field of the host with the expected paragraphs, locating mismatches at
+// the path
+func compareParagraphs(c *comparison, page *jsonapi.JsonApiPage, host jsonapi.JsonApiData, field, path string, expected []model.ExpectedParagraph) error {
+	actual, err := page.Revisions(host, field)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		at := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(actual):
+			c.mismatches = append(c.mismatches, Mismatch{Path: at + ".bundle", Expected: expected[i].Bundle})
+			continue
+		case i >= len(expected):
+			c.mismatches = append(c.mismatches, Mismatch{Path: at + ".bundle", Actual: jsonapi.DrupalType(actual[i].Type()).Bundle()})
+			continue
+		}
+		e, a := expected[i], actual[i]
+		if bundle := jsonapi.DrupalType(a.Type()).Bundle(); bundle != e.Bundle {
+			// the fields of paragraphs of different bundles are not comparable
+			c.mismatches = append(c.mismatches, Mismatch{Path: at + ".bundle", Expected: e.Bundle, Actual: bundle})
+			continue
+		}
+		fields, err := normalize(e.Fields)
+		if err != nil {
+			return err
+		}
+		attributes, _ := a["attributes"].(map[string]interface{})
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c.compare(at+"."+name, fields[name], attributes[name])
+		}
+
+		nested := make([]string, 0, len(e.Paragraphs))
+		for name := range e.Paragraphs {
+			nested = append(nested, name)
+		}
+		sort.Strings(nested)
+		for _, name := range nested {
+			if err := compareParagraphs(c, page, a, name, at+"."+name, e.Paragraphs[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Answers a mock server with a node whose `field_sections` references a text block and a gallery nesting a text block
+// in its `field_items`.  The default revision of the gallery is the revision of the gallery included.
+func newParagraphSite(galleryRevision int) *jsonapitest.MockServer {
+	ref := func(bundle, id string, revision int) map[string]interface{} {
+		return map[string]interface{}{"type": "paragraph--" + bundle, "id": id,
+			"meta": map[string]interface{}{"target_revision_id": revision}}
+	}
+	m := jsonapitest.NewMockServer()
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "n1",
+		"attributes": map[string]interface{}{"title": "Moonrise"},
+		"relationships": map[string]interface{}{"field_sections": map[string]interface{}{"data": []interface{}{
+			ref("text_block", "p1", 7), ref("gallery", "p2", 3)}}}},
+		jsonapitest.Resource{"type": "paragraph--text_block", "id": "p1",
+			"attributes": map[string]interface{}{"drupal_internal__revision_id": 7, "field_heading": "Moonrise",
+				"field_weight": 1}},
+		jsonapitest.Resource{"type": "paragraph--gallery", "id": "p2",
+			"attributes": map[string]interface{}{"drupal_internal__revision_id": galleryRevision},
+			"relationships": map[string]interface{}{"field_items": map[string]interface{}{"data": []interface{}{
+				ref("text_block", "p3", 9)}}}},
+		jsonapitest.Resource{"type": "paragraph--text_block", "id": "p3",
+			"attributes": map[string]interface{}{"drupal_internal__revision_id": 9, "field_heading": "Hernandez"}})
+	return m
+}
+
+func expectedSections(nestedHeading string) []model.ExpectedParagraph {
+	gallery := model.NewExpectedParagraph("gallery", nil)
+	gallery.Paragraphs = map[string][]model.ExpectedParagraph{
+		"field_items": {model.NewExpectedParagraph("text_block", map[string]interface{}{"field_heading": nestedHeading})},
+	}
+	return []model.ExpectedParagraph{
+		model.NewExpectedParagraph("text_block", map[string]interface{}{"field_heading": "Moonrise", "field_weight": 1}),
+		gallery,
+	}
+}
+
+func Test_VerifyParagraphs(t *testing.T) {
+	m := newParagraphSite(3)
+	defer m.Close()
+	u := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise"}
+
+	mismatches, err := VerifyParagraphs(u, "field_sections", expectedSections("Hernandez"))
+	require.Nil(t, err)
+	assert.Empty(t, mismatches)
+	// the paragraphs, and those nested in them, are included rather than retrieved individually
+	require.Len(t, m.Requests(), 1)
+	assert.Equal(t, []string{"field_sections,field_sections.field_items"}, m.Requests()[0].Query["include"])
+	assert.True(t, AssertParagraphs(t, u, "field_sections", expectedSections("Hernandez")))
+
+	mismatches, err = VerifyParagraphs(u, "field_sections", expectedSections("Ansel Adams"))
+	require.Nil(t, err)
+	assert.Equal(t, []Mismatch{{Path: "field_sections[1].field_items[0].field_heading", Expected: "Ansel Adams", Actual: "Hernandez"}}, mismatches)
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertParagraphs(rec, u, "field_sections", expectedSections("Ansel Adams")))
+	assert.Contains(t, rec.String(), `field_sections[1].field_items[0].field_heading: expected "Ansel Adams", got "Hernandez"`)
+
+	reordered := expectedSections("Hernandez")
+	reordered[0], reordered[1] = reordered[1], reordered[0]
+	mismatches, err = VerifyParagraphs(u, "field_sections", append(reordered, model.NewExpectedParagraph("quote", nil)))
+	require.Nil(t, err)
+	assert.Equal(t, []Mismatch{
+		{Path: "field_sections[0].bundle", Expected: "gallery", Actual: "text_block"},
+		{Path: "field_sections[1].bundle", Expected: "text_block", Actual: "gallery"},
+		{Path: "field_sections[2].bundle", Expected: "quote"},
+	}, mismatches)
+}
+
+func Test_VerifyParagraphsStale(t *testing.T) {
+	m := newParagraphSite(4)
+	defer m.Close()
+	u := &jsonapi.JsonApiUrl{BaseUrl: m.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise"}
+
+	_, err := VerifyParagraphs(u, "field_sections", expectedSections("Hernandez"))
+	assert.True(t, errors.Is(err, jsonapi.ErrStaleRevision))
+}
@@ -0,0 +1,138 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var (
+	// The url alias of an entity does not match its expected pattern
+	ErrAliasMismatch = errors.New("alias does not match")
+	// A legacy url does not permanently redirect to the expected alias
+	ErrRedirectMismatch = errors.New("legacy url does not redirect to the alias")
+)
+
+// Verifies the url aliases of migrated entities (e.g. `/collections/moonrise`), and that their legacy urls (e.g.
+// `/islandora/object/jhu:1234`) permanently redirect to their aliases
+type PathVerifier struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a PathVerifier for the Drupal site at the base url
+func NewPathVerifier(baseUrl, username, password string) *PathVerifier {
+	return &PathVerifier{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Answers the url alias computed for the entity of the Drupal type (e.g. `node--collection_object`) with the UUID,
+// read from its `path` attribute, e.g. `/collections/moonrise`.  The empty string is answered if the entity has no
+// alias.
+func (p *PathVerifier) Alias(drupalType, uuid string) (string, error) {
+	t, err := jsonapi.ParseDrupalType(drupalType)
+	if err != nil {
+		return "", err
+	}
+	u := jsonapi.ResourceUrl(p.BaseUrl, t, uuid)
+	u.Username, u.Password = p.Username, p.Password
+	res := &jsonapi.JsonApiResponse{}
+	if err := u.FetchSingle(res); err != nil {
+		return "", err
+	}
+	path, _ := res.Items()[0].Attribute("path").(map[string]interface{})
+	alias, _ := path["alias"].(string)
+	return alias, nil
+}
+
+// Answers an error wrapping ErrAliasMismatch unless the url alias of the entity (see Alias) matches the pattern, a
+// regular expression which must match the entire alias, e.g. `/collections/[a-z0-9-]+`
+func (p *PathVerifier) CheckAlias(drupalType, uuid, pattern string) error {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid alias pattern '%s': %w", pattern, err)
+	}
+	alias, err := p.Alias(drupalType, uuid)
+	if err != nil {
+		return err
+	}
+	if !re.MatchString(alias) {
+		return fmt.Errorf("%w: the alias of %s %s is '%s', which does not match '%s'", ErrAliasMismatch, drupalType, uuid, alias, pattern)
+	}
+	return nil
+}
+
+// Requests the legacy url, which may be a path of the site (e.g. `/islandora/object/jhu:1234`), without following
+// redirects, answering the status of the response and the path (and query, if any) it redirects to.  A redirect to
+// another host is answered as an absolute url.
+func (p *PathVerifier) Redirect(legacyUrl string) (status int, location string, err error) {
+	u, err := p.resolve(legacyUrl)
+	if err != nil {
+		return 0, "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, "", err
+	}
+	if p.Username != "" {
+		req.SetBasicAuth(p.Username, p.Password.Reveal())
+	}
+	base := jsonapi.HTTPClient()
+	client := &http.Client{Transport: base.Transport, Timeout: base.Timeout}
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("error requesting %s: %w", u, err)
+	}
+	res.Body.Close()
+	loc, err := res.Location()
+	if err == http.ErrNoLocation {
+		return res.StatusCode, "", nil
+	}
+	if err != nil {
+		return res.StatusCode, "", fmt.Errorf("invalid Location answered for %s: %w", u, err)
+	}
+	if loc.Host != u.Host {
+		return res.StatusCode, loc.String(), nil
+	}
+	return res.StatusCode, loc.RequestURI(), nil
+}
+
+// Answers an error wrapping ErrRedirectMismatch unless the legacy url answers a 301 redirecting to the alias (e.g.
+// `/collections/moonrise`).  The alias is matched as Drupal matches aliases: case-insensitively, and disregarding a
+// trailing slash.
+func (p *PathVerifier) CheckRedirect(legacyUrl, alias string) error {
+	status, location, err := p.Redirect(legacyUrl)
+	if err != nil {
+		return err
+	}
+	switch {
+	case status != http.StatusMovedPermanently:
+		return fmt.Errorf("%w: %s answered %d, not %d", ErrRedirectMismatch, legacyUrl, status, http.StatusMovedPermanently)
+	case normalizeAlias(location) != normalizeAlias(alias):
+		return fmt.Errorf("%w: %s redirects to '%s', not '%s'", ErrRedirectMismatch, legacyUrl, location, alias)
+	}
+	return nil
+}
+
+// Answers the legacy url, resolved against the base url if it is a path
+func (p *PathVerifier) resolve(legacyUrl string) (*url.URL, error) {
+	base, err := url.Parse(strings.TrimSuffix(p.BaseUrl, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url '%s': %w", p.BaseUrl, err)
+	}
+	ref, err := url.Parse(legacyUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid legacy url '%s': %w", legacyUrl, err)
+	}
+	if !ref.IsAbs() {
+		ref.Path = strings.TrimPrefix(ref.Path, "/")
+	}
+	return base.ResolveReference(ref), nil
+}
@@ -0,0 +1,95 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PathVerifierAlias(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "node--collection_object", "id": "329c57a2-97f2-4350-8b54-439237c68311", "attributes": map[string]interface{}{
+		"title": "Moonrise", "path": map[string]interface{}{"alias": "/collections/moonrise", "pid": 12, "langcode": "en"}}},
+		jsonapitest.Resource{"type": "node--collection_object", "id": "4f0d4a4c-21c5-43c3-b4ad-3a2ba1fbd1a2", "attributes": map[string]interface{}{
+			"title": "Moonset", "path": map[string]interface{}{"alias": nil, "pid": nil, "langcode": "en"}}})
+	p := NewPathVerifier(m.URL, "", "")
+
+	alias, err := p.Alias("node--collection_object", "329c57a2-97f2-4350-8b54-439237c68311")
+	require.Nil(t, err)
+	assert.Equal(t, "/collections/moonrise", alias)
+	alias, err = p.Alias("node--collection_object", "4f0d4a4c-21c5-43c3-b4ad-3a2ba1fbd1a2")
+	require.Nil(t, err)
+	assert.Equal(t, "", alias)
+
+	assert.Nil(t, p.CheckAlias("node--collection_object", "329c57a2-97f2-4350-8b54-439237c68311", "/collections/[a-z0-9-]+"))
+	// the pattern must match the entire alias
+	err = p.CheckAlias("node--collection_object", "329c57a2-97f2-4350-8b54-439237c68311", "/collections")
+	require.True(t, errors.Is(err, ErrAliasMismatch))
+	assert.Equal(t, "alias does not match: the alias of node--collection_object 329c57a2-97f2-4350-8b54-439237c68311 is "+
+		"'/collections/moonrise', which does not match '/collections'", err.Error())
+	err = p.CheckAlias("node--collection_object", "4f0d4a4c-21c5-43c3-b4ad-3a2ba1fbd1a2", "/collections/.+")
+	assert.True(t, errors.Is(err, ErrAliasMismatch))
+	err = p.CheckAlias("node--collection_object", "329c57a2-97f2-4350-8b54-439237c68311", "/collections/(")
+	assert.Contains(t, err.Error(), "invalid alias pattern")
+
+	assert.True(t, p.AssertAlias(t, "node--collection_object", "329c57a2-97f2-4350-8b54-439237c68311", "/collections/moonrise"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, p.AssertAlias(rec, "node--collection_object", "9a9d3c5e-1b7e-4a8f-8d3e-2f4a5b6c7d8e", ".*"))
+	assert.Contains(t, rec.String(), "404 status encountered when requesting")
+}
+
+func Test_PathVerifierRedirect(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/islandora/object/jhu:1234":
+			http.Redirect(w, r, server.URL+"/collections/Moonrise/", http.StatusMovedPermanently)
+		case "/islandora/object/jhu:1235":
+			http.Redirect(w, r, "/collections/moonset", http.StatusFound)
+		case "/islandora/object/jhu:1236":
+			http.Redirect(w, r, "https://archive.example.org/jhu:1236", http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	p := NewPathVerifier(server.URL+"/", "", "")
+
+	status, location, err := p.Redirect("/islandora/object/jhu:1234")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, status)
+	assert.Equal(t, "/collections/Moonrise/", location)
+	_, location, err = p.Redirect(server.URL + "/islandora/object/jhu:1236")
+	require.Nil(t, err)
+	assert.Equal(t, "https://archive.example.org/jhu:1236", location)
+	status, location, err = p.Redirect("/islandora/object/jhu:9999")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "", location)
+
+	assert.Nil(t, p.CheckRedirect("/islandora/object/jhu:1234", "/collections/moonrise"))
+	for legacy, expected := range map[string]string{
+		"/islandora/object/jhu:1234": "legacy url does not redirect to the alias: /islandora/object/jhu:1234 redirects to '/collections/Moonrise/', not '/collections/moonset'",
+		"/islandora/object/jhu:1235": "legacy url does not redirect to the alias: /islandora/object/jhu:1235 answered 302, not 301",
+		"/islandora/object/jhu:9999": "legacy url does not redirect to the alias: /islandora/object/jhu:9999 answered 404, not 301",
+	} {
+		err := p.CheckRedirect(legacy, "/collections/moonset")
+		require.True(t, errors.Is(err, ErrRedirectMismatch), legacy)
+		assert.Equal(t, expected, err.Error())
+	}
+
+	assert.True(t, p.AssertRedirect(t, "islandora/object/jhu:1234", "/collections/moonrise"))
+	rec := &asserttest.Recorder{}
+	assert.False(t, p.AssertRedirect(rec, "/islandora/object/jhu:1235", "/collections/moonset"))
+	assert.Contains(t, rec.String(), "/islandora/object/jhu:1235 answered 302, not 301")
+}
@@ -0,0 +1,158 @@
+package verify
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The fields of each media bundle which reference the media's file
+var mediaFileFields = map[string]string{
+	model.Image:         "field_media_image",
+	model.Document:      "field_media_document",
+	model.Audio:         "field_media_audio_file",
+	model.Video:         "field_media_video_file",
+	model.ExtractedText: "field_media_file",
+	model.File:          "field_media_file",
+	model.Fits:          "field_media_file",
+}
+
+// Matches file names carrying the numeric suffix appended by Drupal when a file of the same name already exists, e.g.
+// `image_0.jpg`
+var collisionSuffix = regexp.MustCompile(`^(.+)_[0-9]+(\.[^.]*)?$`)
+
+// A file of a migrated media that appears to have been renamed by Drupal to avoid a collision with an existing file
+type RenamedFile struct {
+	MediaId     string `json:"media_id"`
+	MediaName   string `json:"media_name"`
+	MediaBundle string `json:"media_bundle"`
+	FileId      string `json:"file_id"`
+	// The name of the file supplied by the migration, e.g. `image.jpg`.  Empty if the media does not record it.
+	OriginalName string `json:"original_name"`
+	// The name of the file as stored by Drupal, e.g. `image_0.jpg`
+	StoredName string `json:"stored_name"`
+	// The Drupal URI of the stored file, e.g. `public://2021-05/image_0.jpg`
+	Uri string `json:"uri"`
+	// True if the rename was inferred solely from the suffix of the stored name, because the original name is unknown.
+	// Suspected renames may be false positives, e.g. a file migrated as `page_1.jpg`.
+	Suspected bool `json:"suspected"`
+}
+
+func (f RenamedFile) String() string {
+	if f.Suspected {
+		return fmt.Sprintf("%s media '%s' (%s): file %s may have been renamed by Drupal", f.MediaBundle, f.MediaName, f.MediaId, f.Uri)
+	}
+	return fmt.Sprintf("%s media '%s' (%s): file '%s' was stored as %s", f.MediaBundle, f.MediaName, f.MediaId, f.OriginalName, f.Uri)
+}
+
+// Answers the name a file carried before Drupal appended a numeric suffix to avoid a collision, e.g. `image_0.jpg`
+// answers `image.jpg`.  False is answered if the name does not carry such a suffix.
+func CollisionOriginalName(name string) (string, bool) {
+	m := collisionSuffix.FindStringSubmatch(name)
+	if m == nil {
+		return "", false
+	}
+	return m[1] + m[2], true
+}
+
+// Audits the files of every media of the supplied bundles (by default, every bundle having a file), answering the
+// files that Drupal appears to have renamed.  A file was renamed if its stored name differs from the original name
+// recorded by its media (`field_original_name`) or file entity (`filename`); if neither records a different name, a
+// file whose stored name carries a collision suffix is reported as a suspected rename.
+//
+// Renames are not necessarily errors, so the audit answers them for curators to review rather than asserting.  They
+// are ordered by media bundle and media id, so that the audits of successive runs may be compared.
+func AuditFileRenames(baseUrl, username, password string, bundles ...string) ([]RenamedFile, error) {
+	if len(bundles) == 0 {
+		bundles = []string{model.Image, model.Document, model.Audio, model.Video, model.ExtractedText, model.File, model.Fits}
+	}
+
+	var renamed []RenamedFile
+	for _, bundle := range bundles {
+		field, ok := mediaFileFields[bundle]
+		if !ok {
+			return nil, fmt.Errorf("media bundle '%s' does not have a file", bundle)
+		}
+		u := &jsonapi.JsonApiUrl{
+			BaseUrl:      baseUrl,
+			DrupalEntity: model.Media,
+			DrupalBundle: bundle,
+			Username:     username,
+			Password:     env.Secret(password),
+		}
+		err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+			for _, media := range page.Data {
+				if f, ok := auditFile(page, media, bundle, field); ok {
+					renamed = append(renamed, f)
+				}
+			}
+			return nil
+		}, field)
+		if err != nil {
+			return nil, fmt.Errorf("unable to audit %s media: %w", bundle, err)
+		}
+	}
+	sort.SliceStable(renamed, func(i, j int) bool {
+		if renamed[i].MediaBundle != renamed[j].MediaBundle {
+			return renamed[i].MediaBundle < renamed[j].MediaBundle
+		}
+		return renamed[i].MediaId < renamed[j].MediaId
+	})
+	return renamed, nil
+}
+
+// Audits the file of a single media, answering true if the file was (or may have been) renamed
+func auditFile(page *jsonapi.JsonApiPage, media map[string]interface{}, bundle, field string) (RenamedFile, bool) {
+	file := page.Related(nested(media, "relationships", field, "data"))
+	if file == nil {
+		return RenamedFile{}, false
+	}
+
+	f := RenamedFile{
+		MediaBundle: bundle,
+		Uri:         str(nested(file, "attributes", "uri"), "value"),
+	}
+	f.MediaId, _ = media["id"].(string)
+	f.FileId, _ = file["id"].(string)
+	f.MediaName = str(nested(media, "attributes"), "name")
+	f.StoredName = path.Base(f.Uri)
+
+	for _, original := range []string{str(nested(media, "attributes"), "field_original_name"), str(nested(file, "attributes"), "filename")} {
+		if original != "" && original != f.StoredName {
+			f.OriginalName = original
+			return f, true
+		}
+		if original != "" && f.OriginalName == "" {
+			f.OriginalName = original
+		}
+	}
+
+	if _, ok := CollisionOriginalName(f.StoredName); ok && f.OriginalName == "" {
+		f.Suspected = true
+		return f, true
+	}
+	return RenamedFile{}, false
+}
+
+// Answers the object found by descending through the supplied keys, or nil if any key is absent
+func nested(m map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, k := range keys {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+// Answers the string value of the key, or the empty string if it is absent or not a string
+func str(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
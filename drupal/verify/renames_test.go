@@ -0,0 +1,90 @@
+package verify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CollisionOriginalName(t *testing.T) {
+	original, ok := CollisionOriginalName("image_0.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, "image.jpg", original)
+
+	original, ok = CollisionOriginalName("README_12")
+	assert.True(t, ok)
+	assert.Equal(t, "README", original)
+
+	_, ok = CollisionOriginalName("image.jpg")
+	assert.False(t, ok)
+	_, ok = CollisionOriginalName("_0.jpg")
+	assert.False(t, ok)
+}
+
+func Test_AuditFileRenames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/jsonapi/media/image", r.URL.Path)
+		require.Equal(t, "field_media_image", r.URL.Query().Get("include"))
+		w.Write([]byte(`{
+		  "data": [
+		    {"type": "media--image", "id": "m1", "attributes": {"name": "Renamed", "field_original_name": "moonrise.jpg"},
+		     "relationships": {"field_media_image": {"data": {"type": "file--file", "id": "f1"}}}},
+		    {"type": "media--image", "id": "m2", "attributes": {"name": "Suspected"},
+		     "relationships": {"field_media_image": {"data": {"type": "file--file", "id": "f2"}}}},
+		    {"type": "media--image", "id": "m3", "attributes": {"name": "Intact", "field_original_name": "page_1.jpg"},
+		     "relationships": {"field_media_image": {"data": {"type": "file--file", "id": "f3"}}}}
+		  ],
+		  "included": [
+		    {"type": "file--file", "id": "f1", "attributes": {"filename": "moonrise_0.jpg", "uri": {"value": "public://2021-05/moonrise_0.jpg"}}},
+		    {"type": "file--file", "id": "f2", "attributes": {"uri": {"value": "public://2021-05/sunset_2.jpg"}}},
+		    {"type": "file--file", "id": "f3", "attributes": {"filename": "page_1.jpg", "uri": {"value": "public://2021-05/page_1.jpg"}}}
+		  ]
+		}`))
+	}))
+	defer server.Close()
+
+	renamed, err := AuditFileRenames(server.URL, "", "", model.Image)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(renamed))
+
+	assert.Equal(t, "m1", renamed[0].MediaId)
+	assert.Equal(t, "f1", renamed[0].FileId)
+	assert.Equal(t, "moonrise.jpg", renamed[0].OriginalName)
+	assert.Equal(t, "moonrise_0.jpg", renamed[0].StoredName)
+	assert.False(t, renamed[0].Suspected)
+
+	assert.Equal(t, "m2", renamed[1].MediaId)
+	assert.Equal(t, "sunset_2.jpg", renamed[1].StoredName)
+	assert.True(t, renamed[1].Suspected)
+
+	_, err = AuditFileRenames(server.URL, "", "", model.RemoteVideo)
+	assert.NotNil(t, err)
+}
+
+// Renames are ordered by media bundle and id, whatever order the bundles are audited in
+func Test_AuditFileRenamesOrdering(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.PageSize = 1
+	for _, media := range []struct{ bundle, field, id string }{
+		{model.Image, "field_media_image", "m2"}, {model.Image, "field_media_image", "m1"}, {model.Document, "field_media_document", "m3"},
+	} {
+		m.Add(jsonapitest.Resource{"type": "media--" + media.bundle, "id": media.id, "attributes": map[string]interface{}{"name": media.id},
+			"relationships": map[string]interface{}{media.field: map[string]interface{}{"data": ref("file--file", "f"+media.id)}}},
+			jsonapitest.Resource{"type": "file--file", "id": "f" + media.id, "attributes": map[string]interface{}{
+				"uri": map[string]interface{}{"value": "public://2021-05/moonrise_" + media.id[1:] + ".jpg"}}})
+	}
+
+	renamed, err := AuditFileRenames(m.URL, "", "", model.Image, model.Document)
+	require.Nil(t, err)
+	var order []string
+	for _, f := range renamed {
+		order = append(order, f.MediaBundle+" "+f.MediaId)
+	}
+	assert.Equal(t, []string{"document m3", "image m1", "image m2"}, order)
+}
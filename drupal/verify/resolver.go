@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// Finds the live entity of a fixture, for bundles whose entities are more readily found by a custom lookup (e.g. a
+// View or a custom endpoint) than by filtering the JSON API on their title or name.  Resolvers are registered per
+// bundle with Engine.Resolvers, and are not consulted for model.ExpectedAbsent fixtures.
+type Resolver interface {
+	// Answers the UUID of the live entity of the fixture, or an error if there is no single such entity.  The fixture
+	// is merged with its defaults, and its values are as read from JSON.
+	Resolve(e *Engine, fixture map[string]interface{}) (uuid string, err error)
+}
+
+// Adapts a function to a Resolver
+type ResolverFunc func(e *Engine, fixture map[string]interface{}) (string, error)
+
+func (f ResolverFunc) Resolve(e *Engine, fixture map[string]interface{}) (string, error) {
+	return f(e, fixture)
+}
+
+// Resolves fixtures using a Drupal View with a REST export display (or any endpoint answering similar JSON), e.g. a
+// View listing the UUID of the repository object of each legacy PID:
+//
+//	engine.Resolvers = map[string]verify.Resolver{
+//		"node--islandora_object": &verify.ViewResolver{Path: "/api/objects-by-pid?pid={pid}"},
+//	}
+//
+// The response must be a JSON array of exactly one object, or a single object, carrying the UUID of the entity.
+type ViewResolver struct {
+	// The path of the View, relative to the base url of the Engine.  Each `{key}` is replaced by the query-escaped
+	// string value of the fixture key, e.g. `{unique_id}`.
+	Path string
+	// The member of the response carrying the UUID of the entity; `uuid` if empty
+	UuidField string
+}
+
+var placeholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Requests the View with the values of the fixture substituted into its Path, using the credentials of the Engine
+func (v *ViewResolver) Resolve(e *Engine, fixture map[string]interface{}) (string, error) {
+	var missing []string
+	path := placeholder.ReplaceAllStringFunc(v.Path, func(p string) string {
+		key := p[1 : len(p)-1]
+		value, ok := fixture[key].(string)
+		if !ok || value == "" {
+			missing = append(missing, key)
+		}
+		return url.QueryEscape(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unable to resolve the entity using %s: the fixture lacks %s", v.Path, strings.Join(missing, ", "))
+	}
+
+	u := strings.TrimSuffix(e.BaseUrl, "/") + "/" + strings.TrimPrefix(path, "/")
+	res, body, err := jsonapi.FetchResource(u, e.Username, e.Password.Reveal())
+	if err == nil {
+		err = jsonapi.CheckJson(res, body)
+	}
+	if err != nil {
+		return "", err
+	}
+	var rows []map[string]interface{}
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "{") {
+		row := map[string]interface{}{}
+		err = json.Unmarshal(body, &row)
+		rows = append(rows, row)
+	} else {
+		err = json.Unmarshal(body, &rows)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to unmarshal the response of %s: %w", u, err)
+	}
+	if len(rows) != 1 {
+		return "", fmt.Errorf("expected exactly one entity from %s, found %d", u, len(rows))
+	}
+
+	field := v.UuidField
+	if field == "" {
+		field = "uuid"
+	}
+	uuid := stringValue(rows[0][field])
+	if uuid == "" {
+		return "", fmt.Errorf("the response of %s carries no '%s'", u, field)
+	}
+	return uuid, nil
+}
+
+// Answers the string, or the first value of a field as serialized by Drupal (e.g. `[{"value": "…"}]`)
+func stringValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []interface{}:
+		if len(s) > 0 {
+			return stringValue(s[0])
+		}
+	case map[string]interface{}:
+		return stringValue(s["value"])
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package verify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EngineResolvers(t *testing.T) {
+	m := jsonapitest.NewMockServer()
+	defer m.Close()
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "b4f3c9a2", "attributes": map[string]interface{}{"title": "Moonrise", "field_unique_id": "moon-1"}})
+	m.Add(jsonapitest.Resource{"type": "node--islandora_object", "id": "0e1d2c3b", "attributes": map[string]interface{}{"title": "Moonrise", "field_unique_id": "moon-2"}})
+
+	e := NewEngine(m.URL, "", "")
+	var resolved []interface{}
+	e.Resolvers = map[string]Resolver{"node--islandora_object": ResolverFunc(func(e *Engine, fixture map[string]interface{}) (string, error) {
+		resolved = append(resolved, fixture["unique_id"])
+		switch fixture["unique_id"] {
+		case "moon-1":
+			return "b4f3c9a2", nil
+		case "moon-2":
+			return "0e1d2c3b", nil
+		}
+		return "", errors.New("no such object")
+	})}
+
+	// two objects share the title, so filtering on it would fail
+	r := e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "unique_id": "moon-2"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	assert.Equal(t, "Moonrise", r.Key)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "unique_id": "moon-1"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+	assert.Equal(t, "b4f3c9a2", r.Key)
+
+	r = e.VerifyJson([]byte(`{"type": "node", "bundle": "islandora_object", "unique_id": "moon-3"}`))
+	require.NotNil(t, r.Err)
+	assert.Contains(t, r.Err.Error(), "unable to resolve the node--islandora_object of the fixture: no such object")
+	assert.Equal(t, []interface{}{"moon-2", "moon-1", "moon-3"}, resolved)
+
+	// other bundles are found by their name
+	m.Add(jsonapitest.Resource{"type": "taxonomy_term--subject", "attributes": map[string]interface{}{"name": "Photography"}})
+	r = e.VerifyJson([]byte(`{"type": "taxonomy_term", "bundle": "subject", "name": "Photography"}`))
+	require.Nil(t, r.Err)
+	assert.True(t, r.Passed(), "%v", r.Mismatches)
+}
+
+func Test_ViewResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("pid") {
+		case "islandora:1":
+			w.Write([]byte(`[{"uuid": "b4f3c9a2", "title": "Moonrise"}]`))
+		case "islandora:2":
+			w.Write([]byte(`{"uuid": [{"value": "0e1d2c3b"}]}`))
+		case "islandora:3":
+			w.Write([]byte(`[{"uuid": "b4f3c9a2"}, {"uuid": "0e1d2c3b"}]`))
+		case "islandora:4":
+			w.Write([]byte(`[{"nid": "4"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+	e := NewEngine(server.URL, "", "")
+	v := &ViewResolver{Path: "/api/objects-by-pid?pid={pid}"}
+
+	uuid, err := v.Resolve(e, map[string]interface{}{"pid": "islandora:1"})
+	require.Nil(t, err)
+	assert.Equal(t, "b4f3c9a2", uuid)
+
+	uuid, err = v.Resolve(e, map[string]interface{}{"pid": "islandora:2"})
+	require.Nil(t, err)
+	assert.Equal(t, "0e1d2c3b", uuid)
+
+	_, err = v.Resolve(e, map[string]interface{}{"pid": "islandora:3"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "expected exactly one entity")
+	_, err = v.Resolve(e, map[string]interface{}{"pid": "islandora:5"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "found 0")
+	_, err = v.Resolve(e, map[string]interface{}{"pid": "islandora:4"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "carries no 'uuid'")
+	_, err = v.Resolve(e, map[string]interface{}{"title": "Moonrise"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "the fixture lacks pid")
+
+	v.UuidField = "nid"
+	uuid, err = v.Resolve(e, map[string]interface{}{"pid": "islandora:4"})
+	require.Nil(t, err)
+	assert.Equal(t, "4", uuid)
+}
@@ -0,0 +1,174 @@
+package verify
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// A cross-field consistency rule, e.g. "a repository object with a publisher country ought to have a publication
+// date".  Rules are evaluated against Expected entities: fixtures, or entities generated from live resources by
+// model.Generate.
+type Rule struct {
+	// Uniquely identifies the rule within a registry, e.g. `publisher-country-requires-date-published`
+	Name string
+	// Answers an error describing the inconsistency if the entity violates the rule.  Entities that the rule does not
+	// apply to (e.g. a taxonomy term evaluated by a repository object rule) answer nil.
+	Check func(e model.ExpectedEntity) error
+}
+
+// A violation of a Rule by an entity
+type Violation struct {
+	Rule string
+	Err  error
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Err)
+}
+
+// A registry of rules, safe for concurrent use
+type Rules struct {
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// Rules shipped with this package, registered in DefaultRules
+var shippedRules = []Rule{
+	{
+		Name: "publisher-country-requires-date-published",
+		Check: repoObjRule(func(o *model.ExpectedRepoObj) error {
+			if len(o.PublisherCountry) > 0 && len(nonEmpty(o.DatePublished)) == 0 {
+				return fmt.Errorf("publisher country %v is present, but date published is empty", o.PublisherCountry)
+			}
+			return nil
+		}),
+	},
+	{
+		Name: "publisher-country-requires-publisher",
+		Check: repoObjRule(func(o *model.ExpectedRepoObj) error {
+			if len(o.PublisherCountry) > 0 && len(nonEmpty(o.Publisher)) == 0 {
+				return fmt.Errorf("publisher country %v is present, but publisher is empty", o.PublisherCountry)
+			}
+			return nil
+		}),
+	},
+	{
+		Name: "geoportal-link-requires-spatial-coverage",
+		Check: repoObjRule(func(o *model.ExpectedRepoObj) error {
+			if o.GeoportalLink.Uri != "" && len(nonEmpty(o.SpatialCoverage)) == 0 {
+				return fmt.Errorf("geoportal link '%s' is present, but spatial coverage is empty", o.GeoportalLink.Uri)
+			}
+			return nil
+		}),
+	},
+	{
+		Name: "spatial-coverage-not-blank",
+		Check: repoObjRule(func(o *model.ExpectedRepoObj) error {
+			if len(nonEmpty(o.SpatialCoverage)) != len(o.SpatialCoverage) {
+				return fmt.Errorf("spatial coverage %q contains a blank value", o.SpatialCoverage)
+			}
+			return nil
+		}),
+	},
+}
+
+// The registry of rules evaluated by AssertRules when no registry is supplied.  It contains the rules shipped with
+// this package, and any site-specific rules added by RegisterRule.
+var DefaultRules = NewRules(shippedRules...)
+
+// Creates a registry containing the supplied rules
+func NewRules(rules ...Rule) *Rules {
+	r := &Rules{}
+	for _, rule := range rules {
+		r.Register(rule)
+	}
+	return r
+}
+
+// Adds a site-specific rule to DefaultRules, typically from an init function of a test suite
+func RegisterRule(rule Rule) {
+	DefaultRules.Register(rule)
+}
+
+// Adds the rule to the registry.  Panics if the rule lacks a name or Check, or if a rule with the same name is
+// already registered.
+func (r *Rules) Register(rule Rule) {
+	if rule.Name == "" || rule.Check == nil {
+		panic("verify: a rule must have a name and a check")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.rules {
+		if existing.Name == rule.Name {
+			panic(fmt.Sprintf("verify: rule '%s' is already registered", rule.Name))
+		}
+	}
+	r.rules = append(r.rules, rule)
+}
+
+// Removes the named rule from the registry, e.g. a shipped rule that does not hold for a site's content.  Answers
+// false if no rule has the name.
+func (r *Rules) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.rules {
+		if existing.Name == name {
+			r.rules = append(r.rules[:i:i], r.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Answers the names of the registered rules, in the order they were registered
+func (r *Rules) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.rules))
+	for i, rule := range r.rules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// Evaluates every registered rule against the entity, answering the violations in the order the rules were
+// registered
+func (r *Rules) Evaluate(e model.ExpectedEntity) []Violation {
+	r.mu.RLock()
+	rules := append([]Rule(nil), r.rules...)
+	r.mu.RUnlock()
+
+	var violations []Violation
+	for _, rule := range rules {
+		if err := rule.Check(e); err != nil {
+			violations = append(violations, Violation{Rule: rule.Name, Err: err})
+		}
+	}
+	return violations
+}
+
+// Adapts a check of repository objects to a Rule check, which ignores every other entity
+func repoObjRule(check func(o *model.ExpectedRepoObj) error) func(e model.ExpectedEntity) error {
+	return func(e model.ExpectedEntity) error {
+		switch o := e.(type) {
+		case *model.ExpectedRepoObj:
+			return check(o)
+		case model.ExpectedRepoObj:
+			return check(&o)
+		}
+		return nil
+	}
+}
+
+// Answers the values that are not empty
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ShippedRules(t *testing.T) {
+	obj := &model.ExpectedRepoObj{PublisherCountry: []string{"United States"}}
+	violations := DefaultRules.Evaluate(obj)
+	assert.Equal(t, 2, len(violations))
+	assert.Equal(t, "publisher-country-requires-date-published", violations[0].Rule)
+	assert.Equal(t, "publisher-country-requires-publisher", violations[1].Rule)
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertRules(rec, obj, nil))
+	assert.Contains(t, rec.String(), "violates rule publisher-country-requires-date-published")
+	assert.Contains(t, rec.String(), "violates rule publisher-country-requires-publisher")
+
+	obj.DatePublished = []string{"1941"}
+	obj.Publisher = []string{"Ansel Adams"}
+	obj.GeoportalLink = model.Link{Uri: "https://geo.example.org/layer/1"}
+	obj.SpatialCoverage = []string{"Hernandez, New Mexico"}
+	assert.Empty(t, DefaultRules.Evaluate(obj))
+	assert.True(t, AssertRules(t, *obj, nil))
+
+	obj.SpatialCoverage = []string{""}
+	violations = DefaultRules.Evaluate(obj)
+	assert.Equal(t, 2, len(violations))
+
+	// rules for repository objects do not apply to other entities
+	assert.Empty(t, DefaultRules.Evaluate(&model.ExpectedSubject{}))
+}
+
+func Test_Rules(t *testing.T) {
+	errNoTitle := errors.New("no title")
+	r := NewRules(Rule{Name: "title-required", Check: func(e model.ExpectedEntity) error {
+		if o, ok := e.(*model.ExpectedRepoObj); ok && o.Title == "" {
+			return errNoTitle
+		}
+		return nil
+	}})
+	assert.Panics(t, func() {
+		r.Register(Rule{Name: "title-required", Check: func(model.ExpectedEntity) error { return nil }})
+	})
+	assert.Panics(t, func() { r.Register(Rule{Name: "unchecked"}) })
+
+	violations := r.Evaluate(&model.ExpectedRepoObj{})
+	assert.Equal(t, 1, len(violations))
+	assert.ErrorIs(t, violations[0].Err, errNoTitle)
+
+	assert.Equal(t, []string{"title-required"}, r.Names())
+	assert.True(t, r.Remove("title-required"))
+	assert.False(t, r.Remove("title-required"))
+	assert.Empty(t, r.Evaluate(&model.ExpectedRepoObj{}))
+}
@@ -0,0 +1,241 @@
+package verify
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// A Scenario declares a multi-step verification flow, e.g. resolve a repository object, wait for its derivatives,
+// then verify its metadata and media.  Steps share State, so later steps may use the resources resolved by earlier
+// steps.  Scenarios are built by chaining:
+//
+//	s := verify.NewScenario("image object").
+//		Resolve("obj", &jsonapi.JsonApiUrl{...}).
+//		WaitUntil("thumbnail", 2*time.Minute, 5*time.Second, thumbnailExists).
+//		VerifyMetadata("obj", checkTitle).
+//		VerifyMedia("obj", "image", checkImages).When(isImage)
+//
+// A Scenario may be executed any number of times; each execution begins with empty State.
+type Scenario struct {
+	Name  string
+	steps []*Step
+}
+
+// A single step of a Scenario
+type Step struct {
+	Name string
+	// Answers whether the step ought to run; steps without a condition are always run
+	Condition func(s *State) bool
+	// Performs the step, answering an error if the step fails
+	Run func(s *State) error
+}
+
+// The state shared by the steps of an executing Scenario
+type State struct {
+	values map[string]interface{}
+	// The JsonApiUrl used to resolve each resource, keyed by the same key as the resource
+	urls map[string]*jsonapi.JsonApiUrl
+}
+
+// The outcome of a single Step
+type StepResult struct {
+	Name string
+	// True if the step's condition was not satisfied, or an earlier step failed
+	Skipped  bool
+	Err      error
+	Duration time.Duration
+}
+
+// The outcome of executing a Scenario
+type ScenarioResult struct {
+	Name  string
+	Steps []StepResult
+}
+
+// Creates an empty Scenario
+func NewScenario(name string) *Scenario {
+	return &Scenario{Name: name}
+}
+
+// Adds an arbitrary step to the scenario
+func (sc *Scenario) Step(step Step) *Scenario {
+	sc.steps = append(sc.steps, &step)
+	return sc
+}
+
+// Makes the most recently added step conditional: it is only run if the condition answers true when the step is
+// reached
+func (sc *Scenario) When(condition func(s *State) bool) *Scenario {
+	if len(sc.steps) == 0 {
+		panic("verify: When must follow the step it applies to")
+	}
+	sc.steps[len(sc.steps)-1].Condition = condition
+	return sc
+}
+
+// Adds a step which retrieves the single resource matched by the JsonApiUrl and stores the *jsonapi.JsonApiResponse in
+// the State under the supplied key
+func (sc *Scenario) Resolve(key string, u *jsonapi.JsonApiUrl) *Scenario {
+	return sc.Step(Step{
+		Name: "resolve " + key,
+		Run: func(s *State) error {
+			res := &jsonapi.JsonApiResponse{}
+			if err := u.FetchSingle(res); err != nil {
+				return err
+			}
+			s.Set(key, res)
+			s.urls[key] = u
+			return nil
+		},
+	})
+}
+
+// Adds a step which pauses for the supplied duration
+func (sc *Scenario) Wait(d time.Duration) *Scenario {
+	return sc.Step(Step{
+		Name: fmt.Sprintf("wait %s", d),
+		Run: func(s *State) error {
+			time.Sleep(d)
+			return nil
+		},
+	})
+}
+
+// Adds a step which polls the condition every interval until it answers nil, failing with the condition's last error
+// if the timeout elapses first
+func (sc *Scenario) WaitUntil(name string, timeout, interval time.Duration, condition func(s *State) error) *Scenario {
+	return sc.Step(Step{
+		Name: "wait until " + name,
+		Run: func(s *State) error {
+			deadline := time.Now().Add(timeout)
+			for {
+				err := condition(s)
+				if err == nil {
+					return nil
+				}
+				if time.Now().Add(interval).After(deadline) {
+					return fmt.Errorf("timed out after %s: %w", timeout, err)
+				}
+				time.Sleep(interval)
+			}
+		},
+	})
+}
+
+// Adds a step which runs an arbitrary verification function
+func (sc *Scenario) Verify(name string, verify func(s *State) error) *Scenario {
+	return sc.Step(Step{Name: "verify " + name, Run: verify})
+}
+
+// Adds a step which verifies the metadata of the resource resolved under the supplied key
+func (sc *Scenario) VerifyMetadata(key string, verify func(data map[string]interface{}) error) *Scenario {
+	return sc.Step(Step{
+		Name: "verify metadata of " + key,
+		Run: func(s *State) error {
+			data, err := s.resource(key)
+			if err != nil {
+				return err
+			}
+			return verify(data)
+		},
+	})
+}
+
+// Adds a step which retrieves the media of the supplied bundle (e.g. `image`) that are media of the resource resolved
+// under the supplied key, and verifies them.  The media are retrieved with the base url and credentials used to
+// resolve the resource.
+func (sc *Scenario) VerifyMedia(key, bundle string, verify func(media *jsonapi.JsonApiResponse) error) *Scenario {
+	return sc.Step(Step{
+		Name: fmt.Sprintf("verify %s media of %s", bundle, key),
+		Run: func(s *State) error {
+			data, err := s.resource(key)
+			if err != nil {
+				return err
+			}
+			resolvedBy, ok := s.urls[key]
+			if !ok {
+				return fmt.Errorf("the resource '%s' was not retrieved by a Resolve step", key)
+			}
+			u := *resolvedBy
+			u.DrupalEntity, u.DrupalBundle = "media", bundle
+			u.Filter, u.Value, u.RawFilter = "field_media_of.id", fmt.Sprintf("%v", data["id"]), ""
+			media := &jsonapi.JsonApiResponse{}
+			if err := u.Fetch(media); err != nil {
+				return err
+			}
+			return verify(media)
+		},
+	})
+}
+
+// Adds a step which verifies that the search index (e.g. Solr) reflects the scenario's resources
+func (sc *Scenario) VerifySearch(name string, verify func(s *State) error) *Scenario {
+	return sc.Step(Step{Name: "verify search " + name, Run: verify})
+}
+
+// Executes each step of the scenario in order.  Steps whose condition is not satisfied are skipped.  Once a step
+// fails, the remaining steps are skipped, because they may depend on state the failed step did not provide.
+func (sc *Scenario) Execute() *ScenarioResult {
+	state := &State{values: map[string]interface{}{}, urls: map[string]*jsonapi.JsonApiUrl{}}
+	result := &ScenarioResult{Name: sc.Name}
+	failed := false
+	for _, step := range sc.steps {
+		if failed || (step.Condition != nil && !step.Condition(state)) {
+			result.Steps = append(result.Steps, StepResult{Name: step.Name, Skipped: true})
+			continue
+		}
+		start := time.Now()
+		err := step.Run(state)
+		result.Steps = append(result.Steps, StepResult{Name: step.Name, Err: err, Duration: time.Since(start)})
+		failed = err != nil
+	}
+	return result
+}
+
+// Executes the scenario, reporting each step as a subtest of the supplied test
+func (sc *Scenario) Run(t *testing.T) {
+	for _, step := range sc.Execute().Steps {
+		step := step
+		t.Run(step.Name, func(t *testing.T) {
+			if step.Skipped {
+				t.Skip("step skipped")
+			}
+			if step.Err != nil {
+				t.Error(step.Err)
+			}
+		})
+	}
+}
+
+// Answers the first error encountered by the scenario, or nil if every step that ran succeeded
+func (r *ScenarioResult) Err() error {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return fmt.Errorf("scenario '%s' failed at step '%s': %w", r.Name, step.Name, step.Err)
+		}
+	}
+	return nil
+}
+
+// Stores a value in the state, replacing any value previously stored under the key
+func (s *State) Set(key string, value interface{}) {
+	s.values[key] = value
+}
+
+// Answers the value stored under the key, and whether it is present
+func (s *State) Get(key string) (interface{}, bool) {
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Answers the data element of the resource resolved under the supplied key
+func (s *State) resource(key string) (map[string]interface{}, error) {
+	v, ok := s.Get(key)
+	if res, isRes := v.(*jsonapi.JsonApiResponse); ok && isRes && len(res.Data) == 1 {
+		return res.Data[0], nil
+	}
+	return nil, fmt.Errorf("no resource has been resolved under the key '%s'", key)
+}
@@ -0,0 +1,95 @@
+package verify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScenarioServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/node/islandora_object":
+			w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "o1", "attributes": {"title": "Moonrise"}}]}`))
+		case "/jsonapi/media/image":
+			if r.URL.Query().Get("filter[field_media_of.id]") == "o1" {
+				w.Write([]byte(`{"data": [{"type": "media--image", "id": "m1"}, {"type": "media--image", "id": "m2"}]}`))
+			} else {
+				w.Write([]byte(`{"data": []}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func Test_ScenarioExecute(t *testing.T) {
+	server := newScenarioServer()
+	defer server.Close()
+
+	polls := 0
+	isImage := func(s *State) bool {
+		v, _ := s.Get("isImage")
+		return v == true
+	}
+	sc := NewScenario("image object").
+		Resolve("obj", &jsonapi.JsonApiUrl{BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise"}).
+		WaitUntil("derivatives", time.Second, time.Millisecond, func(s *State) error {
+			if polls++; polls < 3 {
+				return errors.New("not yet")
+			}
+			s.Set("isImage", true)
+			return nil
+		}).
+		VerifyMetadata("obj", func(data map[string]interface{}) error {
+			assert.Equal(t, "Moonrise", data["attributes"].(map[string]interface{})["title"])
+			return nil
+		}).
+		VerifyMedia("obj", "image", func(media *jsonapi.JsonApiResponse) error {
+			assert.Equal(t, 2, len(media.Data))
+			return nil
+		}).When(isImage).
+		Verify("never", func(s *State) error { return errors.New("should not run") }).When(func(s *State) bool { return false })
+
+	result := sc.Execute()
+	require.Nil(t, result.Err())
+	require.Equal(t, 5, len(result.Steps))
+	assert.Equal(t, 3, polls)
+	assert.False(t, result.Steps[3].Skipped)
+	assert.True(t, result.Steps[4].Skipped)
+}
+
+func Test_ScenarioFailureSkipsRemainingSteps(t *testing.T) {
+	ran := false
+	result := NewScenario("failing").
+		Verify("first", func(s *State) error { return errors.New("boom") }).
+		Verify("second", func(s *State) error { ran = true; return nil }).
+		Execute()
+
+	assert.False(t, ran)
+	assert.True(t, result.Steps[1].Skipped)
+	assert.EqualError(t, result.Err(), "scenario 'failing' failed at step 'verify first': boom")
+}
+
+func Test_ScenarioWaitUntilTimesOut(t *testing.T) {
+	result := NewScenario("timeout").
+		WaitUntil("never", 5*time.Millisecond, time.Millisecond, func(s *State) error { return errors.New("not ready") }).
+		Execute()
+
+	assert.NotNil(t, result.Err())
+	assert.Contains(t, result.Err().Error(), "not ready")
+}
+
+func Test_ScenarioVerifyMetadataRequiresResolve(t *testing.T) {
+	result := NewScenario("unresolved").
+		VerifyMetadata("obj", func(data map[string]interface{}) error { return nil }).
+		Execute()
+
+	assert.NotNil(t, result.Err())
+}
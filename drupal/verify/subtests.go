@@ -0,0 +1,112 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/diff"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Options of RunAsSubtests
+type SubtestOptions struct {
+	// Verifies each fixture
+	Engine *Engine
+	// Runs the subtests in parallel with each other (see testing.T.Parallel).  Note that parallel subtests run once
+	// the test function invoking RunAsSubtests returns.
+	Parallel bool
+	// Invoked with the result of each fixture, e.g. to add it to a report.  Invoked from a single goroutine at a
+	// time, even if Parallel is true.
+	OnResult func(r *Result)
+}
+
+// Verifies each fixture in its own subtest of t, named by the bundle and the title (or name) of the fixture, e.g.
+// `TestFixtures/islandora_object/Moonrise`.  Each fixture is a `.json` file, or a directory whose `.json` files (and
+// those of its subdirectories) are verified in lexical order.  Fixtures are merged with their defaults (see
+// LoadFixture).  Subtests give each entity its own pass or fail line in `go test -v` output, and may be selected
+// using `-run`, e.g. `go test -run 'TestFixtures/subject/.*Photography'`:
+//
+//	func TestFixtures(t *testing.T) {
+//		verify.RunAsSubtests(t, []string{"testdata/expected"}, verify.SubtestOptions{Engine: engine, Parallel: true})
+//	}
+func RunAsSubtests(t *testing.T, fixtures []string, opts SubtestOptions) {
+	var paths []string
+	for _, f := range fixtures {
+		if info, err := os.Stat(f); err == nil && info.IsDir() {
+			dirPaths, err := FixturePaths(f)
+			if !assert.Nil(t, err, "error listing the fixtures of %s: %s", f, err) {
+				return
+			}
+			paths = append(paths, dirPaths...)
+		} else {
+			paths = append(paths, f)
+		}
+	}
+
+	mu := &sync.Mutex{}
+	for _, path := range paths {
+		path := path
+		b, err := LoadFixture(path)
+		t.Run(subtestName(path, b), func(t *testing.T) {
+			if opts.Parallel {
+				t.Parallel()
+			}
+			var r *Result
+			if err != nil {
+				r = &Result{Fixture: path, Err: err}
+			} else {
+				r = opts.Engine.VerifyJson(b)
+				r.Fixture = path
+			}
+			if opts.OnResult != nil {
+				mu.Lock()
+				opts.OnResult(r)
+				mu.Unlock()
+			}
+			AssertResult(t, r)
+		})
+	}
+}
+
+// Asserts that the result passed, failing once for its error, and once for each of its mismatches and violations
+func AssertResult(t assert.TestingT, r *Result) bool {
+	if r.Err != nil {
+		return assert.Fail(t, "unable to verify fixture", "%s: %s", r.Fixture, r.Err)
+	}
+	ok := true
+	color := diff.Colorize()
+	for _, m := range r.Mismatches {
+		ok = assert.Fail(t, "live entity differs from fixture", "%s", m.Format(color)) && ok
+	}
+	for _, v := range r.Violations {
+		ok = assert.Fail(t, "fixture violates a rule", "%s", v) && ok
+	}
+	return ok
+}
+
+// Answers the name of the subtest of the fixture: its bundle and the title, name, filename, PID or filter value
+// identifying it, or the base name of its file if it cannot be read
+func subtestName(path string, b []byte) string {
+	fixture := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return filepath.Base(path)
+	}
+	bundle, _ := fixture["bundle"].(string)
+	for _, key := range []string{"title", "name", "filename", pidKey, "value"} {
+		if v, ok := fixture[key].(string); ok && v != "" {
+			if absent, _ := fixture[model.AbsentKey].(bool); absent {
+				v = "absent " + v
+			}
+			return bundle + "/" + strings.ReplaceAll(v, "/", "_")
+		}
+	}
+	return bundle + "/" + filepath.Base(path)
+}
@@ -0,0 +1,55 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunAsSubtests(t *testing.T) {
+	m := newEngineServer()
+	defer m.Close()
+
+	dir := t.TempDir()
+	require.Nil(t, os.Mkdir(filepath.Join(dir, "terms"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "terms", "genre.json"), []byte(`{"type": "taxonomy_term", "bundle": "genre", "name": "Maps"}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "object.json"), []byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise"}`), 0644))
+	absent := filepath.Join(t.TempDir(), "absent.json")
+	require.Nil(t, ioutil.WriteFile(absent, []byte(`{"type": "node", "bundle": "islandora_object", "absent": true, "value": "Moonset"}`), 0644))
+
+	var keys []string
+	t.Run("fixtures", func(t *testing.T) {
+		RunAsSubtests(t, []string{dir, absent}, SubtestOptions{Engine: NewEngine(m.URL, "", ""), Parallel: true,
+			OnResult: func(r *Result) { keys = append(keys, r.Key) }})
+	})
+	assert.ElementsMatch(t, []string{"Moonrise", "Maps", "Moonset"}, keys)
+}
+
+func Test_AssertResult(t *testing.T) {
+	assert.True(t, AssertResult(t, &Result{}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertResult(rec, &Result{Mismatches: []Mismatch{{Path: "genre", Expected: "Maps"}}}))
+	assert.Contains(t, rec.String(), `genre: expected "Maps", got nothing`)
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertResult(rec, &Result{Err: errors.New("no resource matched")}))
+	assert.Contains(t, rec.String(), "unable to verify fixture")
+	assert.Contains(t, rec.String(), "no resource matched")
+}
+
+func Test_SubtestName(t *testing.T) {
+	assert.Equal(t, "islandora_object/Moonrise", subtestName("a.json", []byte(`{"bundle": "islandora_object", "title": "Moonrise"}`)))
+	assert.Equal(t, "subject/Analog Photography", subtestName("a.json", []byte(`{"bundle": "subject", "name": "Analog Photography"}`)))
+	assert.Equal(t, "islandora_object/islandora:1234", subtestName("a.json", []byte(`{"bundle": "islandora_object", "pid": "islandora:1234"}`)))
+	assert.Equal(t, "islandora_object/absent Moonset", subtestName("a.json", []byte(`{"bundle": "islandora_object", "absent": true, "value": "Moonset"}`)))
+	assert.Equal(t, "islandora_object/AC_DC", subtestName("a.json", []byte(`{"bundle": "islandora_object", "title": "AC/DC"}`)))
+	assert.Equal(t, "a.json", subtestName("testdata/a.json", []byte(`[]`)))
+}
@@ -0,0 +1,32 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// Answers the normalized form of a text value: runs of whitespace (including line breaks) are replaced by a single
+// space, and leading and trailing whitespace is removed.  Normalization makes comparisons insensitive to the line
+// endings and wrapping introduced by spreadsheets and serializers.
+func NormalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Answers the hex-encoded SHA-256 of the normalized text value.  Fixtures may carry the hash of a very large value
+// (e.g. a table of contents or an abstract) instead of the value itself; see model.LanguageString.
+func TextSha256(s string) string {
+	sum := sha256.Sum256([]byte(NormalizeText(s)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Answers true if the actual value matches the expected value.  If the expected value carries a SHA-256, the
+// actual value is hashed and compared to it, otherwise the normalized values are compared.
+func EqualText(expected model.LanguageString, actual string) bool {
+	if expected.Sha256 != "" {
+		return strings.EqualFold(expected.Sha256, TextSha256(actual))
+	}
+	return NormalizeText(expected.Value) == NormalizeText(actual)
+}
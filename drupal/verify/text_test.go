@@ -0,0 +1,42 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TextSha256(t *testing.T) {
+	assert.Equal(t, "Chapter 1. Moonrise Chapter 2. Hernandez", NormalizeText("  Chapter 1. Moonrise\r\n\tChapter 2.  Hernandez\n"))
+	assert.Equal(t, TextSha256("Chapter 1. Moonrise Chapter 2. Hernandez"), TextSha256("Chapter 1. Moonrise\r\nChapter 2. Hernandez\n"))
+	// echo -n "Moonrise" | sha256sum
+	assert.Equal(t, "cc575d66b09dece754136a837fd34fd9f46f67919e302db84adde6193cc3a597", TextSha256("Moonrise"))
+}
+
+func Test_AssertTexts(t *testing.T) {
+	toc := "Chapter 1. Moonrise\nChapter 2. Hernandez"
+	byValue := model.LanguageString{Value: "Chapter 1. Moonrise Chapter 2. Hernandez", LangCode: "en"}
+	byHash := model.LanguageString{Sha256: TextSha256(toc), LangCode: "en"}
+
+	assert.True(t, AssertText(t, byValue, toc))
+	assert.True(t, AssertText(t, byHash, toc))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertText(rec, byHash, "Chapter 1. Moonset"))
+	assert.Contains(t, rec.String(), "SHA-256 of the normalized value differs")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertText(rec, byValue, "Chapter 1. Moonset"))
+	assert.Contains(t, rec.String(), "normalized values differ")
+
+	actual := make([]model.JsonApiLanguageValue, 2)
+	actual[0].Meta.Value = toc
+	actual[1].Meta.Value = "Chapter 3"
+	assert.True(t, AssertTexts(t, []model.LanguageString{byHash, {Value: "Chapter 3"}}, actual))
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertTexts(rec, []model.LanguageString{byHash}, actual))
+	assert.Contains(t, rec.String(), "number of values differ")
+}
@@ -0,0 +1,190 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// The translatable attributes of a taxonomy term, as retrieved from the JSON API
+type jsonApiTermTranslation struct {
+	JsonApiData []struct {
+		JsonApiAttributes struct {
+			Langcode    string
+			Name        string
+			Description struct {
+				Value     string
+				Format    string
+				Processed string
+			}
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// Retrieves the translation of the term identified by its vocabulary and UUID into the language identified by
+// langcode (e.g. `es`).  An error is answered if the term does not exist, or if Drupal answers a different language,
+// which is how Drupal responds when the term has not been translated.
+func FetchTermTranslation(r *jsonapi.TermResolver, vocabulary, id, langcode string) (*model.ExpectedTermTranslation, error) {
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      r.BaseUrl,
+		DrupalEntity: model.TaxonomyTerm,
+		DrupalBundle: vocabulary,
+		Filter:       "id",
+		Value:        id,
+		Username:     r.Username,
+		Password:     r.Password,
+		Langcode:     langcode,
+	}
+	res := &jsonApiTermTranslation{}
+	if err := u.FetchSingle(res); err != nil {
+		return nil, err
+	}
+
+	attrs := res.JsonApiData[0].JsonApiAttributes
+	if attrs.Langcode != langcode {
+		return nil, fmt.Errorf("term %s in vocabulary '%s' has no '%s' translation (Drupal answered '%s')",
+			id, vocabulary, langcode, attrs.Langcode)
+	}
+
+	translation := &model.ExpectedTermTranslation{Langcode: attrs.Langcode, Name: attrs.Name}
+	translation.Description.Value = attrs.Description.Value
+	translation.Description.Format = attrs.Description.Format
+	translation.Description.Processed = attrs.Description.Processed
+	return translation, nil
+}
+
+// Answered, wrapped, when Drupal answers a different language than the language requested, which is how Drupal
+// responds when the entity has not been translated
+var ErrNoTranslation = errors.New("no translation")
+
+// The resource of the languages configured by the site
+const configurableLanguage = "configurable_language"
+
+// Answers the language codes configured by the site at the base url, e.g. `en` and `es`, ordered by their weight.  The
+// locked languages `und` (not specified) and `zxx` (not applicable) are omitted.
+func FetchLanguages(baseUrl, username, password string) ([]string, error) {
+	u := &jsonapi.JsonApiUrl{BaseUrl: baseUrl, DrupalEntity: configurableLanguage, DrupalBundle: configurableLanguage,
+		Username: username, Password: env.Secret(password)}
+	type language struct {
+		code   string
+		weight float64
+	}
+	var languages []language
+	err := u.FetchPages(func(page *jsonapi.JsonApiPage) error {
+		for _, d := range page.Data {
+			attributes, _ := d["attributes"].(map[string]interface{})
+			if locked, _ := attributes["locked"].(bool); locked {
+				continue
+			}
+			l := language{}
+			l.code, _ = attributes["drupal_internal__id"].(string)
+			l.weight, _ = attributes["weight"].(float64)
+			languages = append(languages, l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].weight < languages[j].weight })
+	codes := make([]string, 0, len(languages))
+	for _, l := range languages {
+		codes = append(codes, l.code)
+	}
+	return codes, nil
+}
+
+// Retrieves the translation of the entity of the type and bundle with the UUID into the language identified by
+// langcode, using the language prefix of the JSON API (e.g. `/es/jsonapi/node/islandora_object`), or no prefix if the
+// prefixed request fails and the unprefixed request answers the language.  The title (or name)
+// and the first description of the entity are answered.  An error wrapping ErrNoTranslation is answered if Drupal
+// answers a different language.
+func FetchTranslation(baseUrl, username, password, entityType, bundle, id, langcode string) (*model.ExpectedTranslation, error) {
+	u := &jsonapi.JsonApiUrl{
+		BaseUrl:      baseUrl,
+		DrupalEntity: entityType,
+		DrupalBundle: bundle,
+		Filter:       "id",
+		Value:        id,
+		Username:     username,
+		Password:     env.Secret(password),
+		Langcode:     langcode,
+	}
+	res := &jsonapi.JsonApiResponse{}
+	if err := u.FetchSingle(res); err != nil {
+		// the default language commonly has no prefix, so it is also retrieved without one
+		u.Langcode = ""
+		if u.FetchSingle(res) != nil || langcodeOf(res.Data[0]) != langcode {
+			return nil, err
+		}
+	}
+
+	data := res.Data[0]
+	attributes, _ := data["attributes"].(map[string]interface{})
+	actual := langcodeOf(data)
+	if actual != langcode {
+		return nil, fmt.Errorf("%w: %s--%s %s has no '%s' translation (Drupal answered '%s')", ErrNoTranslation,
+			entityType, bundle, id, langcode, actual)
+	}
+	translation := &model.ExpectedTranslation{Langcode: actual, Description: descriptionOf(data)}
+	if title, ok := attributes["title"].(string); ok {
+		translation.Title = title
+	} else {
+		translation.Title, _ = attributes["name"].(string)
+	}
+	return translation, nil
+}
+
+// Retrieves the translations of the entity of the type and bundle with the UUID into each of the languages, or into
+// each language of the site if none are supplied (see FetchLanguages).  Languages into which the entity has not been
+// translated are omitted, so the translations answered are those available.
+func FetchTranslations(baseUrl, username, password, entityType, bundle, id string, langcodes ...string) ([]model.ExpectedTranslation, error) {
+	if len(langcodes) == 0 {
+		var err error
+		if langcodes, err = FetchLanguages(baseUrl, username, password); err != nil {
+			return nil, err
+		}
+	}
+	var translations []model.ExpectedTranslation
+	for _, langcode := range langcodes {
+		translation, err := FetchTranslation(baseUrl, username, password, entityType, bundle, id, langcode)
+		if errors.Is(err, ErrNoTranslation) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, *translation)
+	}
+	return translations, nil
+}
+
+// Answers the first description of the resource object: the value of the `description` attribute of a taxonomy term,
+// or of the first `field_description` of a node, which IDC carries as the meta of a relationship to its language
+func descriptionOf(data map[string]interface{}) string {
+	attributes, _ := data["attributes"].(map[string]interface{})
+	if d, ok := attributes["description"].(map[string]interface{}); ok {
+		value, _ := d["value"].(string)
+		return value
+	}
+	relationships, _ := data["relationships"].(map[string]interface{})
+	rel, _ := relationships["field_description"].(map[string]interface{})
+	refs, _ := rel["data"].([]interface{})
+	if len(refs) == 0 {
+		return ""
+	}
+	ref, _ := refs[0].(map[string]interface{})
+	meta, _ := ref["meta"].(map[string]interface{})
+	value, _ := meta["value"].(string)
+	return value
+}
+
+func langcodeOf(data map[string]interface{}) string {
+	attributes, _ := data["attributes"].(map[string]interface{})
+	langcode, _ := attributes["langcode"].(string)
+	return langcode
+}
@@ -0,0 +1,111 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertTermTranslations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/taxonomy_term/subject":
+			require.Equal(t, "Analog Photography", r.URL.Query().Get("filter[name]"))
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "s1", "attributes": {"langcode": "en", "name": "Analog Photography"}}]}`))
+		case "/es/jsonapi/taxonomy_term/subject":
+			require.Equal(t, "s1", r.URL.Query().Get("filter[id]"))
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "s1", "attributes": {"langcode": "es", "name": "Fotografía analógica", "description": {"value": "<p>Descripción</p>", "format": "basic_html"}}}]}`))
+		case "/fr/jsonapi/taxonomy_term/subject":
+			// untranslated terms are answered in the default language
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "s1", "attributes": {"langcode": "en", "name": "Analog Photography"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := jsonapi.NewTermResolver(server.URL, "", "")
+
+	translation, err := FetchTermTranslation(r, model.Subject, "s1", "es")
+	require.Nil(t, err)
+	assert.Equal(t, "Fotografía analógica", translation.Name)
+	assert.Equal(t, "basic_html", translation.Description.Format)
+
+	_, err = FetchTermTranslation(r, model.Subject, "s1", "fr")
+	assert.NotNil(t, err)
+
+	expected := model.ExpectedSubject{}
+	expected.Type, expected.Bundle, expected.Name = model.TaxonomyTerm, model.Subject, "Analog Photography"
+	expected.Translations = []model.ExpectedTermTranslation{{Langcode: "es", Name: "Fotografía analógica"}}
+	expected.Translations[0].Description.Value = "<p>Descripción</p>"
+	expected.Translations[0].Description.Format = "basic_html"
+	assert.True(t, AssertTermTranslations(t, r, expected))
+
+	expected.Translations = append(expected.Translations, model.ExpectedTermTranslation{Langcode: "fr", Name: "Photographie argentique"})
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertTermTranslations(rec, r, expected))
+	assert.Contains(t, rec.String(), "unable to retrieve the 'fr' translation of term 'Analog Photography'")
+}
+
+func Test_FetchTranslations(t *testing.T) {
+	object := func(langcode, title, description string) string {
+		return `{"data": [{"type": "node--islandora_object", "id": "n1", "attributes": {"langcode": "` + langcode + `", "title": "` + title + `"},
+			"relationships": {"field_description": {"data": [{"type": "taxonomy_term--language", "id": "l1", "meta": {"value": "` + description + `"}}]}}}]}`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/configurable_language/configurable_language":
+			w.Write([]byte(`{"data": [
+				{"attributes": {"drupal_internal__id": "und", "locked": true, "weight": 2}},
+				{"attributes": {"drupal_internal__id": "fr", "locked": false, "weight": 1}},
+				{"attributes": {"drupal_internal__id": "es", "locked": false, "weight": 0}},
+				{"attributes": {"drupal_internal__id": "en", "locked": false, "weight": -1}}]}`))
+		case "/jsonapi/node/islandora_object", "/fr/jsonapi/node/islandora_object":
+			require.Equal(t, "n1", r.URL.Query().Get("filter[id]"))
+			w.Write([]byte(object("en", "Moonrise", "A moon rising")))
+		case "/es/jsonapi/node/islandora_object":
+			w.Write([]byte(object("es", "Salida de la luna", "Una luna saliendo")))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	languages, err := FetchLanguages(server.URL, "", "")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"en", "es", "fr"}, languages)
+
+	translations, err := FetchTranslations(server.URL, "", "", model.Node, model.RepositoryObject, "n1")
+	require.Nil(t, err)
+	assert.Equal(t, []model.ExpectedTranslation{
+		{Langcode: "en", Title: "Moonrise", Description: "A moon rising"},
+		{Langcode: "es", Title: "Salida de la luna", Description: "Una luna saliendo"},
+	}, translations)
+
+	_, err = FetchTranslation(server.URL, "", "", model.Node, model.RepositoryObject, "n1", "fr")
+	assert.True(t, errors.Is(err, ErrNoTranslation))
+	_, err = FetchTranslations(server.URL, "", "", model.Node, model.RepositoryObject, "n1", "de")
+	assert.NotNil(t, err)
+
+	assert.True(t, AssertTranslations(t, server.URL, "", "", model.Node, model.RepositoryObject, "n1",
+		model.ExpectedTranslation{Langcode: "es", Title: "Salida de la luna"},
+		model.ExpectedTranslation{Langcode: "es", Title: "Salida de la luna", Description: "Una luna saliendo"}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertTranslations(rec, server.URL, "", "", model.Node, model.RepositoryObject, "n1",
+		model.ExpectedTranslation{Langcode: "es", Title: "Salida de la luna", Description: "La luna"}))
+	assert.Contains(t, rec.String(), "description of the 'es' translation differs")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertTranslations(rec, server.URL, "", "", model.Node, model.RepositoryObject, "n1",
+		model.ExpectedTranslation{Langcode: "fr", Title: "Lever de lune"}))
+	assert.Contains(t, rec.String(), "unable to retrieve the 'fr' translation of node--islandora_object n1")
+}
@@ -0,0 +1,133 @@
+// Provides comparisons and assertions between Expected entities and the resources retrieved from the JSON API
+package verify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Configures the canonicalization of URIs prior to their comparison
+type UriOption func(c *uriCanon)
+
+// Treats the `http` and `https` schemes as equivalent, e.g. `http://id.loc.gov/authorities/names/n79021164` will equal
+// `https://id.loc.gov/authorities/names/n79021164`
+func IgnoreScheme() UriOption {
+	return func(c *uriCanon) {
+		c.ignoreScheme = true
+	}
+}
+
+// Ports that are removed from the canonical form of a URI, keyed by scheme
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+type uriCanon struct {
+	ignoreScheme bool
+}
+
+// Answers true if the supplied URIs are equal after both are canonicalized by CanonicalUri
+func EqualUri(expected, actual string, opts ...UriOption) bool {
+	return CanonicalUri(expected, opts...) == CanonicalUri(actual, opts...)
+}
+
+// Answers the canonical form of the supplied URI:
+//   - the scheme and host are lower-cased, and default ports (80 and 443) are removed
+//   - percent-encoded unreserved characters are decoded, other percent-encodings use upper-case hex digits, and
+//     characters which must be encoded (e.g. spaces) are percent-encoded
+//   - trailing slashes are removed from the path, so `http://id.loc.gov/` equals `http://id.loc.gov`
+//   - if IgnoreScheme is supplied, `https` is replaced by `http`
+//
+// Values that cannot be parsed as a URI are answered with surrounding whitespace removed, and are otherwise unmodified.
+func CanonicalUri(uri string, opts ...UriOption) string {
+	c := &uriCanon{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	uri = strings.TrimSpace(uri)
+	u, err := url.Parse(uri)
+	if err != nil || u.Opaque != "" {
+		return uri
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if c.ignoreScheme && scheme == "https" {
+		scheme = "http"
+	}
+
+	host := strings.ToLower(u.Host)
+	if port := u.Port(); port != "" && port == defaultPorts[strings.ToLower(u.Scheme)] {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+
+	var b strings.Builder
+	if scheme != "" {
+		b.WriteString(scheme)
+		b.WriteString(":")
+	}
+	if host != "" || u.User != nil {
+		b.WriteString("//")
+		if u.User != nil {
+			b.WriteString(u.User.String())
+			b.WriteString("@")
+		}
+		b.WriteString(host)
+	}
+	b.WriteString(strings.TrimRight(normalizePercentEncoding(u.EscapedPath()), "/"))
+	if u.RawQuery != "" || u.ForceQuery {
+		b.WriteString("?")
+		b.WriteString(normalizePercentEncoding(u.RawQuery))
+	}
+	if u.Fragment != "" {
+		b.WriteString("#")
+		b.WriteString(normalizePercentEncoding(u.EscapedFragment()))
+	}
+	return b.String()
+}
+
+// Decodes percent-encoded unreserved characters, upper-cases the hex digits of the remaining percent-encodings, and
+// percent-encodes characters that are neither reserved nor unreserved (RFC 3986 section 2)
+func normalizePercentEncoding(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			decoded := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(decoded) {
+				b.WriteByte(decoded)
+			} else {
+				b.WriteString(fmt.Sprintf("%%%02X", decoded))
+			}
+			i += 2
+		case isUnreserved(ch) || strings.IndexByte(":/?#[]@!$&'()*+,;=%", ch) >= 0:
+			b.WriteByte(ch)
+		default:
+			b.WriteString(fmt.Sprintf("%%%02X", ch))
+		}
+	}
+	return b.String()
+}
+
+func isUnreserved(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9' ||
+		ch == '-' || ch == '.' || ch == '_' || ch == '~'
+}
+
+func isHex(ch byte) bool {
+	return '0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func unhex(ch byte) byte {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return ch - '0'
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10
+	default:
+		return ch - 'A' + 10
+	}
+}
@@ -0,0 +1,83 @@
+//go:build !notestify
+// +build !notestify
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/asserttest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CanonicalUri(t *testing.T) {
+	cases := []struct {
+		uri      string
+		expected string
+	}{
+		{"http://id.loc.gov/authorities/names/n79021164", "http://id.loc.gov/authorities/names/n79021164"},
+		{"http://id.loc.gov/authorities/names/n79021164/", "http://id.loc.gov/authorities/names/n79021164"},
+		{"HTTP://ID.LOC.GOV:80/", "http://id.loc.gov"},
+		{"https://id.loc.gov:443/a", "https://id.loc.gov/a"},
+		{"https://id.loc.gov:8443/a", "https://id.loc.gov:8443/a"},
+		{"http://www.google.com?q=Analog%20Photography", "http://www.google.com?q=Analog%20Photography"},
+		{"http://www.google.com?q=Analog Photography", "http://www.google.com?q=Analog%20Photography"},
+		{"http://example.org/%7euser/%2f%41", "http://example.org/~user/%2FA"},
+		{"  http://example.org/a#frag  ", "http://example.org/a#frag"},
+		{"urn:isbn:0451450523", "urn:isbn:0451450523"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, CanonicalUri(c.uri), "canonicalizing %s", c.uri)
+	}
+}
+
+func Test_EqualUriScheme(t *testing.T) {
+	assert.False(t, EqualUri("http://id.loc.gov/vocabulary/iso639-2/eng", "https://id.loc.gov/vocabulary/iso639-2/eng/"))
+	assert.True(t, EqualUri("http://id.loc.gov/vocabulary/iso639-2/eng", "https://id.loc.gov/vocabulary/iso639-2/eng/", IgnoreScheme()))
+	assert.False(t, EqualUri("ftp://id.loc.gov/vocabulary", "https://id.loc.gov/vocabulary", IgnoreScheme()))
+}
+
+func Test_AssertAuthorities(t *testing.T) {
+	expected := []model.Authority{
+		{Uri: "http://id.loc.gov/authorities/subjects/sh85006645", Title: "LCSH", Source: "lcsh"},
+		{Uri: "http://www.google.com?q=Analog Photography", Title: "Google", Source: "other"},
+	}
+	actual := []model.Authority{
+		{Uri: "https://id.loc.gov/authorities/subjects/sh85006645/", Source: "lcsh"},
+		{Uri: "http://www.google.com?q=Analog%20Photography", Source: "other"},
+	}
+
+	assert.True(t, AssertAuthorities(t, expected, actual, IgnoreScheme()))
+	assert.True(t, EqualAuthorities(expected, actual, IgnoreScheme()))
+	assert.False(t, EqualAuthorities(expected, actual))
+	assert.False(t, EqualAuthorities(expected, actual[:1], IgnoreScheme()))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertAuthorities(rec, expected, actual))
+	assert.Contains(t, rec.String(), "URIs are not equal")
+}
+
+func Test_EqualLink(t *testing.T) {
+	assert.Equal(t, "internal:/node/1", CanonicalLinkUri("entity:node/1"))
+	assert.Equal(t, "internal:/node/1", CanonicalLinkUri("internal:/node/1/"))
+	assert.Equal(t, "internal:/", CanonicalLinkUri("route:<front>"))
+	assert.Equal(t, "route:<nolink>", CanonicalLinkUri("route:<nolink>"))
+	assert.Equal(t, "http://id.loc.gov", CanonicalLinkUri("HTTP://id.loc.gov/"))
+
+	assert.True(t, EqualLink(model.Link{Uri: "entity:node/1"}, model.Link{Uri: "internal:/node/1", Title: "Moonrise"}))
+	assert.True(t, EqualLink(model.Link{Uri: "http://example.org", Title: "Example "}, model.Link{Uri: "https://example.org/", Title: "Example"}, IgnoreScheme()))
+	assert.False(t, EqualLink(model.Link{Uri: "http://example.org", Title: "Example"}, model.Link{Uri: "http://example.org", Title: "Other"}))
+	assert.False(t, EqualLink(model.Link{Uri: "internal:/node/1"}, model.Link{Uri: "internal:/node/2"}))
+	options := map[string]interface{}{"attributes": map[string]interface{}{"target": "_blank"}}
+	assert.True(t, EqualLink(model.Link{Uri: "internal:/node/1", Options: options}, model.Link{Uri: "internal:/node/1", Options: options}))
+	assert.False(t, EqualLink(model.Link{Uri: "internal:/node/1", Options: options}, model.Link{Uri: "internal:/node/1"}))
+
+	assert.True(t, AssertLinks(t, []model.Link{{Uri: "entity:node/1"}}, []model.Link{{Uri: "internal:/node/1"}}))
+	rec := &asserttest.Recorder{}
+	assert.False(t, AssertLinks(rec, []model.Link{{Uri: "entity:node/1"}}, nil))
+	assert.Contains(t, rec.String(), "number of links differ")
+	rec = &asserttest.Recorder{}
+	assert.False(t, AssertLinks(rec, []model.Link{{Uri: "entity:node/1"}}, []model.Link{{Uri: "entity:node/2"}}))
+	assert.Contains(t, rec.String(), "link 0 differs")
+}
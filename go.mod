@@ -5,4 +5,6 @@ go 1.16
 require (
 	github.com/rs/zerolog v1.23.0
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.3.7
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
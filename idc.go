@@ -0,0 +1,174 @@
+// Package idc is the recommended entry point to this module.  It composes the lower-level packages (jsonapi, model,
+// verify, taxonomy, and report) into the operations most test suites need:
+//
+//	c, err := idc.NewClientFromEnv()
+//	obj := &model.JsonApiIslandoraObj{}
+//	err = c.GetNodeByTitle(model.RepositoryObject, "Moonrise Over Hernandez", obj)
+//	err = c.WaitForDerivatives("Moonrise Over Hernandez", 2*time.Minute, idc.Thumbnail, idc.ServiceFile)
+//	report, err := c.VerifyFixtureDir("testdata/expected")
+//	report.WriteText(os.Stdout)
+//
+// Callers needing finer control may use the lower-level packages directly.
+package idc
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/jhu-idc/idc-golang/drupal/preflight"
+	"github.com/jhu-idc/idc-golang/drupal/report"
+	"github.com/jhu-idc/idc-golang/drupal/taxonomy"
+	"github.com/jhu-idc/idc-golang/drupal/verify"
+)
+
+// The outcomes of verifying fixtures (see report.Report)
+type Report = report.Report
+
+// The outcome of verifying a single fixture (see verify.Result)
+type Result = verify.Result
+
+// The interval between polls of WaitForDerivatives
+var PollInterval = 5 * time.Second
+
+// A derivative Islandora generates for a repository object, identified by the bundle of its media and its media use
+type Derivative struct {
+	Bundle   string
+	MediaUse string
+}
+
+var (
+	Thumbnail     = Derivative{Bundle: "image", MediaUse: "Thumbnail Image"}
+	ServiceFile   = Derivative{Bundle: "image", MediaUse: "Service File"}
+	ExtractedText = Derivative{Bundle: "extracted_text", MediaUse: "Extracted Text"}
+)
+
+// Accesses the content of a Drupal site
+type Client struct {
+	BaseUrl  string
+	Username string
+	Password env.Secret
+}
+
+// Creates a Client for the Drupal site at the base url.  If the username is not empty, requests are authenticated
+// using HTTP Basic Auth.
+func NewClient(baseUrl, username, password string) *Client {
+	return &Client{BaseUrl: baseUrl, Username: username, Password: env.Secret(password)}
+}
+
+// Creates a Client from the environment variables 'DRUPAL_BASE_URL', 'DRUPAL_USERNAME', and 'DRUPAL_PASSWORD'.  An
+// error is answered if the base url is unset.
+func NewClientFromEnv() (*Client, error) {
+	baseUrl := env.BaseUrlOr("")
+	if baseUrl == "" {
+		return nil, fmt.Errorf("idc: the environment variable DRUPAL_BASE_URL must be set")
+	}
+	return NewClient(baseUrl, env.UsernameOr(""), env.PasswordOr("")), nil
+}
+
+// Creates a Client for the Drupal site and user of the Config (see env.LoadConfig)
+func NewClientFromConfig(c *env.Config) *Client {
+	return NewClient(c.BaseUrl, c.Username, c.Password.Reveal())
+}
+
+// Retrieves the single node of the bundle (e.g. `islandora_object`) with the title, and unmarshals it into v (e.g. a
+// pointer to a model.JsonApiIslandoraObj)
+func (c *Client) GetNodeByTitle(bundle, title string, v interface{}) error {
+	return c.url(model.Node, bundle, "title", title).FetchSingle(v)
+}
+
+// Answers the single taxonomy term of the vocabulary (e.g. `genre`) with the name
+func (c *Client) GetTermByName(vocabulary, name string) (*taxonomy.Term, error) {
+	return taxonomy.NewClient(c.BaseUrl, c.Username, c.Password.Reveal()).FindTermByName(vocabulary, name)
+}
+
+// Retrieves the media of the bundle (e.g. `image`) which are media of the node with the title, and unmarshals them
+// into v (e.g. a pointer to a model.JsonApiImageMedia).  Any number of media may be present.
+func (c *Client) GetMediaOf(bundle, title string, v interface{}) error {
+	return c.url("media", bundle, "field_media_of.title", title).Fetch(v)
+}
+
+// Generates an Expected fixture from the live entity of the type and bundle with the title or name (see
+// model.Generate)
+func (c *Client) GenerateFixture(entityType, bundle, titleOrName string) (model.ExpectedEntity, error) {
+	expected, err := model.NewExpected(entityType, bundle)
+	if err != nil {
+		return nil, err
+	}
+	field := "name"
+	if n, ok := expected.(model.NamedOrTitled); ok {
+		field = n.Field()
+	}
+	return model.Generate(c.url(entityType, bundle, field, titleOrName))
+}
+
+// Validates the configuration of the client before a run: that the site is reachable, that the credentials are
+// accepted, and that the vocabularies used by IDC migrations exist.  An error describing each failed check, with a
+// hint for correcting it, is answered.
+func (c *Client) Preflight() error {
+	return preflight.NewChecker(c.BaseUrl, c.Username, c.Password.Reveal()).Run().Err()
+}
+
+// Verifies the fixture read from the file against its live entity
+func (c *Client) VerifyFixture(path string) *Result {
+	return c.engine().VerifyFile(path)
+}
+
+// Verifies each `.json` fixture in the directory and its subdirectories against its live entity
+func (c *Client) VerifyFixtureDir(dir string) (*Report, error) {
+	started := time.Now()
+	results, err := c.engine().VerifyDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return report.New(started, results...), nil
+}
+
+// Waits until each derivative of the node with the title exists, answering an error if any are missing once the
+// timeout elapses
+func (c *Client) WaitForDerivatives(title string, timeout time.Duration, derivatives ...Derivative) error {
+	deadline := time.Now().Add(timeout)
+	pending := append([]Derivative(nil), derivatives...)
+	for {
+		var missing []Derivative
+		for _, d := range pending {
+			res := &jsonapi.JsonApiResponse{}
+			u := c.url("media", d.Bundle, "", "")
+			u.RawFilter = fmt.Sprintf("filter[of][condition][path]=field_media_of.title&filter[of][condition][value]=%s"+
+				"&filter[use][condition][path]=field_media_use.name&filter[use][condition][value]=%s",
+				url.QueryEscape(title), url.QueryEscape(d.MediaUse))
+			if err := u.Fetch(res); err != nil {
+				return err
+			}
+			if len(res.Data) == 0 {
+				missing = append(missing, d)
+			}
+		}
+		if pending = missing; len(pending) == 0 {
+			return nil
+		}
+		if time.Now().Add(PollInterval).After(deadline) {
+			return fmt.Errorf("idc: timed out after %s waiting for derivatives of '%s': %v", timeout, title, pending)
+		}
+		time.Sleep(PollInterval)
+	}
+}
+
+func (c *Client) engine() *verify.Engine {
+	return verify.NewEngine(c.BaseUrl, c.Username, c.Password.Reveal())
+}
+
+func (c *Client) url(entity, bundle, filter, value string) *jsonapi.JsonApiUrl {
+	return &jsonapi.JsonApiUrl{
+		BaseUrl:      c.BaseUrl,
+		DrupalEntity: entity,
+		DrupalBundle: bundle,
+		Filter:       filter,
+		Value:        value,
+		Username:     c.Username,
+		Password:     c.Password,
+	}
+}
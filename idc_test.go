@@ -0,0 +1,115 @@
+package idc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapitest"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer() *jsonapitest.MockServer {
+	m := jsonapitest.NewMockServer()
+	m.Add(
+		jsonapitest.Resource{"type": "node--islandora_object", "id": "n1", "attributes": map[string]interface{}{"title": "Moonrise", "field_unique_id": "io_1"}},
+		jsonapitest.Resource{"type": "taxonomy_term--islandora_media_use", "id": "u1", "attributes": map[string]interface{}{"name": "Thumbnail Image"}},
+		jsonapitest.Resource{"type": "taxonomy_term--genre", "id": "g1", "attributes": map[string]interface{}{"name": "Maps"}},
+		jsonapitest.Resource{"type": "media--image", "id": "m1", "attributes": map[string]interface{}{"name": "Thumbnail Image.jpg"},
+			"relationships": map[string]interface{}{
+				"field_media_of":  map[string]interface{}{"data": map[string]interface{}{"type": "node--islandora_object", "id": "n1"}},
+				"field_media_use": map[string]interface{}{"data": []interface{}{map[string]interface{}{"type": "taxonomy_term--islandora_media_use", "id": "u1"}}},
+			}},
+	)
+	return m
+}
+
+func Test_NewClientFromEnv(t *testing.T) {
+	os.Unsetenv("DRUPAL_BASE_URL")
+	_, err := NewClientFromEnv()
+	assert.NotNil(t, err)
+
+	os.Setenv("DRUPAL_BASE_URL", "https://islandora-idc.traefik.me")
+	os.Setenv("DRUPAL_USERNAME", "admin")
+	defer os.Unsetenv("DRUPAL_BASE_URL")
+	defer os.Unsetenv("DRUPAL_USERNAME")
+	c, err := NewClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, &Client{BaseUrl: "https://islandora-idc.traefik.me", Username: "admin"}, c)
+}
+
+func Test_ClientRetrieval(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	obj := &model.JsonApiIslandoraObj{}
+	require.Nil(t, c.GetNodeByTitle(model.RepositoryObject, "Moonrise", obj))
+	assert.Equal(t, "n1", obj.JsonApiData[0].Id)
+	assert.NotNil(t, c.GetNodeByTitle(model.RepositoryObject, "Moonset", obj))
+
+	term, err := c.GetTermByName(model.Genre, "Maps")
+	require.Nil(t, err)
+	assert.Equal(t, "g1", term.Id)
+
+	media := &jsonapi.JsonApiResponse{}
+	require.Nil(t, c.GetMediaOf("image", "Moonrise", media))
+	assert.Equal(t, 1, len(media.Data))
+
+	expected, err := c.GenerateFixture(model.Node, model.RepositoryObject, "Moonrise")
+	require.Nil(t, err)
+	assert.Equal(t, "io_1", expected.(*model.ExpectedRepoObj).UniqueId)
+}
+
+func Test_ClientPreflight(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+
+	err := NewClient(m.URL, "admin", "password").Preflight()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "taxonomy_term--subject is not listed")
+	assert.NotContains(t, err.Error(), "taxonomy_term--genre")
+	assert.NotContains(t, err.Error(), "credentials")
+
+	assert.NotNil(t, NewClient(m.URL+"/drupal", "", "").Preflight())
+}
+
+func Test_ClientWaitForDerivatives(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	interval := PollInterval
+	PollInterval = 10 * time.Millisecond
+	defer func() { PollInterval = interval }()
+
+	assert.Nil(t, c.WaitForDerivatives("Moonrise", time.Second, Thumbnail))
+	err := c.WaitForDerivatives("Moonrise", 50*time.Millisecond, Thumbnail, ServiceFile)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Service File")
+}
+
+func Test_ClientVerifyFixtureDir(t *testing.T) {
+	m := newServer()
+	defer m.Close()
+	c := NewClient(m.URL, "", "")
+
+	dir := t.TempDir()
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "object.json"), []byte(`{"type": "node", "bundle": "islandora_object", "title": "Moonrise", "unique_id": "io_1"}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "genre.json"), []byte(`{"type": "taxonomy_term", "bundle": "genre", "name": "Map"}`), 0644))
+
+	assert.True(t, c.VerifyFixture(filepath.Join(dir, "object.json")).Passed())
+
+	report, err := c.VerifyFixtureDir(dir)
+	require.Nil(t, err)
+	assert.False(t, report.Passed())
+	assert.Equal(t, 1, report.Summary().Errored)
+
+	_, err = c.VerifyFixtureDir(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+}